@@ -0,0 +1,130 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"os"
+	"strconv"
+	"text/tabwriter"
+
+	"github.com/openvote/backend/internal/config"
+	"github.com/openvote/backend/internal/platform/database"
+	"github.com/openvote/backend/internal/platform/logging"
+	"github.com/openvote/backend/internal/platform/migrate"
+)
+
+var migrateLogger = logging.For("migrate")
+
+const migrateUsage = `Usage : main migrate <commande>
+
+  up [version]    applique les migrations en attente (jusqu'à version incluse)
+  down [n]        annule les n dernières migrations (1 par défaut)
+  status          liste les migrations appliquées, en attente ou modifiées
+  create <nom>    crée NNN_nom.sql et NNN_nom.down.sql dans le dossier des migrations
+
+Le dossier des migrations est lu dans MIGRATIONS_DIR ou database.migrations_dir ("migration" par défaut).`
+
+// runMigrate exécute la sous-commande "migrate" puis rend la main (pas de serveur HTTP)
+func runMigrate(ctx context.Context, dbConfig config.DatabaseConfig, args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("missing command\n\n%s", migrateUsage)
+	}
+	dir := dbConfig.MigrationsDir
+
+	// create ne touche pas à la base
+	if args[0] == "create" {
+		if len(args) < 2 {
+			return fmt.Errorf("missing migration name\n\n%s", migrateUsage)
+		}
+		up, down, err := migrate.Create(dir, args[1])
+		if err != nil {
+			return err
+		}
+		migrateLogger.Info("Migration créée", "up", up, "down", down)
+		return nil
+	}
+
+	db, err := database.NewPostgresDB(dbConfig.DSN())
+	if err != nil {
+		return fmt.Errorf("could not connect to database: %w", err)
+	}
+	defer db.Close()
+
+	migrator, err := migrate.Open(db, dir)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "up":
+		var target int64
+		if len(args) > 1 {
+			if target, err = strconv.ParseInt(args[1], 10, 64); err != nil || target < 1 {
+				return fmt.Errorf("invalid target version %q", args[1])
+			}
+		}
+		applied, err := migrator.Up(ctx, target)
+		if err != nil {
+			return err
+		}
+		migrateLogger.Info("Migrations appliquées", "count", len(applied))
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			if steps, err = strconv.Atoi(args[1]); err != nil || steps < 1 {
+				return fmt.Errorf("invalid step count %q", args[1])
+			}
+		}
+		reverted, err := migrator.Down(ctx, steps)
+		if err != nil {
+			return err
+		}
+		migrateLogger.Info("Migrations annulées", "count", len(reverted))
+	case "status":
+		return printMigrationStatus(ctx, migrator)
+	default:
+		return fmt.Errorf("unknown command %q\n\n%s", args[0], migrateUsage)
+	}
+	return nil
+}
+
+func printMigrationStatus(ctx context.Context, migrator *migrate.Migrator) error {
+	statuses, err := migrator.Status(ctx)
+	if err != nil {
+		return err
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNOM\tÉTAT\tAPPLIQUÉE LE")
+	for _, st := range statuses {
+		state, appliedAt := "en attente", ""
+		switch {
+		case st.Unknown:
+			state = "inconnue du binaire"
+		case st.Modified:
+			state = "MODIFIÉE"
+		case st.Applied:
+			state = "appliquée"
+		}
+		if st.Applied {
+			appliedAt = st.AppliedAt.Format("2006-01-02 15:04:05")
+		}
+		fmt.Fprintf(w, "%03d\t%s\t%s\t%s\n", st.Version, st.Name, state, appliedAt)
+	}
+	return w.Flush()
+}
+
+// ensureSchema refuse de démarrer sur un schéma en retard ou modifié. Avec AUTO_MIGRATE=true
+// (développement), les migrations en attente sont appliquées au démarrage.
+func ensureSchema(ctx context.Context, db *sql.DB, dbConfig config.DatabaseConfig) error {
+	migrator, err := migrate.Open(db, dbConfig.MigrationsDir)
+	if err != nil {
+		return err
+	}
+	if dbConfig.AutoMigrate {
+		if _, err := migrator.Up(ctx, 0); err != nil {
+			return err
+		}
+	}
+	return migrator.Check(ctx)
+}
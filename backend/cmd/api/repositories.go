@@ -0,0 +1,117 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"database/sql"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+	"github.com/openvote/backend/internal/repository/memory"
+	"github.com/openvote/backend/internal/repository/postgres"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// repositories regroupe les implémentations injectées dans les services et handlers
+type repositories struct {
+	users         repository.UserRepository
+	reports       repository.ReportRepository
+	regions       repository.RegionRepository
+	elections     repository.ElectionRepository
+	auditLogs     repository.AuditLogRepository
+	incidentTypes repository.IncidentTypeRepository
+	legal         repository.LegalRepository
+	clusters      repository.SuspiciousClusterRepository
+	conflicts     repository.ConflictRepository
+	events        repository.IncidentEventRepository
+	outbox        repository.OutboxRepository
+	webhooks      repository.WebhookRepository
+	alerts        repository.AlertRepository
+	notifications repository.NotificationRepository
+	config        repository.ConfigRepository
+}
+
+func postgresRepositories(db *sql.DB) repositories {
+	return repositories{
+		users:         postgres.NewUserRepository(db),
+		reports:       postgres.NewReportRepository(db),
+		regions:       postgres.NewRegionRepository(db),
+		elections:     postgres.NewElectionRepository(db),
+		auditLogs:     postgres.NewAuditLogRepository(db),
+		incidentTypes: postgres.NewIncidentTypeRepository(db),
+		legal:         postgres.NewLegalRepository(db),
+		clusters:      postgres.NewSuspiciousClusterRepository(db),
+		conflicts:     postgres.NewConflictRepository(db),
+		events:        postgres.NewIncidentEventRepository(db),
+		outbox:        postgres.NewOutboxRepository(db),
+		webhooks:      postgres.NewWebhookRepository(db),
+		alerts:        postgres.NewAlertRepository(db),
+		notifications: postgres.NewNotificationRepository(db),
+		config:        postgres.NewConfigRepository(db),
+	}
+}
+
+// memoryRepositories construit un store chargé des données de référence et d'un compte
+// super_admin de démonstration (MEMORY_ADMIN_PASSWORD, sinon mot de passe généré et affiché sur
+// le terminal)
+func memoryRepositories(ctx context.Context, password string) (repositories, error) {
+	store := memory.NewStore()
+	if err := memory.Seed(store); err != nil {
+		return repositories{}, err
+	}
+	repos := repositories{
+		users:         memory.NewUserRepository(store),
+		reports:       memory.NewReportRepository(store),
+		regions:       memory.NewRegionRepository(store),
+		elections:     memory.NewElectionRepository(store),
+		auditLogs:     memory.NewAuditLogRepository(store),
+		incidentTypes: memory.NewIncidentTypeRepository(store),
+		legal:         memory.NewLegalRepository(store),
+		clusters:      memory.NewSuspiciousClusterRepository(store),
+		conflicts:     memory.NewConflictRepository(store),
+		events:        memory.NewIncidentEventRepository(store),
+		outbox:        memory.NewOutboxRepository(store),
+		webhooks:      memory.NewWebhookRepository(store),
+		alerts:        memory.NewAlertRepository(store),
+		notifications: memory.NewNotificationRepository(store),
+		config:        memory.NewConfigRepository(store),
+	}
+
+	// Mot de passe généré : affiché sur le terminal uniquement, jamais dans les journaux collectés
+	generated := password == ""
+	if generated && !interactive(os.Stderr) {
+		return repositories{}, errors.New("MEMORY_ADMIN_PASSWORD is required when stderr is not a terminal")
+	}
+	if generated {
+		buf := make([]byte, 15)
+		if _, err := rand.Read(buf); err != nil {
+			return repositories{}, err
+		}
+		password = base64.RawURLEncoding.EncodeToString(buf)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return repositories{}, err
+	}
+	now := time.Now()
+	admin := &entity.User{ID: uuid.New().String(), Username: "admin", Role: entity.RoleSuperAdmin, PasswordHash: string(hash), CreatedAt: now, UpdatedAt: now}
+	if err := repos.users.Create(ctx, admin); err != nil {
+		return repositories{}, err
+	}
+	if generated {
+		fmt.Fprintf(os.Stderr, "Compte de démonstration : admin / %s (définir MEMORY_ADMIN_PASSWORD pour le fixer)\n", password)
+	}
+	return repos, nil
+}
+
+// interactive indique si f est un terminal (et non un fichier, un tube ou un collecteur de journaux)
+func interactive(f *os.File) bool {
+	info, err := f.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
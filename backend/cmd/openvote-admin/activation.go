@@ -0,0 +1,98 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"strconv"
+
+	"github.com/google/uuid"
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/service"
+)
+
+// maxActivationBatch borne un lot (impression de QR codes, distribution en main propre)
+const maxActivationBatch = 10000
+
+// activationRoles : rôles attribuables par token d'activation (jamais super_admin)
+var activationRoles = []entity.UserRole{
+	entity.RoleRegionAdmin, entity.RoleLocalCoord, entity.RoleObserver, entity.RoleVerifiedCitizen, entity.RoleCitizen,
+}
+
+// createOutput ouvre le fichier de sortie, ou l'écran si path est vide
+func (a *app) createOutput(path string) (io.Writer, func() error, error) {
+	if path == "" {
+		return a.stdout, func() error { return nil }, nil
+	}
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return nil, nil, err
+	}
+	return f, f.Close, nil
+}
+
+func runIssueActivation(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("issue-activation", flag.ContinueOnError)
+	role := fs.String("role", string(entity.RoleObserver), "Rôle attribué à l'enrôlement")
+	regionID := fs.String("region", "", "Région rattachée aux comptes (obligatoire hors citizen)")
+	count := fs.Int("count", 1, "Nombre de tokens du lot")
+	output := fs.String("output", "", "Fichier CSV créé (0600, jamais écrasé) ; écran par défaut")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	userRole := entity.UserRole(*role)
+	if !slices.Contains(activationRoles, userRole) {
+		return fmt.Errorf("role must be one of %v", activationRoles)
+	}
+	if *count < 1 || *count > maxActivationBatch {
+		return fmt.Errorf("count must be between 1 and %d", maxActivationBatch)
+	}
+	if *regionID != "" {
+		region, err := a.regions.GetRegionByID(ctx, *regionID)
+		if err != nil {
+			return err
+		}
+		if region == nil {
+			return fmt.Errorf("region %s not found", *regionID)
+		}
+	} else if userRole != entity.RoleCitizen && userRole != entity.RoleVerifiedCitizen {
+		return errors.New("-region is required for this role")
+	}
+
+	w, closeOutput, err := a.createOutput(*output)
+	if err != nil {
+		return err
+	}
+	defer closeOutput()
+
+	enrolment := service.NewEnrolmentService(a.users, a.jwtSecret)
+	batchID := uuid.New().String()
+	out := csv.NewWriter(w)
+	_ = out.Write([]string{"batch_id", "index", "role", "region_id", "activation_token"})
+	for i := 1; i <= *count; i++ {
+		token, err := enrolment.GenerateActivationToken(ctx, userRole, *regionID)
+		if err != nil {
+			return err
+		}
+		_ = out.Write([]string{batchID, strconv.Itoa(i), string(userRole), *regionID, token})
+	}
+	out.Flush()
+	if err := out.Error(); err != nil {
+		return err
+	}
+	if err := closeOutput(); err != nil {
+		return err
+	}
+
+	a.logAction(ctx, "ISSUE_ACTIVATION_BATCH", batchID, fmt.Sprintf("%d token(s) %s, région %q", *count, userRole, *regionID))
+	if *output != "" {
+		fmt.Fprintf(a.stdout, "Lot %s : %d token(s) écrits dans %s\n", batchID, *count, *output)
+	}
+	return nil
+}
@@ -0,0 +1,222 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fakeUserRepo : UserRepository en mémoire
+type fakeUserRepo struct {
+	users map[string]*entity.User
+}
+
+func (f *fakeUserRepo) Create(ctx context.Context, u *entity.User) error {
+	f.users[u.ID] = u
+	return nil
+}
+func (f *fakeUserRepo) GetByID(ctx context.Context, id string) (*entity.User, error) {
+	return f.users[id], nil
+}
+func (f *fakeUserRepo) GetByUsername(ctx context.Context, username string) (*entity.User, error) {
+	for _, u := range f.users {
+		if u.Username == username {
+			return u, nil
+		}
+	}
+	return nil, nil
+}
+func (f *fakeUserRepo) GetAll(ctx context.Context) ([]entity.User, error) { return nil, nil }
+func (f *fakeUserRepo) UpdateRole(ctx context.Context, id string, role entity.UserRole, regionID string) error {
+	return nil
+}
+func (f *fakeUserRepo) UpdateLastLogin(ctx context.Context, id string) error { return nil }
+func (f *fakeUserRepo) UpdatePasswordHash(ctx context.Context, id, hash string) error {
+	f.users[id].PasswordHash = hash
+	return nil
+}
+func (f *fakeUserRepo) Delete(ctx context.Context, id string) error { return nil }
+
+// fakeAuditRepo chaîne les entrées comme le repository PostgreSQL
+type fakeAuditRepo struct {
+	logs []entity.AuditLog
+}
+
+func (f *fakeAuditRepo) Create(ctx context.Context, l *entity.AuditLog) error {
+	l.Seq = int64(len(f.logs) + 1)
+	l.ID = "log-" + string(rune('a'+len(f.logs)))
+	l.CreatedAt = time.Date(2026, 6, 1, 8, 0, len(f.logs), 123456000, time.UTC)
+	if n := len(f.logs); n > 0 {
+		l.PrevHash = f.logs[n-1].Hash
+	}
+	l.Hash = l.ComputeHash(l.PrevHash)
+	f.logs = append(f.logs, *l)
+	return nil
+}
+func (f *fakeAuditRepo) GetAll(ctx context.Context, limit int) ([]entity.AuditLog, error) {
+	return f.logs, nil
+}
+func (f *fakeAuditRepo) ListChain(ctx context.Context, afterSeq int64, limit int) ([]entity.AuditLog, error) {
+	var out []entity.AuditLog
+	for _, l := range f.logs {
+		if l.Seq > afterSeq && len(out) < limit {
+			out = append(out, l)
+		}
+	}
+	return out, nil
+}
+
+func newTestApp(stdin string) (*app, *fakeUserRepo, *fakeAuditRepo, *bytes.Buffer) {
+	users := &fakeUserRepo{users: map[string]*entity.User{}}
+	audit := &fakeAuditRepo{}
+	out := &bytes.Buffer{}
+	return &app{users: users, audit: audit, operator: "test", stdin: strings.NewReader(stdin), stdout: out}, users, audit, out
+}
+
+func TestCreateSuperAdminAndResetPIN(t *testing.T) {
+	ctx := context.Background()
+	a, users, audit, out := newTestApp("correct-horse-battery\n")
+
+	if err := runCreateSuperAdmin(ctx, a, []string{"-username", "root", "-password-stdin"}); err != nil {
+		t.Fatalf("create-superadmin: %v", err)
+	}
+	admin, _ := users.GetByUsername(ctx, "root")
+	if admin == nil || admin.Role != entity.RoleSuperAdmin {
+		t.Fatalf("expected a super_admin, got %+v", admin)
+	}
+	if bcrypt.CompareHashAndPassword([]byte(admin.PasswordHash), []byte("correct-horse-battery")) != nil {
+		t.Fatal("password not hashed from stdin")
+	}
+	if err := runCreateSuperAdmin(ctx, a, []string{"-username", "root"}); err == nil {
+		t.Fatal("an existing username must be refused")
+	}
+
+	out.Reset()
+	if err := runResetPIN(ctx, a, []string{"-id", admin.ID}); err != nil {
+		t.Fatalf("reset-pin: %v", err)
+	}
+	var pin string
+	for _, line := range strings.Split(out.String(), "\n") {
+		if i := strings.LastIndex(line, ": "); strings.HasPrefix(line, "Nouveau PIN") && i > 0 {
+			pin = line[i+2:]
+		}
+	}
+	if len(pin) != 6 || bcrypt.CompareHashAndPassword([]byte(admin.PasswordHash), []byte(pin)) != nil {
+		t.Fatalf("generated PIN %q does not match the stored hash", pin)
+	}
+
+	if len(audit.logs) != 2 || audit.logs[0].Action != "CREATE_SUPERADMIN" || audit.logs[1].Action != "RESET_PIN" {
+		t.Fatalf("unexpected audit trail: %+v", audit.logs)
+	}
+}
+
+func TestShortPasswordRefused(t *testing.T) {
+	a, _, _, _ := newTestApp("court\n")
+	if err := runCreateSuperAdmin(context.Background(), a, []string{"-username", "root", "-password-stdin"}); err == nil {
+		t.Fatal("short password must be refused")
+	}
+}
+
+func TestParsePollingStations(t *testing.T) {
+	csvData := "\ufeffCode,Name,Department_Code,Latitude,Longitude,Registered_Voters\n" +
+		"bv-001, École publique de Mvog-Ada ,ce-mf,3.8667,11.5167,850\n" +
+		"BV-002,Mairie,CE-MF,,,\n"
+	stations, err := parsePollingStations(strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("parse: %v", err)
+	}
+	if len(stations) != 2 {
+		t.Fatalf("expected 2 stations, got %d", len(stations))
+	}
+	first := stations[0]
+	if first.Code != "BV-001" || first.DepartmentCode != "CE-MF" || first.Name != "École publique de Mvog-Ada" {
+		t.Errorf("unexpected normalisation: %+v", first)
+	}
+	if first.Latitude == nil || *first.Latitude != 3.8667 || first.RegisteredVoters != 850 {
+		t.Errorf("unexpected values: %+v", first)
+	}
+	if stations[1].Latitude != nil {
+		t.Error("empty coordinates should stay nil")
+	}
+}
+
+func TestParseCSVErrors(t *testing.T) {
+	cases := map[string]string{
+		"missing column":  "code,name\nBV-1,Mairie\n",
+		"unknown column":  "code,name,department_code,color\nBV-1,Mairie,CE-MF,red\n",
+		"empty required":  "code,name,department_code\nBV-1,,CE-MF\n",
+		"duplicate code":  "code,name,department_code\nBV-1,A,CE-MF\nbv-1,B,CE-MF\n",
+		"bad latitude":    "code,name,department_code,latitude,longitude\nBV-1,A,CE-MF,95,11\n",
+		"lonely latitude": "code,name,department_code,latitude\nBV-1,A,CE-MF,3.8\n",
+		"negative voters": "code,name,department_code,registered_voters\nBV-1,A,CE-MF,-4\n",
+	}
+	for name, data := range cases {
+		if _, err := parsePollingStations(strings.NewReader(data)); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+	if _, err := parseDepartments(strings.NewReader("code,name,region_code,population\nCE-MF,Mfoundi,CE,abc\n")); err == nil {
+		t.Error("non numeric population should be refused")
+	}
+}
+
+func TestVerifyAuditChain(t *testing.T) {
+	ctx := context.Background()
+	a, _, audit, out := newTestApp("")
+	// Deux entrées antérieures au chaînage, puis trois entrées chaînées
+	audit.logs = []entity.AuditLog{{Seq: 1, ID: "old-1"}, {Seq: 2, ID: "old-2"}}
+	for i := 0; i < 3; i++ {
+		a.logAction(ctx, "UPDATE_CONFIG", "storage", "version")
+	}
+
+	if err := runVerifyAudit(ctx, a, nil); err != nil {
+		t.Fatalf("intact chain reported broken: %v\n%s", err, out.String())
+	}
+	if !strings.Contains(out.String(), "3 entrée(s) vérifiée(s), 2 entrée(s) antérieure(s)") {
+		t.Fatalf("unexpected report: %s", out.String())
+	}
+
+	// Modification a posteriori du contenu
+	audit.logs[3].Details = "falsifié"
+	out.Reset()
+	if err := runVerifyAudit(ctx, a, nil); err == nil || !strings.Contains(out.String(), "seq=4") {
+		t.Fatalf("tampered entry not detected: %v\n%s", err, out.String())
+	}
+
+	// Suppression d'une entrée
+	audit.logs[3].Details = "version"
+	audit.logs = append(audit.logs[:3], audit.logs[4])
+	out.Reset()
+	if err := runVerifyAudit(ctx, a, nil); err == nil || !strings.Contains(out.String(), "supprimée") {
+		t.Fatalf("deleted entry not detected: %v\n%s", err, out.String())
+	}
+}
+
+func TestFilterAndWriteReports(t *testing.T) {
+	day := time.Date(2026, 6, 1, 0, 0, 0, 0, time.UTC)
+	reports := []entity.Report{
+		{ID: "r1", ObserverID: "obs-1", Status: entity.ReportStatus("verified"), CreatedAt: day.Add(-time.Hour)},
+		{ID: "r2", ObserverID: "obs-2", Status: entity.ReportStatus("verified"), CreatedAt: day.Add(time.Hour), Description: "urne, ouverte"},
+		{ID: "r3", ObserverID: "obs-3", Status: entity.ReportStatus("verified"), CreatedAt: day.Add(24 * time.Hour)},
+	}
+	exported := filterReports(reports, day, day.Add(24*time.Hour), false)
+	if len(exported) != 1 || exported[0].ID != "r2" {
+		t.Fatalf("unexpected filter result: %+v", exported)
+	}
+
+	var buf bytes.Buffer
+	if err := writeReportsCSV(&buf, exported, false); err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(buf.String(), "obs-2") || strings.Contains(buf.String(), "observer_id") {
+		t.Fatal("observer must not be exported by default")
+	}
+	if !strings.Contains(buf.String(), `"urne, ouverte"`) {
+		t.Fatalf("CSV fields not quoted: %s", buf.String())
+	}
+}
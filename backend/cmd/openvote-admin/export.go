@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+)
+
+// exportedReport : colonnes exportées ; l'observateur n'apparaît que sur demande explicite
+type exportedReport struct {
+	ID           string    `json:"id"`
+	CreatedAt    time.Time `json:"created_at"`
+	Status       string    `json:"status"`
+	IncidentType string    `json:"incident_type"`
+	Severity     int       `json:"severity"`
+	Description  string    `json:"description"`
+	GPSLocation  string    `json:"gps_location"`
+	H3Index      string    `json:"h3_index"`
+	ProofURL     string    `json:"proof_url"`
+	ObserverID   string    `json:"observer_id,omitempty"`
+}
+
+// filterReports garde les signalements de [from, to) (bornes nulles ignorées)
+func filterReports(reports []entity.Report, from, to time.Time, includeObserver bool) []exportedReport {
+	out := make([]exportedReport, 0, len(reports))
+	for _, r := range reports {
+		if !from.IsZero() && r.CreatedAt.Before(from) {
+			continue
+		}
+		if !to.IsZero() && !r.CreatedAt.Before(to) {
+			continue
+		}
+		e := exportedReport{
+			ID: r.ID, CreatedAt: r.CreatedAt, Status: string(r.Status), IncidentType: r.IncidentType, Severity: r.Severity,
+			Description: r.Description, GPSLocation: r.GPSLocation, H3Index: r.H3Index, ProofURL: r.ProofURL,
+		}
+		if includeObserver {
+			e.ObserverID = r.ObserverID
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+func writeReportsCSV(w io.Writer, reports []exportedReport, includeObserver bool) error {
+	out := csv.NewWriter(w)
+	header := []string{"id", "created_at", "status", "incident_type", "severity", "description", "gps_location", "h3_index", "proof_url"}
+	if includeObserver {
+		header = append(header, "observer_id")
+	}
+	_ = out.Write(header)
+	for _, r := range reports {
+		record := []string{r.ID, r.CreatedAt.UTC().Format(time.RFC3339), r.Status, r.IncidentType, strconv.Itoa(r.Severity),
+			r.Description, r.GPSLocation, r.H3Index, r.ProofURL}
+		if includeObserver {
+			record = append(record, r.ObserverID)
+		}
+		_ = out.Write(record)
+	}
+	out.Flush()
+	return out.Error()
+}
+
+func parseOptionalTime(name, value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("-%s must be RFC3339 (2026-06-01T00:00:00Z)", name)
+	}
+	return t, nil
+}
+
+func runExportReports(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("export-reports", flag.ContinueOnError)
+	status := fs.String("status", "", "Statut des signalements (pending, verified, rejected) ; tous par défaut")
+	fromFlag := fs.String("from", "", "Début de la période (RFC3339, inclus)")
+	toFlag := fs.String("to", "", "Fin de la période (RFC3339, exclue)")
+	format := fs.String("format", "csv", "Format : csv ou json")
+	output := fs.String("output", "", "Fichier créé (0600, jamais écrasé) ; écran par défaut")
+	includeObserver := fs.Bool("include-observer", false, "Inclure l'identifiant de l'observateur (donnée personnelle)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	from, err := parseOptionalTime("from", *fromFlag)
+	if err != nil {
+		return err
+	}
+	to, err := parseOptionalTime("to", *toFlag)
+	if err != nil {
+		return err
+	}
+	if *format != "csv" && *format != "json" {
+		return fmt.Errorf("unknown format %q (csv or json)", *format)
+	}
+
+	reports, err := a.reports.GetAll(ctx, *status)
+	if err != nil {
+		return err
+	}
+	exported := filterReports(reports, from, to, *includeObserver)
+
+	w, closeOutput, err := a.createOutput(*output)
+	if err != nil {
+		return err
+	}
+	defer closeOutput()
+	if *format == "json" {
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		err = enc.Encode(exported)
+	} else {
+		err = writeReportsCSV(w, exported, *includeObserver)
+	}
+	if err != nil {
+		return err
+	}
+	if err := closeOutput(); err != nil {
+		return err
+	}
+
+	a.logAction(ctx, "EXPORT_REPORTS", *status, fmt.Sprintf("%d signalement(s) %s, observateurs inclus: %t", len(exported), *format, *includeObserver))
+	if *output != "" {
+		fmt.Fprintf(a.stdout, "%d signalement(s) exporté(s) dans %s\n", len(exported), *output)
+	}
+	return nil
+}
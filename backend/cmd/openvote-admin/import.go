@@ -0,0 +1,415 @@
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/openvote/backend/internal/domain/entity"
+)
+
+// csvRow est une ligne indexée par nom de colonne (en-tête insensible à la casse)
+type csvRow struct {
+	line   int
+	values map[string]string
+}
+
+func (r csvRow) get(column string) string { return r.values[column] }
+
+// readCSV lit un CSV avec en-tête ; les colonnes required doivent exister et être renseignées
+func readCSV(r io.Reader, required, optional []string) ([]csvRow, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+
+	known := map[string]bool{}
+	for _, c := range append(append([]string{}, required...), optional...) {
+		known[c] = true
+	}
+	columns := make([]string, len(header))
+	present := map[string]bool{}
+	for i, h := range header {
+		h = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(h, "\ufeff")))
+		if !known[h] {
+			return nil, fmt.Errorf("unknown column %q (expected %s)", h, strings.Join(append(required, optional...), ", "))
+		}
+		columns[i] = h
+		present[h] = true
+	}
+	for _, c := range required {
+		if !present[c] {
+			return nil, fmt.Errorf("missing column %q", c)
+		}
+	}
+
+	var rows []csvRow
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		row := csvRow{line: line, values: map[string]string{}}
+		for i, v := range record {
+			row.values[columns[i]] = strings.TrimSpace(v)
+		}
+		for _, c := range required {
+			if row.values[c] == "" {
+				return nil, fmt.Errorf("line %d: %s is empty", line, c)
+			}
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+// optionalInt lit un entier facultatif (0 si vide)
+func optionalInt(row csvRow, column string) (int, error) {
+	v := row.get(column)
+	if v == "" {
+		return 0, nil
+	}
+	n, err := strconv.Atoi(v)
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("line %d: %s must be a positive integer", row.line, column)
+	}
+	return n, nil
+}
+
+// optionalCoordinate lit une coordonnée facultative bornée à [-limit, limit]
+func optionalCoordinate(row csvRow, column string, limit float64) (*float64, error) {
+	v := row.get(column)
+	if v == "" {
+		return nil, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f < -limit || f > limit {
+		return nil, fmt.Errorf("line %d: %s must be between %v and %v", row.line, column, -limit, limit)
+	}
+	return &f, nil
+}
+
+// parseRegions : code,name
+func parseRegions(r io.Reader) ([]entity.Region, error) {
+	rows, err := readCSV(r, []string{"code", "name"}, nil)
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]int{}
+	regions := make([]entity.Region, 0, len(rows))
+	for _, row := range rows {
+		code := strings.ToUpper(row.get("code"))
+		if prev, ok := seen[code]; ok {
+			return nil, fmt.Errorf("line %d: duplicate code %s (line %d)", row.line, code, prev)
+		}
+		seen[code] = row.line
+		regions = append(regions, entity.Region{Code: code, Name: row.get("name")})
+	}
+	return regions, nil
+}
+
+// departmentRow associe un département au code de sa région
+type departmentRow struct {
+	entity.Department
+	RegionCode string
+}
+
+// parseDepartments : code,name,region_code[,population,registered_voters]
+func parseDepartments(r io.Reader) ([]departmentRow, error) {
+	rows, err := readCSV(r, []string{"code", "name", "region_code"}, []string{"population", "registered_voters"})
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]int{}
+	depts := make([]departmentRow, 0, len(rows))
+	for _, row := range rows {
+		code := strings.ToUpper(row.get("code"))
+		if prev, ok := seen[code]; ok {
+			return nil, fmt.Errorf("line %d: duplicate code %s (line %d)", row.line, code, prev)
+		}
+		seen[code] = row.line
+		population, err := optionalInt(row, "population")
+		if err != nil {
+			return nil, err
+		}
+		voters, err := optionalInt(row, "registered_voters")
+		if err != nil {
+			return nil, err
+		}
+		depts = append(depts, departmentRow{
+			Department: entity.Department{Code: code, Name: row.get("name"), Population: population, RegisteredVoters: voters},
+			RegionCode: strings.ToUpper(row.get("region_code")),
+		})
+	}
+	return depts, nil
+}
+
+// stationRow associe un bureau de vote au code de son département
+type stationRow struct {
+	entity.PollingStation
+	DepartmentCode string
+}
+
+// parsePollingStations : code,name,department_code[,address,latitude,longitude,registered_voters]
+func parsePollingStations(r io.Reader) ([]stationRow, error) {
+	rows, err := readCSV(r, []string{"code", "name", "department_code"}, []string{"address", "latitude", "longitude", "registered_voters"})
+	if err != nil {
+		return nil, err
+	}
+	seen := map[string]int{}
+	stations := make([]stationRow, 0, len(rows))
+	for _, row := range rows {
+		code := strings.ToUpper(row.get("code"))
+		if prev, ok := seen[code]; ok {
+			return nil, fmt.Errorf("line %d: duplicate code %s (line %d)", row.line, code, prev)
+		}
+		seen[code] = row.line
+		lat, err := optionalCoordinate(row, "latitude", 90)
+		if err != nil {
+			return nil, err
+		}
+		lon, err := optionalCoordinate(row, "longitude", 180)
+		if err != nil {
+			return nil, err
+		}
+		if (lat == nil) != (lon == nil) {
+			return nil, fmt.Errorf("line %d: latitude and longitude go together", row.line)
+		}
+		voters, err := optionalInt(row, "registered_voters")
+		if err != nil {
+			return nil, err
+		}
+		stations = append(stations, stationRow{
+			PollingStation: entity.PollingStation{
+				Code: code, Name: row.get("name"), Address: row.get("address"),
+				Latitude: lat, Longitude: lon, RegisteredVoters: voters,
+			},
+			DepartmentCode: strings.ToUpper(row.get("department_code")),
+		})
+	}
+	return stations, nil
+}
+
+// importSummary compte les lignes créées, modifiées et inchangées
+type importSummary struct {
+	Created, Updated, Unchanged int
+}
+
+func (s importSummary) String() string {
+	return fmt.Sprintf("%d créé(s), %d modifié(s), %d inchangé(s)", s.Created, s.Updated, s.Unchanged)
+}
+
+func runImport(ctx context.Context, a *app, args []string) error {
+	if len(args) == 0 {
+		return errors.New("usage: import regions|departments|polling-stations -file data.csv [-dry-run]")
+	}
+	kind := args[0]
+	fs := flag.NewFlagSet("import "+kind, flag.ContinueOnError)
+	file := fs.String("file", "", "Fichier CSV (UTF-8, avec en-tête)")
+	dryRun := fs.Bool("dry-run", false, "Valider le fichier et afficher le résultat sans rien écrire")
+	if err := fs.Parse(args[1:]); err != nil {
+		return err
+	}
+	if *file == "" {
+		return errors.New("-file is required")
+	}
+	f, err := os.Open(*file)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	var summary importSummary
+	switch kind {
+	case "regions":
+		regions, err := parseRegions(f)
+		if err != nil {
+			return err
+		}
+		summary, err = a.importRegions(ctx, regions, *dryRun)
+		if err != nil {
+			return err
+		}
+	case "departments":
+		depts, err := parseDepartments(f)
+		if err != nil {
+			return err
+		}
+		summary, err = a.importDepartments(ctx, depts, *dryRun)
+		if err != nil {
+			return err
+		}
+	case "polling-stations":
+		stations, err := parsePollingStations(f)
+		if err != nil {
+			return err
+		}
+		summary, err = a.importPollingStations(ctx, stations, *dryRun)
+		if err != nil {
+			return err
+		}
+	default:
+		return fmt.Errorf("unknown import %q (regions, departments or polling-stations)", kind)
+	}
+
+	if *dryRun {
+		fmt.Fprintf(a.stdout, "Simulation %s : %s\n", kind, summary)
+		return nil
+	}
+	a.logAction(ctx, "IMPORT_"+strings.ToUpper(strings.ReplaceAll(kind, "-", "_")), *file, summary.String())
+	fmt.Fprintf(a.stdout, "Import %s : %s\n", kind, summary)
+	return nil
+}
+
+// Les imports résolvent toutes les références avant la première écriture : un fichier
+// incohérent n'est jamais appliqué à moitié.
+
+func (a *app) importRegions(ctx context.Context, regions []entity.Region, dryRun bool) (importSummary, error) {
+	var summary importSummary
+	existing, err := a.regions.GetAllRegions(ctx)
+	if err != nil {
+		return summary, err
+	}
+	byCode := map[string]entity.Region{}
+	for _, r := range existing {
+		byCode[strings.ToUpper(r.Code)] = r
+	}
+
+	for i := range regions {
+		region := &regions[i]
+		current, ok := byCode[region.Code]
+		switch {
+		case !ok:
+			summary.Created++
+			if !dryRun {
+				if err := a.regions.CreateRegion(ctx, region); err != nil {
+					return summary, fmt.Errorf("region %s: %w", region.Code, err)
+				}
+			}
+		case current.Name != region.Name:
+			summary.Updated++
+			if !dryRun {
+				if err := a.regions.UpdateRegion(ctx, current.ID, region.Name, region.Code); err != nil {
+					return summary, fmt.Errorf("region %s: %w", region.Code, err)
+				}
+			}
+		default:
+			summary.Unchanged++
+		}
+	}
+	return summary, nil
+}
+
+func (a *app) importDepartments(ctx context.Context, depts []departmentRow, dryRun bool) (importSummary, error) {
+	var summary importSummary
+	regions, err := a.regions.GetAllRegions(ctx)
+	if err != nil {
+		return summary, err
+	}
+	regionIDs := map[string]string{}
+	for _, r := range regions {
+		regionIDs[strings.ToUpper(r.Code)] = r.ID
+	}
+	existing, err := a.regions.GetAllDepartments(ctx)
+	if err != nil {
+		return summary, err
+	}
+	byCode := map[string]entity.Department{}
+	for _, d := range existing {
+		byCode[strings.ToUpper(d.Code)] = d
+	}
+
+	for i := range depts {
+		regionID, ok := regionIDs[depts[i].RegionCode]
+		if !ok {
+			return summary, fmt.Errorf("department %s: unknown region %s", depts[i].Code, depts[i].RegionCode)
+		}
+		depts[i].RegionID = regionID
+	}
+
+	for i := range depts {
+		dept := &depts[i].Department
+		current, ok := byCode[dept.Code]
+		switch {
+		case !ok:
+			summary.Created++
+			if !dryRun {
+				if err := a.regions.CreateDepartment(ctx, dept); err != nil {
+					return summary, fmt.Errorf("department %s: %w", dept.Code, err)
+				}
+			}
+		case current.Name != dept.Name || current.RegionID != dept.RegionID ||
+			current.Population != dept.Population || current.RegisteredVoters != dept.RegisteredVoters:
+			summary.Updated++
+			if !dryRun {
+				if err := a.regions.UpdateDepartment(ctx, current.ID, dept.Name, dept.Code, dept.RegionID, dept.Population, dept.RegisteredVoters); err != nil {
+					return summary, fmt.Errorf("department %s: %w", dept.Code, err)
+				}
+			}
+		default:
+			summary.Unchanged++
+		}
+	}
+	return summary, nil
+}
+
+func (a *app) importPollingStations(ctx context.Context, stations []stationRow, dryRun bool) (importSummary, error) {
+	var summary importSummary
+	depts, err := a.regions.GetAllDepartments(ctx)
+	if err != nil {
+		return summary, err
+	}
+	deptIDs := map[string]string{}
+	for _, d := range depts {
+		deptIDs[strings.ToUpper(d.Code)] = d.ID
+	}
+	existing, err := a.stations.GetAll(ctx)
+	if err != nil {
+		return summary, err
+	}
+	known := map[string]bool{}
+	for _, s := range existing {
+		known[s.Code] = true
+	}
+
+	for i := range stations {
+		deptID, ok := deptIDs[stations[i].DepartmentCode]
+		if !ok {
+			return summary, fmt.Errorf("polling station %s: unknown department %s", stations[i].Code, stations[i].DepartmentCode)
+		}
+		stations[i].DepartmentID = deptID
+	}
+
+	for i := range stations {
+		station := &stations[i].PollingStation
+		if dryRun {
+			if known[station.Code] {
+				summary.Updated++
+			} else {
+				summary.Created++
+			}
+			continue
+		}
+		created, err := a.stations.Upsert(ctx, station)
+		if err != nil {
+			return summary, fmt.Errorf("polling station %s: %w", station.Code, err)
+		}
+		if created {
+			summary.Created++
+		} else {
+			summary.Updated++
+		}
+	}
+	return summary, nil
+}
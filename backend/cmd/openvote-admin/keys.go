@@ -0,0 +1,83 @@
+package main
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"flag"
+	"fmt"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/service"
+)
+
+// newEnvSecret génère une valeur pour un secret lu dans l'environnement (JWT_SECRET, FINGERPRINT_SECRET)
+func newEnvSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// runRotateKeys : les secrets d'environnement ne sont pas en base, la commande en propose de nouveaux
+// à déployer ; les secrets de signature des webhooks sont renouvelés directement.
+func runRotateKeys(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("rotate-keys", flag.ContinueOnError)
+	webhooks := fs.Bool("webhooks", false, "Renouveler le secret de tous les abonnements webhooks")
+	subscription := fs.String("subscription", "", "Renouveler le secret d'un seul abonnement webhook")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if !*webhooks && *subscription == "" {
+		jwtSecret, err := newEnvSecret()
+		if err != nil {
+			return err
+		}
+		fingerprintSecret, err := newEnvSecret()
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(a.stdout, "JWT_SECRET=%s\nFINGERPRINT_SECRET=%s\n\n", jwtSecret, fingerprintSecret)
+		fmt.Fprintln(a.stdout, "À déployer sur l'API et le worker, puis redémarrer :")
+		fmt.Fprintln(a.stdout, "- JWT_SECRET invalide les sessions et les tokens d'activation non utilisés (réémettre les lots) ;")
+		fmt.Fprintln(a.stdout, "- FINGERPRINT_SECRET rend les nouvelles empreintes incomparables aux anciennes (détection Sybil).")
+		fmt.Fprintln(a.stdout, "Secrets des webhooks : openvote-admin rotate-keys -webhooks")
+		a.logAction(ctx, "ROTATE_KEYS", "env", "JWT_SECRET, FINGERPRINT_SECRET générés")
+		return nil
+	}
+
+	var subs []entity.WebhookSubscription
+	if *subscription != "" {
+		sub, err := a.webhooks.GetSubscription(ctx, *subscription)
+		if err != nil {
+			return err
+		}
+		if sub == nil {
+			return errors.New("webhook subscription not found")
+		}
+		subs = append(subs, *sub)
+	} else {
+		var err error
+		if subs, err = a.webhooks.ListSubscriptions(ctx); err != nil {
+			return err
+		}
+	}
+
+	for _, sub := range subs {
+		secret, err := service.NewWebhookSecret()
+		if err != nil {
+			return err
+		}
+		if err := a.webhooks.UpdateSecret(ctx, sub.ID, secret); err != nil {
+			return fmt.Errorf("subscription %s: %w", sub.ID, err)
+		}
+		a.logAction(ctx, "ROTATE_WEBHOOK_SECRET", sub.ID, sub.Name)
+		// Le partenaire doit recevoir le nouveau secret : les livraisons suivantes sont signées avec
+		fmt.Fprintf(a.stdout, "%s\t%s\t%s\n", sub.ID, sub.Name, secret)
+	}
+	fmt.Fprintf(a.stdout, "%d secret(s) de webhook renouvelé(s)\n", len(subs))
+	return nil
+}
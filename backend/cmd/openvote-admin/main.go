@@ -0,0 +1,165 @@
+// Command openvote-admin regroupe les opérations d'administration hors API :
+// amorçage du premier super_admin, réinitialisation de PIN, lots d'activation,
+// imports de référentiels, exports, rotation des secrets et vérification du journal d'audit.
+//
+// Il s'adresse directement aux repositories PostgreSQL (configuration de l'API : -config, variables
+// DB_*), sans passer par l'API :
+// il fonctionne donc avant la création de tout compte. Chaque opération qui modifie des données
+// est consignée dans le journal d'audit au nom de l'opérateur (-operator, $USER par défaut).
+//
+// Usage :
+//
+//	openvote-admin create-superadmin -username admin [-password-stdin]
+//	openvote-admin reset-pin -username <uuid> [-pin-stdin]
+//	openvote-admin issue-activation -role observer -region <id> -count 200 -output lot.csv
+//	openvote-admin import regions|departments|polling-stations -file data.csv [-dry-run]
+//	openvote-admin export-reports [-status verified] [-from ...] [-to ...] [-format csv|json]
+//	openvote-admin rotate-keys [-webhooks] [-subscription <id>]
+//	openvote-admin verify-audit
+package main
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+
+	"github.com/openvote/backend/internal/config"
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+	"github.com/openvote/backend/internal/platform/database"
+	"github.com/openvote/backend/internal/platform/logging"
+	"github.com/openvote/backend/internal/repository/postgres"
+	"github.com/openvote/backend/internal/service"
+)
+
+var auditLogger = logging.For("audit")
+
+// app regroupe les repositories utilisés par les sous-commandes
+type app struct {
+	users    repository.UserRepository
+	regions  repository.RegionRepository
+	stations repository.PollingStationRepository
+	reports  repository.ReportRepository
+	webhooks repository.WebhookRepository
+	audit    repository.AuditLogRepository
+	// jwtSecret signe les tokens d'activation (même secret que l'API)
+	jwtSecret string
+	operator  string
+	stdin     io.Reader
+	stdout    io.Writer
+}
+
+func newApp(db *sql.DB, jwtSecret, operator string) *app {
+	return &app{
+		users:     postgres.NewUserRepository(db),
+		regions:   postgres.NewRegionRepository(db),
+		stations:  postgres.NewPollingStationRepository(db),
+		reports:   postgres.NewReportRepository(db),
+		webhooks:  postgres.NewWebhookRepository(db),
+		audit:     postgres.NewAuditLogRepository(db),
+		jwtSecret: jwtSecret,
+		operator:  operator,
+		stdin:     os.Stdin,
+		stdout:    os.Stdout,
+	}
+}
+
+// logAction consigne l'opération dans le journal d'audit (AdminID "cli" : pas de compte associé)
+func (a *app) logAction(ctx context.Context, action, targetID, details string) {
+	entry := &entity.AuditLog{
+		AdminID:   "cli",
+		AdminName: a.operator,
+		Action:    action,
+		TargetID:  targetID,
+		Details:   details,
+	}
+	if err := a.audit.Create(ctx, entry); err != nil {
+		auditLogger.ErrorContext(ctx, "Error persisting audit log", "action", entry.Action, "error", err)
+	}
+}
+
+type command struct {
+	summary string
+	run     func(ctx context.Context, a *app, args []string) error
+}
+
+var commands = map[string]command{
+	"create-superadmin": {"Crée un compte super_admin (amorçage)", runCreateSuperAdmin},
+	"reset-pin":         {"Réinitialise le PIN (ou mot de passe) d'un utilisateur", runResetPIN},
+	"issue-activation":  {"Émet un lot de tokens d'activation (CSV)", runIssueActivation},
+	"import":            {"Importe régions, départements ou bureaux de vote depuis un CSV", runImport},
+	"export-reports":    {"Exporte les signalements (CSV ou JSON)", runExportReports},
+	"rotate-keys":       {"Génère de nouveaux secrets (JWT, empreintes) et renouvelle ceux des webhooks", runRotateKeys},
+	"verify-audit":      {"Vérifie le chaînage du journal d'audit", runVerifyAudit},
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "Usage : openvote-admin [-operator nom] <commande> [options]\n\nCommandes :")
+	names := make([]string, 0, len(commands))
+	for name := range commands {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(os.Stderr, "  %-18s %s\n", name, commands[name].summary)
+	}
+	fmt.Fprintln(os.Stderr, "\nLa base et les secrets sont lus dans la configuration partagée avec l'API (-config, CONFIG_FILE,")
+	fmt.Fprintln(os.Stderr, "variables DB_HOST, DB_PORT, DB_USER, DB_PASSWORD, DB_NAME, JWT_SECRET...).")
+}
+
+func main() {
+	operator := flag.String("operator", os.Getenv("USER"), "Nom de l'opérateur consigné dans le journal d'audit")
+	var flags config.Flags
+	flags.Register(flag.CommandLine)
+	flag.Usage = usage
+	flag.Parse()
+	if flags.Print {
+		if _, _, err := flags.Load(os.Stdout); err != nil {
+			log.Fatalf("[ADMIN] %v", err)
+		}
+		return
+	}
+
+	if flag.NArg() == 0 {
+		usage()
+		os.Exit(2)
+	}
+	cmd, ok := commands[flag.Arg(0)]
+	if !ok {
+		fmt.Fprintf(os.Stderr, "Commande inconnue : %s\n\n", flag.Arg(0))
+		usage()
+		os.Exit(2)
+	}
+	if *operator == "" {
+		*operator = "openvote-admin"
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	cfg, _, err := flags.Load(os.Stdout)
+	if err != nil {
+		log.Fatalf("[ADMIN] %v", err)
+	}
+	service.SetFingerprintKey(cfg.Auth.FingerprintKey())
+	db, err := database.NewPostgresDB(cfg.Database.DSN())
+	if err != nil {
+		log.Fatalf("[ADMIN] Could not connect to database: %v", err)
+	}
+	defer db.Close()
+
+	if err := cmd.run(ctx, newApp(db, cfg.Auth.JWTSecret, *operator), flag.Args()[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(2)
+		}
+		log.Fatalf("[ADMIN] %s : %v", flag.Arg(0), err)
+	}
+}
@@ -0,0 +1,173 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/openvote/backend/internal/domain/entity"
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	minPasswordLength = 12
+	// Bornes du PIN saisi à l'enrôlement (voir AuthHandler.Enroll)
+	minPINLength = 4
+	maxPINLength = 8
+)
+
+// readSecret lit un secret sur la première ligne de r (jamais en argument : il apparaîtrait dans ps)
+func readSecret(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", err
+	}
+	return strings.TrimRight(line, "\r\n"), nil
+}
+
+// randomString tire length caractères de alphabet avec crypto/rand
+func randomString(alphabet string, length int) (string, error) {
+	var b strings.Builder
+	max := big.NewInt(int64(len(alphabet)))
+	for i := 0; i < length; i++ {
+		n, err := rand.Int(rand.Reader, max)
+		if err != nil {
+			return "", err
+		}
+		b.WriteByte(alphabet[n.Int64()])
+	}
+	return b.String(), nil
+}
+
+// findUser résout un utilisateur par identifiant ou par nom
+func (a *app) findUser(ctx context.Context, id, username string) (*entity.User, error) {
+	var user *entity.User
+	var err error
+	switch {
+	case id != "":
+		user, err = a.users.GetByID(ctx, id)
+	case username != "":
+		user, err = a.users.GetByUsername(ctx, username)
+	default:
+		return nil, errors.New("-id or -username is required")
+	}
+	if err != nil {
+		return nil, err
+	}
+	if user == nil {
+		return nil, errors.New("user not found")
+	}
+	return user, nil
+}
+
+func runCreateSuperAdmin(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("create-superadmin", flag.ContinueOnError)
+	username := fs.String("username", "", "Nom du compte (obligatoire)")
+	passwordStdin := fs.Bool("password-stdin", false, "Lire le mot de passe sur l'entrée standard (sinon, il est généré et affiché)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if strings.TrimSpace(*username) == "" {
+		return errors.New("-username is required")
+	}
+
+	existing, err := a.users.GetByUsername(ctx, *username)
+	if err != nil {
+		return err
+	}
+	if existing != nil {
+		return fmt.Errorf("user %q already exists (role %s)", *username, existing.Role)
+	}
+
+	password, generated := "", false
+	if *passwordStdin {
+		if password, err = readSecret(a.stdin); err != nil {
+			return err
+		}
+		if len(password) < minPasswordLength {
+			return fmt.Errorf("password must be at least %d characters", minPasswordLength)
+		}
+	} else {
+		if password, err = randomString("abcdefghijkmnopqrstuvwxyzABCDEFGHJKLMNPQRSTUVWXYZ23456789", 20); err != nil {
+			return err
+		}
+		generated = true
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	now := time.Now()
+	user := &entity.User{
+		ID:           uuid.New().String(),
+		Username:     *username,
+		Role:         entity.RoleSuperAdmin,
+		PasswordHash: string(hash),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+	if err := a.users.Create(ctx, user); err != nil {
+		return err
+	}
+	a.logAction(ctx, "CREATE_SUPERADMIN", user.ID, user.Username)
+
+	fmt.Fprintf(a.stdout, "super_admin créé : %s (%s)\n", user.Username, user.ID)
+	if generated {
+		fmt.Fprintf(a.stdout, "Mot de passe (affiché une seule fois) : %s\n", password)
+	}
+	return nil
+}
+
+func runResetPIN(ctx context.Context, a *app, args []string) error {
+	fs := flag.NewFlagSet("reset-pin", flag.ContinueOnError)
+	id := fs.String("id", "", "Identifiant de l'utilisateur")
+	username := fs.String("username", "", "Nom de l'utilisateur (UUID pour les comptes enrôlés)")
+	pinStdin := fs.Bool("pin-stdin", false, "Lire le nouveau PIN sur l'entrée standard (sinon, un PIN à 6 chiffres est généré)")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	user, err := a.findUser(ctx, *id, *username)
+	if err != nil {
+		return err
+	}
+
+	pin, generated := "", false
+	if *pinStdin {
+		if pin, err = readSecret(a.stdin); err != nil {
+			return err
+		}
+		if len(pin) < minPINLength || len(pin) > maxPINLength {
+			return fmt.Errorf("PIN must be between %d and %d characters", minPINLength, maxPINLength)
+		}
+	} else {
+		if pin, err = randomString("0123456789", 6); err != nil {
+			return err
+		}
+		generated = true
+	}
+
+	hash, err := bcrypt.GenerateFromPassword([]byte(pin), bcrypt.DefaultCost)
+	if err != nil {
+		return err
+	}
+	if err := a.users.UpdatePasswordHash(ctx, user.ID, string(hash)); err != nil {
+		return err
+	}
+	a.logAction(ctx, "RESET_PIN", user.ID, user.Username)
+
+	fmt.Fprintf(a.stdout, "PIN réinitialisé pour %s (%s)\n", user.Username, user.Role)
+	if generated {
+		fmt.Fprintf(a.stdout, "Nouveau PIN (affiché une seule fois) : %s\n", pin)
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/uber/h3-go/v4"
+)
+
+// replayRecord est le format d'échange (JSON ou CSV) d'un signalement historique.
+// Les colonnes CSV portent les mêmes noms que les clés JSON.
+type replayRecord struct {
+	ID                string    `json:"id"`
+	ObserverID        string    `json:"observer_id"`
+	AuthorRole        string    `json:"author_role"`
+	IncidentType      string    `json:"incident_type"`
+	Description       string    `json:"description"`
+	Latitude          float64   `json:"latitude"`
+	Longitude         float64   `json:"longitude"`
+	H3Index           string    `json:"h3_index"`
+	Status            string    `json:"status"` // Statut final attribué (vérité terrain)
+	CreatedAt         time.Time `json:"created_at"`
+	AuthorTokenHash   string    `json:"author_token_hash"`
+	SourceFingerprint string    `json:"source_fingerprint"`
+	DeviceFingerprint string    `json:"device_fingerprint"`
+}
+
+func (r replayRecord) toReport() entity.Report {
+	h3Index := r.H3Index
+	if h3Index == "" {
+		h3Index = h3.LatLngToCell(h3.NewLatLng(r.Latitude, r.Longitude), 10).String()
+	}
+	return entity.Report{
+		ID:                r.ID,
+		ObserverID:        r.ObserverID,
+		AuthorRole:        entity.UserRole(r.AuthorRole),
+		IncidentType:      r.IncidentType,
+		Description:       r.Description,
+		GPSLocation:       fmt.Sprintf("POINT(%f %f)", r.Longitude, r.Latitude),
+		H3Index:           h3Index,
+		Status:            entity.ReportStatus(r.Status),
+		CreatedAt:         r.CreatedAt,
+		AuthorTokenHash:   r.AuthorTokenHash,
+		SourceFingerprint: r.SourceFingerprint,
+		DeviceFingerprint: r.DeviceFingerprint,
+	}
+}
+
+// loadFile lit un export JSON (tableau d'objets) ou CSV (avec en-tête)
+func loadFile(path, format string) ([]entity.Report, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	if format == "" {
+		format = strings.TrimPrefix(strings.ToLower(filepath.Ext(path)), ".")
+	}
+
+	var records []replayRecord
+	switch format {
+	case "json":
+		if err := json.NewDecoder(f).Decode(&records); err != nil {
+			return nil, fmt.Errorf("invalid JSON input: %w", err)
+		}
+	case "csv":
+		records, err = readCSV(f)
+		if err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unsupported input format %q (expected json or csv)", format)
+	}
+
+	return loadRecords(records), nil
+}
+
+func loadRecords(records []replayRecord) []entity.Report {
+	reports := make([]entity.Report, 0, len(records))
+	for _, r := range records {
+		reports = append(reports, r.toReport())
+	}
+	return reports
+}
+
+func readCSV(r io.Reader) ([]replayRecord, error) {
+	rows, err := csv.NewReader(r).ReadAll()
+	if err != nil {
+		return nil, fmt.Errorf("invalid CSV input: %w", err)
+	}
+	if len(rows) == 0 {
+		return nil, nil
+	}
+
+	columns := make(map[string]int, len(rows[0]))
+	for i, name := range rows[0] {
+		columns[strings.TrimSpace(name)] = i
+	}
+	for _, required := range []string{"id", "author_role", "incident_type", "latitude", "longitude", "status", "created_at"} {
+		if _, ok := columns[required]; !ok {
+			return nil, fmt.Errorf("missing CSV column %q", required)
+		}
+	}
+
+	records := make([]replayRecord, 0, len(rows)-1)
+	for line, row := range rows[1:] {
+		get := func(name string) string {
+			if i, ok := columns[name]; ok && i < len(row) {
+				return strings.TrimSpace(row[i])
+			}
+			return ""
+		}
+		lat, err := strconv.ParseFloat(get("latitude"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid latitude: %w", line+2, err)
+		}
+		lon, err := strconv.ParseFloat(get("longitude"), 64)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid longitude: %w", line+2, err)
+		}
+		createdAt, err := time.Parse(time.RFC3339, get("created_at"))
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid created_at: %w", line+2, err)
+		}
+		records = append(records, replayRecord{
+			ID:                get("id"),
+			ObserverID:        get("observer_id"),
+			AuthorRole:        get("author_role"),
+			IncidentType:      get("incident_type"),
+			Description:       get("description"),
+			Latitude:          lat,
+			Longitude:         lon,
+			H3Index:           get("h3_index"),
+			Status:            get("status"),
+			CreatedAt:         createdAt,
+			AuthorTokenHash:   get("author_token_hash"),
+			SourceFingerprint: get("source_fingerprint"),
+			DeviceFingerprint: get("device_fingerprint"),
+		})
+	}
+	return records, nil
+}
+
+// loadDB charge les signalements d'une période directement depuis la base (lecture seule)
+func loadDB(ctx context.Context, db *sql.DB, from, to time.Time) ([]entity.Report, error) {
+	query := `
+		SELECT r.id, r.observer_id, r.incident_type, COALESCE(r.description, ''), ST_AsText(r.gps_location), r.h3_index, r.status, r.created_at, u.role,
+		       COALESCE(r.source_fingerprint, ''), COALESCE(r.device_fingerprint, ''), COALESCE(u.activation_token_hash, '')
+		FROM reports r
+		JOIN users u ON r.observer_id = u.id
+		WHERE r.created_at BETWEEN $1 AND $2
+		ORDER BY r.created_at`
+	rows, err := db.QueryContext(ctx, query, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reports []entity.Report
+	for rows.Next() {
+		var report entity.Report
+		var roleStr string
+		if err := rows.Scan(&report.ID, &report.ObserverID, &report.IncidentType, &report.Description, &report.GPSLocation,
+			&report.H3Index, &report.Status, &report.CreatedAt, &roleStr,
+			&report.SourceFingerprint, &report.DeviceFingerprint, &report.AuthorTokenHash); err != nil {
+			return nil, err
+		}
+		report.AuthorRole = entity.UserRole(roleStr)
+		reports = append(reports, report)
+	}
+	return reports, rows.Err()
+}
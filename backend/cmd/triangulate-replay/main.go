@@ -0,0 +1,174 @@
+// Command triangulate-replay rejoue des signalements historiques contre une ou
+// plusieurs configurations de triangulation candidates, et compare les décisions
+// simulées aux statuts attribués par les modérateurs.
+//
+// Usage :
+//
+//	triangulate-replay -input reports.json [-configs candidates.json] [-output text|json]
+//	triangulate-replay -from-db -from 2026-06-01T00:00:00Z -to 2026-06-02T00:00:00Z
+//
+// La première configuration sert de référence pour lister les décisions modifiées.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/openvote/backend/internal/config"
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/platform/database"
+	"github.com/openvote/backend/internal/service"
+)
+
+func main() {
+	input := flag.String("input", "", "Fichier d'export des signalements (JSON ou CSV)")
+	format := flag.String("format", "", "Format du fichier d'entrée : json ou csv (déduit de l'extension par défaut)")
+	fromDB := flag.Bool("from-db", false, "Charger les signalements depuis PostgreSQL (configuration de l'API : CONFIG_FILE, variables DB_*)")
+	from := flag.String("from", "", "Début de la période chargée depuis la base (RFC3339)")
+	to := flag.String("to", "", "Fin de la période chargée depuis la base (RFC3339, défaut : maintenant)")
+	configsPath := flag.String("configs", "", "Fichier JSON contenant la liste des configurations candidates")
+	output := flag.String("output", "text", "Format du rapport : text ou json")
+	maxChanges := flag.Int("max-changes", 20, "Nombre maximal de décisions modifiées affichées par configuration (text)")
+	flag.Parse()
+
+	reports, err := loadReports(*input, *format, *fromDB, *from, *to)
+	if err != nil {
+		log.Fatalf("[REPLAY] Chargement impossible : %v", err)
+	}
+	if len(reports) == 0 {
+		log.Fatal("[REPLAY] Aucun signalement à rejouer")
+	}
+
+	candidates := defaultCandidates()
+	if *configsPath != "" {
+		candidates, err = loadConfigs(*configsPath)
+		if err != nil {
+			log.Fatalf("[REPLAY] Configurations invalides : %v", err)
+		}
+	}
+
+	results := runCandidates(reports, candidates, service.NewSybilDetector())
+
+	switch *output {
+	case "json":
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(results); err != nil {
+			log.Fatalf("[REPLAY] Écriture du rapport impossible : %v", err)
+		}
+	case "text":
+		printText(os.Stdout, len(reports), results, *maxChanges)
+	default:
+		log.Fatalf("[REPLAY] Format de sortie inconnu : %s", *output)
+	}
+}
+
+func loadReports(input, format string, fromDB bool, from, to string) ([]entity.Report, error) {
+	if !fromDB {
+		if input == "" {
+			return nil, fmt.Errorf("-input or -from-db is required")
+		}
+		return loadFile(input, format)
+	}
+
+	start, err := time.Parse(time.RFC3339, from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid -from: %w", err)
+	}
+	end := time.Now()
+	if to != "" {
+		if end, err = time.Parse(time.RFC3339, to); err != nil {
+			return nil, fmt.Errorf("invalid -to: %w", err)
+		}
+	}
+
+	// Seule la section database est lue : pas de validation des secrets de l'API
+	cfg, err := config.Load("")
+	if err != nil {
+		return nil, err
+	}
+	db, err := database.NewPostgresDB(cfg.Database.DSN())
+	if err != nil {
+		return nil, err
+	}
+	defer db.Close()
+	return loadDB(context.Background(), db, start, end)
+}
+
+// loadConfigs lit un tableau de TriangulationConfig ; les champs absents héritent de la configuration par défaut
+func loadConfigs(path string) ([]service.TriangulationConfig, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var raw []json.RawMessage
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	if len(raw) == 0 {
+		return nil, fmt.Errorf("no configuration in %s", path)
+	}
+
+	configs := make([]service.TriangulationConfig, 0, len(raw))
+	for i, msg := range raw {
+		cfg := service.DefaultTriangulationConfig()
+		cfg.Name = fmt.Sprintf("candidate-%d", i)
+		if err := json.Unmarshal(msg, &cfg); err != nil {
+			return nil, fmt.Errorf("configuration %d: %w", i, err)
+		}
+		if cfg.Threshold <= 0 || cfg.RadiusMeters <= 0 || cfg.TimeWindowMinutes <= 0 {
+			return nil, fmt.Errorf("configuration %q: threshold, radius_meters and time_window_minutes must be positive", cfg.Name)
+		}
+		configs = append(configs, cfg)
+	}
+	return configs, nil
+}
+
+// runCandidates rejoue chaque configuration et la compare à la première
+func runCandidates(reports []entity.Report, candidates []service.TriangulationConfig, detector service.SybilDetector) []replayResult {
+	results := make([]replayResult, 0, len(candidates))
+	for i, cfg := range candidates {
+		decisions := replay(reports, cfg, detector)
+		result := replayResult{Config: cfg, Metrics: computeMetrics(decisions), Decisions: decisions}
+		if i > 0 {
+			result.Changed = diffDecisions(results[0].Decisions, decisions)
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+func printText(w io.Writer, total int, results []replayResult, maxChanges int) {
+	fmt.Fprintf(w, "Signalements rejoués : %d\n\n", total)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "CONFIG\tRADIUS\tWINDOW\tTHRESHOLD\tTP\tFP\tFN\tTN\tPRECISION\tRECALL\tF1\tCHANGED")
+	for _, r := range results {
+		m := r.Metrics
+		fmt.Fprintf(tw, "%s\t%.0fm\t%dmin\t%.2f\t%d\t%d\t%d\t%d\t%.3f\t%.3f\t%.3f\t%d\n",
+			r.Config.Name, r.Config.RadiusMeters, r.Config.TimeWindowMinutes, r.Config.Threshold,
+			m.TruePositives, m.FalsePositives, m.FalseNegatives, m.TrueNegatives, m.Precision, m.Recall, m.F1, len(r.Changed))
+	}
+	tw.Flush()
+
+	for _, r := range results[1:] {
+		if len(r.Changed) == 0 {
+			continue
+		}
+		fmt.Fprintf(w, "\nDécisions modifiées par %s (référence : %s)\n", r.Config.Name, results[0].Config.Name)
+		for i, c := range r.Changed {
+			if i == maxChanges {
+				fmt.Fprintf(w, "  ... %d de plus\n", len(r.Changed)-maxChanges)
+				break
+			}
+			fmt.Fprintf(w, "  %s: %s -> %s (humain : %s)\n", c.ReportID, c.Baseline, c.Candidate, c.HumanLabel)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"sort"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/service"
+)
+
+// replayDecision est la décision simulée pour un signalement, comparée au statut humain
+type replayDecision struct {
+	ReportID   string                       `json:"report_id"`
+	Outcome    service.TriangulationOutcome `json:"outcome"`
+	Score      float64                      `json:"score"`
+	HumanLabel entity.ReportStatus          `json:"human_label"`
+}
+
+// replayMetrics mesure l'auto-vérification par rapport aux décisions humaines.
+// Positif = vérifié ; les signalements encore "pending" sont exclus de la matrice.
+type replayMetrics struct {
+	TruePositives  int     `json:"true_positives"`
+	FalsePositives int     `json:"false_positives"`
+	FalseNegatives int     `json:"false_negatives"`
+	TrueNegatives  int     `json:"true_negatives"`
+	Unlabeled      int     `json:"unlabeled"`
+	Precision      float64 `json:"precision"`
+	Recall         float64 `json:"recall"`
+	F1             float64 `json:"f1"`
+}
+
+// replayResult regroupe le résultat d'une configuration candidate
+type replayResult struct {
+	Config    service.TriangulationConfig `json:"config"`
+	Metrics   replayMetrics               `json:"metrics"`
+	Decisions []replayDecision            `json:"-"`
+	Changed   []decisionChange            `json:"changed_decisions,omitempty"`
+}
+
+// decisionChange signale un signalement dont la décision diffère de la configuration de référence
+type decisionChange struct {
+	ReportID   string                       `json:"report_id"`
+	Baseline   service.TriangulationOutcome `json:"baseline"`
+	Candidate  service.TriangulationOutcome `json:"candidate"`
+	HumanLabel entity.ReportStatus          `json:"human_label"`
+}
+
+// replay rejoue chronologiquement les signalements : chacun est évalué à son arrivée,
+// en ne voyant que les signalements déjà reçus, avec les statuts simulés par le rejeu
+// (et non les statuts finaux, qui servent de vérité terrain).
+func replay(reports []entity.Report, cfg service.TriangulationConfig, detector service.SybilDetector) []replayDecision {
+	ordered := make([]entity.Report, len(reports))
+	copy(ordered, reports)
+	sort.SliceStable(ordered, func(i, j int) bool { return ordered[i].CreatedAt.Before(ordered[j].CreatedAt) })
+
+	labels := make(map[string]entity.ReportStatus, len(ordered))
+	for i := range ordered {
+		labels[ordered[i].ID] = ordered[i].Status
+		ordered[i].Status = entity.StatusPending
+	}
+
+	inConflict := make(map[string]bool)
+	decisions := make([]replayDecision, 0, len(ordered))
+	for i, target := range ordered {
+		neighbors := service.FindNeighbors(target, ordered[:i+1], cfg)
+		decision := service.EvaluateTriangulation(neighbors, cfg, detector)
+
+		outcome := decision.Outcome
+		switch outcome {
+		case service.OutcomeConflict:
+			for _, n := range neighbors {
+				inConflict[n.ID] = true
+			}
+		case service.OutcomeVerified, service.OutcomeSuspicious:
+			// Sans résolution humaine simulée, un conflit reste ouvert pendant tout le rejeu
+			if inConflict[target.ID] {
+				outcome = service.OutcomeConflict
+			}
+		}
+		if outcome == service.OutcomeVerified {
+			ordered[i].Status = entity.StatusVerified
+		}
+
+		decisions = append(decisions, replayDecision{
+			ReportID:   target.ID,
+			Outcome:    outcome,
+			Score:      decision.Score,
+			HumanLabel: labels[target.ID],
+		})
+	}
+	return decisions
+}
+
+// computeMetrics construit la matrice de confusion et les scores dérivés
+func computeMetrics(decisions []replayDecision) replayMetrics {
+	var m replayMetrics
+	for _, d := range decisions {
+		predicted := d.Outcome == service.OutcomeVerified
+		switch d.HumanLabel {
+		case entity.StatusVerified:
+			if predicted {
+				m.TruePositives++
+			} else {
+				m.FalseNegatives++
+			}
+		case entity.StatusRejected:
+			if predicted {
+				m.FalsePositives++
+			} else {
+				m.TrueNegatives++
+			}
+		default:
+			m.Unlabeled++
+		}
+	}
+	if tp := float64(m.TruePositives); tp > 0 {
+		m.Precision = tp / float64(m.TruePositives+m.FalsePositives)
+		m.Recall = tp / float64(m.TruePositives+m.FalseNegatives)
+		m.F1 = 2 * m.Precision * m.Recall / (m.Precision + m.Recall)
+	}
+	return m
+}
+
+// diffDecisions liste les décisions qui changent par rapport à la configuration de référence
+func diffDecisions(baseline, candidate []replayDecision) []decisionChange {
+	byID := make(map[string]service.TriangulationOutcome, len(baseline))
+	for _, d := range baseline {
+		byID[d.ReportID] = d.Outcome
+	}
+	var changes []decisionChange
+	for _, d := range candidate {
+		if before := byID[d.ReportID]; before != d.Outcome {
+			changes = append(changes, decisionChange{ReportID: d.ReportID, Baseline: before, Candidate: d.Outcome, HumanLabel: d.HumanLabel})
+		}
+	}
+	return changes
+}
+
+// defaultCandidates fournit une grille de configurations autour de la configuration de production
+func defaultCandidates() []service.TriangulationConfig {
+	base := service.DefaultTriangulationConfig()
+	variant := func(name string, mutate func(*service.TriangulationConfig)) service.TriangulationConfig {
+		c := base
+		c.Name = name
+		mutate(&c)
+		return c
+	}
+	return []service.TriangulationConfig{
+		base,
+		variant("radius-250m", func(c *service.TriangulationConfig) { c.RadiusMeters = 250 }),
+		variant("radius-1000m", func(c *service.TriangulationConfig) { c.RadiusMeters = 1000 }),
+		variant("window-15min", func(c *service.TriangulationConfig) { c.TimeWindowMinutes = 15 }),
+		variant("window-60min", func(c *service.TriangulationConfig) { c.TimeWindowMinutes = 60 }),
+		variant("threshold-1.2", func(c *service.TriangulationConfig) { c.Threshold = 1.2 }),
+		variant("citizen-0.15", func(c *service.TriangulationConfig) { c.Weights.Citizen = 0.15 }),
+	}
+}
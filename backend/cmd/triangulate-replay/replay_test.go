@@ -0,0 +1,62 @@
+package main
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/openvote/backend/internal/service"
+)
+
+const sampleCSV = `id,observer_id,author_role,incident_type,description,latitude,longitude,status,created_at
+r1,u1,citizen,FRAUD,Urne bourrée,5.3600,-4.0083,verified,2026-06-01T10:00:00Z
+r2,u2,verified_citizen,FRAUD,Urne bourrée,5.3601,-4.0083,verified,2026-06-01T10:05:00Z
+r3,u3,observer,FRAUD,Urne bourrée,5.3602,-4.0084,verified,2026-06-01T10:10:00Z
+r4,u4,citizen,VIOLENCE,Bagarre,5.5000,-4.2000,rejected,2026-06-01T12:00:00Z
+`
+
+func TestReplay_OnlySeesEarlierReports(t *testing.T) {
+	records, err := readCSV(strings.NewReader(sampleCSV))
+	if err != nil {
+		t.Fatalf("readCSV: %v", err)
+	}
+	if len(records) != 4 {
+		t.Fatalf("expected 4 records, got %d", len(records))
+	}
+	reports := loadRecords(records)
+
+	decisions := replay(reports, service.DefaultTriangulationConfig(), service.NewSybilDetector())
+	got := map[string]service.TriangulationOutcome{}
+	for _, d := range decisions {
+		got[d.ReportID] = d.Outcome
+	}
+
+	// r1 et r2 arrivent avant l'observateur : score insuffisant à leur arrivée
+	if got["r1"] != service.OutcomeInsufficient || got["r2"] != service.OutcomeInsufficient {
+		t.Errorf("expected r1 and r2 insufficient, got %s and %s", got["r1"], got["r2"])
+	}
+	if got["r3"] != service.OutcomeVerified {
+		t.Errorf("expected r3 verified, got %s", got["r3"])
+	}
+
+	m := computeMetrics(decisions)
+	if m.TruePositives != 1 || m.FalseNegatives != 2 || m.TrueNegatives != 1 || m.FalsePositives != 0 {
+		t.Errorf("unexpected confusion matrix: %+v", m)
+	}
+	if m.Precision != 1 {
+		t.Errorf("expected precision 1, got %.2f", m.Precision)
+	}
+}
+
+func TestDiffDecisions_ListsChangedReports(t *testing.T) {
+	records, _ := readCSV(strings.NewReader(sampleCSV))
+	reports := loadRecords(records)
+
+	strict := service.DefaultTriangulationConfig()
+	strict.Threshold = 2.0
+	results := runCandidates(reports, []service.TriangulationConfig{service.DefaultTriangulationConfig(), strict}, service.NewSybilDetector())
+
+	changed := results[1].Changed
+	if len(changed) != 1 || changed[0].ReportID != "r3" || changed[0].Candidate != service.OutcomeInsufficient {
+		t.Errorf("expected only r3 to change to insufficient, got %+v", changed)
+	}
+}
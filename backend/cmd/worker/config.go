@@ -0,0 +1,23 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/openvote/backend/internal/config"
+	"github.com/openvote/backend/internal/platform/queue"
+)
+
+// loadConfig charge la configuration partagée (fichier, WORKER_*, QUEUE_BACKEND...) et
+// vérifie les réglages propres au binaire worker ; done : --print-config, arrêter ici
+func loadConfig(flags *config.Flags) (cfg config.Config, done bool, err error) {
+	cfg, done, err = flags.Load(os.Stdout)
+	if err != nil || done {
+		return cfg, done, err
+	}
+	// Un broker en mémoire n'est pas partagé avec l'API : le worker ne recevrait rien
+	if cfg.Queue.Backend == queue.BackendMemory {
+		return cfg, false, fmt.Errorf("queue backend %q cannot be shared between processes", queue.BackendMemory)
+	}
+	return cfg, false, nil
+}
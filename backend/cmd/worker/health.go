@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/openvote/backend/internal/platform/telemetry"
+)
+
+// healthState expose l'état du worker au point de santé
+type healthState struct {
+	ping      func(ctx context.Context) error // Ping de la base
+	queue     string                          // Backend de file utilisé
+	queues    []string                        // Files consommées
+	consuming atomic.Bool
+	draining  atomic.Bool
+}
+
+// handle répond 200 si le worker consomme et que la base répond, 503 sinon
+func (h *healthState) handle(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 2*time.Second)
+	defer cancel()
+
+	status := http.StatusOK
+	database := "up"
+	if h.ping == nil {
+		database = "down"
+		status = http.StatusServiceUnavailable
+	} else if err := h.ping(ctx); err != nil {
+		database = "down"
+		status = http.StatusServiceUnavailable
+	}
+
+	state := "consuming"
+	switch {
+	case h.draining.Load():
+		state = "draining"
+		status = http.StatusServiceUnavailable
+	case !h.consuming.Load():
+		state = "stopped"
+		status = http.StatusServiceUnavailable
+	}
+
+	label := "healthy"
+	if status != http.StatusOK {
+		label = "unhealthy"
+	}
+	c.JSON(status, gin.H{
+		"status":   label,
+		"state":    state,
+		"database": database,
+		"queue":    h.queue,
+		"queues":   h.queues,
+	})
+}
+
+func newHealthRouter(h *healthState) *gin.Engine {
+	r := gin.New()
+	r.Use(gin.Recovery())
+	r.GET("/health", h.handle)
+	// Métriques Prometheus : files (publication, traitement, attente), triangulation, pool SQL
+	r.GET("/metrics", gin.WrapH(telemetry.Handler()))
+	return r
+}
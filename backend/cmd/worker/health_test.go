@@ -0,0 +1,68 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/openvote/backend/internal/config"
+)
+
+func healthStatus(t *testing.T, h *healthState) (int, map[string]interface{}) {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	newHealthRouter(h).ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/health", nil))
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	return w.Code, body
+}
+
+func TestHealth(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+	down := func(ctx context.Context) error { return errors.New("connection refused") }
+
+	h := &healthState{ping: ok, queue: "postgres", queues: []string{"new_reports"}}
+	if code, body := healthStatus(t, h); code != http.StatusServiceUnavailable || body["state"] != "stopped" {
+		t.Errorf("before start: %d %v", code, body)
+	}
+
+	h.consuming.Store(true)
+	if code, body := healthStatus(t, h); code != http.StatusOK || body["status"] != "healthy" {
+		t.Errorf("consuming: %d %v", code, body)
+	}
+
+	h.ping = down
+	if code, body := healthStatus(t, h); code != http.StatusServiceUnavailable || body["database"] != "down" {
+		t.Errorf("database down: %d %v", code, body)
+	}
+
+	h.ping = ok
+	h.draining.Store(true)
+	if code, body := healthStatus(t, h); code != http.StatusServiceUnavailable || body["state"] != "draining" {
+		t.Errorf("draining: %d %v", code, body)
+	}
+}
+
+func TestLoadConfig_RejectsMemoryBackend(t *testing.T) {
+	t.Setenv("QUEUE_BACKEND", "memory")
+	if _, _, err := loadConfig(&config.Flags{}); err == nil {
+		t.Fatal("expected memory backend to be rejected for a standalone worker")
+	}
+
+	t.Setenv("QUEUE_BACKEND", "postgres")
+	t.Setenv("WORKER_SHUTDOWN_TIMEOUT", "45s")
+	cfg, _, err := loadConfig(&config.Flags{})
+	if err != nil {
+		t.Fatalf("load config: %v", err)
+	}
+	if cfg.Worker.HealthPort != "8096" || cfg.Worker.ShutdownTimeout.Std().Seconds() != 45 || !cfg.Worker.OutboxRelay {
+		t.Errorf("unexpected config: %+v", cfg.Worker)
+	}
+}
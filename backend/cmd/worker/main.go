@@ -0,0 +1,171 @@
+// Commande worker : traitement asynchrone des signalements (triangulation, regroupement)
+// et relais de l'outbox, déployable indépendamment de l'API HTTP.
+//
+// Usage :
+//
+//	QUEUE_BACKEND=rabbitmq WORKER_POOL_SIZE=8 go run ./cmd/worker [--config openvote.yaml] [--print-config]
+package main
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"log"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/openvote/backend/internal/config"
+	"github.com/openvote/backend/internal/domain/event"
+	"github.com/openvote/backend/internal/platform/database"
+	"github.com/openvote/backend/internal/platform/logging"
+	"github.com/openvote/backend/internal/platform/migrate"
+	"github.com/openvote/backend/internal/platform/queue"
+	"github.com/openvote/backend/internal/platform/telemetry"
+	"github.com/openvote/backend/internal/repository/postgres"
+	"github.com/openvote/backend/internal/service"
+	"github.com/openvote/backend/internal/worker"
+)
+
+// Loggers du démarrage et de l'arrêt du worker
+var (
+	workerLogger = logging.For("worker")
+	configLogger = logging.For("config")
+)
+
+func main() {
+	var flags config.Flags
+	flags.Register(flag.CommandLine)
+	flag.Parse()
+	cfg, done, err := loadConfig(&flags)
+	if err != nil {
+		log.Fatalf("[WORKER] Configuration invalide: %v", err)
+	}
+	if done {
+		return
+	}
+
+	logConfig, _ := cfg.Logging.Options() // Vérifiée par Validate
+	logging.Setup(os.Stdout, "openvote-worker", logConfig)
+	for _, problem := range cfg.Insecure() {
+		configLogger.Warn("Réglage refusé en production", "problem", problem)
+	}
+	service.SetFingerprintKey(cfg.Auth.FingerprintKey())
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Les traitements poursuivent les traces des requêtes de l'API (en-têtes des messages)
+	shutdownTracing, err := telemetry.SetupTracing(ctx, "openvote-worker")
+	if err != nil {
+		log.Fatalf("[WORKER] %v", err)
+	}
+
+	// Le worker ne joue pas les migrations ("main migrate up") mais refuse un schéma en retard
+	db, err := database.NewPostgresDB(cfg.Database.DSN())
+	if err != nil {
+		log.Fatalf("[WORKER] Could not connect to database: %v", err)
+	}
+	defer db.Close()
+	telemetry.RegisterDBStats(db)
+
+	migrator, err := migrate.Open(db, cfg.Database.MigrationsDir)
+	if err != nil {
+		log.Fatalf("[WORKER] Migrations illisibles: %v", err)
+	}
+	if err := migrator.Check(ctx); err != nil {
+		log.Fatalf("[WORKER] %v", err)
+	}
+
+	publisher, consumer, err := queue.Open(cfg.Queue.Options(), db)
+	if err != nil {
+		log.Fatalf("[WORKER] Could not open queue backend %q: %v", cfg.Queue.Backend, err)
+	}
+	defer publisher.Close()
+	defer consumer.Close()
+
+	reportRepo := postgres.NewReportRepository(db)
+	clusterRepo := postgres.NewSuspiciousClusterRepository(db)
+	conflictRepo := postgres.NewConflictRepository(db)
+	eventRepo := postgres.NewIncidentEventRepository(db)
+	outboxRepo := postgres.NewOutboxRepository(db)
+	webhookRepo := postgres.NewWebhookRepository(db)
+	alertRepo := postgres.NewAlertRepository(db)
+	notificationRepo := postgres.NewNotificationRepository(db)
+	userRepo := postgres.NewUserRepository(db)
+	configRepo := postgres.NewConfigRepository(db)
+
+	eventPublisher := service.NewEventPublisher(outboxRepo)
+	triangulationService := service.NewTriangulationService(reportRepo, clusterRepo, conflictRepo, eventPublisher)
+	clusteringService := service.NewClusteringService(reportRepo, eventRepo)
+
+	// Paramètres de triangulation modifiés depuis l'administration, repris à chaud
+	configService := service.NewConfigService(configRepo, eventPublisher)
+	configService.OnChange(func(section string, cfg service.SystemConfig) {
+		if section == service.ConfigSectionTriangulation {
+			triangulationService.Reconfigure(cfg.Triangulation)
+		}
+	})
+	if err := configService.Load(ctx); err != nil {
+		configLogger.Warn("Configuration système indisponible, valeurs par défaut", "error", err)
+	}
+	go configService.Start(ctx)
+
+	health := &healthState{ping: db.PingContext, queue: cfg.Queue.Backend, queues: queue.ReportQueues}
+	if health.queue == "" {
+		health.queue = queue.BackendRabbitMQ
+	}
+
+	reportConsumer := worker.NewReportConsumer(consumer, triangulationService, clusteringService)
+	if err := reportConsumer.Start(ctx); err != nil {
+		log.Fatalf("[WORKER] Could not start report consumer: %v", err)
+	}
+
+	// Abonnés aux événements métier
+	eventRegistry := worker.NewEventRegistry(consumer)
+	webhookDispatcher := worker.NewWebhookDispatcher(service.NewWebhookService(webhookRepo, nil, cfg.Env == config.EnvDevelopment))
+	eventRegistry.Subscribe(worker.SubscriberWebhooks, webhookDispatcher.HandleEvent, "#")
+	alertService := service.NewAlertService(alertRepo, reportRepo, eventPublisher)
+	eventRegistry.Subscribe(worker.SubscriberAlerts, worker.NewAlertEvaluator(alertService).HandleEvent, event.TypeReportCreated)
+	notificationService := service.NewNotificationService(notificationRepo, cfg.Notify.Channels()...)
+	eventRegistry.Subscribe(worker.SubscriberNotifications, worker.NewAlertNotifier(notificationService, userRepo).HandleEvent,
+		event.TypeAlertTriggered, event.TypeAlertStatusChanged)
+	if err := eventRegistry.Start(ctx); err != nil {
+		log.Fatalf("[WORKER] Could not start event subscribers: %v", err)
+	}
+	go webhookDispatcher.Start(ctx)
+	go worker.NewNotificationDispatcher(notificationService).Start(ctx)
+	health.queues = append(health.queues, eventRegistry.Subscribers()...)
+	health.consuming.Store(true)
+
+	if cfg.Worker.OutboxRelay {
+		go worker.NewOutboxRelay(outboxRepo, publisher).Start(ctx)
+	}
+
+	srv := &http.Server{Addr: ":" + cfg.Worker.HealthPort, Handler: newHealthRouter(health)}
+	go func() {
+		workerLogger.Info("Health endpoint started", "port", cfg.Worker.HealthPort)
+		if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			workerLogger.Error("Health server error", "error", err)
+		}
+	}()
+
+	<-ctx.Done()
+	stop()
+	health.draining.Store(true)
+	workerLogger.Info("Arrêt demandé : fin des messages en cours")
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.Worker.ShutdownTimeout.Std())
+	defer cancel()
+	if err := consumer.Drain(shutdownCtx); err != nil {
+		workerLogger.Warn("Messages encore en traitement à l'arrêt, ils seront redistribués", "error", err)
+	}
+	if err := srv.Shutdown(shutdownCtx); err != nil {
+		workerLogger.Warn("Arrêt du point de santé incomplet", "error", err)
+	}
+	if err := shutdownTracing(shutdownCtx); err != nil {
+		workerLogger.Warn("Spans non exportés à l'arrêt", "error", err)
+	}
+	workerLogger.Info("Arrêt terminé")
+}
@@ -0,0 +1,37 @@
+package config
+
+import (
+	"flag"
+	"fmt"
+	"io"
+)
+
+// Flags : options communes aux binaires (--config, --print-config)
+type Flags struct {
+	Path  string
+	Print bool
+}
+
+// Register déclare les options sur fs
+func (f *Flags) Register(fs *flag.FlagSet) {
+	fs.StringVar(&f.Path, "config", "", "Fichier de configuration YAML ou TOML ("+FileEnv+" par défaut)")
+	fs.BoolVar(&f.Print, "print-config", false, "Affiche la configuration effective, secrets masqués, puis quitte")
+}
+
+// Load charge et valide la configuration. Avec --print-config, elle est écrite sur w et done
+// indique que le programme doit s'arrêter (après l'erreur de validation éventuelle).
+func (f *Flags) Load(w io.Writer) (cfg Config, done bool, err error) {
+	cfg, err = Load(f.Path)
+	if err != nil {
+		return cfg, f.Print, err
+	}
+	if f.Print {
+		if err := Print(w, cfg); err != nil {
+			return cfg, true, err
+		}
+	}
+	if err := cfg.Validate(); err != nil {
+		return cfg, f.Print, fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return cfg, f.Print, nil
+}
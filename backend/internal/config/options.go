@@ -0,0 +1,53 @@
+package config
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/openvote/backend/internal/platform/logging"
+	"github.com/openvote/backend/internal/platform/notify"
+	"github.com/openvote/backend/internal/platform/queue"
+)
+
+// Options convertit la section en configuration du paquet queue
+func (q QueueConfig) Options() queue.Config {
+	return queue.Config{
+		Backend:   q.Backend,
+		RabbitURL: q.RabbitURL,
+		Consumer: queue.ConsumerOptions{
+			Prefetch:       q.Prefetch,
+			Workers:        q.PoolSize,
+			MessageTimeout: q.MessageTimeout.Std(),
+		},
+	}
+}
+
+// Options convertit la section en configuration du journal ; les niveaux invalides sont
+// rapportés par Validate
+func (l LoggingConfig) Options() (logging.Config, error) {
+	cfg := logging.Config{Format: l.Format, Levels: map[string]slog.Level{}}
+	if l.Format != logging.FormatJSON && l.Format != logging.FormatText {
+		return cfg, fmt.Errorf("logging.format (LOG_FORMAT) must be %s or %s, got %q", logging.FormatJSON, logging.FormatText, l.Format)
+	}
+	if err := cfg.Level.UnmarshalText([]byte(l.Level)); err != nil {
+		return cfg, fmt.Errorf("logging.level (LOG_LEVEL): %w", err)
+	}
+	for component, value := range l.Levels {
+		var level slog.Level
+		if err := level.UnmarshalText([]byte(value)); err != nil {
+			return cfg, fmt.Errorf("logging.levels (LOG_LEVELS) for %s: %w", component, err)
+		}
+		cfg.Levels[strings.ToLower(component)] = level
+	}
+	return cfg, nil
+}
+
+// Channels retourne les canaux externes configurés ; sans configuration, seule la boîte de
+// réception in-app reste disponible
+func (n NotifyConfig) Channels() []notify.Channel {
+	return notify.Channels(
+		notify.SMTPConfig{Host: n.SMTP.Host, Port: n.SMTP.Port, Username: n.SMTP.Username, Password: n.SMTP.Password, From: n.SMTP.From},
+		notify.SMSConfig{URL: n.SMS.URL, Token: n.SMS.Token, Sender: n.SMS.Sender},
+	)
+}
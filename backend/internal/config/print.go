@@ -0,0 +1,45 @@
+package config
+
+import (
+	"io"
+	"reflect"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Redacted remplace la valeur des secrets dans Print
+const Redacted = "[REDACTED]"
+
+// Print écrit la configuration effective en YAML, relisible avec --config. Les champs
+// marqués secret sont masqués (seul le mot de passe pour une URL) ; un secret vide reste
+// vide, pour distinguer « non défini » de « défini ».
+func Print(w io.Writer, cfg Config) error {
+	redacted := cfg
+	redact(reflect.ValueOf(&redacted).Elem())
+	enc := yaml.NewEncoder(w)
+	enc.SetIndent(2)
+	if err := enc.Encode(redacted); err != nil {
+		return err
+	}
+	return enc.Close()
+}
+
+func redact(v reflect.Value) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field, value := t.Field(i), v.Field(i)
+		if value.Kind() == reflect.Struct {
+			redact(value)
+			continue
+		}
+		if value.Kind() != reflect.String || value.String() == "" {
+			continue
+		}
+		switch field.Tag.Get("secret") {
+		case "true":
+			value.SetString(Redacted)
+		case "url":
+			value.SetString(redactURL(value.String()))
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/openvote/backend/internal/platform/queue"
+)
+
+// minSecretLength : longueur minimale des secrets de signature en production
+const minSecretLength = 32
+
+// knownDefaults : valeurs publiées (docker-compose, exemples, anciennes valeurs par défaut du
+// code) qu'un déploiement de production ne doit jamais conserver
+var knownDefaults = map[string]bool{
+	"minioadmin":                         true,
+	"default-secret-change-me":           true,
+	DevJWTSecret:                         true,
+	"openvote-dev-secret-change-in-prod": true,
+	"securepassword":                     true,
+	"changeme":                           true,
+	"password":                           true,
+}
+
+// IsProduction indique si la configuration s'applique à un déploiement de production
+func (c Config) IsProduction() bool {
+	return c.Env == EnvProduction
+}
+
+// Validate vérifie la cohérence de la configuration ; en production, les réglages signalés par
+// Insecure sont également refusés. Toutes les erreurs sont rapportées ensemble.
+func (c Config) Validate() error {
+	var errs []error
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.Env != EnvDevelopment && c.Env != EnvProduction {
+		fail("env (APP_ENV) must be %s or %s, got %q", EnvDevelopment, EnvProduction, c.Env)
+	}
+	if c.Database.Backend != DBBackendPostgres && c.Database.Backend != DBBackendMemory {
+		fail("database.backend (DB_BACKEND) must be %s or %s, got %q", DBBackendPostgres, DBBackendMemory, c.Database.Backend)
+	}
+	switch c.Queue.Backend {
+	case queue.BackendRabbitMQ, queue.BackendMemory, queue.BackendPostgres:
+	default:
+		fail("queue.backend (QUEUE_BACKEND) must be %s, %s or %s, got %q", queue.BackendRabbitMQ, queue.BackendPostgres, queue.BackendMemory, c.Queue.Backend)
+	}
+	if c.Queue.PoolSize < 1 || c.Queue.Prefetch < 1 {
+		fail("queue.pool_size and queue.prefetch must be positive")
+	}
+	if c.Queue.MessageTimeout <= 0 || c.Worker.ShutdownTimeout <= 0 {
+		fail("queue.message_timeout and worker.shutdown_timeout must be positive")
+	}
+	if c.Server.MetricsAddr != "" {
+		// Le port public servirait les métriques à tout le monde : écoute distincte obligatoire
+		if _, port, err := net.SplitHostPort(c.Server.MetricsAddr); err != nil {
+			fail("server.metrics_addr (METRICS_ADDR) must be host:port, got %q", c.Server.MetricsAddr)
+		} else if port == c.Server.Port {
+			fail("server.metrics_addr (METRICS_ADDR) must not use the public port %s", c.Server.Port)
+		}
+	}
+	if c.Auth.JWTSecret == "" {
+		fail("auth.jwt_secret (JWT_SECRET) is required")
+	}
+	if _, err := c.Logging.Options(); err != nil {
+		errs = append(errs, err)
+	}
+
+	if c.IsProduction() {
+		// Dépôts en mémoire : les signalements seraient perdus au premier redémarrage
+		if c.Database.Backend == DBBackendMemory {
+			fail("database.backend (DB_BACKEND) %s is not allowed in production", DBBackendMemory)
+		}
+		for _, problem := range c.Insecure() {
+			fail("insecure setting in production: %s", problem)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Insecure liste les réglages acceptables en développement mais pas en production : secrets
+// absents, trop courts ou laissés à une valeur publiée
+func (c Config) Insecure() []string {
+	var problems []string
+	check := func(name, value string, required bool, minLength int) {
+		switch {
+		case value == "":
+			if required {
+				problems = append(problems, name+" is not set")
+			}
+		case knownDefaults[strings.ToLower(value)]:
+			problems = append(problems, name+" uses a published default value")
+		case len(value) < minLength:
+			problems = append(problems, fmt.Sprintf("%s is shorter than %d characters", name, minLength))
+		}
+	}
+
+	check("auth.jwt_secret (JWT_SECRET)", c.Auth.JWTSecret, true, minSecretLength)
+	check("auth.fingerprint_secret (FINGERPRINT_SECRET)", c.Auth.FingerprintSecret, false, minSecretLength)
+	check("storage.access_key (MINIO_ACCESS_KEY)", c.Storage.AccessKey, true, 0)
+	check("storage.secret_key (MINIO_SECRET_KEY)", c.Storage.SecretKey, true, 0)
+	if c.Database.Backend == DBBackendPostgres {
+		check("database.password (DB_PASSWORD)", c.Database.Password, true, 0)
+	}
+	return problems
+}
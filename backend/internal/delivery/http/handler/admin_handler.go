@@ -2,16 +2,18 @@ package handler
 
 import (
 	"context"
-	"log"
 	"net/http"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/event"
 	"github.com/openvote/backend/internal/domain/repository"
+	"github.com/openvote/backend/internal/platform/logging"
 	"github.com/openvote/backend/internal/service"
 )
 
@@ -24,9 +26,10 @@ type AdminHandler struct {
 	legalRepo            repository.LegalRepository
 	embeddingService     service.EmbeddingService
 	legalAnalysisService service.LegalAnalysisService
+	events               event.Publisher
 }
 
-func NewAdminHandler(enrolmentService service.EnrolmentService, userRepo repository.UserRepository, auditRepo repository.AuditLogRepository, reportService service.ReportService, electionRepo repository.ElectionRepository, legalRepo repository.LegalRepository, embeddingService service.EmbeddingService, legalAnalysisService service.LegalAnalysisService) *AdminHandler {
+func NewAdminHandler(enrolmentService service.EnrolmentService, userRepo repository.UserRepository, auditRepo repository.AuditLogRepository, reportService service.ReportService, electionRepo repository.ElectionRepository, legalRepo repository.LegalRepository, embeddingService service.EmbeddingService, legalAnalysisService service.LegalAnalysisService, events event.Publisher) *AdminHandler {
 	return &AdminHandler{
 		enrolmentService:     enrolmentService,
 		userRepo:             userRepo,
@@ -36,9 +39,19 @@ func NewAdminHandler(enrolmentService service.EnrolmentService, userRepo reposit
 		legalRepo:            legalRepo,
 		embeddingService:     embeddingService,
 		legalAnalysisService: legalAnalysisService,
+		events:               events,
 	}
 }
 
+// Loggers des composants du paquet
+var (
+	auditLogger     = logging.For("audit")
+	eventsLogger    = logging.For("events")
+	embeddingLogger = logging.For("embedding")
+	qualifyLogger   = logging.For("qualify")
+	llmLogger       = logging.For("llm")
+)
+
 // logAction persiste un log d'audit en base
 func (h *AdminHandler) logAction(ctx context.Context, adminID, adminName, action, targetID, details string) {
 	entry := &entity.AuditLog{
@@ -49,9 +62,10 @@ func (h *AdminHandler) logAction(ctx context.Context, adminID, adminName, action
 		Details:   details,
 	}
 	if err := h.auditRepo.Create(ctx, entry); err != nil {
-		log.Printf("[AUDIT] Error persisting log: %v", err)
+		auditLogger.ErrorContext(ctx, "Error persisting audit log", "action", action, "error", err)
 	}
-	log.Printf("[AUDIT] %s | %s | cible: %s | %s", action, adminName, targetID, details)
+	// Console : identifiants seulement, le nom et les détails restent dans la table d'audit
+	auditLogger.InfoContext(ctx, "Admin action", "action", action, "admin_id", adminID, "target_id", targetID)
 }
 
 // ========================================
@@ -200,6 +214,19 @@ func (h *AdminHandler) UpdateUser(c *gin.Context) {
 	h.logAction(c.Request.Context(), currentAdminID.(string), adminName, "UPDATE_ROLE",
 		userID, "Ancien: "+string(user.Role)+" → Nouveau: "+input.Role)
 
+	if h.events != nil {
+		err := h.events.Publish(c.Request.Context(), event.UserRoleChanged{
+			UserID:    userID,
+			OldRole:   string(user.Role),
+			NewRole:   input.Role,
+			RegionID:  input.RegionID,
+			ChangedBy: currentAdminID.(string),
+		})
+		if err != nil {
+			eventsLogger.ErrorContext(c.Request.Context(), "Publication impossible", "event_type", event.TypeUserRoleChanged, "error", err)
+		}
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"message":  "Rôle mis à jour",
 		"user_id":  userID,
@@ -256,55 +283,6 @@ func (h *AdminHandler) GetAuditLogs(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"logs": logs, "total": len(logs)})
 }
 
-// ========================================
-// Configuration Système
-// ========================================
-func (h *AdminHandler) GetConfig(c *gin.Context) {
-	config := gin.H{
-		"triangulation": gin.H{
-			"threshold":           1.0,
-			"radius_meters":       500,
-			"time_window_minutes": 30,
-			"weights": gin.H{
-				"observer":         1.0,
-				"verified_citizen": 0.35,
-				"citizen":          0.2,
-				"other":            0.1,
-			},
-		},
-		"rate_limiting": gin.H{"global_per_minute": 100, "auth_per_minute": 10},
-		"storage":       gin.H{"bucket_name": "evidence", "upload_expiry_min": 15},
-		"roles":         []string{"super_admin", "region_admin", "local_coord", "observer", "verified_citizen", "citizen"},
-	}
-
-	// Merge overrides
-	for k, v := range configOverrides {
-		config[k] = v
-	}
-
-	c.JSON(http.StatusOK, config)
-}
-
-// configOverrides stocke les personnalisations de config
-var configOverrides = make(map[string]interface{})
-
-// UpdateConfig met à jour la configuration système
-func (h *AdminHandler) UpdateConfig(c *gin.Context) {
-	var input map[string]interface{}
-	if err := c.ShouldBindJSON(&input); err != nil {
-		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
-		return
-	}
-	for k, v := range input {
-		configOverrides[k] = v
-	}
-	// Log d'audit
-	adminName := c.GetString("username")
-	adminID, _ := c.Get("userID")
-	h.logAction(c.Request.Context(), adminID.(string), adminName, "UPDATE_CONFIG", "", "Configuration mise à jour")
-	c.JSON(http.StatusOK, gin.H{"message": "Configuration mise à jour", "overrides": configOverrides})
-}
-
 // ========================================
 // KPIs Dashboard
 // ========================================
@@ -500,19 +478,19 @@ func (h *AdminHandler) GenerateEmbeddings(c *gin.Context) {
 
 		embedding, err := h.embeddingService.GenerateEmbedding(ctx, text)
 		if err != nil {
-			log.Printf("[EMBEDDING] Erreur pour %s: %v", art.ArticleNumber, err)
+			embeddingLogger.ErrorContext(ctx, "Embedding impossible", "article", art.ArticleNumber, "error", err)
 			errors++
 			continue
 		}
 
 		if err := h.legalRepo.UpdateArticleEmbedding(ctx, art.ID, embedding); err != nil {
-			log.Printf("[EMBEDDING] Erreur DB pour %s: %v", art.ArticleNumber, err)
+			embeddingLogger.ErrorContext(ctx, "Enregistrement de l'embedding impossible", "article", art.ArticleNumber, "error", err)
 			errors++
 			continue
 		}
 
 		processed++
-		log.Printf("[EMBEDDING] ✓ %s - %s", art.ArticleNumber, art.Title)
+		embeddingLogger.InfoContext(ctx, "Embedding généré", "article", art.ArticleNumber, "title", art.Title)
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -620,7 +598,7 @@ func (h *AdminHandler) QualifyReport(c *gin.Context) {
 			MatchType:       "auto",
 		}
 		if err := h.legalRepo.CreateReportMatch(ctx, match); err != nil {
-			log.Printf("[QUALIFY] Erreur sauvegarde match: %v", err)
+			qualifyLogger.ErrorContext(ctx, "Enregistrement de la correspondance impossible", "report_id", reportID, "article", art.ArticleNumber, "error", err)
 			continue
 		}
 		match.ArticleNumber = art.ArticleNumber
@@ -719,7 +697,7 @@ func (h *AdminHandler) AnalyzeReport(c *gin.Context) {
 
 	llmAnalysis, err := h.legalAnalysisService.AnalyzeIncident(ctx, incident)
 	if err != nil {
-		log.Printf("[LLM] Erreur analyse: %v", err)
+		llmLogger.ErrorContext(ctx, "Analyse impossible", "report_id", reportID, "error", err)
 		// On retourne quand même les résultats RAG même si le LLM échoue
 		c.JSON(http.StatusOK, gin.H{
 			"report_id":    reportID,
@@ -740,7 +718,7 @@ func (h *AdminHandler) AnalyzeReport(c *gin.Context) {
 		LLMModel:       "mistral",
 	}
 	if err := h.legalRepo.SaveAnalysis(ctx, dbAnalysis); err != nil {
-		log.Printf("[LLM] Erreur sauvegarde analyse: %v", err)
+		llmLogger.ErrorContext(ctx, "Enregistrement de l'analyse impossible", "report_id", reportID, "error", err)
 	}
 
 	c.JSON(http.StatusOK, gin.H{
@@ -751,6 +729,23 @@ func (h *AdminHandler) AnalyzeReport(c *gin.Context) {
 	})
 }
 
+// GetReviewQueue retourne les signalements en attente de revue, les plus urgents d'abord :
+// sévérité décroissante, puis corroboration (signalements du même événement), puis ancienneté
+func (h *AdminHandler) GetReviewQueue(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 500 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit doit être compris entre 1 et 500"})
+		return
+	}
+
+	items, err := h.reportService.GetReviewQueue(c.Request.Context(), limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"items": items, "total": len(items)})
+}
+
 // GetReportAnalysis retourne l'analyse juridique existante d'un rapport
 func (h *AdminHandler) GetReportAnalysis(c *gin.Context) {
 	reportID := c.Param("id")
@@ -0,0 +1,226 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+	"github.com/openvote/backend/internal/service"
+)
+
+// AlertHandler expose les règles d'alerte (administration) et le suivi des alertes (coordinateurs)
+type AlertHandler struct {
+	alertService service.AlertService
+	userRepo     repository.UserRepository
+	auditRepo    repository.AuditLogRepository
+}
+
+func NewAlertHandler(alertService service.AlertService, userRepo repository.UserRepository, auditRepo repository.AuditLogRepository) *AlertHandler {
+	return &AlertHandler{alertService: alertService, userRepo: userRepo, auditRepo: auditRepo}
+}
+
+func (h *AlertHandler) logAction(c *gin.Context, action, targetID, details string) {
+	entry := &entity.AuditLog{
+		AdminID:   c.GetString("userID"),
+		AdminName: c.GetString("username"),
+		Action:    action,
+		TargetID:  targetID,
+		Details:   details,
+	}
+	if err := h.auditRepo.Create(c.Request.Context(), entry); err != nil {
+		auditLogger.ErrorContext(c.Request.Context(), "Error persisting audit log", "action", entry.Action, "error", err)
+	}
+}
+
+// respondAlertError traduit les erreurs du service en statut HTTP
+func respondAlertError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrAlertRuleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Règle introuvable"})
+	case errors.Is(err, service.ErrAlertNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Alerte introuvable"})
+	case errors.Is(err, service.ErrInvalidAlertRule):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidAlertTransition):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// ========================================
+// Règles d'alerte
+// ========================================
+
+// ListRules retourne toutes les règles
+func (h *AlertHandler) ListRules(c *gin.Context) {
+	rules, err := h.alertService.ListRules(c.Request.Context())
+	if err != nil {
+		respondAlertError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"rules": rules, "total": len(rules)})
+}
+
+// CreateRule enregistre une règle (valeurs par défaut : sévérité 1, regroupement par région, canal dashboard)
+func (h *AlertHandler) CreateRule(c *gin.Context) {
+	var rule entity.AlertRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	rule.ID = ""
+	rule.CreatedBy = c.GetString("userID")
+
+	if err := h.alertService.CreateRule(c.Request.Context(), &rule); err != nil {
+		respondAlertError(c, err)
+		return
+	}
+	h.logAction(c, "CREATE_ALERT_RULE", rule.ID, rule.Name)
+
+	c.JSON(http.StatusCreated, rule)
+}
+
+// GetRule retourne une règle
+func (h *AlertHandler) GetRule(c *gin.Context) {
+	rule, err := h.alertService.GetRule(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondAlertError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// UpdateRule remplace les conditions d'une règle
+func (h *AlertHandler) UpdateRule(c *gin.Context) {
+	var rule entity.AlertRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	rule.ID = c.Param("id")
+
+	if err := h.alertService.UpdateRule(c.Request.Context(), &rule); err != nil {
+		respondAlertError(c, err)
+		return
+	}
+	h.logAction(c, "UPDATE_ALERT_RULE", rule.ID, rule.Name)
+
+	c.JSON(http.StatusOK, rule)
+}
+
+// DeleteRule supprime une règle et ses alertes
+func (h *AlertHandler) DeleteRule(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.alertService.DeleteRule(c.Request.Context(), id); err != nil {
+		respondAlertError(c, err)
+		return
+	}
+	h.logAction(c, "DELETE_ALERT_RULE", id, "")
+
+	c.JSON(http.StatusOK, gin.H{"message": "Règle supprimée"})
+}
+
+// ========================================
+// Alertes
+// ========================================
+
+// authorizeAlert vérifie que l'alerte relève du périmètre de l'utilisateur
+func (h *AlertHandler) authorizeAlert(c *gin.Context, id string) (*entity.Alert, bool) {
+	scope, ok := resolveRegionScope(c, h.userRepo)
+	if !ok {
+		return nil, false
+	}
+	alert, err := h.alertService.GetAlert(c.Request.Context(), id)
+	if err != nil {
+		respondAlertError(c, err)
+		return nil, false
+	}
+	if !scope.All && alert.RegionID != scope.RegionID {
+		// Même réponse qu'une alerte inexistante : pas de fuite hors périmètre
+		c.JSON(http.StatusNotFound, gin.H{"error": "Alerte introuvable"})
+		return nil, false
+	}
+	return alert, true
+}
+
+// ListAlerts retourne les alertes du périmètre de l'utilisateur (?status=open&rule_id=&limit=)
+func (h *AlertHandler) ListAlerts(c *gin.Context) {
+	scope, ok := resolveRegionScope(c, h.userRepo)
+	if !ok {
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "100"))
+	if err != nil || limit < 1 || limit > 500 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit doit être compris entre 1 et 500"})
+		return
+	}
+	status := c.Query("status")
+	switch entity.AlertStatus(status) {
+	case "", entity.AlertOpen, entity.AlertAcknowledged, entity.AlertResolved:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status invalide: " + status})
+		return
+	}
+
+	filter := repository.AlertFilter{Status: status, RuleID: c.Query("rule_id"), Limit: limit}
+	if scope.All {
+		filter.RegionID = c.Query("region_id")
+	} else {
+		filter.RegionID = scope.RegionID
+	}
+	alerts, err := h.alertService.ListAlerts(c.Request.Context(), filter)
+	if err != nil {
+		respondAlertError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"alerts": alerts, "total": len(alerts)})
+}
+
+// GetAlert retourne une alerte et les signalements qui l'ont déclenchée
+func (h *AlertHandler) GetAlert(c *gin.Context) {
+	alert, ok := h.authorizeAlert(c, c.Param("id"))
+	if !ok {
+		return
+	}
+	c.JSON(http.StatusOK, alert)
+}
+
+// Acknowledge signale qu'un coordinateur prend l'alerte en charge
+func (h *AlertHandler) Acknowledge(c *gin.Context) {
+	if _, ok := h.authorizeAlert(c, c.Param("id")); !ok {
+		return
+	}
+	alert, err := h.alertService.Acknowledge(c.Request.Context(), c.Param("id"), c.GetString("userID"))
+	if err != nil {
+		respondAlertError(c, err)
+		return
+	}
+	h.logAction(c, "ACK_ALERT", alert.ID, alert.RuleName)
+
+	c.JSON(http.StatusOK, alert)
+}
+
+// Resolve clôt une alerte avec une note facultative
+func (h *AlertHandler) Resolve(c *gin.Context) {
+	var input struct {
+		Note string `json:"note"`
+	}
+	// Corps optionnel
+	_ = c.ShouldBindJSON(&input)
+
+	if _, ok := h.authorizeAlert(c, c.Param("id")); !ok {
+		return
+	}
+	alert, err := h.alertService.Resolve(c.Request.Context(), c.Param("id"), c.GetString("userID"), input.Note)
+	if err != nil {
+		respondAlertError(c, err)
+		return
+	}
+	h.logAction(c, "RESOLVE_ALERT", alert.ID, input.Note)
+
+	c.JSON(http.StatusOK, alert)
+}
@@ -0,0 +1,211 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+	"github.com/openvote/backend/internal/service"
+)
+
+// configRoles : rôles affichés avec la configuration (lecture seule)
+var configRoles = []string{"super_admin", "region_admin", "local_coord", "observer", "verified_citizen", "citizen"}
+
+// ConfigHandler expose la configuration système versionnée
+type ConfigHandler struct {
+	configService service.ConfigService
+	auditRepo     repository.AuditLogRepository
+}
+
+func NewConfigHandler(configService service.ConfigService, auditRepo repository.AuditLogRepository) *ConfigHandler {
+	return &ConfigHandler{configService: configService, auditRepo: auditRepo}
+}
+
+func (h *ConfigHandler) logAction(c *gin.Context, action, targetID, details string) {
+	entry := &entity.AuditLog{
+		AdminID:   c.GetString("userID"),
+		AdminName: c.GetString("username"),
+		Action:    action,
+		TargetID:  targetID,
+		Details:   details,
+	}
+	if err := h.auditRepo.Create(c.Request.Context(), entry); err != nil {
+		auditLogger.ErrorContext(c.Request.Context(), "Error persisting audit log", "action", entry.Action, "error", err)
+	}
+}
+
+// respondConfigError traduit les erreurs du service en statut HTTP ; les erreurs de validation
+// détaillent chaque champ refusé
+func respondConfigError(c *gin.Context, err error) {
+	var verr *service.ConfigValidationError
+	switch {
+	case errors.As(err, &verr):
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Configuration invalide", "fields": verr.Fields})
+	case errors.Is(err, service.ErrConfigVersionNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Version introuvable"})
+	case errors.Is(err, service.ErrConfigConflict):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// section vérifie le paramètre :section
+func (h *ConfigHandler) section(c *gin.Context) (string, bool) {
+	section := c.Param("section")
+	if !slices.Contains(service.ConfigSections, section) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Section inconnue: " + section, "sections": service.ConfigSections})
+		return "", false
+	}
+	return section, true
+}
+
+// GetConfig retourne la configuration courante et la version de chaque section
+func (h *ConfigHandler) GetConfig(c *gin.Context) {
+	cfg := h.configService.Current()
+	c.JSON(http.StatusOK, gin.H{
+		"triangulation": cfg.Triangulation,
+		"rate_limiting": cfg.RateLimiting,
+		"storage":       cfg.Storage,
+		"roles":         configRoles,
+		"versions":      h.configService.Versions(),
+	})
+}
+
+// UpdateConfig accepte la configuration complète ou partielle, indexée par section.
+// Les clés en lecture seule renvoyées par GetConfig (roles, versions) sont ignorées.
+func (h *ConfigHandler) UpdateConfig(c *gin.Context) {
+	var input map[string]json.RawMessage
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	var comment string
+	if raw, ok := input["comment"]; ok {
+		_ = json.Unmarshal(raw, &comment)
+	}
+	delete(input, "comment")
+	delete(input, "roles")
+	delete(input, "versions")
+
+	versions, err := h.configService.UpdateSections(c.Request.Context(), input, c.GetString("username"), comment)
+	if err != nil {
+		respondConfigError(c, err)
+		return
+	}
+	for _, v := range versions {
+		h.logAction(c, "UPDATE_CONFIG", v.Section, fmt.Sprintf("version %d", v.Version))
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Configuration mise à jour", "changed": versions, "versions": h.configService.Versions()})
+}
+
+// UpdateSection modifie une section : {"data": {...}, "comment": "...", "base_version": 3}.
+// base_version protège contre l'écrasement d'une modification concurrente (409).
+func (h *ConfigHandler) UpdateSection(c *gin.Context) {
+	section, ok := h.section(c)
+	if !ok {
+		return
+	}
+	var input struct {
+		Data        json.RawMessage `json:"data" binding:"required"`
+		Comment     string          `json:"comment"`
+		BaseVersion int64           `json:"base_version"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	v, err := h.configService.Update(c.Request.Context(), service.ConfigUpdate{
+		Section:     section,
+		Patch:       input.Data,
+		ChangedBy:   c.GetString("username"),
+		Comment:     input.Comment,
+		BaseVersion: input.BaseVersion,
+	})
+	if err != nil {
+		respondConfigError(c, err)
+		return
+	}
+	if v == nil {
+		c.JSON(http.StatusOK, gin.H{"message": "Aucun changement", "version": h.configService.Versions()[section]})
+		return
+	}
+	h.logAction(c, "UPDATE_CONFIG", section, fmt.Sprintf("version %d", v.Version))
+	c.JSON(http.StatusOK, v)
+}
+
+// History retourne les versions d'une section, de la plus récente à la plus ancienne (?limit=)
+func (h *ConfigHandler) History(c *gin.Context) {
+	section, ok := h.section(c)
+	if !ok {
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 500 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit doit être compris entre 1 et 500"})
+		return
+	}
+	versions, err := h.configService.History(c.Request.Context(), section, limit)
+	if err != nil {
+		respondConfigError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"section": section, "versions": versions, "current": h.configService.Versions()[section]})
+}
+
+// Diff compare deux versions (?from=&to=) ; 0 désigne les valeurs par défaut, to vaut la version courante par défaut
+func (h *ConfigHandler) Diff(c *gin.Context) {
+	section, ok := h.section(c)
+	if !ok {
+		return
+	}
+	from, err := strconv.ParseInt(c.DefaultQuery("from", "0"), 10, 64)
+	if err != nil || from < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from invalide"})
+		return
+	}
+	to := h.configService.Versions()[section]
+	if raw := c.Query("to"); raw != "" {
+		if to, err = strconv.ParseInt(raw, 10, 64); err != nil || to < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to invalide"})
+			return
+		}
+	}
+	diff, err := h.configService.Diff(c.Request.Context(), section, from, to)
+	if err != nil {
+		respondConfigError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"section": section, "from": from, "to": to, "changes": diff})
+}
+
+// Rollback rétablit le contenu d'une version antérieure (0 : valeurs par défaut) sous une nouvelle version
+func (h *ConfigHandler) Rollback(c *gin.Context) {
+	section, ok := h.section(c)
+	if !ok {
+		return
+	}
+	var input struct {
+		Version *int64 `json:"version" binding:"required"`
+		Comment string `json:"comment"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	v, err := h.configService.Rollback(c.Request.Context(), section, *input.Version, c.GetString("username"), input.Comment)
+	if err != nil {
+		respondConfigError(c, err)
+		return
+	}
+	h.logAction(c, "ROLLBACK_CONFIG", section, fmt.Sprintf("version %d -> %d", *input.Version, v.Version))
+	c.JSON(http.StatusOK, v)
+}
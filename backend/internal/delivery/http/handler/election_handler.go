@@ -6,15 +6,17 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/event"
 	"github.com/openvote/backend/internal/domain/repository"
 )
 
 type ElectionHandler struct {
 	electionRepo repository.ElectionRepository
+	events       event.Publisher
 }
 
-func NewElectionHandler(repo repository.ElectionRepository) *ElectionHandler {
-	return &ElectionHandler{electionRepo: repo}
+func NewElectionHandler(repo repository.ElectionRepository, events event.Publisher) *ElectionHandler {
+	return &ElectionHandler{electionRepo: repo, events: events}
 }
 
 func (h *ElectionHandler) List(c *gin.Context) {
@@ -120,10 +122,32 @@ func (h *ElectionHandler) UpdateStatus(c *gin.Context) {
 		return
 	}
 
+	election, err := h.electionRepo.GetByID(c.Request.Context(), id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if election == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Scrutin non trouvé"})
+		return
+	}
+
 	if err := h.electionRepo.UpdateStatus(c.Request.Context(), id, entity.ElectionStatus(input.Status)); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+
+	if h.events != nil && string(election.Status) != input.Status {
+		err := h.events.Publish(c.Request.Context(), event.ElectionStatusChanged{
+			ElectionID: id,
+			OldStatus:  string(election.Status),
+			NewStatus:  input.Status,
+			ChangedBy:  c.GetString("userID"),
+		})
+		if err != nil {
+			eventsLogger.ErrorContext(c.Request.Context(), "Publication impossible", "event_type", event.TypeElectionStatusChanged, "error", err)
+		}
+	}
 	c.JSON(http.StatusOK, gin.H{"message": "Statut mis à jour"})
 }
 
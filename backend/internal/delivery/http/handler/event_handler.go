@@ -0,0 +1,237 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+	"github.com/openvote/backend/internal/service"
+)
+
+// maxEventDescriptions limite le nombre de descriptions distinctes transmises au LLM
+const maxEventDescriptions = 5
+
+// EventHandler expose les événements consolidés (regroupement de signalements)
+type EventHandler struct {
+	eventRepo            repository.IncidentEventRepository
+	clusteringService    service.ClusteringService
+	reportService        service.ReportService
+	legalRepo            repository.LegalRepository
+	embeddingService     service.EmbeddingService
+	legalAnalysisService service.LegalAnalysisService
+	auditRepo            repository.AuditLogRepository
+}
+
+func NewEventHandler(eventRepo repository.IncidentEventRepository, clusteringService service.ClusteringService, reportService service.ReportService, legalRepo repository.LegalRepository, embeddingService service.EmbeddingService, legalAnalysisService service.LegalAnalysisService, auditRepo repository.AuditLogRepository) *EventHandler {
+	return &EventHandler{
+		eventRepo:            eventRepo,
+		clusteringService:    clusteringService,
+		reportService:        reportService,
+		legalRepo:            legalRepo,
+		embeddingService:     embeddingService,
+		legalAnalysisService: legalAnalysisService,
+		auditRepo:            auditRepo,
+	}
+}
+
+// audit persiste l'opération manuelle dans le journal d'audit
+func (h *EventHandler) audit(c *gin.Context, action, targetID, details string) {
+	entry := &entity.AuditLog{
+		AdminID:   c.GetString("userID"),
+		AdminName: c.GetString("username"),
+		Action:    action,
+		TargetID:  targetID,
+		Details:   details,
+	}
+	if err := h.auditRepo.Create(c.Request.Context(), entry); err != nil {
+		auditLogger.ErrorContext(c.Request.Context(), "Error persisting audit log", "action", entry.Action, "error", err)
+	}
+}
+
+// respondEventError traduit les erreurs du service de regroupement en codes HTTP
+func respondEventError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrEventNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrInvalidEventOperation):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// List retourne les événements (filtrables par statut active/merged)
+func (h *EventHandler) List(c *gin.Context) {
+	events, err := h.eventRepo.GetAll(c.Request.Context(), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"events": events, "total": len(events)})
+}
+
+// Get retourne un événement et ses signalements
+func (h *EventHandler) Get(c *gin.Context) {
+	event, err := h.eventRepo.GetByID(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if event == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Événement non trouvé"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"event": event})
+}
+
+// Merge fusionne d'autres événements dans l'événement cible
+func (h *EventHandler) Merge(c *gin.Context) {
+	id := c.Param("id")
+	var input struct {
+		SourceIDs []string `json:"source_ids" binding:"required,min=1"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	event, err := h.clusteringService.MergeEvents(c.Request.Context(), id, input.SourceIDs)
+	if err != nil {
+		respondEventError(c, err)
+		return
+	}
+
+	h.audit(c, "MERGE_EVENTS", id, "Sources: "+strings.Join(input.SourceIDs, ","))
+	c.JSON(http.StatusOK, gin.H{"message": "Événements fusionnés", "event": event})
+}
+
+// Split détache des signalements vers un nouvel événement
+func (h *EventHandler) Split(c *gin.Context) {
+	id := c.Param("id")
+	var input struct {
+		ReportIDs []string `json:"report_ids" binding:"required,min=1"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	created, err := h.clusteringService.SplitEvent(c.Request.Context(), id, input.ReportIDs)
+	if err != nil {
+		respondEventError(c, err)
+		return
+	}
+
+	h.audit(c, "SPLIT_EVENT", id, fmt.Sprintf("Nouvel événement %s (%d signalements)", created.ID, len(input.ReportIDs)))
+	c.JSON(http.StatusCreated, gin.H{"message": "Événement découpé", "event": created})
+}
+
+// Analyze effectue l'analyse juridique (RAG + LLM) de l'événement consolidé,
+// à partir des descriptions de ses signalements plutôt que d'un signalement isolé
+func (h *EventHandler) Analyze(c *gin.Context) {
+	ctx := c.Request.Context()
+	event, err := h.eventRepo.GetByID(ctx, c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if event == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Événement non trouvé"})
+		return
+	}
+
+	// 1. Descriptions distinctes des signalements non rejetés
+	var descriptions []string
+	seen := make(map[string]bool)
+	for _, reportID := range event.ReportIDs {
+		report, err := h.reportService.GetReportByID(ctx, reportID)
+		if err != nil || report == nil || report.Status == entity.StatusRejected {
+			continue
+		}
+		desc := strings.TrimSpace(report.Description)
+		if desc == "" || seen[desc] {
+			continue
+		}
+		seen[desc] = true
+		descriptions = append(descriptions, desc)
+		if len(descriptions) == maxEventDescriptions {
+			break
+		}
+	}
+	description := strings.Join(descriptions, "\n- ")
+
+	// 2. RAG : Trouver les articles pertinents
+	queryEmbedding, err := h.embeddingService.GenerateEmbedding(ctx, event.IncidentType+": "+description)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Erreur embedding: " + err.Error()})
+		return
+	}
+	articles, scores, err := h.legalRepo.SemanticSearch(ctx, queryEmbedding, 5)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	var articleMatches []service.ArticleMatch
+	for i, art := range articles {
+		articleMatches = append(articleMatches, service.ArticleMatch{
+			ArticleNumber: art.ArticleNumber,
+			Title:         art.Title,
+			Content:       art.Content,
+			Similarity:    scores[i],
+		})
+	}
+
+	// 3. LLM : Analyse juridique de l'événement
+	llmAnalysis, err := h.legalAnalysisService.AnalyzeIncident(ctx, service.IncidentContext{
+		IncidentType: event.IncidentType,
+		Description:  description,
+		ReportCount:  event.ReportCount,
+		Articles:     articleMatches,
+	})
+	if err != nil {
+		llmLogger.ErrorContext(ctx, "Analyse de l'événement impossible", "event_id", event.ID, "error", err)
+		c.JSON(http.StatusOK, gin.H{
+			"event_id":  event.ID,
+			"matches":   len(articles),
+			"llm_error": err.Error(),
+			"articles":  articleMatches,
+		})
+		return
+	}
+
+	// 4. Sauvegarder l'analyse
+	dbAnalysis := &entity.LegalAnalysis{
+		EventID:        event.ID,
+		Summary:        llmAnalysis.Summary,
+		Recommendation: llmAnalysis.Recommendation,
+		SeverityLevel:  llmAnalysis.SeverityLevel,
+		RawResponse:    llmAnalysis.RawResponse,
+		LLMModel:       "mistral",
+	}
+	if err := h.legalRepo.SaveEventAnalysis(ctx, dbAnalysis); err != nil {
+		llmLogger.ErrorContext(ctx, "Enregistrement de l'analyse impossible", "event_id", event.ID, "error", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"event_id":         event.ID,
+		"incident":         event.IncidentType,
+		"report_count":     event.ReportCount,
+		"analysis":         llmAnalysis,
+		"matched_articles": len(articles),
+	})
+}
+
+// GetAnalysis retourne l'analyse juridique existante d'un événement
+func (h *EventHandler) GetAnalysis(c *gin.Context) {
+	analysis, err := h.legalRepo.GetAnalysisByEvent(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Aucune analyse trouvée"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"analysis": analysis})
+}
@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/openvote/backend/internal/service"
+)
+
+type HealthHandler struct {
+	healthService service.HealthService
+}
+
+func NewHealthHandler(healthService service.HealthService) *HealthHandler {
+	return &HealthHandler{healthService: healthService}
+}
+
+// Livez répond tant que le processus sert des requêtes, sans sonder les dépendances :
+// un redémarrage ne réparerait pas une base en panne
+func (h *HealthHandler) Livez(c *gin.Context) {
+	c.JSON(http.StatusOK, gin.H{
+		"status":         "alive",
+		"uptime_seconds": int64(h.healthService.Uptime().Seconds()),
+	})
+}
+
+// Readyz sonde chaque dépendance : 503 si un composant critique est en panne, 200 sinon
+// (mode dégradé compris), avec le statut et la latence de chaque composant. Route publique :
+// ni erreurs ni détails
+func (h *HealthHandler) Readyz(c *gin.Context) {
+	readiness := h.healthService.Readiness(c.Request.Context())
+	c.JSON(readinessStatus(readiness), readiness.Summary())
+}
+
+// ReadyzDetails ajoute les erreurs et détails de chaque composant (pool de connexions, modèles) ;
+// servi uniquement sur l'écoute interne (METRICS_ADDR)
+func (h *HealthHandler) ReadyzDetails(c *gin.Context) {
+	readiness := h.healthService.Readiness(c.Request.Context())
+	c.JSON(readinessStatus(readiness), readiness)
+}
+
+func readinessStatus(readiness service.Readiness) int {
+	if !readiness.Ready() {
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusOK
+}
+
+// Health conserve le format historique ({"status": "healthy"}) pour la page de statut,
+// en reflétant désormais l'état réel des dépendances
+func (h *HealthHandler) Health(c *gin.Context) {
+	readiness := h.healthService.Readiness(c.Request.Context())
+	status, label := http.StatusOK, "healthy"
+	switch readiness.Status {
+	case service.ReadinessDegraded:
+		label = "degraded"
+	case service.ReadinessNotReady:
+		status, label = http.StatusServiceUnavailable, "unhealthy"
+	}
+	components := make(gin.H, len(readiness.Components))
+	for _, comp := range readiness.Components {
+		components[comp.Name] = comp.Status
+	}
+	c.JSON(status, gin.H{"status": label, "components": components})
+}
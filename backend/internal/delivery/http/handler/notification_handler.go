@@ -0,0 +1,157 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+	"github.com/openvote/backend/internal/service"
+)
+
+// NotificationHandler expose la boîte de réception, les préférences et le journal des envois
+type NotificationHandler struct {
+	notificationService service.NotificationService
+	auditRepo           repository.AuditLogRepository
+}
+
+func NewNotificationHandler(notificationService service.NotificationService, auditRepo repository.AuditLogRepository) *NotificationHandler {
+	return &NotificationHandler{notificationService: notificationService, auditRepo: auditRepo}
+}
+
+// respondNotificationError traduit les erreurs du service en statut HTTP
+func respondNotificationError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrInvalidPreferences):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, service.ErrNotificationDeliveryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Envoi introuvable"})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+func parseNotificationLimit(c *gin.Context) (int, bool) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 200 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit doit être compris entre 1 et 200"})
+		return 0, false
+	}
+	return limit, true
+}
+
+// ========================================
+// Boîte de réception et préférences (utilisateur connecté)
+// ========================================
+
+// ListInbox retourne les notifications de l'utilisateur (?unread=true&limit=) et le nombre de non lues
+func (h *NotificationHandler) ListInbox(c *gin.Context) {
+	limit, ok := parseNotificationLimit(c)
+	if !ok {
+		return
+	}
+	items, unread, err := h.notificationService.ListInbox(c.Request.Context(), c.GetString("userID"), c.Query("unread") == "true", limit)
+	if err != nil {
+		respondNotificationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"notifications": items, "total": len(items), "unread": unread})
+}
+
+// MarkRead marque une notification comme lue
+func (h *NotificationHandler) MarkRead(c *gin.Context) {
+	count, err := h.notificationService.MarkRead(c.Request.Context(), c.GetString("userID"), c.Param("id"))
+	if err != nil {
+		respondNotificationError(c, err)
+		return
+	}
+	if count == 0 {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Notification introuvable ou déjà lue"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Notification lue"})
+}
+
+// MarkAllRead vide la liste des non lues
+func (h *NotificationHandler) MarkAllRead(c *gin.Context) {
+	count, err := h.notificationService.MarkRead(c.Request.Context(), c.GetString("userID"), "")
+	if err != nil {
+		respondNotificationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Notifications lues", "updated": count})
+}
+
+// GetPreferences retourne les préférences (valeurs par défaut si jamais enregistrées)
+// et les canaux disponibles sur la plateforme
+func (h *NotificationHandler) GetPreferences(c *gin.Context) {
+	prefs, err := h.notificationService.GetPreferences(c.Request.Context(), c.GetString("userID"))
+	if err != nil {
+		respondNotificationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"preferences": prefs, "available_channels": h.notificationService.Channels()})
+}
+
+// UpdatePreferences remplace les préférences de l'utilisateur
+func (h *NotificationHandler) UpdatePreferences(c *gin.Context) {
+	var prefs entity.NotificationPreferences
+	if err := c.ShouldBindJSON(&prefs); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	prefs.UserID = c.GetString("userID")
+
+	if err := h.notificationService.UpdatePreferences(c.Request.Context(), &prefs); err != nil {
+		respondNotificationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"preferences": prefs})
+}
+
+// ========================================
+// Journal des envois (administration)
+// ========================================
+
+// ListDeliveries retourne les derniers envois (?status=pending|sent|failed&limit=)
+func (h *NotificationHandler) ListDeliveries(c *gin.Context) {
+	limit, ok := parseNotificationLimit(c)
+	if !ok {
+		return
+	}
+	status := c.Query("status")
+	switch entity.NotificationDeliveryStatus(status) {
+	case "", entity.NotificationPending, entity.NotificationSent, entity.NotificationFailed:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status invalide: " + status})
+		return
+	}
+	deliveries, err := h.notificationService.ListDeliveries(c.Request.Context(), status, limit)
+	if err != nil {
+		respondNotificationError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries, "total": len(deliveries)})
+}
+
+// RetryDelivery remet un envoi en file (après correction d'une adresse ou d'une passerelle)
+func (h *NotificationHandler) RetryDelivery(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.notificationService.RetryDelivery(c.Request.Context(), id); err != nil {
+		respondNotificationError(c, err)
+		return
+	}
+
+	entry := &entity.AuditLog{
+		AdminID:   c.GetString("userID"),
+		AdminName: c.GetString("username"),
+		Action:    "RETRY_NOTIFICATION",
+		TargetID:  id,
+	}
+	if err := h.auditRepo.Create(c.Request.Context(), entry); err != nil {
+		auditLogger.ErrorContext(c.Request.Context(), "Error persisting audit log", "action", entry.Action, "error", err)
+	}
+	c.JSON(http.StatusAccepted, gin.H{"message": "Envoi replanifié"})
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+	"github.com/openvote/backend/internal/platform/queue"
+)
+
+// QueueHandler expose l'inspection et le rejeu des files mortes
+type QueueHandler struct {
+	deadLetters queue.DeadLetterManager
+	auditRepo   repository.AuditLogRepository
+	queues      []string
+}
+
+// NewQueueHandler accepte un gestionnaire nil (broker indisponible) : les routes répondent alors 503.
+// queues liste les files administrables (files de signalements et files des abonnés aux événements) ;
+// sans liste, seules les files de signalements le sont.
+func NewQueueHandler(deadLetters queue.DeadLetterManager, auditRepo repository.AuditLogRepository, queues ...string) *QueueHandler {
+	if len(queues) == 0 {
+		queues = queue.ReportQueues
+	}
+	return &QueueHandler{deadLetters: deadLetters, auditRepo: auditRepo, queues: queues}
+}
+
+// resolveQueue valide la file demandée (file ordinaire par défaut)
+func (h *QueueHandler) resolveQueue(c *gin.Context, name string) (string, bool) {
+	if h.deadLetters == nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": "File de messages indisponible"})
+		return "", false
+	}
+	if name == "" {
+		name = queue.QueueNewReports
+	}
+	if !slices.Contains(h.queues, name) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "File inconnue: " + name, "queues": h.queues})
+		return "", false
+	}
+	return name, true
+}
+
+// ListDeadLetters retourne les messages ayant épuisé leurs tentatives
+func (h *QueueHandler) ListDeadLetters(c *gin.Context) {
+	name, ok := h.resolveQueue(c, c.Query("queue"))
+	if !ok {
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 500 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit doit être compris entre 1 et 500"})
+		return
+	}
+
+	letters, err := h.deadLetters.ListDeadLetters(c.Request.Context(), name, limit)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"queue": name, "dead_letters": letters, "total": len(letters)})
+}
+
+// ReplayDeadLetters renvoie les messages sélectionnés (ou tous) vers leur file d'origine
+func (h *QueueHandler) ReplayDeadLetters(c *gin.Context) {
+	var input struct {
+		Queue      string   `json:"queue"`
+		MessageIDs []string `json:"message_ids"`
+	}
+	// Corps optionnel : sans message_ids, toute la file morte est rejouée
+	if err := c.ShouldBindJSON(&input); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	name, ok := h.resolveQueue(c, input.Queue)
+	if !ok {
+		return
+	}
+
+	replayed, err := h.deadLetters.ReplayDeadLetters(c.Request.Context(), name, input.MessageIDs)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error(), "replayed": replayed})
+		return
+	}
+
+	details := "Tous les messages"
+	if len(input.MessageIDs) > 0 {
+		details = "Messages: " + strings.Join(input.MessageIDs, ",")
+	}
+	entry := &entity.AuditLog{
+		AdminID:   c.GetString("userID"),
+		AdminName: c.GetString("username"),
+		Action:    "REPLAY_DEAD_LETTERS",
+		TargetID:  name,
+		Details:   details,
+	}
+	if err := h.auditRepo.Create(c.Request.Context(), entry); err != nil {
+		auditLogger.ErrorContext(c.Request.Context(), "Error persisting audit log", "action", entry.Action, "error", err)
+	}
+
+	c.JSON(http.StatusOK, gin.H{"message": "Messages rejoués", "queue": name, "replayed": replayed})
+}
@@ -51,6 +51,9 @@ func (h *ReportHandler) Create(c *gin.Context) {
 		Status:       entity.StatusPending,
 		ProofURL:     req.ProofURL,
 		CreatedAt:    time.Now(),
+		// Valeurs brutes, converties en empreintes par le service avant persistance
+		SourceFingerprint: c.ClientIP(),
+		DeviceFingerprint: c.GetHeader("X-Device-ID"),
 	}
 
 	if err := h.reportService.CreateReport(c.Request.Context(), &report); err != nil {
@@ -136,7 +139,7 @@ func (h *ReportHandler) UpdateStatus(c *gin.Context) {
 		return
 	}
 
-	if err := h.reportService.UpdateReportStatus(c.Request.Context(), id, entity.ReportStatus(req.Status)); err != nil {
+	if err := h.reportService.UpdateReportStatus(c.Request.Context(), id, entity.ReportStatus(req.Status), c.GetString("userID")); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to update report: " + err.Error()})
 		return
 	}
@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+	"github.com/openvote/backend/internal/service"
+)
+
+// ReviewHandler expose les files de revue humaine issues de la triangulation
+type ReviewHandler struct {
+	reviewService service.ReviewService
+	auditRepo     repository.AuditLogRepository
+}
+
+func NewReviewHandler(reviewService service.ReviewService, auditRepo repository.AuditLogRepository) *ReviewHandler {
+	return &ReviewHandler{reviewService: reviewService, auditRepo: auditRepo}
+}
+
+// audit persiste la décision du modérateur dans le journal d'audit
+func (h *ReviewHandler) audit(c *gin.Context, action, targetID, details string) {
+	entry := &entity.AuditLog{
+		AdminID:   c.GetString("userID"),
+		AdminName: c.GetString("username"),
+		Action:    action,
+		TargetID:  targetID,
+		Details:   details,
+	}
+	if err := h.auditRepo.Create(c.Request.Context(), entry); err != nil {
+		auditLogger.ErrorContext(c.Request.Context(), "Error persisting audit log", "action", entry.Action, "error", err)
+	}
+}
+
+// ========================================
+// Clusters Sybil
+// ========================================
+
+// ListSuspiciousClusters retourne les clusters suspects (filtrables par statut)
+func (h *ReviewHandler) ListSuspiciousClusters(c *gin.Context) {
+	clusters, err := h.reviewService.ListClusters(c.Request.Context(), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"clusters": clusters, "total": len(clusters)})
+}
+
+// ReviewSuspiciousCluster enregistre la décision humaine : collusion confirmée (signalements
+// rejetés) ou faux positif (signalements rejoués par la triangulation)
+func (h *ReviewHandler) ReviewSuspiciousCluster(c *gin.Context) {
+	id := c.Param("id")
+	var input struct {
+		Status string `json:"status" binding:"required,oneof=confirmed dismissed"`
+		Note   string `json:"note"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := entity.ClusterReviewStatus(input.Status)
+	reportIDs, err := h.reviewService.ReviewCluster(c.Request.Context(), id, status, c.GetString("userID"), input.Note)
+	// Rejets déjà effectués avant une éventuelle erreur : audités dans tous les cas
+	if status == entity.ClusterConfirmed {
+		for _, reportID := range reportIDs {
+			h.audit(c, "REJECT_REPORT", reportID, "Collusion confirmée, cluster "+id)
+		}
+	}
+	if errors.Is(err, service.ErrClusterNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Cluster non trouvé"})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if reportIDs == nil {
+		reportIDs = []string{}
+	}
+	h.audit(c, "REVIEW_SYBIL_CLUSTER", id, fmt.Sprintf("Décision: %s (%d signalement(s))", input.Status, len(reportIDs)))
+	c.JSON(http.StatusOK, gin.H{"message": "Cluster revu", "id": id, "status": input.Status, "report_ids": reportIDs})
+}
+
+// ========================================
+// Conflits de types d'incidents
+// ========================================
+
+// ListConflicts retourne les conflits (filtrables par statut open/resolved)
+func (h *ReviewHandler) ListConflicts(c *gin.Context) {
+	conflicts, err := h.reviewService.ListConflicts(c.Request.Context(), c.Query("status"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"conflicts": conflicts, "total": len(conflicts)})
+}
+
+// GetConflict retourne un conflit et les signalements liés
+func (h *ReviewHandler) GetConflict(c *gin.Context) {
+	conflict, err := h.reviewService.GetConflict(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	if conflict == nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Conflit non trouvé"})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"conflict": conflict})
+}
+
+// AssignConflict attribue un conflit à un modérateur (soi-même par défaut)
+func (h *ReviewHandler) AssignConflict(c *gin.Context) {
+	id := c.Param("id")
+	var input struct {
+		AssigneeID string `json:"assignee_id"`
+	}
+	// Corps optionnel : sans assignee_id, le conflit est attribué à l'appelant
+	if err := c.ShouldBindJSON(&input); err != nil && err != io.EOF {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if input.AssigneeID == "" {
+		input.AssigneeID = c.GetString("userID")
+	}
+
+	err := h.reviewService.AssignConflict(c.Request.Context(), id, input.AssigneeID)
+	switch {
+	case errors.Is(err, service.ErrInvalidAssignee):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, service.ErrAssigneeNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Utilisateur non trouvé"})
+		return
+	case errors.Is(err, service.ErrConflictNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Conflit non trouvé"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.audit(c, "ASSIGN_CONFLICT", id, "Assigné à: "+input.AssigneeID)
+	c.JSON(http.StatusOK, gin.H{"message": "Conflit assigné", "id": id, "assignee_id": input.AssigneeID})
+}
+
+// ResolveConflict clôt un conflit ouvert avec une note de résolution obligatoire. Les signalements
+// listés dans rejected_report_ids sont rejetés, les autres repassent par la triangulation.
+func (h *ReviewHandler) ResolveConflict(c *gin.Context) {
+	id := c.Param("id")
+	var input struct {
+		Note        string   `json:"resolution_note" binding:"required"`
+		RejectedIDs []string `json:"rejected_report_ids"`
+	}
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rejected, queued, err := h.reviewService.ResolveConflict(c.Request.Context(), id, c.GetString("userID"), input.Note, input.RejectedIDs)
+	for _, reportID := range rejected {
+		h.audit(c, "REJECT_REPORT", reportID, "Conflit résolu "+id)
+	}
+	switch {
+	case errors.Is(err, service.ErrInvalidResolution):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, service.ErrConflictNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Conflit non trouvé ou déjà résolu"})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if rejected == nil {
+		rejected = []string{}
+	}
+	if queued == nil {
+		queued = []string{}
+	}
+	h.audit(c, "RESOLVE_CONFLICT", id, input.Note)
+	c.JSON(http.StatusOK, gin.H{"message": "Conflit résolu", "id": id, "rejected_report_ids": rejected, "requeued_report_ids": queued})
+}
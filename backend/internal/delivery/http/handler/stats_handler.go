@@ -6,15 +6,17 @@ import (
 
 	"github.com/gin-gonic/gin"
 	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
 	"github.com/openvote/backend/internal/service"
 )
 
 type StatsHandler struct {
 	reportService service.ReportService
+	eventRepo     repository.IncidentEventRepository
 }
 
-func NewStatsHandler(rs service.ReportService) *StatsHandler {
-	return &StatsHandler{reportService: rs}
+func NewStatsHandler(rs service.ReportService, eventRepo repository.IncidentEventRepository) *StatsHandler {
+	return &StatsHandler{reportService: rs, eventRepo: eventRepo}
 }
 
 // GetStats retourne les statistiques agrégées des signalements
@@ -95,6 +97,21 @@ func (h *StatsHandler) GetStats(c *gin.Context) {
 
 	_ = entity.StatusPending // Pour garder l'import
 
+	// Vue consolidée : un incident signalé par plusieurs observateurs compte une seule fois
+	events, err := h.eventRepo.GetAll(ctx, string(entity.EventActive))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	eventCounts := make(map[string]int)
+	for _, e := range events {
+		eventCounts[e.IncidentType]++
+	}
+	topEvents := events // Déjà triés par sévérité puis nombre de signalements
+	if len(topEvents) > 10 {
+		topEvents = topEvents[:10]
+	}
+
 	c.JSON(http.StatusOK, gin.H{
 		"total":            len(allReports),
 		"last_24h":         last24h,
@@ -104,6 +121,11 @@ func (h *StatsHandler) GetStats(c *gin.Context) {
 		"top_observers":    topObservers,
 		"recent_reports":   recentReports,
 		"unique_observers": len(observerCounts),
-		"generated_at":     time.Now(),
+		"events": gin.H{
+			"total":           len(events),
+			"incident_counts": eventCounts,
+			"top":             topEvents,
+		},
+		"generated_at": time.Now(),
 	})
 }
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/event"
+	"github.com/openvote/backend/internal/domain/repository"
+	"github.com/openvote/backend/internal/service"
+	"golang.org/x/net/websocket"
+)
+
+const (
+	// streamHeartbeat maintient la connexion ouverte à travers les proxys
+	streamHeartbeat = 25 * time.Second
+	// streamRetry : délai de reconnexion suggéré aux clients EventSource (ms)
+	streamRetry = 3000
+
+	// Messages de contrôle (hors événements métier)
+	streamResetEvent = "stream.reset"
+	streamPingEvent  = "stream.ping"
+)
+
+// StreamHandler pousse les événements en temps réel (SSE, ou WebSocket en option)
+type StreamHandler struct {
+	streamService  service.StreamService
+	userRepo       repository.UserRepository
+	allowedOrigins []string
+}
+
+// NewStreamHandler : allowedOrigins restreint les connexions WebSocket des navigateurs (mêmes origines que CORS)
+func NewStreamHandler(streamService service.StreamService, userRepo repository.UserRepository, allowedOrigins []string) *StreamHandler {
+	return &StreamHandler{streamService: streamService, userRepo: userRepo, allowedOrigins: allowedOrigins}
+}
+
+// resolveRegionScope : le super_admin voit tout le territoire, les autres rôles leur seule région
+func resolveRegionScope(c *gin.Context, userRepo repository.UserRepository) (service.StreamScope, bool) {
+	if entity.UserRole(c.GetString("role")) == entity.RoleSuperAdmin {
+		return service.StreamScope{All: true}, true
+	}
+	user, err := userRepo.GetByID(c.Request.Context(), c.GetString("userID"))
+	if err != nil || user == nil {
+		c.JSON(http.StatusUnauthorized, gin.H{"error": "Utilisateur introuvable"})
+		return service.StreamScope{}, false
+	}
+	if user.RegionID == "" {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Aucune région rattachée à ce compte"})
+		return service.StreamScope{}, false
+	}
+	return service.StreamScope{RegionID: user.RegionID}, true
+}
+
+// lastEventID : en-tête envoyé par EventSource à la reconnexion, ou paramètre pour la première connexion
+func lastEventID(c *gin.Context) string {
+	if id := c.GetHeader("Last-Event-ID"); id != "" {
+		return id
+	}
+	return c.Query("last_event_id")
+}
+
+// Stream diffuse les événements au format Server-Sent Events
+func (h *StreamHandler) Stream(c *gin.Context) {
+	scope, ok := resolveRegionScope(c, h.userRepo)
+	if !ok {
+		return
+	}
+	sub := h.streamService.Subscribe(scope, lastEventID(c))
+	defer h.streamService.Unsubscribe(sub)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no") // Désactive la mise en tampon de Nginx
+	c.Status(http.StatusOK)
+
+	w := c.Writer
+	fmt.Fprintf(w, "retry: %d\n\n", streamRetry)
+	if !sub.Resumed {
+		fmt.Fprintf(w, "event: %s\ndata: {\"reason\":\"history_unavailable\"}\n\n", streamResetEvent)
+	}
+	for _, env := range sub.Backlog {
+		writeSSE(w, env)
+	}
+	w.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-c.Request.Context().Done():
+			return
+		case env, open := <-sub.Events:
+			if !open {
+				return
+			}
+			writeSSE(w, env)
+			w.Flush()
+		case <-heartbeat.C:
+			fmt.Fprint(w, ": ping\n\n")
+			w.Flush()
+		}
+	}
+}
+
+func writeSSE(w gin.ResponseWriter, env event.Envelope) {
+	data, err := json.Marshal(env)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", env.ID, env.Type, data)
+}
+
+// WebSocket diffuse les mêmes événements sur une connexion WebSocket : un message JSON
+// (enveloppe) par événement. La reprise passe par ?last_event_id=.
+func (h *StreamHandler) WebSocket(c *gin.Context) {
+	scope, ok := resolveRegionScope(c, h.userRepo)
+	if !ok {
+		return
+	}
+	resumeFrom := lastEventID(c)
+
+	server := websocket.Server{
+		Handshake: func(cfg *websocket.Config, r *http.Request) error {
+			// Clients hors navigateur : pas d'en-tête Origin
+			if origin := r.Header.Get("Origin"); origin != "" && !slices.Contains(h.allowedOrigins, origin) {
+				return fmt.Errorf("origin %s not allowed", origin)
+			}
+			return nil
+		},
+		Handler: func(ws *websocket.Conn) {
+			defer ws.Close()
+			sub := h.streamService.Subscribe(scope, resumeFrom)
+			defer h.streamService.Unsubscribe(sub)
+
+			// Le client n'envoie rien : la lecture ne sert qu'à détecter la fermeture
+			closed := make(chan struct{})
+			go func() {
+				defer close(closed)
+				var discard string
+				for websocket.Message.Receive(ws, &discard) == nil {
+				}
+			}()
+
+			if !sub.Resumed {
+				if websocket.JSON.Send(ws, gin.H{"type": streamResetEvent, "reason": "history_unavailable"}) != nil {
+					return
+				}
+			}
+			for _, env := range sub.Backlog {
+				if websocket.JSON.Send(ws, env) != nil {
+					return
+				}
+			}
+
+			heartbeat := time.NewTicker(streamHeartbeat)
+			defer heartbeat.Stop()
+			for {
+				select {
+				case <-closed:
+					return
+				case env, open := <-sub.Events:
+					if !open || websocket.JSON.Send(ws, env) != nil {
+						return
+					}
+				case <-heartbeat.C:
+					if websocket.JSON.Send(ws, gin.H{"type": streamPingEvent}) != nil {
+						return
+					}
+				}
+			}
+		},
+	}
+	server.ServeHTTP(c.Writer, c.Request)
+}
@@ -0,0 +1,189 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+	"github.com/openvote/backend/internal/service"
+)
+
+// WebhookHandler expose la gestion des abonnements webhook et de leurs livraisons
+type WebhookHandler struct {
+	webhookService service.WebhookService
+	auditRepo      repository.AuditLogRepository
+}
+
+func NewWebhookHandler(webhookService service.WebhookService, auditRepo repository.AuditLogRepository) *WebhookHandler {
+	return &WebhookHandler{webhookService: webhookService, auditRepo: auditRepo}
+}
+
+type webhookInput struct {
+	Name        string   `json:"name" binding:"required"`
+	URL         string   `json:"url" binding:"required"`
+	EventTypes  []string `json:"event_types" binding:"required"`
+	RegionIDs   []string `json:"region_ids"`
+	MinSeverity int      `json:"min_severity"`
+	Active      *bool    `json:"active"`
+}
+
+func (in webhookInput) apply(sub *entity.WebhookSubscription) {
+	sub.Name = in.Name
+	sub.URL = in.URL
+	sub.EventTypes = in.EventTypes
+	sub.RegionIDs = in.RegionIDs
+	sub.MinSeverity = in.MinSeverity
+	if in.Active != nil {
+		sub.Active = *in.Active
+	}
+}
+
+func (h *WebhookHandler) logAction(ctx context.Context, c *gin.Context, action, targetID, details string) {
+	entry := &entity.AuditLog{
+		AdminID:   c.GetString("userID"),
+		AdminName: c.GetString("username"),
+		Action:    action,
+		TargetID:  targetID,
+		Details:   details,
+	}
+	if err := h.auditRepo.Create(ctx, entry); err != nil {
+		auditLogger.ErrorContext(ctx, "Error persisting audit log", "action", entry.Action, "error", err)
+	}
+}
+
+// respondWebhookError traduit les erreurs du service en statut HTTP
+func respondWebhookError(c *gin.Context, err error) {
+	switch {
+	case errors.Is(err, service.ErrWebhookNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Webhook introuvable"})
+	case errors.Is(err, service.ErrDeliveryNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": "Livraison introuvable"})
+	case errors.Is(err, service.ErrInvalidWebhook):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	default:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
+
+// List retourne les abonnements webhook
+func (h *WebhookHandler) List(c *gin.Context) {
+	subs, err := h.webhookService.ListSubscriptions(c.Request.Context())
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"webhooks": subs, "total": len(subs)})
+}
+
+// Create enregistre un abonnement ; le secret de signature n'est retourné qu'ici
+func (h *WebhookHandler) Create(c *gin.Context) {
+	var input webhookInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sub := &entity.WebhookSubscription{Active: true, CreatedBy: c.GetString("userID")}
+	input.apply(sub)
+
+	if err := h.webhookService.CreateSubscription(c.Request.Context(), sub); err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+	h.logAction(c.Request.Context(), c, "CREATE_WEBHOOK", sub.ID, sub.Name+" -> "+sub.URL)
+
+	c.JSON(http.StatusCreated, gin.H{
+		"webhook": sub,
+		"secret":  sub.Secret,
+		"message": "Conservez ce secret : il ne sera plus affiché",
+	})
+}
+
+// Get retourne un abonnement
+func (h *WebhookHandler) Get(c *gin.Context) {
+	sub, err := h.webhookService.GetSubscription(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, sub)
+}
+
+// Update remplace les filtres et la destination d'un abonnement
+func (h *WebhookHandler) Update(c *gin.Context) {
+	var input webhookInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	sub, err := h.webhookService.GetSubscription(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+	input.apply(sub)
+
+	if err := h.webhookService.UpdateSubscription(c.Request.Context(), sub); err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+	h.logAction(c.Request.Context(), c, "UPDATE_WEBHOOK", sub.ID, "Événements: "+strings.Join(sub.EventTypes, ","))
+
+	c.JSON(http.StatusOK, sub)
+}
+
+// Delete supprime un abonnement et son historique de livraisons
+func (h *WebhookHandler) Delete(c *gin.Context) {
+	id := c.Param("id")
+	if err := h.webhookService.DeleteSubscription(c.Request.Context(), id); err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+	h.logAction(c.Request.Context(), c, "DELETE_WEBHOOK", id, "")
+
+	c.JSON(http.StatusOK, gin.H{"message": "Webhook supprimé"})
+}
+
+// ListDeliveries retourne le journal des livraisons d'un abonnement (?status=failed)
+func (h *WebhookHandler) ListDeliveries(c *gin.Context) {
+	id := c.Param("id")
+	if _, err := h.webhookService.GetSubscription(c.Request.Context(), id); err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "50"))
+	if err != nil || limit < 1 || limit > 500 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit doit être compris entre 1 et 500"})
+		return
+	}
+	status := c.Query("status")
+	switch entity.WebhookDeliveryStatus(status) {
+	case "", entity.DeliveryPending, entity.DeliveryDelivered, entity.DeliveryFailed:
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "status invalide: " + status})
+		return
+	}
+
+	deliveries, err := h.webhookService.ListDeliveries(c.Request.Context(), id, status, limit)
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"deliveries": deliveries, "total": len(deliveries)})
+}
+
+// ReplayDelivery replanifie immédiatement une livraison (échouée ou non)
+func (h *WebhookHandler) ReplayDelivery(c *gin.Context) {
+	delivery, err := h.webhookService.ReplayDelivery(c.Request.Context(), c.Param("id"))
+	if err != nil {
+		respondWebhookError(c, err)
+		return
+	}
+	h.logAction(c.Request.Context(), c, "REPLAY_WEBHOOK_DELIVERY", delivery.ID, delivery.EventType+" "+delivery.EventID)
+
+	c.JSON(http.StatusOK, gin.H{"message": "Livraison replanifiée", "delivery": delivery})
+}
@@ -52,3 +52,16 @@ func AuthMiddleware(authService service.AuthService, userRepo ...repository.User
 		c.Next()
 	}
 }
+
+// TokenFromQuery accepte le jeton dans ?access_token= pour les clients qui ne peuvent pas
+// fixer d'en-tête (EventSource et WebSocket des navigateurs). À placer avant AuthMiddleware.
+func TokenFromQuery() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.GetHeader("Authorization") == "" {
+			if token := c.Query("access_token"); token != "" {
+				c.Request.Header.Set("Authorization", "Bearer "+token)
+			}
+		}
+		c.Next()
+	}
+}
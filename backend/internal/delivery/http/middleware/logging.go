@@ -0,0 +1,72 @@
+package middleware
+
+import (
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/google/uuid"
+	"github.com/openvote/backend/internal/platform/logging"
+)
+
+var httpLogger = logging.For("http")
+
+// RequestID attribue à chaque requête un identifiant de corrélation : celui du proxy ou du
+// client (X-Request-ID) s'il est valide, un UUID sinon. Il est renvoyé dans la réponse, repris
+// par chaque entrée de journal écrite avec c.Request.Context() et transmis aux messages de file.
+func RequestID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id := c.GetHeader(logging.HeaderRequestID)
+		if !logging.ValidRequestID(id) {
+			id = uuid.New().String()
+		}
+		c.Header(logging.HeaderRequestID, id)
+		c.Set("requestID", id)
+		c.Request = c.Request.WithContext(logging.WithRequestID(c.Request.Context(), id))
+		c.Next()
+	}
+}
+
+// AccessLog remplace le journal de Gin : route, statut et durée, sans la chaîne de requête
+// (?access_token= des flux), l'adresse du client ni l'identité de l'utilisateur
+func AccessLog() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		c.Next()
+
+		route := c.FullPath()
+		level := slog.LevelInfo
+		switch {
+		case untracedRoutes[route]:
+			level = slog.LevelDebug // Sondes et collecte, interrogées en boucle
+		case c.Writer.Status() >= http.StatusInternalServerError:
+			level = slog.LevelError
+		}
+		attrs := []slog.Attr{
+			slog.String("method", c.Request.Method),
+			slog.String("route", route),
+			slog.Int("status", c.Writer.Status()),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.Int("bytes", c.Writer.Size()),
+		}
+		if route == "" {
+			// Chemin sans route (404) : sans paramètres, pour repérer les sondages
+			attrs[1] = slog.String("path", c.Request.URL.Path)
+		}
+		if len(c.Errors) > 0 {
+			attrs = append(attrs, slog.String("error", c.Errors.String()))
+		}
+		httpLogger.LogAttrs(c.Request.Context(), level, "http request", attrs...)
+	}
+}
+
+// Recovery journalise la panique (route et identifiant de requête, sans le dump des en-têtes
+// de gin.Recovery) et répond 500
+func Recovery() gin.HandlerFunc {
+	return gin.CustomRecoveryWithWriter(nil, func(c *gin.Context, recovered any) {
+		httpLogger.ErrorContext(c.Request.Context(), "panic while handling request",
+			"route", c.FullPath(), "panic", recovered)
+		c.AbortWithStatusJSON(http.StatusInternalServerError, gin.H{"error": "Internal server error"})
+	})
+}
@@ -66,26 +66,36 @@ func (rl *rateLimiter) getVisitor(ip string) *visitor {
 	return v
 }
 
+// SetRate modifie le quota à chaud (configuration système) ; il s'applique à la prochaine fenêtre de chaque IP
+func (rl *rateLimiter) SetRate(maxRequests int) {
+	rl.mu.Lock()
+	defer rl.mu.Unlock()
+	rl.rate = maxRequests
+}
+
 // RateLimitMiddleware crée un middleware Gin de rate limiting par IP
 func RateLimitMiddleware(maxRequests int, window time.Duration) gin.HandlerFunc {
-	limiter := NewRateLimiter(maxRequests, window)
+	return NewRateLimiter(maxRequests, window).Middleware()
+}
 
+// Middleware retourne le middleware Gin associé au limiteur
+func (rl *rateLimiter) Middleware() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		ip := c.ClientIP()
-		v := limiter.getVisitor(ip)
+		v := rl.getVisitor(ip)
 
-		limiter.mu.Lock()
+		rl.mu.Lock()
 		if v.tokens <= 0 {
-			limiter.mu.Unlock()
+			rl.mu.Unlock()
 			c.JSON(http.StatusTooManyRequests, gin.H{
 				"error":       "too many requests",
-				"retry_after": limiter.window.Seconds(),
+				"retry_after": rl.window.Seconds(),
 			})
 			c.Abort()
 			return
 		}
 		v.tokens--
-		limiter.mu.Unlock()
+		rl.mu.Unlock()
 
 		c.Next()
 	}
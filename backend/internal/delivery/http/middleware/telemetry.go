@@ -0,0 +1,59 @@
+package middleware
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/openvote/backend/internal/platform/logging"
+	"github.com/openvote/backend/internal/platform/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// unmatchedRoute regroupe les requêtes sans route (404) : le chemin brut ferait exploser
+// le nombre de séries
+const unmatchedRoute = "unmatched"
+
+// untracedRoutes : sondes et collecte, interrogées en boucle, comptées mais sans span
+var untracedRoutes = map[string]bool{"/livez": true, "/readyz": true, "/health": true, "/metrics": true}
+
+// Telemetry mesure chaque requête (taux, erreurs, durée par route) et ouvre le span serveur,
+// rattaché à la trace de l'appelant (traceparent) : les services et dépôts appelés avec
+// c.Request.Context() y accrochent leurs spans.
+func Telemetry() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		start := time.Now()
+		route := c.FullPath()
+		if route == "" {
+			route = unmatchedRoute
+		}
+
+		var span trace.Span
+		if !untracedRoutes[route] {
+			ctx := telemetry.ExtractHTTP(c.Request.Context(), c.Request.Header)
+			ctx, span = telemetry.StartSpan(ctx, c.Request.Method+" "+route,
+				trace.WithSpanKind(trace.SpanKindServer),
+				trace.WithAttributes(
+					semconv.HTTPRequestMethodKey.String(c.Request.Method),
+					semconv.HTTPRoute(route),
+					attribute.String("request.id", logging.RequestID(ctx)),
+				))
+			c.Request = c.Request.WithContext(ctx)
+		}
+
+		c.Next()
+
+		status := c.Writer.Status()
+		telemetry.ObserveHTTP(c.Request.Method, route, status, time.Since(start))
+		if span != nil {
+			span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+			if status >= http.StatusInternalServerError {
+				span.SetStatus(codes.Error, http.StatusText(status))
+			}
+			span.End()
+		}
+	}
+}
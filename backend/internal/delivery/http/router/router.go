@@ -0,0 +1,239 @@
+// Package router assemble le routeur HTTP de l'API à partir des handlers déjà construits.
+//
+// Le câblage des dépendances (bases, files, services externes) reste dans cmd/api : les tests
+// construisent le même routeur avec des services factices, sans réseau.
+package router
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/gin-contrib/cors"
+	"github.com/gin-gonic/gin"
+	"github.com/openvote/backend/internal/delivery/http/handler"
+	"github.com/openvote/backend/internal/delivery/http/middleware"
+	"github.com/openvote/backend/internal/domain/repository"
+	"github.com/openvote/backend/internal/service"
+)
+
+// DefaultOrigins : origines CORS du développement local (CORS_ORIGINS non défini)
+var DefaultOrigins = []string{"http://localhost:8888", "http://localhost:5173", "http://localhost:3000"}
+
+// Deps regroupe ce dont les routes ont besoin ; tous les champs sont obligatoires
+type Deps struct {
+	// Authentification des requêtes (le dépôt résout le nom de l'utilisateur)
+	AuthService service.AuthService
+	UserRepo    repository.UserRepository
+
+	// Origines autorisées (CORS et WebSocket)
+	Origins []string
+	// Limitation de débit : globale, puis stricte sur /auth (anti brute-force)
+	RateLimit     gin.HandlerFunc
+	AuthRateLimit gin.HandlerFunc
+
+	Auth         *handler.AuthHandler
+	Report       *handler.ReportHandler
+	Admin        *handler.AdminHandler
+	Stats        *handler.StatsHandler
+	Region       *handler.RegionHandler
+	Election     *handler.ElectionHandler
+	IncidentType *handler.IncidentTypeHandler
+	Review       *handler.ReviewHandler
+	Event        *handler.EventHandler
+	Queue        *handler.QueueHandler
+	Webhook      *handler.WebhookHandler
+	Alert        *handler.AlertHandler
+	Notification *handler.NotificationHandler
+	Config       *handler.ConfigHandler
+	Stream       *handler.StreamHandler
+	Health       *handler.HealthHandler
+}
+
+// NewRouter déclare toutes les routes de l'API (/api/v1) et les sondes de santé. /metrics est
+// servi sur une écoute interne séparée (METRICS_ADDR), pas sur ce routeur public.
+func NewRouter(deps Deps) *gin.Engine {
+	// Journal structuré à la place du journal de Gin (chemins complets, jetons en paramètre)
+	r := gin.New()
+	r.Use(middleware.RequestID(), middleware.Recovery(), middleware.Telemetry(), middleware.AccessLog())
+
+	// Configuration CORS sécurisée (origines autorisées via env var)
+	r.Use(cors.New(cors.Config{
+		AllowOrigins:     deps.Origins,
+		AllowMethods:     []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+		AllowHeaders:     []string{"Origin", "Content-Type", "Accept", "Authorization", "traceparent", "tracestate", "X-Request-ID"},
+		ExposeHeaders:    []string{"Content-Length", "X-Request-ID"},
+		AllowCredentials: true,
+		MaxAge:           12 * time.Hour,
+	}))
+
+	// Middleware
+	authMiddleware := middleware.AuthMiddleware(deps.AuthService, deps.UserRepo)
+
+	// Routes API Versioning
+	api := r.Group("/api/v1")
+	api.Use(deps.RateLimit) // Rate limiting global
+	{
+		// Auth (rate limiting strict anti brute-force)
+		auth := api.Group("/auth")
+		auth.Use(deps.AuthRateLimit)
+		{
+			auth.POST("/register", deps.Auth.Register)
+			auth.POST("/login", deps.Auth.Login)
+			auth.POST("/enroll", deps.Auth.Enroll)
+		}
+
+		// Admin (authentifié + rôle admin requis)
+		admin := api.Group("/admin")
+		admin.Use(authMiddleware, middleware.AdminOnly())
+		{
+			admin.POST("/generate-token", deps.Admin.GenerateToken)
+			admin.GET("/users", deps.Admin.ListUsers)
+			admin.PATCH("/users/:id", deps.Admin.UpdateUser)
+			admin.DELETE("/users/:id", deps.Admin.DeleteUser)
+			admin.GET("/audit-logs", deps.Admin.GetAuditLogs)
+			admin.GET("/config", deps.Config.GetConfig)
+			admin.PATCH("/config", deps.Config.UpdateConfig)
+			admin.PATCH("/config/:section", deps.Config.UpdateSection)
+			admin.GET("/config/:section/history", deps.Config.History)
+			admin.GET("/config/:section/diff", deps.Config.Diff)
+			admin.POST("/config/:section/rollback", deps.Config.Rollback)
+			admin.GET("/kpis", deps.Admin.GetKPIs)
+			admin.GET("/legal", deps.Admin.GetLegalArticles)
+			admin.POST("/legal", deps.Admin.CreateLegalArticle)
+			admin.POST("/legal/batch", deps.Admin.BatchCreateLegalArticles)
+			admin.POST("/legal/extract-pdf", deps.Admin.ExtractTextFromPDF)
+			admin.DELETE("/legal/:id", deps.Admin.DeleteLegalArticle)
+			admin.GET("/legal-documents", deps.Admin.GetLegalDocuments)
+			admin.POST("/legal-documents", deps.Admin.CreateLegalDocument)
+
+			// Base de Connaissance Juridique (RAG)
+			admin.POST("/legal/search", deps.Admin.SemanticSearchArticles)
+			admin.POST("/legal/embeddings", deps.Admin.GenerateEmbeddings)
+			admin.POST("/reports/:id/qualify", deps.Admin.QualifyReport)
+			admin.POST("/reports/:id/analyze", deps.Admin.AnalyzeReport)
+			admin.GET("/reports/:id/legal-matches", deps.Admin.GetReportMatches)
+			admin.GET("/reports/:id/analysis", deps.Admin.GetReportAnalysis)
+			admin.GET("/review-queue", deps.Admin.GetReviewQueue)
+
+			// Régions & Départements (admin CRUD)
+			admin.POST("/regions", deps.Region.CreateRegion)
+			admin.PATCH("/regions/:id", deps.Region.UpdateRegion)
+			admin.DELETE("/regions/:id", deps.Region.DeleteRegion)
+			admin.POST("/departments", deps.Region.CreateDepartment)
+			admin.PATCH("/departments/:id", deps.Region.UpdateDepartment)
+			admin.DELETE("/departments/:id", deps.Region.DeleteDepartment)
+
+			// Elections (admin CRUD)
+			admin.GET("/elections", deps.Election.List)
+			admin.POST("/elections", deps.Election.Create)
+			admin.PATCH("/elections/:id", deps.Election.Update)
+			admin.PATCH("/elections/:id/status", deps.Election.UpdateStatus)
+			admin.DELETE("/elections/:id", deps.Election.Delete)
+
+			// Types d'incidents (admin CRUD)
+			admin.POST("/incident-types", deps.IncidentType.Create)
+			admin.DELETE("/incident-types/:id", deps.IncidentType.Delete)
+
+			// Revue humaine (clusters Sybil)
+			admin.GET("/suspicious-clusters", deps.Review.ListSuspiciousClusters)
+			admin.PATCH("/suspicious-clusters/:id", deps.Review.ReviewSuspiciousCluster)
+
+			// Conflits d'incidents
+			admin.GET("/conflicts", deps.Review.ListConflicts)
+			admin.GET("/conflicts/:id", deps.Review.GetConflict)
+			admin.PATCH("/conflicts/:id/assign", deps.Review.AssignConflict)
+			admin.POST("/conflicts/:id/resolve", deps.Review.ResolveConflict)
+
+			// Événements consolidés (regroupement de signalements)
+			admin.GET("/events", deps.Event.List)
+			admin.GET("/events/:id", deps.Event.Get)
+			admin.POST("/events/:id/merge", deps.Event.Merge)
+			admin.POST("/events/:id/split", deps.Event.Split)
+			admin.POST("/events/:id/analyze", deps.Event.Analyze)
+			admin.GET("/events/:id/analysis", deps.Event.GetAnalysis)
+
+			// Files mortes (messages en échec après toutes les tentatives)
+			admin.GET("/dead-letters", deps.Queue.ListDeadLetters)
+			admin.POST("/dead-letters/replay", deps.Queue.ReplayDeadLetters)
+
+			// Webhooks sortants (abonnements partenaires, journal et rejeu des livraisons)
+			admin.GET("/webhooks", deps.Webhook.List)
+			admin.POST("/webhooks", deps.Webhook.Create)
+			admin.GET("/webhooks/:id", deps.Webhook.Get)
+			admin.PUT("/webhooks/:id", deps.Webhook.Update)
+			admin.DELETE("/webhooks/:id", deps.Webhook.Delete)
+			admin.GET("/webhooks/:id/deliveries", deps.Webhook.ListDeliveries)
+			admin.POST("/webhooks/deliveries/:id/replay", deps.Webhook.ReplayDelivery)
+
+			// Règles d'alerte (conditions évaluées par le worker sur chaque nouveau signalement)
+			admin.GET("/alert-rules", deps.Alert.ListRules)
+			admin.POST("/alert-rules", deps.Alert.CreateRule)
+			admin.GET("/alert-rules/:id", deps.Alert.GetRule)
+			admin.PUT("/alert-rules/:id", deps.Alert.UpdateRule)
+			admin.DELETE("/alert-rules/:id", deps.Alert.DeleteRule)
+
+			// Journal des notifications (e-mail, SMS, boîte de réception) et relance manuelle
+			admin.GET("/notification-deliveries", deps.Notification.ListDeliveries)
+			admin.POST("/notification-deliveries/:id/retry", deps.Notification.RetryDelivery)
+		}
+
+		// Régions & Départements (lecture pour tous les utilisateurs authentifiés)
+		api.GET("/regions", authMiddleware, deps.Region.ListRegions)
+		api.GET("/departments", authMiddleware, deps.Region.ListDepartments)
+		api.GET("/incident-types", authMiddleware, deps.IncidentType.List)
+
+		// Rapports
+		reports := api.Group("/reports")
+		reports.Use(authMiddleware)
+		{
+			reports.POST("", deps.Report.Create)
+			reports.GET("", deps.Report.List)
+			reports.GET("/upload-url", deps.Report.GetUploadURL)
+			reports.GET("/:id", deps.Report.GetDetails)
+			reports.PATCH("/:id", deps.Report.UpdateStatus) // Vérification RBAC dans le handler
+		}
+
+		// Statistiques agrégées (admin)
+		api.GET("/stats", authMiddleware, deps.Stats.GetStats)
+
+		// Flux temps réel (SSE, WebSocket en option) ; jeton accepté en ?access_token= pour EventSource
+		api.GET("/stream", middleware.TokenFromQuery(), authMiddleware, middleware.CoordinatorAndAbove(), deps.Stream.Stream)
+		api.GET("/stream/ws", middleware.TokenFromQuery(), authMiddleware, middleware.CoordinatorAndAbove(), deps.Stream.WebSocket)
+
+		// Alertes (périmètre régional, acquittement et résolution par les coordinateurs)
+		alerts := api.Group("/alerts", authMiddleware, middleware.CoordinatorAndAbove())
+		{
+			alerts.GET("", deps.Alert.ListAlerts)
+			alerts.GET("/:id", deps.Alert.GetAlert)
+			alerts.POST("/:id/acknowledge", deps.Alert.Acknowledge)
+			alerts.POST("/:id/resolve", deps.Alert.Resolve)
+		}
+
+		// Notifications de l'utilisateur connecté (boîte de réception et préférences)
+		notifications := api.Group("/notifications", authMiddleware)
+		{
+			notifications.GET("", deps.Notification.ListInbox)
+			notifications.POST("/read-all", deps.Notification.MarkAllRead)
+			notifications.POST("/:id/read", deps.Notification.MarkRead)
+			notifications.GET("/preferences", deps.Notification.GetPreferences)
+			notifications.PUT("/preferences", deps.Notification.UpdatePreferences)
+		}
+	}
+
+	// Santé : vivacité du processus, disponibilité des dépendances, résumé pour la page de statut
+	r.GET("/livez", deps.Health.Livez)
+	r.GET("/readyz", deps.Health.Readyz)
+	r.GET("/health", deps.Health.Health)
+
+	return r
+}
+
+// NewInternalRouter sert l'écoute interne (METRICS_ADDR) : métriques Prometheus et vue détaillée
+// de la disponibilité, absentes du routeur public
+func NewInternalRouter(health *handler.HealthHandler, metrics http.Handler) *gin.Engine {
+	r := gin.New()
+	r.Use(middleware.Recovery())
+	r.GET("/metrics", gin.WrapH(metrics))
+	r.GET("/readyz", health.ReadyzDetails)
+	return r
+}
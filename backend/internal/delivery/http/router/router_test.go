@@ -0,0 +1,675 @@
+package router
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/openvote/backend/internal/delivery/http/handler"
+	"github.com/openvote/backend/internal/delivery/http/middleware"
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/platform/queue"
+	"github.com/openvote/backend/internal/platform/telemetry"
+	"github.com/openvote/backend/internal/repository/memory"
+	"github.com/openvote/backend/internal/service"
+	"github.com/openvote/backend/internal/worker"
+)
+
+// Région Littoral du référentiel (memory.Seed)
+const littoral = "a1000001-0000-0000-0000-000000000005"
+
+// Jetons statiques reconnus par fakeAuth, un par profil de test
+const (
+	tokenSuperAdmin  = "token-super-admin"
+	tokenRegionAdmin = "token-region-admin"
+	tokenCoord       = "token-coord"
+	tokenObserver    = "token-observer"
+	tokenNoRegion    = "token-coord-no-region"
+
+	testJWTSecret = "router-test-secret"
+)
+
+// fakeAuth délègue l'inscription et la connexion au vrai service ; la validation accepte en plus
+// les jetons statiques des profils de test
+type fakeAuth struct {
+	service.AuthService
+	tokens map[string]jwt.MapClaims
+}
+
+func (f *fakeAuth) ValidateToken(token string) (*jwt.MapClaims, error) {
+	if claims, ok := f.tokens[token]; ok {
+		return &claims, nil
+	}
+	return f.AuthService.ValidateToken(token)
+}
+
+type fakeStorage struct{}
+
+func (fakeStorage) GenerateUploadURL(ctx context.Context, fileName string) (string, error) {
+	return "https://storage.test/evidence/" + fileName + "?signature=test", nil
+}
+func (fakeStorage) Initialize(ctx context.Context) error                         { return nil }
+func (fakeStorage) Prepare(ctx context.Context, cfg service.StorageConfig) error { return nil }
+func (fakeStorage) Reconfigure(ctx context.Context, cfg service.StorageConfig) error {
+	return nil
+}
+func (fakeStorage) Ping(ctx context.Context) error { return nil }
+
+// fakeEmbedding renvoie le même vecteur pour tous les textes : toutes les similarités valent 1
+type fakeEmbedding struct{}
+
+func (fakeEmbedding) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	return []float32{0.6, 0.8, 0}, nil
+}
+func (fakeEmbedding) GetModel() string { return "fake-embedding" }
+
+type fakeLegalAnalysis struct{ err error }
+
+func (f *fakeLegalAnalysis) GetModel() string { return "fake-llm" }
+
+func (f *fakeLegalAnalysis) AnalyzeIncident(ctx context.Context, incident service.IncidentContext) (*service.LegalAnalysis, error) {
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &service.LegalAnalysis{
+		Summary:        "Analyse de " + incident.IncidentType,
+		Recommendation: "Transmettre à la commission électorale",
+		SeverityLevel:  4,
+		RawResponse:    "{}",
+	}, nil
+}
+
+type fakeDeadLetters struct {
+	letters  map[string][]queue.DeadLetter
+	replayed []string
+}
+
+func (f *fakeDeadLetters) ListDeadLetters(ctx context.Context, queueName string, limit int) ([]queue.DeadLetter, error) {
+	letters := f.letters[queueName]
+	if len(letters) > limit {
+		letters = letters[:limit]
+	}
+	return letters, nil
+}
+
+func (f *fakeDeadLetters) ReplayDeadLetters(ctx context.Context, queueName string, messageIDs []string) (int, error) {
+	f.replayed = append(f.replayed, messageIDs...)
+	if len(messageIDs) == 0 {
+		return len(f.letters[queueName]), nil
+	}
+	return len(messageIDs), nil
+}
+
+// testServer : le routeur complet sur des dépôts en mémoire, sans réseau
+type testServer struct {
+	t        *testing.T
+	engine   *gin.Engine
+	internal *gin.Engine // Écoute interne (METRICS_ADDR)
+	store    *memory.Store
+	users    map[string]*entity.User // par jeton
+	analysis *fakeLegalAnalysis
+	dead     *fakeDeadLetters
+}
+
+type serverOptions struct {
+	authPerMinute int
+	noDeadLetters bool
+	health        []service.HealthCheck // Sondes /readyz ; aucune par défaut
+}
+
+func newTestServer(t *testing.T) *testServer {
+	return newTestServerWith(t, serverOptions{})
+}
+
+func newTestServerWith(t *testing.T, opts serverOptions) *testServer {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	ctx := context.Background()
+
+	s := memory.NewStore()
+	if err := memory.Seed(s); err != nil {
+		t.Fatalf("seed: %v", err)
+	}
+	userRepo := memory.NewUserRepository(s)
+	reportRepo := memory.NewReportRepository(s)
+	auditLogRepo := memory.NewAuditLogRepository(s)
+	incidentTypeRepo := memory.NewIncidentTypeRepository(s)
+	electionRepo := memory.NewElectionRepository(s)
+	legalRepo := memory.NewLegalRepository(s)
+	eventRepo := memory.NewIncidentEventRepository(s)
+
+	ts := &testServer{
+		t: t, store: s, users: map[string]*entity.User{},
+		analysis: &fakeLegalAnalysis{},
+		dead: &fakeDeadLetters{letters: map[string][]queue.DeadLetter{
+			queue.QueueNewReports: {{MessageID: "msg-1", Queue: queue.QueueNewReports, RetryCount: 5, LastError: "timeout", Body: json.RawMessage(`{}`)}},
+		}},
+	}
+	auth := &fakeAuth{AuthService: service.NewAuthService(userRepo, testJWTSecret), tokens: map[string]jwt.MapClaims{}}
+	profiles := []struct {
+		token, username string
+		role            entity.UserRole
+		regionID        string
+	}{
+		{tokenSuperAdmin, "super", entity.RoleSuperAdmin, ""},
+		{tokenRegionAdmin, "region-admin", entity.RoleRegionAdmin, littoral},
+		{tokenCoord, "coord", entity.RoleLocalCoord, littoral},
+		{tokenObserver, "observer", entity.RoleObserver, littoral},
+		{tokenNoRegion, "coord-no-region", entity.RoleLocalCoord, ""},
+	}
+	for _, p := range profiles {
+		u := &entity.User{Username: p.username, Role: p.role, RegionID: p.regionID, PasswordHash: "-"}
+		if err := userRepo.Create(ctx, u); err != nil {
+			t.Fatalf("create user %s: %v", p.username, err)
+		}
+		ts.users[p.token] = u
+		auth.tokens[p.token] = jwt.MapClaims{"sub": u.ID, "role": string(u.Role)}
+	}
+
+	enrolmentService := service.NewEnrolmentService(userRepo, testJWTSecret)
+	publisher := service.NewEventPublisher(memory.NewOutboxRepository(s))
+	reportService := service.NewReportService(reportRepo, incidentTypeRepo, userRepo)
+	clusteringService := service.NewClusteringService(reportRepo, eventRepo)
+	alertService := service.NewAlertService(memory.NewAlertRepository(s), reportRepo, publisher)
+
+	var deadLetters queue.DeadLetterManager = ts.dead
+	if opts.noDeadLetters {
+		deadLetters = nil
+	}
+	authPerMinute := opts.authPerMinute
+	if authPerMinute == 0 {
+		authPerMinute = 1000
+	}
+
+	health := handler.NewHealthHandler(service.NewHealthService(time.Second, opts.health...))
+	ts.engine = NewRouter(Deps{
+		AuthService:   auth,
+		UserRepo:      userRepo,
+		Origins:       DefaultOrigins,
+		RateLimit:     middleware.RateLimitMiddleware(1000, time.Minute),
+		AuthRateLimit: middleware.RateLimitMiddleware(authPerMinute, time.Minute),
+		Auth:          handler.NewAuthHandler(auth, enrolmentService),
+		Report:        handler.NewReportHandler(reportService, fakeStorage{}),
+		Admin:         handler.NewAdminHandler(enrolmentService, userRepo, auditLogRepo, reportService, electionRepo, legalRepo, fakeEmbedding{}, ts.analysis, publisher),
+		Stats:         handler.NewStatsHandler(reportService, eventRepo),
+		Region:        handler.NewRegionHandler(memory.NewRegionRepository(s)),
+		Election:      handler.NewElectionHandler(electionRepo, publisher),
+		IncidentType:  handler.NewIncidentTypeHandler(incidentTypeRepo),
+		Review:        handler.NewReviewHandler(service.NewReviewService(memory.NewSuspiciousClusterRepository(s), memory.NewConflictRepository(s), userRepo, reportRepo, memory.NewOutboxRepository(s), reportService), auditLogRepo),
+		Event:         handler.NewEventHandler(eventRepo, clusteringService, reportService, legalRepo, fakeEmbedding{}, ts.analysis, auditLogRepo),
+		Queue:         handler.NewQueueHandler(deadLetters, auditLogRepo, append(slices.Clone(queue.ReportQueues), worker.EventQueueName(worker.SubscriberWebhooks))...),
+		Webhook:       handler.NewWebhookHandler(service.NewWebhookService(memory.NewWebhookRepository(s), nil, false), auditLogRepo),
+		Alert:         handler.NewAlertHandler(alertService, userRepo, auditLogRepo),
+		Notification:  handler.NewNotificationHandler(service.NewNotificationService(memory.NewNotificationRepository(s)), auditLogRepo),
+		Config:        handler.NewConfigHandler(service.NewConfigService(memory.NewConfigRepository(s), publisher), auditLogRepo),
+		Stream:        handler.NewStreamHandler(service.NewStreamService(), userRepo, DefaultOrigins),
+		Health:        health,
+	})
+	ts.internal = NewInternalRouter(health, telemetry.Handler())
+	return ts
+}
+
+// do exécute une requête ; body est encodé en JSON sauf s'il s'agit déjà d'une chaîne
+func (ts *testServer) do(method, path, token string, body interface{}) *httptest.ResponseRecorder {
+	ts.t.Helper()
+	var reader *bytes.Reader
+	switch b := body.(type) {
+	case nil:
+		reader = bytes.NewReader(nil)
+	case string:
+		reader = bytes.NewReader([]byte(b))
+	default:
+		raw, err := json.Marshal(b)
+		if err != nil {
+			ts.t.Fatalf("encode body: %v", err)
+		}
+		reader = bytes.NewReader(raw)
+	}
+	req := httptest.NewRequest(method, path, reader)
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	ts.engine.ServeHTTP(w, req)
+	return w
+}
+
+// expect vérifie le code HTTP et décode la réponse JSON
+func (ts *testServer) expect(w *httptest.ResponseRecorder, status int) map[string]interface{} {
+	ts.t.Helper()
+	if w.Code != status {
+		ts.t.Fatalf("status = %d, want %d; body = %s", w.Code, status, w.Body.String())
+	}
+	var out map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+		ts.t.Fatalf("decode %s: %v", w.Body.String(), err)
+	}
+	return out
+}
+
+// expectList décode une réponse dont le corps est un tableau JSON
+func (ts *testServer) expectList(w *httptest.ResponseRecorder, status int) []interface{} {
+	ts.t.Helper()
+	if w.Code != status {
+		ts.t.Fatalf("status = %d, want %d; body = %s", w.Code, status, w.Body.String())
+	}
+	var out []interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &out); err != nil {
+		ts.t.Fatalf("decode %s: %v", w.Body.String(), err)
+	}
+	return out
+}
+
+// hasKeys vérifie la forme d'une réponse
+func hasKeys(t *testing.T, body map[string]interface{}, keys ...string) {
+	t.Helper()
+	for _, k := range keys {
+		if _, ok := body[k]; !ok {
+			t.Errorf("response %v has no %q field", body, k)
+		}
+	}
+}
+
+// expectError vérifie le code HTTP et la présence du champ error
+func (ts *testServer) expectError(w *httptest.ResponseRecorder, status int) string {
+	ts.t.Helper()
+	body := ts.expect(w, status)
+	msg, ok := body["error"].(string)
+	if !ok || msg == "" {
+		ts.t.Fatalf("response %v has no error message", body)
+	}
+	return msg
+}
+
+type access int
+
+const (
+	public access = iota
+	authenticated
+	coordinator // local_coord et au-delà
+	adminOnly   // super_admin et region_admin
+)
+
+// routes recense chaque route de l'API avec son niveau d'accès ; TestRouteTableIsComplete
+// échoue dès qu'une route est ajoutée au routeur sans figurer ici
+var routes = []struct {
+	method, path string
+	access       access
+}{
+	{"GET", "/health", public},
+	{"GET", "/livez", public},
+	{"GET", "/readyz", public},
+	{"POST", "/api/v1/auth/register", public},
+	{"POST", "/api/v1/auth/login", public},
+	{"POST", "/api/v1/auth/enroll", public},
+
+	{"POST", "/api/v1/admin/generate-token", adminOnly},
+	{"GET", "/api/v1/admin/users", adminOnly},
+	{"PATCH", "/api/v1/admin/users/:id", adminOnly},
+	{"DELETE", "/api/v1/admin/users/:id", adminOnly},
+	{"GET", "/api/v1/admin/audit-logs", adminOnly},
+	{"GET", "/api/v1/admin/config", adminOnly},
+	{"PATCH", "/api/v1/admin/config", adminOnly},
+	{"PATCH", "/api/v1/admin/config/:section", adminOnly},
+	{"GET", "/api/v1/admin/config/:section/history", adminOnly},
+	{"GET", "/api/v1/admin/config/:section/diff", adminOnly},
+	{"POST", "/api/v1/admin/config/:section/rollback", adminOnly},
+	{"GET", "/api/v1/admin/kpis", adminOnly},
+	{"GET", "/api/v1/admin/legal", adminOnly},
+	{"POST", "/api/v1/admin/legal", adminOnly},
+	{"POST", "/api/v1/admin/legal/batch", adminOnly},
+	{"POST", "/api/v1/admin/legal/extract-pdf", adminOnly},
+	{"DELETE", "/api/v1/admin/legal/:id", adminOnly},
+	{"GET", "/api/v1/admin/legal-documents", adminOnly},
+	{"POST", "/api/v1/admin/legal-documents", adminOnly},
+	{"POST", "/api/v1/admin/legal/search", adminOnly},
+	{"POST", "/api/v1/admin/legal/embeddings", adminOnly},
+	{"POST", "/api/v1/admin/reports/:id/qualify", adminOnly},
+	{"POST", "/api/v1/admin/reports/:id/analyze", adminOnly},
+	{"GET", "/api/v1/admin/reports/:id/legal-matches", adminOnly},
+	{"GET", "/api/v1/admin/reports/:id/analysis", adminOnly},
+	{"GET", "/api/v1/admin/review-queue", adminOnly},
+	{"POST", "/api/v1/admin/regions", adminOnly},
+	{"PATCH", "/api/v1/admin/regions/:id", adminOnly},
+	{"DELETE", "/api/v1/admin/regions/:id", adminOnly},
+	{"POST", "/api/v1/admin/departments", adminOnly},
+	{"PATCH", "/api/v1/admin/departments/:id", adminOnly},
+	{"DELETE", "/api/v1/admin/departments/:id", adminOnly},
+	{"GET", "/api/v1/admin/elections", adminOnly},
+	{"POST", "/api/v1/admin/elections", adminOnly},
+	{"PATCH", "/api/v1/admin/elections/:id", adminOnly},
+	{"PATCH", "/api/v1/admin/elections/:id/status", adminOnly},
+	{"DELETE", "/api/v1/admin/elections/:id", adminOnly},
+	{"POST", "/api/v1/admin/incident-types", adminOnly},
+	{"DELETE", "/api/v1/admin/incident-types/:id", adminOnly},
+	{"GET", "/api/v1/admin/suspicious-clusters", adminOnly},
+	{"PATCH", "/api/v1/admin/suspicious-clusters/:id", adminOnly},
+	{"GET", "/api/v1/admin/conflicts", adminOnly},
+	{"GET", "/api/v1/admin/conflicts/:id", adminOnly},
+	{"PATCH", "/api/v1/admin/conflicts/:id/assign", adminOnly},
+	{"POST", "/api/v1/admin/conflicts/:id/resolve", adminOnly},
+	{"GET", "/api/v1/admin/events", adminOnly},
+	{"GET", "/api/v1/admin/events/:id", adminOnly},
+	{"POST", "/api/v1/admin/events/:id/merge", adminOnly},
+	{"POST", "/api/v1/admin/events/:id/split", adminOnly},
+	{"POST", "/api/v1/admin/events/:id/analyze", adminOnly},
+	{"GET", "/api/v1/admin/events/:id/analysis", adminOnly},
+	{"GET", "/api/v1/admin/dead-letters", adminOnly},
+	{"POST", "/api/v1/admin/dead-letters/replay", adminOnly},
+	{"GET", "/api/v1/admin/webhooks", adminOnly},
+	{"POST", "/api/v1/admin/webhooks", adminOnly},
+	{"GET", "/api/v1/admin/webhooks/:id", adminOnly},
+	{"PUT", "/api/v1/admin/webhooks/:id", adminOnly},
+	{"DELETE", "/api/v1/admin/webhooks/:id", adminOnly},
+	{"GET", "/api/v1/admin/webhooks/:id/deliveries", adminOnly},
+	{"POST", "/api/v1/admin/webhooks/deliveries/:id/replay", adminOnly},
+	{"GET", "/api/v1/admin/alert-rules", adminOnly},
+	{"POST", "/api/v1/admin/alert-rules", adminOnly},
+	{"GET", "/api/v1/admin/alert-rules/:id", adminOnly},
+	{"PUT", "/api/v1/admin/alert-rules/:id", adminOnly},
+	{"DELETE", "/api/v1/admin/alert-rules/:id", adminOnly},
+	{"GET", "/api/v1/admin/notification-deliveries", adminOnly},
+	{"POST", "/api/v1/admin/notification-deliveries/:id/retry", adminOnly},
+
+	{"GET", "/api/v1/regions", authenticated},
+	{"GET", "/api/v1/departments", authenticated},
+	{"GET", "/api/v1/incident-types", authenticated},
+	{"POST", "/api/v1/reports", authenticated},
+	{"GET", "/api/v1/reports", authenticated},
+	{"GET", "/api/v1/reports/upload-url", authenticated},
+	{"GET", "/api/v1/reports/:id", authenticated},
+	{"PATCH", "/api/v1/reports/:id", adminOnly}, // contrôle fait dans le handler
+	{"GET", "/api/v1/stats", authenticated},
+	{"GET", "/api/v1/stream", coordinator},
+	{"GET", "/api/v1/stream/ws", coordinator},
+	{"GET", "/api/v1/alerts", coordinator},
+	{"GET", "/api/v1/alerts/:id", coordinator},
+	{"POST", "/api/v1/alerts/:id/acknowledge", coordinator},
+	{"POST", "/api/v1/alerts/:id/resolve", coordinator},
+	{"GET", "/api/v1/notifications", authenticated},
+	{"POST", "/api/v1/notifications/read-all", authenticated},
+	{"POST", "/api/v1/notifications/:id/read", authenticated},
+	{"GET", "/api/v1/notifications/preferences", authenticated},
+	{"PUT", "/api/v1/notifications/preferences", authenticated},
+}
+
+func TestRouteTableIsComplete(t *testing.T) {
+	ts := newTestServer(t)
+	declared := map[string]bool{}
+	for _, r := range routes {
+		declared[r.method+" "+r.path] = true
+	}
+	registered := map[string]bool{}
+	for _, r := range ts.engine.Routes() {
+		key := r.Method + " " + r.Path
+		registered[key] = true
+		if !declared[key] {
+			t.Errorf("route %s is not covered by the route table", key)
+		}
+	}
+	for key := range declared {
+		if !registered[key] {
+			t.Errorf("route %s is in the table but not registered", key)
+		}
+	}
+}
+
+// concretePath remplace les paramètres de chemin par des valeurs plausibles
+func concretePath(path string) string {
+	path = strings.ReplaceAll(path, ":section", service.ConfigSectionTriangulation)
+	return strings.ReplaceAll(path, ":id", "00000000-0000-0000-0000-000000000000")
+}
+
+func TestRoutesRequireAuthentication(t *testing.T) {
+	ts := newTestServer(t)
+	for _, r := range routes {
+		if r.access == public {
+			continue
+		}
+		t.Run(r.method+" "+r.path, func(t *testing.T) {
+			w := ts.do(r.method, concretePath(r.path), "", nil)
+			ts.expectError(w, http.StatusUnauthorized)
+
+			w = ts.do(r.method, concretePath(r.path), "not-a-valid-token", nil)
+			ts.expectError(w, http.StatusUnauthorized)
+		})
+	}
+}
+
+func TestRoutesEnforceRoles(t *testing.T) {
+	ts := newTestServer(t)
+	for _, r := range routes {
+		// Profil juste en dessous du niveau requis
+		var token string
+		switch r.access {
+		case adminOnly:
+			token = tokenCoord
+		case coordinator:
+			token = tokenObserver
+		default:
+			continue
+		}
+		t.Run(r.method+" "+r.path, func(t *testing.T) {
+			w := ts.do(r.method, concretePath(r.path), token, nil)
+			ts.expectError(w, http.StatusForbidden)
+		})
+	}
+}
+
+func TestPublicRoutesDoNotRequireAuthentication(t *testing.T) {
+	ts := newTestServer(t)
+	for _, r := range routes {
+		if r.access != public {
+			continue
+		}
+		t.Run(r.method+" "+r.path, func(t *testing.T) {
+			w := ts.do(r.method, r.path, "", "{}")
+			if w.Code == http.StatusUnauthorized && strings.Contains(w.Body.String(), "Authorization") {
+				t.Errorf("public route asks for a token: %s", w.Body.String())
+			}
+		})
+	}
+}
+
+func TestHealthProbes(t *testing.T) {
+	up := func(name string, critical bool) service.HealthCheck {
+		return service.HealthCheck{Name: name, Critical: critical, Probe: func(ctx context.Context) (map[string]interface{}, error) {
+			return map[string]interface{}{"backend": name}, nil
+		}}
+	}
+	down := func(name string, critical bool) service.HealthCheck {
+		return service.HealthCheck{Name: name, Critical: critical, Probe: func(ctx context.Context) (map[string]interface{}, error) {
+			return map[string]interface{}{"backend": name}, errors.New("dial tcp 10.0.0.5:5432: connection refused")
+		}}
+	}
+	cases := []struct {
+		name         string
+		checks       []service.HealthCheck
+		status       int
+		readiness    string
+		healthStatus string
+	}{
+		{"all dependencies up", []service.HealthCheck{up("database", true), up("queue", false)}, http.StatusOK, service.ReadinessReady, "healthy"},
+		{"queue down", []service.HealthCheck{up("database", true), down("queue", false)}, http.StatusOK, service.ReadinessDegraded, "degraded"},
+		{"database down", []service.HealthCheck{down("database", true), up("queue", false)}, http.StatusServiceUnavailable, service.ReadinessNotReady, "unhealthy"},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			ts := newTestServerWith(t, serverOptions{health: tc.checks})
+
+			// La vivacité ne dépend pas des composants
+			live := ts.expect(ts.do("GET", "/livez", "", nil), http.StatusOK)
+			hasKeys(t, live, "status", "uptime_seconds")
+
+			ready := ts.expect(ts.do("GET", "/readyz", "", nil), tc.status)
+			hasKeys(t, ready, "status", "components", "checked_at")
+			if ready["status"] != tc.readiness {
+				t.Errorf("readiness = %v, want %s", ready["status"], tc.readiness)
+			}
+			components, _ := ready["components"].([]interface{})
+			if len(components) != len(tc.checks) {
+				t.Fatalf("components = %v", ready["components"])
+			}
+			for _, c := range components {
+				comp := c.(map[string]interface{})
+				hasKeys(t, comp, "name", "status", "critical", "latency_ms")
+				// Route publique : ni erreurs ni détails des dépendances
+				if _, ok := comp["error"]; ok {
+					t.Errorf("public component exposes its error: %v", comp)
+				}
+				if _, ok := comp["details"]; ok {
+					t.Errorf("public component exposes its details: %v", comp)
+				}
+			}
+
+			// Vue détaillée sur l'écoute interne uniquement
+			w := httptest.NewRecorder()
+			ts.internal.ServeHTTP(w, httptest.NewRequest("GET", "/readyz", nil))
+			if w.Code != tc.status {
+				t.Fatalf("internal /readyz = %d, want %d", w.Code, tc.status)
+			}
+			var detailed service.Readiness
+			if err := json.Unmarshal(w.Body.Bytes(), &detailed); err != nil {
+				t.Fatal(err)
+			}
+			for _, c := range detailed.Components {
+				if c.Details["backend"] != c.Name || (c.Status == service.HealthDown) != (c.Error != "") {
+					t.Errorf("internal component = %+v", c)
+				}
+			}
+
+			health := ts.expect(ts.do("GET", "/health", "", nil), tc.status)
+			if health["status"] != tc.healthStatus {
+				t.Errorf("health = %v, want %s", health, tc.healthStatus)
+			}
+			hasKeys(t, health, "components")
+		})
+	}
+}
+
+func TestMetricsExposeRouteTemplates(t *testing.T) {
+	ts := newTestServer(t)
+	ts.expect(ts.do("GET", "/livez", "", nil), http.StatusOK)
+	ts.do("GET", "/api/v1/reports/8f14e45f-ceea-467f-a0e6-5b3f2d1c9e7a", "", nil)
+	ts.do("GET", "/no-such-route/42", "", nil)
+
+	// Servies sur l'écoute interne (METRICS_ADDR), jamais sur le port public
+	if public := ts.do("GET", "/metrics", "", nil); public.Code != http.StatusNotFound {
+		t.Errorf("GET /metrics on the public router = %d, want 404", public.Code)
+	}
+	w := httptest.NewRecorder()
+	ts.internal.ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	if w.Code != http.StatusOK {
+		t.Fatalf("GET /metrics = %d", w.Code)
+	}
+	body := w.Body.String()
+	for _, want := range []string{
+		`openvote_http_requests_total{method="GET",route="/livez",status="200"}`,
+		`openvote_http_requests_total{method="GET",route="/api/v1/reports/:id",status="401"}`,
+		`openvote_http_requests_total{method="GET",route="unmatched",status="404"}`,
+		`openvote_http_request_duration_seconds_bucket{method="GET",route="/livez"`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics miss %s", want)
+		}
+	}
+	// Les identifiants ne deviennent pas des séries
+	if strings.Contains(body, "no-such-route") {
+		t.Error("raw path used as a label")
+	}
+}
+
+func TestRequestIDIsEchoedOrGenerated(t *testing.T) {
+	ts := newTestServer(t)
+
+	generated := ts.do("GET", "/livez", "", nil).Header().Get("X-Request-ID")
+	if generated == "" {
+		t.Fatal("no request ID generated")
+	}
+	if other := ts.do("GET", "/livez", "", nil).Header().Get("X-Request-ID"); other == generated {
+		t.Errorf("request ID %s reused across requests", generated)
+	}
+
+	for sent, kept := range map[string]bool{"proxy-7f3a.42": true, "bad id\nforged": false} {
+		req := httptest.NewRequest("GET", "/livez", nil)
+		req.Header.Set("X-Request-ID", sent)
+		w := httptest.NewRecorder()
+		ts.engine.ServeHTTP(w, req)
+		got := w.Header().Get("X-Request-ID")
+		if (got == sent) != kept || got == "" {
+			t.Errorf("X-Request-ID %q answered with %q (kept = %v)", sent, got, kept)
+		}
+	}
+}
+
+func TestCORSAllowsConfiguredOrigins(t *testing.T) {
+	ts := newTestServer(t)
+	for origin, allowed := range map[string]bool{"http://localhost:5173": true, "https://evil.example": false} {
+		req := httptest.NewRequest("OPTIONS", "/api/v1/reports", nil)
+		req.Header.Set("Origin", origin)
+		req.Header.Set("Access-Control-Request-Method", "POST")
+		w := httptest.NewRecorder()
+		ts.engine.ServeHTTP(w, req)
+		got := w.Header().Get("Access-Control-Allow-Origin") == origin
+		if got != allowed {
+			t.Errorf("origin %s allowed = %v, want %v (status %d)", origin, got, allowed, w.Code)
+		}
+	}
+}
+
+func TestAuthRateLimit(t *testing.T) {
+	ts := newTestServerWith(t, serverOptions{authPerMinute: 2})
+	creds := map[string]string{"username": "nobody", "password": "wrong-password"}
+	for i := 0; i < 2; i++ {
+		ts.expectError(ts.do("POST", "/api/v1/auth/login", "", creds), http.StatusUnauthorized)
+	}
+	body := ts.expect(ts.do("POST", "/api/v1/auth/login", "", creds), http.StatusTooManyRequests)
+	hasKeys(t, body, "error", "retry_after")
+
+	// La limite stricte ne concerne que /auth
+	ts.expect(ts.do("GET", "/api/v1/regions", tokenObserver, nil), http.StatusOK)
+}
+
+// errLLM simule l'indisponibilité du modèle de langage
+var errLLM = errors.New("llm unavailable")
+
+// id extrait un identifiant d'une réponse : body[key].id ou body[key] si c'est une chaîne
+func id(t *testing.T, body map[string]interface{}, key string) string {
+	t.Helper()
+	switch v := body[key].(type) {
+	case string:
+		return v
+	case map[string]interface{}:
+		if s, ok := v["id"].(string); ok {
+			return s
+		}
+	}
+	t.Fatalf("no id under %q in %v", key, body)
+	return ""
+}
+
+// total lit le champ total d'une réponse paginée
+func total(t *testing.T, body map[string]interface{}) int {
+	t.Helper()
+	n, ok := body["total"].(float64)
+	if !ok {
+		t.Fatalf("no total in %v", body)
+	}
+	return int(n)
+}
+
+// path construit un chemin d'API
+func path(format string, args ...interface{}) string {
+	return "/api/v1" + fmt.Sprintf(format, args...)
+}
@@ -0,0 +1,773 @@
+package router
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/platform/queue"
+	"github.com/openvote/backend/internal/repository/memory"
+	"github.com/openvote/backend/internal/service"
+	"github.com/openvote/backend/internal/worker"
+)
+
+// createReport dépose un signalement de l'observateur (Douala) et retourne son identifiant
+func (ts *testServer) createReport(incidentType string) string {
+	ts.t.Helper()
+	body := ts.expect(ts.do("POST", path("/reports"), tokenObserver, map[string]interface{}{
+		"observer_id":   ts.users[tokenObserver].ID,
+		"incident_type": incidentType,
+		"description":   "Urne ouverte avant le dépouillement",
+		"latitude":      4.0511,
+		"longitude":     9.7679,
+	}), http.StatusCreated)
+	return id(ts.t, body, "id")
+}
+
+func TestAuthRoutes(t *testing.T) {
+	ts := newTestServer(t)
+
+	t.Run("register validates the payload", func(t *testing.T) {
+		ts.expectError(ts.do("POST", path("/auth/register"), "", map[string]string{"username": "alice"}), http.StatusBadRequest)
+		ts.expectError(ts.do("POST", path("/auth/register"), "", map[string]string{"username": "alice", "password": "123"}), http.StatusBadRequest)
+		ts.expectError(ts.do("POST", path("/auth/register"), "", "{not json"), http.StatusBadRequest)
+	})
+
+	t.Run("register then login", func(t *testing.T) {
+		user := ts.expect(ts.do("POST", path("/auth/register"), "", map[string]string{"username": "alice", "password": "s3cret-pass"}), http.StatusCreated)
+		if user["username"] != "alice" || user["role"] != string(entity.RoleObserver) {
+			t.Errorf("registered user = %v", user)
+		}
+		if _, leaked := user["password_hash"]; leaked {
+			t.Error("password hash is exposed")
+		}
+
+		ts.expectError(ts.do("POST", path("/auth/login"), "", map[string]string{"username": "alice", "password": "wrong"}), http.StatusUnauthorized)
+		login := ts.expect(ts.do("POST", path("/auth/login"), "", map[string]string{"username": "alice", "password": "s3cret-pass"}), http.StatusOK)
+		token, _ := login["token"].(string)
+		if token == "" {
+			t.Fatalf("login = %v", login)
+		}
+		// Le jeton émis ouvre les routes authentifiées
+		ts.expect(ts.do("GET", path("/regions"), token, nil), http.StatusOK)
+	})
+
+	t.Run("enroll with an activation token", func(t *testing.T) {
+		ts.expectError(ts.do("POST", path("/auth/enroll"), "", map[string]string{"activation_token": "x", "pin": "12"}), http.StatusBadRequest)
+		ts.expectError(ts.do("POST", path("/auth/enroll"), "", map[string]string{"activation_token": "garbage", "pin": "1234"}), http.StatusUnauthorized)
+
+		gen := ts.expect(ts.do("POST", path("/admin/generate-token"), tokenSuperAdmin, map[string]string{
+			"role": string(entity.RoleObserver), "region_id": littoral,
+		}), http.StatusOK)
+		hasKeys(t, gen, "activation_token", "role", "region_id")
+
+		enrolled := ts.expect(ts.do("POST", path("/auth/enroll"), "", map[string]string{
+			"activation_token": gen["activation_token"].(string), "pin": "1234",
+		}), http.StatusOK)
+		hasKeys(t, enrolled, "user", "access_token", "refresh_token")
+	})
+}
+
+func TestReportRoutes(t *testing.T) {
+	ts := newTestServer(t)
+
+	t.Run("create validates the payload", func(t *testing.T) {
+		ts.expectError(ts.do("POST", path("/reports"), tokenObserver, map[string]string{"incident_type": "STUFF"}), http.StatusBadRequest)
+	})
+
+	reportID := ts.createReport("STUFF")
+
+	t.Run("create returns the H3 cell", func(t *testing.T) {
+		body := ts.expect(ts.do("POST", path("/reports"), tokenObserver, map[string]interface{}{
+			"observer_id": ts.users[tokenObserver].ID, "incident_type": "TECH", "latitude": 3.848, "longitude": 11.5021,
+		}), http.StatusCreated)
+		hasKeys(t, body, "message", "id", "h3_index")
+	})
+
+	t.Run("list and details", func(t *testing.T) {
+		list := ts.expectList(ts.do("GET", path("/reports"), tokenObserver, nil), http.StatusOK)
+		if len(list) != 2 {
+			t.Errorf("list = %d reports, want 2", len(list))
+		}
+		report := ts.expect(ts.do("GET", path("/reports/%s", reportID), tokenObserver, nil), http.StatusOK)
+		if report["id"] != reportID || report["incident_type"] != "STUFF" {
+			t.Errorf("details = %v", report)
+		}
+		ts.expectError(ts.do("GET", path("/reports/%s", "00000000-0000-0000-0000-000000000000"), tokenObserver, nil), http.StatusNotFound)
+	})
+
+	t.Run("upload url", func(t *testing.T) {
+		ts.expectError(ts.do("GET", path("/reports/upload-url"), tokenObserver, nil), http.StatusBadRequest)
+		body := ts.expect(ts.do("GET", path("/reports/upload-url?file_name=proof.jpg"), tokenObserver, nil), http.StatusOK)
+		if url, _ := body["upload_url"].(string); !strings.Contains(url, "proof.jpg") {
+			t.Errorf("upload url = %v", body)
+		}
+	})
+
+	t.Run("status update is reserved to admins", func(t *testing.T) {
+		ts.expectError(ts.do("PATCH", path("/reports/%s", reportID), tokenObserver, map[string]string{"status": "verified"}), http.StatusForbidden)
+		ts.expectError(ts.do("PATCH", path("/reports/%s", reportID), tokenRegionAdmin, map[string]string{"status": "bogus"}), http.StatusBadRequest)
+		body := ts.expect(ts.do("PATCH", path("/reports/%s", reportID), tokenRegionAdmin, map[string]string{"status": "verified"}), http.StatusOK)
+		if body["status"] != "verified" {
+			t.Errorf("update = %v", body)
+		}
+		report := ts.expect(ts.do("GET", path("/reports/%s", reportID), tokenObserver, nil), http.StatusOK)
+		if report["status"] != "verified" {
+			t.Errorf("status after update = %v", report["status"])
+		}
+	})
+}
+
+func TestAdminUserRoutes(t *testing.T) {
+	ts := newTestServer(t)
+	observerID := ts.users[tokenObserver].ID
+	superID := ts.users[tokenSuperAdmin].ID
+
+	t.Run("generate token rejects unknown roles", func(t *testing.T) {
+		ts.expectError(ts.do("POST", path("/admin/generate-token"), tokenSuperAdmin, map[string]string{"role": "emperor", "region_id": littoral}), http.StatusBadRequest)
+		ts.expectError(ts.do("POST", path("/admin/generate-token"), tokenSuperAdmin, map[string]string{"role": "observer"}), http.StatusBadRequest)
+	})
+
+	t.Run("list users", func(t *testing.T) {
+		body := ts.expect(ts.do("GET", path("/admin/users"), tokenRegionAdmin, nil), http.StatusOK)
+		hasKeys(t, body, "users", "total")
+		if total(t, body) != len(ts.users) {
+			t.Errorf("total = %d, want %d", total(t, body), len(ts.users))
+		}
+	})
+
+	t.Run("update role", func(t *testing.T) {
+		ts.expectError(ts.do("PATCH", path("/admin/users/%s", observerID), tokenSuperAdmin, map[string]string{"role": "emperor"}), http.StatusBadRequest)
+		ts.expectError(ts.do("PATCH", path("/admin/users/%s", "00000000-0000-0000-0000-000000000000"), tokenSuperAdmin, map[string]string{"role": "observer"}), http.StatusNotFound)
+		ts.expectError(ts.do("PATCH", path("/admin/users/%s", superID), tokenSuperAdmin, map[string]string{"role": "observer"}), http.StatusForbidden)
+		body := ts.expect(ts.do("PATCH", path("/admin/users/%s", observerID), tokenSuperAdmin, map[string]string{"role": "local_coord", "region_id": littoral}), http.StatusOK)
+		if body["new_role"] != "local_coord" || body["user_id"] != observerID {
+			t.Errorf("update = %v", body)
+		}
+	})
+
+	t.Run("delete user", func(t *testing.T) {
+		ts.expectError(ts.do("DELETE", path("/admin/users/%s", superID), tokenSuperAdmin, nil), http.StatusForbidden)
+		ts.expectError(ts.do("DELETE", path("/admin/users/%s", "00000000-0000-0000-0000-000000000000"), tokenSuperAdmin, nil), http.StatusNotFound)
+		ts.expect(ts.do("DELETE", path("/admin/users/%s", ts.users[tokenNoRegion].ID), tokenSuperAdmin, nil), http.StatusOK)
+	})
+
+	t.Run("audit log records admin actions", func(t *testing.T) {
+		body := ts.expect(ts.do("GET", path("/admin/audit-logs"), tokenSuperAdmin, nil), http.StatusOK)
+		hasKeys(t, body, "logs", "total")
+		if total(t, body) == 0 {
+			t.Error("no audit entry after role update and deletion")
+		}
+	})
+
+	t.Run("kpis", func(t *testing.T) {
+		ts.createReport("STUFF")
+		body := ts.expect(ts.do("GET", path("/admin/kpis"), tokenSuperAdmin, nil), http.StatusOK)
+		hasKeys(t, body, "users", "reports", "elections")
+		users, _ := body["users"].(map[string]interface{})
+		hasKeys(t, users, "total", "by_role")
+		elections, _ := body["elections"].(map[string]interface{})
+		hasKeys(t, elections, "total", "active")
+	})
+}
+
+func TestConfigRoutes(t *testing.T) {
+	ts := newTestServer(t)
+	section := service.ConfigSectionTriangulation
+
+	body := ts.expect(ts.do("GET", path("/admin/config"), tokenSuperAdmin, nil), http.StatusOK)
+	hasKeys(t, body, "triangulation", "rate_limiting", "storage", "roles", "versions")
+
+	t.Run("section update validates input", func(t *testing.T) {
+		ts.expectError(ts.do("PATCH", path("/admin/config/unknown"), tokenSuperAdmin, map[string]interface{}{"data": map[string]int{}}), http.StatusNotFound)
+		ts.expectError(ts.do("PATCH", path("/admin/config/%s", section), tokenSuperAdmin, map[string]string{"comment": "no data"}), http.StatusBadRequest)
+		invalid := ts.expect(ts.do("PATCH", path("/admin/config/%s", section), tokenSuperAdmin, map[string]interface{}{
+			"data": map[string]interface{}{"radius_meters": -5},
+		}), http.StatusBadRequest)
+		hasKeys(t, invalid, "error", "fields")
+	})
+
+	triangulation := ts.expect(ts.do("GET", path("/admin/config"), tokenSuperAdmin, nil), http.StatusOK)["triangulation"].(map[string]interface{})
+	changed := map[string]interface{}{}
+	for k, v := range triangulation {
+		changed[k] = v
+	}
+	changed["radius_meters"] = 750.0
+
+	t.Run("section update, history, diff and rollback", func(t *testing.T) {
+		ts.expect(ts.do("PATCH", path("/admin/config/%s", section), tokenSuperAdmin, map[string]interface{}{"data": changed, "comment": "élargir"}), http.StatusOK)
+		// Même contenu : aucune nouvelle version
+		same := ts.expect(ts.do("PATCH", path("/admin/config/%s", section), tokenSuperAdmin, map[string]interface{}{"data": changed}), http.StatusOK)
+		if same["message"] != "Aucun changement" {
+			t.Errorf("unchanged update = %v", same)
+		}
+		// Version de base périmée
+		ts.expectError(ts.do("PATCH", path("/admin/config/%s", section), tokenSuperAdmin, map[string]interface{}{
+			"data": triangulation, "base_version": 999,
+		}), http.StatusConflict)
+
+		ts.expectError(ts.do("GET", path("/admin/config/%s/history?limit=0", section), tokenSuperAdmin, nil), http.StatusBadRequest)
+		history := ts.expect(ts.do("GET", path("/admin/config/%s/history", section), tokenSuperAdmin, nil), http.StatusOK)
+		hasKeys(t, history, "section", "versions", "current")
+		versions, _ := history["versions"].([]interface{})
+		if len(versions) == 0 {
+			t.Fatalf("history = %v", history)
+		}
+		version := int64(versions[0].(map[string]interface{})["version"].(float64))
+
+		ts.expectError(ts.do("GET", path("/admin/config/%s/diff?from=abc", section), tokenSuperAdmin, nil), http.StatusBadRequest)
+		diff := ts.expect(ts.do("GET", path("/admin/config/%s/diff?from=0&to=%d", section, version), tokenSuperAdmin, nil), http.StatusOK)
+		hasKeys(t, diff, "section", "from", "to", "changes")
+
+		ts.expectError(ts.do("POST", path("/admin/config/%s/rollback", section), tokenSuperAdmin, map[string]string{}), http.StatusBadRequest)
+	})
+
+	t.Run("bulk update", func(t *testing.T) {
+		ts.expectError(ts.do("PATCH", path("/admin/config"), tokenSuperAdmin, "[]"), http.StatusBadRequest)
+		changed["radius_meters"] = 900.0
+		ts.expect(ts.do("PATCH", path("/admin/config"), tokenSuperAdmin, map[string]interface{}{section: changed}), http.StatusOK)
+	})
+}
+
+func TestLegalRoutes(t *testing.T) {
+	ts := newTestServer(t)
+
+	docs := ts.expectList(ts.do("GET", path("/admin/legal-documents"), tokenSuperAdmin, nil), http.StatusOK)
+	initial := len(docs)
+	doc := ts.expect(ts.do("POST", path("/admin/legal-documents"), tokenSuperAdmin, map[string]string{
+		"title": "Code électoral", "short_name": "CE", "document_type": "loi", "year": "2012",
+	}), http.StatusCreated)
+	docID := id(t, doc, "id")
+	if docs = ts.expectList(ts.do("GET", path("/admin/legal-documents"), tokenSuperAdmin, nil), http.StatusOK); len(docs) != initial+1 {
+		t.Errorf("documents = %d, want %d", len(docs), initial+1)
+	}
+
+	t.Run("articles", func(t *testing.T) {
+		article := ts.expect(ts.do("POST", path("/admin/legal"), tokenSuperAdmin, map[string]string{
+			"document_id": docID, "article_number": "Art. 118", "content": "Le bourrage d'urnes est puni.", "category": "fraude",
+		}), http.StatusCreated)
+		articleID := id(t, article, "id")
+
+		ts.expectError(ts.do("POST", path("/admin/legal/batch"), tokenSuperAdmin, map[string]string{"document_id": docID}), http.StatusBadRequest)
+		batch := ts.expect(ts.do("POST", path("/admin/legal/batch"), tokenSuperAdmin, map[string]interface{}{
+			"document_id": docID,
+			"articles": []map[string]string{
+				{"article_number": "Art. 119", "content": "L'intimidation des électeurs est punie.", "category": "fraude"},
+				{"article_number": "Art. 120", "content": "L'achat de votes est puni.", "category": "corruption"},
+			},
+		}), http.StatusCreated)
+		hasKeys(t, batch, "message", "count")
+
+		byDoc := ts.expectList(ts.do("GET", path("/admin/legal?document_id=%s", docID), tokenSuperAdmin, nil), http.StatusOK)
+		if len(byDoc) != 3 {
+			t.Errorf("articles of the document = %d, want 3", len(byDoc))
+		}
+		byCategory := ts.expectList(ts.do("GET", path("/admin/legal?category=corruption"), tokenSuperAdmin, nil), http.StatusOK)
+		if len(byCategory) != 1 {
+			t.Errorf("articles of the category = %d, want 1", len(byCategory))
+		}
+
+		ts.expect(ts.do("DELETE", path("/admin/legal/%s", articleID), tokenSuperAdmin, nil), http.StatusOK)
+	})
+
+	t.Run("pdf extraction needs a file", func(t *testing.T) {
+		ts.expectError(ts.do("POST", path("/admin/legal/extract-pdf"), tokenSuperAdmin, nil), http.StatusBadRequest)
+	})
+
+	t.Run("embeddings and semantic search", func(t *testing.T) {
+		gen := ts.expect(ts.do("POST", path("/admin/legal/embeddings"), tokenSuperAdmin, nil), http.StatusOK)
+		hasKeys(t, gen, "message", "processed")
+		if gen["processed"].(float64) != 2 {
+			t.Errorf("embeddings = %v, want 2 processed", gen)
+		}
+		again := ts.expect(ts.do("POST", path("/admin/legal/embeddings"), tokenSuperAdmin, nil), http.StatusOK)
+		if again["processed"].(float64) != 0 {
+			t.Errorf("second run = %v, want nothing left to process", again)
+		}
+
+		ts.expectError(ts.do("POST", path("/admin/legal/search"), tokenSuperAdmin, map[string]string{}), http.StatusBadRequest)
+		search := ts.expect(ts.do("POST", path("/admin/legal/search"), tokenSuperAdmin, map[string]interface{}{"query": "achat de votes", "limit": 5}), http.StatusOK)
+		hasKeys(t, search, "query", "results")
+		if results, _ := search["results"].([]interface{}); len(results) != 2 {
+			t.Errorf("search results = %v", search["results"])
+		}
+	})
+
+	t.Run("report qualification and analysis", func(t *testing.T) {
+		reportID := ts.createReport("BUYV")
+		ts.expectError(ts.do("POST", path("/admin/reports/%s/qualify", "00000000-0000-0000-0000-000000000000"), tokenSuperAdmin, nil), http.StatusNotFound)
+		qualify := ts.expect(ts.do("POST", path("/admin/reports/%s/qualify", reportID), tokenSuperAdmin, nil), http.StatusOK)
+		hasKeys(t, qualify, "report_id", "incident", "matches", "total")
+
+		ts.expectError(ts.do("GET", path("/admin/reports/%s/analysis", reportID), tokenSuperAdmin, nil), http.StatusNotFound)
+		analyze := ts.expect(ts.do("POST", path("/admin/reports/%s/analyze", reportID), tokenSuperAdmin, nil), http.StatusOK)
+		hasKeys(t, analyze, "report_id", "incident", "analysis", "matched_articles")
+
+		matches := ts.expectList(ts.do("GET", path("/admin/reports/%s/legal-matches", reportID), tokenSuperAdmin, nil), http.StatusOK)
+		if len(matches) == 0 {
+			t.Error("no legal match stored after qualification")
+		}
+		stored := ts.expect(ts.do("GET", path("/admin/reports/%s/analysis", reportID), tokenSuperAdmin, nil), http.StatusOK)
+		hasKeys(t, stored, "analysis", "matches")
+
+		// Modèle indisponible : les correspondances restent exploitables
+		ts.analysis.err = errLLM
+		defer func() { ts.analysis.err = nil }()
+		degraded := ts.expect(ts.do("POST", path("/admin/reports/%s/analyze", reportID), tokenSuperAdmin, nil), http.StatusOK)
+		hasKeys(t, degraded, "llm_error", "articles")
+	})
+
+	t.Run("review queue", func(t *testing.T) {
+		ts.expectError(ts.do("GET", path("/admin/review-queue?limit=0"), tokenSuperAdmin, nil), http.StatusBadRequest)
+		ts.expectError(ts.do("GET", path("/admin/review-queue?limit=501"), tokenSuperAdmin, nil), http.StatusBadRequest)
+		queue := ts.expect(ts.do("GET", path("/admin/review-queue"), tokenSuperAdmin, nil), http.StatusOK)
+		hasKeys(t, queue, "items", "total")
+		if total(t, queue) != 1 {
+			t.Errorf("review queue = %v", queue)
+		}
+	})
+}
+
+func TestRegionRoutes(t *testing.T) {
+	ts := newTestServer(t)
+
+	regions := ts.expect(ts.do("GET", path("/regions"), tokenObserver, nil), http.StatusOK)
+	hasKeys(t, regions, "regions", "total")
+	if total(t, regions) != 10 {
+		t.Errorf("regions = %d, want the 10 seeded regions", total(t, regions))
+	}
+	first := regions["regions"].([]interface{})[0].(map[string]interface{})
+	hasKeys(t, first, "departments", "dept_count")
+
+	ts.expectError(ts.do("POST", path("/admin/regions"), tokenSuperAdmin, map[string]string{"name": "Nouvelle"}), http.StatusBadRequest)
+	region := ts.expect(ts.do("POST", path("/admin/regions"), tokenSuperAdmin, map[string]string{"name": "Nouvelle", "code": "NV"}), http.StatusCreated)
+	regionID := id(t, region, "region")
+
+	ts.expect(ts.do("PATCH", path("/admin/regions/%s", regionID), tokenSuperAdmin, map[string]string{"name": "Renommée", "code": "RN"}), http.StatusOK)
+	ts.expectError(ts.do("PATCH", path("/admin/regions/%s", "00000000-0000-0000-0000-000000000000"), tokenSuperAdmin, map[string]string{"name": "X", "code": "XX"}), http.StatusNotFound)
+
+	ts.expectError(ts.do("POST", path("/admin/departments"), tokenSuperAdmin, map[string]string{
+		"name": "Wouri", "code": "WO", "region_id": "00000000-0000-0000-0000-000000000000",
+	}), http.StatusBadRequest)
+	dept := ts.expect(ts.do("POST", path("/admin/departments"), tokenSuperAdmin, map[string]string{
+		"name": "Wouri", "code": "WO", "region_id": regionID,
+	}), http.StatusCreated)
+	deptID := id(t, dept, "department")
+
+	depts := ts.expect(ts.do("GET", path("/departments?region_id=%s", regionID), tokenObserver, nil), http.StatusOK)
+	hasKeys(t, depts, "departments", "total")
+	if total(t, depts) != 1 {
+		t.Errorf("departments of the region = %v", depts)
+	}
+
+	ts.expect(ts.do("PATCH", path("/admin/departments/%s", deptID), tokenSuperAdmin, map[string]interface{}{
+		"name": "Wouri", "code": "WO", "region_id": regionID, "population": 3000000,
+	}), http.StatusOK)
+	ts.expect(ts.do("DELETE", path("/admin/departments/%s", deptID), tokenSuperAdmin, nil), http.StatusOK)
+	ts.expect(ts.do("DELETE", path("/admin/regions/%s", regionID), tokenSuperAdmin, nil), http.StatusOK)
+}
+
+func TestElectionRoutes(t *testing.T) {
+	ts := newTestServer(t)
+
+	ts.expectError(ts.do("POST", path("/admin/elections"), tokenSuperAdmin, map[string]string{"name": "Présidentielle"}), http.StatusBadRequest)
+	ts.expectError(ts.do("POST", path("/admin/elections"), tokenSuperAdmin, map[string]string{
+		"name": "Présidentielle", "type": "presidential", "date": "12/10/2025",
+	}), http.StatusBadRequest)
+	created := ts.expect(ts.do("POST", path("/admin/elections"), tokenSuperAdmin, map[string]string{
+		"name": "Présidentielle", "type": "presidential", "date": "2025-10-12",
+	}), http.StatusCreated)
+	electionID := id(t, created, "election")
+
+	list := ts.expect(ts.do("GET", path("/admin/elections"), tokenSuperAdmin, nil), http.StatusOK)
+	hasKeys(t, list, "elections", "total")
+	if total(t, list) != 1 {
+		t.Errorf("elections = %v", list)
+	}
+
+	ts.expect(ts.do("PATCH", path("/admin/elections/%s", electionID), tokenSuperAdmin, map[string]string{
+		"name": "Présidentielle 2025", "type": "presidential", "date": "2025-10-12",
+	}), http.StatusOK)
+	ts.expectError(ts.do("PATCH", path("/admin/elections/%s/status", electionID), tokenSuperAdmin, map[string]string{"status": "cancelled-ish"}), http.StatusBadRequest)
+	ts.expectError(ts.do("PATCH", path("/admin/elections/%s/status", "00000000-0000-0000-0000-000000000000"), tokenSuperAdmin, map[string]string{"status": "active"}), http.StatusNotFound)
+	ts.expect(ts.do("PATCH", path("/admin/elections/%s/status", electionID), tokenSuperAdmin, map[string]string{"status": "active"}), http.StatusOK)
+	ts.expect(ts.do("DELETE", path("/admin/elections/%s", electionID), tokenSuperAdmin, nil), http.StatusOK)
+}
+
+func TestIncidentTypeRoutes(t *testing.T) {
+	ts := newTestServer(t)
+
+	list := ts.expect(ts.do("GET", path("/incident-types"), tokenObserver, nil), http.StatusOK)
+	hasKeys(t, list, "incident_types", "total")
+	if total(t, list) != 12 {
+		t.Errorf("incident types = %d, want the 12 seeded types", total(t, list))
+	}
+
+	ts.expectError(ts.do("POST", path("/admin/incident-types"), tokenSuperAdmin, map[string]interface{}{"name": "Panne", "code": "PANNE"}), http.StatusBadRequest)
+	ts.expectError(ts.do("POST", path("/admin/incident-types"), tokenSuperAdmin, map[string]interface{}{"name": "Panne", "code": "PANNE", "severity": 9}), http.StatusBadRequest)
+	created := ts.expect(ts.do("POST", path("/admin/incident-types"), tokenSuperAdmin, map[string]interface{}{"name": "Panne", "code": "PANNE", "severity": 2}), http.StatusCreated)
+	typeID := id(t, created, "incident_type")
+	ts.expect(ts.do("DELETE", path("/admin/incident-types/%s", typeID), tokenSuperAdmin, nil), http.StatusOK)
+}
+
+func TestReviewRoutes(t *testing.T) {
+	ts := newTestServer(t)
+	ctx := context.Background()
+	reportID := ts.createReport("STUFF")
+	otherID := ts.createReport("INTIM")
+
+	cluster := &entity.SuspiciousCluster{ReportID: reportID, RelatedReportIDs: []string{otherID}, Signals: []string{"same_device"}, Score: 0.9}
+	if err := memory.NewSuspiciousClusterRepository(ts.store).Upsert(ctx, cluster); err != nil {
+		t.Fatalf("seed cluster: %v", err)
+	}
+	conflict := &entity.Conflict{H3Index: "8a2a1072b59ffff", IncidentTypes: []string{"STUFF", "INTIM"}, ReportIDs: []string{reportID, otherID}}
+	if err := memory.NewConflictRepository(ts.store).Create(ctx, conflict); err != nil {
+		t.Fatalf("seed conflict: %v", err)
+	}
+
+	t.Run("suspicious clusters", func(t *testing.T) {
+		list := ts.expect(ts.do("GET", path("/admin/suspicious-clusters"), tokenRegionAdmin, nil), http.StatusOK)
+		hasKeys(t, list, "clusters", "total")
+		if total(t, list) != 1 {
+			t.Errorf("clusters = %v", list)
+		}
+		ts.expectError(ts.do("PATCH", path("/admin/suspicious-clusters/%s", cluster.ID), tokenRegionAdmin, map[string]string{"status": "maybe"}), http.StatusBadRequest)
+		ts.expectError(ts.do("PATCH", path("/admin/suspicious-clusters/%s", "00000000-0000-0000-0000-000000000000"), tokenRegionAdmin, map[string]string{"status": "confirmed"}), http.StatusNotFound)
+		reviewed := ts.expect(ts.do("PATCH", path("/admin/suspicious-clusters/%s", cluster.ID), tokenRegionAdmin, map[string]string{"status": "confirmed", "note": "même appareil"}), http.StatusOK)
+		if rejected, _ := reviewed["report_ids"].([]interface{}); len(rejected) != 2 {
+			t.Errorf("confirmed cluster should reject both reports: %v", reviewed)
+		}
+		for _, id := range []string{reportID, otherID} {
+			if report, _ := memory.NewReportRepository(ts.store).GetByID(ctx, id); report == nil || report.Status != entity.StatusRejected {
+				t.Errorf("report %s not rejected: %+v", id, report)
+			}
+		}
+	})
+
+	t.Run("conflicts", func(t *testing.T) {
+		list := ts.expect(ts.do("GET", path("/admin/conflicts"), tokenRegionAdmin, nil), http.StatusOK)
+		hasKeys(t, list, "conflicts", "total")
+		ts.expectError(ts.do("GET", path("/admin/conflicts/%s", "00000000-0000-0000-0000-000000000000"), tokenRegionAdmin, nil), http.StatusNotFound)
+		got := ts.expect(ts.do("GET", path("/admin/conflicts/%s", conflict.ID), tokenRegionAdmin, nil), http.StatusOK)
+		hasKeys(t, got, "conflict")
+
+		ts.expectError(ts.do("PATCH", path("/admin/conflicts/%s/assign", "00000000-0000-0000-0000-000000000000"), tokenRegionAdmin, nil), http.StatusNotFound)
+		ts.expect(ts.do("PATCH", path("/admin/conflicts/%s/assign", conflict.ID), tokenRegionAdmin, nil), http.StatusOK)
+		ts.expectError(ts.do("PATCH", path("/admin/conflicts/%s/assign", conflict.ID), tokenRegionAdmin, map[string]string{"assignee_id": "not-a-uuid"}), http.StatusBadRequest)
+		ts.expectError(ts.do("PATCH", path("/admin/conflicts/%s/assign", conflict.ID), tokenRegionAdmin, map[string]string{"assignee_id": "00000000-0000-0000-0000-000000000000"}), http.StatusNotFound)
+
+		ts.expectError(ts.do("POST", path("/admin/conflicts/%s/resolve", conflict.ID), tokenRegionAdmin, map[string]string{}), http.StatusBadRequest)
+		ts.expectError(ts.do("POST", path("/admin/conflicts/%s/resolve", conflict.ID), tokenRegionAdmin, map[string]interface{}{"resolution_note": "x", "rejected_report_ids": []string{"00000000-0000-0000-0000-000000000000"}}), http.StatusBadRequest)
+		ts.expect(ts.do("POST", path("/admin/conflicts/%s/resolve", conflict.ID), tokenRegionAdmin, map[string]string{"resolution_note": "bourrage confirmé"}), http.StatusOK)
+		// Déjà résolu
+		ts.expectError(ts.do("POST", path("/admin/conflicts/%s/resolve", conflict.ID), tokenRegionAdmin, map[string]string{"resolution_note": "encore"}), http.StatusNotFound)
+	})
+}
+
+func TestEventRoutes(t *testing.T) {
+	ts := newTestServer(t)
+	ctx := context.Background()
+	clustering := service.NewClusteringService(memory.NewReportRepository(ts.store), memory.NewIncidentEventRepository(ts.store))
+
+	// Deux événements distincts (types différents), le premier avec deux signalements
+	var eventIDs []string
+	var firstReports []string
+	for _, incidentType := range []string{"STUFF", "STUFF", "VIOLE"} {
+		reportID := ts.createReport(incidentType)
+		ev, err := clustering.AssignReport(ctx, reportID)
+		if err != nil {
+			t.Fatalf("assign report: %v", err)
+		}
+		if len(eventIDs) == 0 || eventIDs[len(eventIDs)-1] != ev.ID {
+			eventIDs = append(eventIDs, ev.ID)
+		}
+		if ev.ID == eventIDs[0] {
+			firstReports = append(firstReports, reportID)
+		}
+	}
+	if len(eventIDs) != 2 || len(firstReports) != 2 {
+		t.Fatalf("events = %v, reports of the first = %v", eventIDs, firstReports)
+	}
+
+	list := ts.expect(ts.do("GET", path("/admin/events"), tokenSuperAdmin, nil), http.StatusOK)
+	hasKeys(t, list, "events", "total")
+	if total(t, list) != 2 {
+		t.Errorf("events = %v", list)
+	}
+	ts.expectError(ts.do("GET", path("/admin/events/%s", "00000000-0000-0000-0000-000000000000"), tokenSuperAdmin, nil), http.StatusNotFound)
+	hasKeys(t, ts.expect(ts.do("GET", path("/admin/events/%s", eventIDs[0]), tokenSuperAdmin, nil), http.StatusOK), "event")
+
+	t.Run("analysis", func(t *testing.T) {
+		ts.expectError(ts.do("GET", path("/admin/events/%s/analysis", eventIDs[0]), tokenSuperAdmin, nil), http.StatusNotFound)
+		analyze := ts.expect(ts.do("POST", path("/admin/events/%s/analyze", eventIDs[0]), tokenSuperAdmin, nil), http.StatusOK)
+		hasKeys(t, analyze, "event_id", "incident", "report_count", "analysis", "matched_articles")
+		hasKeys(t, ts.expect(ts.do("GET", path("/admin/events/%s/analysis", eventIDs[0]), tokenSuperAdmin, nil), http.StatusOK), "analysis")
+	})
+
+	t.Run("split and merge", func(t *testing.T) {
+		ts.expectError(ts.do("POST", path("/admin/events/%s/split", eventIDs[0]), tokenSuperAdmin, map[string]interface{}{"report_ids": []string{}}), http.StatusBadRequest)
+		split := ts.expect(ts.do("POST", path("/admin/events/%s/split", eventIDs[0]), tokenSuperAdmin, map[string]interface{}{
+			"report_ids": firstReports[1:],
+		}), http.StatusCreated)
+		splitID := id(t, split, "event")
+
+		ts.expectError(ts.do("POST", path("/admin/events/%s/merge", eventIDs[0]), tokenSuperAdmin, map[string]interface{}{}), http.StatusBadRequest)
+		merged := ts.expect(ts.do("POST", path("/admin/events/%s/merge", eventIDs[0]), tokenSuperAdmin, map[string]interface{}{
+			"source_ids": []string{splitID, eventIDs[1]},
+		}), http.StatusOK)
+		hasKeys(t, merged, "message", "event")
+		target := ts.expect(ts.do("GET", path("/admin/events/%s", eventIDs[0]), tokenSuperAdmin, nil), http.StatusOK)["event"].(map[string]interface{})
+		if target["report_count"].(float64) != 3 {
+			t.Errorf("merged event = %v", target)
+		}
+		source := ts.expect(ts.do("GET", path("/admin/events/%s", eventIDs[1]), tokenSuperAdmin, nil), http.StatusOK)["event"].(map[string]interface{})
+		if source["status"] != "merged" || source["merged_into"] != eventIDs[0] {
+			t.Errorf("source event after merge = %v", source)
+		}
+	})
+}
+
+func TestDeadLetterRoutes(t *testing.T) {
+	ts := newTestServer(t)
+
+	ts.expectError(ts.do("GET", path("/admin/dead-letters?queue=unknown"), tokenSuperAdmin, nil), http.StatusBadRequest)
+	list := ts.expect(ts.do("GET", path("/admin/dead-letters?queue=%s", queue.QueueNewReports), tokenSuperAdmin, nil), http.StatusOK)
+	hasKeys(t, list, "queue", "dead_letters", "total")
+	if total(t, list) != 1 {
+		t.Errorf("dead letters = %v", list)
+	}
+
+	replay := ts.expect(ts.do("POST", path("/admin/dead-letters/replay"), tokenSuperAdmin, map[string]interface{}{
+		"queue": queue.QueueNewReports, "message_ids": []string{"msg-1"},
+	}), http.StatusOK)
+	hasKeys(t, replay, "message", "queue", "replayed")
+	if len(ts.dead.replayed) != 1 || ts.dead.replayed[0] != "msg-1" {
+		t.Errorf("replayed = %v", ts.dead.replayed)
+	}
+	// Corps facultatif : toute la file par défaut
+	ts.expect(ts.do("POST", path("/admin/dead-letters/replay"), tokenSuperAdmin, nil), http.StatusOK)
+
+	// Les files des abonnés aux événements sont administrables elles aussi
+	webhooks := worker.EventQueueName(worker.SubscriberWebhooks)
+	ts.expect(ts.do("GET", path("/admin/dead-letters?queue=%s", webhooks), tokenSuperAdmin, nil), http.StatusOK)
+	replayed := ts.expect(ts.do("POST", path("/admin/dead-letters/replay"), tokenSuperAdmin, map[string]interface{}{"queue": webhooks}), http.StatusOK)
+	if replayed["queue"] != webhooks {
+		t.Errorf("replay = %v", replayed)
+	}
+
+	t.Run("queue backend unavailable", func(t *testing.T) {
+		down := newTestServerWith(t, serverOptions{noDeadLetters: true})
+		down.expectError(down.do("GET", path("/admin/dead-letters"), tokenSuperAdmin, nil), http.StatusServiceUnavailable)
+		down.expectError(down.do("POST", path("/admin/dead-letters/replay"), tokenSuperAdmin, nil), http.StatusServiceUnavailable)
+	})
+}
+
+func TestWebhookRoutes(t *testing.T) {
+	ts := newTestServer(t)
+
+	ts.expectError(ts.do("POST", path("/admin/webhooks"), tokenSuperAdmin, map[string]string{"name": "partenaire"}), http.StatusBadRequest)
+	ts.expectError(ts.do("POST", path("/admin/webhooks"), tokenSuperAdmin, map[string]interface{}{
+		"name": "partenaire", "url": "ftp://partner.example", "event_types": []string{"report.created"},
+	}), http.StatusBadRequest)
+	created := ts.expect(ts.do("POST", path("/admin/webhooks"), tokenSuperAdmin, map[string]interface{}{
+		"name": "partenaire", "url": "https://partner.example/hook", "event_types": []string{"report.created"},
+	}), http.StatusCreated)
+	hasKeys(t, created, "webhook", "secret", "message")
+	webhookID := id(t, created, "webhook")
+
+	list := ts.expect(ts.do("GET", path("/admin/webhooks"), tokenSuperAdmin, nil), http.StatusOK)
+	if total(t, list) != 1 {
+		t.Errorf("webhooks = %v", list)
+	}
+	ts.expectError(ts.do("GET", path("/admin/webhooks/%s", "00000000-0000-0000-0000-000000000000"), tokenSuperAdmin, nil), http.StatusNotFound)
+	got := ts.expect(ts.do("GET", path("/admin/webhooks/%s", webhookID), tokenSuperAdmin, nil), http.StatusOK)
+	if _, leaked := got["secret"]; leaked {
+		t.Errorf("webhook secret is exposed after creation: %v", got)
+	}
+
+	ts.expect(ts.do("PUT", path("/admin/webhooks/%s", webhookID), tokenSuperAdmin, map[string]interface{}{
+		"name": "partenaire", "url": "https://partner.example/v2", "event_types": []string{"report.created"}, "min_severity": 3,
+	}), http.StatusOK)
+
+	ts.expectError(ts.do("GET", path("/admin/webhooks/%s/deliveries", "00000000-0000-0000-0000-000000000000"), tokenSuperAdmin, nil), http.StatusNotFound)
+	ts.expectError(ts.do("GET", path("/admin/webhooks/%s/deliveries?status=lost", webhookID), tokenSuperAdmin, nil), http.StatusBadRequest)
+	deliveries := ts.expect(ts.do("GET", path("/admin/webhooks/%s/deliveries", webhookID), tokenSuperAdmin, nil), http.StatusOK)
+	hasKeys(t, deliveries, "deliveries", "total")
+	ts.expectError(ts.do("POST", path("/admin/webhooks/deliveries/%s/replay", "00000000-0000-0000-0000-000000000000"), tokenSuperAdmin, nil), http.StatusNotFound)
+
+	ts.expect(ts.do("DELETE", path("/admin/webhooks/%s", webhookID), tokenSuperAdmin, nil), http.StatusOK)
+}
+
+func TestAlertRoutes(t *testing.T) {
+	ts := newTestServer(t)
+
+	ts.expectError(ts.do("POST", path("/admin/alert-rules"), tokenSuperAdmin, map[string]interface{}{"name": "Vague"}), http.StatusBadRequest)
+	rule := ts.expect(ts.do("POST", path("/admin/alert-rules"), tokenSuperAdmin, map[string]interface{}{
+		"name": "Bourrages", "incident_types": []string{"STUFF"}, "threshold": 2, "window_minutes": 60, "active": true,
+	}), http.StatusCreated)
+	ruleID := id(t, rule, "id")
+
+	rules := ts.expect(ts.do("GET", path("/admin/alert-rules"), tokenSuperAdmin, nil), http.StatusOK)
+	hasKeys(t, rules, "rules", "total")
+	ts.expectError(ts.do("GET", path("/admin/alert-rules/%s", "00000000-0000-0000-0000-000000000000"), tokenSuperAdmin, nil), http.StatusNotFound)
+	ts.expect(ts.do("GET", path("/admin/alert-rules/%s", ruleID), tokenSuperAdmin, nil), http.StatusOK)
+	rule["threshold"] = 3
+	ts.expect(ts.do("PUT", path("/admin/alert-rules/%s", ruleID), tokenSuperAdmin, rule), http.StatusOK)
+
+	// Alertes déclenchées dans deux régions
+	alertRepo := memory.NewAlertRepository(ts.store)
+	alerts := map[string]*entity.Alert{}
+	for _, regionID := range []string{littoral, "a1000001-0000-0000-0000-000000000002"} {
+		a := &entity.Alert{RuleID: ruleID, GroupKey: "region:" + regionID, RegionID: regionID, Severity: 5, ReportCount: 3}
+		if _, err := alertRepo.UpsertOpenAlert(context.Background(), a); err != nil {
+			t.Fatalf("seed alert: %v", err)
+		}
+		alerts[regionID] = a
+	}
+	inRegion, outOfRegion := alerts[littoral].ID, alerts["a1000001-0000-0000-0000-000000000002"].ID
+
+	t.Run("listing is scoped to the user's region", func(t *testing.T) {
+		ts.expectError(ts.do("GET", path("/alerts?limit=0"), tokenCoord, nil), http.StatusBadRequest)
+		ts.expectError(ts.do("GET", path("/alerts?status=lost"), tokenCoord, nil), http.StatusBadRequest)
+		ts.expectError(ts.do("GET", path("/alerts"), tokenNoRegion, nil), http.StatusForbidden)
+
+		if body := ts.expect(ts.do("GET", path("/alerts"), tokenCoord, nil), http.StatusOK); total(t, body) != 1 {
+			t.Errorf("coordinator alerts = %v", body)
+		}
+		if body := ts.expect(ts.do("GET", path("/alerts"), tokenSuperAdmin, nil), http.StatusOK); total(t, body) != 2 {
+			t.Errorf("super admin alerts = %v", body)
+		}
+		ts.expectError(ts.do("GET", path("/alerts/%s", outOfRegion), tokenCoord, nil), http.StatusNotFound)
+		ts.expect(ts.do("GET", path("/alerts/%s", inRegion), tokenCoord, nil), http.StatusOK)
+	})
+
+	t.Run("acknowledge then resolve", func(t *testing.T) {
+		ts.expectError(ts.do("POST", path("/alerts/%s/acknowledge", outOfRegion), tokenCoord, nil), http.StatusNotFound)
+		ack := ts.expect(ts.do("POST", path("/alerts/%s/acknowledge", inRegion), tokenCoord, nil), http.StatusOK)
+		if ack["status"] != string(entity.AlertAcknowledged) {
+			t.Errorf("acknowledged alert = %v", ack)
+		}
+		ts.expectError(ts.do("POST", path("/alerts/%s/acknowledge", inRegion), tokenCoord, nil), http.StatusConflict)
+		resolved := ts.expect(ts.do("POST", path("/alerts/%s/resolve", inRegion), tokenCoord, map[string]string{"note": "traité"}), http.StatusOK)
+		if resolved["status"] != string(entity.AlertResolved) {
+			t.Errorf("resolved alert = %v", resolved)
+		}
+	})
+
+	ts.expect(ts.do("DELETE", path("/admin/alert-rules/%s", ruleID), tokenSuperAdmin, nil), http.StatusOK)
+}
+
+func TestNotificationRoutes(t *testing.T) {
+	ts := newTestServer(t)
+	ctx := context.Background()
+	repo := memory.NewNotificationRepository(ts.store)
+	userID := ts.users[tokenObserver].ID
+	var items []*entity.Notification
+	for _, title := range []string{"Alerte Littoral", "Alerte Centre"} {
+		n := &entity.Notification{UserID: userID, Kind: "alert.triggered", Title: title, Body: "3 signalements"}
+		if err := repo.CreateInboxItem(ctx, n); err != nil {
+			t.Fatalf("seed inbox: %v", err)
+		}
+		items = append(items, n)
+	}
+
+	t.Run("inbox", func(t *testing.T) {
+		ts.expectError(ts.do("GET", path("/notifications?limit=500"), tokenObserver, nil), http.StatusBadRequest)
+		inbox := ts.expect(ts.do("GET", path("/notifications"), tokenObserver, nil), http.StatusOK)
+		hasKeys(t, inbox, "notifications", "total", "unread")
+		if inbox["unread"].(float64) != 2 {
+			t.Errorf("inbox = %v", inbox)
+		}
+		// Boîte d'un autre utilisateur
+		if other := ts.expect(ts.do("GET", path("/notifications"), tokenCoord, nil), http.StatusOK); total(t, other) != 0 {
+			t.Errorf("other user's inbox = %v", other)
+		}
+
+		ts.expectError(ts.do("POST", path("/notifications/%s/read", items[0].ID), tokenCoord, nil), http.StatusNotFound)
+		ts.expect(ts.do("POST", path("/notifications/%s/read", items[0].ID), tokenObserver, nil), http.StatusOK)
+		all := ts.expect(ts.do("POST", path("/notifications/read-all"), tokenObserver, nil), http.StatusOK)
+		if all["updated"].(float64) != 1 {
+			t.Errorf("read-all = %v", all)
+		}
+	})
+
+	t.Run("preferences", func(t *testing.T) {
+		prefs := ts.expect(ts.do("GET", path("/notifications/preferences"), tokenObserver, nil), http.StatusOK)
+		hasKeys(t, prefs, "preferences", "available_channels")
+		ts.expectError(ts.do("PUT", path("/notifications/preferences"), tokenObserver, map[string]interface{}{"channels": []string{"email"}}), http.StatusBadRequest)
+		saved := ts.expect(ts.do("PUT", path("/notifications/preferences"), tokenObserver, map[string]interface{}{
+			"locale": "en", "channels": []string{"inbox", "email"}, "email": "observer@example.org",
+		}), http.StatusOK)
+		hasKeys(t, saved, "preferences")
+	})
+
+	t.Run("delivery log", func(t *testing.T) {
+		ts.expectError(ts.do("GET", path("/admin/notification-deliveries?status=lost"), tokenSuperAdmin, nil), http.StatusBadRequest)
+		d := &entity.NotificationDelivery{UserID: userID, Channel: "email", Kind: "alert.triggered", DedupKey: "k1", Recipient: "observer@example.org",
+			Subject: "Alerte", Body: "3 signalements", Status: entity.NotificationDeliveryStatus("failed"), NextAttemptAt: time.Now()}
+		if _, err := repo.CreateDelivery(ctx, d); err != nil {
+			t.Fatalf("seed delivery: %v", err)
+		}
+		list := ts.expect(ts.do("GET", path("/admin/notification-deliveries"), tokenSuperAdmin, nil), http.StatusOK)
+		hasKeys(t, list, "deliveries", "total")
+		ts.expectError(ts.do("POST", path("/admin/notification-deliveries/%s/retry", "00000000-0000-0000-0000-000000000000"), tokenSuperAdmin, nil), http.StatusNotFound)
+		ts.expect(ts.do("POST", path("/admin/notification-deliveries/%s/retry", d.ID), tokenSuperAdmin, nil), http.StatusAccepted)
+	})
+}
+
+func TestStatsRoute(t *testing.T) {
+	ts := newTestServer(t)
+	ts.createReport("STUFF")
+	ts.createReport("VIOLE")
+
+	stats := ts.expect(ts.do("GET", path("/stats"), tokenCoord, nil), http.StatusOK)
+	hasKeys(t, stats, "total", "last_24h", "status_counts", "incident_counts", "hourly_counts",
+		"top_observers", "recent_reports", "unique_observers", "events", "generated_at")
+	if total(t, stats) != 2 {
+		t.Errorf("total = %v", stats["total"])
+	}
+}
+
+func TestStreamRoutes(t *testing.T) {
+	ts := newTestServer(t)
+
+	// Reprise depuis un identifiant inconnu : le client est invité à recharger son état
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	req := httptest.NewRequest("GET", path("/stream?access_token=%s", tokenCoord), nil).WithContext(ctx)
+	req.Header.Set("Last-Event-ID", "unknown-event")
+	w := httptest.NewRecorder()
+	ts.engine.ServeHTTP(w, req)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("status = %d; body = %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		t.Errorf("content type = %q", ct)
+	}
+	if body := w.Body.String(); !strings.HasPrefix(body, "retry: 3000") || !strings.Contains(body, "event: stream.reset") {
+		t.Errorf("stream = %q", body)
+	}
+
+	// Sans région, un coordinateur ne peut pas s'abonner
+	ts.expectError(ts.do("GET", path("/stream?access_token=%s", tokenNoRegion), "", nil), http.StatusForbidden)
+	ts.expectError(ts.do("GET", path("/stream/ws?access_token=%s", tokenNoRegion), "", nil), http.StatusForbidden)
+}
+
+// Les erreurs de validation ont toutes la même forme : {"error": "..."}
+func TestValidationErrorsAreJSON(t *testing.T) {
+	ts := newTestServer(t)
+	w := ts.do("POST", path("/reports"), tokenObserver, "{")
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/json") {
+		t.Errorf("content type = %q", ct)
+	}
+	var body map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil || body["error"] == nil {
+		t.Errorf("malformed body = %s (%v)", w.Body.String(), err)
+	}
+}
@@ -0,0 +1,26 @@
+package entity
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"time"
+)
+
+// ComputeHash calcule l'empreinte d'une entrée d'audit chaînée à la précédente.
+// Les champs sont encodés en tableau JSON (pas d'ambiguïté de concaténation) ;
+// l'horodatage est normalisé en UTC à la microseconde, précision de PostgreSQL.
+func (l AuditLog) ComputeHash(prevHash string) string {
+	data, _ := json.Marshal([]string{
+		prevHash,
+		l.ID,
+		l.AdminID,
+		l.AdminName,
+		l.Action,
+		l.TargetID,
+		l.Details,
+		l.CreatedAt.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano),
+	})
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
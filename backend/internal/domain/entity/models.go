@@ -1,6 +1,7 @@
 package entity
 
 import (
+	"encoding/json"
 	"time"
 )
 
@@ -33,6 +34,8 @@ type User struct {
 	CreatedAt    time.Time  `json:"created_at" db:"created_at"`
 	UpdatedAt    time.Time  `json:"updated_at" db:"updated_at"`
 	LastLoginAt  *time.Time `json:"last_login_at,omitempty" db:"last_login_at"`
+	// Empreinte du token d'activation utilisé à l'enrôlement (détection Sybil)
+	ActivationTokenHash string `json:"-" db:"activation_token_hash"`
 }
 
 // Report représente un signalement d'incident sur le terrain
@@ -47,10 +50,18 @@ type Report struct {
 	H3Index      string       `json:"h3_index" db:"h3_index" gorm:"index"`
 	Status       ReportStatus `json:"status" db:"status" gorm:"type:report_status;default:'pending'"`
 	ProofURL     string       `json:"proof_url" db:"proof_url"`
+	Severity     int          `json:"severity" db:"severity"` // 1-5, copiée du type d'incident à la soumission
 	CreatedAt    time.Time    `json:"created_at" db:"created_at"`
+
+	// Empreintes (HMAC) de l'IP source et de l'appareil : jamais stockées ni exposées en clair
+	SourceFingerprint string `json:"-" db:"source_fingerprint"`
+	DeviceFingerprint string `json:"-" db:"device_fingerprint"`
 	
 	// Fields populated via Joins
 	AuthorRole   UserRole     `json:"author_role" db:"author_role" gorm:"-"`
+	AuthorTokenHash string    `json:"-" db:"author_token_hash" gorm:"-"`
+	RegionID     string       `json:"region_id,omitempty" db:"region_id" gorm:"-"` // Région de l'observateur
+	DepartmentID string       `json:"department_id,omitempty" db:"department_id" gorm:"-"` // Département du bureau de vote le plus proche (alertes)
 }
 
 // TableName surcharge pour GORM (optionnel mais recommandé)
@@ -89,6 +100,24 @@ func (Department) TableName() string {
 	return "departments"
 }
 
+// PollingStation représente un bureau de vote rattaché à un département
+type PollingStation struct {
+	ID               string    `json:"id" db:"id"`
+	Code             string    `json:"code" db:"code"`
+	Name             string    `json:"name" db:"name"`
+	DepartmentID     string    `json:"department_id" db:"department_id"`
+	Address          string    `json:"address" db:"address"`
+	Latitude         *float64  `json:"latitude,omitempty" db:"latitude"`
+	Longitude        *float64  `json:"longitude,omitempty" db:"longitude"`
+	RegisteredVoters int       `json:"registered_voters" db:"registered_voters"`
+	CreatedAt        time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt        time.Time `json:"updated_at" db:"updated_at"`
+}
+
+func (PollingStation) TableName() string {
+	return "polling_stations"
+}
+
 // ElectionStatus définit l'état d'un scrutin
 type ElectionStatus string
 
@@ -125,6 +154,10 @@ type AuditLog struct {
 	TargetID  string    `json:"target_id" db:"target_id"`
 	Details   string    `json:"details" db:"details"`
 	CreatedAt time.Time `json:"created_at" db:"created_at"`
+	// Chaînage : Hash couvre l'entrée et PrevHash (vides pour les entrées antérieures au chaînage)
+	Seq      int64  `json:"seq" db:"seq"`
+	PrevHash string `json:"prev_hash,omitempty" db:"prev_hash"`
+	Hash     string `json:"hash,omitempty" db:"hash"`
 }
 
 func (AuditLog) TableName() string {
@@ -205,7 +238,8 @@ func (ReportLegalMatch) TableName() string {
 // LegalAnalysis représente l'analyse juridique d'un rapport générée par le LLM
 type LegalAnalysis struct {
 	ID             string    `json:"id" db:"id"`
-	ReportID       string    `json:"report_id" db:"report_id"`
+	ReportID       string    `json:"report_id,omitempty" db:"report_id"`
+	EventID        string    `json:"event_id,omitempty" db:"event_id"` // Analyse consolidée d'un événement
 	Summary        string    `json:"summary" db:"summary"`
 	Recommendation string    `json:"recommendation" db:"recommendation"`
 	SeverityLevel  int       `json:"severity_level" db:"severity_level"`
@@ -217,3 +251,305 @@ type LegalAnalysis struct {
 func (LegalAnalysis) TableName() string {
 	return "legal_analyses"
 }
+
+// ClusterReviewStatus définit l'état de revue d'un cluster suspect
+type ClusterReviewStatus string
+
+const (
+	ClusterOpen      ClusterReviewStatus = "open"
+	ClusterConfirmed ClusterReviewStatus = "confirmed" // Collusion avérée
+	ClusterDismissed ClusterReviewStatus = "dismissed" // Faux positif
+)
+
+// SuspiciousCluster représente un groupe de signalements citoyens soupçonnés de collusion (Sybil).
+// Le signalement cible n'est pas auto-vérifié tant qu'un humain n'a pas tranché.
+type SuspiciousCluster struct {
+	ID               string              `json:"id" db:"id"`
+	ReportID         string              `json:"report_id" db:"report_id"`
+	RelatedReportIDs []string            `json:"related_report_ids" db:"related_report_ids"`
+	Signals          []string            `json:"signals" db:"signals"`
+	Score            float64             `json:"score" db:"score"`
+	Status           ClusterReviewStatus `json:"status" db:"status"`
+	ReviewedBy       string              `json:"reviewed_by,omitempty" db:"reviewed_by"`
+	ReviewNote       string              `json:"review_note,omitempty" db:"review_note"`
+	CreatedAt        time.Time           `json:"created_at" db:"created_at"`
+	ReviewedAt       *time.Time          `json:"reviewed_at,omitempty" db:"reviewed_at"`
+}
+
+func (SuspiciousCluster) TableName() string {
+	return "suspicious_clusters"
+}
+
+// ConflictStatus définit l'état d'un conflit entre signalements
+type ConflictStatus string
+
+const (
+	ConflictOpen     ConflictStatus = "open"
+	ConflictResolved ConflictStatus = "resolved"
+)
+
+// Conflict regroupe des signalements d'une même zone/fenêtre temporelle décrivant des incidents
+// de types différents. Tant qu'il est ouvert, aucun des signalements liés n'est auto-vérifié.
+type Conflict struct {
+	ID             string         `json:"id" db:"id"`
+	H3Index        string         `json:"h3_index" db:"h3_index"`
+	IncidentTypes  []string       `json:"incident_types" db:"incident_types"`
+	ReportIDs      []string       `json:"report_ids" db:"-"` // Table de liaison conflict_reports
+	Status         ConflictStatus `json:"status" db:"status"`
+	AssigneeID     string         `json:"assignee_id,omitempty" db:"assignee_id"`
+	ResolutionNote string         `json:"resolution_note,omitempty" db:"resolution_note"`
+	ResolvedBy     string         `json:"resolved_by,omitempty" db:"resolved_by"`
+	CreatedAt      time.Time      `json:"created_at" db:"created_at"`
+	ResolvedAt     *time.Time     `json:"resolved_at,omitempty" db:"resolved_at"`
+}
+
+func (Conflict) TableName() string {
+	return "conflicts"
+}
+
+// IncidentEventStatus définit l'état d'un événement consolidé
+type IncidentEventStatus string
+
+const (
+	EventActive IncidentEventStatus = "active"
+	EventMerged IncidentEventStatus = "merged" // Fusionné manuellement dans un autre événement
+)
+
+// IncidentEvent regroupe les signalements décrivant un même incident (même type, zone et période).
+// Les agrégats (centroïde, période, compteur, sévérité) sont recalculés à partir des signalements liés.
+type IncidentEvent struct {
+	ID              string              `json:"id" db:"id"`
+	IncidentType    string              `json:"incident_type" db:"incident_type"`
+	H3Index         string              `json:"h3_index" db:"h3_index"`
+	Centroid        string              `json:"centroid" db:"centroid"` // WKT POINT(lon lat)
+	FirstReportedAt time.Time           `json:"first_reported_at" db:"first_reported_at"`
+	LastReportedAt  time.Time           `json:"last_reported_at" db:"last_reported_at"`
+	ReportCount     int                 `json:"report_count" db:"report_count"`
+	MaxSeverity     int                 `json:"max_severity" db:"max_severity"`
+	Status          IncidentEventStatus `json:"status" db:"status"`
+	MergedInto      string              `json:"merged_into,omitempty" db:"merged_into"`
+	ReportIDs       []string            `json:"report_ids,omitempty" db:"-"` // Signalements liés (reports.event_id)
+	CreatedAt       time.Time           `json:"created_at" db:"created_at"`
+	UpdatedAt       time.Time           `json:"updated_at" db:"updated_at"`
+}
+
+func (IncidentEvent) TableName() string {
+	return "incident_events"
+}
+
+// ReviewQueueItem est un signalement en attente enrichi des critères de priorisation
+type ReviewQueueItem struct {
+	Report
+	EventID       string  `json:"event_id,omitempty"`
+	Corroboration int     `json:"corroboration"` // Autres signalements non rejetés du même événement
+	AgeMinutes    float64 `json:"age_minutes"`
+}
+
+// OutboxMessage est un message écrit dans la même transaction que la donnée métier,
+// en attente de publication par le relais
+type OutboxMessage struct {
+	ID        string            `json:"id" db:"id"`
+	Queue     string            `json:"queue" db:"queue"` // File de destination (échange par défaut)
+	Topic     string            `json:"topic" db:"topic"` // Ou clé de routage sur l'échange des événements
+	Payload   json.RawMessage   `json:"payload" db:"payload"`
+	Headers   map[string]string `json:"headers,omitempty" db:"headers"` // Contexte de trace de la requête d'origine (traceparent)
+	Attempts  int               `json:"attempts" db:"attempts"`
+	LastError string            `json:"last_error,omitempty" db:"last_error"`
+	CreatedAt time.Time         `json:"created_at" db:"created_at"`
+}
+
+func (OutboxMessage) TableName() string {
+	return "outbox"
+}
+
+// WebhookSubscription est un abonnement d'une organisation partenaire aux événements
+type WebhookSubscription struct {
+	ID          string    `json:"id" db:"id"`
+	Name        string    `json:"name" db:"name"`
+	URL         string    `json:"url" db:"url"`
+	Secret      string    `json:"-" db:"secret"` // Clé HMAC, communiquée une seule fois à la création
+	EventTypes  []string  `json:"event_types" db:"event_types"`
+	RegionIDs   []string  `json:"region_ids" db:"region_ids"` // Vide : toutes les régions
+	MinSeverity int       `json:"min_severity" db:"min_severity"`
+	Active      bool      `json:"active" db:"active"`
+	CreatedBy   string    `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt   time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at" db:"updated_at"`
+}
+
+func (WebhookSubscription) TableName() string {
+	return "webhook_subscriptions"
+}
+
+type WebhookDeliveryStatus string
+
+const (
+	DeliveryPending   WebhookDeliveryStatus = "pending"
+	DeliveryDelivered WebhookDeliveryStatus = "delivered"
+	DeliveryFailed    WebhookDeliveryStatus = "failed" // Tentatives épuisées, rejouable manuellement
+)
+
+// WebhookDelivery journalise l'envoi d'un événement à un abonné
+type WebhookDelivery struct {
+	ID             string                `json:"id" db:"id"`
+	SubscriptionID string                `json:"subscription_id" db:"subscription_id"`
+	EventID        string                `json:"event_id" db:"event_id"`
+	EventType      string                `json:"event_type" db:"event_type"`
+	Payload        json.RawMessage       `json:"payload" db:"payload"`
+	Status         WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts       int                   `json:"attempts" db:"attempts"`
+	ResponseStatus int                   `json:"response_status" db:"response_status"`
+	LastError      string                `json:"last_error,omitempty" db:"last_error"`
+	NextAttemptAt  time.Time             `json:"next_attempt_at" db:"next_attempt_at"`
+	CreatedAt      time.Time             `json:"created_at" db:"created_at"`
+	DeliveredAt    *time.Time            `json:"delivered_at,omitempty" db:"delivered_at"`
+}
+
+func (WebhookDelivery) TableName() string {
+	return "webhook_deliveries"
+}
+
+// Regroupement géographique des signalements d'une règle d'alerte
+const (
+	AlertGroupNone       = "none"       // Tout le périmètre de la règle
+	AlertGroupRegion     = "region"     // Par région de l'observateur
+	AlertGroupDepartment = "department" // Par département du bureau de vote le plus proche du signalement
+	AlertGroupH3         = "h3"         // Par cellule H3 parente (résolution de la règle)
+)
+
+// AlertRule décrit les conditions de déclenchement d'une alerte
+type AlertRule struct {
+	ID             string    `json:"id" db:"id"`
+	Name           string    `json:"name" db:"name"`
+	Description    string    `json:"description" db:"description"`
+	IncidentTypes  []string  `json:"incident_types" db:"incident_types"` // Codes ou noms ; vide : tous
+	MinSeverity    int       `json:"min_severity" db:"min_severity"`
+	RegionIDs      []string  `json:"region_ids" db:"region_ids"` // Vide : toutes les régions
+	GroupBy        string    `json:"group_by" db:"group_by"`
+	H3Resolution   int       `json:"h3_resolution" db:"h3_resolution"`
+	Threshold      int       `json:"threshold" db:"threshold"`
+	WindowMinutes  int       `json:"window_minutes" db:"window_minutes"`
+	TimeOfDayStart string    `json:"time_of_day_start,omitempty" db:"time_of_day_start"` // "HH:MM" inclus
+	TimeOfDayEnd   string    `json:"time_of_day_end,omitempty" db:"time_of_day_end"`     // "HH:MM" exclu
+	Timezone       string    `json:"timezone" db:"timezone"`
+	Channels       []string  `json:"channels" db:"channels"`
+	Active         bool      `json:"active" db:"active"`
+	CreatedBy      string    `json:"created_by,omitempty" db:"created_by"`
+	CreatedAt      time.Time `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time `json:"updated_at" db:"updated_at"`
+}
+
+func (AlertRule) TableName() string {
+	return "alert_rules"
+}
+
+type AlertStatus string
+
+const (
+	AlertOpen         AlertStatus = "open"
+	AlertAcknowledged AlertStatus = "acknowledged"
+	AlertResolved     AlertStatus = "resolved"
+)
+
+// Alert est le déclenchement d'une règle sur une zone ; elle reste ouverte (et s'enrichit
+// des nouveaux signalements) jusqu'à sa résolution
+type Alert struct {
+	ID               string      `json:"id" db:"id"`
+	RuleID           string      `json:"rule_id" db:"rule_id"`
+	RuleName         string      `json:"rule_name" db:"rule_name" gorm:"-"`
+	GroupKey         string      `json:"group_key" db:"group_key"`
+	RegionID         string      `json:"region_id,omitempty" db:"region_id"`
+	Status           AlertStatus `json:"status" db:"status"`
+	Severity         int         `json:"severity" db:"severity"`
+	ReportIDs        []string    `json:"report_ids" db:"report_ids"`
+	ReportCount      int         `json:"report_count" db:"report_count"`
+	FirstTriggeredAt time.Time   `json:"first_triggered_at" db:"first_triggered_at"`
+	LastTriggeredAt  time.Time   `json:"last_triggered_at" db:"last_triggered_at"`
+	AcknowledgedBy   string      `json:"acknowledged_by,omitempty" db:"acknowledged_by"`
+	AcknowledgedAt   *time.Time  `json:"acknowledged_at,omitempty" db:"acknowledged_at"`
+	ResolvedBy       string      `json:"resolved_by,omitempty" db:"resolved_by"`
+	ResolvedAt       *time.Time  `json:"resolved_at,omitempty" db:"resolved_at"`
+	ResolutionNote   string      `json:"resolution_note,omitempty" db:"resolution_note"`
+}
+
+func (Alert) TableName() string {
+	return "alerts"
+}
+
+// NotificationPreferences : canaux, langue et heures calmes d'un utilisateur
+type NotificationPreferences struct {
+	UserID          string    `json:"user_id" db:"user_id"`
+	Locale          string    `json:"locale" db:"locale"` // "fr" ou "en"
+	Email           string    `json:"email,omitempty" db:"email"`
+	Phone           string    `json:"phone,omitempty" db:"phone"` // Format international (+237...)
+	Channels        []string  `json:"channels" db:"channels"`
+	MutedKinds      []string  `json:"muted_kinds" db:"muted_kinds"`
+	QuietHoursStart string    `json:"quiet_hours_start,omitempty" db:"quiet_hours_start"`
+	QuietHoursEnd   string    `json:"quiet_hours_end,omitempty" db:"quiet_hours_end"`
+	Timezone        string    `json:"timezone" db:"timezone"`
+	UpdatedAt       time.Time `json:"updated_at" db:"updated_at"`
+}
+
+func (NotificationPreferences) TableName() string {
+	return "notification_preferences"
+}
+
+// Notification est un message de la boîte de réception in-app
+type Notification struct {
+	ID        string     `json:"id" db:"id"`
+	UserID    string     `json:"user_id" db:"user_id"`
+	Kind      string     `json:"kind" db:"kind"`
+	Title     string     `json:"title" db:"title"`
+	Body      string     `json:"body" db:"body"`
+	ReadAt    *time.Time `json:"read_at,omitempty" db:"read_at"`
+	CreatedAt time.Time  `json:"created_at" db:"created_at"`
+}
+
+func (Notification) TableName() string {
+	return "notifications"
+}
+
+type NotificationDeliveryStatus string
+
+const (
+	NotificationPending NotificationDeliveryStatus = "pending"
+	NotificationSent    NotificationDeliveryStatus = "sent"
+	NotificationFailed  NotificationDeliveryStatus = "failed"
+)
+
+// NotificationDelivery journalise l'envoi d'une notification sur un canal
+type NotificationDelivery struct {
+	ID            string                     `json:"id" db:"id"`
+	UserID        string                     `json:"user_id" db:"user_id"`
+	Channel       string                     `json:"channel" db:"channel"`
+	Kind          string                     `json:"kind" db:"kind"`
+	DedupKey      string                     `json:"dedup_key" db:"dedup_key"`
+	Recipient     string                     `json:"recipient" db:"recipient"`
+	Subject       string                     `json:"subject" db:"subject"`
+	Body          string                     `json:"body" db:"body"`
+	Status        NotificationDeliveryStatus `json:"status" db:"status"`
+	Attempts      int                        `json:"attempts" db:"attempts"`
+	LastError     string                     `json:"last_error,omitempty" db:"last_error"`
+	NextAttemptAt time.Time                  `json:"next_attempt_at" db:"next_attempt_at"`
+	CreatedAt     time.Time                  `json:"created_at" db:"created_at"`
+	SentAt        *time.Time                 `json:"sent_at,omitempty" db:"sent_at"`
+}
+
+func (NotificationDelivery) TableName() string {
+	return "notification_deliveries"
+}
+
+// ConfigVersion est une version d'une section de la configuration système
+type ConfigVersion struct {
+	Version        int64           `json:"version" db:"version"`
+	Section        string          `json:"section" db:"section"`
+	Data           json.RawMessage `json:"data" db:"data"`
+	ChangedBy      string          `json:"changed_by" db:"changed_by"`
+	Comment        string          `json:"comment,omitempty" db:"comment"`
+	RolledBackFrom *int64          `json:"rolled_back_from,omitempty" db:"rolled_back_from"`
+	CreatedAt      time.Time       `json:"created_at" db:"created_at"`
+}
+
+func (ConfigVersion) TableName() string {
+	return "system_config_versions"
+}
@@ -0,0 +1,219 @@
+// Package event définit les événements métier publiés sur le bus (échange topic) :
+// chaque événement circule dans une enveloppe versionnée, la clé de routage étant son type.
+package event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Type identifie un événement ; il sert aussi de clé de routage ("report.created")
+type Type string
+
+const (
+	TypeReportCreated         Type = "report.created"
+	TypeReportStatusChanged   Type = "report.status_changed"
+	TypeReportTriangulated    Type = "report.triangulated"
+	TypeEvidenceUploaded      Type = "evidence.uploaded"
+	TypeElectionStatusChanged Type = "election.status_changed"
+	TypeUserRoleChanged       Type = "user.role_changed"
+	TypeAlertTriggered        Type = "alert.triggered"
+	TypeAlertStatusChanged    Type = "alert.status_changed"
+	TypeConfigChanged         Type = "config.changed"
+)
+
+// Payload est implémenté par chaque événement métier
+type Payload interface {
+	EventType() Type
+	// SchemaVersion est incrémentée à chaque changement incompatible du contenu
+	SchemaVersion() int
+}
+
+// Envelope transporte un événement et ses métadonnées
+type Envelope struct {
+	ID         string          `json:"id"`
+	Type       Type            `json:"type"`
+	Version    int             `json:"version"`
+	OccurredAt time.Time       `json:"occurred_at"`
+	Data       json.RawMessage `json:"data"`
+}
+
+// New emballe un événement dans une enveloppe horodatée
+func New(p Payload) (Envelope, error) {
+	data, err := json.Marshal(p)
+	if err != nil {
+		return Envelope{}, fmt.Errorf("failed to marshal %s event: %w", p.EventType(), err)
+	}
+	return Envelope{
+		ID:         uuid.New().String(),
+		Type:       p.EventType(),
+		Version:    p.SchemaVersion(),
+		OccurredAt: time.Now().UTC(),
+		Data:       data,
+	}, nil
+}
+
+// Decode extrait le contenu de l'enveloppe dans p, qui doit être du même type.
+// Une version plus récente que celle connue du consommateur est refusée plutôt que mal lue.
+func (e Envelope) Decode(p Payload) error {
+	if e.Type != p.EventType() {
+		return fmt.Errorf("event type mismatch: got %s, want %s", e.Type, p.EventType())
+	}
+	if e.Version > p.SchemaVersion() {
+		return fmt.Errorf("unsupported %s schema version %d (max %d)", e.Type, e.Version, p.SchemaVersion())
+	}
+	if err := json.Unmarshal(e.Data, p); err != nil {
+		return fmt.Errorf("failed to decode %s event: %w", e.Type, err)
+	}
+	return nil
+}
+
+// Publisher publie des événements métier ; les implémentations passent par l'outbox
+type Publisher interface {
+	Publish(ctx context.Context, events ...Payload) error
+}
+
+// ReportCreated : nouveau signalement enregistré
+type ReportCreated struct {
+	ReportID     string    `json:"report_id"`
+	ObserverID   string    `json:"observer_id"`
+	IncidentType string    `json:"incident_type"`
+	Severity     int       `json:"severity"`
+	RegionID     string    `json:"region_id,omitempty"`
+	H3Index      string    `json:"h3_index"`
+	GPSLocation  string    `json:"gps_location"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+func (ReportCreated) EventType() Type    { return TypeReportCreated }
+func (ReportCreated) SchemaVersion() int { return 1 }
+
+// Sources d'un changement de statut de signalement
+const (
+	SourceAdmin         = "admin"
+	SourceTriangulation = "triangulation"
+)
+
+// ReportStatusChanged : statut d'un signalement modifié par un administrateur ou la triangulation
+type ReportStatusChanged struct {
+	ReportID     string `json:"report_id"`
+	OldStatus    string `json:"old_status"`
+	NewStatus    string `json:"new_status"`
+	IncidentType string `json:"incident_type"`
+	Severity     int    `json:"severity"`
+	RegionID     string `json:"region_id,omitempty"`
+	Source       string `json:"source"`
+	ChangedBy    string `json:"changed_by,omitempty"`
+}
+
+func (ReportStatusChanged) EventType() Type    { return TypeReportStatusChanged }
+func (ReportStatusChanged) SchemaVersion() int { return 1 }
+
+// ReportTriangulated : décision de la triangulation (vérifié, conflit ou cluster suspect).
+// Les signalements restés sous le seuil ne produisent pas d'événement.
+type ReportTriangulated struct {
+	ReportID         string  `json:"report_id"`
+	Outcome          string  `json:"outcome"`
+	Score            float64 `json:"score"`
+	IndependentScore float64 `json:"independent_score"`
+	NeighborCount    int     `json:"neighbor_count"`
+	IncidentType     string  `json:"incident_type"`
+	Severity         int     `json:"severity"`
+	RegionID         string  `json:"region_id,omitempty"`
+}
+
+func (ReportTriangulated) EventType() Type    { return TypeReportTriangulated }
+func (ReportTriangulated) SchemaVersion() int { return 1 }
+
+// Types liste les événements publiés, pour la validation des abonnements
+var Types = []Type{
+	TypeReportCreated,
+	TypeReportStatusChanged,
+	TypeReportTriangulated,
+	TypeEvidenceUploaded,
+	TypeElectionStatusChanged,
+	TypeUserRoleChanged,
+	TypeAlertTriggered,
+	TypeAlertStatusChanged,
+	TypeConfigChanged,
+}
+
+// EvidenceUploaded : preuve (photo, document) rattachée à un signalement
+type EvidenceUploaded struct {
+	ReportID string `json:"report_id"`
+	ProofURL string `json:"proof_url"`
+	RegionID string `json:"region_id,omitempty"`
+}
+
+func (EvidenceUploaded) EventType() Type    { return TypeEvidenceUploaded }
+func (EvidenceUploaded) SchemaVersion() int { return 1 }
+
+// ElectionStatusChanged : cycle de vie d'un scrutin (planned → active → closed → archived)
+type ElectionStatusChanged struct {
+	ElectionID string `json:"election_id"`
+	OldStatus  string `json:"old_status"`
+	NewStatus  string `json:"new_status"`
+	ChangedBy  string `json:"changed_by,omitempty"`
+}
+
+func (ElectionStatusChanged) EventType() Type    { return TypeElectionStatusChanged }
+func (ElectionStatusChanged) SchemaVersion() int { return 1 }
+
+// UserRoleChanged : rôle ou région d'un utilisateur modifié par un administrateur
+type UserRoleChanged struct {
+	UserID    string `json:"user_id"`
+	OldRole   string `json:"old_role"`
+	NewRole   string `json:"new_role"`
+	RegionID  string `json:"region_id,omitempty"`
+	ChangedBy string `json:"changed_by,omitempty"`
+}
+
+func (UserRoleChanged) EventType() Type    { return TypeUserRoleChanged }
+func (UserRoleChanged) SchemaVersion() int { return 1 }
+
+// AlertTriggered : une règle d'alerte vient de se déclencher sur une zone.
+// Channels reprend les canaux de la règle ("dashboard", "webhook"...).
+type AlertTriggered struct {
+	AlertID     string   `json:"alert_id"`
+	RuleID      string   `json:"rule_id"`
+	RuleName    string   `json:"rule_name"`
+	GroupKey    string   `json:"group_key"`
+	Severity    int      `json:"severity"`
+	RegionID    string   `json:"region_id,omitempty"`
+	ReportIDs   []string `json:"report_ids"`
+	ReportCount int      `json:"report_count"`
+	Channels    []string `json:"channels"`
+}
+
+func (AlertTriggered) EventType() Type    { return TypeAlertTriggered }
+func (AlertTriggered) SchemaVersion() int { return 1 }
+
+// AlertStatusChanged : alerte acquittée ou résolue par un coordinateur
+type AlertStatusChanged struct {
+	AlertID   string   `json:"alert_id"`
+	RuleID    string   `json:"rule_id"`
+	OldStatus string   `json:"old_status"`
+	NewStatus string   `json:"new_status"`
+	Severity  int      `json:"severity"`
+	RegionID  string   `json:"region_id,omitempty"`
+	ChangedBy string   `json:"changed_by,omitempty"`
+	Channels  []string `json:"channels"`
+}
+
+func (AlertStatusChanged) EventType() Type    { return TypeAlertStatusChanged }
+func (AlertStatusChanged) SchemaVersion() int { return 1 }
+
+// ConfigChanged : nouvelle version d'une section de la configuration système
+type ConfigChanged struct {
+	Section        string `json:"section"`
+	Version        int64  `json:"version"`
+	ChangedBy      string `json:"changed_by,omitempty"`
+	RolledBackFrom int64  `json:"rolled_back_from,omitempty"`
+}
+
+func (ConfigChanged) EventType() Type    { return TypeConfigChanged }
+func (ConfigChanged) SchemaVersion() int { return 1 }
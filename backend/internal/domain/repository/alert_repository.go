@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+)
+
+// AlertReportFilter sélectionne les signalements candidats d'une règle sur sa fenêtre
+type AlertReportFilter struct {
+	IncidentTypes []string
+	MinSeverity   int
+	RegionIDs     []string
+	Since         time.Time
+	Until         time.Time
+	// WithDepartment renseigne le département du bureau de vote le plus proche de chaque signalement
+	WithDepartment bool
+}
+
+// AlertFilter filtre la liste des alertes (champs vides : pas de filtre)
+type AlertFilter struct {
+	Status   string
+	RegionID string
+	RuleID   string
+	Limit    int
+}
+
+// AlertRepository gère les règles d'alerte et les alertes déclenchées
+type AlertRepository interface {
+	CreateRule(ctx context.Context, rule *entity.AlertRule) error
+	UpdateRule(ctx context.Context, rule *entity.AlertRule) error
+	DeleteRule(ctx context.Context, id string) error
+	GetRule(ctx context.Context, id string) (*entity.AlertRule, error)
+	ListRules(ctx context.Context) ([]entity.AlertRule, error)
+	ActiveRules(ctx context.Context) ([]entity.AlertRule, error)
+
+	// ReportsInWindow retourne les signalements correspondant au filtre (région de l'observateur renseignée,
+	// département sur demande)
+	ReportsInWindow(ctx context.Context, filter AlertReportFilter) ([]entity.Report, error)
+
+	// UpsertOpenAlert crée l'alerte, ou met à jour l'alerte ouverte de la même règle et de la même
+	// zone (une alerte acquittée n'est plus alimentée) ; retourne true si elle vient d'être créée
+	UpsertOpenAlert(ctx context.Context, alert *entity.Alert) (bool, error)
+	GetAlert(ctx context.Context, id string) (*entity.Alert, error)
+	ListAlerts(ctx context.Context, filter AlertFilter) ([]entity.Alert, error)
+	// UpdateAlertStatus applique un acquittement ou une résolution (by, note)
+	UpdateAlertStatus(ctx context.Context, id string, status entity.AlertStatus, by, note string) error
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/openvote/backend/internal/domain/entity"
+)
+
+// ErrConfigVersionConflict : la section a été modifiée depuis la version lue par l'appelant
+var ErrConfigVersionConflict = errors.New("config section was modified concurrently")
+
+// ConfigRepository stocke l'historique des versions de la configuration système
+type ConfigRepository interface {
+	// Latest retourne la dernière version de chaque section enregistrée
+	Latest(ctx context.Context) ([]entity.ConfigVersion, error)
+	// CurrentVersion retourne le plus grand numéro de version (0 si aucune), toutes sections confondues
+	CurrentVersion(ctx context.Context) (int64, error)
+	// GetVersion retourne nil si la version n'existe pas
+	GetVersion(ctx context.Context, version int64) (*entity.ConfigVersion, error)
+	History(ctx context.Context, section string, limit int) ([]entity.ConfigVersion, error)
+	// Append ajoute une version si la dernière version de la section est toujours baseVersion
+	// (0 : la section n'a jamais été enregistrée) ; sinon ErrConfigVersionConflict
+	Append(ctx context.Context, v *entity.ConfigVersion, baseVersion int64) error
+	// AppendAll ajoute plusieurs versions (une par section) dans une même transaction : si une seule
+	// section a changé depuis sa version de base (baseVersions[section]), aucune n'est écrite
+	AppendAll(ctx context.Context, versions []*entity.ConfigVersion, baseVersions map[string]int64) error
+}
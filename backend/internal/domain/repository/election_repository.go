@@ -15,12 +15,17 @@ type ElectionRepository interface {
 }
 
 type AuditLogRepository interface {
+	// Create chaîne l'entrée à la précédente (ID, CreatedAt, Seq et empreintes sont renseignés)
 	Create(ctx context.Context, log *entity.AuditLog) error
 	GetAll(ctx context.Context, limit int) ([]entity.AuditLog, error)
+	// ListChain retourne les entrées dans l'ordre d'insertion à partir de afterSeq (exclu)
+	ListChain(ctx context.Context, afterSeq int64, limit int) ([]entity.AuditLog, error)
 }
 
 type IncidentTypeRepository interface {
 	GetAll(ctx context.Context) ([]entity.IncidentType, error)
+	// FindByCodeOrName résout le type saisi sur un signalement (code ou libellé)
+	FindByCodeOrName(ctx context.Context, value string) (*entity.IncidentType, error)
 	Create(ctx context.Context, it *entity.IncidentType) error
 	Update(ctx context.Context, it *entity.IncidentType) error
 	Delete(ctx context.Context, id string) error
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+)
+
+// IncidentEventRepository persiste les événements consolidés et leur rattachement aux signalements
+type IncidentEventRepository interface {
+	// FindCandidate retourne l'événement actif du même type, proche dans l'espace (même tuile H3
+	// ou centroïde dans le rayon) et dont la période chevauche [start, end]
+	FindCandidate(ctx context.Context, incidentType, h3Index string, lat, lon, radius float64, start, end time.Time) (*entity.IncidentEvent, error)
+	// AssignReport rattache le signalement à l'événement candidat, ou crée l'événement fallback
+	// s'il n'y en a pas, en une transaction sérialisée sur lockKey (type et zone). Un signalement
+	// déjà rattaché reste à son événement. Retourne l'événement et s'il vient d'être créé.
+	AssignReport(ctx context.Context, reportID, lockKey string, candidate EventCandidate, fallback *entity.IncidentEvent) (*entity.IncidentEvent, bool, error)
+	Create(ctx context.Context, event *entity.IncidentEvent) error
+	// AttachReports rattache des signalements à l'événement et recalcule ses agrégats
+	AttachReports(ctx context.Context, eventID string, reportIDs []string) error
+	GetByID(ctx context.Context, id string) (*entity.IncidentEvent, error)
+	GetAll(ctx context.Context, status string) ([]entity.IncidentEvent, error)
+	// Merge déplace les signalements des sources vers la cible et marque les sources comme fusionnées
+	Merge(ctx context.Context, targetID string, sourceIDs []string) error
+	// Split détache des signalements vers un nouvel événement et recalcule les deux
+	Split(ctx context.Context, eventID string, reportIDs []string) (*entity.IncidentEvent, error)
+}
+
+// EventCandidate décrit la recherche d'un événement existant pour un signalement (voir FindCandidate)
+type EventCandidate struct {
+	IncidentType string
+	H3Index      string
+	Lat, Lon     float64
+	Radius       float64
+	Start, End   time.Time
+}
@@ -32,4 +32,6 @@ type LegalRepository interface {
 	// Analyses juridiques LLM
 	SaveAnalysis(ctx context.Context, analysis *entity.LegalAnalysis) error
 	GetAnalysisByReport(ctx context.Context, reportID string) (*entity.LegalAnalysis, error)
+	SaveEventAnalysis(ctx context.Context, analysis *entity.LegalAnalysis) error
+	GetAnalysisByEvent(ctx context.Context, eventID string) (*entity.LegalAnalysis, error)
 }
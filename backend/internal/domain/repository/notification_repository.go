@@ -0,0 +1,32 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+)
+
+// NotificationRepository gère les préférences, la boîte de réception et le journal des envois
+type NotificationRepository interface {
+	// GetPreferences retourne nil si l'utilisateur n'a jamais enregistré de préférences
+	GetPreferences(ctx context.Context, userID string) (*entity.NotificationPreferences, error)
+	SavePreferences(ctx context.Context, prefs *entity.NotificationPreferences) error
+
+	CreateInboxItem(ctx context.Context, n *entity.Notification) error
+	ListInbox(ctx context.Context, userID string, unreadOnly bool, limit int) ([]entity.Notification, error)
+	CountUnread(ctx context.Context, userID string) (int, error)
+	// MarkRead marque un message (ou tous si id est vide) comme lu ; retourne le nombre de messages modifiés
+	MarkRead(ctx context.Context, userID, id string) (int, error)
+
+	// CreateDelivery ignore un doublon (même utilisateur, canal et clé) et retourne false
+	CreateDelivery(ctx context.Context, d *entity.NotificationDelivery) (bool, error)
+	// ClaimDueDeliveries réserve les envois échus pendant la durée du bail (SKIP LOCKED)
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.NotificationDelivery, error)
+	MarkSent(ctx context.Context, id string) error
+	// MarkFailed consigne l'échec ; retryAt nil signifie abandon (statut failed)
+	MarkFailed(ctx context.Context, id, cause string, retryAt *time.Time) error
+	ListDeliveries(ctx context.Context, status string, limit int) ([]entity.NotificationDelivery, error)
+	// ResetDelivery remet un envoi en attente immédiate ; false si l'envoi n'existe pas
+	ResetDelivery(ctx context.Context, id string) (bool, error)
+}
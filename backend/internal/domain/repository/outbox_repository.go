@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+)
+
+// OutboxRepository gère les messages en attente de publication vers le broker
+type OutboxRepository interface {
+	// Enqueue écrit des messages hors de toute transaction métier (une seule transaction pour le lot)
+	Enqueue(ctx context.Context, messages ...*entity.OutboxMessage) error
+	// Claim réserve jusqu'à limit messages publiables pendant la durée du bail,
+	// sans bloquer les autres relais (SKIP LOCKED)
+	Claim(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxMessage, error)
+	MarkSent(ctx context.Context, id string) error
+	// MarkFailed consigne l'erreur et reporte la prochaine tentative
+	MarkFailed(ctx context.Context, id string, cause string, retryAfter time.Duration) error
+	// PurgeSent supprime les messages publiés plus anciens que la rétention
+	PurgeSent(ctx context.Context, olderThan time.Duration) (int64, error)
+}
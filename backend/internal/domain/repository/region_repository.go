@@ -19,3 +19,11 @@ type RegionRepository interface {
 	UpdateDepartment(ctx context.Context, id, name, code, regionID string, population, voters int) error
 	DeleteDepartment(ctx context.Context, id string) error
 }
+
+// PollingStationRepository gère les bureaux de vote, identifiés par leur code
+type PollingStationRepository interface {
+	GetAll(ctx context.Context) ([]entity.PollingStation, error)
+	GetByDepartment(ctx context.Context, departmentID string) ([]entity.PollingStation, error)
+	// Upsert crée le bureau ou met à jour celui qui porte le même code ; retourne true si créé
+	Upsert(ctx context.Context, station *entity.PollingStation) (bool, error)
+}
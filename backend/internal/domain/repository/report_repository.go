@@ -9,8 +9,15 @@ import (
 
 type ReportRepository interface {
 	Create(ctx context.Context, report *entity.Report) error
+	// CreateWithOutbox enregistre le signalement et ses messages de publication dans une même transaction
+	CreateWithOutbox(ctx context.Context, report *entity.Report, messages ...*entity.OutboxMessage) error
 	GetAll(ctx context.Context, status string) ([]entity.Report, error)
 	GetByID(ctx context.Context, id string) (*entity.Report, error)
 	FindNearbyWithRole(ctx context.Context, h3Index string, lat, lon, radius float64, start, end time.Time) ([]entity.Report, error)
 	UpdateStatus(ctx context.Context, id string, status entity.ReportStatus) error
+	// UpdateStatusWithOutbox change le statut et enregistre ses messages de publication dans une même transaction.
+	// Sans signalement correspondant, rien n'est écrit.
+	UpdateStatusWithOutbox(ctx context.Context, id string, status entity.ReportStatus, messages ...*entity.OutboxMessage) error
+	// GetReviewQueue retourne les signalements en attente triés par sévérité, corroboration puis ancienneté
+	GetReviewQueue(ctx context.Context, limit int) ([]entity.ReviewQueueItem, error)
 }
@@ -0,0 +1,37 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/openvote/backend/internal/domain/entity"
+)
+
+// SuspiciousClusterRepository persiste les clusters Sybil en attente de revue humaine
+type SuspiciousClusterRepository interface {
+	// Upsert crée ou remplace le cluster associé au signalement cible. Un cluster déjà revu garde
+	// sa décision, sauf si de nouveaux membres le rejoignent : il repasse alors en revue.
+	Upsert(ctx context.Context, cluster *entity.SuspiciousCluster) error
+	GetAll(ctx context.Context, status string) ([]entity.SuspiciousCluster, error)
+	GetByID(ctx context.Context, id string) (*entity.SuspiciousCluster, error)
+	// FindByReport retourne les clusters dont le signalement est la cible ou un membre
+	FindByReport(ctx context.Context, reportID string) ([]entity.SuspiciousCluster, error)
+	UpdateStatus(ctx context.Context, id string, status entity.ClusterReviewStatus, reviewerID, note string) error
+}
+
+// ConflictRepository persiste les conflits de types d'incidents détectés par la triangulation
+type ConflictRepository interface {
+	// Create enregistre le conflit et ses liaisons vers les signalements
+	Create(ctx context.Context, conflict *entity.Conflict) error
+	// FindOpenByReports retourne un conflit ouvert impliquant au moins un des signalements
+	FindOpenByReports(ctx context.Context, reportIDs []string) (*entity.Conflict, error)
+	// FindResolvedCovering retourne le dernier conflit résolu qui couvre déjà tous les
+	// signalements et tous les types donnés (désaccord déjà arbitré)
+	FindResolvedCovering(ctx context.Context, reportIDs []string, incidentTypes []string) (*entity.Conflict, error)
+	// AddReports rattache de nouveaux signalements (et types) à un conflit existant
+	AddReports(ctx context.Context, conflictID string, reportIDs []string, incidentTypes []string) error
+	GetByID(ctx context.Context, id string) (*entity.Conflict, error)
+	GetAll(ctx context.Context, status string) ([]entity.Conflict, error)
+	HasOpenConflict(ctx context.Context, reportID string) (bool, error)
+	Assign(ctx context.Context, id, assigneeID string) error
+	Resolve(ctx context.Context, id, resolverID, note string) error
+}
@@ -12,5 +12,7 @@ type UserRepository interface {
 	GetAll(ctx context.Context) ([]entity.User, error)
 	UpdateRole(ctx context.Context, id string, role entity.UserRole, regionID string) error
 	UpdateLastLogin(ctx context.Context, id string) error
+	// UpdatePasswordHash remplace le mot de passe ou le PIN (déjà haché)
+	UpdatePasswordHash(ctx context.Context, id, passwordHash string) error
 	Delete(ctx context.Context, id string) error
 }
@@ -0,0 +1,33 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+)
+
+// WebhookRepository gère les abonnements webhooks et le journal de leurs livraisons
+type WebhookRepository interface {
+	CreateSubscription(ctx context.Context, sub *entity.WebhookSubscription) error
+	UpdateSubscription(ctx context.Context, sub *entity.WebhookSubscription) error
+	DeleteSubscription(ctx context.Context, id string) error
+	// UpdateSecret remplace le secret de signature (rotation)
+	UpdateSecret(ctx context.Context, id, secret string) error
+	GetSubscription(ctx context.Context, id string) (*entity.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]entity.WebhookSubscription, error)
+	// ActiveSubscriptions retourne les abonnements actifs portant sur ce type d'événement
+	ActiveSubscriptions(ctx context.Context, eventType string) ([]entity.WebhookSubscription, error)
+
+	// CreateDelivery ignore un doublon (même abonnement, même événement) et retourne false
+	CreateDelivery(ctx context.Context, d *entity.WebhookDelivery) (bool, error)
+	// ClaimDueDeliveries réserve les livraisons échues pendant la durée du bail (SKIP LOCKED)
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.WebhookDelivery, error)
+	MarkDelivered(ctx context.Context, id string, responseStatus int) error
+	// MarkFailed consigne l'échec ; retryAt nil signifie tentatives épuisées (statut failed)
+	MarkFailed(ctx context.Context, id string, responseStatus int, cause string, retryAt *time.Time) error
+	GetDelivery(ctx context.Context, id string) (*entity.WebhookDelivery, error)
+	ListDeliveries(ctx context.Context, subscriptionID, status string, limit int) ([]entity.WebhookDelivery, error)
+	// ResetDelivery remet une livraison en attente immédiate avec un compteur de tentatives à zéro
+	ResetDelivery(ctx context.Context, id string) error
+}
@@ -2,22 +2,19 @@ package database
 
 import (
 	"database/sql"
-	"fmt"
-	"os"
 	_ "github.com/lib/pq"
-)
-
-func NewPostgresDB() (*sql.DB, error) {
-	host := os.Getenv("DB_HOST")
-	port := os.Getenv("DB_PORT")
-	user := os.Getenv("DB_USER")
-	password := os.Getenv("DB_PASSWORD")
-	dbname := os.Getenv("DB_NAME")
 
-	dsn := fmt.Sprintf("host=%s port=%s user=%s password=%s dbname=%s sslmode=disable", 
-		host, port, user, password, dbname)
+	"github.com/XSAM/otelsql"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
 
-	db, err := sql.Open("postgres", dsn)
+// NewPostgresDB ouvre et vérifie la connexion décrite par dsn (config.Database.DSN())
+func NewPostgresDB(dsn string) (*sql.DB, error) {
+	// Chaque requête produit un span, rattaché au span du service appelant via le contexte
+	db, err := otelsql.Open("postgres", dsn,
+		otelsql.WithAttributes(semconv.DBSystemPostgreSQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{OmitConnResetSession: true, OmitRows: true}),
+	)
 	if err != nil {
 		return nil, err
 	}
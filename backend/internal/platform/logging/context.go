@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"context"
+	"regexp"
+)
+
+// HeaderRequestID transporte l'identifiant de requête : en-tête HTTP (fourni par le client ou
+// le proxy, renvoyé dans la réponse) et en-tête des messages de file (minuscules, comme les
+// en-têtes de trace)
+const (
+	HeaderRequestID  = "X-Request-ID"
+	MessageRequestID = "x-request-id"
+)
+
+type requestIDKey struct{}
+
+// validRequestID écarte les identifiants fournis par le client qui pollueraient le journal
+var validRequestID = regexp.MustCompile(`^[A-Za-z0-9._-]{1,64}$`)
+
+// ValidRequestID indique si un identifiant reçu peut être repris tel quel
+func ValidRequestID(id string) bool {
+	return validRequestID.MatchString(id)
+}
+
+// WithRequestID rattache l'identifiant de requête au contexte : chaque entrée de journal
+// écrite avec ce contexte le reprend (request_id)
+func WithRequestID(ctx context.Context, id string) context.Context {
+	if id == "" {
+		return ctx
+	}
+	return context.WithValue(ctx, requestIDKey{}, id)
+}
+
+// RequestID retourne l'identifiant de requête du contexte, vide s'il n'y en a pas
+func RequestID(ctx context.Context) string {
+	id, _ := ctx.Value(requestIDKey{}).(string)
+	return id
+}
+
+// InjectRequestID ajoute l'identifiant de requête de ctx aux en-têtes d'un message
+func InjectRequestID(ctx context.Context, headers map[string]string) map[string]string {
+	id := RequestID(ctx)
+	if id == "" {
+		return headers
+	}
+	if headers == nil {
+		headers = map[string]string{}
+	}
+	headers[MessageRequestID] = id
+	return headers
+}
+
+// ExtractRequestID rattache à ctx l'identifiant de requête transporté par un message
+func ExtractRequestID(ctx context.Context, headers map[string]string) context.Context {
+	if id := headers[MessageRequestID]; ValidRequestID(id) {
+		return WithRequestID(ctx, id)
+	}
+	return ctx
+}
@@ -0,0 +1,50 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"regexp"
+	"strings"
+)
+
+// legacyLine reconnaît la convention historique "[COMPOSANT] message"
+var legacyLine = regexp.MustCompile(`^\[([A-Za-z0-9_-]+)\] ?(.*)$`)
+
+// legacyErrorWords signalent une entrée d'erreur dans le texte d'un appel log.Printf
+var legacyErrorWords = []string{"error", "erreur", "could not", "impossible", "failed", "échec", "abandon"}
+
+// legacyWriter reçoit la sortie du paquet log et la réécrit en entrée structurée : le préfixe
+// [COMPOSANT] devient l'attribut component (et en règle le niveau), "Warning" un
+// avertissement et les messages d'échec des erreurs
+type legacyWriter struct{}
+
+func (legacyWriter) Write(p []byte) (int, error) {
+	component, level, msg := parseLegacy(string(p))
+	For(component).Log(context.Background(), level, msg)
+	return len(p), nil
+}
+
+func parseLegacy(line string) (component string, level slog.Level, msg string) {
+	msg = strings.TrimRight(line, "\n")
+	if m := legacyLine.FindStringSubmatch(msg); m != nil {
+		component, msg = strings.ToLower(m[1]), m[2]
+	}
+
+	lower := strings.ToLower(msg)
+	switch {
+	case strings.HasPrefix(lower, "warning"):
+		return component, slog.LevelWarn, msg
+	case containsAny(lower, legacyErrorWords):
+		return component, slog.LevelError, msg
+	}
+	return component, slog.LevelInfo, msg
+}
+
+func containsAny(s string, words []string) bool {
+	for _, w := range words {
+		if strings.Contains(s, w) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,138 @@
+// Package logging fournit le journal structuré (slog) de l'API et du worker : sortie JSON,
+// niveau par composant, identifiant de requête et de trace repris du contexte, et masquage
+// des champs sensibles (description, position GPS, nom d'utilisateur, jetons...).
+//
+// Les composants obtiennent leur logger avec For("queue") ; le niveau de chacun se règle par
+// Config.Levels (LOG_LEVELS="queue=debug,triangulation=warn" via le paquet config), Config.Level
+// fixant celui des autres. Les appels historiques log.Printf("[QUEUE] ...") passent par le
+// même chemin (voir legacy.go).
+package logging
+
+import (
+	"context"
+	"io"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Formats de sortie (LOG_FORMAT)
+const (
+	FormatJSON = "json"
+	FormatText = "text"
+)
+
+// Config règle la sortie du journal
+type Config struct {
+	Format string
+	// Level : niveau par défaut des composants
+	Level slog.Level
+	// Levels : niveau propre à certains composants ("queue" -> debug)
+	Levels map[string]slog.Level
+}
+
+// state est la configuration active, partagée par tous les loggers déjà créés
+type state struct {
+	handler slog.Handler
+	level   slog.Level
+	levels  map[string]slog.Level
+}
+
+func (s *state) levelFor(component string) slog.Level {
+	if level, ok := s.levels[component]; ok {
+		return level
+	}
+	return s.level
+}
+
+var current atomic.Pointer[state]
+
+func init() {
+	// Avant Setup (tests, outils) : texte sur la sortie d'erreur, champs sensibles déjà masqués
+	current.Store(&state{
+		handler: slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelDebug, ReplaceAttr: Redact}),
+		level:   slog.LevelInfo,
+	})
+}
+
+// Setup installe la configuration pour le service ("openvote-api", "openvote-worker") :
+// slog.Default et le paquet log écrivent désormais dans w au format choisi
+func Setup(w io.Writer, service string, cfg Config) {
+	opts := &slog.HandlerOptions{Level: slog.LevelDebug, ReplaceAttr: Redact} // Filtrage par composant en amont
+	var handler slog.Handler
+	if cfg.Format == FormatText {
+		handler = slog.NewTextHandler(w, opts)
+	} else {
+		handler = slog.NewJSONHandler(w, opts)
+	}
+	if service != "" {
+		handler = handler.WithAttrs([]slog.Attr{slog.String("service", service)})
+	}
+	levels := cfg.Levels
+	if levels == nil {
+		levels = map[string]slog.Level{}
+	}
+	current.Store(&state{handler: handler, level: cfg.Level, levels: levels})
+
+	slog.SetDefault(slog.New(&componentHandler{}))
+	// Après SetDefault, qui redirige lui-même le paquet log
+	log.SetFlags(0)
+	log.SetOutput(legacyWriter{})
+}
+
+// For retourne le logger d'un composant ("queue", "triangulation"...) ; il suit les
+// changements de configuration, et peut donc être créé à l'initialisation du paquet
+func For(component string) *slog.Logger {
+	return slog.New(&componentHandler{component: strings.ToLower(component)})
+}
+
+// componentHandler résout la configuration active à chaque entrée : niveau du composant,
+// attributs de contexte (requête, trace), puis sortie masquée
+type componentHandler struct {
+	component string
+	// ops rejoue les With/WithGroup sur le handler de sortie courant
+	ops []func(slog.Handler) slog.Handler
+}
+
+func (h *componentHandler) Enabled(ctx context.Context, level slog.Level) bool {
+	return level >= current.Load().levelFor(h.component)
+}
+
+func (h *componentHandler) Handle(ctx context.Context, r slog.Record) error {
+	handler := current.Load().handler
+	if h.component != "" {
+		handler = handler.WithAttrs([]slog.Attr{slog.String("component", h.component)})
+	}
+	// Corrélation ajoutée avant les groupes éventuels, au premier niveau de l'entrée
+	if id := RequestID(ctx); id != "" {
+		handler = handler.WithAttrs([]slog.Attr{slog.String("request_id", id)})
+	}
+	if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+		handler = handler.WithAttrs([]slog.Attr{
+			slog.String("trace_id", sc.TraceID().String()),
+			slog.String("span_id", sc.SpanID().String()),
+		})
+	}
+	for _, op := range h.ops {
+		handler = op(handler)
+	}
+	return handler.Handle(ctx, r)
+}
+
+func (h *componentHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithAttrs(attrs) })
+}
+
+func (h *componentHandler) WithGroup(name string) slog.Handler {
+	return h.with(func(next slog.Handler) slog.Handler { return next.WithGroup(name) })
+}
+
+func (h *componentHandler) with(op func(slog.Handler) slog.Handler) slog.Handler {
+	ops := make([]func(slog.Handler) slog.Handler, len(h.ops), len(h.ops)+1)
+	copy(ops, h.ops)
+	return &componentHandler{component: h.component, ops: append(ops, op)}
+}
@@ -0,0 +1,173 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log"
+	"log/slog"
+	"os"
+	"strings"
+	"testing"
+
+	"go.opentelemetry.io/otel/trace"
+)
+
+// capture installe une sortie JSON en mémoire et restaure la configuration à la fin du test
+func capture(t *testing.T, cfg Config) *bytes.Buffer {
+	t.Helper()
+	previous, defaultLogger := current.Load(), slog.Default()
+	t.Cleanup(func() {
+		current.Store(previous)
+		slog.SetDefault(defaultLogger)
+		log.SetFlags(log.LstdFlags)
+		log.SetOutput(os.Stderr)
+	})
+	var buf bytes.Buffer
+	Setup(&buf, "openvote-test", cfg)
+	return &buf
+}
+
+// entries décode les lignes JSON écrites depuis le début du test
+func entries(t *testing.T, buf *bytes.Buffer) []map[string]any {
+	t.Helper()
+	var out []map[string]any
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if line == "" {
+			continue
+		}
+		var entry map[string]any
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid JSON line %q: %v", line, err)
+		}
+		out = append(out, entry)
+	}
+	return out
+}
+
+func TestRedactsSensitiveFields(t *testing.T) {
+	buf := capture(t, Config{Format: FormatJSON, Level: slog.LevelInfo})
+
+	For("reports").Info("report received",
+		"report_id", "r-1",
+		"description", "Bourrage d'urne au bureau 12",
+		"GPS_Location", "POINT(9.70 4.05)",
+		"admin_username", "jdoe",
+		"access_token", "eyJhbGciOi",
+		slog.Group("observer", "username", "obs-12", "region", "Littoral"),
+	)
+
+	got := entries(t, buf)
+	if len(got) != 1 {
+		t.Fatalf("expected 1 entry, got %d: %s", len(got), buf)
+	}
+	entry := got[0]
+	for _, key := range []string{"description", "GPS_Location", "admin_username", "access_token"} {
+		if entry[key] != Redacted {
+			t.Errorf("%s = %v, want %s", key, entry[key], Redacted)
+		}
+	}
+	observer, _ := entry["observer"].(map[string]any)
+	if observer["username"] != Redacted || observer["region"] != "Littoral" {
+		t.Errorf("unexpected group: %v", observer)
+	}
+	if entry["report_id"] != "r-1" || entry["component"] != "reports" || entry["service"] != "openvote-test" {
+		t.Errorf("unexpected entry: %v", entry)
+	}
+	for _, secret := range []string{"Bourrage", "9.70", "jdoe", "eyJhbGciOi", "obs-12"} {
+		if strings.Contains(buf.String(), secret) {
+			t.Errorf("output leaks %q: %s", secret, buf)
+		}
+	}
+}
+
+func TestPerComponentLevels(t *testing.T) {
+	buf := capture(t, Config{Format: FormatJSON, Level: slog.LevelWarn, Levels: map[string]slog.Level{"queue": slog.LevelDebug}})
+
+	// Logger créé avant Setup : il suit la nouvelle configuration
+	queue := For("Queue")
+	queue.Debug("queue detail")
+	For("triangulation").Info("triangulation info")
+	For("triangulation").Warn("triangulation warning")
+
+	var msgs []string
+	for _, entry := range entries(t, buf) {
+		msgs = append(msgs, entry["msg"].(string))
+	}
+	if strings.Join(msgs, "|") != "queue detail|triangulation warning" {
+		t.Errorf("unexpected entries: %v", msgs)
+	}
+}
+
+func TestContextCorrelation(t *testing.T) {
+	buf := capture(t, Config{Format: FormatJSON, Level: slog.LevelInfo})
+
+	traceID, _ := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	spanID, _ := trace.SpanIDFromHex("00f067aa0ba902b7")
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: traceID, SpanID: spanID, TraceFlags: trace.FlagsSampled,
+	}))
+	ctx = WithRequestID(ctx, "req-42")
+
+	For("worker").With("queue", "reports.high").WithGroup("job").InfoContext(ctx, "processing", "attempt", 2)
+
+	entry := entries(t, buf)[0]
+	if entry["request_id"] != "req-42" || entry["trace_id"] != traceID.String() || entry["span_id"] != spanID.String() {
+		t.Errorf("missing correlation: %v", entry)
+	}
+	if entry["queue"] != "reports.high" {
+		t.Errorf("With attributes lost: %v", entry)
+	}
+	if job, _ := entry["job"].(map[string]any); job["attempt"] != float64(2) {
+		t.Errorf("group lost: %v", entry)
+	}
+}
+
+func TestRequestIDHeaders(t *testing.T) {
+	if headers := InjectRequestID(context.Background(), nil); headers != nil {
+		t.Errorf("no request ID should leave headers untouched, got %v", headers)
+	}
+
+	ctx := WithRequestID(context.Background(), "abc-123")
+	headers := InjectRequestID(ctx, map[string]string{"traceparent": "00-x"})
+	if headers[MessageRequestID] != "abc-123" || headers["traceparent"] != "00-x" {
+		t.Fatalf("unexpected headers: %v", headers)
+	}
+	if got := RequestID(ExtractRequestID(context.Background(), headers)); got != "abc-123" {
+		t.Errorf("round trip = %q", got)
+	}
+
+	// Identifiant forgé (saut de ligne, longueur) : ignoré
+	for _, bad := range []string{"a\nb", strings.Repeat("x", 65), "<script>"} {
+		if got := RequestID(ExtractRequestID(context.Background(), map[string]string{MessageRequestID: bad})); got != "" {
+			t.Errorf("%q accepted as request ID", bad)
+		}
+	}
+}
+
+func TestLegacyBridge(t *testing.T) {
+	buf := capture(t, Config{Format: FormatJSON, Level: slog.LevelInfo, Levels: map[string]slog.Level{"outbox": slog.LevelError}})
+
+	log.Printf("[CONFIG] Sections chargées: %v", []string{"alerts"})
+	log.Printf("[WORKER] Warning: pool saturé")
+	log.Printf("[NOTIFY] Erreur d'envoi: timeout")
+	log.Printf("[OUTBOX] Démarrage du relais") // Sous le niveau du composant
+	log.Printf("sans préfixe")
+
+	got := entries(t, buf)
+	want := []struct{ component, level, msg string }{
+		{"config", "INFO", "Sections chargées: [alerts]"},
+		{"worker", "WARN", "Warning: pool saturé"},
+		{"notify", "ERROR", "Erreur d'envoi: timeout"},
+		{"", "INFO", "sans préfixe"},
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d entries, got %d: %s", len(want), len(got), buf)
+	}
+	for i, w := range want {
+		component, _ := got[i]["component"].(string)
+		if component != w.component || got[i]["level"] != w.level || got[i]["msg"] != w.msg {
+			t.Errorf("entry %d = %v, want %+v", i, got[i], w)
+		}
+	}
+}
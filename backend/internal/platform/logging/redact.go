@@ -0,0 +1,61 @@
+package logging
+
+import (
+	"log/slog"
+	"strings"
+)
+
+// Redacted remplace la valeur des champs sensibles
+const Redacted = "[REDACTED]"
+
+// sensitiveKeys : champs jamais écrits en clair (données personnelles des observateurs,
+// contenu des signalements, secrets). La comparaison ignore la casse.
+var sensitiveKeys = map[string]bool{
+	"description":   true,
+	"gps":           true,
+	"gps_location":  true,
+	"location":      true,
+	"latitude":      true,
+	"longitude":     true,
+	"lat":           true,
+	"lon":           true,
+	"username":      true,
+	"full_name":     true,
+	"email":         true,
+	"phone":         true,
+	"password":      true,
+	"token":         true,
+	"access_token":  true,
+	"refresh_token": true,
+	"authorization": true,
+	"secret":        true,
+	"api_key":       true,
+	"prompt":        true,
+}
+
+// sensitiveSuffixes couvre les variantes préfixées ("admin_username", "webhook_secret")
+var sensitiveSuffixes = []string{"_username", "_password", "_token", "_secret", "_description", "_gps", "_prompt"}
+
+// IsSensitive indique si un champ de ce nom doit être masqué
+func IsSensitive(key string) bool {
+	key = strings.ToLower(key)
+	if sensitiveKeys[key] {
+		return true
+	}
+	for _, suffix := range sensitiveSuffixes {
+		if strings.HasSuffix(key, suffix) {
+			return true
+		}
+	}
+	return false
+}
+
+// Redact masque la valeur des champs sensibles, groupes compris ; s'utilise comme
+// slog.HandlerOptions.ReplaceAttr. Le texte libre des messages n'est pas analysé : les
+// données personnelles ne doivent jamais y être interpolées.
+func Redact(groups []string, a slog.Attr) slog.Attr {
+	if IsSensitive(a.Key) {
+		return slog.String(a.Key, Redacted)
+	}
+	return a
+}
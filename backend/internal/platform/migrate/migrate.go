@@ -0,0 +1,442 @@
+// Package migrate applique les migrations SQL versionnées du dossier migration/ :
+// une table schema_migrations trace les versions appliquées et l'empreinte (SHA-256)
+// de chaque fichier, pour détecter une migration modifiée après coup.
+//
+// Fichiers : NNN_nom.sql (montée) et, facultatif, NNN_nom.down.sql (descente).
+// Chaque migration est jouée dans une transaction avec son enregistrement, sauf si
+// le fichier commence par la directive "-- migrate:no-transaction" (CREATE INDEX CONCURRENTLY...).
+//
+// Une migration appliquée n'est jamais modifiée. Si son script est défaillant, une migration
+// ultérieure portant la directive "-- migrate:replaces NNN" en fournit la version corrigée :
+// elle est jouée à la place de NNN sur les bases où NNN est en attente, et n'exécute rien à
+// son propre rang.
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openvote/backend/internal/platform/logging"
+)
+
+var migrateLogger = logging.For("migrate")
+
+// NoTransactionDirective exclut une migration de la transaction englobante
+const NoTransactionDirective = "-- migrate:no-transaction"
+
+// lockID : verrou consultatif partagé par toutes les instances qui migrent la même base
+const lockID = 7_210_042_001
+
+var (
+	// ErrSchemaBehind : des migrations connues du binaire ne sont pas appliquées
+	ErrSchemaBehind = errors.New("database schema is behind")
+	// ErrChecksumMismatch : une migration appliquée a été modifiée depuis
+	ErrChecksumMismatch = errors.New("applied migration was modified")
+	// ErrIrreversible : pas de fichier .down.sql pour cette migration
+	ErrIrreversible = errors.New("migration has no down script")
+)
+
+// ReplacesDirective précède la version de la migration corrigée ("-- migrate:replaces 004")
+const ReplacesDirective = "-- migrate:replaces"
+
+var (
+	fileName = regexp.MustCompile(`^(\d+)_([a-z0-9_]+?)(\.down)?\.sql$`)
+	replaces = regexp.MustCompile(`(?m)^` + ReplacesDirective + ` (\d+)\s*$`)
+)
+
+// Migration est une version du schéma
+type Migration struct {
+	Version       int64
+	Name          string
+	Up            string
+	Down          string // Vide : migration irréversible
+	Checksum      string // SHA-256 du script de montée
+	NoTransaction bool
+	Replaces      int64  // Version dont ce script est la correction (0 : aucune)
+	Replacement   string // Script correctif joué à la place de Up
+}
+
+// script retourne le script de montée effectivement joué
+func (m Migration) script() string {
+	switch {
+	case m.Replaces != 0:
+		return "" // Déjà joué au rang de la migration corrigée
+	case m.Replacement != "":
+		return m.Replacement
+	}
+	return m.Up
+}
+
+// Load lit les migrations d'un dossier (os.DirFS("migration")) triées par version
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	byVersion := map[int64]*Migration{}
+	downs := map[int64]string{}
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+		m := fileName.FindStringSubmatch(entry.Name())
+		if m == nil {
+			return nil, fmt.Errorf("invalid migration file name %q (expected NNN_name.sql or NNN_name.down.sql)", entry.Name())
+		}
+		version, _ := strconv.ParseInt(m[1], 10, 64)
+		data, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		if m[3] != "" {
+			downs[version] = string(data)
+			continue
+		}
+		if existing, ok := byVersion[version]; ok {
+			return nil, fmt.Errorf("duplicate migration version %d (%s and %s)", version, existing.Name, m[2])
+		}
+		sum := sha256.Sum256(data)
+		mig := &Migration{
+			Version:       version,
+			Name:          m[2],
+			Up:            string(data),
+			Checksum:      hex.EncodeToString(sum[:]),
+			NoTransaction: strings.HasPrefix(strings.TrimSpace(string(data)), NoTransactionDirective),
+		}
+		if r := replaces.FindStringSubmatch(mig.Up); r != nil {
+			mig.Replaces, _ = strconv.ParseInt(r[1], 10, 64)
+		}
+		byVersion[version] = mig
+	}
+
+	for version, down := range downs {
+		m, ok := byVersion[version]
+		if !ok {
+			return nil, fmt.Errorf("down migration %d has no matching up migration", version)
+		}
+		m.Down = down
+	}
+	for _, m := range byVersion {
+		if m.Replaces == 0 {
+			continue
+		}
+		target, ok := byVersion[m.Replaces]
+		switch {
+		case !ok || m.Replaces >= m.Version:
+			return nil, fmt.Errorf("migration %d replaces %d, which is not an earlier migration", m.Version, m.Replaces)
+		case target.Replacement != "":
+			return nil, fmt.Errorf("migration %d is replaced more than once", m.Replaces)
+		case m.NoTransaction != target.NoTransaction:
+			return nil, fmt.Errorf("migration %d and its replacement %d must share the transaction mode", m.Replaces, m.Version)
+		}
+		target.Replacement = m.Up
+	}
+
+	migrations := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		migrations = append(migrations, *m)
+	}
+	sort.Slice(migrations, func(i, j int) bool { return migrations[i].Version < migrations[j].Version })
+	return migrations, nil
+}
+
+// Applied est une ligne de schema_migrations
+type Applied struct {
+	Version   int64
+	Name      string
+	Checksum  string
+	AppliedAt time.Time
+}
+
+// Status décrit l'état d'une migration connue du binaire ou de la base
+type Status struct {
+	Version   int64
+	Name      string
+	Applied   bool
+	AppliedAt time.Time
+	Modified  bool // Fichier modifié depuis son application
+	Unknown   bool // Appliquée en base mais absente du binaire (base plus récente)
+}
+
+// plan confronte les fichiers aux versions appliquées
+func plan(migrations []Migration, applied map[int64]Applied) (statuses []Status, pending []Migration) {
+	known := map[int64]bool{}
+	for _, m := range migrations {
+		known[m.Version] = true
+		st := Status{Version: m.Version, Name: m.Name}
+		if a, ok := applied[m.Version]; ok {
+			st.Applied, st.AppliedAt, st.Modified = true, a.AppliedAt, a.Checksum != m.Checksum
+		} else {
+			pending = append(pending, m)
+		}
+		statuses = append(statuses, st)
+	}
+	for version, a := range applied {
+		if !known[version] {
+			statuses = append(statuses, Status{Version: version, Name: a.Name, Applied: true, AppliedAt: a.AppliedAt, Unknown: true})
+		}
+	}
+	sort.Slice(statuses, func(i, j int) bool { return statuses[i].Version < statuses[j].Version })
+	return statuses, pending
+}
+
+// Migrator applique les migrations sur une base PostgreSQL
+type Migrator struct {
+	db         *sql.DB
+	migrations []Migration
+}
+
+func New(db *sql.DB, migrations []Migration) *Migrator {
+	return &Migrator{db: db, migrations: migrations}
+}
+
+// Open charge les migrations du dossier dir pour la base db
+func Open(db *sql.DB, dir string) (*Migrator, error) {
+	migrations, err := Load(os.DirFS(dir))
+	if err != nil {
+		return nil, err
+	}
+	return New(db, migrations), nil
+}
+
+const createTable = `CREATE TABLE IF NOT EXISTS schema_migrations (
+    version BIGINT PRIMARY KEY,
+    name VARCHAR(255) NOT NULL,
+    checksum VARCHAR(64) NOT NULL,
+    execution_ms INT NOT NULL DEFAULT 0,
+    applied_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+)`
+
+func (m *Migrator) applied(ctx context.Context, q interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+}) (map[int64]Applied, error) {
+	rows, err := q.QueryContext(ctx, `SELECT version, name, checksum, applied_at FROM schema_migrations`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	applied := map[int64]Applied{}
+	for rows.Next() {
+		var a Applied
+		if err := rows.Scan(&a.Version, &a.Name, &a.Checksum, &a.AppliedAt); err != nil {
+			return nil, err
+		}
+		applied[a.Version] = a
+	}
+	return applied, rows.Err()
+}
+
+// Status retourne l'état de chaque migration
+func (m *Migrator) Status(ctx context.Context) ([]Status, error) {
+	if _, err := m.db.ExecContext(ctx, createTable); err != nil {
+		return nil, fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	applied, err := m.applied(ctx, m.db)
+	if err != nil {
+		return nil, err
+	}
+	statuses, _ := plan(m.migrations, applied)
+	return statuses, nil
+}
+
+// Check vérifie que le schéma est à jour : ErrSchemaBehind si des migrations manquent,
+// ErrChecksumMismatch si une migration appliquée a été modifiée
+func (m *Migrator) Check(ctx context.Context) error {
+	statuses, err := m.Status(ctx)
+	if err != nil {
+		return err
+	}
+	var pending, modified []string
+	for _, st := range statuses {
+		switch {
+		case st.Unknown:
+			migrateLogger.WarnContext(ctx, "Migration appliquée mais inconnue de ce binaire", "version", st.Version, "name", st.Name)
+		case st.Modified:
+			modified = append(modified, fmt.Sprintf("%03d_%s", st.Version, st.Name))
+		case !st.Applied:
+			pending = append(pending, fmt.Sprintf("%03d_%s", st.Version, st.Name))
+		}
+	}
+	if len(modified) > 0 {
+		return fmt.Errorf("%w: %s", ErrChecksumMismatch, strings.Join(modified, ", "))
+	}
+	if len(pending) > 0 {
+		return fmt.Errorf("%w: %d pending migration(s): %s", ErrSchemaBehind, len(pending), strings.Join(pending, ", "))
+	}
+	return nil
+}
+
+// withLock exécute fn sur une connexion dédiée qui détient le verrou consultatif :
+// deux instances lancées en même temps ne jouent pas les mêmes migrations
+func (m *Migrator) withLock(ctx context.Context, fn func(conn *sql.Conn) error) error {
+	conn, err := m.db.Conn(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, lockID); err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	defer conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, lockID)
+
+	if _, err := conn.ExecContext(ctx, createTable); err != nil {
+		return fmt.Errorf("failed to create schema_migrations: %w", err)
+	}
+	return fn(conn)
+}
+
+// Up applique les migrations en attente jusqu'à target incluse (0 : toutes).
+// Une migration appliquée puis modifiée bloque la montée.
+func (m *Migrator) Up(ctx context.Context, target int64) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		statuses, pending := plan(m.migrations, applied)
+		for _, st := range statuses {
+			if st.Modified {
+				return fmt.Errorf("%w: %03d_%s", ErrChecksumMismatch, st.Version, st.Name)
+			}
+		}
+
+		for _, mig := range pending {
+			if target > 0 && mig.Version > target {
+				break
+			}
+			start := time.Now()
+			if err := m.run(ctx, conn, mig, mig.script(), func(exec execer) error {
+				_, err := exec.ExecContext(ctx, `INSERT INTO schema_migrations (version, name, checksum, execution_ms) VALUES ($1, $2, $3, $4)`,
+					mig.Version, mig.Name, mig.Checksum, time.Since(start).Milliseconds())
+				return err
+			}); err != nil {
+				return fmt.Errorf("migration %03d_%s failed: %w", mig.Version, mig.Name, err)
+			}
+			if mig.Replacement != "" {
+				migrateLogger.InfoContext(ctx, "Script correctif joué à la place de la migration", "version", mig.Version, "name", mig.Name)
+			}
+			migrateLogger.InfoContext(ctx, "Migration appliquée", "version", mig.Version, "name", mig.Name, "duration", time.Since(start).Round(time.Millisecond).String())
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+// Down annule les steps dernières migrations appliquées, de la plus récente à la plus ancienne
+func (m *Migrator) Down(ctx context.Context, steps int) ([]Migration, error) {
+	var done []Migration
+	err := m.withLock(ctx, func(conn *sql.Conn) error {
+		applied, err := m.applied(ctx, conn)
+		if err != nil {
+			return err
+		}
+		byVersion := map[int64]Migration{}
+		for _, mig := range m.migrations {
+			byVersion[mig.Version] = mig
+		}
+		versions := make([]int64, 0, len(applied))
+		for v := range applied {
+			versions = append(versions, v)
+		}
+		sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+
+		for i := 0; i < steps && i < len(versions); i++ {
+			mig, ok := byVersion[versions[i]]
+			if !ok {
+				return fmt.Errorf("applied migration %d is unknown to this binary", versions[i])
+			}
+			down := mig.Down
+			if down == "" && mig.Replaces == 0 { // Un correctif n'a rien exécuté à son rang
+				return fmt.Errorf("%w: %03d_%s", ErrIrreversible, mig.Version, mig.Name)
+			}
+			if err := m.run(ctx, conn, mig, down, func(exec execer) error {
+				_, err := exec.ExecContext(ctx, `DELETE FROM schema_migrations WHERE version = $1`, mig.Version)
+				return err
+			}); err != nil {
+				return fmt.Errorf("rollback of %03d_%s failed: %w", mig.Version, mig.Name, err)
+			}
+			migrateLogger.InfoContext(ctx, "Migration annulée", "version", mig.Version, "name", mig.Name)
+			done = append(done, mig)
+		}
+		return nil
+	})
+	return done, err
+}
+
+type execer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+}
+
+// run exécute le script puis record dans une même transaction (ou directement avec no-transaction)
+// Un script vide n'enregistre que la version.
+func (m *Migrator) run(ctx context.Context, conn *sql.Conn, mig Migration, script string, record func(execer) error) error {
+	if mig.NoTransaction || script == "" {
+		if script != "" {
+			if _, err := conn.ExecContext(ctx, script); err != nil {
+				return err
+			}
+		}
+		return record(conn)
+	}
+
+	tx, err := conn.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if _, err := tx.ExecContext(ctx, script); err != nil {
+		return err
+	}
+	if err := record(tx); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Create écrit une nouvelle paire de fichiers (montée/descente) numérotée après la dernière version du dossier
+func Create(dir, name string) (up, down string, err error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	name = regexp.MustCompile(`[^a-z0-9]+`).ReplaceAllString(name, "_")
+	name = strings.Trim(name, "_")
+	if name == "" {
+		return "", "", fmt.Errorf("migration name is required")
+	}
+
+	migrations, err := Load(os.DirFS(dir))
+	if err != nil {
+		return "", "", err
+	}
+	next := int64(1)
+	if len(migrations) > 0 {
+		next = migrations[len(migrations)-1].Version + 1
+	}
+
+	base := fmt.Sprintf("%03d_%s", next, name)
+	up = filepath.Join(dir, base+".sql")
+	down = filepath.Join(dir, base+".down.sql")
+	if err := os.WriteFile(up, []byte(fmt.Sprintf("-- %s\n", base)), 0o644); err != nil {
+		return "", "", err
+	}
+	if err := os.WriteFile(down, []byte(fmt.Sprintf("-- Annule %s\n", base)), 0o644); err != nil {
+		return "", "", err
+	}
+	return up, down, nil
+}
@@ -0,0 +1,161 @@
+package migrate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestLoadOrdersAndPairsMigrations(t *testing.T) {
+	fsys := fstest.MapFS{
+		"010_alerts.sql":      {Data: []byte("CREATE TABLE alerts ();")},
+		"010_alerts.down.sql": {Data: []byte("DROP TABLE alerts;")},
+		"002_users.sql":       {Data: []byte("CREATE TABLE users ();")},
+		"003_index.sql":       {Data: []byte(NoTransactionDirective + "\nCREATE INDEX CONCURRENTLY i ON users (id);")},
+		"README.md":           {Data: []byte("ignoré")},
+	}
+	migrations, err := Load(fsys)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if len(migrations) != 3 {
+		t.Fatalf("expected 3 migrations, got %d", len(migrations))
+	}
+	if migrations[0].Version != 2 || migrations[1].Version != 3 || migrations[2].Version != 10 {
+		t.Fatalf("unexpected order: %+v", migrations)
+	}
+	if migrations[2].Name != "alerts" || migrations[2].Down != "DROP TABLE alerts;" {
+		t.Fatalf("down script not paired: %+v", migrations[2])
+	}
+	if migrations[0].Down != "" {
+		t.Fatal("002 should be irreversible")
+	}
+	if !migrations[1].NoTransaction || migrations[0].NoTransaction {
+		t.Fatal("no-transaction directive not detected")
+	}
+	if len(migrations[0].Checksum) != 64 {
+		t.Fatalf("unexpected checksum %q", migrations[0].Checksum)
+	}
+}
+
+func TestLoadRejectsInvalidFiles(t *testing.T) {
+	cases := map[string]fstest.MapFS{
+		"bad name":         {"users.sql": {Data: []byte("")}},
+		"duplicate":        {"001_a.sql": {Data: []byte("")}, "0001_b.sql": {Data: []byte("")}},
+		"orphan down":      {"001_a.sql": {Data: []byte("")}, "002_b.down.sql": {Data: []byte("")}},
+		"uppercase name":   {"001_Users.sql": {Data: []byte("")}},
+		"replaces later":   {"001_a.sql": {Data: []byte(ReplacesDirective + " 2\n")}, "002_b.sql": {Data: []byte("")}},
+		"replaces unknown": {"002_b.sql": {Data: []byte(ReplacesDirective + " 1\n")}},
+		"replaced twice": {
+			"001_a.sql": {Data: []byte("")},
+			"002_b.sql": {Data: []byte(ReplacesDirective + " 1\n")},
+			"003_c.sql": {Data: []byte(ReplacesDirective + " 001\n")},
+		},
+	}
+	for name, fsys := range cases {
+		if _, err := Load(fsys); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
+
+func TestReplacementRunsInPlaceOfBrokenMigration(t *testing.T) {
+	migrations, err := Load(fstest.MapFS{
+		"001_seed.sql":     {Data: []byte("INSERT broken;")},
+		"002_other.sql":    {Data: []byte("CREATE TABLE other ();")},
+		"003_seed_fix.sql": {Data: []byte(ReplacesDirective + " 001\nINSERT fixed;")},
+	})
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	seed, other, fix := migrations[0], migrations[1], migrations[2]
+	if seed.Up != "INSERT broken;" || !strings.HasSuffix(seed.script(), "INSERT fixed;") {
+		t.Errorf("001 should keep its script (checksum) and run the fix: %+v", seed)
+	}
+	if fix.Replaces != 1 || fix.script() != "" || other.script() != other.Up {
+		t.Errorf("unexpected scripts: %+v / %+v", fix, other)
+	}
+}
+
+func TestPlanDetectsPendingModifiedAndUnknown(t *testing.T) {
+	migrations, err := Load(fstest.MapFS{
+		"001_a.sql": {Data: []byte("A")},
+		"002_b.sql": {Data: []byte("B")},
+		"003_c.sql": {Data: []byte("C")},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	applied := map[int64]Applied{
+		1: {Version: 1, Name: "a", Checksum: migrations[0].Checksum, AppliedAt: now},
+		2: {Version: 2, Name: "b", Checksum: "modifié", AppliedAt: now},
+		9: {Version: 9, Name: "future", Checksum: "x", AppliedAt: now},
+	}
+
+	statuses, pending := plan(migrations, applied)
+	if len(pending) != 1 || pending[0].Version != 3 {
+		t.Fatalf("expected 003 pending, got %+v", pending)
+	}
+	if len(statuses) != 4 {
+		t.Fatalf("expected 4 statuses, got %d", len(statuses))
+	}
+	if statuses[0].Modified || !statuses[0].Applied {
+		t.Errorf("001 should be applied and intact: %+v", statuses[0])
+	}
+	if !statuses[1].Modified {
+		t.Errorf("002 should be reported as modified: %+v", statuses[1])
+	}
+	if statuses[2].Applied {
+		t.Errorf("003 should be pending: %+v", statuses[2])
+	}
+	if !statuses[3].Unknown || statuses[3].Version != 9 {
+		t.Errorf("009 should be unknown to the binary: %+v", statuses[3])
+	}
+}
+
+func TestCreateNumbersAfterLastVersion(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "019_notifications.sql"), []byte("SELECT 1;"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	up, down, err := Create(dir, "Add Audit Chain")
+	if err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+	if filepath.Base(up) != "020_add_audit_chain.sql" || filepath.Base(down) != "020_add_audit_chain.down.sql" {
+		t.Fatalf("unexpected files %s, %s", up, down)
+	}
+
+	migrations, err := Load(os.DirFS(dir))
+	if err != nil {
+		t.Fatalf("created files should load: %v", err)
+	}
+	if len(migrations) != 2 || !strings.Contains(migrations[1].Down, "Annule") {
+		t.Fatalf("unexpected migrations: %+v", migrations)
+	}
+
+	if _, _, err := Create(dir, "  "); err == nil {
+		t.Fatal("empty name should be rejected")
+	}
+}
+
+func TestRepositoryMigrationsLoad(t *testing.T) {
+	migrations, err := Load(os.DirFS("../../../migration"))
+	if err != nil {
+		t.Fatalf("repository migrations should load: %v", err)
+	}
+	if len(migrations) == 0 || migrations[0].Version != 1 {
+		t.Fatalf("unexpected migrations: %d", len(migrations))
+	}
+	// Depuis 010, toute migration doit pouvoir être annulée
+	for _, m := range migrations {
+		if m.Version >= 10 && m.Down == "" && m.Replaces == 0 {
+			t.Errorf("%03d_%s has no down script", m.Version, m.Name)
+		}
+	}
+}
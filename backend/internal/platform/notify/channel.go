@@ -0,0 +1,46 @@
+// Package notify fournit les canaux d'envoi des notifications (e-mail SMTP, passerelle SMS HTTP).
+// La boîte de réception in-app, stockée en base, est implémentée côté service.
+package notify
+
+import (
+	"context"
+	"errors"
+)
+
+// Noms des canaux, repris dans les préférences utilisateur et le journal des envois
+const (
+	ChannelEmail = "email"
+	ChannelSMS   = "sms"
+	ChannelInbox = "inbox"
+)
+
+// ErrPermanent marque un échec définitif (destinataire invalide...) : inutile de réessayer
+var ErrPermanent = errors.New("permanent delivery failure")
+
+// Message est une notification rendue, prête à être envoyée sur un canal
+type Message struct {
+	UserID    string
+	Recipient string // Adresse e-mail, numéro de téléphone ou identifiant utilisateur selon le canal
+	Kind      string
+	Subject   string
+	Body      string
+}
+
+// Channel envoie un message sur un canal de notification
+type Channel interface {
+	Name() string
+	Send(ctx context.Context, msg Message) error
+}
+
+// Channels retourne les canaux externes configurés (serveur SMTP, passerelle SMS) ;
+// sans configuration, seule la boîte de réception in-app reste disponible
+func Channels(smtpCfg SMTPConfig, smsCfg SMSConfig) []Channel {
+	var channels []Channel
+	if smtpCfg.Enabled() {
+		channels = append(channels, NewSMTPChannel(smtpCfg))
+	}
+	if smsCfg.Enabled() {
+		channels = append(channels, NewSMSChannel(smsCfg, nil))
+	}
+	return channels
+}
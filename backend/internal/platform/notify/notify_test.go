@@ -0,0 +1,150 @@
+package notify
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// smtpSink est un serveur SMTP local minimal qui conserve les messages reçus
+type smtpSink struct {
+	listener net.Listener
+	mu       sync.Mutex
+	messages []string
+	rcpts    []string
+}
+
+func newSMTPSink(t *testing.T) *smtpSink {
+	t.Helper()
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("listen: %v", err)
+	}
+	sink := &smtpSink{listener: l}
+	go func() {
+		for {
+			conn, err := l.Accept()
+			if err != nil {
+				return
+			}
+			go sink.serve(conn)
+		}
+	}()
+	t.Cleanup(func() { l.Close() })
+	return sink
+}
+
+func (s *smtpSink) serve(conn net.Conn) {
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { conn.Write([]byte(line + "\r\n")) }
+	reply("220 sink ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		cmd := strings.ToUpper(strings.TrimSpace(line))
+		switch {
+		case strings.HasPrefix(cmd, "EHLO"), strings.HasPrefix(cmd, "HELO"):
+			reply("250 sink")
+		case strings.HasPrefix(cmd, "RCPT TO"):
+			s.mu.Lock()
+			s.rcpts = append(s.rcpts, strings.Trim(strings.TrimSpace(line)[len("RCPT TO:"):], "<>"))
+			s.mu.Unlock()
+			reply("250 OK")
+		case cmd == "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				l, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if l == ".\r\n" {
+					break
+				}
+				data.WriteString(l)
+			}
+			s.mu.Lock()
+			s.messages = append(s.messages, data.String())
+			s.mu.Unlock()
+			reply("250 OK queued")
+		case cmd == "QUIT":
+			reply("221 Bye")
+			return
+		default:
+			reply("250 OK")
+		}
+	}
+}
+
+func TestSMTPChannel_SendsToSink(t *testing.T) {
+	sink := newSMTPSink(t)
+	host, port, _ := net.SplitHostPort(sink.listener.Addr().String())
+	ch := NewSMTPChannel(SMTPConfig{Host: host, Port: port, From: "OpenVote <alerts@openvote.test>"})
+
+	err := ch.Send(context.Background(), Message{Recipient: "coord@example.org", Subject: "Alerte : fermeture anticipée", Body: "3 incidents\nZone Littoral"})
+	if err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	sink.mu.Lock()
+	defer sink.mu.Unlock()
+	if len(sink.messages) != 1 || len(sink.rcpts) != 1 || sink.rcpts[0] != "coord@example.org" {
+		t.Fatalf("Expected one message to coord@example.org, got %v %v", sink.rcpts, sink.messages)
+	}
+	msg := sink.messages[0]
+	if !strings.Contains(msg, "Subject: =?utf-8?q?Alerte_:_fermeture_anticip=C3=A9e?=") || !strings.Contains(msg, "3 incidents\r\nZone Littoral") {
+		t.Errorf("Unexpected message:\n%s", msg)
+	}
+
+	if err := ch.Send(context.Background(), Message{Recipient: "not-an-email"}); !errors.Is(err, ErrPermanent) {
+		t.Errorf("Expected ErrPermanent for invalid address, got %v", err)
+	}
+}
+
+func TestSMSChannel_PostsToGateway(t *testing.T) {
+	var received map[string]string
+	var auth string
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth = r.Header.Get("Authorization")
+		json.NewDecoder(r.Body).Decode(&received)
+		if received["to"] == "+237600000000" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if received["to"] == "+237699999999" {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	}))
+	defer gateway.Close()
+
+	ch := NewSMSChannel(SMSConfig{URL: gateway.URL, Token: "secret", Sender: "OpenVote"}, gateway.Client())
+	ctx := context.Background()
+
+	if err := ch.Send(ctx, Message{Recipient: "+237 677 12 34 56", Body: "Alerte"}); err != nil {
+		t.Fatalf("send: %v", err)
+	}
+	if received["to"] != "+237677123456" || received["from"] != "OpenVote" || auth != "Bearer secret" {
+		t.Errorf("Unexpected gateway request %v (auth %q)", received, auth)
+	}
+
+	if err := ch.Send(ctx, Message{Recipient: "+237600000000", Body: "x"}); !errors.Is(err, ErrPermanent) {
+		t.Errorf("Expected ErrPermanent on 400, got %v", err)
+	}
+	if err := ch.Send(ctx, Message{Recipient: "+237699999999", Body: "x"}); err == nil || errors.Is(err, ErrPermanent) {
+		t.Errorf("Expected retryable error on 503, got %v", err)
+	}
+	if err := ch.Send(ctx, Message{Recipient: "677123456", Body: "x"}); !errors.Is(err, ErrPermanent) {
+		t.Errorf("Expected ErrPermanent for local number, got %v", err)
+	}
+}
@@ -0,0 +1,85 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// SMSConfig : passerelle SMS HTTP (fournisseur agrégateur)
+type SMSConfig struct {
+	URL    string
+	Token  string
+	Sender string
+}
+
+func (c SMSConfig) Enabled() bool {
+	return c.URL != ""
+}
+
+// smsMaxLength : au-delà, le message est tronqué (3 SMS concaténés)
+const smsMaxLength = 459
+
+type smsChannel struct {
+	cfg    SMSConfig
+	client *http.Client
+}
+
+// NewSMSChannel poste {"to", "from", "message"} en JSON à la passerelle, authentifié par jeton Bearer.
+// Un client nil utilise un client avec délai d'expiration.
+func NewSMSChannel(cfg SMSConfig, client *http.Client) Channel {
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+	return &smsChannel{cfg: cfg, client: client}
+}
+
+func (c *smsChannel) Name() string { return ChannelSMS }
+
+func (c *smsChannel) Send(ctx context.Context, msg Message) error {
+	to := strings.ReplaceAll(msg.Recipient, " ", "")
+	if !strings.HasPrefix(to, "+") || len(to) < 8 {
+		// Le numéro n'est pas repris : l'erreur est journalisée et conservée avec l'envoi
+		return fmt.Errorf("%w: phone number must be in international format", ErrPermanent)
+	}
+
+	text := msg.Body
+	if runes := []rune(text); len(runes) > smsMaxLength {
+		text = string(runes[:smsMaxLength-1]) + "…"
+	}
+	payload, err := json.Marshal(map[string]string{"to": to, "from": c.cfg.Sender, "message": text})
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, c.cfg.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.cfg.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+c.cfg.Token)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(resp.Body, 4<<10))
+
+	switch {
+	case resp.StatusCode >= 200 && resp.StatusCode < 300:
+		return nil
+	case resp.StatusCode == http.StatusBadRequest || resp.StatusCode == http.StatusUnprocessableEntity:
+		// Numéro ou contenu refusé par la passerelle : une nouvelle tentative échouerait aussi
+		return fmt.Errorf("%w: gateway rejected message (%d): %s", ErrPermanent, resp.StatusCode, strings.TrimSpace(string(body)))
+	default:
+		return fmt.Errorf("gateway returned status %d", resp.StatusCode)
+	}
+}
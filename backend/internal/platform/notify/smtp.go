@@ -0,0 +1,81 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net"
+	"net/mail"
+	"net/smtp"
+	"strings"
+	"time"
+)
+
+// SMTPConfig : serveur d'envoi des e-mails
+type SMTPConfig struct {
+	Host     string
+	Port     string
+	Username string
+	Password string
+	From     string
+}
+
+// Enabled : le canal n'est proposé que si un serveur est configuré
+func (c SMTPConfig) Enabled() bool {
+	return c.Host != ""
+}
+
+type smtpChannel struct {
+	cfg  SMTPConfig
+	send func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewSMTPChannel envoie des e-mails texte (UTF-8) ; STARTTLS est négocié si le serveur le propose
+func NewSMTPChannel(cfg SMTPConfig) Channel {
+	return &smtpChannel{cfg: cfg, send: smtp.SendMail}
+}
+
+func (c *smtpChannel) Name() string { return ChannelEmail }
+
+func (c *smtpChannel) Send(ctx context.Context, msg Message) error {
+	if msg.Recipient == "" || !strings.Contains(msg.Recipient, "@") {
+		// L'adresse n'est pas reprise : l'erreur est journalisée et conservée avec l'envoi
+		return fmt.Errorf("%w: invalid email address", ErrPermanent)
+	}
+
+	var auth smtp.Auth
+	if c.cfg.Username != "" {
+		auth = smtp.PlainAuth("", c.cfg.Username, c.cfg.Password, c.cfg.Host)
+	}
+	from := c.cfg.From
+	if addr, err := mail.ParseAddress(from); err == nil {
+		from = addr.Address
+	}
+
+	// net/smtp n'accepte pas de contexte : l'envoi est abandonné (mais pas interrompu) à l'annulation
+	done := make(chan error, 1)
+	go func() {
+		done <- c.send(net.JoinHostPort(c.cfg.Host, c.cfg.Port), auth, from, []string{msg.Recipient}, buildEmail(c.cfg.From, msg))
+	}()
+	select {
+	case err := <-done:
+		return err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// buildEmail compose un message RFC 5322 en texte brut
+func buildEmail(from string, msg Message) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + from + "\r\n")
+	b.WriteString("To: " + msg.Recipient + "\r\n")
+	b.WriteString("Subject: " + mime.QEncoding.Encode("utf-8", msg.Subject) + "\r\n")
+	b.WriteString("Date: " + time.Now().Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	b.WriteString("Content-Transfer-Encoding: 8bit\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(msg.Body, "\n", "\r\n"))
+	return []byte(b.String())
+}
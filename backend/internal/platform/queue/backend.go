@@ -0,0 +1,60 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/openvote/backend/internal/platform/logging"
+)
+
+var logger = logging.For("queue")
+
+// Backends de file disponibles (QUEUE_BACKEND)
+const (
+	BackendRabbitMQ = "rabbitmq"
+	BackendMemory   = "memory"
+	BackendPostgres = "postgres"
+)
+
+// Pinger est implémenté par les backends capables de vérifier leur disponibilité (sonde /readyz)
+type Pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// Config décrit le backend de file et le réglage des consommateurs
+type Config struct {
+	Backend   string
+	RabbitURL string
+	Consumer  ConsumerOptions
+}
+
+// Open instancie le publisher et le consumer du backend demandé ("rabbitmq" par défaut).
+// Les backends memory et postgres partagent une seule instance entre les deux rôles.
+func Open(cfg Config, db *sql.DB) (Publisher, Consumer, error) {
+	backend, rabbitURL, opts := cfg.Backend, cfg.RabbitURL, cfg.Consumer
+	switch backend {
+	case "", BackendRabbitMQ:
+		publisher, err := NewRabbitPublisher(rabbitURL)
+		if err != nil {
+			return nil, nil, err
+		}
+		consumer, err := NewRabbitConsumer(rabbitURL, opts)
+		if err != nil {
+			publisher.Close()
+			return nil, nil, err
+		}
+		return publisher, consumer, nil
+	case BackendMemory:
+		broker := NewMemoryBroker(opts)
+		return broker, broker, nil
+	case BackendPostgres:
+		if db == nil {
+			return nil, nil, fmt.Errorf("postgres queue backend requires a database connection")
+		}
+		q := NewPostgresQueue(db, opts)
+		return q, q, nil
+	default:
+		return nil, nil, fmt.Errorf("unknown queue backend %q", backend)
+	}
+}
@@ -0,0 +1,102 @@
+package queue
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+const (
+	reconnectInitialDelay = 500 * time.Millisecond
+	reconnectMaxDelay     = 30 * time.Second
+)
+
+var errConnectionClosed = errors.New("rabbitmq connection closed")
+
+// rabbitConnection maintient une connexion AMQP et la rétablit avec un backoff exponentiel
+// lorsque le broker redémarre ou que le réseau coupe.
+type rabbitConnection struct {
+	url    string
+	mu     sync.Mutex
+	conn   *amqp.Connection
+	closed bool
+}
+
+// dialRabbit établit la connexion initiale : une erreur au démarrage reste remontée à l'appelant
+func dialRabbit(url string) (*rabbitConnection, error) {
+	conn, err := amqp.Dial(url)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+	}
+	return &rabbitConnection{url: url, conn: conn}, nil
+}
+
+// channel ouvre un canal sur la connexion courante, en la rétablissant si nécessaire.
+// Bloque jusqu'à la reconnexion, la fermeture du client ou l'annulation du contexte.
+func (c *rabbitConnection) channel(ctx context.Context) (*amqp.Channel, error) {
+	delay := reconnectInitialDelay
+	for {
+		ch, err := c.tryChannel()
+		if err == nil {
+			return ch, nil
+		}
+		if errors.Is(err, errConnectionClosed) {
+			return nil, err
+		}
+
+		logger.Warn("RabbitMQ indisponible, nouvelle tentative", "error", err, "retry_in", delay.String())
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(delay):
+		}
+		delay *= 2
+		if delay > reconnectMaxDelay {
+			delay = reconnectMaxDelay
+		}
+	}
+}
+
+func (c *rabbitConnection) tryChannel() (*amqp.Channel, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.closed {
+		return nil, errConnectionClosed
+	}
+	if c.conn == nil || c.conn.IsClosed() {
+		conn, err := amqp.Dial(c.url)
+		if err != nil {
+			return nil, err
+		}
+		c.conn = conn
+		logger.Info("Connexion RabbitMQ rétablie")
+	}
+
+	ch, err := c.conn.Channel()
+	if err != nil {
+		c.conn.Close()
+		c.conn = nil
+		return nil, err
+	}
+	return ch, nil
+}
+
+func (c *rabbitConnection) isClosed() bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.closed
+}
+
+func (c *rabbitConnection) Close() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.closed = true
+	if c.conn != nil {
+		c.conn.Close()
+	}
+}
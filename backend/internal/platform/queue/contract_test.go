@@ -0,0 +1,476 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+	"github.com/openvote/backend/internal/platform/logging"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// broker regroupe les trois rôles qu'un backend de file doit remplir
+type broker interface {
+	Publisher
+	Consumer
+	DeadLetterManager
+}
+
+// Suite de contrat commune : chaque backend doit livrer, réessayer puis envoyer en file morte
+// de la même façon. Postgres et RabbitMQ ne sont testés que si leur URL est fournie.
+func TestQueueContract(t *testing.T) {
+	saved := retryDelays
+	retryDelays = []time.Duration{50 * time.Millisecond, 100 * time.Millisecond}
+	defer func() { retryDelays = saved }()
+
+	t.Run("memory", func(t *testing.T) {
+		runQueueContract(t, func(t *testing.T, opts ConsumerOptions) broker { return NewMemoryBroker(opts) })
+	})
+
+	t.Run("postgres", func(t *testing.T) {
+		dsn := os.Getenv("TEST_DATABASE_URL")
+		if dsn == "" {
+			t.Skip("TEST_DATABASE_URL non défini")
+		}
+		db, err := sql.Open("postgres", dsn)
+		if err != nil {
+			t.Fatalf("open database: %v", err)
+		}
+		defer db.Close()
+		if _, err := db.Exec(`CREATE EXTENSION IF NOT EXISTS "uuid-ossp"`); err != nil {
+			t.Fatalf("uuid extension: %v", err)
+		}
+		for _, file := range []string{"014_outbox.sql", "015_queue_jobs.sql", "016_event_bus.sql", "022_trace_headers.sql"} {
+			migration, err := os.ReadFile("../../../migration/" + file)
+			if err != nil {
+				t.Fatalf("read migration: %v", err)
+			}
+			if _, err := db.Exec(string(migration)); err != nil {
+				t.Fatalf("apply migration %s: %v", file, err)
+			}
+		}
+		runQueueContract(t, func(t *testing.T, opts ConsumerOptions) broker { return NewPostgresQueue(db, opts) })
+	})
+
+	t.Run("rabbitmq", func(t *testing.T) {
+		url := os.Getenv("TEST_RABBITMQ_URL")
+		if url == "" {
+			t.Skip("TEST_RABBITMQ_URL non défini")
+		}
+		runQueueContract(t, func(t *testing.T, opts ConsumerOptions) broker {
+			publisher, err := NewRabbitPublisher(url)
+			if err != nil {
+				t.Fatalf("rabbit publisher: %v", err)
+			}
+			consumer, err := NewRabbitConsumer(url, opts)
+			if err != nil {
+				t.Fatalf("rabbit consumer: %v", err)
+			}
+			return rabbitBroker{Publisher: publisher, rabbitConsumer: consumer.(*rabbitConsumer)}
+		})
+	})
+}
+
+// rabbitBroker assemble publisher et consumer RabbitMQ pour la suite de contrat
+type rabbitBroker struct {
+	Publisher
+	*rabbitConsumer
+}
+
+func (b rabbitBroker) Close() {
+	b.Publisher.Close()
+	b.rabbitConsumer.Close()
+}
+
+type contractMessage struct {
+	N int `json:"n"`
+}
+
+func runQueueContract(t *testing.T, openWith func(t *testing.T, opts ConsumerOptions) broker) {
+	open := func(t *testing.T) broker { return openWith(t, DefaultConsumerOptions()) }
+
+	t.Run("delivers published messages", func(t *testing.T) {
+		b := open(t)
+		defer b.Close()
+		queueName := uniqueQueue()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		received := make(chan int, 3)
+		if err := b.Consume(ctx, queueName, func(ctx context.Context, body []byte) error {
+			var m contractMessage
+			if err := json.Unmarshal(body, &m); err != nil {
+				return err
+			}
+			received <- m.N
+			return nil
+		}); err != nil {
+			t.Fatalf("consume: %v", err)
+		}
+		for i := 1; i <= 3; i++ {
+			if err := b.Publish(ctx, queueName, contractMessage{N: i}); err != nil {
+				t.Fatalf("publish: %v", err)
+			}
+		}
+
+		seen := map[int]bool{}
+		for len(seen) < 3 {
+			select {
+			case n := <-received:
+				seen[n] = true
+			case <-time.After(5 * time.Second):
+				t.Fatalf("only %d/3 messages delivered", len(seen))
+			}
+		}
+	})
+
+	t.Run("propagates trace context and request ID to the consumer", func(t *testing.T) {
+		restore := installTracing()
+		defer restore()
+		b := open(t)
+		defer b.Close()
+		queueName := uniqueQueue()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		received := make(chan trace.SpanContext, 1)
+		requestID := make(chan string, 1)
+		if err := b.Consume(ctx, queueName, func(ctx context.Context, body []byte) error {
+			requestID <- logging.RequestID(ctx)
+			received <- trace.SpanContextFromContext(ctx)
+			return nil
+		}); err != nil {
+			t.Fatalf("consume: %v", err)
+		}
+
+		pubCtx, span := otel.Tracer("contract").Start(logging.WithRequestID(ctx, "req-contract"), "request")
+		if err := b.Publish(pubCtx, queueName, contractMessage{N: 1}); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+		span.End()
+
+		select {
+		case sc := <-received:
+			if sc.TraceID() != span.SpanContext().TraceID() {
+				t.Errorf("consumer trace = %s, want %s", sc.TraceID(), span.SpanContext().TraceID())
+			}
+			if sc.SpanID() == span.SpanContext().SpanID() {
+				t.Error("consumer reuses the producer span instead of opening its own")
+			}
+			if id := <-requestID; id != "req-contract" {
+				t.Errorf("consumer request ID = %q, want req-contract", id)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("message not delivered")
+		}
+	})
+
+	t.Run("retries failed messages", func(t *testing.T) {
+		b := open(t)
+		defer b.Close()
+		queueName := uniqueQueue()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var calls int32
+		done := make(chan struct{})
+		if err := b.Consume(ctx, queueName, func(ctx context.Context, body []byte) error {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				return fmt.Errorf("transient failure")
+			}
+			close(done)
+			return nil
+		}); err != nil {
+			t.Fatalf("consume: %v", err)
+		}
+		if err := b.Publish(ctx, queueName, contractMessage{N: 1}); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatalf("message not retried (calls=%d)", atomic.LoadInt32(&calls))
+		}
+	})
+
+	t.Run("dead-letters then replays exhausted messages", func(t *testing.T) {
+		b := open(t)
+		defer b.Close()
+		queueName := uniqueQueue()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		var failing atomic.Bool
+		failing.Store(true)
+		var calls int32
+		recovered := make(chan string, 1)
+		if err := b.Consume(ctx, queueName, func(ctx context.Context, body []byte) error {
+			atomic.AddInt32(&calls, 1)
+			if failing.Load() {
+				return fmt.Errorf("permanent failure")
+			}
+			recovered <- logging.RequestID(ctx)
+			return nil
+		}); err != nil {
+			t.Fatalf("consume: %v", err)
+		}
+		if err := b.Publish(logging.WithRequestID(ctx, "req-dead-letter"), queueName, contractMessage{N: 42}); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+
+		var letters []DeadLetter
+		deadline := time.Now().Add(10 * time.Second)
+		for len(letters) == 0 {
+			if time.Now().After(deadline) {
+				t.Fatalf("message never dead-lettered (calls=%d)", atomic.LoadInt32(&calls))
+			}
+			time.Sleep(50 * time.Millisecond)
+			var err error
+			if letters, err = b.ListDeadLetters(ctx, queueName, 10); err != nil {
+				t.Fatalf("list dead letters: %v", err)
+			}
+		}
+
+		if got, want := int(atomic.LoadInt32(&calls)), len(retryDelays)+1; got != want {
+			t.Errorf("handler called %d times before dead-letter, want %d", got, want)
+		}
+		if letters[0].LastError != "permanent failure" {
+			t.Errorf("last error = %q", letters[0].LastError)
+		}
+		var m contractMessage
+		if err := json.Unmarshal(letters[0].Body, &m); err != nil || m.N != 42 {
+			t.Errorf("dead letter body = %s", letters[0].Body)
+		}
+
+		failing.Store(false)
+		n, err := b.ReplayDeadLetters(ctx, queueName, []string{letters[0].MessageID})
+		if err != nil || n != 1 {
+			t.Fatalf("replay = %d, %v", n, err)
+		}
+		// Le message rejoué garde ses en-têtes : la corrélation avec la requête d'origine est conservée
+		select {
+		case id := <-recovered:
+			if id != "req-dead-letter" {
+				t.Errorf("replayed request id = %q, want req-dead-letter", id)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("replayed message not delivered")
+		}
+		if left, _ := b.ListDeadLetters(ctx, queueName, 10); len(left) != 0 {
+			t.Errorf("%d dead letters left after replay", len(left))
+		}
+	})
+
+	t.Run("routes topic messages to bound queues", func(t *testing.T) {
+		b := open(t)
+		defer b.Close()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		// Clés préfixées pour isoler le test des autres liaisons de l'échange
+		prefix := "contract" + uuid.New().String()[:8]
+		reports, all := uniqueQueue(), uniqueQueue()
+		if err := b.Bind(ctx, reports, prefix+".report.*"); err != nil {
+			t.Fatalf("bind: %v", err)
+		}
+		if err := b.Bind(ctx, all, prefix+".#"); err != nil {
+			t.Fatalf("bind: %v", err)
+		}
+
+		got := make(chan string, 4)
+		for _, q := range []string{reports, all} {
+			q := q
+			if err := b.Consume(ctx, q, func(ctx context.Context, body []byte) error {
+				var m contractMessage
+				if err := json.Unmarshal(body, &m); err != nil {
+					return err
+				}
+				got <- fmt.Sprintf("%s:%d", q, m.N)
+				return nil
+			}); err != nil {
+				t.Fatalf("consume: %v", err)
+			}
+		}
+
+		if err := b.PublishTopic(ctx, prefix+".report.created", contractMessage{N: 1}); err != nil {
+			t.Fatalf("publish topic: %v", err)
+		}
+		if err := b.PublishTopic(ctx, prefix+".user.role_changed", contractMessage{N: 2}); err != nil {
+			t.Fatalf("publish topic: %v", err)
+		}
+
+		want := map[string]bool{reports + ":1": true, all + ":1": true, all + ":2": true}
+		for len(want) > 0 {
+			select {
+			case delivery := <-got:
+				if !want[delivery] {
+					t.Fatalf("unexpected delivery %s", delivery)
+				}
+				delete(want, delivery)
+			case <-time.After(5 * time.Second):
+				t.Fatalf("missing deliveries: %v", want)
+			}
+		}
+	})
+
+	t.Run("processes messages concurrently", func(t *testing.T) {
+		b := openWith(t, ConsumerOptions{Workers: 3, MessageTimeout: 5 * time.Second})
+		defer b.Close()
+		queueName := uniqueQueue()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		started := make(chan struct{}, 3)
+		release := make(chan struct{})
+		if err := b.Consume(ctx, queueName, func(ctx context.Context, body []byte) error {
+			started <- struct{}{}
+			<-release
+			return nil
+		}); err != nil {
+			t.Fatalf("consume: %v", err)
+		}
+		defer close(release)
+		for i := 0; i < 3; i++ {
+			if err := b.Publish(ctx, queueName, contractMessage{N: i}); err != nil {
+				t.Fatalf("publish: %v", err)
+			}
+		}
+		for i := 0; i < 3; i++ {
+			select {
+			case <-started:
+			case <-time.After(5 * time.Second):
+				t.Fatalf("only %d/3 messages handled in parallel", i)
+			}
+		}
+	})
+
+	t.Run("bounds handler time with the message timeout", func(t *testing.T) {
+		b := openWith(t, ConsumerOptions{Workers: 1, MessageTimeout: 100 * time.Millisecond})
+		defer b.Close()
+		queueName := uniqueQueue()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		errs := make(chan error, 1)
+		if err := b.Consume(ctx, queueName, func(ctx context.Context, body []byte) error {
+			<-ctx.Done()
+			select {
+			case errs <- ctx.Err():
+			default:
+			}
+			return nil
+		}); err != nil {
+			t.Fatalf("consume: %v", err)
+		}
+		if err := b.Publish(ctx, queueName, contractMessage{N: 1}); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+		select {
+		case err := <-errs:
+			if err != context.DeadlineExceeded {
+				t.Errorf("handler context error = %v, want deadline exceeded", err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("handler context never expired")
+		}
+	})
+
+	t.Run("drains in-flight messages after cancellation", func(t *testing.T) {
+		b := open(t)
+		defer b.Close()
+		queueName := uniqueQueue()
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		started := make(chan struct{})
+		release := make(chan struct{})
+		var finished atomic.Bool
+		var handlerErr atomic.Value
+		if err := b.Consume(ctx, queueName, func(hctx context.Context, body []byte) error {
+			close(started)
+			<-release
+			if hctx.Err() != nil {
+				handlerErr.Store(hctx.Err())
+			}
+			finished.Store(true)
+			return nil
+		}); err != nil {
+			t.Fatalf("consume: %v", err)
+		}
+		if err := b.Publish(ctx, queueName, contractMessage{N: 1}); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+		select {
+		case <-started:
+		case <-time.After(5 * time.Second):
+			t.Fatal("message not delivered")
+		}
+
+		cancel() // Arrêt demandé pendant le traitement
+		short, cancelShort := context.WithTimeout(context.Background(), 100*time.Millisecond)
+		defer cancelShort()
+		if err := b.Drain(short); err == nil {
+			t.Fatal("Drain returned while a message was still in flight")
+		}
+
+		close(release)
+		drainCtx, cancelDrain := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancelDrain()
+		if err := b.Drain(drainCtx); err != nil {
+			t.Fatalf("drain: %v", err)
+		}
+		if !finished.Load() {
+			t.Error("in-flight message not completed before Drain returned")
+		}
+		if err := handlerErr.Load(); err != nil {
+			t.Errorf("handler context cancelled by shutdown: %v", err)
+		}
+	})
+}
+
+// installTracing active un fournisseur de traces réel et la propagation W3C le temps d'un test
+func installTracing() (restore func()) {
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return func() {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	}
+}
+
+func uniqueQueue() string {
+	return "contract." + uuid.New().String()[:8]
+}
+
+func TestTopicMatches(t *testing.T) {
+	cases := []struct {
+		pattern, key string
+		want         bool
+	}{
+		{"report.created", "report.created", true},
+		{"report.*", "report.created", true},
+		{"report.*", "report.created.urgent", false},
+		{"report.#", "report.created.urgent", true},
+		{"report.#", "report", true},
+		{"#", "user.role_changed", true},
+		{"*.status_changed", "election.status_changed", true},
+		{"*.status_changed", "report.created", false},
+	}
+	for _, c := range cases {
+		if got := topicMatches(c.pattern, c.key); got != c.want {
+			t.Errorf("topicMatches(%q, %q) = %v, want %v", c.pattern, c.key, got, c.want)
+		}
+	}
+}
@@ -0,0 +1,121 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// En-têtes posés sur un message en échec
+const (
+	HeaderRetryCount    = "x-retry-count"
+	HeaderLastError     = "x-last-error"
+	HeaderOriginalQueue = "x-original-queue"
+	HeaderFailedAt      = "x-failed-at"
+)
+
+// retryDelays : délai avant chaque nouvelle tentative. Au-delà, le message part en file morte.
+var retryDelays = []time.Duration{5 * time.Second, 30 * time.Second, 2 * time.Minute}
+
+// RetryQueueName nomme la file d'attente de la n-ième nouvelle tentative (n à partir de 1)
+func RetryQueueName(queueName string, attempt int) string {
+	return fmt.Sprintf("%s.retry.%d", queueName, attempt)
+}
+
+// DeadLetterQueueName nomme la file morte associée à une file de travail
+func DeadLetterQueueName(queueName string) string {
+	return queueName + ".dlq"
+}
+
+// declareQueueTopology déclare la file de travail, ses files de délai et sa file morte.
+// Les files de délai n'ont pas de consommateur : à l'expiration du TTL, RabbitMQ
+// renvoie le message vers la file de travail via l'échange par défaut.
+func declareQueueTopology(ch *amqp.Channel, name string) error {
+	// La file de travail garde ses arguments historiques (aucun) pour rester compatible
+	if _, err := ch.QueueDeclare(name, true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare queue %s: %w", name, err)
+	}
+	for i, delay := range retryDelays {
+		args := amqp.Table{
+			"x-message-ttl":             delay.Milliseconds(),
+			"x-dead-letter-exchange":    "",
+			"x-dead-letter-routing-key": name,
+		}
+		retryQueue := RetryQueueName(name, i+1)
+		if _, err := ch.QueueDeclare(retryQueue, true, false, false, false, args); err != nil {
+			return fmt.Errorf("failed to declare queue %s: %w", retryQueue, err)
+		}
+	}
+	if _, err := ch.QueueDeclare(DeadLetterQueueName(name), true, false, false, false, nil); err != nil {
+		return fmt.Errorf("failed to declare queue %s: %w", DeadLetterQueueName(name), err)
+	}
+	return nil
+}
+
+// DeadLetter est un message ayant épuisé ses nouvelles tentatives
+type DeadLetter struct {
+	MessageID  string          `json:"message_id"`
+	Queue      string          `json:"queue"`
+	RetryCount int             `json:"retry_count"`
+	LastError  string          `json:"last_error"`
+	FailedAt   time.Time       `json:"failed_at"`
+	Body       json.RawMessage `json:"body"`
+}
+
+// DeadLetterManager permet d'inspecter et de rejouer les files mortes
+type DeadLetterManager interface {
+	ListDeadLetters(ctx context.Context, queueName string, limit int) ([]DeadLetter, error)
+	// ReplayDeadLetters renvoie les messages vers leur file d'origine (tous si messageIDs est vide)
+	ReplayDeadLetters(ctx context.Context, queueName string, messageIDs []string) (int, error)
+}
+
+// retryCount lit le compteur de tentatives (les entiers AMQP arrivent sous plusieurs types)
+func retryCount(headers amqp.Table) int {
+	switch v := headers[HeaderRetryCount].(type) {
+	case int32:
+		return int(v)
+	case int64:
+		return int(v)
+	case int:
+		return v
+	default:
+		return 0
+	}
+}
+
+// replayHeaders reprend les en-têtes d'un message mort (trace, identifiant de requête, diagnostic)
+// et ne remet à zéro que le compteur de tentatives
+func replayHeaders(headers amqp.Table) amqp.Table {
+	replayed := amqp.Table{}
+	for k, v := range headers {
+		replayed[k] = v
+	}
+	delete(replayed, HeaderRetryCount)
+	return replayed
+}
+
+func headerString(headers amqp.Table, key string) string {
+	if s, ok := headers[key].(string); ok {
+		return s
+	}
+	return ""
+}
+
+func toDeadLetter(queueName string, d amqp.Delivery) DeadLetter {
+	body := json.RawMessage(d.Body)
+	if !json.Valid(d.Body) {
+		body, _ = json.Marshal(string(d.Body))
+	}
+	failedAt, _ := time.Parse(time.RFC3339, headerString(d.Headers, HeaderFailedAt))
+	return DeadLetter{
+		MessageID:  d.MessageId,
+		Queue:      queueName,
+		RetryCount: retryCount(d.Headers),
+		LastError:  headerString(d.Headers, HeaderLastError),
+		FailedAt:   failedAt,
+		Body:       body,
+	}
+}
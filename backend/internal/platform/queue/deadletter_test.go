@@ -0,0 +1,29 @@
+package queue
+
+import (
+	"testing"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+func TestReplayHeadersResetOnlyRetryCount(t *testing.T) {
+	dead := amqp.Table{
+		"traceparent":       "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		"X-Request-ID":      "req-1",
+		HeaderRetryCount:    int32(4),
+		HeaderLastError:     "boom",
+		HeaderOriginalQueue: "new_reports",
+	}
+	replayed := replayHeaders(dead)
+	if _, ok := replayed[HeaderRetryCount]; ok {
+		t.Errorf("retry count kept: %v", replayed)
+	}
+	for _, key := range []string{"traceparent", "X-Request-ID", HeaderLastError, HeaderOriginalQueue} {
+		if replayed[key] != dead[key] {
+			t.Errorf("%s = %v, want %v", key, replayed[key], dead[key])
+		}
+	}
+	if retryCount(dead) != 4 {
+		t.Error("dead letter headers modified")
+	}
+}
@@ -0,0 +1,284 @@
+package queue
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// memoryQueueBuffer : capacité d'une file en mémoire avant que Publish ne bloque
+const memoryQueueBuffer = 1024
+
+var errBrokerClosed = errors.New("queue broker closed")
+
+// MemoryBroker est une implémentation en processus (canaux Go) de Publisher, Consumer et
+// DeadLetterManager, pour les tests, le développement local et les déploiements mono-nœud.
+// Les messages ne survivent pas à un redémarrage : l'outbox reste la source de vérité.
+type MemoryBroker struct {
+	mu       sync.Mutex
+	queues   map[string]*memoryQueue
+	bindings map[string][]string // File -> motifs de clés de routage (échange des événements)
+	opts     ConsumerOptions
+	inflight inflight
+	closed   chan struct{}
+	once     sync.Once
+}
+
+type memoryQueue struct {
+	messages chan memoryMessage
+	dead     []memoryMessage
+}
+
+type memoryMessage struct {
+	id          string
+	body        []byte
+	headers     map[string]string // Contexte de trace du producteur
+	publishedAt time.Time
+	attempts    int
+	lastErr     string
+	failedAt    time.Time
+}
+
+func NewMemoryBroker(opts ConsumerOptions) *MemoryBroker {
+	return &MemoryBroker{
+		queues:   make(map[string]*memoryQueue),
+		bindings: make(map[string][]string),
+		opts:     opts.normalized(),
+		closed:   make(chan struct{}),
+	}
+}
+
+func (b *MemoryBroker) queue(name string) *memoryQueue {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	q, ok := b.queues[name]
+	if !ok {
+		q = &memoryQueue{messages: make(chan memoryMessage, memoryQueueBuffer)}
+		b.queues[name] = q
+	}
+	return q
+}
+
+func (b *MemoryBroker) Publish(ctx context.Context, queueName string, message interface{}) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	return tracePublish(ctx, queueName, func(ctx context.Context, headers map[string]string) error {
+		return b.enqueue(ctx, queueName, memoryMessage{id: uuid.New().String(), body: body, headers: headers, publishedAt: time.Now()})
+	})
+}
+
+func (b *MemoryBroker) PublishTopic(ctx context.Context, routingKey string, message interface{}) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	id := uuid.New().String()
+	return tracePublish(ctx, routingKey, func(ctx context.Context, headers map[string]string) error {
+		for _, queueName := range b.boundQueues(routingKey) {
+			if err := b.enqueue(ctx, queueName, memoryMessage{id: id, body: body, headers: headers, publishedAt: time.Now()}); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// boundQueues liste les files dont un motif correspond à la clé de routage
+func (b *MemoryBroker) boundQueues(routingKey string) []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var matched []string
+	for queueName, patterns := range b.bindings {
+		for _, pattern := range patterns {
+			if topicMatches(pattern, routingKey) {
+				matched = append(matched, queueName)
+				break
+			}
+		}
+	}
+	return matched
+}
+
+func (b *MemoryBroker) Bind(ctx context.Context, queueName string, patterns ...string) error {
+	b.queue(queueName)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, pattern := range patterns {
+		if !containsString(b.bindings[queueName], pattern) {
+			b.bindings[queueName] = append(b.bindings[queueName], pattern)
+		}
+	}
+	return nil
+}
+
+func containsString(values []string, s string) bool {
+	for _, v := range values {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func (b *MemoryBroker) enqueue(ctx context.Context, queueName string, msg memoryMessage) error {
+	q := b.queue(queueName)
+	select {
+	case <-b.closed:
+		return errBrokerClosed
+	default:
+	}
+	select {
+	case q.messages <- msg:
+		return nil
+	case <-b.closed:
+		return errBrokerClosed
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (b *MemoryBroker) Consume(ctx context.Context, queueName string, handler func(ctx context.Context, body []byte) error) error {
+	q := b.queue(queueName)
+	b.inflight.wg.Add(1)
+	go func() {
+		defer b.inflight.wg.Done()
+		var running sync.WaitGroup
+		defer running.Wait()
+		slots := make(chan struct{}, b.opts.Workers)
+
+		for {
+			// Un worker libre est réservé avant de retirer un message de la file
+			select {
+			case slots <- struct{}{}:
+			case <-ctx.Done():
+				return
+			case <-b.closed:
+				return
+			}
+			select {
+			case <-ctx.Done():
+				return
+			case <-b.closed:
+				return
+			case msg := <-q.messages:
+				running.Add(1)
+				go func() {
+					defer func() {
+						<-slots
+						running.Done()
+					}()
+					b.handle(ctx, queueName, q, handler, msg)
+				}()
+			}
+		}
+	}()
+	return nil
+}
+
+func (b *MemoryBroker) handle(ctx context.Context, queueName string, q *memoryQueue, handler func(ctx context.Context, body []byte) error, msg memoryMessage) {
+	processCtx, cancel := b.opts.messageContext(ctx)
+	defer cancel()
+	msgCtx, err := traceConsume(processCtx, queueName, msg.id, msg.headers, msg.publishedAt, msg.body, handler)
+	if err == nil {
+		return
+	}
+
+	msg.attempts++
+	msg.lastErr = err.Error()
+
+	if msg.attempts > len(retryDelays) {
+		msg.failedAt = time.Now()
+		b.mu.Lock()
+		q.dead = append(q.dead, msg)
+		b.mu.Unlock()
+		logger.WarnContext(msgCtx, "Message envoyé en file morte", "queue", queueName, "message_id", msg.id, "retries", msg.attempts-1)
+		return
+	}
+
+	// Nouvelle tentative différée, sans bloquer la consommation
+	time.AfterFunc(retryDelays[msg.attempts-1], func() {
+		if err := b.enqueue(context.Background(), queueName, msg); err != nil && !errors.Is(err, errBrokerClosed) {
+			logger.ErrorContext(msgCtx, "Nouvelle tentative impossible", "queue", queueName, "message_id", msg.id, "error", err)
+		}
+	})
+}
+
+func (b *MemoryBroker) ListDeadLetters(ctx context.Context, queueName string, limit int) ([]DeadLetter, error) {
+	q := b.queue(queueName)
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	letters := []DeadLetter{}
+	for _, msg := range q.dead {
+		if len(letters) == limit {
+			break
+		}
+		letters = append(letters, DeadLetter{
+			MessageID:  msg.id,
+			Queue:      queueName,
+			RetryCount: msg.attempts,
+			LastError:  msg.lastErr,
+			FailedAt:   msg.failedAt,
+			Body:       json.RawMessage(msg.body),
+		})
+	}
+	return letters, nil
+}
+
+func (b *MemoryBroker) ReplayDeadLetters(ctx context.Context, queueName string, messageIDs []string) (int, error) {
+	q := b.queue(queueName)
+	wanted := make(map[string]bool, len(messageIDs))
+	for _, id := range messageIDs {
+		wanted[id] = true
+	}
+
+	b.mu.Lock()
+	var replay, keep []memoryMessage
+	for _, msg := range q.dead {
+		if len(wanted) == 0 || wanted[msg.id] {
+			replay = append(replay, memoryMessage{id: msg.id, body: msg.body, headers: msg.headers, publishedAt: time.Now()})
+		} else {
+			keep = append(keep, msg)
+		}
+	}
+	q.dead = keep
+	b.mu.Unlock()
+
+	for i, msg := range replay {
+		if err := b.enqueue(ctx, queueName, msg); err != nil {
+			// Les messages non rejoués retournent en file morte
+			b.mu.Lock()
+			q.dead = append(q.dead, replay[i:]...)
+			b.mu.Unlock()
+			return i, err
+		}
+	}
+	return len(replay), nil
+}
+
+// Drain attend la fin des messages en cours
+func (b *MemoryBroker) Drain(ctx context.Context) error {
+	return b.inflight.wait(ctx)
+}
+
+// Ping échoue une fois le broker fermé
+func (b *MemoryBroker) Ping(ctx context.Context) error {
+	select {
+	case <-b.closed:
+		return fmt.Errorf("memory broker closed")
+	default:
+		return nil
+	}
+}
+
+// Close arrête les consommations ; il est sûr de l'appeler plusieurs fois
+func (b *MemoryBroker) Close() {
+	b.once.Do(func() { close(b.closed) })
+}
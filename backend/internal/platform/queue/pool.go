@@ -0,0 +1,72 @@
+package queue
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// ConsumerOptions règle le parallélisme et les délais de consommation
+type ConsumerOptions struct {
+	// Prefetch : messages non acquittés qu'un broker peut confier au consommateur (RabbitMQ)
+	Prefetch int
+	// Workers : messages traités simultanément par file
+	Workers int
+	// MessageTimeout : durée maximale de traitement d'un message
+	MessageTimeout time.Duration
+}
+
+// DefaultConsumerOptions retourne les réglages par défaut des consommateurs
+func DefaultConsumerOptions() ConsumerOptions {
+	return ConsumerOptions{
+		Prefetch:       20,
+		Workers:        4,
+		MessageTimeout: 2 * time.Minute,
+	}
+}
+
+// normalized complète les valeurs manquantes avec les valeurs par défaut
+func (o ConsumerOptions) normalized() ConsumerOptions {
+	def := DefaultConsumerOptions()
+	if o.Workers <= 0 {
+		o.Workers = def.Workers
+	}
+	if o.Prefetch <= 0 {
+		o.Prefetch = def.Prefetch
+	}
+	// Un prefetch inférieur au pool laisserait des workers inactifs
+	if o.Prefetch < o.Workers {
+		o.Prefetch = o.Workers
+	}
+	if o.MessageTimeout <= 0 {
+		o.MessageTimeout = def.MessageTimeout
+	}
+	return o
+}
+
+// messageContext dérive le contexte de traitement d'un message : il conserve les valeurs du
+// contexte de consommation mais pas son annulation, pour qu'un arrêt du service laisse les
+// messages en cours se terminer ; seul le délai par message les interrompt.
+func (o ConsumerOptions) messageContext(ctx context.Context) (context.Context, context.CancelFunc) {
+	return context.WithTimeout(context.WithoutCancel(ctx), o.MessageTimeout)
+}
+
+// inflight suit les boucles de consommation et leurs messages en cours, pour Drain
+type inflight struct {
+	wg sync.WaitGroup
+}
+
+// wait attend la fin des traitements en cours, ou l'expiration du contexte
+func (f *inflight) wait(ctx context.Context) error {
+	done := make(chan struct{})
+	go func() {
+		f.wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
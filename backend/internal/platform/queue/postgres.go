@@ -0,0 +1,244 @@
+package queue
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// pgPollInterval : attente entre deux scrutations d'une file vide
+const pgPollInterval = 500 * time.Millisecond
+
+// PostgresQueue implémente Publisher, Consumer et DeadLetterManager sur la table queue_jobs
+// (migration 015). Chaque message est traité dans la transaction qui le verrouille
+// (FOR UPDATE SKIP LOCKED) : si le worker meurt, la transaction est annulée et le message
+// redevient disponible pour les autres consommateurs.
+type PostgresQueue struct {
+	db       *sql.DB
+	opts     ConsumerOptions
+	inflight inflight
+	closed   chan struct{}
+	once     sync.Once
+}
+
+func NewPostgresQueue(db *sql.DB, opts ConsumerOptions) *PostgresQueue {
+	return &PostgresQueue{db: db, opts: opts.normalized(), closed: make(chan struct{})}
+}
+
+func (q *PostgresQueue) Publish(ctx context.Context, queueName string, message interface{}) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+	return tracePublish(ctx, queueName, func(ctx context.Context, headers map[string]string) error {
+		_, err := q.db.ExecContext(ctx, `INSERT INTO queue_jobs (queue, payload, headers) VALUES ($1, $2, $3)`, queueName, body, jsonHeaders(headers))
+		if err != nil {
+			return fmt.Errorf("failed to publish message: %w", err)
+		}
+		return nil
+	})
+}
+
+// jsonHeaders encode les en-têtes de trace pour la colonne headers (JSONB)
+func jsonHeaders(headers map[string]string) []byte {
+	if len(headers) == 0 {
+		return []byte(`{}`)
+	}
+	data, _ := json.Marshal(headers)
+	return data
+}
+
+// PublishTopic insère une copie du message pour chaque file liée (table queue_bindings)
+func (q *PostgresQueue) PublishTopic(ctx context.Context, routingKey string, message interface{}) error {
+	body, err := json.Marshal(message)
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	rows, err := q.db.QueryContext(ctx, `SELECT queue, pattern FROM queue_bindings`)
+	if err != nil {
+		return fmt.Errorf("failed to load bindings: %w", err)
+	}
+	var matched []string
+	for rows.Next() {
+		var queueName, pattern string
+		if err := rows.Scan(&queueName, &pattern); err != nil {
+			rows.Close()
+			return err
+		}
+		if topicMatches(pattern, routingKey) && !containsString(matched, queueName) {
+			matched = append(matched, queueName)
+		}
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+	if len(matched) == 0 {
+		return nil
+	}
+
+	return tracePublish(ctx, routingKey, func(ctx context.Context, headers map[string]string) error {
+		_, err := q.db.ExecContext(ctx, `INSERT INTO queue_jobs (queue, payload, headers) SELECT unnest($1::text[]), $2, $3`,
+			pq.Array(matched), body, jsonHeaders(headers))
+		if err != nil {
+			return fmt.Errorf("failed to publish message: %w", err)
+		}
+		return nil
+	})
+}
+
+func (q *PostgresQueue) Bind(ctx context.Context, queueName string, patterns ...string) error {
+	for _, pattern := range patterns {
+		_, err := q.db.ExecContext(ctx, `INSERT INTO queue_bindings (queue, pattern) VALUES ($1, $2) ON CONFLICT DO NOTHING`, queueName, pattern)
+		if err != nil {
+			return fmt.Errorf("failed to bind %s to %s: %w", queueName, pattern, err)
+		}
+	}
+	return nil
+}
+
+// Consume lance un pool de Workers scrutateurs : SKIP LOCKED garantit qu'un message
+// n'est traité que par un seul d'entre eux
+func (q *PostgresQueue) Consume(ctx context.Context, queueName string, handler func(ctx context.Context, body []byte) error) error {
+	for i := 0; i < q.opts.Workers; i++ {
+		q.inflight.wg.Add(1)
+		go func() {
+			defer q.inflight.wg.Done()
+			for {
+				select {
+				case <-ctx.Done():
+					return
+				case <-q.closed:
+					return
+				default:
+				}
+				processed, err := q.processNext(ctx, queueName, handler)
+				if err != nil {
+					logger.Error("Erreur de consommation", "queue", queueName, "error", err)
+				}
+				if processed {
+					continue
+				}
+				select {
+				case <-ctx.Done():
+					return
+				case <-q.closed:
+					return
+				case <-time.After(pgPollInterval):
+				}
+			}
+		}()
+	}
+	return nil
+}
+
+// processNext verrouille et traite le plus ancien message disponible ; retourne false si la file est vide
+func (q *PostgresQueue) processNext(ctx context.Context, queueName string, handler func(ctx context.Context, body []byte) error) (bool, error) {
+	// La transaction vit avec le message : un arrêt du service la laisse se terminer
+	ctx, cancel := q.opts.messageContext(ctx)
+	defer cancel()
+	tx, err := q.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	var id string
+	var body, rawHeaders []byte
+	var attempts int
+	var createdAt time.Time
+	query := `SELECT id, payload, attempts, headers, created_at FROM queue_jobs
+	          WHERE queue = $1 AND status = 'ready' AND available_at <= NOW()
+	          ORDER BY created_at
+	          LIMIT 1
+	          FOR UPDATE SKIP LOCKED`
+	err = tx.QueryRowContext(ctx, query, queueName).Scan(&id, &body, &attempts, &rawHeaders, &createdAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+
+	var headers map[string]string
+	json.Unmarshal(rawHeaders, &headers) // En-têtes illisibles : le traitement démarre une nouvelle trace
+	if msgCtx, handlerErr := traceConsume(ctx, queueName, id, headers, createdAt, body, handler); handlerErr != nil {
+		attempts++
+		if attempts > len(retryDelays) {
+			_, err = tx.ExecContext(ctx, `UPDATE queue_jobs SET status = 'dead', attempts = $1, last_error = $2, failed_at = NOW() WHERE id = $3`,
+				attempts, handlerErr.Error(), id)
+			logger.WarnContext(msgCtx, "Message envoyé en file morte", "queue", queueName, "message_id", id, "retries", attempts-1)
+		} else {
+			_, err = tx.ExecContext(ctx, `UPDATE queue_jobs SET attempts = $1, last_error = $2, available_at = NOW() + $3::interval WHERE id = $4`,
+				attempts, handlerErr.Error(), fmt.Sprintf("%d milliseconds", retryDelays[attempts-1].Milliseconds()), id)
+		}
+	} else {
+		_, err = tx.ExecContext(ctx, `DELETE FROM queue_jobs WHERE id = $1`, id)
+	}
+	if err != nil {
+		return true, err
+	}
+	return true, tx.Commit()
+}
+
+func (q *PostgresQueue) ListDeadLetters(ctx context.Context, queueName string, limit int) ([]DeadLetter, error) {
+	query := `SELECT id, payload, attempts, COALESCE(last_error, ''), failed_at FROM queue_jobs
+	          WHERE queue = $1 AND status = 'dead' ORDER BY failed_at LIMIT $2`
+	rows, err := q.db.QueryContext(ctx, query, queueName, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	letters := []DeadLetter{}
+	for rows.Next() {
+		var d DeadLetter
+		var body []byte
+		var failedAt sql.NullTime
+		if err := rows.Scan(&d.MessageID, &body, &d.RetryCount, &d.LastError, &failedAt); err != nil {
+			return nil, err
+		}
+		d.Queue = queueName
+		d.Body = body
+		d.FailedAt = failedAt.Time
+		letters = append(letters, d)
+	}
+	return letters, rows.Err()
+}
+
+func (q *PostgresQueue) ReplayDeadLetters(ctx context.Context, queueName string, messageIDs []string) (int, error) {
+	query := `UPDATE queue_jobs SET status = 'ready', attempts = 0, last_error = '', failed_at = NULL, available_at = NOW()
+	          WHERE queue = $1 AND status = 'dead'`
+	args := []interface{}{queueName}
+	if len(messageIDs) > 0 {
+		query += ` AND id::text = ANY($2)`
+		args = append(args, pq.Array(messageIDs))
+	}
+	result, err := q.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	n, _ := result.RowsAffected()
+	return int(n), nil
+}
+
+// Drain attend la fin des messages en cours
+func (q *PostgresQueue) Drain(ctx context.Context) error {
+	return q.inflight.wait(ctx)
+}
+
+// Ping vérifie la connexion à la base qui porte la table queue_jobs
+func (q *PostgresQueue) Ping(ctx context.Context) error {
+	return q.db.PingContext(ctx)
+}
+
+// Close arrête les consommations ; la connexion à la base appartient à l'appelant
+func (q *PostgresQueue) Close() {
+	q.once.Do(func() { close(q.closed) })
+}
@@ -4,57 +4,110 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/google/uuid"
 	amqp "github.com/rabbitmq/amqp091-go"
 )
 
+// Files de traitement des nouveaux signalements. Les incidents graves disposent de leur
+// propre file, consommée en parallèle : ils n'attendent pas derrière le backlog ordinaire.
+const (
+	QueueNewReports    = "new_reports"
+	QueueUrgentReports = "new_reports.urgent"
+
+	// UrgentSeverity : sévérité (1-5) à partir de laquelle un signalement passe en file urgente
+	UrgentSeverity = 4
+)
+
+// ReportQueues liste les files de signalements, de la plus prioritaire à la moins prioritaire
+var ReportQueues = []string{QueueUrgentReports, QueueNewReports}
+
+// ReportQueue retourne la file adaptée à la sévérité d'un signalement
+func ReportQueue(severity int) string {
+	if severity >= UrgentSeverity {
+		return QueueUrgentReports
+	}
+	return QueueNewReports
+}
+
+// declareReportQueues s'assure que les files de signalements (et leurs files de délai/mortes) existent
+func declareReportQueues(ch *amqp.Channel) error {
+	for _, name := range ReportQueues {
+		if err := declareQueueTopology(ch, name); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 type Publisher interface {
 	Publish(ctx context.Context, queueName string, message interface{}) error
+	// PublishTopic publie sur l'échange des événements : chaque file liée par Bind
+	// à un motif correspondant à routingKey en reçoit une copie
+	PublishTopic(ctx context.Context, routingKey string, message interface{}) error
 	Close()
 }
 
+// Consumer distribue les messages d'une file à un pool de workers. L'annulation du contexte
+// passé à Consume arrête la réception ; Drain attend ensuite la fin des messages en cours.
 type Consumer interface {
 	Consume(ctx context.Context, queueName string, handler func(ctx context.Context, body []byte) error) error
+	// Bind crée la file si besoin et l'abonne aux clés de routage de l'échange des événements
+	Bind(ctx context.Context, queueName string, patterns ...string) error
+	Drain(ctx context.Context) error
 	Close()
 }
 
+// rabbitPublisher publie en mode confirm : Publish ne retourne qu'après l'accusé du broker
 type rabbitPublisher struct {
-	conn    *amqp.Connection
+	conn    *rabbitConnection
+	mu      sync.Mutex
 	channel *amqp.Channel
 }
 
 func NewRabbitPublisher(url string) (Publisher, error) {
-	conn, err := amqp.Dial(url)
+	conn, err := dialRabbit(url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+		return nil, err
 	}
 
-	ch, err := conn.Channel()
+	p := &rabbitPublisher{conn: conn}
+	p.mu.Lock()
+	_, err = p.ensureChannel(context.Background())
+	p.mu.Unlock()
 	if err != nil {
 		conn.Close()
-		return nil, fmt.Errorf("failed to open a channel: %w", err)
+		return nil, err
 	}
+	return p, nil
+}
 
-	// Déclarer la queue pour s'assurer qu'elle existe
-	_, err = ch.QueueDeclare(
-		"new_reports", // name
-		true,          // durable
-		false,         // delete when unused
-		false,         // exclusive
-		false,         // no-wait
-		nil,           // arguments
-	)
+// ensureChannel (appelé sous verrou) rouvre un canal en mode confirm si le précédent est tombé
+func (p *rabbitPublisher) ensureChannel(ctx context.Context) (*amqp.Channel, error) {
+	if p.channel != nil && !p.channel.IsClosed() {
+		return p.channel, nil
+	}
+	ch, err := p.conn.channel(ctx)
 	if err != nil {
+		return nil, err
+	}
+	if err := ch.Confirm(false); err != nil {
 		ch.Close()
-		conn.Close()
-		return nil, fmt.Errorf("failed to declare queue: %w", err)
+		return nil, fmt.Errorf("failed to enable publisher confirms: %w", err)
 	}
-
-	return &rabbitPublisher{
-		conn:    conn,
-		channel: ch,
-	}, nil
+	if err := ch.ExchangeDeclare(EventsExchange, "topic", true, false, false, false, nil); err != nil {
+		ch.Close()
+		return nil, fmt.Errorf("failed to declare exchange %s: %w", EventsExchange, err)
+	}
+	// Déclarer les queues pour s'assurer qu'elles existent
+	if err := declareReportQueues(ch); err != nil {
+		ch.Close()
+		return nil, err
+	}
+	p.channel = ch
+	return ch, nil
 }
 
 func (p *rabbitPublisher) Publish(ctx context.Context, queueName string, message interface{}) error {
@@ -66,73 +119,144 @@ func (p *rabbitPublisher) Publish(ctx context.Context, queueName string, message
 	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
 	defer cancel()
 
-	err = p.channel.PublishWithContext(ctx,
-		"",        // exchange
-		queueName, // routing key
-		false,     // mandatory
-		false,     // immediate
-		amqp.Publishing{
-			ContentType: "application/json",
-			Body:        body,
-			Timestamp:   time.Now(),
+	return tracePublish(ctx, queueName, func(ctx context.Context, headers map[string]string) error {
+		return p.publish(ctx, "", queueName, amqp.Publishing{
+			Headers:      withTraceHeaders(nil, headers),
+			ContentType:  "application/json",
+			DeliveryMode: amqp.Persistent,
+			MessageId:    uuid.New().String(),
+			Body:         body,
+			Timestamp:    time.Now(),
 		})
+	})
+}
 
+func (p *rabbitPublisher) PublishTopic(ctx context.Context, routingKey string, message interface{}) error {
+	body, err := json.Marshal(message)
 	if err != nil {
-		return fmt.Errorf("failed to publish message: %w", err)
+		return fmt.Errorf("failed to marshal message: %w", err)
 	}
 
-	return nil
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	return tracePublish(ctx, routingKey, func(ctx context.Context, headers map[string]string) error {
+		return p.publish(ctx, EventsExchange, routingKey, amqp.Publishing{
+			Headers:      withTraceHeaders(nil, headers),
+			ContentType:  "application/json",
+			DeliveryMode: amqp.Persistent,
+			MessageId:    uuid.New().String(),
+			Body:         body,
+			Timestamp:    time.Now(),
+		})
+	})
+}
+
+// publish envoie un message (échange par défaut : routingKey est le nom de la file) et attend
+// la confirmation du broker. Une erreur de canal (broker redémarré) provoque une reconnexion
+// et une seconde tentative.
+func (p *rabbitPublisher) publish(ctx context.Context, exchange, routingKey string, msg amqp.Publishing) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var lastErr error
+	for attempt := 0; attempt < 2; attempt++ {
+		ch, err := p.ensureChannel(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to open channel: %w", err)
+		}
+
+		confirm, err := ch.PublishWithDeferredConfirmWithContext(ctx,
+			exchange,
+			routingKey,
+			false, // mandatory
+			false, // immediate
+			msg)
+		if err != nil {
+			lastErr = err
+			ch.Close()
+			p.channel = nil
+			continue
+		}
+
+		acked, err := confirm.WaitContext(ctx)
+		if err != nil {
+			return fmt.Errorf("failed to confirm publication: %w", err)
+		}
+		if !acked {
+			return fmt.Errorf("message rejected by broker (routing key %s)", routingKey)
+		}
+		return nil
+	}
+	return fmt.Errorf("failed to publish message: %w", lastErr)
+}
+
+// Ping rouvre au besoin le canal de publication : la connexion est rétablie dans le délai du contexte
+func (p *rabbitPublisher) Ping(ctx context.Context) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	_, err := p.ensureChannel(ctx)
+	return err
 }
 
 func (p *rabbitPublisher) Close() {
+	p.mu.Lock()
 	if p.channel != nil {
 		p.channel.Close()
 	}
-	if p.conn != nil {
-		p.conn.Close()
-	}
+	p.mu.Unlock()
+	p.conn.Close()
 }
 
+// rabbitConsumer consomme avec acquittement manuel et se réabonne après une reconnexion.
+// Un message en échec est republié dans une file de délai (nouvelle tentative différée),
+// puis dans la file morte une fois les tentatives épuisées.
 type rabbitConsumer struct {
-	conn    *amqp.Connection
-	channel *amqp.Channel
+	conn      *rabbitConnection
+	publisher *rabbitPublisher // Canal en mode confirm pour les republications
+	opts      ConsumerOptions
+	inflight  inflight
 }
 
-func NewRabbitConsumer(url string) (Consumer, error) {
-	conn, err := amqp.Dial(url)
+func NewRabbitConsumer(url string, opts ConsumerOptions) (Consumer, error) {
+	conn, err := dialRabbit(url)
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to RabbitMQ: %w", err)
+		return nil, err
 	}
 
-	ch, err := conn.Channel()
-	if err != nil {
-		conn.Close()
-		return nil, fmt.Errorf("failed to open a channel: %w", err)
-	}
-
-	// Déclarer la queue pour s'assurer qu'elle existe
-	_, err = ch.QueueDeclare(
-		"new_reports", // name
-		true,          // durable
-		false,         // delete when unused
-		false,         // exclusive
-		false,         // no-wait
-		nil,           // arguments
-	)
+	// La déclaration des queues se fait via le canal de republication
+	publisher := &rabbitPublisher{conn: conn}
+	publisher.mu.Lock()
+	_, err = publisher.ensureChannel(context.Background())
+	publisher.mu.Unlock()
 	if err != nil {
-		ch.Close()
 		conn.Close()
-		return nil, fmt.Errorf("failed to declare queue: %w", err)
+		return nil, err
 	}
 
 	return &rabbitConsumer{
-		conn:    conn,
-		channel: ch,
+		conn:      conn,
+		publisher: publisher,
+		opts:      opts.normalized(),
 	}, nil
 }
 
-func (c *rabbitConsumer) Consume(ctx context.Context, queueName string, handler func(ctx context.Context, body []byte) error) error {
-	msgs, err := c.channel.Consume(
+func (c *rabbitConsumer) subscribe(ctx context.Context, queueName string) (*amqp.Channel, <-chan amqp.Delivery, error) {
+	ch, err := c.conn.channel(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	// Topologie déclarée côté consommateur aussi : la file peut ne pas encore exister
+	if err := declareQueueTopology(ch, queueName); err != nil {
+		ch.Close()
+		return nil, nil, err
+	}
+	// Le broker ne confie pas plus de Prefetch messages non acquittés à ce canal
+	if err := ch.Qos(c.opts.Prefetch, 0, false); err != nil {
+		ch.Close()
+		return nil, nil, fmt.Errorf("failed to set prefetch: %w", err)
+	}
+	msgs, err := ch.Consume(
 		queueName,
 		"",    // consumer
 		false, // auto-ack - ON VEUT DES ACKS MANUELS
@@ -142,21 +266,81 @@ func (c *rabbitConsumer) Consume(ctx context.Context, queueName string, handler
 		nil,   // args
 	)
 	if err != nil {
-		return fmt.Errorf("failed to register a consumer: %w", err)
+		ch.Close()
+		return nil, nil, fmt.Errorf("failed to register a consumer: %w", err)
+	}
+	return ch, msgs, nil
+}
+
+func (c *rabbitConsumer) Bind(ctx context.Context, queueName string, patterns ...string) error {
+	ch, err := c.conn.channel(ctx)
+	if err != nil {
+		return err
+	}
+	defer ch.Close()
+
+	if err := declareQueueTopology(ch, queueName); err != nil {
+		return err
+	}
+	for _, pattern := range patterns {
+		if err := ch.QueueBind(queueName, pattern, EventsExchange, false, nil); err != nil {
+			return fmt.Errorf("failed to bind %s to %s: %w", queueName, pattern, err)
+		}
+	}
+	return nil
+}
+
+func (c *rabbitConsumer) Consume(ctx context.Context, queueName string, handler func(ctx context.Context, body []byte) error) error {
+	ch, msgs, err := c.subscribe(ctx, queueName)
+	if err != nil {
+		return err
 	}
 
+	c.inflight.wg.Add(1)
 	go func() {
-		for d := range msgs {
-			// Créer un contexte fils pour chaque message si nécessaire
-			processCtx := context.Background()
-			err := handler(processCtx, d.Body)
-			if err != nil {
-				fmt.Printf("Error processing message: %v\n", err)
-				// En cas d'erreur, on peut choisir de requeue ou non
-				// Ici on ne requeue pas par défaut pour éviter les boucles infinies sur messages malformés
-				d.Nack(false, false)
-			} else {
-				d.Ack(false)
+		defer c.inflight.wg.Done()
+		var running sync.WaitGroup
+		slots := make(chan struct{}, c.opts.Workers)
+		defer func() {
+			// Les messages en cours sont acquittés avant la fermeture du canal ;
+			// les messages préchargés mais non traités sont rendus au broker
+			running.Wait()
+			ch.Close()
+		}()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case d, ok := <-msgs:
+				if ok {
+					select {
+					case slots <- struct{}{}:
+					case <-ctx.Done():
+						return // Non acquitté : rendu à la file à la fermeture du canal
+					}
+					running.Add(1)
+					go func() {
+						defer func() {
+							<-slots
+							running.Done()
+						}()
+						c.handle(ctx, queueName, handler, d)
+					}()
+					continue
+				}
+				// Canal fermé : connexion perdue, on se réabonne dès que le broker revient
+				if c.conn.isClosed() || ctx.Err() != nil {
+					return
+				}
+				logger.Warn("Consommation interrompue, reconnexion", "queue", queueName)
+				newCh, newMsgs, err := c.subscribe(ctx, queueName)
+				if err != nil {
+					logger.Error("Abandon de la consommation", "queue", queueName, "error", err)
+					return
+				}
+				ch, msgs = newCh, newMsgs
+				logger.Info("Consommation rétablie", "queue", queueName)
 			}
 		}
 	}()
@@ -164,11 +348,136 @@ func (c *rabbitConsumer) Consume(ctx context.Context, queueName string, handler
 	return nil
 }
 
-func (c *rabbitConsumer) Close() {
-	if c.channel != nil {
-		c.channel.Close()
+func (c *rabbitConsumer) handle(ctx context.Context, queueName string, handler func(ctx context.Context, body []byte) error, d amqp.Delivery) {
+	processCtx, cancel := c.opts.messageContext(ctx)
+	defer cancel()
+	// Les nouvelles tentatives conservent l'horodatage et les en-têtes d'origine :
+	// l'attente mesurée court depuis la première publication
+	msgCtx, err := traceConsume(processCtx, queueName, d.MessageId, stringHeaders(d.Headers), d.Timestamp, d.Body, handler)
+	if err == nil {
+		d.Ack(false)
+		return
+	}
+	c.retryOrDeadLetter(msgCtx, queueName, d, err)
+}
+
+// retryOrDeadLetter republie le message en file de délai, ou en file morte après la dernière tentative.
+// Le message d'origine n'est acquitté qu'une fois la republication confirmée.
+func (c *rabbitConsumer) retryOrDeadLetter(ctx context.Context, queueName string, d amqp.Delivery, cause error) {
+	attempt := retryCount(d.Headers) + 1
+
+	headers := amqp.Table{}
+	for k, v := range d.Headers {
+		headers[k] = v
+	}
+	headers[HeaderRetryCount] = int32(attempt)
+	headers[HeaderLastError] = cause.Error()
+	headers[HeaderOriginalQueue] = queueName
+
+	target := DeadLetterQueueName(queueName)
+	if attempt <= len(retryDelays) {
+		target = RetryQueueName(queueName, attempt)
+	} else {
+		headers[HeaderFailedAt] = time.Now().UTC().Format(time.RFC3339)
+		logger.WarnContext(ctx, "Message envoyé en file morte", "queue", queueName, "message_id", d.MessageId, "retries", attempt-1)
+	}
+
+	pubCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	err := c.publisher.publish(pubCtx, "", target, amqp.Publishing{
+		Headers:      headers,
+		ContentType:  d.ContentType,
+		DeliveryMode: amqp.Persistent,
+		MessageId:    d.MessageId,
+		Timestamp:    d.Timestamp,
+		Body:         d.Body,
+	})
+	if err != nil {
+		// Sans republication confirmée, on rend le message à la file plutôt que de le perdre
+		logger.ErrorContext(ctx, "Republication impossible, remise en file", "queue", queueName, "message_id", d.MessageId, "error", err)
+		d.Nack(false, true)
+		return
+	}
+	d.Ack(false)
+}
+
+// ListDeadLetters lit les messages de la file morte sans les consommer : les messages
+// non acquittés sont rendus à la file à la fermeture du canal.
+func (c *rabbitConsumer) ListDeadLetters(ctx context.Context, queueName string, limit int) ([]DeadLetter, error) {
+	ch, err := c.conn.channel(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer ch.Close()
+
+	letters := []DeadLetter{}
+	for len(letters) < limit {
+		d, ok, err := ch.Get(DeadLetterQueueName(queueName), false)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read dead letters: %w", err)
+		}
+		if !ok {
+			break
+		}
+		letters = append(letters, toDeadLetter(queueName, d))
+	}
+	return letters, nil
+}
+
+// ReplayDeadLetters renvoie des messages de la file morte vers leur file d'origine avec leurs
+// en-têtes (la trace et la corrélation se poursuivent), compteur de tentatives remis à zéro
+func (c *rabbitConsumer) ReplayDeadLetters(ctx context.Context, queueName string, messageIDs []string) (int, error) {
+	ch, err := c.conn.channel(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer ch.Close()
+
+	wanted := make(map[string]bool, len(messageIDs))
+	for _, id := range messageIDs {
+		wanted[id] = true
 	}
-	if c.conn != nil {
-		c.conn.Close()
+
+	replayed := 0
+	remaining := -1
+	for remaining != 0 {
+		d, ok, err := ch.Get(DeadLetterQueueName(queueName), false)
+		if err != nil {
+			return replayed, fmt.Errorf("failed to read dead letters: %w", err)
+		}
+		if !ok {
+			break
+		}
+		remaining = int(d.MessageCount)
+
+		if len(wanted) > 0 && !wanted[d.MessageId] {
+			continue // Rendu à la file à la fermeture du canal
+		}
+
+		pubCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+		err = c.publisher.publish(pubCtx, "", queueName, amqp.Publishing{
+			Headers:      replayHeaders(d.Headers),
+			ContentType:  d.ContentType,
+			DeliveryMode: amqp.Persistent,
+			MessageId:    d.MessageId,
+			Timestamp:    d.Timestamp,
+			Body:         d.Body,
+		})
+		cancel()
+		if err != nil {
+			return replayed, fmt.Errorf("failed to replay message %s: %w", d.MessageId, err)
+		}
+		d.Ack(false)
+		replayed++
 	}
+	return replayed, nil
+}
+
+// Drain attend la fin des messages en cours sur toutes les files consommées
+func (c *rabbitConsumer) Drain(ctx context.Context) error {
+	return c.inflight.wait(ctx)
+}
+
+func (c *rabbitConsumer) Close() {
+	c.publisher.Close()
 }
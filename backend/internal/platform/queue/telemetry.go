@@ -0,0 +1,75 @@
+package queue
+
+import (
+	"context"
+	"time"
+
+	"github.com/openvote/backend/internal/platform/logging"
+	"github.com/openvote/backend/internal/platform/telemetry"
+	amqp "github.com/rabbitmq/amqp091-go"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracePublish mesure une publication et ouvre le span producteur ; publish reçoit les
+// en-têtes de trace (traceparent...) et l'identifiant de requête à joindre au message pour
+// que le consommateur poursuive la même trace et la même corrélation du journal
+func tracePublish(ctx context.Context, destination string, publish func(ctx context.Context, headers map[string]string) error) error {
+	ctx, span := telemetry.StartSpan(ctx, destination+" publish",
+		trace.WithSpanKind(trace.SpanKindProducer),
+		trace.WithAttributes(semconv.MessagingDestinationName(destination), semconv.MessagingOperationTypePublish))
+	start := time.Now()
+	err := publish(ctx, logging.InjectRequestID(ctx, telemetry.InjectHeaders(ctx)))
+	telemetry.ObserveQueue(destination, telemetry.QueuePublish, start, err)
+	telemetry.EndSpan(span, err)
+	return err
+}
+
+// traceConsume exécute le handler dans un span consommateur rattaché à la trace (et à
+// l'identifiant de requête) du producteur, et mesure l'attente du message (publishedAt,
+// zéro si inconnu) puis son traitement. Le contexte retourné porte cette corrélation pour
+// la suite du traitement (nouvelle tentative, file morte).
+func traceConsume(ctx context.Context, queueName, messageID string, headers map[string]string, publishedAt time.Time, body []byte, handler func(ctx context.Context, body []byte) error) (context.Context, error) {
+	telemetry.ObserveQueueLag(queueName, publishedAt)
+	ctx = logging.ExtractRequestID(telemetry.ExtractHeaders(ctx, headers), headers)
+	ctx, span := telemetry.StartSpan(ctx, queueName+" process",
+		trace.WithSpanKind(trace.SpanKindConsumer),
+		trace.WithAttributes(
+			semconv.MessagingDestinationName(queueName),
+			semconv.MessagingOperationTypeDeliver,
+			semconv.MessagingMessageID(messageID),
+		))
+	start := time.Now()
+	err := handler(ctx, body)
+	telemetry.ObserveQueue(queueName, telemetry.QueueConsume, start, err)
+	telemetry.EndSpan(span, err)
+	if err != nil {
+		logger.ErrorContext(ctx, "Échec du traitement d'un message", "queue", queueName, "message_id", messageID, "error", err)
+	}
+	return ctx, err
+}
+
+// withTraceHeaders ajoute les en-têtes de trace aux en-têtes AMQP
+func withTraceHeaders(table amqp.Table, headers map[string]string) amqp.Table {
+	if len(headers) == 0 {
+		return table
+	}
+	if table == nil {
+		table = amqp.Table{}
+	}
+	for k, v := range headers {
+		table[k] = v
+	}
+	return table
+}
+
+// stringHeaders extrait les en-têtes textuels d'un message AMQP (dont ceux de trace)
+func stringHeaders(table amqp.Table) map[string]string {
+	headers := make(map[string]string, len(table))
+	for k, v := range table {
+		if s, ok := v.(string); ok {
+			headers[k] = s
+		}
+	}
+	return headers
+}
@@ -0,0 +1,31 @@
+package queue
+
+import "strings"
+
+// EventsExchange : échange topic des événements métier, routés par type ("report.created")
+const EventsExchange = "openvote.events"
+
+// topicMatches applique la sémantique des échanges topic AMQP : les mots sont séparés par
+// des points, "*" remplace exactement un mot et "#" zéro ou plusieurs mots.
+func topicMatches(pattern, key string) bool {
+	return matchWords(strings.Split(pattern, "."), strings.Split(key, "."))
+}
+
+func matchWords(pattern, key []string) bool {
+	if len(pattern) == 0 {
+		return len(key) == 0
+	}
+	switch pattern[0] {
+	case "#":
+		for i := 0; i <= len(key); i++ {
+			if matchWords(pattern[1:], key[i:]) {
+				return true
+			}
+		}
+		return false
+	case "*":
+		return len(key) > 0 && matchWords(pattern[1:], key[1:])
+	default:
+		return len(key) > 0 && pattern[0] == key[0] && matchWords(pattern[1:], key[1:])
+	}
+}
@@ -0,0 +1,147 @@
+package telemetry
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const namespace = "openvote"
+
+// Issue d'une opération, en label des métriques
+const (
+	OutcomeOK    = "ok"
+	OutcomeError = "error"
+)
+
+// Opérations de file
+const (
+	QueuePublish = "publish"
+	QueueConsume = "consume"
+)
+
+// registry regroupe les métriques du processus (API ou worker), exposées sur /metrics
+var registry = prometheus.NewRegistry()
+
+var (
+	httpRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "Requêtes HTTP traitées, par route et code de statut.",
+	}, []string{"method", "route", "status"})
+
+	httpDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Durée de traitement des requêtes HTTP, par route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	queueMessages = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "queue_messages_total",
+		Help:      "Messages publiés et consommés, par file (ou clé de routage) et issue.",
+	}, []string{"queue", "operation", "outcome"})
+
+	queueDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "queue_operation_duration_seconds",
+		Help:      "Durée de publication (confirmation comprise) et de traitement des messages.",
+		Buckets:   []float64{.005, .01, .025, .05, .1, .25, .5, 1, 2.5, 5, 10, 30, 60, 120},
+	}, []string{"queue", "operation"})
+
+	queueLag = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "queue_lag_seconds",
+		Help:      "Délai entre la publication d'un message et le début de son traitement.",
+		Buckets:   []float64{.01, .05, .1, .5, 1, 5, 10, 30, 60, 300, 900, 3600},
+	}, []string{"queue"})
+
+	triangulationDecisions = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "triangulation_decisions_total",
+		Help:      "Décisions de triangulation, par issue.",
+	}, []string{"outcome"})
+
+	modelDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "ollama_request_duration_seconds",
+		Help:      "Durée des appels à Ollama (embedding, génération LLM), par modèle et issue.",
+		Buckets:   []float64{.05, .1, .25, .5, 1, 2.5, 5, 10, 20, 30, 60, 120, 180},
+	}, []string{"operation", "model", "outcome"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		httpRequests, httpDuration,
+		queueMessages, queueDuration, queueLag,
+		triangulationDecisions,
+		modelDuration,
+	)
+}
+
+// Handler expose les métriques au format Prometheus
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// RegisterDBStats expose les statistiques du pool de connexions (ouvertes, en attente...)
+func RegisterDBStats(db *sql.DB) {
+	if db == nil {
+		return
+	}
+	err := registry.Register(collectors.NewDBStatsCollector(db, namespace))
+	var already prometheus.AlreadyRegisteredError
+	if err != nil && !errors.As(err, &already) {
+		telemetryLogger.Warn("Statistiques du pool non exposées", "error", err)
+	}
+}
+
+// ObserveHTTP compte une requête ; route est le motif Gin ("/api/v1/reports/:id"), pas le chemin
+func ObserveHTTP(method, route string, status int, elapsed time.Duration) {
+	httpRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	httpDuration.WithLabelValues(method, route).Observe(elapsed.Seconds())
+}
+
+// ObserveQueue compte une publication ou un traitement de message commencé à start
+func ObserveQueue(queueName, operation string, start time.Time, err error) {
+	queueMessages.WithLabelValues(queueName, operation, outcome(err)).Inc()
+	queueDuration.WithLabelValues(queueName, operation).Observe(time.Since(start).Seconds())
+}
+
+// ObserveQueueLag mesure l'attente d'un message publié à publishedAt (ignoré si inconnu)
+func ObserveQueueLag(queueName string, publishedAt time.Time) {
+	if publishedAt.IsZero() {
+		return
+	}
+	lag := time.Since(publishedAt)
+	if lag < 0 {
+		lag = 0 // Horloges de l'API et du worker décalées
+	}
+	queueLag.WithLabelValues(queueName).Observe(lag.Seconds())
+}
+
+// CountTriangulation compte une décision de triangulation
+func CountTriangulation(outcome string) {
+	triangulationDecisions.WithLabelValues(outcome).Inc()
+}
+
+// ObserveModelCall mesure un appel à Ollama commencé à start ("embedding" ou "generate")
+func ObserveModelCall(operation, model string, start time.Time, err error) {
+	modelDuration.WithLabelValues(operation, model, outcome(err)).Observe(time.Since(start).Seconds())
+}
+
+func outcome(err error) string {
+	if err != nil {
+		return OutcomeError
+	}
+	return OutcomeOK
+}
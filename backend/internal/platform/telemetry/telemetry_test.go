@@ -0,0 +1,85 @@
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func scrape(t *testing.T) string {
+	t.Helper()
+	w := httptest.NewRecorder()
+	Handler().ServeHTTP(w, httptest.NewRequest("GET", "/metrics", nil))
+	if w.Code != 200 {
+		t.Fatalf("GET /metrics = %d", w.Code)
+	}
+	return w.Body.String()
+}
+
+func TestMetricsAreExposed(t *testing.T) {
+	start := time.Now().Add(-time.Second)
+	ObserveQueue("reports.high", QueuePublish, start, nil)
+	ObserveQueue("reports.high", QueueConsume, start, errors.New("boom"))
+	ObserveQueueLag("reports.high", start)
+	ObserveQueueLag("unknown.lag", time.Time{}) // Horodatage inconnu : ignoré
+	CountTriangulation("verified")
+	ObserveModelCall("embedding", "nomic-embed-text", start, nil)
+
+	body := scrape(t)
+	for _, want := range []string{
+		`openvote_queue_messages_total{operation="publish",outcome="ok",queue="reports.high"} 1`,
+		`openvote_queue_messages_total{operation="consume",outcome="error",queue="reports.high"} 1`,
+		`openvote_queue_operation_duration_seconds_count{operation="consume",queue="reports.high"} 1`,
+		`openvote_queue_lag_seconds_count{queue="reports.high"} 1`,
+		`openvote_triangulation_decisions_total{outcome="verified"} 1`,
+		`openvote_ollama_request_duration_seconds_count{model="nomic-embed-text",operation="embedding",outcome="ok"} 1`,
+		`go_goroutines`,
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("metrics miss %s", want)
+		}
+	}
+	if strings.Contains(body, "unknown.lag") {
+		t.Error("lag observed without a publication timestamp")
+	}
+}
+
+func TestRegisterDBStatsWithoutDatabase(t *testing.T) {
+	RegisterDBStats(nil)
+	if strings.Contains(scrape(t), "openvote_max_open_connections") {
+		t.Error("pool metrics registered without a database")
+	}
+}
+
+func TestHeadersRoundTrip(t *testing.T) {
+	provider, propagator := otel.GetTracerProvider(), otel.GetTextMapPropagator()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider())
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer func() {
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagator)
+	}()
+
+	if headers := InjectHeaders(context.Background()); headers != nil {
+		t.Errorf("headers without a span = %v", headers)
+	}
+
+	ctx, span := StartSpan(context.Background(), "request")
+	defer span.End()
+	headers := InjectHeaders(ctx)
+	if headers["traceparent"] == "" {
+		t.Fatalf("headers = %v", headers)
+	}
+	got := trace.SpanContextFromContext(ExtractHeaders(context.Background(), headers))
+	if !got.IsRemote() || got.TraceID() != span.SpanContext().TraceID() || got.SpanID() != span.SpanContext().SpanID() {
+		t.Errorf("extracted %v, want %v", got, span.SpanContext())
+	}
+}
@@ -0,0 +1,100 @@
+package telemetry
+
+import (
+	"context"
+	"fmt"
+	"os"
+
+	"github.com/openvote/backend/internal/platform/logging"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var telemetryLogger = logging.For("telemetry")
+
+const instrumentationName = "github.com/openvote/backend"
+
+// SetupTracing installe la propagation W3C (traceparent, baggage) et, si
+// OTEL_EXPORTER_OTLP_ENDPOINT est défini ("http://otel-collector:4318"), l'export des spans
+// vers un collecteur OTLP/HTTP. Sans collecteur, le contexte de trace est tout de même propagé
+// (en-têtes HTTP, messages) pour ne pas casser les traces d'un appelant instrumenté.
+// La fonction retournée vide les spans en attente à l'arrêt.
+func SetupTracing(ctx context.Context, serviceName string) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(
+		propagation.TraceContext{},
+		propagation.Baggage{},
+	))
+
+	if os.Getenv("OTEL_EXPORTER_OTLP_ENDPOINT") == "" && os.Getenv("OTEL_EXPORTER_OTLP_TRACES_ENDPOINT") == "" {
+		telemetryLogger.Info("OTEL_EXPORTER_OTLP_ENDPOINT non défini, traces non exportées")
+		return func(context.Context) error { return nil }, nil
+	}
+
+	// L'exportateur lit lui-même OTEL_EXPORTER_OTLP_* (adresse, en-têtes, TLS)
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(serviceName)),
+		resource.WithFromEnv(), // OTEL_RESOURCE_ATTRIBUTES, OTEL_SERVICE_NAME
+		resource.WithTelemetrySDK(),
+		resource.WithHost(),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build trace resource: %w", err)
+	}
+
+	// Échantillonnage : OTEL_TRACES_SAMPLER / OTEL_TRACES_SAMPLER_ARG, tout par défaut
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+	telemetryLogger.Info("Export des traces activé", "service", serviceName)
+	return provider.Shutdown, nil
+}
+
+// StartSpan ouvre un span enfant du span porté par ctx
+func StartSpan(ctx context.Context, name string, opts ...trace.SpanStartOption) (context.Context, trace.Span) {
+	return otel.Tracer(instrumentationName).Start(ctx, name, opts...)
+}
+
+// EndSpan ferme le span en y consignant l'erreur éventuelle
+func EndSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// InjectHeaders sérialise le contexte de trace de ctx (traceparent, tracestate, baggage)
+// pour le transporter dans un message ; nil si ctx ne porte aucune trace
+func InjectHeaders(ctx context.Context) map[string]string {
+	carrier := propagation.MapCarrier{}
+	otel.GetTextMapPropagator().Inject(ctx, carrier)
+	if len(carrier) == 0 {
+		return nil
+	}
+	return carrier
+}
+
+// ExtractHeaders rattache à ctx le contexte de trace transporté par un message
+func ExtractHeaders(ctx context.Context, headers map[string]string) context.Context {
+	if len(headers) == 0 {
+		return ctx
+	}
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(headers))
+}
+
+// ExtractHTTP rattache à ctx le contexte de trace des en-têtes d'une requête entrante
+func ExtractHTTP(ctx context.Context, header map[string][]string) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+}
@@ -0,0 +1,257 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"math"
+	"slices"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+)
+
+type alertRepo struct{ s *Store }
+
+func NewAlertRepository(s *Store) repository.AlertRepository {
+	return &alertRepo{s: s}
+}
+
+func copyRule(rule *entity.AlertRule) entity.AlertRule {
+	copied := *rule
+	copied.IncidentTypes = cloneStrings(rule.IncidentTypes)
+	copied.RegionIDs = cloneStrings(rule.RegionIDs)
+	copied.Channels = cloneStrings(rule.Channels)
+	return copied
+}
+
+func (r *alertRepo) CreateRule(ctx context.Context, rule *entity.AlertRule) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := r.s.timestamp()
+	rule.ID, rule.CreatedAt, rule.UpdatedAt = newID(), now, now
+	stored := copyRule(rule)
+	r.s.alertRules[rule.ID] = &stored
+	return nil
+}
+
+func (r *alertRepo) UpdateRule(ctx context.Context, rule *entity.AlertRule) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, ok := r.s.alertRules[rule.ID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	rule.UpdatedAt = r.s.timestamp()
+	updated := copyRule(rule)
+	updated.CreatedBy, updated.CreatedAt = stored.CreatedBy, stored.CreatedAt
+	*stored = updated
+	return nil
+}
+
+func (r *alertRepo) DeleteRule(ctx context.Context, id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.alertRules, id)
+	for aid, a := range r.s.alerts {
+		if a.RuleID == id {
+			delete(r.s.alerts, aid)
+		}
+	}
+	return nil
+}
+
+func (r *alertRepo) GetRule(ctx context.Context, id string) (*entity.AlertRule, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	rule, ok := r.s.alertRules[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := copyRule(rule)
+	return &copied, nil
+}
+
+func (r *alertRepo) ListRules(ctx context.Context) ([]entity.AlertRule, error) {
+	return r.rules(false, func(a, b *entity.AlertRule) int { return byTime(b.CreatedAt, a.CreatedAt, b.ID, a.ID) })
+}
+
+func (r *alertRepo) ActiveRules(ctx context.Context) ([]entity.AlertRule, error) {
+	return r.rules(true, func(a, b *entity.AlertRule) int { return byTime(a.CreatedAt, b.CreatedAt, a.ID, b.ID) })
+}
+
+func (r *alertRepo) rules(activeOnly bool, order func(a, b *entity.AlertRule) int) ([]entity.AlertRule, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	rules := []entity.AlertRule{}
+	for _, rule := range sortedValues(r.s.alertRules, order) {
+		if !activeOnly || rule.Active {
+			rules = append(rules, copyRule(rule))
+		}
+	}
+	return rules, nil
+}
+
+func (r *alertRepo) ReportsInWindow(ctx context.Context, f repository.AlertReportFilter) ([]entity.Report, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var reports []entity.Report
+	for _, row := range slices.Backward(r.s.sortedReports()) {
+		if row.CreatedAt.Before(f.Since) || row.CreatedAt.After(f.Until) {
+			continue
+		}
+		if row.Severity < f.MinSeverity || row.Status == entity.StatusRejected {
+			continue
+		}
+		if len(f.IncidentTypes) > 0 && !r.s.incidentTypeMatches(row.IncidentType, f.IncidentTypes) {
+			continue
+		}
+		var regionID string
+		if u, ok := r.s.users[row.ObserverID]; ok {
+			regionID = u.RegionID
+		}
+		if len(f.RegionIDs) > 0 && !slices.Contains(f.RegionIDs, regionID) {
+			continue
+		}
+		report := entity.Report{
+			ID: row.ID, ObserverID: row.ObserverID, IncidentType: row.IncidentType, H3Index: row.H3Index,
+			Status: row.Status, Severity: row.Severity, CreatedAt: row.CreatedAt, RegionID: regionID,
+		}
+		if f.WithDepartment {
+			report.DepartmentID = r.s.nearestDepartment(row.GPSLocation)
+		}
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+// nearestDepartment : département du bureau de vote géolocalisé le plus proche (l'appelant détient le verrou)
+func (s *Store) nearestDepartment(wkt string) string {
+	lat, lon, ok := parseWKTPoint(wkt)
+	if !ok {
+		return ""
+	}
+	departmentID, best := "", math.Inf(1)
+	for _, ps := range s.stations {
+		if ps.Latitude == nil || ps.Longitude == nil {
+			continue
+		}
+		if d := distanceMeters(lat, lon, *ps.Latitude, *ps.Longitude); d < best {
+			departmentID, best = ps.DepartmentID, d
+		}
+	}
+	return departmentID
+}
+
+// incidentTypeMatches : les types d'une règle peuvent être des codes ou des noms, comme à la soumission
+func (s *Store) incidentTypeMatches(reportType string, wanted []string) bool {
+	if slices.Contains(wanted, reportType) {
+		return true
+	}
+	for _, it := range s.incidentTypes {
+		if (it.Code == reportType || it.Name == reportType) && (slices.Contains(wanted, it.Code) || slices.Contains(wanted, it.Name)) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Store) alertView(a *entity.Alert) entity.Alert {
+	view := *a
+	view.ReportIDs = cloneStrings(a.ReportIDs)
+	view.AcknowledgedAt = cloneTime(a.AcknowledgedAt)
+	view.ResolvedAt = cloneTime(a.ResolvedAt)
+	if rule, ok := s.alertRules[a.RuleID]; ok {
+		view.RuleName = rule.Name
+	}
+	return view
+}
+
+// UpsertOpenAlert : une alerte encore ouverte cumule les signalements des déclenchements successifs
+func (r *alertRepo) UpsertOpenAlert(ctx context.Context, a *entity.Alert) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := r.s.timestamp()
+	for _, existing := range r.s.alerts {
+		if existing.RuleID != a.RuleID || existing.GroupKey != a.GroupKey || existing.Status != entity.AlertOpen {
+			continue
+		}
+		existing.ReportIDs = linkReports(existing.ReportIDs, a.ReportIDs)
+		existing.ReportCount = len(existing.ReportIDs)
+		existing.Severity = max(existing.Severity, a.Severity)
+		existing.LastTriggeredAt = now
+		r.s.fillUpserted(a, existing)
+		return false, nil
+	}
+
+	stored := entity.Alert{
+		ID: newID(), RuleID: a.RuleID, GroupKey: a.GroupKey, RegionID: a.RegionID, Status: entity.AlertOpen, Severity: a.Severity,
+		ReportIDs: cloneStrings(a.ReportIDs), ReportCount: a.ReportCount, FirstTriggeredAt: now, LastTriggeredAt: now,
+	}
+	r.s.alerts[stored.ID] = &stored
+	r.s.fillUpserted(a, &stored)
+	return true, nil
+}
+
+// fillUpserted recopie les colonnes retournées par RETURNING
+func (s *Store) fillUpserted(a, stored *entity.Alert) {
+	a.ID, a.Status, a.Severity, a.ReportCount = stored.ID, stored.Status, stored.Severity, stored.ReportCount
+	a.ReportIDs = cloneStrings(stored.ReportIDs)
+	a.FirstTriggeredAt, a.LastTriggeredAt = stored.FirstTriggeredAt, stored.LastTriggeredAt
+}
+
+func (r *alertRepo) GetAlert(ctx context.Context, id string) (*entity.Alert, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	a, ok := r.s.alerts[id]
+	if !ok {
+		return nil, nil
+	}
+	view := r.s.alertView(a)
+	return &view, nil
+}
+
+func (r *alertRepo) ListAlerts(ctx context.Context, f repository.AlertFilter) ([]entity.Alert, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	limit := f.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	alerts := []entity.Alert{}
+	for _, a := range sortedValues(r.s.alerts, func(a, b *entity.Alert) int { return byTime(b.LastTriggeredAt, a.LastTriggeredAt, b.ID, a.ID) }) {
+		if len(alerts) >= limit {
+			break
+		}
+		if (f.Status != "" && string(a.Status) != f.Status) || (f.RegionID != "" && a.RegionID != f.RegionID) || (f.RuleID != "" && a.RuleID != f.RuleID) {
+			continue
+		}
+		alerts = append(alerts, r.s.alertView(a))
+	}
+	return alerts, nil
+}
+
+func (r *alertRepo) UpdateAlertStatus(ctx context.Context, id string, status entity.AlertStatus, by, note string) error {
+	if status != entity.AlertAcknowledged && status != entity.AlertResolved {
+		return fmt.Errorf("unsupported alert status %q", status)
+	}
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	a, ok := r.s.alerts[id]
+	if !ok {
+		return nil
+	}
+	a.Status = status
+	if status == entity.AlertAcknowledged {
+		a.AcknowledgedBy, a.AcknowledgedAt = by, timePtr(r.s.timestamp())
+		return nil
+	}
+	a.ResolvedBy, a.ResolvedAt, a.ResolutionNote = by, timePtr(r.s.timestamp()), note
+	return nil
+}
@@ -0,0 +1,105 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"strings"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+)
+
+type configRepo struct{ s *Store }
+
+func NewConfigRepository(s *Store) repository.ConfigRepository {
+	return &configRepo{s: s}
+}
+
+func copyConfigVersion(v entity.ConfigVersion) entity.ConfigVersion {
+	v.Data = slices.Clone(v.Data)
+	if v.RolledBackFrom != nil {
+		from := *v.RolledBackFrom
+		v.RolledBackFrom = &from
+	}
+	return v
+}
+
+// Latest retourne la dernière version de chaque section, par ordre de section
+func (r *configRepo) Latest(ctx context.Context) ([]entity.ConfigVersion, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	latest := map[string]entity.ConfigVersion{}
+	for _, v := range r.s.configVersions {
+		latest[v.Section] = v
+	}
+	versions := []entity.ConfigVersion{}
+	for _, v := range latest {
+		versions = append(versions, copyConfigVersion(v))
+	}
+	slices.SortFunc(versions, func(a, b entity.ConfigVersion) int { return strings.Compare(a.Section, b.Section) })
+	return versions, nil
+}
+
+func (r *configRepo) CurrentVersion(ctx context.Context) (int64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	if n := len(r.s.configVersions); n > 0 {
+		return r.s.configVersions[n-1].Version, nil
+	}
+	return 0, nil
+}
+
+func (r *configRepo) GetVersion(ctx context.Context, version int64) (*entity.ConfigVersion, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	for _, v := range r.s.configVersions {
+		if v.Version == version {
+			copied := copyConfigVersion(v)
+			return &copied, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *configRepo) History(ctx context.Context, section string, limit int) ([]entity.ConfigVersion, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	versions := []entity.ConfigVersion{}
+	for i := len(r.s.configVersions) - 1; i >= 0 && len(versions) < limit; i-- {
+		if v := r.s.configVersions[i]; v.Section == section {
+			versions = append(versions, copyConfigVersion(v))
+		}
+	}
+	return versions, nil
+}
+
+// Append refuse l'écriture si la section a changé depuis baseVersion (verrou optimiste)
+func (r *configRepo) Append(ctx context.Context, v *entity.ConfigVersion, baseVersion int64) error {
+	return r.AppendAll(ctx, []*entity.ConfigVersion{v}, map[string]int64{v.Section: baseVersion})
+}
+
+func (r *configRepo) AppendAll(ctx context.Context, versions []*entity.ConfigVersion, baseVersions map[string]int64) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	latest := map[string]int64{}
+	var current int64
+	for _, existing := range r.s.configVersions {
+		latest[existing.Section] = existing.Version
+		current = existing.Version
+	}
+	for _, v := range versions {
+		if latest[v.Section] != baseVersions[v.Section] {
+			return repository.ErrConfigVersionConflict
+		}
+	}
+	now := r.s.timestamp()
+	for _, v := range versions {
+		current++
+		v.Version, v.CreatedAt = current, now
+		r.s.configVersions = append(r.s.configVersions, copyConfigVersion(*v))
+	}
+	return nil
+}
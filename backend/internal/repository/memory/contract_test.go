@@ -0,0 +1,34 @@
+package memory
+
+import (
+	"testing"
+
+	"github.com/openvote/backend/internal/repository/repositorytest"
+)
+
+func TestRepositoryContract(t *testing.T) {
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Repositories {
+		s := NewStore()
+		if err := Seed(s); err != nil {
+			t.Fatalf("seed: %v", err)
+		}
+		return repositorytest.Repositories{
+			Users:         NewUserRepository(s),
+			Reports:       NewReportRepository(s),
+			Regions:       NewRegionRepository(s),
+			Stations:      NewPollingStationRepository(s),
+			Elections:     NewElectionRepository(s),
+			AuditLogs:     NewAuditLogRepository(s),
+			IncidentTypes: NewIncidentTypeRepository(s),
+			Legal:         NewLegalRepository(s),
+			Clusters:      NewSuspiciousClusterRepository(s),
+			Conflicts:     NewConflictRepository(s),
+			Events:        NewIncidentEventRepository(s),
+			Outbox:        NewOutboxRepository(s),
+			Webhooks:      NewWebhookRepository(s),
+			Alerts:        NewAlertRepository(s),
+			Notifications: NewNotificationRepository(s),
+			Config:        NewConfigRepository(s),
+		}
+	})
+}
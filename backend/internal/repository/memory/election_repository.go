@@ -0,0 +1,246 @@
+package memory
+
+import (
+	"context"
+	"strings"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+)
+
+// ========================================
+// Election Repository
+// ========================================
+type electionRepo struct{ s *Store }
+
+func NewElectionRepository(s *Store) repository.ElectionRepository {
+	return &electionRepo{s: s}
+}
+
+func (r *electionRepo) GetAll(ctx context.Context) ([]entity.Election, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var results []entity.Election
+	for _, e := range sortedValues(r.s.elections, func(a, b *entity.Election) int { return byTime(b.Date, a.Date, b.ID, a.ID) }) {
+		results = append(results, *e)
+	}
+	return results, nil
+}
+
+func (r *electionRepo) GetByID(ctx context.Context, id string) (*entity.Election, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	e, ok := r.s.elections[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *e
+	return &copied, nil
+}
+
+func (r *electionRepo) Create(ctx context.Context, e *entity.Election) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := r.s.timestamp()
+	e.ID, e.CreatedAt, e.UpdatedAt = newID(), now, now
+	stored := *e
+	r.s.elections[e.ID] = &stored
+	return nil
+}
+
+func (r *electionRepo) Update(ctx context.Context, e *entity.Election) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if stored, ok := r.s.elections[e.ID]; ok {
+		stored.Name, stored.Type, stored.Date, stored.Description, stored.RegionIDs = e.Name, e.Type, e.Date, e.Description, e.RegionIDs
+		stored.UpdatedAt = r.s.timestamp()
+	}
+	return nil
+}
+
+func (r *electionRepo) UpdateStatus(ctx context.Context, id string, status entity.ElectionStatus) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if stored, ok := r.s.elections[id]; ok {
+		stored.Status, stored.UpdatedAt = status, r.s.timestamp()
+	}
+	return nil
+}
+
+func (r *electionRepo) Delete(ctx context.Context, id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.elections, id)
+	return nil
+}
+
+// ========================================
+// Audit Log Repository
+// ========================================
+type auditLogRepo struct{ s *Store }
+
+func NewAuditLogRepository(s *Store) repository.AuditLogRepository {
+	return &auditLogRepo{s: s}
+}
+
+// Create chaîne l'entrée à la dernière entrée chaînée, comme le repository postgres
+func (r *auditLogRepo) Create(ctx context.Context, log *entity.AuditLog) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	var prevHash string
+	for i := len(r.s.auditLogs) - 1; i >= 0; i-- {
+		if h := r.s.auditLogs[i].Hash; h != "" {
+			prevHash = h
+			break
+		}
+	}
+	var seq int64 = 1
+	if n := len(r.s.auditLogs); n > 0 {
+		seq = r.s.auditLogs[n-1].Seq + 1
+	}
+
+	log.ID = newID()
+	log.CreatedAt = r.s.now().UTC().Truncate(time.Microsecond)
+	log.Seq = seq
+	log.PrevHash = prevHash
+	log.Hash = log.ComputeHash(prevHash)
+	r.s.auditLogs = append(r.s.auditLogs, *log)
+	return nil
+}
+
+func (r *auditLogRepo) GetAll(ctx context.Context, limit int) ([]entity.AuditLog, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var results []entity.AuditLog
+	for i := len(r.s.auditLogs) - 1; i >= 0 && len(results) < limit; i-- {
+		results = append(results, r.s.auditLogs[i])
+	}
+	return results, nil
+}
+
+func (r *auditLogRepo) ListChain(ctx context.Context, afterSeq int64, limit int) ([]entity.AuditLog, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var results []entity.AuditLog
+	for _, l := range r.s.auditLogs {
+		if len(results) >= limit {
+			break
+		}
+		if l.Seq > afterSeq {
+			results = append(results, l)
+		}
+	}
+	return results, nil
+}
+
+// ========================================
+// Incident Type Repository
+// ========================================
+type incidentTypeRepo struct{ s *Store }
+
+func NewIncidentTypeRepository(s *Store) repository.IncidentTypeRepository {
+	return &incidentTypeRepo{s: s}
+}
+
+func (r *incidentTypeRepo) GetAll(ctx context.Context) ([]entity.IncidentType, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	// Sévérité décroissante puis nom
+	rows := sortedValues(r.s.incidentTypes, func(a, b *entity.IncidentType) int {
+		if a.Severity != b.Severity {
+			return b.Severity - a.Severity
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+	var results []entity.IncidentType
+	for _, it := range rows {
+		results = append(results, *it)
+	}
+	return results, nil
+}
+
+func (r *incidentTypeRepo) FindByCodeOrName(ctx context.Context, value string) (*entity.IncidentType, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	// Un code l'emporte sur un libellé identique
+	var byName *entity.IncidentType
+	for _, it := range r.s.incidentTypes {
+		if it.Code == value {
+			copied := *it
+			return &copied, nil
+		}
+		if it.Name == value {
+			byName = it
+		}
+	}
+	if byName == nil {
+		return nil, nil
+	}
+	copied := *byName
+	return &copied, nil
+}
+
+func (r *incidentTypeRepo) Create(ctx context.Context, it *entity.IncidentType) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	return r.s.insertIncidentType(it)
+}
+
+// insertIncidentType : l'appelant détient le verrou d'écriture
+func (s *Store) insertIncidentType(it *entity.IncidentType) error {
+	if err := s.checkIncidentTypeUnique("", it.Name, it.Code); err != nil {
+		return err
+	}
+	it.ID = newID()
+	it.CreatedAt = s.timestamp()
+	stored := *it
+	if stored.Color == "" {
+		stored.Color = "#8b949e"
+	}
+	s.incidentTypes[it.ID] = &stored
+	return nil
+}
+
+func (s *Store) checkIncidentTypeUnique(id, name, code string) error {
+	for _, other := range s.incidentTypes {
+		if other.ID == id {
+			continue
+		}
+		if other.Name == name {
+			return uniqueViolation("incident_types", "name", name)
+		}
+		if other.Code == code {
+			return uniqueViolation("incident_types", "code", code)
+		}
+	}
+	return nil
+}
+
+func (r *incidentTypeRepo) Update(ctx context.Context, it *entity.IncidentType) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	stored, ok := r.s.incidentTypes[it.ID]
+	if !ok {
+		return nil
+	}
+	if err := r.s.checkIncidentTypeUnique(it.ID, it.Name, it.Code); err != nil {
+		return err
+	}
+	stored.Name, stored.Code, stored.Description, stored.Severity, stored.Color = it.Name, it.Code, it.Description, it.Severity, it.Color
+	return nil
+}
+
+func (r *incidentTypeRepo) Delete(ctx context.Context, id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.incidentTypes, id)
+	return nil
+}
@@ -0,0 +1,288 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+)
+
+// ========================================
+// Incident Event Repository
+// ========================================
+type incidentEventRepo struct{ s *Store }
+
+func NewIncidentEventRepository(s *Store) repository.IncidentEventRepository {
+	return &incidentEventRepo{s: s}
+}
+
+// eventView ajoute à l'événement ses signalements liés, du plus ancien au plus récent
+func (s *Store) eventView(e *entity.IncidentEvent) entity.IncidentEvent {
+	view := *e
+	view.ReportIDs = []string{}
+	for _, row := range s.eventReports(e.ID) {
+		view.ReportIDs = append(view.ReportIDs, row.ID)
+	}
+	return view
+}
+
+func (s *Store) eventReports(eventID string) []*reportRow {
+	var rows []*reportRow
+	for _, row := range s.reports {
+		if row.eventID == eventID {
+			rows = append(rows, row)
+		}
+	}
+	slices.SortFunc(rows, func(a, b *reportRow) int { return byTime(a.CreatedAt, b.CreatedAt, a.ID, b.ID) })
+	return rows
+}
+
+// refreshEvent recalcule les agrégats d'un événement à partir de ses signalements ;
+// un événement vidé conserve ses anciennes valeurs, comme la requête postgres
+func (s *Store) refreshEvent(eventID string) {
+	e, ok := s.events[eventID]
+	if !ok {
+		return
+	}
+	rows := s.eventReports(eventID)
+	if len(rows) == 0 {
+		return
+	}
+	var sumLat, sumLon float64
+	points := 0
+	e.MaxSeverity = rows[0].Severity
+	for _, row := range rows {
+		if lat, lon, ok := parseWKTPoint(row.GPSLocation); ok {
+			sumLat, sumLon = sumLat+lat, sumLon+lon
+			points++
+		}
+		e.MaxSeverity = max(e.MaxSeverity, row.Severity)
+	}
+	e.Centroid = ""
+	if points > 0 {
+		e.Centroid = formatWKTPoint(sumLat/float64(points), sumLon/float64(points))
+	}
+	e.ReportCount = len(rows)
+	e.FirstReportedAt, e.LastReportedAt = rows[0].CreatedAt, rows[len(rows)-1].CreatedAt
+	e.UpdatedAt = s.timestamp()
+}
+
+func formatWKTPoint(lat, lon float64) string {
+	return "POINT(" + strconv.FormatFloat(lon, 'f', -1, 64) + " " + strconv.FormatFloat(lat, 'f', -1, 64) + ")"
+}
+
+func (r *incidentEventRepo) FindCandidate(ctx context.Context, incidentType, h3Index string, lat, lon, radius float64, start, end time.Time) (*entity.IncidentEvent, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	best := r.s.findEventCandidate(repository.EventCandidate{
+		IncidentType: incidentType, H3Index: h3Index, Lat: lat, Lon: lon, Radius: radius, Start: start, End: end,
+	})
+	if best == nil {
+		return nil, nil
+	}
+	view := r.s.eventView(best)
+	return &view, nil
+}
+
+// findEventCandidate : l'appelant détient le verrou
+func (s *Store) findEventCandidate(c repository.EventCandidate) *entity.IncidentEvent {
+	var best *entity.IncidentEvent
+	bestDistance := 0.0
+	for _, e := range sortedValues(s.events, func(a, b *entity.IncidentEvent) int { return byTime(a.CreatedAt, b.CreatedAt, a.ID, b.ID) }) {
+		if e.Status != entity.EventActive || e.IncidentType != c.IncidentType {
+			continue
+		}
+		if e.H3Index != c.H3Index && !withinRadius(e.Centroid, c.Lat, c.Lon, c.Radius) {
+			continue
+		}
+		if e.LastReportedAt.Before(c.Start) || e.FirstReportedAt.After(c.End) {
+			continue
+		}
+		// Le plus proche l'emporte ; un centroïde inconnu passe en dernier
+		distance := -1.0
+		if cLat, cLon, ok := parseWKTPoint(e.Centroid); ok {
+			distance = distanceMeters(c.Lat, c.Lon, cLat, cLon)
+		}
+		if best == nil || (distance >= 0 && (bestDistance < 0 || distance < bestDistance)) {
+			best, bestDistance = e, distance
+		}
+	}
+	return best
+}
+
+// AssignReport : le verrou d'écriture du store sérialise déjà la recherche et la création
+func (r *incidentEventRepo) AssignReport(ctx context.Context, reportID, lockKey string, candidate repository.EventCandidate, fallback *entity.IncidentEvent) (*entity.IncidentEvent, bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	row, ok := r.s.reports[reportID]
+	if !ok {
+		return nil, false, sql.ErrNoRows
+	}
+	// Signalement déjà regroupé (message rejoué) : il reste à son événement
+	if e, ok := r.s.events[row.eventID]; ok {
+		view := r.s.eventView(e)
+		return &view, false, nil
+	}
+
+	event := r.s.findEventCandidate(candidate)
+	created := event == nil
+	if created {
+		r.s.insertEvent(fallback)
+		event = r.s.events[fallback.ID]
+	}
+	r.s.attachReports(event.ID, []string{reportID})
+	view := r.s.eventView(event)
+	return &view, created, nil
+}
+
+func (r *incidentEventRepo) Create(ctx context.Context, e *entity.IncidentEvent) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.insertEvent(e)
+	return nil
+}
+
+// insertEvent : l'appelant détient le verrou d'écriture
+func (s *Store) insertEvent(e *entity.IncidentEvent) {
+	if e.Status == "" {
+		e.Status = entity.EventActive
+	}
+	if e.MaxSeverity == 0 {
+		e.MaxSeverity = 1
+	}
+	now := s.timestamp()
+	e.ID, e.CreatedAt, e.UpdatedAt = newID(), now, now
+	stored := *e
+	stored.MergedInto, stored.ReportIDs = "", nil
+	s.events[e.ID] = &stored
+}
+
+func (r *incidentEventRepo) AttachReports(ctx context.Context, eventID string, reportIDs []string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.attachReports(eventID, reportIDs)
+	return nil
+}
+
+// attachReports : l'appelant détient le verrou d'écriture
+func (s *Store) attachReports(eventID string, reportIDs []string) {
+	// Les anciens événements des signalements déplacés doivent aussi être recalculés
+	var previous []string
+	for _, id := range reportIDs {
+		row, ok := s.reports[id]
+		if !ok {
+			continue
+		}
+		if row.eventID != "" && row.eventID != eventID && !slices.Contains(previous, row.eventID) {
+			previous = append(previous, row.eventID)
+		}
+		row.eventID = eventID
+	}
+	for _, id := range append(previous, eventID) {
+		s.refreshEvent(id)
+	}
+}
+
+func (r *incidentEventRepo) GetByID(ctx context.Context, id string) (*entity.IncidentEvent, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	e, ok := r.s.events[id]
+	if !ok {
+		return nil, nil
+	}
+	view := r.s.eventView(e)
+	return &view, nil
+}
+
+func (r *incidentEventRepo) GetAll(ctx context.Context, status string) ([]entity.IncidentEvent, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	// Sévérité, nombre de signalements puis activité récente
+	rows := sortedValues(r.s.events, func(a, b *entity.IncidentEvent) int {
+		if a.MaxSeverity != b.MaxSeverity {
+			return b.MaxSeverity - a.MaxSeverity
+		}
+		if a.ReportCount != b.ReportCount {
+			return b.ReportCount - a.ReportCount
+		}
+		return byTime(b.LastReportedAt, a.LastReportedAt, b.ID, a.ID)
+	})
+	results := []entity.IncidentEvent{}
+	for _, e := range rows {
+		if status == "" || string(e.Status) == status {
+			results = append(results, r.s.eventView(e))
+		}
+	}
+	return results, nil
+}
+
+func (r *incidentEventRepo) Merge(ctx context.Context, targetID string, sourceIDs []string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	// Toutes les sources doivent être actives et distinctes de la cible, sinon rien n'est modifié
+	for _, id := range sourceIDs {
+		source, ok := r.s.events[id]
+		if !ok || id == targetID || source.Status != entity.EventActive {
+			return sql.ErrNoRows
+		}
+	}
+	now := r.s.timestamp()
+	for _, id := range sourceIDs {
+		source := r.s.events[id]
+		source.Status, source.MergedInto, source.ReportCount, source.UpdatedAt = entity.EventMerged, targetID, 0, now
+	}
+	// Les événements déjà fusionnés dans une source pointent désormais vers la cible
+	for _, e := range r.s.events {
+		if slices.Contains(sourceIDs, e.MergedInto) {
+			e.MergedInto = targetID
+		}
+	}
+	for _, row := range r.s.reports {
+		if slices.Contains(sourceIDs, row.eventID) {
+			row.eventID = targetID
+		}
+	}
+	r.s.refreshEvent(targetID)
+	return nil
+}
+
+func (r *incidentEventRepo) Split(ctx context.Context, eventID string, reportIDs []string) (*entity.IncidentEvent, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	source, ok := r.s.events[eventID]
+	if !ok || source.Status != entity.EventActive {
+		return nil, sql.ErrNoRows
+	}
+	moved := 0
+	for _, id := range reportIDs {
+		if row, ok := r.s.reports[id]; ok && row.eventID == eventID {
+			moved++
+		}
+	}
+	if moved != len(reportIDs) {
+		return nil, fmt.Errorf("%d of %d reports do not belong to event %s", len(reportIDs)-moved, len(reportIDs), eventID)
+	}
+
+	split := &entity.IncidentEvent{
+		IncidentType: source.IncidentType, H3Index: source.H3Index, Centroid: source.Centroid,
+		FirstReportedAt: source.FirstReportedAt, LastReportedAt: source.LastReportedAt, MaxSeverity: source.MaxSeverity,
+	}
+	r.s.insertEvent(split)
+	for _, id := range reportIDs {
+		r.s.reports[id].eventID = split.ID
+	}
+	r.s.refreshEvent(eventID)
+	r.s.refreshEvent(split.ID)
+
+	view := r.s.eventView(r.s.events[split.ID])
+	return &view, nil
+}
@@ -0,0 +1,331 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"math"
+	"slices"
+	"strings"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+)
+
+type legalRepo struct{ s *Store }
+
+func NewLegalRepository(s *Store) repository.LegalRepository {
+	return &legalRepo{s: s}
+}
+
+// Documents
+func (r *legalRepo) GetAllDocuments(ctx context.Context) ([]entity.LegalDocument, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var docs []entity.LegalDocument
+	for _, d := range sortedValues(r.s.documents, func(a, b *entity.LegalDocument) int { return strings.Compare(a.Title, b.Title) }) {
+		docs = append(docs, *d)
+	}
+	return docs, nil
+}
+
+func (r *legalRepo) CreateDocument(ctx context.Context, d *entity.LegalDocument) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	d.ID, d.CreatedAt = newID(), r.s.timestamp()
+	stored := *d
+	r.s.documents[d.ID] = &stored
+	return nil
+}
+
+func (r *legalRepo) UpdateDocumentFullText(ctx context.Context, docID string, text string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if d, ok := r.s.documents[docID]; ok {
+		d.FullText = text
+	}
+	return nil
+}
+
+// Articles
+func (r *legalRepo) GetAllArticles(ctx context.Context) ([]entity.LegalArticle, error) {
+	return r.articles(func(*articleRow) bool { return true }, byArticleNumber)
+}
+
+func (r *legalRepo) GetArticlesByDocument(ctx context.Context, docID string) ([]entity.LegalArticle, error) {
+	return r.articles(func(a *articleRow) bool { return a.DocumentID == docID }, byArticleNumber)
+}
+
+func (r *legalRepo) GetArticlesByCategory(ctx context.Context, category string) ([]entity.LegalArticle, error) {
+	return r.articles(func(a *articleRow) bool { return a.Category == category }, byArticleNumber)
+}
+
+func (r *legalRepo) GetArticlesWithoutEmbedding(ctx context.Context) ([]entity.LegalArticle, error) {
+	return r.articles(func(a *articleRow) bool { return a.embedding == nil }, func(a, b *articleRow) int {
+		return byTime(a.CreatedAt, b.CreatedAt, a.ID, b.ID)
+	})
+}
+
+func byArticleNumber(a, b *articleRow) int {
+	if c := strings.Compare(a.ArticleNumber, b.ArticleNumber); c != 0 {
+		return c
+	}
+	return strings.Compare(a.ID, b.ID)
+}
+
+func (r *legalRepo) articles(keep func(*articleRow) bool, order func(a, b *articleRow) int) ([]entity.LegalArticle, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var articles []entity.LegalArticle
+	for _, a := range sortedValues(r.s.articles, order) {
+		if keep(a) {
+			articles = append(articles, a.view())
+		}
+	}
+	return articles, nil
+}
+
+// view reprend les colonnes lues par le repository postgres
+func (a *articleRow) view() entity.LegalArticle {
+	return entity.LegalArticle{ID: a.ID, DocumentID: a.DocumentID, ArticleNumber: a.ArticleNumber, Title: a.Title, Content: a.Content, Category: a.Category, CreatedAt: a.CreatedAt}
+}
+
+func (r *legalRepo) CreateArticle(ctx context.Context, art *entity.LegalArticle) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if existing := r.s.findArticle(art.DocumentID, art.ArticleNumber); existing != nil {
+		return uniqueViolation("legal_framework", "(document_id, article_number)", art.ArticleNumber)
+	}
+	r.s.insertArticle(art)
+	return nil
+}
+
+// findArticle applique la contrainte (document_id, article_number) ; un article sans document n'entre pas en conflit
+func (s *Store) findArticle(documentID, number string) *articleRow {
+	if documentID == "" {
+		return nil
+	}
+	for _, a := range s.articles {
+		if a.DocumentID == documentID && a.ArticleNumber == number {
+			return a
+		}
+	}
+	return nil
+}
+
+func (s *Store) insertArticle(art *entity.LegalArticle) {
+	art.ID, art.CreatedAt = newID(), s.timestamp()
+	s.articles[art.ID] = &articleRow{LegalArticle: entity.LegalArticle{
+		ID: art.ID, DocumentID: art.DocumentID, ArticleNumber: art.ArticleNumber, Title: art.Title,
+		Content: art.Content, Category: art.Category, CreatedAt: art.CreatedAt,
+	}}
+}
+
+func (r *legalRepo) BatchCreateArticles(ctx context.Context, articles []entity.LegalArticle) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for i := range articles {
+		art := articles[i]
+		// ON CONFLICT (document_id, article_number) DO UPDATE
+		if existing := r.s.findArticle(art.DocumentID, art.ArticleNumber); existing != nil {
+			existing.Title, existing.Content, existing.Category = art.Title, art.Content, art.Category
+			continue
+		}
+		r.s.insertArticle(&art)
+	}
+	return nil
+}
+
+func (r *legalRepo) DeleteArticle(ctx context.Context, id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.articles, id)
+	for mid, m := range r.s.matches {
+		if m.ArticleID == id {
+			delete(r.s.matches, mid)
+		}
+	}
+	return nil
+}
+
+// ========================================
+// Recherche Sémantique (RAG)
+// ========================================
+
+func (r *legalRepo) UpdateArticleEmbedding(ctx context.Context, articleID string, embedding []float32) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if a, ok := r.s.articles[articleID]; ok {
+		a.embedding = slices.Clone(embedding)
+	}
+	return nil
+}
+
+func (r *legalRepo) SemanticSearch(ctx context.Context, queryEmbedding []float32, limit int) ([]entity.LegalArticle, []float64, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	type scored struct {
+		article    *articleRow
+		similarity float64
+	}
+	var candidates []scored
+	for _, a := range r.s.articles {
+		if a.embedding != nil {
+			candidates = append(candidates, scored{a, cosineSimilarity(queryEmbedding, a.embedding)})
+		}
+	}
+	// Distance cosinus croissante (opérateur <=> de pgvector)
+	slices.SortFunc(candidates, func(x, y scored) int {
+		if x.similarity != y.similarity {
+			if x.similarity > y.similarity {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(x.article.ID, y.article.ID)
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+
+	var articles []entity.LegalArticle
+	var scores []float64
+	for _, c := range candidates {
+		articles = append(articles, c.article.view())
+		scores = append(scores, c.similarity)
+	}
+	return articles, scores, nil
+}
+
+// cosineSimilarity vaut 1 - distance cosinus (0 pour un vecteur nul)
+func cosineSimilarity(a, b []float32) float64 {
+	var dot, normA, normB float64
+	for i := 0; i < len(a) && i < len(b); i++ {
+		dot += float64(a[i]) * float64(b[i])
+		normA += float64(a[i]) * float64(a[i])
+		normB += float64(b[i]) * float64(b[i])
+	}
+	if normA == 0 || normB == 0 {
+		return 0
+	}
+	return dot / (math.Sqrt(normA) * math.Sqrt(normB))
+}
+
+// ========================================
+// Croisement Terrain / Droit
+// ========================================
+
+func (r *legalRepo) CreateReportMatch(ctx context.Context, match *entity.ReportLegalMatch) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	// ON CONFLICT (report_id, article_id) : score et notes mis à jour
+	for _, existing := range r.s.matches {
+		if existing.ReportID == match.ReportID && existing.ArticleID == match.ArticleID {
+			existing.SimilarityScore, existing.Notes = match.SimilarityScore, match.Notes
+			match.ID, match.CreatedAt = existing.ID, existing.CreatedAt
+			return nil
+		}
+	}
+	match.ID, match.CreatedAt = newID(), r.s.timestamp()
+	stored := entity.ReportLegalMatch{ID: match.ID, ReportID: match.ReportID, ArticleID: match.ArticleID, SimilarityScore: match.SimilarityScore,
+		MatchType: match.MatchType, Notes: match.Notes, CreatedAt: match.CreatedAt}
+	r.s.matches[match.ID] = &stored
+	return nil
+}
+
+func (r *legalRepo) GetMatchesByReport(ctx context.Context, reportID string) ([]entity.ReportLegalMatch, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var matches []entity.ReportLegalMatch
+	for _, m := range r.s.sortedMatches() {
+		article, ok := r.s.articles[m.ArticleID]
+		if m.ReportID != reportID || !ok {
+			continue
+		}
+		joined := *m
+		joined.ArticleNumber, joined.ArticleTitle, joined.ArticleContent = article.ArticleNumber, article.Title, article.Content
+		matches = append(matches, joined)
+	}
+	return matches, nil
+}
+
+func (r *legalRepo) GetMatchesByArticle(ctx context.Context, articleID string) ([]entity.ReportLegalMatch, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var matches []entity.ReportLegalMatch
+	for _, m := range r.s.sortedMatches() {
+		if m.ArticleID == articleID {
+			matches = append(matches, *m)
+		}
+	}
+	return matches, nil
+}
+
+// sortedMatches : score de similarité décroissant
+func (s *Store) sortedMatches() []*entity.ReportLegalMatch {
+	return sortedValues(s.matches, func(a, b *entity.ReportLegalMatch) int {
+		if a.SimilarityScore != b.SimilarityScore {
+			if a.SimilarityScore > b.SimilarityScore {
+				return -1
+			}
+			return 1
+		}
+		return strings.Compare(a.ID, b.ID)
+	})
+}
+
+// ========================================
+// Analyses Juridiques LLM
+// ========================================
+
+func (r *legalRepo) SaveAnalysis(ctx context.Context, analysis *entity.LegalAnalysis) error {
+	return r.saveAnalysis(analysis, func(a *entity.LegalAnalysis) bool { return a.ReportID != "" && a.ReportID == analysis.ReportID })
+}
+
+func (r *legalRepo) SaveEventAnalysis(ctx context.Context, analysis *entity.LegalAnalysis) error {
+	return r.saveAnalysis(analysis, func(a *entity.LegalAnalysis) bool { return a.EventID != "" && a.EventID == analysis.EventID })
+}
+
+// saveAnalysis remplace l'analyse existante du même signalement (ou événement) en conservant son identifiant
+func (r *legalRepo) saveAnalysis(analysis *entity.LegalAnalysis, sameTarget func(*entity.LegalAnalysis) bool) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	analysis.ID, analysis.CreatedAt = newID(), r.s.timestamp()
+	for _, existing := range r.s.analyses {
+		if sameTarget(existing) {
+			analysis.ID = existing.ID
+			break
+		}
+	}
+	stored := *analysis
+	r.s.analyses[analysis.ID] = &stored
+	return nil
+}
+
+func (r *legalRepo) GetAnalysisByReport(ctx context.Context, reportID string) (*entity.LegalAnalysis, error) {
+	return r.findAnalysis(func(a *entity.LegalAnalysis) bool { return a.ReportID != "" && a.ReportID == reportID })
+}
+
+func (r *legalRepo) GetAnalysisByEvent(ctx context.Context, eventID string) (*entity.LegalAnalysis, error) {
+	return r.findAnalysis(func(a *entity.LegalAnalysis) bool { return a.EventID != "" && a.EventID == eventID })
+}
+
+// findAnalysis retourne sql.ErrNoRows en l'absence d'analyse, comme le repository postgres
+func (r *legalRepo) findAnalysis(match func(*entity.LegalAnalysis) bool) (*entity.LegalAnalysis, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	for _, a := range r.s.analyses {
+		if match(a) {
+			copied := *a
+			return &copied, nil
+		}
+	}
+	return nil, sql.ErrNoRows
+}
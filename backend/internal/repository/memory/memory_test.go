@@ -0,0 +1,179 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+)
+
+var base = time.Date(2026, 6, 1, 8, 0, 0, 0, time.UTC)
+
+func seedReport(t *testing.T, s *Store, r entity.Report) {
+	t.Helper()
+	if r.GPSLocation == "" {
+		r.GPSLocation = "POINT(9.7679 4.0511)"
+	}
+	if err := NewReportRepository(s).Create(context.Background(), &r); err != nil {
+		t.Fatalf("create report %s: %v", r.ID, err)
+	}
+}
+
+func TestFindNearbyWithRole_JoinsAuthor(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore()
+	users := NewUserRepository(s)
+	users.Create(ctx, &entity.User{ID: "u1", Username: "obs", Role: entity.RoleObserver, ActivationTokenHash: "tok"})
+
+	seedReport(t, s, entity.Report{ID: "same-cell", ObserverID: "u1", H3Index: "cell", GPSLocation: "POINT(13.39 9.30)", CreatedAt: base})
+	seedReport(t, s, entity.Report{ID: "in-radius", ObserverID: "u1", H3Index: "other", GPSLocation: "POINT(9.7690 4.0511)", CreatedAt: base})
+	seedReport(t, s, entity.Report{ID: "far", ObserverID: "u1", H3Index: "other", GPSLocation: "POINT(13.39 9.30)", CreatedAt: base})
+	seedReport(t, s, entity.Report{ID: "late", ObserverID: "u1", H3Index: "cell", CreatedAt: base.Add(2 * time.Hour)})
+	seedReport(t, s, entity.Report{ID: "orphan", ObserverID: "unknown", H3Index: "cell", CreatedAt: base})
+
+	got, err := NewReportRepository(s).FindNearbyWithRole(ctx, "cell", 4.0511, 9.7679, 500, base.Add(-time.Hour), base.Add(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, r := range got {
+		ids = append(ids, r.ID)
+		if r.AuthorRole != entity.RoleObserver || r.AuthorTokenHash != "tok" {
+			t.Errorf("Expected author fields joined on %s, got role %q token %q", r.ID, r.AuthorRole, r.AuthorTokenHash)
+		}
+	}
+	slices.Sort(ids)
+	if !slices.Equal(ids, []string{"in-radius", "same-cell"}) {
+		t.Errorf("Expected same-cell and in-radius reports, got %v", ids)
+	}
+}
+
+func TestAuditLog_ChainsEntries(t *testing.T) {
+	ctx := context.Background()
+	repo := NewAuditLogRepository(NewStore())
+	for _, action := range []string{"LOGIN", "UPDATE_ROLE", "DELETE_USER"} {
+		if err := repo.Create(ctx, &entity.AuditLog{Action: action, AdminID: "admin"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	chain, err := repo.ListChain(ctx, 0, 10)
+	if err != nil || len(chain) != 3 {
+		t.Fatalf("Expected 3 chained entries, got %d (%v)", len(chain), err)
+	}
+	prev := ""
+	for i, l := range chain {
+		if l.Seq != int64(i+1) || l.PrevHash != prev || l.Hash != l.ComputeHash(prev) {
+			t.Errorf("Broken chain at entry %d: %+v", i, l)
+		}
+		prev = l.Hash
+	}
+	if page, _ := repo.ListChain(ctx, 2, 10); len(page) != 1 || page[0].Action != "DELETE_USER" {
+		t.Errorf("Expected paging after seq 2 to return the last entry, got %+v", page)
+	}
+	if latest, _ := repo.GetAll(ctx, 1); len(latest) != 1 || latest[0].Action != "DELETE_USER" {
+		t.Errorf("Expected newest entry first, got %+v", latest)
+	}
+}
+
+func TestReviewQueue_PrioritizesSeverityAndCorroboration(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore()
+	s.SetClock(func() time.Time { return base.Add(time.Hour) })
+	seedReport(t, s, entity.Report{ID: "minor", Severity: 2, CreatedAt: base})
+	seedReport(t, s, entity.Report{ID: "alone", Severity: 5, CreatedAt: base})
+	seedReport(t, s, entity.Report{ID: "corroborated", Severity: 5, CreatedAt: base.Add(30 * time.Minute)})
+	seedReport(t, s, entity.Report{ID: "witness", Severity: 1, Status: entity.StatusVerified, CreatedAt: base})
+
+	events := NewIncidentEventRepository(s)
+	e := &entity.IncidentEvent{IncidentType: "VIOLE"}
+	events.Create(ctx, e)
+	events.AttachReports(ctx, e.ID, []string{"corroborated", "witness"})
+
+	queue, err := NewReportRepository(s).GetReviewQueue(ctx, 10)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, item := range queue {
+		ids = append(ids, item.ID)
+	}
+	if !slices.Equal(ids, []string{"corroborated", "alone", "minor"}) {
+		t.Fatalf("Unexpected queue order: %v", ids)
+	}
+	if queue[0].Corroboration != 1 || queue[0].EventID != e.ID || queue[1].AgeMinutes != 60 {
+		t.Errorf("Unexpected queue details: %+v", queue[:2])
+	}
+}
+
+func TestIncidentEvents_MergeAndSplitRefreshAggregates(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore()
+	seedReport(t, s, entity.Report{ID: "r1", Severity: 2, GPSLocation: "POINT(10 4)", CreatedAt: base})
+	seedReport(t, s, entity.Report{ID: "r2", Severity: 5, GPSLocation: "POINT(12 6)", CreatedAt: base.Add(10 * time.Minute)})
+	seedReport(t, s, entity.Report{ID: "r3", Severity: 3, GPSLocation: "POINT(11 5)", CreatedAt: base.Add(20 * time.Minute)})
+
+	repo := NewIncidentEventRepository(s)
+	target, source := &entity.IncidentEvent{IncidentType: "STUFF"}, &entity.IncidentEvent{IncidentType: "STUFF"}
+	repo.Create(ctx, target)
+	repo.Create(ctx, source)
+	repo.AttachReports(ctx, target.ID, []string{"r1"})
+	repo.AttachReports(ctx, source.ID, []string{"r2", "r3"})
+
+	if err := repo.Merge(ctx, target.ID, []string{source.ID}); err != nil {
+		t.Fatal(err)
+	}
+	merged, _ := repo.GetByID(ctx, target.ID)
+	if merged.ReportCount != 3 || merged.MaxSeverity != 5 || merged.Centroid != "POINT(11 5)" || !merged.LastReportedAt.Equal(base.Add(20*time.Minute)) {
+		t.Errorf("Unexpected aggregates after merge: %+v", merged)
+	}
+	if old, _ := repo.GetByID(ctx, source.ID); old.Status != entity.EventMerged || old.MergedInto != target.ID || len(old.ReportIDs) != 0 {
+		t.Errorf("Expected source marked as merged, got %+v", old)
+	}
+	if err := repo.Merge(ctx, target.ID, []string{source.ID}); err == nil {
+		t.Error("Expected merging an already merged event to fail")
+	}
+
+	split, err := repo.Split(ctx, target.ID, []string{"r2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if split.ReportCount != 1 || split.MaxSeverity != 5 || !slices.Equal(split.ReportIDs, []string{"r2"}) {
+		t.Errorf("Unexpected split event: %+v", split)
+	}
+	if rest, _ := repo.GetByID(ctx, target.ID); rest.ReportCount != 2 || rest.MaxSeverity != 3 || !slices.Equal(rest.ReportIDs, []string{"r1", "r3"}) {
+		t.Errorf("Unexpected remaining event: %+v", rest)
+	}
+	if _, err := repo.Split(ctx, target.ID, []string{"r2"}); err == nil {
+		t.Error("Expected splitting a foreign report to fail")
+	}
+}
+
+func TestOutbox_ClaimLeaseAndRetry(t *testing.T) {
+	ctx := context.Background()
+	s := NewStore()
+	now := base
+	s.SetClock(func() time.Time { return now })
+	repo := NewOutboxRepository(s)
+	repo.Enqueue(ctx, &entity.OutboxMessage{Queue: "reports", Payload: []byte(`{}`)})
+
+	claimed, _ := repo.Claim(ctx, 10, time.Minute)
+	if len(claimed) != 1 || claimed[0].Attempts != 1 {
+		t.Fatalf("Expected one claimed message, got %+v", claimed)
+	}
+	if again, _ := repo.Claim(ctx, 10, time.Minute); len(again) != 0 {
+		t.Errorf("Expected leased message to stay hidden, got %d", len(again))
+	}
+	now = now.Add(2 * time.Minute)
+	if again, _ := repo.Claim(ctx, 10, time.Minute); len(again) != 1 || again[0].Attempts != 2 {
+		t.Errorf("Expected message back after lease expiry, got %+v", again)
+	}
+
+	repo.MarkSent(ctx, claimed[0].ID)
+	now = now.Add(time.Hour)
+	if purged, _ := repo.PurgeSent(ctx, 30*time.Minute); purged != 1 {
+		t.Errorf("Expected sent message to be purged, got %d", purged)
+	}
+}
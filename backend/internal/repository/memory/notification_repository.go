@@ -0,0 +1,197 @@
+package memory
+
+import (
+	"context"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+)
+
+type notificationRepo struct{ s *Store }
+
+func NewNotificationRepository(s *Store) repository.NotificationRepository {
+	return &notificationRepo{s: s}
+}
+
+func (r *notificationRepo) GetPreferences(ctx context.Context, userID string) (*entity.NotificationPreferences, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	p, ok := r.s.preferences[userID]
+	if !ok {
+		return nil, nil
+	}
+	copied := *p
+	copied.Channels, copied.MutedKinds = cloneStrings(p.Channels), cloneStrings(p.MutedKinds)
+	return &copied, nil
+}
+
+func (r *notificationRepo) SavePreferences(ctx context.Context, p *entity.NotificationPreferences) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	p.UpdatedAt = r.s.timestamp()
+	stored := *p
+	stored.Channels, stored.MutedKinds = cloneStrings(p.Channels), cloneStrings(p.MutedKinds)
+	r.s.preferences[p.UserID] = &stored
+	return nil
+}
+
+func (r *notificationRepo) CreateInboxItem(ctx context.Context, n *entity.Notification) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	n.ID, n.CreatedAt = newID(), r.s.timestamp()
+	r.s.notifications[n.ID] = &entity.Notification{ID: n.ID, UserID: n.UserID, Kind: n.Kind, Title: n.Title, Body: n.Body, CreatedAt: n.CreatedAt}
+	return nil
+}
+
+func (r *notificationRepo) ListInbox(ctx context.Context, userID string, unreadOnly bool, limit int) ([]entity.Notification, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	items := []entity.Notification{}
+	for _, n := range sortedValues(r.s.notifications, func(a, b *entity.Notification) int { return byTime(b.CreatedAt, a.CreatedAt, b.ID, a.ID) }) {
+		if len(items) >= limit {
+			break
+		}
+		if n.UserID != userID || (unreadOnly && n.ReadAt != nil) {
+			continue
+		}
+		copied := *n
+		copied.ReadAt = cloneTime(n.ReadAt)
+		items = append(items, copied)
+	}
+	return items, nil
+}
+
+func (r *notificationRepo) CountUnread(ctx context.Context, userID string) (int, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	count := 0
+	for _, n := range r.s.notifications {
+		if n.UserID == userID && n.ReadAt == nil {
+			count++
+		}
+	}
+	return count, nil
+}
+
+// MarkRead marque un message, ou tous les messages non lus de l'utilisateur si id est vide
+func (r *notificationRepo) MarkRead(ctx context.Context, userID, id string) (int, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := r.s.timestamp()
+	count := 0
+	for _, n := range r.s.notifications {
+		if n.UserID != userID || n.ReadAt != nil || (id != "" && n.ID != id) {
+			continue
+		}
+		n.ReadAt = timePtr(now)
+		count++
+	}
+	return count, nil
+}
+
+func copyNotificationDelivery(d *entity.NotificationDelivery) entity.NotificationDelivery {
+	copied := *d
+	copied.SentAt = cloneTime(d.SentAt)
+	return copied
+}
+
+// CreateDelivery ignore une notification déjà planifiée (ON CONFLICT (user_id, channel, dedup_key) DO NOTHING)
+func (r *notificationRepo) CreateDelivery(ctx context.Context, d *entity.NotificationDelivery) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, existing := range r.s.notificationDeliveries {
+		if existing.UserID == d.UserID && existing.Channel == d.Channel && existing.DedupKey == d.DedupKey {
+			return false, nil
+		}
+	}
+	if d.NextAttemptAt.IsZero() {
+		d.NextAttemptAt = r.s.now()
+	}
+	d.ID, d.Status, d.CreatedAt = newID(), entity.NotificationPending, r.s.timestamp()
+	r.s.notificationDeliveries[d.ID] = &entity.NotificationDelivery{
+		ID: d.ID, UserID: d.UserID, Channel: d.Channel, Kind: d.Kind, DedupKey: d.DedupKey, Recipient: d.Recipient, Subject: d.Subject,
+		Body: d.Body, Status: d.Status, NextAttemptAt: d.NextAttemptAt.Truncate(time.Microsecond), CreatedAt: d.CreatedAt,
+	}
+	return true, nil
+}
+
+func (r *notificationRepo) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.NotificationDelivery, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := r.s.timestamp()
+	rows := sortedValues(r.s.notificationDeliveries, func(a, b *entity.NotificationDelivery) int {
+		return byTime(a.NextAttemptAt, b.NextAttemptAt, a.ID, b.ID)
+	})
+	deliveries := []entity.NotificationDelivery{}
+	for _, d := range rows {
+		if len(deliveries) >= limit {
+			break
+		}
+		if d.Status != entity.NotificationPending || d.NextAttemptAt.After(now) {
+			continue
+		}
+		d.NextAttemptAt = now.Add(lease)
+		d.Attempts++
+		deliveries = append(deliveries, copyNotificationDelivery(d))
+	}
+	return deliveries, nil
+}
+
+func (r *notificationRepo) MarkSent(ctx context.Context, id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if d, ok := r.s.notificationDeliveries[id]; ok {
+		d.Status, d.LastError, d.SentAt = entity.NotificationSent, "", timePtr(r.s.timestamp())
+	}
+	return nil
+}
+
+func (r *notificationRepo) MarkFailed(ctx context.Context, id, cause string, retryAt *time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	d, ok := r.s.notificationDeliveries[id]
+	if !ok {
+		return nil
+	}
+	d.LastError = cause
+	if retryAt == nil {
+		d.Status = entity.NotificationFailed
+		return nil
+	}
+	d.NextAttemptAt = retryAt.Truncate(time.Microsecond)
+	return nil
+}
+
+func (r *notificationRepo) ListDeliveries(ctx context.Context, status string, limit int) ([]entity.NotificationDelivery, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	deliveries := []entity.NotificationDelivery{}
+	for _, d := range sortedValues(r.s.notificationDeliveries, func(a, b *entity.NotificationDelivery) int { return byTime(b.CreatedAt, a.CreatedAt, b.ID, a.ID) }) {
+		if len(deliveries) >= limit {
+			break
+		}
+		if status == "" || string(d.Status) == status {
+			deliveries = append(deliveries, copyNotificationDelivery(d))
+		}
+	}
+	return deliveries, nil
+}
+
+func (r *notificationRepo) ResetDelivery(ctx context.Context, id string) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	d, ok := r.s.notificationDeliveries[id]
+	if !ok {
+		return false, nil
+	}
+	d.Status, d.Attempts, d.LastError, d.NextAttemptAt, d.SentAt = entity.NotificationPending, 0, "", r.s.timestamp(), nil
+	return true, nil
+}
@@ -0,0 +1,91 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+)
+
+type outboxRepo struct{ s *Store }
+
+func NewOutboxRepository(s *Store) repository.OutboxRepository {
+	return &outboxRepo{s: s}
+}
+
+// insertOutbox écrit un message ; l'appelant détient le verrou d'écriture
+func (s *Store) insertOutbox(msg *entity.OutboxMessage) {
+	msg.ID = newID()
+	msg.CreatedAt = s.timestamp()
+	row := &outboxRow{OutboxMessage: *msg, availableAt: msg.CreatedAt}
+	row.Payload = slices.Clone(msg.Payload)
+	s.outbox[msg.ID] = row
+}
+
+func (r *outboxRepo) Enqueue(ctx context.Context, messages ...*entity.OutboxMessage) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	for _, msg := range messages {
+		r.s.insertOutbox(msg)
+	}
+	return nil
+}
+
+func (r *outboxRepo) Claim(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxMessage, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := r.s.now()
+	due := sortedValues(r.s.outbox, func(a, b *outboxRow) int { return byTime(a.CreatedAt, b.CreatedAt, a.ID, b.ID) })
+	var messages []entity.OutboxMessage
+	for _, row := range due {
+		if len(messages) >= limit {
+			break
+		}
+		if row.sent || row.availableAt.After(now) {
+			continue
+		}
+		// Le bail repousse la disponibilité : un relais qui meurt rend le message à son expiration
+		row.availableAt = now.Add(lease)
+		row.Attempts++
+		msg := row.OutboxMessage
+		msg.Payload = slices.Clone(row.Payload)
+		messages = append(messages, msg)
+	}
+	return messages, nil
+}
+
+func (r *outboxRepo) MarkSent(ctx context.Context, id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if row, ok := r.s.outbox[id]; ok {
+		row.sent, row.sentAt, row.LastError = true, r.s.now(), ""
+	}
+	return nil
+}
+
+func (r *outboxRepo) MarkFailed(ctx context.Context, id string, cause string, retryAfter time.Duration) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if row, ok := r.s.outbox[id]; ok {
+		row.LastError, row.availableAt = cause, r.s.now().Add(retryAfter)
+	}
+	return nil
+}
+
+func (r *outboxRepo) PurgeSent(ctx context.Context, olderThan time.Duration) (int64, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	cutoff := r.s.now().Add(-olderThan)
+	var purged int64
+	for id, row := range r.s.outbox {
+		if row.sent && row.sentAt.Before(cutoff) {
+			delete(r.s.outbox, id)
+			purged++
+		}
+	}
+	return purged, nil
+}
@@ -0,0 +1,244 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+)
+
+type regionRepo struct{ s *Store }
+
+func NewRegionRepository(s *Store) repository.RegionRepository {
+	return &regionRepo{s: s}
+}
+
+// ========================================
+// Régions
+// ========================================
+
+func (r *regionRepo) GetAllRegions(ctx context.Context) ([]entity.Region, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var regions []entity.Region
+	for _, region := range sortedValues(r.s.regions, func(a, b *entity.Region) int { return strings.Compare(a.Name, b.Name) }) {
+		regions = append(regions, *region)
+	}
+	return regions, nil
+}
+
+func (r *regionRepo) GetRegionByID(ctx context.Context, id string) (*entity.Region, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	region, ok := r.s.regions[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := *region
+	return &copied, nil
+}
+
+func (r *regionRepo) CreateRegion(ctx context.Context, region *entity.Region) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	region.ID = ""
+	return r.s.insertRegion(region)
+}
+
+// insertRegion conserve l'identifiant fourni (données de référence) ; l'appelant détient le verrou
+func (s *Store) insertRegion(region *entity.Region) error {
+	if err := s.checkRegionUnique("", region.Name, region.Code); err != nil {
+		return err
+	}
+	if region.ID == "" {
+		region.ID = newID()
+	}
+	region.CreatedAt = s.timestamp()
+	stored := *region
+	s.regions[region.ID] = &stored
+	return nil
+}
+
+func (s *Store) checkRegionUnique(id, name, code string) error {
+	for _, other := range s.regions {
+		if other.ID == id {
+			continue
+		}
+		if other.Name == name {
+			return uniqueViolation("regions", "name", name)
+		}
+		if other.Code == code {
+			return uniqueViolation("regions", "code", code)
+		}
+	}
+	return nil
+}
+
+func (r *regionRepo) UpdateRegion(ctx context.Context, id, name, code string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	region, ok := r.s.regions[id]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	if err := r.s.checkRegionUnique(id, name, code); err != nil {
+		return err
+	}
+	region.Name, region.Code = name, code
+	return nil
+}
+
+func (r *regionRepo) DeleteRegion(ctx context.Context, id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.regions, id)
+	// ON DELETE CASCADE : départements puis bureaux de vote
+	for deptID, dept := range r.s.departments {
+		if dept.RegionID == id {
+			r.s.deleteDepartment(deptID)
+		}
+	}
+	return nil
+}
+
+// ========================================
+// Départements
+// ========================================
+
+func (r *regionRepo) GetAllDepartments(ctx context.Context) ([]entity.Department, error) {
+	return r.departments(func(*entity.Department) bool { return true })
+}
+
+func (r *regionRepo) GetDepartmentsByRegion(ctx context.Context, regionID string) ([]entity.Department, error) {
+	return r.departments(func(d *entity.Department) bool { return d.RegionID == regionID })
+}
+
+func (r *regionRepo) departments(keep func(*entity.Department) bool) ([]entity.Department, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var depts []entity.Department
+	for _, dept := range sortedValues(r.s.departments, func(a, b *entity.Department) int { return strings.Compare(a.Name, b.Name) }) {
+		if keep(dept) {
+			depts = append(depts, *dept)
+		}
+	}
+	return depts, nil
+}
+
+func (r *regionRepo) CreateDepartment(ctx context.Context, dept *entity.Department) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if err := r.s.checkDepartmentUnique("", dept.Code); err != nil {
+		return err
+	}
+	dept.ID = newID()
+	dept.CreatedAt = r.s.timestamp()
+	stored := *dept
+	r.s.departments[dept.ID] = &stored
+	return nil
+}
+
+func (s *Store) checkDepartmentUnique(id, code string) error {
+	for _, other := range s.departments {
+		if other.ID != id && other.Code == code {
+			return uniqueViolation("departments", "code", code)
+		}
+	}
+	return nil
+}
+
+func (r *regionRepo) UpdateDepartment(ctx context.Context, id, name, code, regionID string, population, voters int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	dept, ok := r.s.departments[id]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	if err := r.s.checkDepartmentUnique(id, code); err != nil {
+		return err
+	}
+	dept.Name, dept.Code, dept.RegionID, dept.Population, dept.RegisteredVoters = name, code, regionID, population, voters
+	return nil
+}
+
+func (r *regionRepo) DeleteDepartment(ctx context.Context, id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	r.s.deleteDepartment(id)
+	return nil
+}
+
+func (s *Store) deleteDepartment(id string) {
+	delete(s.departments, id)
+	for stationID, station := range s.stations {
+		if station.DepartmentID == id {
+			delete(s.stations, stationID)
+		}
+	}
+}
+
+// ========================================
+// Bureaux de vote
+// ========================================
+
+type pollingStationRepo struct{ s *Store }
+
+func NewPollingStationRepository(s *Store) repository.PollingStationRepository {
+	return &pollingStationRepo{s: s}
+}
+
+func (r *pollingStationRepo) GetAll(ctx context.Context) ([]entity.PollingStation, error) {
+	return r.stations(func(*entity.PollingStation) bool { return true })
+}
+
+func (r *pollingStationRepo) GetByDepartment(ctx context.Context, departmentID string) ([]entity.PollingStation, error) {
+	return r.stations(func(ps *entity.PollingStation) bool { return ps.DepartmentID == departmentID })
+}
+
+func (r *pollingStationRepo) stations(keep func(*entity.PollingStation) bool) ([]entity.PollingStation, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var stations []entity.PollingStation
+	for _, ps := range sortedValues(r.s.stations, func(a, b *entity.PollingStation) int { return strings.Compare(a.Code, b.Code) }) {
+		if keep(ps) {
+			stations = append(stations, copyStation(ps))
+		}
+	}
+	return stations, nil
+}
+
+func copyStation(ps *entity.PollingStation) entity.PollingStation {
+	copied := *ps
+	// Les coordonnées ne sont renseignées que par paire, comme à la lecture postgres
+	copied.Latitude, copied.Longitude = nil, nil
+	if ps.Latitude != nil && ps.Longitude != nil {
+		lat, lon := *ps.Latitude, *ps.Longitude
+		copied.Latitude, copied.Longitude = &lat, &lon
+	}
+	return copied
+}
+
+func (r *pollingStationRepo) Upsert(ctx context.Context, ps *entity.PollingStation) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := r.s.timestamp()
+	for _, existing := range r.s.stations {
+		if existing.Code == ps.Code {
+			id, createdAt := existing.ID, existing.CreatedAt
+			*existing = copyStation(ps)
+			existing.ID, existing.CreatedAt, existing.UpdatedAt = id, createdAt, now
+			ps.ID, ps.CreatedAt, ps.UpdatedAt = id, createdAt, now
+			return false, nil
+		}
+	}
+	ps.ID, ps.CreatedAt, ps.UpdatedAt = newID(), now, now
+	stored := copyStation(ps)
+	r.s.stations[ps.ID] = &stored
+	return true, nil
+}
@@ -0,0 +1,207 @@
+package memory
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+)
+
+type reportRepo struct{ s *Store }
+
+func NewReportRepository(s *Store) repository.ReportRepository {
+	return &reportRepo{s: s}
+}
+
+func (r *reportRepo) Create(ctx context.Context, report *entity.Report) error {
+	return r.CreateWithOutbox(ctx, report)
+}
+
+func (r *reportRepo) CreateWithOutbox(ctx context.Context, report *entity.Report, messages ...*entity.OutboxMessage) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if report.ID == "" {
+		report.ID = newID()
+	}
+	if _, exists := r.s.reports[report.ID]; exists {
+		return uniqueViolation("reports", "id", report.ID)
+	}
+	if report.Status == "" {
+		report.Status = entity.StatusPending
+	}
+	// Les champs joints ne sont pas des colonnes de la table
+	stored := *report
+	stored.AuthorRole, stored.AuthorTokenHash, stored.RegionID = "", "", ""
+	stored.CreatedAt = report.CreatedAt.Round(time.Microsecond)
+	// Relu tel que ST_AsText le renvoie (zéros non significatifs retirés)
+	if lat, lon, ok := parseWKTPoint(report.GPSLocation); ok {
+		stored.GPSLocation = formatWKTPoint(lat, lon)
+	}
+	r.s.reports[report.ID] = &reportRow{Report: stored}
+
+	for _, msg := range messages {
+		r.s.insertOutbox(msg)
+	}
+	return nil
+}
+
+// summary reprend les colonnes de la liste (sans empreintes ni champs joints)
+func (row *reportRow) summary() entity.Report {
+	return entity.Report{
+		ID: row.ID, ObserverID: row.ObserverID, IncidentType: row.IncidentType, Description: row.Description,
+		GPSLocation: row.GPSLocation, H3Index: row.H3Index, Status: row.Status, ProofURL: row.ProofURL,
+		Severity: row.Severity, CreatedAt: row.CreatedAt,
+	}
+}
+
+func (r *reportRepo) GetAll(ctx context.Context, status string) ([]entity.Report, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	reports := []entity.Report{}
+	for _, row := range r.s.sortedReports() {
+		if status != "" && string(row.Status) != status {
+			continue
+		}
+		reports = append(reports, row.summary())
+	}
+	return reports, nil
+}
+
+// sortedReports : du plus récent au plus ancien
+func (s *Store) sortedReports() []*reportRow {
+	return sortedValues(s.reports, func(a, b *reportRow) int { return byTime(b.CreatedAt, a.CreatedAt, b.ID, a.ID) })
+}
+
+func (r *reportRepo) GetByID(ctx context.Context, id string) (*entity.Report, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	row, ok := r.s.reports[id]
+	if !ok {
+		return nil, nil
+	}
+	report := row.summary()
+	if u, ok := r.s.users[row.ObserverID]; ok {
+		report.RegionID = u.RegionID
+	}
+	return &report, nil
+}
+
+func (r *reportRepo) FindNearbyWithRole(ctx context.Context, h3Index string, lat, lon, radius float64, start, end time.Time) ([]entity.Report, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var reports []entity.Report
+	for _, row := range r.s.sortedReports() {
+		// Jointure interne : un signalement sans auteur connu n'est pas retenu
+		author, ok := r.s.users[row.ObserverID]
+		if !ok {
+			continue
+		}
+		if row.H3Index != h3Index && !withinRadius(row.GPSLocation, lat, lon, radius) {
+			continue
+		}
+		if row.CreatedAt.Before(start) || row.CreatedAt.After(end) {
+			continue
+		}
+		report := row.summary()
+		report.AuthorRole = author.Role
+		report.SourceFingerprint = row.SourceFingerprint
+		report.DeviceFingerprint = row.DeviceFingerprint
+		report.AuthorTokenHash = author.ActivationTokenHash
+		reports = append(reports, report)
+	}
+	return reports, nil
+}
+
+func (r *reportRepo) UpdateStatus(ctx context.Context, id string, status entity.ReportStatus) error {
+	return r.UpdateStatusWithOutbox(ctx, id, status)
+}
+
+func (r *reportRepo) UpdateStatusWithOutbox(ctx context.Context, id string, status entity.ReportStatus, messages ...*entity.OutboxMessage) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	row, ok := r.s.reports[id]
+	if !ok {
+		return nil
+	}
+	row.Status = status
+	for _, msg := range messages {
+		r.s.insertOutbox(msg)
+	}
+	return nil
+}
+
+func (r *reportRepo) GetReviewQueue(ctx context.Context, limit int) ([]entity.ReviewQueueItem, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	now := r.s.now()
+	items := []entity.ReviewQueueItem{}
+	for _, row := range r.s.reports {
+		if row.Status != entity.StatusPending {
+			continue
+		}
+		items = append(items, entity.ReviewQueueItem{
+			Report:        row.summary(),
+			EventID:       row.eventID,
+			Corroboration: r.s.corroboration(row),
+			AgeMinutes:    now.Sub(row.CreatedAt).Minutes(),
+		})
+	}
+	// Sévérité décroissante, corroboration décroissante puis ancienneté
+	slices.SortFunc(items, func(a, b entity.ReviewQueueItem) int {
+		if a.Severity != b.Severity {
+			return b.Severity - a.Severity
+		}
+		if a.Corroboration != b.Corroboration {
+			return b.Corroboration - a.Corroboration
+		}
+		return byTime(a.CreatedAt, b.CreatedAt, a.ID, b.ID)
+	})
+	if limit >= 0 && len(items) > limit {
+		items = items[:limit]
+	}
+	return items, nil
+}
+
+// corroboration compte les autres signalements non rejetés du même événement consolidé
+func (s *Store) corroboration(row *reportRow) int {
+	if row.eventID == "" {
+		return 0
+	}
+	count := 0
+	for _, other := range s.reports {
+		if other.eventID == row.eventID && other.ID != row.ID && other.Status != entity.StatusRejected {
+			count++
+		}
+	}
+	return count
+}
+
+// deleteReport supprime un signalement et les lignes qui en dépendent (ON DELETE CASCADE)
+func (s *Store) deleteReport(id string) {
+	delete(s.reports, id)
+	for mid, m := range s.matches {
+		if m.ReportID == id {
+			delete(s.matches, mid)
+		}
+	}
+	for aid, a := range s.analyses {
+		if a.ReportID == id {
+			delete(s.analyses, aid)
+		}
+	}
+	for cid, c := range s.clusters {
+		if c.ReportID == id {
+			delete(s.clusters, cid)
+		}
+	}
+	for _, c := range s.conflicts {
+		c.ReportIDs = slices.DeleteFunc(c.ReportIDs, func(reportID string) bool { return reportID == id })
+	}
+}
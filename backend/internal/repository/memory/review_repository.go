@@ -0,0 +1,276 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+)
+
+// ========================================
+// Suspicious Cluster Repository (Sybil)
+// ========================================
+type suspiciousClusterRepo struct{ s *Store }
+
+func NewSuspiciousClusterRepository(s *Store) repository.SuspiciousClusterRepository {
+	return &suspiciousClusterRepo{s: s}
+}
+
+func copyCluster(c *entity.SuspiciousCluster) entity.SuspiciousCluster {
+	copied := *c
+	copied.RelatedReportIDs = cloneStrings(c.RelatedReportIDs)
+	copied.Signals = cloneStrings(c.Signals)
+	copied.ReviewedAt = cloneTime(c.ReviewedAt)
+	return copied
+}
+
+func (r *suspiciousClusterRepo) Upsert(ctx context.Context, c *entity.SuspiciousCluster) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if c.Status == "" {
+		c.Status = entity.ClusterOpen
+	}
+	// Un cluster déjà revu garde son statut : seule la preuve est rafraîchie. De nouveaux
+	// membres n'ont pas été revus : la décision est alors effacée et le cluster rouvert.
+	for _, existing := range r.s.clusters {
+		if existing.ReportID == c.ReportID {
+			if !containsAll(existing.RelatedReportIDs, c.RelatedReportIDs) {
+				existing.Status, existing.ReviewedBy, existing.ReviewNote, existing.ReviewedAt = entity.ClusterOpen, "", "", nil
+			}
+			existing.RelatedReportIDs = cloneStrings(c.RelatedReportIDs)
+			existing.Signals = cloneStrings(c.Signals)
+			existing.Score = c.Score
+			c.ID, c.Status, c.CreatedAt = existing.ID, existing.Status, existing.CreatedAt
+			return nil
+		}
+	}
+	c.ID, c.CreatedAt = newID(), r.s.timestamp()
+	stored := copyCluster(c)
+	stored.ReviewedBy, stored.ReviewNote, stored.ReviewedAt = "", "", nil
+	r.s.clusters[c.ID] = &stored
+	return nil
+}
+
+func (r *suspiciousClusterRepo) GetAll(ctx context.Context, status string) ([]entity.SuspiciousCluster, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	results := []entity.SuspiciousCluster{}
+	for _, c := range sortedValues(r.s.clusters, func(a, b *entity.SuspiciousCluster) int { return byTime(b.CreatedAt, a.CreatedAt, b.ID, a.ID) }) {
+		if status == "" || string(c.Status) == status {
+			results = append(results, copyCluster(c))
+		}
+	}
+	return results, nil
+}
+
+func (r *suspiciousClusterRepo) GetByID(ctx context.Context, id string) (*entity.SuspiciousCluster, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	c, ok := r.s.clusters[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := copyCluster(c)
+	return &copied, nil
+}
+
+func (r *suspiciousClusterRepo) FindByReport(ctx context.Context, reportID string) ([]entity.SuspiciousCluster, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	results := []entity.SuspiciousCluster{}
+	for _, c := range sortedValues(r.s.clusters, func(a, b *entity.SuspiciousCluster) int { return byTime(b.CreatedAt, a.CreatedAt, b.ID, a.ID) }) {
+		if c.ReportID == reportID || slices.Contains(c.RelatedReportIDs, reportID) {
+			results = append(results, copyCluster(c))
+		}
+	}
+	return results, nil
+}
+
+func (r *suspiciousClusterRepo) UpdateStatus(ctx context.Context, id string, status entity.ClusterReviewStatus, reviewerID, note string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	c, ok := r.s.clusters[id]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	c.Status, c.ReviewedBy, c.ReviewNote, c.ReviewedAt = status, reviewerID, note, timePtr(r.s.timestamp())
+	return nil
+}
+
+// ========================================
+// Conflict Repository
+// ========================================
+type conflictRepo struct{ s *Store }
+
+func NewConflictRepository(s *Store) repository.ConflictRepository {
+	return &conflictRepo{s: s}
+}
+
+// copyConflict : signalements liés triés, comme la sous-requête postgres
+func copyConflict(c *entity.Conflict) entity.Conflict {
+	copied := *c
+	copied.IncidentTypes = cloneStrings(c.IncidentTypes)
+	copied.ReportIDs = cloneStrings(c.ReportIDs)
+	slices.Sort(copied.ReportIDs)
+	copied.ResolvedAt = cloneTime(c.ResolvedAt)
+	return copied
+}
+
+func (r *conflictRepo) Create(ctx context.Context, c *entity.Conflict) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if c.Status == "" {
+		c.Status = entity.ConflictOpen
+	}
+	c.ID, c.CreatedAt = newID(), r.s.timestamp()
+	stored := copyConflict(c)
+	stored.ReportIDs = linkReports(nil, c.ReportIDs)
+	r.s.conflicts[c.ID] = &stored
+	return nil
+}
+
+// linkReports ajoute des liaisons sans doublon (ON CONFLICT DO NOTHING)
+func linkReports(linked, reportIDs []string) []string {
+	for _, id := range reportIDs {
+		if !slices.Contains(linked, id) {
+			linked = append(linked, id)
+		}
+	}
+	return linked
+}
+
+func (r *conflictRepo) FindOpenByReports(ctx context.Context, reportIDs []string) (*entity.Conflict, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	for _, c := range r.s.sortedConflicts(false) {
+		if c.Status != entity.ConflictOpen {
+			continue
+		}
+		for _, id := range reportIDs {
+			if slices.Contains(c.ReportIDs, id) {
+				copied := copyConflict(c)
+				return &copied, nil
+			}
+		}
+	}
+	return nil, nil
+}
+
+func (r *conflictRepo) FindResolvedCovering(ctx context.Context, reportIDs []string, incidentTypes []string) (*entity.Conflict, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	var found *entity.Conflict
+	for _, c := range r.s.sortedConflicts(false) {
+		if c.Status != entity.ConflictResolved || !containsAll(c.ReportIDs, reportIDs) || !containsAll(c.IncidentTypes, incidentTypes) {
+			continue
+		}
+		if found == nil || c.ResolvedAt.After(*found.ResolvedAt) {
+			found = c
+		}
+	}
+	if found == nil {
+		return nil, nil
+	}
+	copied := copyConflict(found)
+	return &copied, nil
+}
+
+// containsAll indique si toutes les valeurs de want figurent dans have (opérateur @> postgres)
+func containsAll(have, want []string) bool {
+	for _, v := range want {
+		if !slices.Contains(have, v) {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *Store) sortedConflicts(newestFirst bool) []*entity.Conflict {
+	return sortedValues(s.conflicts, func(a, b *entity.Conflict) int {
+		if newestFirst {
+			return byTime(b.CreatedAt, a.CreatedAt, b.ID, a.ID)
+		}
+		return byTime(a.CreatedAt, b.CreatedAt, a.ID, b.ID)
+	})
+}
+
+func (r *conflictRepo) AddReports(ctx context.Context, conflictID string, reportIDs []string, incidentTypes []string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	c, ok := r.s.conflicts[conflictID]
+	if !ok {
+		return nil
+	}
+	// Union triée des types déjà connus et des nouveaux
+	types := append(cloneStrings(c.IncidentTypes), incidentTypes...)
+	slices.Sort(types)
+	c.IncidentTypes = slices.Compact(types)
+	c.ReportIDs = linkReports(c.ReportIDs, reportIDs)
+	return nil
+}
+
+func (r *conflictRepo) GetByID(ctx context.Context, id string) (*entity.Conflict, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	c, ok := r.s.conflicts[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := copyConflict(c)
+	return &copied, nil
+}
+
+func (r *conflictRepo) GetAll(ctx context.Context, status string) ([]entity.Conflict, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	results := []entity.Conflict{}
+	for _, c := range r.s.sortedConflicts(true) {
+		if status == "" || string(c.Status) == status {
+			results = append(results, copyConflict(c))
+		}
+	}
+	return results, nil
+}
+
+func (r *conflictRepo) HasOpenConflict(ctx context.Context, reportID string) (bool, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	for _, c := range r.s.conflicts {
+		if c.Status == entity.ConflictOpen && slices.Contains(c.ReportIDs, reportID) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *conflictRepo) Assign(ctx context.Context, id, assigneeID string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	c, ok := r.s.conflicts[id]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	c.AssigneeID = assigneeID
+	return nil
+}
+
+func (r *conflictRepo) Resolve(ctx context.Context, id, resolverID, note string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	c, ok := r.s.conflicts[id]
+	if !ok || c.Status != entity.ConflictOpen {
+		return sql.ErrNoRows
+	}
+	c.Status, c.ResolvedBy, c.ResolutionNote, c.ResolvedAt = entity.ConflictResolved, resolverID, note, timePtr(r.s.timestamp())
+	return nil
+}
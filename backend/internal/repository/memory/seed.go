@@ -0,0 +1,49 @@
+package memory
+
+import "github.com/openvote/backend/internal/domain/entity"
+
+// Seed charge les données de référence insérées par les migrations (régions, types d'incidents),
+// sans lesquelles l'API n'est pas utilisable en démonstration
+func Seed(s *Store) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	regions := []struct{ id, name, code string }{
+		{"a1000001-0000-0000-0000-000000000001", "Adamaoua", "AD"},
+		{"a1000001-0000-0000-0000-000000000002", "Centre", "CE"},
+		{"a1000001-0000-0000-0000-000000000003", "Est", "ES"},
+		{"a1000001-0000-0000-0000-000000000004", "Extrême-Nord", "EN"},
+		{"a1000001-0000-0000-0000-000000000005", "Littoral", "LT"},
+		{"a1000001-0000-0000-0000-000000000006", "Nord", "NO"},
+		{"a1000001-0000-0000-0000-000000000007", "Nord-Ouest", "NW"},
+		{"a1000001-0000-0000-0000-000000000008", "Ouest", "OU"},
+		{"a1000001-0000-0000-0000-000000000009", "Sud", "SU"},
+		{"a1000001-0000-0000-0000-000000000010", "Sud-Ouest", "SW"},
+	}
+	for _, r := range regions {
+		if err := s.insertRegion(&entity.Region{ID: r.id, Name: r.name, Code: r.code}); err != nil {
+			return err
+		}
+	}
+
+	incidentTypes := []entity.IncidentType{
+		{Name: "Bourrage d'urnes", Code: "STUFF", Description: "Introduction frauduleuse de bulletins dans l'urne", Severity: 5, Color: "#f85149"},
+		{Name: "Intimidation", Code: "INTIM", Description: "Menaces ou pressions exercées sur les électeurs", Severity: 5, Color: "#f85149"},
+		{Name: "Achat de votes", Code: "BUYV", Description: "Distribution d'argent ou de biens en échange de votes", Severity: 4, Color: "#f0883e"},
+		{Name: "Violence", Code: "VIOLE", Description: "Actes de violence physique liés au processus électoral", Severity: 5, Color: "#da3633"},
+		{Name: "Fermeture anticipée", Code: "EARLY", Description: "Bureau de vote fermé avant l'heure officielle", Severity: 4, Color: "#f0883e"},
+		{Name: "Matériel manquant", Code: "NOMAT", Description: "Absence de matériel électoral nécessaire", Severity: 3, Color: "#d29922"},
+		{Name: "Procuration frauduleuse", Code: "FRAUD", Description: "Utilisation abusive de procurations", Severity: 4, Color: "#f0883e"},
+		{Name: "Obstruction", Code: "OBSTR", Description: "Empêchement de l'accès au bureau de vote", Severity: 4, Color: "#f0883e"},
+		{Name: "Décompte irrégulier", Code: "COUNT", Description: "Anomalies lors du dépouillement des votes", Severity: 5, Color: "#f85149"},
+		{Name: "Propagande illégale", Code: "PROPA", Description: "Propagande électorale le jour du scrutin", Severity: 2, Color: "#8b949e"},
+		{Name: "Défaillance technique", Code: "TECH", Description: "Panne d'équipement ou problème technique", Severity: 2, Color: "#8b949e"},
+		{Name: "Autre", Code: "OTHER", Description: "Incident non catégorisé", Severity: 1, Color: "#8b949e"},
+	}
+	for i := range incidentTypes {
+		if err := s.insertIncidentType(&incidentTypes[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
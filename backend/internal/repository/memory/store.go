@@ -0,0 +1,198 @@
+// Package memory implémente les interfaces de domain/repository en mémoire, sans PostgreSQL.
+//
+// Toutes les tables vivent dans un Store partagé : les jointures du schéma (rôle et région de
+// l'observateur d'un signalement, rattachement aux événements, outbox écrite avec le signalement)
+// se comportent comme avec les repositories postgres. Les contraintes d'unicité et les suppressions
+// en cascade sont reproduites ; l'existence des lignes référencées n'est pas vérifiée. Les données
+// sont perdues à l'arrêt du processus : ce backend sert aux démonstrations, aux formations et aux tests.
+package memory
+
+import (
+	"fmt"
+	"math"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/openvote/backend/internal/domain/entity"
+)
+
+// Store contient l'ensemble des tables ; un seul verrou garantit la cohérence des opérations
+// qui touchent plusieurs tables (l'équivalent d'une transaction)
+type Store struct {
+	mu sync.RWMutex
+
+	users         map[string]*entity.User
+	reports       map[string]*reportRow
+	regions       map[string]*entity.Region
+	departments   map[string]*entity.Department
+	stations      map[string]*entity.PollingStation
+	elections     map[string]*entity.Election
+	auditLogs     []entity.AuditLog
+	incidentTypes map[string]*entity.IncidentType
+
+	documents map[string]*entity.LegalDocument
+	articles  map[string]*articleRow
+	matches   map[string]*entity.ReportLegalMatch
+	analyses  map[string]*entity.LegalAnalysis
+
+	clusters  map[string]*entity.SuspiciousCluster
+	conflicts map[string]*entity.Conflict
+	events    map[string]*entity.IncidentEvent
+	outbox    map[string]*outboxRow
+
+	webhooks          map[string]*entity.WebhookSubscription
+	webhookDeliveries map[string]*entity.WebhookDelivery
+	alertRules        map[string]*entity.AlertRule
+	alerts            map[string]*entity.Alert
+
+	preferences            map[string]*entity.NotificationPreferences
+	notifications          map[string]*entity.Notification
+	notificationDeliveries map[string]*entity.NotificationDelivery
+
+	configVersions []entity.ConfigVersion
+
+	// now est remplaçable dans les tests (baux, fenêtres de rétention)
+	now func() time.Time
+}
+
+// reportRow ajoute au signalement les colonnes absentes de l'entité
+type reportRow struct {
+	entity.Report
+	eventID string
+}
+
+// articleRow ajoute à l'article son vecteur d'embedding (colonne pgvector)
+type articleRow struct {
+	entity.LegalArticle
+	embedding []float32
+}
+
+// outboxRow ajoute au message son état de publication
+type outboxRow struct {
+	entity.OutboxMessage
+	sent        bool
+	availableAt time.Time
+	sentAt      time.Time
+}
+
+func NewStore() *Store {
+	return &Store{
+		users:                  make(map[string]*entity.User),
+		reports:                make(map[string]*reportRow),
+		regions:                make(map[string]*entity.Region),
+		departments:            make(map[string]*entity.Department),
+		stations:               make(map[string]*entity.PollingStation),
+		elections:              make(map[string]*entity.Election),
+		incidentTypes:          make(map[string]*entity.IncidentType),
+		documents:              make(map[string]*entity.LegalDocument),
+		articles:               make(map[string]*articleRow),
+		matches:                make(map[string]*entity.ReportLegalMatch),
+		analyses:               make(map[string]*entity.LegalAnalysis),
+		clusters:               make(map[string]*entity.SuspiciousCluster),
+		conflicts:              make(map[string]*entity.Conflict),
+		events:                 make(map[string]*entity.IncidentEvent),
+		outbox:                 make(map[string]*outboxRow),
+		webhooks:               make(map[string]*entity.WebhookSubscription),
+		webhookDeliveries:      make(map[string]*entity.WebhookDelivery),
+		alertRules:             make(map[string]*entity.AlertRule),
+		alerts:                 make(map[string]*entity.Alert),
+		preferences:            make(map[string]*entity.NotificationPreferences),
+		notifications:          make(map[string]*entity.Notification),
+		notificationDeliveries: make(map[string]*entity.NotificationDelivery),
+		now:                    time.Now,
+	}
+}
+
+// SetClock remplace l'horloge du store (tests des baux et des rétentions)
+func (s *Store) SetClock(now func() time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.now = now
+}
+
+// timestamp reproduit la précision des colonnes TIMESTAMPTZ (microseconde)
+func (s *Store) timestamp() time.Time {
+	return s.now().Truncate(time.Microsecond)
+}
+
+func newID() string {
+	return uuid.New().String()
+}
+
+// uniqueViolation reproduit l'erreur d'une contrainte UNIQUE
+func uniqueViolation(table, column, value string) error {
+	return fmt.Errorf("duplicate key value violates unique constraint on %s.%s: %q", table, column, value)
+}
+
+// sortedValues retourne les lignes d'une table dans l'ordre demandé
+func sortedValues[T any](rows map[string]*T, less func(a, b *T) int) []*T {
+	out := make([]*T, 0, len(rows))
+	for _, row := range rows {
+		out = append(out, row)
+	}
+	slices.SortFunc(out, less)
+	return out
+}
+
+// byTime compare deux horodatages ; l'identifiant départage les égalités pour un ordre stable
+func byTime(a, b time.Time, idA, idB string) int {
+	if c := a.Compare(b); c != 0 {
+		return c
+	}
+	if idA < idB {
+		return -1
+	}
+	if idA > idB {
+		return 1
+	}
+	return 0
+}
+
+func timePtr(t time.Time) *time.Time {
+	return &t
+}
+
+// cloneTime copie un horodatage optionnel pour ne pas partager le pointeur stocké
+func cloneTime(t *time.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	return timePtr(*t)
+}
+
+// cloneStrings copie un tableau en conservant la distinction nil / vide des colonnes NOT NULL
+func cloneStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return slices.Clone(values)
+}
+
+// parseWKTPoint extrait latitude et longitude d'un point WKT "POINT(lon lat)"
+func parseWKTPoint(wkt string) (lat, lon float64, ok bool) {
+	if _, err := fmt.Sscanf(wkt, "POINT(%f %f)", &lon, &lat); err != nil {
+		return 0, 0, false
+	}
+	return lat, lon, true
+}
+
+// distanceMeters approche ST_Distance sur geography (sphère de rayon moyen)
+func distanceMeters(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadius = 6371000.0
+	toRad := func(d float64) float64 { return d * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
+
+// withinRadius : équivalent de ST_DWithin entre un point WKT et des coordonnées
+func withinRadius(wkt string, lat, lon, radius float64) bool {
+	pLat, pLon, ok := parseWKTPoint(wkt)
+	if !ok {
+		return false
+	}
+	return distanceMeters(lat, lon, pLat, pLon) <= radius
+}
@@ -0,0 +1,132 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+)
+
+type userRepo struct{ s *Store }
+
+func NewUserRepository(s *Store) repository.UserRepository {
+	return &userRepo{s: s}
+}
+
+func (r *userRepo) Create(ctx context.Context, user *entity.User) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	if user.ID == "" {
+		user.ID = newID()
+	}
+	if _, exists := r.s.users[user.ID]; exists {
+		return uniqueViolation("users", "id", user.ID)
+	}
+	for _, u := range r.s.users {
+		if u.Username == user.Username {
+			return uniqueViolation("users", "username", user.Username)
+		}
+	}
+	stored := *user
+	stored.LastLoginAt = nil
+	stored.CreatedAt, stored.UpdatedAt = user.CreatedAt.Round(time.Microsecond), user.UpdatedAt.Round(time.Microsecond)
+	r.s.users[user.ID] = &stored
+	return nil
+}
+
+// GetByID et GetByUsername renvoient les mêmes colonnes que le repository postgres
+// (ni dernière connexion, ni empreinte du token d'activation)
+func (r *userRepo) GetByID(ctx context.Context, id string) (*entity.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	u, ok := r.s.users[id]
+	if !ok {
+		return nil, nil
+	}
+	return credentialsView(u), nil
+}
+
+func (r *userRepo) GetByUsername(ctx context.Context, username string) (*entity.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	for _, u := range r.s.users {
+		if u.Username == username {
+			return credentialsView(u), nil
+		}
+	}
+	return nil, nil
+}
+
+func credentialsView(u *entity.User) *entity.User {
+	return &entity.User{ID: u.ID, Username: u.Username, Role: u.Role, PasswordHash: u.PasswordHash, RegionID: u.RegionID, CreatedAt: u.CreatedAt, UpdatedAt: u.UpdatedAt}
+}
+
+func (r *userRepo) GetAll(ctx context.Context) ([]entity.User, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	rows := sortedValues(r.s.users, func(a, b *entity.User) int { return byTime(b.CreatedAt, a.CreatedAt, b.ID, a.ID) })
+	var users []entity.User
+	for _, u := range rows {
+		users = append(users, entity.User{ID: u.ID, Username: u.Username, Role: u.Role, RegionID: u.RegionID, CreatedAt: u.CreatedAt, UpdatedAt: u.UpdatedAt, LastLoginAt: cloneTime(u.LastLoginAt)})
+	}
+	return users, nil
+}
+
+func (r *userRepo) UpdateRole(ctx context.Context, id string, role entity.UserRole, regionID string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	u, ok := r.s.users[id]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	u.Role, u.RegionID, u.UpdatedAt = role, regionID, r.s.timestamp()
+	return nil
+}
+
+func (r *userRepo) UpdateLastLogin(ctx context.Context, id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if u, ok := r.s.users[id]; ok {
+		u.LastLoginAt = timePtr(r.s.timestamp())
+	}
+	return nil
+}
+
+func (r *userRepo) UpdatePasswordHash(ctx context.Context, id, passwordHash string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	u, ok := r.s.users[id]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	u.PasswordHash, u.UpdatedAt = passwordHash, r.s.timestamp()
+	return nil
+}
+
+func (r *userRepo) Delete(ctx context.Context, id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.users, id)
+	// ON DELETE CASCADE : signalements de l'utilisateur et données de notification
+	for reportID, row := range r.s.reports {
+		if row.ObserverID == id {
+			r.s.deleteReport(reportID)
+		}
+	}
+	delete(r.s.preferences, id)
+	for nid, n := range r.s.notifications {
+		if n.UserID == id {
+			delete(r.s.notifications, nid)
+		}
+	}
+	for did, d := range r.s.notificationDeliveries {
+		if d.UserID == id {
+			delete(r.s.notificationDeliveries, did)
+		}
+	}
+	return nil
+}
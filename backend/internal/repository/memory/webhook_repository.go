@@ -0,0 +1,218 @@
+package memory
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+)
+
+type webhookRepo struct{ s *Store }
+
+func NewWebhookRepository(s *Store) repository.WebhookRepository {
+	return &webhookRepo{s: s}
+}
+
+func copySubscription(sub *entity.WebhookSubscription) entity.WebhookSubscription {
+	copied := *sub
+	copied.EventTypes = cloneStrings(sub.EventTypes)
+	copied.RegionIDs = cloneStrings(sub.RegionIDs)
+	return copied
+}
+
+func (r *webhookRepo) CreateSubscription(ctx context.Context, sub *entity.WebhookSubscription) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	now := r.s.timestamp()
+	sub.ID, sub.CreatedAt, sub.UpdatedAt = newID(), now, now
+	stored := copySubscription(sub)
+	r.s.webhooks[sub.ID] = &stored
+	return nil
+}
+
+// UpdateSubscription ne touche pas au secret, renouvelé par UpdateSecret
+func (r *webhookRepo) UpdateSubscription(ctx context.Context, sub *entity.WebhookSubscription) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	stored, ok := r.s.webhooks[sub.ID]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	stored.Name, stored.URL, stored.MinSeverity, stored.Active = sub.Name, sub.URL, sub.MinSeverity, sub.Active
+	stored.EventTypes, stored.RegionIDs = cloneStrings(sub.EventTypes), cloneStrings(sub.RegionIDs)
+	stored.UpdatedAt = r.s.timestamp()
+	sub.UpdatedAt = stored.UpdatedAt
+	return nil
+}
+
+func (r *webhookRepo) UpdateSecret(ctx context.Context, id, secret string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	stored, ok := r.s.webhooks[id]
+	if !ok {
+		return sql.ErrNoRows
+	}
+	stored.Secret, stored.UpdatedAt = secret, r.s.timestamp()
+	return nil
+}
+
+func (r *webhookRepo) DeleteSubscription(ctx context.Context, id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	delete(r.s.webhooks, id)
+	for did, d := range r.s.webhookDeliveries {
+		if d.SubscriptionID == id {
+			delete(r.s.webhookDeliveries, did)
+		}
+	}
+	return nil
+}
+
+func (r *webhookRepo) GetSubscription(ctx context.Context, id string) (*entity.WebhookSubscription, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	sub, ok := r.s.webhooks[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := copySubscription(sub)
+	return &copied, nil
+}
+
+func (r *webhookRepo) ListSubscriptions(ctx context.Context) ([]entity.WebhookSubscription, error) {
+	return r.subscriptions(func(*entity.WebhookSubscription) bool { return true })
+}
+
+func (r *webhookRepo) ActiveSubscriptions(ctx context.Context, eventType string) ([]entity.WebhookSubscription, error) {
+	return r.subscriptions(func(sub *entity.WebhookSubscription) bool {
+		return sub.Active && slices.Contains(sub.EventTypes, eventType)
+	})
+}
+
+func (r *webhookRepo) subscriptions(keep func(*entity.WebhookSubscription) bool) ([]entity.WebhookSubscription, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	subs := []entity.WebhookSubscription{}
+	for _, sub := range sortedValues(r.s.webhooks, func(a, b *entity.WebhookSubscription) int { return byTime(b.CreatedAt, a.CreatedAt, b.ID, a.ID) }) {
+		if keep(sub) {
+			subs = append(subs, copySubscription(sub))
+		}
+	}
+	return subs, nil
+}
+
+func copyWebhookDelivery(d *entity.WebhookDelivery) entity.WebhookDelivery {
+	copied := *d
+	copied.Payload = slices.Clone(d.Payload)
+	copied.DeliveredAt = cloneTime(d.DeliveredAt)
+	return copied
+}
+
+// CreateDelivery ignore un événement déjà planifié pour cet abonné (ON CONFLICT DO NOTHING)
+func (r *webhookRepo) CreateDelivery(ctx context.Context, d *entity.WebhookDelivery) (bool, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	for _, existing := range r.s.webhookDeliveries {
+		if existing.SubscriptionID == d.SubscriptionID && existing.EventID == d.EventID {
+			return false, nil
+		}
+	}
+	now := r.s.timestamp()
+	d.ID, d.Status, d.NextAttemptAt, d.CreatedAt = newID(), entity.DeliveryPending, now, now
+	stored := entity.WebhookDelivery{
+		ID: d.ID, SubscriptionID: d.SubscriptionID, EventID: d.EventID, EventType: d.EventType, Payload: slices.Clone(d.Payload),
+		Status: d.Status, NextAttemptAt: now, CreatedAt: now,
+	}
+	r.s.webhookDeliveries[d.ID] = &stored
+	return true, nil
+}
+
+func (r *webhookRepo) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.WebhookDelivery, error) {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+
+	// Le bail repousse next_attempt_at : si le dispatcher meurt, la livraison sera reprise
+	now := r.s.timestamp()
+	rows := sortedValues(r.s.webhookDeliveries, func(a, b *entity.WebhookDelivery) int { return byTime(a.NextAttemptAt, b.NextAttemptAt, a.ID, b.ID) })
+	var deliveries []entity.WebhookDelivery
+	for _, d := range rows {
+		if len(deliveries) >= limit {
+			break
+		}
+		if d.Status != entity.DeliveryPending || d.NextAttemptAt.After(now) {
+			continue
+		}
+		d.NextAttemptAt = now.Add(lease)
+		d.Attempts++
+		deliveries = append(deliveries, copyWebhookDelivery(d))
+	}
+	return deliveries, nil
+}
+
+func (r *webhookRepo) MarkDelivered(ctx context.Context, id string, responseStatus int) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if d, ok := r.s.webhookDeliveries[id]; ok {
+		d.Status, d.ResponseStatus, d.LastError, d.DeliveredAt = entity.DeliveryDelivered, responseStatus, "", timePtr(r.s.timestamp())
+	}
+	return nil
+}
+
+func (r *webhookRepo) MarkFailed(ctx context.Context, id string, responseStatus int, cause string, retryAt *time.Time) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	d, ok := r.s.webhookDeliveries[id]
+	if !ok {
+		return nil
+	}
+	d.ResponseStatus, d.LastError = responseStatus, cause
+	if retryAt == nil {
+		d.Status = entity.DeliveryFailed
+		return nil
+	}
+	d.NextAttemptAt = retryAt.Truncate(time.Microsecond)
+	return nil
+}
+
+func (r *webhookRepo) GetDelivery(ctx context.Context, id string) (*entity.WebhookDelivery, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+	d, ok := r.s.webhookDeliveries[id]
+	if !ok {
+		return nil, nil
+	}
+	copied := copyWebhookDelivery(d)
+	return &copied, nil
+}
+
+func (r *webhookRepo) ListDeliveries(ctx context.Context, subscriptionID, status string, limit int) ([]entity.WebhookDelivery, error) {
+	r.s.mu.RLock()
+	defer r.s.mu.RUnlock()
+
+	deliveries := []entity.WebhookDelivery{}
+	for _, d := range sortedValues(r.s.webhookDeliveries, func(a, b *entity.WebhookDelivery) int { return byTime(b.CreatedAt, a.CreatedAt, b.ID, a.ID) }) {
+		if len(deliveries) >= limit {
+			break
+		}
+		if d.SubscriptionID == subscriptionID && (status == "" || string(d.Status) == status) {
+			deliveries = append(deliveries, copyWebhookDelivery(d))
+		}
+	}
+	return deliveries, nil
+}
+
+func (r *webhookRepo) ResetDelivery(ctx context.Context, id string) error {
+	r.s.mu.Lock()
+	defer r.s.mu.Unlock()
+	if d, ok := r.s.webhookDeliveries[id]; ok {
+		d.Status, d.Attempts, d.LastError, d.NextAttemptAt, d.DeliveredAt = entity.DeliveryPending, 0, "", r.s.timestamp(), nil
+	}
+	return nil
+}
@@ -0,0 +1,232 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/lib/pq"
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+)
+
+type alertRepo struct{ db *sql.DB }
+
+func NewAlertRepository(db *sql.DB) repository.AlertRepository {
+	return &alertRepo{db: db}
+}
+
+const alertRuleColumns = `id, name, COALESCE(description,''), incident_types, min_severity, region_ids, group_by, h3_resolution, threshold, window_minutes,
+	COALESCE(time_of_day_start,''), COALESCE(time_of_day_end,''), timezone, channels, active, COALESCE(created_by,''), created_at, updated_at`
+
+func scanAlertRule(row interface{ Scan(...interface{}) error }) (*entity.AlertRule, error) {
+	var rule entity.AlertRule
+	err := row.Scan(&rule.ID, &rule.Name, &rule.Description, pq.Array(&rule.IncidentTypes), &rule.MinSeverity, pq.Array(&rule.RegionIDs),
+		&rule.GroupBy, &rule.H3Resolution, &rule.Threshold, &rule.WindowMinutes, &rule.TimeOfDayStart, &rule.TimeOfDayEnd, &rule.Timezone,
+		pq.Array(&rule.Channels), &rule.Active, &rule.CreatedBy, &rule.CreatedAt, &rule.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+func (r *alertRepo) CreateRule(ctx context.Context, rule *entity.AlertRule) error {
+	query := `INSERT INTO alert_rules (name, description, incident_types, min_severity, region_ids, group_by, h3_resolution, threshold, window_minutes,
+	            time_of_day_start, time_of_day_end, timezone, channels, active, created_by)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15)
+	          RETURNING id, created_at, updated_at`
+	return r.db.QueryRowContext(ctx, query, rule.Name, rule.Description, pq.Array(nonNilStrings(rule.IncidentTypes)), rule.MinSeverity,
+		pq.Array(nonNilStrings(rule.RegionIDs)), rule.GroupBy, rule.H3Resolution, rule.Threshold, rule.WindowMinutes,
+		rule.TimeOfDayStart, rule.TimeOfDayEnd, rule.Timezone, pq.Array(nonNilStrings(rule.Channels)), rule.Active, rule.CreatedBy).
+		Scan(&rule.ID, &rule.CreatedAt, &rule.UpdatedAt)
+}
+
+func (r *alertRepo) UpdateRule(ctx context.Context, rule *entity.AlertRule) error {
+	query := `UPDATE alert_rules SET name = $1, description = $2, incident_types = $3, min_severity = $4, region_ids = $5, group_by = $6,
+	            h3_resolution = $7, threshold = $8, window_minutes = $9, time_of_day_start = $10, time_of_day_end = $11, timezone = $12,
+	            channels = $13, active = $14, updated_at = NOW()
+	          WHERE id = $15
+	          RETURNING updated_at`
+	return r.db.QueryRowContext(ctx, query, rule.Name, rule.Description, pq.Array(nonNilStrings(rule.IncidentTypes)), rule.MinSeverity,
+		pq.Array(nonNilStrings(rule.RegionIDs)), rule.GroupBy, rule.H3Resolution, rule.Threshold, rule.WindowMinutes,
+		rule.TimeOfDayStart, rule.TimeOfDayEnd, rule.Timezone, pq.Array(nonNilStrings(rule.Channels)), rule.Active, rule.ID).
+		Scan(&rule.UpdatedAt)
+}
+
+func (r *alertRepo) DeleteRule(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM alert_rules WHERE id = $1`, id)
+	return err
+}
+
+func (r *alertRepo) GetRule(ctx context.Context, id string) (*entity.AlertRule, error) {
+	rule, err := scanAlertRule(r.db.QueryRowContext(ctx, `SELECT `+alertRuleColumns+` FROM alert_rules WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return rule, err
+}
+
+func (r *alertRepo) ListRules(ctx context.Context) ([]entity.AlertRule, error) {
+	return r.queryRules(ctx, `SELECT `+alertRuleColumns+` FROM alert_rules ORDER BY created_at DESC`)
+}
+
+func (r *alertRepo) ActiveRules(ctx context.Context) ([]entity.AlertRule, error) {
+	return r.queryRules(ctx, `SELECT `+alertRuleColumns+` FROM alert_rules WHERE active ORDER BY created_at`)
+}
+
+func (r *alertRepo) queryRules(ctx context.Context, query string, args ...interface{}) ([]entity.AlertRule, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	rules := []entity.AlertRule{}
+	for rows.Next() {
+		rule, err := scanAlertRule(rows)
+		if err != nil {
+			return nil, err
+		}
+		rules = append(rules, *rule)
+	}
+	return rules, rows.Err()
+}
+
+func (r *alertRepo) ReportsInWindow(ctx context.Context, f repository.AlertReportFilter) ([]entity.Report, error) {
+	// Le département (bureau de vote géolocalisé le plus proche) n'est calculé que s'il est demandé
+	department, departmentJoin := `''`, ``
+	if f.WithDepartment {
+		department = `COALESCE(ps.department_id::text, '')`
+		departmentJoin = `LEFT JOIN LATERAL (
+	            SELECT department_id FROM polling_stations
+	            WHERE latitude IS NOT NULL AND longitude IS NOT NULL AND r.gps_location IS NOT NULL
+	            ORDER BY r.gps_location <-> ST_SetSRID(ST_MakePoint(longitude, latitude), 4326)
+	            LIMIT 1) ps ON TRUE`
+	}
+	// Les types d'incident de la règle peuvent être des codes ou des noms, comme à la soumission
+	query := `SELECT r.id, r.observer_id, r.incident_type, r.h3_index, r.status, r.severity, r.created_at, COALESCE(u.region_id, ''), ` + department + `
+	          FROM reports r
+	          LEFT JOIN users u ON r.observer_id = u.id
+	          LEFT JOIN incident_types it ON it.code = r.incident_type OR it.name = r.incident_type
+	          ` + departmentJoin + `
+	          WHERE r.created_at >= $1 AND r.created_at <= $2
+	            AND r.severity >= $3
+	            AND r.status <> 'rejected'
+	            AND (cardinality($4::text[]) = 0 OR r.incident_type = ANY($4) OR it.code = ANY($4) OR it.name = ANY($4))
+	            AND (cardinality($5::text[]) = 0 OR u.region_id = ANY($5))
+	          ORDER BY r.created_at`
+	rows, err := r.db.QueryContext(ctx, query, f.Since, f.Until, f.MinSeverity, pq.Array(nonNilStrings(f.IncidentTypes)), pq.Array(nonNilStrings(f.RegionIDs)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var reports []entity.Report
+	for rows.Next() {
+		var rep entity.Report
+		if err := rows.Scan(&rep.ID, &rep.ObserverID, &rep.IncidentType, &rep.H3Index, &rep.Status, &rep.Severity, &rep.CreatedAt, &rep.RegionID,
+			&rep.DepartmentID); err != nil {
+			return nil, err
+		}
+		reports = append(reports, rep)
+	}
+	return reports, rows.Err()
+}
+
+const alertColumns = `a.id, a.rule_id, ar.name, a.group_key, COALESCE(a.region_id,''), a.status, a.severity, a.report_ids, a.report_count,
+	a.first_triggered_at, a.last_triggered_at, COALESCE(a.acknowledged_by,''), a.acknowledged_at, COALESCE(a.resolved_by,''), a.resolved_at,
+	COALESCE(a.resolution_note,'')`
+
+func scanAlert(row interface{ Scan(...interface{}) error }) (*entity.Alert, error) {
+	var a entity.Alert
+	var ackAt, resolvedAt sql.NullTime
+	err := row.Scan(&a.ID, &a.RuleID, &a.RuleName, &a.GroupKey, &a.RegionID, &a.Status, &a.Severity, pq.Array(&a.ReportIDs), &a.ReportCount,
+		&a.FirstTriggeredAt, &a.LastTriggeredAt, &a.AcknowledgedBy, &ackAt, &a.ResolvedBy, &resolvedAt, &a.ResolutionNote)
+	if err != nil {
+		return nil, err
+	}
+	if ackAt.Valid {
+		a.AcknowledgedAt = &ackAt.Time
+	}
+	if resolvedAt.Valid {
+		a.ResolvedAt = &resolvedAt.Time
+	}
+	return &a, nil
+}
+
+func (r *alertRepo) UpsertOpenAlert(ctx context.Context, a *entity.Alert) (bool, error) {
+	// Une alerte encore ouverte cumule les signalements des déclenchements successifs ; une alerte
+	// acquittée ou résolue n'en absorbe plus (nouvelle alerte).
+	// xmax = 0 : la ligne vient d'être insérée (et non mise à jour par ON CONFLICT)
+	query := `INSERT INTO alerts (rule_id, group_key, region_id, severity, report_ids, report_count)
+	          VALUES ($1, $2, $3, $4, $5, $6)
+	          ON CONFLICT (rule_id, group_key) WHERE status = 'open'
+	          DO UPDATE SET report_ids = ARRAY(SELECT DISTINCT unnest(alerts.report_ids || EXCLUDED.report_ids)),
+	            report_count = cardinality(ARRAY(SELECT DISTINCT unnest(alerts.report_ids || EXCLUDED.report_ids))),
+	            severity = GREATEST(alerts.severity, EXCLUDED.severity), last_triggered_at = NOW()
+	          RETURNING id, status, severity, report_ids, report_count, first_triggered_at, last_triggered_at, (xmax = 0)`
+	var created bool
+	err := r.db.QueryRowContext(ctx, query, a.RuleID, a.GroupKey, a.RegionID, a.Severity, pq.Array(nonNilStrings(a.ReportIDs)), a.ReportCount).
+		Scan(&a.ID, &a.Status, &a.Severity, pq.Array(&a.ReportIDs), &a.ReportCount, &a.FirstTriggeredAt, &a.LastTriggeredAt, &created)
+	return created, err
+}
+
+func (r *alertRepo) GetAlert(ctx context.Context, id string) (*entity.Alert, error) {
+	a, err := scanAlert(r.db.QueryRowContext(ctx, `SELECT `+alertColumns+` FROM alerts a JOIN alert_rules ar ON ar.id = a.rule_id WHERE a.id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return a, err
+}
+
+func (r *alertRepo) ListAlerts(ctx context.Context, f repository.AlertFilter) ([]entity.Alert, error) {
+	query := `SELECT ` + alertColumns + ` FROM alerts a JOIN alert_rules ar ON ar.id = a.rule_id WHERE 1=1`
+	var args []interface{}
+	if f.Status != "" {
+		args = append(args, f.Status)
+		query += fmt.Sprintf(` AND a.status = $%d`, len(args))
+	}
+	if f.RegionID != "" {
+		args = append(args, f.RegionID)
+		query += fmt.Sprintf(` AND a.region_id = $%d`, len(args))
+	}
+	if f.RuleID != "" {
+		args = append(args, f.RuleID)
+		query += fmt.Sprintf(` AND a.rule_id = $%d`, len(args))
+	}
+	limit := f.Limit
+	if limit <= 0 {
+		limit = 100
+	}
+	query += fmt.Sprintf(` ORDER BY a.last_triggered_at DESC LIMIT %d`, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	alerts := []entity.Alert{}
+	for rows.Next() {
+		a, err := scanAlert(rows)
+		if err != nil {
+			return nil, err
+		}
+		alerts = append(alerts, *a)
+	}
+	return alerts, rows.Err()
+}
+
+func (r *alertRepo) UpdateAlertStatus(ctx context.Context, id string, status entity.AlertStatus, by, note string) error {
+	switch status {
+	case entity.AlertAcknowledged:
+		query := `UPDATE alerts SET status = $1, acknowledged_by = $2, acknowledged_at = NOW() WHERE id = $3`
+		_, err := r.db.ExecContext(ctx, query, status, by, id)
+		return err
+	case entity.AlertResolved:
+		query := `UPDATE alerts SET status = $1, resolved_by = $2, resolved_at = NOW(), resolution_note = $3 WHERE id = $4`
+		_, err := r.db.ExecContext(ctx, query, status, by, note, id)
+		return err
+	}
+	return fmt.Errorf("unsupported alert status %q", status)
+}
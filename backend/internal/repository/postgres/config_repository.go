@@ -0,0 +1,119 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+)
+
+type configRepo struct{ db *sql.DB }
+
+func NewConfigRepository(db *sql.DB) repository.ConfigRepository {
+	return &configRepo{db: db}
+}
+
+const configVersionColumns = `version, section, data, COALESCE(changed_by,''), COALESCE(comment,''), rolled_back_from, created_at`
+
+func scanConfigVersion(row interface{ Scan(...interface{}) error }) (*entity.ConfigVersion, error) {
+	var v entity.ConfigVersion
+	var data []byte
+	var rolledBackFrom sql.NullInt64
+	if err := row.Scan(&v.Version, &v.Section, &data, &v.ChangedBy, &v.Comment, &rolledBackFrom, &v.CreatedAt); err != nil {
+		return nil, err
+	}
+	v.Data = data
+	if rolledBackFrom.Valid {
+		v.RolledBackFrom = &rolledBackFrom.Int64
+	}
+	return &v, nil
+}
+
+func (r *configRepo) queryVersions(ctx context.Context, query string, args ...interface{}) ([]entity.ConfigVersion, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	versions := []entity.ConfigVersion{}
+	for rows.Next() {
+		v, err := scanConfigVersion(rows)
+		if err != nil {
+			return nil, err
+		}
+		versions = append(versions, *v)
+	}
+	return versions, rows.Err()
+}
+
+func (r *configRepo) Latest(ctx context.Context) ([]entity.ConfigVersion, error) {
+	query := `SELECT DISTINCT ON (section) ` + configVersionColumns + `
+	          FROM system_config_versions ORDER BY section, version DESC`
+	return r.queryVersions(ctx, query)
+}
+
+func (r *configRepo) CurrentVersion(ctx context.Context) (int64, error) {
+	var version int64
+	err := r.db.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM system_config_versions`).Scan(&version)
+	return version, err
+}
+
+func (r *configRepo) GetVersion(ctx context.Context, version int64) (*entity.ConfigVersion, error) {
+	query := `SELECT ` + configVersionColumns + ` FROM system_config_versions WHERE version = $1`
+	v, err := scanConfigVersion(r.db.QueryRowContext(ctx, query, version))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return v, err
+}
+
+func (r *configRepo) History(ctx context.Context, section string, limit int) ([]entity.ConfigVersion, error) {
+	query := `SELECT ` + configVersionColumns + ` FROM system_config_versions WHERE section = $1
+	          ORDER BY version DESC` + fmt.Sprintf(` LIMIT %d`, limit)
+	return r.queryVersions(ctx, query, section)
+}
+
+func (r *configRepo) Append(ctx context.Context, v *entity.ConfigVersion, baseVersion int64) error {
+	return r.AppendAll(ctx, []*entity.ConfigVersion{v}, map[string]int64{v.Section: baseVersion})
+}
+
+func (r *configRepo) AppendAll(ctx context.Context, versions []*entity.ConfigVersion, baseVersions map[string]int64) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Sérialise les écritures d'une même section entre instances jusqu'à la fin de la transaction ;
+	// les verrous sont pris dans l'ordre des sections pour éviter les interblocages
+	sections := make([]string, 0, len(versions))
+	for _, v := range versions {
+		sections = append(sections, v.Section)
+	}
+	slices.Sort(sections)
+	for _, section := range sections {
+		if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('system_config:' || $1))`, section); err != nil {
+			return err
+		}
+		var latest int64
+		if err := tx.QueryRowContext(ctx, `SELECT COALESCE(MAX(version), 0) FROM system_config_versions WHERE section = $1`, section).Scan(&latest); err != nil {
+			return err
+		}
+		if latest != baseVersions[section] {
+			return repository.ErrConfigVersionConflict
+		}
+	}
+
+	query := `INSERT INTO system_config_versions (section, data, changed_by, comment, rolled_back_from)
+	          VALUES ($1, $2, $3, $4, $5) RETURNING version, created_at`
+	for _, v := range versions {
+		if err := tx.QueryRowContext(ctx, query, v.Section, []byte(v.Data), v.ChangedBy, v.Comment, v.RolledBackFrom).Scan(&v.Version, &v.CreatedAt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
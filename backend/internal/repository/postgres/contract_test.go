@@ -0,0 +1,98 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"net/url"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+	_ "github.com/lib/pq"
+	"github.com/openvote/backend/internal/platform/migrate"
+	"github.com/openvote/backend/internal/repository/repositorytest"
+)
+
+// La suite de contrat ne s'exécute que sur une base fournie (PostGIS et pgvector requis) ;
+// chaque sous-test migre son propre schéma, supprimé à la fin du test
+func TestRepositoryContract(t *testing.T) {
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL non défini")
+	}
+	admin, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("open database: %v", err)
+	}
+	defer admin.Close()
+	// Les extensions sont installées une fois dans public : les migrations du schéma de test les retrouvent
+	for _, ext := range []string{`"uuid-ossp"`, "postgis", "vector"} {
+		if _, err := admin.Exec(`CREATE EXTENSION IF NOT EXISTS ` + ext + ` SCHEMA public`); err != nil {
+			t.Fatalf("extension %s: %v", ext, err)
+		}
+	}
+
+	repositorytest.Run(t, func(t *testing.T) repositorytest.Repositories {
+		db := openTestSchema(t, admin, dsn)
+		return repositorytest.Repositories{
+			Users:         NewUserRepository(db),
+			Reports:       NewReportRepository(db),
+			Regions:       NewRegionRepository(db),
+			Stations:      NewPollingStationRepository(db),
+			Elections:     NewElectionRepository(db),
+			AuditLogs:     NewAuditLogRepository(db),
+			IncidentTypes: NewIncidentTypeRepository(db),
+			Legal:         NewLegalRepository(db),
+			Clusters:      NewSuspiciousClusterRepository(db),
+			Conflicts:     NewConflictRepository(db),
+			Events:        NewIncidentEventRepository(db),
+			Outbox:        NewOutboxRepository(db),
+			Webhooks:      NewWebhookRepository(db),
+			Alerts:        NewAlertRepository(db),
+			Notifications: NewNotificationRepository(db),
+			Config:        NewConfigRepository(db),
+		}
+	})
+}
+
+// openTestSchema crée un schéma vierge, y applique toutes les migrations et retourne une
+// connexion dont le search_path le place devant public
+func openTestSchema(t *testing.T, admin *sql.DB, dsn string) *sql.DB {
+	t.Helper()
+	schema := "test_" + strings.ReplaceAll(uuid.New().String()[:13], "-", "")
+	if _, err := admin.Exec(`CREATE SCHEMA ` + schema); err != nil {
+		t.Fatalf("create schema: %v", err)
+	}
+	t.Cleanup(func() {
+		if _, err := admin.Exec(`DROP SCHEMA ` + schema + ` CASCADE`); err != nil {
+			t.Errorf("drop schema %s: %v", schema, err)
+		}
+	})
+
+	db, err := sql.Open("postgres", withSearchPath(dsn, schema+",public"))
+	if err != nil {
+		t.Fatalf("open schema: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	m, err := migrate.Open(db, "../../../migration")
+	if err != nil {
+		t.Fatalf("load migrations: %v", err)
+	}
+	if _, err := m.Up(context.Background(), 0); err != nil {
+		t.Fatalf("migrate %s: %v", schema, err)
+	}
+	return db
+}
+
+// withSearchPath ajoute le paramètre search_path au DSN, sous forme d'URL ou de liste clé=valeur
+func withSearchPath(dsn, searchPath string) string {
+	if u, err := url.Parse(dsn); err == nil && (u.Scheme == "postgres" || u.Scheme == "postgresql") {
+		q := u.Query()
+		q.Set("search_path", searchPath)
+		u.RawQuery = q.Encode()
+		return u.String()
+	}
+	return dsn + " search_path='" + searchPath + "'"
+}
@@ -3,7 +3,9 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"time"
 
+	"github.com/google/uuid"
 	"github.com/openvote/backend/internal/domain/entity"
 	"github.com/openvote/backend/internal/domain/repository"
 )
@@ -76,14 +78,49 @@ func NewAuditLogRepository(db *sql.DB) repository.AuditLogRepository {
 	return &auditLogRepo{db: db}
 }
 
+// Create chaîne l'entrée à la précédente ; le verrou sérialise les écritures concurrentes
+// pour que deux entrées ne référencent jamais la même empreinte précédente
 func (r *auditLogRepo) Create(ctx context.Context, log *entity.AuditLog) error {
-	query := `INSERT INTO audit_logs (admin_id, admin_name, action, target_id, details) VALUES ($1,$2,$3,$4,$5) RETURNING id, created_at`
-	return r.db.QueryRowContext(ctx, query, log.AdminID, log.AdminName, log.Action, log.TargetID, log.Details).Scan(&log.ID, &log.CreatedAt)
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('audit_logs'))`); err != nil {
+		return err
+	}
+	var prevHash string
+	err = tx.QueryRowContext(ctx, `SELECT hash FROM audit_logs WHERE hash IS NOT NULL ORDER BY seq DESC LIMIT 1`).Scan(&prevHash)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	log.ID = uuid.New().String()
+	log.CreatedAt = time.Now().UTC().Truncate(time.Microsecond)
+	log.PrevHash = prevHash
+	log.Hash = log.ComputeHash(prevHash)
+
+	query := `INSERT INTO audit_logs (id, admin_id, admin_name, action, target_id, details, created_at, prev_hash, hash)
+	          VALUES ($1,$2,$3,$4,$5,$6,$7,NULLIF($8,''),$9) RETURNING seq`
+	if err := tx.QueryRowContext(ctx, query, log.ID, log.AdminID, log.AdminName, log.Action, log.TargetID, log.Details, log.CreatedAt, log.PrevHash, log.Hash).Scan(&log.Seq); err != nil {
+		return err
+	}
+	return tx.Commit()
 }
 
 func (r *auditLogRepo) GetAll(ctx context.Context, limit int) ([]entity.AuditLog, error) {
-	query := `SELECT id, admin_id, COALESCE(admin_name,''), action, COALESCE(target_id,''), COALESCE(details,''), created_at FROM audit_logs ORDER BY created_at DESC LIMIT $1`
-	rows, err := r.db.QueryContext(ctx, query, limit)
+	return r.query(ctx, `SELECT `+auditLogColumns+` FROM audit_logs ORDER BY seq DESC LIMIT $1`, limit)
+}
+
+func (r *auditLogRepo) ListChain(ctx context.Context, afterSeq int64, limit int) ([]entity.AuditLog, error) {
+	return r.query(ctx, `SELECT `+auditLogColumns+` FROM audit_logs WHERE seq > $1 ORDER BY seq LIMIT $2`, afterSeq, limit)
+}
+
+const auditLogColumns = `id, admin_id, COALESCE(admin_name,''), action, COALESCE(target_id,''), COALESCE(details,''), created_at, seq, COALESCE(prev_hash,''), COALESCE(hash,'')`
+
+func (r *auditLogRepo) query(ctx context.Context, query string, args ...interface{}) ([]entity.AuditLog, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
 	if err != nil {
 		return nil, err
 	}
@@ -91,12 +128,12 @@ func (r *auditLogRepo) GetAll(ctx context.Context, limit int) ([]entity.AuditLog
 	var results []entity.AuditLog
 	for rows.Next() {
 		var l entity.AuditLog
-		if err := rows.Scan(&l.ID, &l.AdminID, &l.AdminName, &l.Action, &l.TargetID, &l.Details, &l.CreatedAt); err != nil {
+		if err := rows.Scan(&l.ID, &l.AdminID, &l.AdminName, &l.Action, &l.TargetID, &l.Details, &l.CreatedAt, &l.Seq, &l.PrevHash, &l.Hash); err != nil {
 			return nil, err
 		}
 		results = append(results, l)
 	}
-	return results, nil
+	return results, rows.Err()
 }
 
 // ========================================
@@ -126,6 +163,20 @@ func (r *incidentTypeRepo) GetAll(ctx context.Context) ([]entity.IncidentType, e
 	return results, nil
 }
 
+func (r *incidentTypeRepo) FindByCodeOrName(ctx context.Context, value string) (*entity.IncidentType, error) {
+	query := `SELECT id, name, code, COALESCE(description,''), severity, COALESCE(color,'#8b949e'), created_at
+	          FROM incident_types WHERE code = $1 OR name = $1 ORDER BY (code = $1) DESC LIMIT 1`
+	var it entity.IncidentType
+	err := r.db.QueryRowContext(ctx, query, value).Scan(&it.ID, &it.Name, &it.Code, &it.Description, &it.Severity, &it.Color, &it.CreatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &it, nil
+}
+
 func (r *incidentTypeRepo) Create(ctx context.Context, it *entity.IncidentType) error {
 	query := `INSERT INTO incident_types (name, code, description, severity, color) VALUES ($1,$2,$3,$4,$5) RETURNING id, created_at`
 	return r.db.QueryRowContext(ctx, query, it.Name, it.Code, it.Description, it.Severity, it.Color).Scan(&it.ID, &it.CreatedAt)
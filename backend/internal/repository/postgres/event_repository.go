@@ -0,0 +1,285 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+)
+
+// ========================================
+// Incident Event Repository
+// ========================================
+type incidentEventRepo struct{ db *sql.DB }
+
+func NewIncidentEventRepository(db *sql.DB) repository.IncidentEventRepository {
+	return &incidentEventRepo{db: db}
+}
+
+const incidentEventColumns = `e.id, e.incident_type, COALESCE(e.h3_index,''), COALESCE(ST_AsText(e.centroid),''),
+	e.first_reported_at, e.last_reported_at, e.report_count, e.max_severity, e.status, COALESCE(e.merged_into::text,''),
+	ARRAY(SELECT r.id::text FROM reports r WHERE r.event_id = e.id ORDER BY r.created_at),
+	e.created_at, e.updated_at`
+
+func scanIncidentEvent(row interface{ Scan(...interface{}) error }) (*entity.IncidentEvent, error) {
+	var e entity.IncidentEvent
+	err := row.Scan(&e.ID, &e.IncidentType, &e.H3Index, &e.Centroid, &e.FirstReportedAt, &e.LastReportedAt,
+		&e.ReportCount, &e.MaxSeverity, &e.Status, &e.MergedInto, pq.Array(&e.ReportIDs), &e.CreatedAt, &e.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+// refreshIncidentEvent recalcule les agrégats d'un événement à partir de ses signalements
+func refreshIncidentEvent(ctx context.Context, exec interface {
+	ExecContext(context.Context, string, ...interface{}) (sql.Result, error)
+}, eventID string) error {
+	query := `UPDATE incident_events e SET
+	            report_count = agg.cnt,
+	            centroid = agg.centroid,
+	            first_reported_at = agg.first_at,
+	            last_reported_at = agg.last_at,
+	            max_severity = agg.max_sev,
+	            updated_at = NOW()
+	          FROM (
+	            SELECT COUNT(*) AS cnt, ST_Centroid(ST_Collect(r.gps_location)) AS centroid,
+	                   MIN(r.created_at) AS first_at, MAX(r.created_at) AS last_at,
+	                   MAX(r.severity) AS max_sev
+	            FROM reports r
+	            WHERE r.event_id = $1
+	          ) agg
+	          WHERE e.id = $1 AND agg.cnt > 0`
+	_, err := exec.ExecContext(ctx, query, eventID)
+	return err
+}
+
+// rowQuerier : *sql.DB ou *sql.Tx
+type rowQuerier interface {
+	QueryRowContext(context.Context, string, ...interface{}) *sql.Row
+}
+
+func (r *incidentEventRepo) FindCandidate(ctx context.Context, incidentType, h3Index string, lat, lon, radius float64, start, end time.Time) (*entity.IncidentEvent, error) {
+	return findIncidentEventCandidate(ctx, r.db, repository.EventCandidate{
+		IncidentType: incidentType, H3Index: h3Index, Lat: lat, Lon: lon, Radius: radius, Start: start, End: end,
+	})
+}
+
+func findIncidentEventCandidate(ctx context.Context, q rowQuerier, c repository.EventCandidate) (*entity.IncidentEvent, error) {
+	query := `SELECT ` + incidentEventColumns + ` FROM incident_events e
+	          WHERE e.status = 'active' AND e.incident_type = $1
+	          AND (e.h3_index = $2 OR ST_DWithin(e.centroid::geography, ST_SetSRID(ST_MakePoint($3, $4), 4326)::geography, $5))
+	          AND e.last_reported_at >= $6 AND e.first_reported_at <= $7
+	          ORDER BY ST_Distance(e.centroid::geography, ST_SetSRID(ST_MakePoint($3, $4), 4326)::geography)
+	          LIMIT 1`
+	e, err := scanIncidentEvent(q.QueryRowContext(ctx, query, c.IncidentType, c.H3Index, c.Lon, c.Lat, c.Radius, c.Start, c.End))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return e, err
+}
+
+func (r *incidentEventRepo) AssignReport(ctx context.Context, reportID, lockKey string, candidate repository.EventCandidate, fallback *entity.IncidentEvent) (*entity.IncidentEvent, bool, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback()
+
+	// Deux signalements simultanés de la même zone ne doivent pas créer chacun leur événement
+	if _, err := tx.ExecContext(ctx, `SELECT pg_advisory_xact_lock(hashtext('incident_events:' || $1))`, lockKey); err != nil {
+		return nil, false, err
+	}
+
+	// Signalement déjà regroupé (message rejoué) : il reste à son événement
+	var current sql.NullString
+	if err := tx.QueryRowContext(ctx, `SELECT event_id::text FROM reports WHERE id = $1 FOR UPDATE`, reportID).Scan(&current); err != nil {
+		return nil, false, err
+	}
+	if current.Valid {
+		if err := tx.Commit(); err != nil {
+			return nil, false, err
+		}
+		e, err := r.GetByID(ctx, current.String)
+		return e, false, err
+	}
+
+	event, err := findIncidentEventCandidate(ctx, tx, candidate)
+	if err != nil {
+		return nil, false, err
+	}
+	created := event == nil
+	if created {
+		if err := insertIncidentEvent(ctx, tx, fallback); err != nil {
+			return nil, false, err
+		}
+		event = fallback
+	}
+	if err := attachIncidentReports(ctx, tx, event.ID, []string{reportID}); err != nil {
+		return nil, false, err
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, false, err
+	}
+	e, err := r.GetByID(ctx, event.ID)
+	return e, created, err
+}
+
+func (r *incidentEventRepo) Create(ctx context.Context, e *entity.IncidentEvent) error {
+	return insertIncidentEvent(ctx, r.db, e)
+}
+
+func insertIncidentEvent(ctx context.Context, q rowQuerier, e *entity.IncidentEvent) error {
+	if e.Status == "" {
+		e.Status = entity.EventActive
+	}
+	if e.MaxSeverity == 0 {
+		e.MaxSeverity = 1
+	}
+	query := `INSERT INTO incident_events (incident_type, h3_index, centroid, first_reported_at, last_reported_at, report_count, max_severity, status)
+	          VALUES ($1, NULLIF($2, ''), ST_GeomFromText(NULLIF($3, ''), 4326), $4, $5, $6, $7, $8)
+	          RETURNING id, created_at, updated_at`
+	return q.QueryRowContext(ctx, query, e.IncidentType, e.H3Index, e.Centroid, e.FirstReportedAt, e.LastReportedAt, e.ReportCount, e.MaxSeverity, e.Status).
+		Scan(&e.ID, &e.CreatedAt, &e.UpdatedAt)
+}
+
+func (r *incidentEventRepo) AttachReports(ctx context.Context, eventID string, reportIDs []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	if err := attachIncidentReports(ctx, tx, eventID, reportIDs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func attachIncidentReports(ctx context.Context, tx *sql.Tx, eventID string, reportIDs []string) error {
+	// Les anciens événements des signalements déplacés doivent aussi être recalculés
+	rows, err := tx.QueryContext(ctx, `SELECT DISTINCT event_id::text FROM reports WHERE id = ANY($1::uuid[]) AND event_id IS NOT NULL AND event_id <> $2`, pq.Array(reportIDs), eventID)
+	if err != nil {
+		return err
+	}
+	var previous []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			rows.Close()
+			return err
+		}
+		previous = append(previous, id)
+	}
+	rows.Close()
+
+	if _, err := tx.ExecContext(ctx, `UPDATE reports SET event_id = $1 WHERE id = ANY($2::uuid[])`, eventID, pq.Array(reportIDs)); err != nil {
+		return err
+	}
+	for _, id := range append(previous, eventID) {
+		if err := refreshIncidentEvent(ctx, tx, id); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (r *incidentEventRepo) GetByID(ctx context.Context, id string) (*entity.IncidentEvent, error) {
+	query := `SELECT ` + incidentEventColumns + ` FROM incident_events e WHERE e.id = $1`
+	e, err := scanIncidentEvent(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return e, err
+}
+
+func (r *incidentEventRepo) GetAll(ctx context.Context, status string) ([]entity.IncidentEvent, error) {
+	query := `SELECT ` + incidentEventColumns + ` FROM incident_events e`
+	var args []interface{}
+	if status != "" {
+		query += " WHERE e.status = $1"
+		args = append(args, status)
+	}
+	query += " ORDER BY e.max_severity DESC, e.report_count DESC, e.last_reported_at DESC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []entity.IncidentEvent{}
+	for rows.Next() {
+		e, err := scanIncidentEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *e)
+	}
+	return results, rows.Err()
+}
+
+func (r *incidentEventRepo) Merge(ctx context.Context, targetID string, sourceIDs []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `UPDATE incident_events SET status = 'merged', merged_into = $1, report_count = 0, updated_at = NOW()
+	                                    WHERE id = ANY($2::uuid[]) AND id <> $1 AND status = 'active'`, targetID, pq.Array(sourceIDs))
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); int(n) != len(sourceIDs) {
+		return sql.ErrNoRows
+	}
+	// Les événements déjà fusionnés dans une source pointent désormais vers la cible
+	if _, err := tx.ExecContext(ctx, `UPDATE incident_events SET merged_into = $1 WHERE merged_into = ANY($2::uuid[])`, targetID, pq.Array(sourceIDs)); err != nil {
+		return err
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE reports SET event_id = $1 WHERE event_id = ANY($2::uuid[])`, targetID, pq.Array(sourceIDs)); err != nil {
+		return err
+	}
+	if err := refreshIncidentEvent(ctx, tx, targetID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *incidentEventRepo) Split(ctx context.Context, eventID string, reportIDs []string) (*entity.IncidentEvent, error) {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	var newID string
+	query := `INSERT INTO incident_events (incident_type, h3_index, centroid, first_reported_at, last_reported_at, max_severity)
+	          SELECT incident_type, h3_index, centroid, first_reported_at, last_reported_at, max_severity
+	          FROM incident_events WHERE id = $1 AND status = 'active'
+	          RETURNING id`
+	if err := tx.QueryRowContext(ctx, query, eventID).Scan(&newID); err != nil {
+		return nil, err
+	}
+
+	result, err := tx.ExecContext(ctx, `UPDATE reports SET event_id = $1 WHERE id = ANY($2::uuid[]) AND event_id = $3`, newID, pq.Array(reportIDs), eventID)
+	if err != nil {
+		return nil, err
+	}
+	if n, _ := result.RowsAffected(); int(n) != len(reportIDs) {
+		return nil, fmt.Errorf("%d of %d reports do not belong to event %s", len(reportIDs)-int(n), len(reportIDs), eventID)
+	}
+	for _, id := range []string{eventID, newID} {
+		if err := refreshIncidentEvent(ctx, tx, id); err != nil {
+			return nil, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return r.GetByID(ctx, newID)
+}
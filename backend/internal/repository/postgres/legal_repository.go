@@ -18,7 +18,7 @@ func NewLegalRepository(db *sql.DB) repository.LegalRepository {
 
 // Documents
 func (r *legalRepo) GetAllDocuments(ctx context.Context) ([]entity.LegalDocument, error) {
-	query := `SELECT id, title, description, doc_type, version, full_text, file_path, created_at FROM legal_documents ORDER BY title`
+	query := `SELECT id, title, COALESCE(description,''), doc_type, COALESCE(version,''), COALESCE(full_text,''), COALESCE(file_path,''), created_at FROM legal_documents ORDER BY title`
 	rows, err := r.db.QueryContext(ctx, query)
 	if err != nil {
 		return nil, err
@@ -307,3 +307,28 @@ func (r *legalRepo) GetAnalysisByReport(ctx context.Context, reportID string) (*
 	}
 	return &a, nil
 }
+
+func (r *legalRepo) SaveEventAnalysis(ctx context.Context, analysis *entity.LegalAnalysis) error {
+	query := `INSERT INTO legal_analyses (event_id, summary, recommendation, severity_level, raw_response, llm_model)
+	          VALUES ($1, $2, $3, $4, $5, $6)
+	          ON CONFLICT (event_id) DO UPDATE SET
+	            summary = EXCLUDED.summary,
+	            recommendation = EXCLUDED.recommendation,
+	            severity_level = EXCLUDED.severity_level,
+	            raw_response = EXCLUDED.raw_response,
+	            llm_model = EXCLUDED.llm_model,
+	            created_at = NOW()
+	          RETURNING id, created_at`
+	return r.db.QueryRowContext(ctx, query, analysis.EventID, analysis.Summary, analysis.Recommendation, analysis.SeverityLevel, analysis.RawResponse, analysis.LLMModel).Scan(&analysis.ID, &analysis.CreatedAt)
+}
+
+func (r *legalRepo) GetAnalysisByEvent(ctx context.Context, eventID string) (*entity.LegalAnalysis, error) {
+	query := `SELECT id, event_id, summary, recommendation, severity_level, raw_response, llm_model, created_at
+	          FROM legal_analyses WHERE event_id = $1`
+	var a entity.LegalAnalysis
+	err := r.db.QueryRowContext(ctx, query, eventID).Scan(&a.ID, &a.EventID, &a.Summary, &a.Recommendation, &a.SeverityLevel, &a.RawResponse, &a.LLMModel, &a.CreatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
@@ -0,0 +1,198 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+)
+
+type notificationRepo struct{ db *sql.DB }
+
+func NewNotificationRepository(db *sql.DB) repository.NotificationRepository {
+	return &notificationRepo{db: db}
+}
+
+func (r *notificationRepo) GetPreferences(ctx context.Context, userID string) (*entity.NotificationPreferences, error) {
+	query := `SELECT user_id, locale, COALESCE(email,''), COALESCE(phone,''), channels, muted_kinds,
+	            COALESCE(quiet_hours_start,''), COALESCE(quiet_hours_end,''), timezone, updated_at
+	          FROM notification_preferences WHERE user_id = $1`
+	var p entity.NotificationPreferences
+	err := r.db.QueryRowContext(ctx, query, userID).Scan(&p.UserID, &p.Locale, &p.Email, &p.Phone, pq.Array(&p.Channels), pq.Array(&p.MutedKinds),
+		&p.QuietHoursStart, &p.QuietHoursEnd, &p.Timezone, &p.UpdatedAt)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
+
+func (r *notificationRepo) SavePreferences(ctx context.Context, p *entity.NotificationPreferences) error {
+	query := `INSERT INTO notification_preferences (user_id, locale, email, phone, channels, muted_kinds, quiet_hours_start, quiet_hours_end, timezone)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+	          ON CONFLICT (user_id) DO UPDATE SET locale = EXCLUDED.locale, email = EXCLUDED.email, phone = EXCLUDED.phone,
+	            channels = EXCLUDED.channels, muted_kinds = EXCLUDED.muted_kinds, quiet_hours_start = EXCLUDED.quiet_hours_start,
+	            quiet_hours_end = EXCLUDED.quiet_hours_end, timezone = EXCLUDED.timezone, updated_at = NOW()
+	          RETURNING updated_at`
+	return r.db.QueryRowContext(ctx, query, p.UserID, p.Locale, p.Email, p.Phone, pq.Array(nonNilStrings(p.Channels)), pq.Array(nonNilStrings(p.MutedKinds)),
+		p.QuietHoursStart, p.QuietHoursEnd, p.Timezone).Scan(&p.UpdatedAt)
+}
+
+func (r *notificationRepo) CreateInboxItem(ctx context.Context, n *entity.Notification) error {
+	query := `INSERT INTO notifications (user_id, kind, title, body) VALUES ($1, $2, $3, $4) RETURNING id, created_at`
+	return r.db.QueryRowContext(ctx, query, n.UserID, n.Kind, n.Title, n.Body).Scan(&n.ID, &n.CreatedAt)
+}
+
+func (r *notificationRepo) ListInbox(ctx context.Context, userID string, unreadOnly bool, limit int) ([]entity.Notification, error) {
+	query := `SELECT id, user_id, kind, title, body, read_at, created_at FROM notifications WHERE user_id = $1`
+	if unreadOnly {
+		query += ` AND read_at IS NULL`
+	}
+	query += fmt.Sprintf(` ORDER BY created_at DESC LIMIT %d`, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []entity.Notification{}
+	for rows.Next() {
+		var n entity.Notification
+		var readAt sql.NullTime
+		if err := rows.Scan(&n.ID, &n.UserID, &n.Kind, &n.Title, &n.Body, &readAt, &n.CreatedAt); err != nil {
+			return nil, err
+		}
+		if readAt.Valid {
+			n.ReadAt = &readAt.Time
+		}
+		items = append(items, n)
+	}
+	return items, rows.Err()
+}
+
+func (r *notificationRepo) CountUnread(ctx context.Context, userID string) (int, error) {
+	var count int
+	err := r.db.QueryRowContext(ctx, `SELECT COUNT(*) FROM notifications WHERE user_id = $1 AND read_at IS NULL`, userID).Scan(&count)
+	return count, err
+}
+
+func (r *notificationRepo) MarkRead(ctx context.Context, userID, id string) (int, error) {
+	query := `UPDATE notifications SET read_at = NOW() WHERE user_id = $1 AND read_at IS NULL`
+	args := []interface{}{userID}
+	if id != "" {
+		query += ` AND id = $2`
+		args = append(args, id)
+	}
+	res, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		return 0, err
+	}
+	n, err := res.RowsAffected()
+	return int(n), err
+}
+
+const notificationDeliveryColumns = `id, user_id, channel, kind, dedup_key, recipient, subject, body, status, attempts, COALESCE(last_error,''), next_attempt_at, created_at, sent_at`
+
+func scanNotificationDelivery(row interface{ Scan(...interface{}) error }) (*entity.NotificationDelivery, error) {
+	var d entity.NotificationDelivery
+	var sentAt sql.NullTime
+	err := row.Scan(&d.ID, &d.UserID, &d.Channel, &d.Kind, &d.DedupKey, &d.Recipient, &d.Subject, &d.Body, &d.Status, &d.Attempts, &d.LastError,
+		&d.NextAttemptAt, &d.CreatedAt, &sentAt)
+	if err != nil {
+		return nil, err
+	}
+	if sentAt.Valid {
+		d.SentAt = &sentAt.Time
+	}
+	return &d, nil
+}
+
+func (r *notificationRepo) CreateDelivery(ctx context.Context, d *entity.NotificationDelivery) (bool, error) {
+	query := `INSERT INTO notification_deliveries (user_id, channel, kind, dedup_key, recipient, subject, body, next_attempt_at)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	          ON CONFLICT (user_id, channel, dedup_key) DO NOTHING
+	          RETURNING id, status, created_at`
+	nextAttempt := d.NextAttemptAt
+	if nextAttempt.IsZero() {
+		nextAttempt = time.Now()
+	}
+	err := r.db.QueryRowContext(ctx, query, d.UserID, d.Channel, d.Kind, d.DedupKey, d.Recipient, d.Subject, d.Body, nextAttempt).
+		Scan(&d.ID, &d.Status, &d.CreatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	d.NextAttemptAt = nextAttempt
+	return err == nil, err
+}
+
+func (r *notificationRepo) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.NotificationDelivery, error) {
+	query := `UPDATE notification_deliveries SET next_attempt_at = NOW() + $2::interval, attempts = attempts + 1
+	          WHERE id IN (
+	            SELECT id FROM notification_deliveries
+	            WHERE status = 'pending' AND next_attempt_at <= NOW()
+	            ORDER BY next_attempt_at
+	            LIMIT $1
+	            FOR UPDATE SKIP LOCKED)
+	          RETURNING ` + notificationDeliveryColumns
+	return r.queryDeliveries(ctx, query, limit, fmt.Sprintf("%d milliseconds", lease.Milliseconds()))
+}
+
+func (r *notificationRepo) MarkSent(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE notification_deliveries SET status = 'sent', last_error = '', sent_at = NOW() WHERE id = $1`, id)
+	return err
+}
+
+func (r *notificationRepo) MarkFailed(ctx context.Context, id, cause string, retryAt *time.Time) error {
+	if retryAt == nil {
+		_, err := r.db.ExecContext(ctx, `UPDATE notification_deliveries SET status = 'failed', last_error = $1 WHERE id = $2`, cause, id)
+		return err
+	}
+	_, err := r.db.ExecContext(ctx, `UPDATE notification_deliveries SET last_error = $1, next_attempt_at = $2 WHERE id = $3`, cause, *retryAt, id)
+	return err
+}
+
+func (r *notificationRepo) ListDeliveries(ctx context.Context, status string, limit int) ([]entity.NotificationDelivery, error) {
+	query := `SELECT ` + notificationDeliveryColumns + ` FROM notification_deliveries`
+	var args []interface{}
+	if status != "" {
+		query += ` WHERE status = $1`
+		args = append(args, status)
+	}
+	query += fmt.Sprintf(` ORDER BY created_at DESC LIMIT %d`, limit)
+	return r.queryDeliveries(ctx, query, args...)
+}
+
+func (r *notificationRepo) queryDeliveries(ctx context.Context, query string, args ...interface{}) ([]entity.NotificationDelivery, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []entity.NotificationDelivery{}
+	for rows.Next() {
+		d, err := scanNotificationDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *d)
+	}
+	return deliveries, rows.Err()
+}
+
+func (r *notificationRepo) ResetDelivery(ctx context.Context, id string) (bool, error) {
+	query := `UPDATE notification_deliveries SET status = 'pending', attempts = 0, last_error = '', next_attempt_at = NOW(), sent_at = NULL WHERE id = $1`
+	res, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
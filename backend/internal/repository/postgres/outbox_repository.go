@@ -0,0 +1,101 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+)
+
+// ========================================
+// Outbox Repository
+// ========================================
+type outboxRepo struct{ db *sql.DB }
+
+func NewOutboxRepository(db *sql.DB) repository.OutboxRepository {
+	return &outboxRepo{db: db}
+}
+
+// insertOutbox écrit un message dans l'outbox au sein de la transaction appelante
+func insertOutbox(ctx context.Context, tx *sql.Tx, msg *entity.OutboxMessage) error {
+	headers := []byte(`{}`)
+	if len(msg.Headers) > 0 {
+		var err error
+		if headers, err = json.Marshal(msg.Headers); err != nil {
+			return err
+		}
+	}
+	query := `INSERT INTO outbox (queue, topic, payload, headers) VALUES ($1, $2, $3, $4) RETURNING id, created_at`
+	return tx.QueryRowContext(ctx, query, msg.Queue, msg.Topic, []byte(msg.Payload), headers).Scan(&msg.ID, &msg.CreatedAt)
+}
+
+func (r *outboxRepo) Enqueue(ctx context.Context, messages ...*entity.OutboxMessage) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	for _, msg := range messages {
+		if err := insertOutbox(ctx, tx, msg); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *outboxRepo) Claim(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxMessage, error) {
+	// Le bail repousse available_at : si le relais meurt, le message redevient publiable à son expiration
+	query := `UPDATE outbox SET available_at = NOW() + $2::interval, attempts = attempts + 1
+	          WHERE id IN (
+	            SELECT id FROM outbox
+	            WHERE status = 'pending' AND available_at <= NOW()
+	            ORDER BY created_at
+	            LIMIT $1
+	            FOR UPDATE SKIP LOCKED)
+	          RETURNING id, queue, topic, payload, headers, attempts, COALESCE(last_error, ''), created_at`
+	rows, err := r.db.QueryContext(ctx, query, limit, fmt.Sprintf("%d milliseconds", lease.Milliseconds()))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []entity.OutboxMessage
+	for rows.Next() {
+		var m entity.OutboxMessage
+		var payload, headers []byte
+		if err := rows.Scan(&m.ID, &m.Queue, &m.Topic, &payload, &headers, &m.Attempts, &m.LastError, &m.CreatedAt); err != nil {
+			return nil, err
+		}
+		m.Payload = payload
+		if err := json.Unmarshal(headers, &m.Headers); err != nil {
+			return nil, fmt.Errorf("invalid headers for outbox message %s: %w", m.ID, err)
+		}
+		messages = append(messages, m)
+	}
+	return messages, rows.Err()
+}
+
+func (r *outboxRepo) MarkSent(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `UPDATE outbox SET status = 'sent', sent_at = NOW(), last_error = '' WHERE id = $1`, id)
+	return err
+}
+
+func (r *outboxRepo) MarkFailed(ctx context.Context, id string, cause string, retryAfter time.Duration) error {
+	query := `UPDATE outbox SET last_error = $1, available_at = NOW() + $2::interval WHERE id = $3`
+	_, err := r.db.ExecContext(ctx, query, cause, fmt.Sprintf("%d milliseconds", retryAfter.Milliseconds()), id)
+	return err
+}
+
+func (r *outboxRepo) PurgeSent(ctx context.Context, olderThan time.Duration) (int64, error) {
+	query := `DELETE FROM outbox WHERE status = 'sent' AND sent_at < NOW() - $1::interval`
+	result, err := r.db.ExecContext(ctx, query, fmt.Sprintf("%d milliseconds", olderThan.Milliseconds()))
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,62 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+)
+
+type pollingStationRepo struct {
+	db *sql.DB
+}
+
+func NewPollingStationRepository(db *sql.DB) repository.PollingStationRepository {
+	return &pollingStationRepo{db: db}
+}
+
+const pollingStationColumns = `id, code, name, department_id, COALESCE(address, ''), latitude, longitude, registered_voters, created_at, updated_at`
+
+func (r *pollingStationRepo) GetAll(ctx context.Context) ([]entity.PollingStation, error) {
+	return r.query(ctx, `SELECT `+pollingStationColumns+` FROM polling_stations ORDER BY code`)
+}
+
+func (r *pollingStationRepo) GetByDepartment(ctx context.Context, departmentID string) ([]entity.PollingStation, error) {
+	return r.query(ctx, `SELECT `+pollingStationColumns+` FROM polling_stations WHERE department_id = $1 ORDER BY code`, departmentID)
+}
+
+func (r *pollingStationRepo) Upsert(ctx context.Context, ps *entity.PollingStation) (bool, error) {
+	// xmax = 0 : la ligne vient d'être insérée (pas de conflit)
+	query := `INSERT INTO polling_stations (code, name, department_id, address, latitude, longitude, registered_voters)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7)
+	          ON CONFLICT (code) DO UPDATE SET name = EXCLUDED.name, department_id = EXCLUDED.department_id, address = EXCLUDED.address,
+	              latitude = EXCLUDED.latitude, longitude = EXCLUDED.longitude, registered_voters = EXCLUDED.registered_voters, updated_at = NOW()
+	          RETURNING id, created_at, updated_at, (xmax = 0)`
+	var created bool
+	err := r.db.QueryRowContext(ctx, query, ps.Code, ps.Name, ps.DepartmentID, ps.Address, ps.Latitude, ps.Longitude, ps.RegisteredVoters).
+		Scan(&ps.ID, &ps.CreatedAt, &ps.UpdatedAt, &created)
+	return created, err
+}
+
+func (r *pollingStationRepo) query(ctx context.Context, query string, args ...interface{}) ([]entity.PollingStation, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var stations []entity.PollingStation
+	for rows.Next() {
+		var ps entity.PollingStation
+		var lat, lon sql.NullFloat64
+		if err := rows.Scan(&ps.ID, &ps.Code, &ps.Name, &ps.DepartmentID, &ps.Address, &lat, &lon, &ps.RegisteredVoters, &ps.CreatedAt, &ps.UpdatedAt); err != nil {
+			return nil, err
+		}
+		if lat.Valid && lon.Valid {
+			ps.Latitude, ps.Longitude = &lat.Float64, &lon.Float64
+		}
+		stations = append(stations, ps)
+	}
+	return stations, rows.Err()
+}
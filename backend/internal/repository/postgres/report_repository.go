@@ -18,10 +18,20 @@ func NewReportRepository(db *sql.DB) repository.ReportRepository {
 }
 
 func (r *reportRepo) Create(ctx context.Context, report *entity.Report) error {
+	return r.CreateWithOutbox(ctx, report)
+}
+
+func (r *reportRepo) CreateWithOutbox(ctx context.Context, report *entity.Report, messages ...*entity.OutboxMessage) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
 	// Note: on attend que report.GPSLocation soit formaté WKT "POINT(lon lat)"
-	query := `INSERT INTO reports (id, observer_id, incident_type, description, gps_location, h3_index, status, proof_url, created_at) 
-	          VALUES ($1, $2, $3, $4, ST_GeomFromText($5, 4326), $6, $7, $8, $9)`
-	_, err := r.db.ExecContext(ctx, query,
+	query := `INSERT INTO reports (id, observer_id, incident_type, description, gps_location, h3_index, status, proof_url, created_at, source_fingerprint, device_fingerprint, severity) 
+	          VALUES ($1, $2, $3, $4, ST_GeomFromText($5, 4326), $6, $7, $8, $9, NULLIF($10, ''), NULLIF($11, ''), $12)`
+	_, err = tx.ExecContext(ctx, query,
 		report.ID,
 		report.ObserverID,
 		report.IncidentType,
@@ -31,13 +41,26 @@ func (r *reportRepo) Create(ctx context.Context, report *entity.Report) error {
 		report.Status,
 		report.ProofURL,
 		report.CreatedAt,
+		report.SourceFingerprint,
+		report.DeviceFingerprint,
+		report.Severity,
 	)
-	return err
+	if err != nil {
+		return err
+	}
+
+	// Les messages de publication n'existent que si le signalement est bien enregistré
+	for _, msg := range messages {
+		if err := insertOutbox(ctx, tx, msg); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
 }
 
 func (r *reportRepo) GetAll(ctx context.Context, status string) ([]entity.Report, error) {
 	// On récupère la géométrie au format Text (WKT) pour le mapper dans le struct
-	query := `SELECT id, observer_id, incident_type, COALESCE(description, '') as description, ST_AsText(gps_location) as gps_location, h3_index, status, COALESCE(proof_url, '') as proof_url, created_at FROM reports`
+	query := `SELECT id, observer_id, incident_type, COALESCE(description, '') as description, ST_AsText(gps_location) as gps_location, h3_index, status, COALESCE(proof_url, '') as proof_url, severity, created_at FROM reports`
 
 	var args []interface{}
 	if status != "" {
@@ -66,6 +89,7 @@ func (r *reportRepo) GetAll(ctx context.Context, status string) ([]entity.Report
 			&report.H3Index,
 			&report.Status,
 			&report.ProofURL,
+			&report.Severity,
 			&report.CreatedAt,
 		)
 		if err != nil {
@@ -77,7 +101,10 @@ func (r *reportRepo) GetAll(ctx context.Context, status string) ([]entity.Report
 }
 
 func (r *reportRepo) GetByID(ctx context.Context, id string) (*entity.Report, error) {
-	query := `SELECT id, observer_id, incident_type, COALESCE(description, '') as description, ST_AsText(gps_location) as gps_location, h3_index, status, COALESCE(proof_url, '') as proof_url, created_at FROM reports WHERE id = $1`
+	query := `SELECT r.id, r.observer_id, r.incident_type, COALESCE(r.description, '') as description, ST_AsText(r.gps_location) as gps_location, r.h3_index, r.status, COALESCE(r.proof_url, '') as proof_url, r.severity, r.created_at, COALESCE(u.region_id, '')
+	          FROM reports r
+	          LEFT JOIN users u ON r.observer_id = u.id
+	          WHERE r.id = $1`
 	report := &entity.Report{}
 	err := r.db.QueryRowContext(ctx, query, id).Scan(
 		&report.ID,
@@ -88,7 +115,9 @@ func (r *reportRepo) GetByID(ctx context.Context, id string) (*entity.Report, er
 		&report.H3Index,
 		&report.Status,
 		&report.ProofURL,
+		&report.Severity,
 		&report.CreatedAt,
+		&report.RegionID,
 	)
 	if err == sql.ErrNoRows {
 		return nil, nil
@@ -99,7 +128,8 @@ func (r *reportRepo) GetByID(ctx context.Context, id string) (*entity.Report, er
 func (r *reportRepo) FindNearbyWithRole(ctx context.Context, h3Index string, lat, lon, radius float64, start, end time.Time) ([]entity.Report, error) {
 	// Sélection avec jointure pour avoir le rôle
 	query := `
-		SELECT r.id, r.observer_id, r.incident_type, COALESCE(r.description, '') as description, ST_AsText(r.gps_location) as gps_location, r.h3_index, r.status, COALESCE(r.proof_url, '') as proof_url, r.created_at, u.role
+		SELECT r.id, r.observer_id, r.incident_type, COALESCE(r.description, '') as description, ST_AsText(r.gps_location) as gps_location, r.h3_index, r.status, COALESCE(r.proof_url, '') as proof_url, r.severity, r.created_at, u.role,
+		       COALESCE(r.source_fingerprint, ''), COALESCE(r.device_fingerprint, ''), COALESCE(u.activation_token_hash, '')
 		FROM reports r
 		JOIN users u ON r.observer_id = u.id
 		WHERE (r.h3_index = $1 OR ST_DWithin(r.gps_location::geography, ST_SetSRID(ST_MakePoint($2, $3), 4326)::geography, $4))
@@ -124,8 +154,12 @@ func (r *reportRepo) FindNearbyWithRole(ctx context.Context, h3Index string, lat
 			&report.H3Index,
 			&report.Status,
 			&report.ProofURL,
+			&report.Severity,
 			&report.CreatedAt,
 			&roleStr,
+			&report.SourceFingerprint,
+			&report.DeviceFingerprint,
+			&report.AuthorTokenHash,
 		)
 		if err != nil {
 			return nil, err
@@ -137,7 +171,70 @@ func (r *reportRepo) FindNearbyWithRole(ctx context.Context, h3Index string, lat
 }
 
 func (r *reportRepo) UpdateStatus(ctx context.Context, id string, status entity.ReportStatus) error {
-	query := `UPDATE reports SET status = $1 WHERE id = $2`
-	_, err := r.db.ExecContext(ctx, query, status, id)
-	return err
+	return r.UpdateStatusWithOutbox(ctx, id, status)
+}
+
+func (r *reportRepo) UpdateStatusWithOutbox(ctx context.Context, id string, status entity.ReportStatus, messages ...*entity.OutboxMessage) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	res, err := tx.ExecContext(ctx, `UPDATE reports SET status = $1 WHERE id = $2`, status, id)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return err
+	}
+	for _, msg := range messages {
+		if err := insertOutbox(ctx, tx, msg); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *reportRepo) GetReviewQueue(ctx context.Context, limit int) ([]entity.ReviewQueueItem, error) {
+	// Corroboration : autres signalements non rejetés rattachés au même événement consolidé
+	query := `
+		SELECT r.id, r.observer_id, r.incident_type, COALESCE(r.description, ''), ST_AsText(r.gps_location), r.h3_index, r.status, COALESCE(r.proof_url, ''), r.severity, r.created_at,
+		       COALESCE(r.event_id::text, ''),
+		       COALESCE((SELECT COUNT(*) FROM reports o WHERE o.event_id = r.event_id AND o.id <> r.id AND o.status <> 'rejected'), 0) AS corroboration,
+		       EXTRACT(EPOCH FROM (NOW() - r.created_at)) / 60 AS age_minutes
+		FROM reports r
+		WHERE r.status = 'pending'
+		ORDER BY r.severity DESC, corroboration DESC, r.created_at ASC
+		LIMIT $1`
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	items := []entity.ReviewQueueItem{}
+	for rows.Next() {
+		var item entity.ReviewQueueItem
+		err := rows.Scan(
+			&item.ID,
+			&item.ObserverID,
+			&item.IncidentType,
+			&item.Description,
+			&item.GPSLocation,
+			&item.H3Index,
+			&item.Status,
+			&item.ProofURL,
+			&item.Severity,
+			&item.CreatedAt,
+			&item.EventID,
+			&item.Corroboration,
+			&item.AgeMinutes,
+		)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, item)
+	}
+	return items, rows.Err()
 }
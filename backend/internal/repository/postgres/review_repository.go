@@ -0,0 +1,294 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/lib/pq"
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+)
+
+// ========================================
+// Suspicious Cluster Repository (Sybil)
+// ========================================
+type suspiciousClusterRepo struct{ db *sql.DB }
+
+func NewSuspiciousClusterRepository(db *sql.DB) repository.SuspiciousClusterRepository {
+	return &suspiciousClusterRepo{db: db}
+}
+
+const suspiciousClusterColumns = `id, report_id, related_report_ids::text[], signals, score, status, COALESCE(reviewed_by,''), COALESCE(review_note,''), created_at, reviewed_at`
+
+func scanSuspiciousCluster(row interface{ Scan(...interface{}) error }) (*entity.SuspiciousCluster, error) {
+	var c entity.SuspiciousCluster
+	var reviewedAt sql.NullTime
+	err := row.Scan(&c.ID, &c.ReportID, pq.Array(&c.RelatedReportIDs), pq.Array(&c.Signals), &c.Score, &c.Status, &c.ReviewedBy, &c.ReviewNote, &c.CreatedAt, &reviewedAt)
+	if err != nil {
+		return nil, err
+	}
+	if reviewedAt.Valid {
+		c.ReviewedAt = &reviewedAt.Time
+	}
+	return &c, nil
+}
+
+func (r *suspiciousClusterRepo) Upsert(ctx context.Context, c *entity.SuspiciousCluster) error {
+	// Un cluster déjà revu garde son statut : seule la preuve est rafraîchie. De nouveaux
+	// membres n'ont pas été revus : la décision est alors effacée et le cluster rouvert.
+	query := `INSERT INTO suspicious_clusters (report_id, related_report_ids, signals, score, status)
+	          VALUES ($1, $2::uuid[], $3, $4, $5)
+	          ON CONFLICT (report_id) DO UPDATE SET
+	            related_report_ids = EXCLUDED.related_report_ids,
+	            signals = EXCLUDED.signals,
+	            score = EXCLUDED.score,
+	            status = CASE WHEN suspicious_clusters.related_report_ids @> EXCLUDED.related_report_ids
+	                          THEN suspicious_clusters.status ELSE 'open' END,
+	            reviewed_by = CASE WHEN suspicious_clusters.related_report_ids @> EXCLUDED.related_report_ids
+	                               THEN suspicious_clusters.reviewed_by END,
+	            review_note = CASE WHEN suspicious_clusters.related_report_ids @> EXCLUDED.related_report_ids
+	                               THEN suspicious_clusters.review_note END,
+	            reviewed_at = CASE WHEN suspicious_clusters.related_report_ids @> EXCLUDED.related_report_ids
+	                               THEN suspicious_clusters.reviewed_at END
+	          RETURNING id, status, created_at`
+	if c.Status == "" {
+		c.Status = entity.ClusterOpen
+	}
+	return r.db.QueryRowContext(ctx, query, c.ReportID, pq.Array(c.RelatedReportIDs), pq.Array(c.Signals), c.Score, c.Status).Scan(&c.ID, &c.Status, &c.CreatedAt)
+}
+
+func (r *suspiciousClusterRepo) GetAll(ctx context.Context, status string) ([]entity.SuspiciousCluster, error) {
+	query := `SELECT ` + suspiciousClusterColumns + ` FROM suspicious_clusters`
+	var args []interface{}
+	if status != "" {
+		query += " WHERE status = $1"
+		args = append(args, status)
+	}
+	query += " ORDER BY created_at DESC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []entity.SuspiciousCluster{}
+	for rows.Next() {
+		c, err := scanSuspiciousCluster(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *c)
+	}
+	return results, rows.Err()
+}
+
+func (r *suspiciousClusterRepo) GetByID(ctx context.Context, id string) (*entity.SuspiciousCluster, error) {
+	query := `SELECT ` + suspiciousClusterColumns + ` FROM suspicious_clusters WHERE id = $1`
+	c, err := scanSuspiciousCluster(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return c, err
+}
+
+func (r *suspiciousClusterRepo) FindByReport(ctx context.Context, reportID string) ([]entity.SuspiciousCluster, error) {
+	query := `SELECT ` + suspiciousClusterColumns + ` FROM suspicious_clusters
+	          WHERE report_id = $1 OR $1 = ANY(related_report_ids) ORDER BY created_at DESC`
+	rows, err := r.db.QueryContext(ctx, query, reportID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []entity.SuspiciousCluster{}
+	for rows.Next() {
+		c, err := scanSuspiciousCluster(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *c)
+	}
+	return results, rows.Err()
+}
+
+func (r *suspiciousClusterRepo) UpdateStatus(ctx context.Context, id string, status entity.ClusterReviewStatus, reviewerID, note string) error {
+	query := `UPDATE suspicious_clusters SET status = $1, reviewed_by = $2, review_note = $3, reviewed_at = NOW() WHERE id = $4`
+	result, err := r.db.ExecContext(ctx, query, status, reviewerID, note, id)
+	if err != nil {
+		return err
+	}
+	n, _ := result.RowsAffected()
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// ========================================
+// Conflict Repository
+// ========================================
+type conflictRepo struct{ db *sql.DB }
+
+func NewConflictRepository(db *sql.DB) repository.ConflictRepository {
+	return &conflictRepo{db: db}
+}
+
+const conflictColumns = `c.id, COALESCE(c.h3_index,''), c.incident_types,
+	ARRAY(SELECT cr.report_id::text FROM conflict_reports cr WHERE cr.conflict_id = c.id ORDER BY cr.report_id),
+	c.status, COALESCE(c.assignee_id::text,''), COALESCE(c.resolution_note,''), COALESCE(c.resolved_by,''), c.created_at, c.resolved_at`
+
+func scanConflict(row interface{ Scan(...interface{}) error }) (*entity.Conflict, error) {
+	var c entity.Conflict
+	var resolvedAt sql.NullTime
+	err := row.Scan(&c.ID, &c.H3Index, pq.Array(&c.IncidentTypes), pq.Array(&c.ReportIDs), &c.Status, &c.AssigneeID, &c.ResolutionNote, &c.ResolvedBy, &c.CreatedAt, &resolvedAt)
+	if err != nil {
+		return nil, err
+	}
+	if resolvedAt.Valid {
+		c.ResolvedAt = &resolvedAt.Time
+	}
+	return &c, nil
+}
+
+func (r *conflictRepo) Create(ctx context.Context, c *entity.Conflict) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if c.Status == "" {
+		c.Status = entity.ConflictOpen
+	}
+	query := `INSERT INTO conflicts (h3_index, incident_types, status) VALUES ($1, $2, $3) RETURNING id, created_at`
+	if err := tx.QueryRowContext(ctx, query, c.H3Index, pq.Array(c.IncidentTypes), c.Status).Scan(&c.ID, &c.CreatedAt); err != nil {
+		return err
+	}
+	if err := linkConflictReports(ctx, tx, c.ID, c.ReportIDs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func linkConflictReports(ctx context.Context, tx *sql.Tx, conflictID string, reportIDs []string) error {
+	query := `INSERT INTO conflict_reports (conflict_id, report_id) SELECT $1, unnest($2::uuid[]) ON CONFLICT DO NOTHING`
+	_, err := tx.ExecContext(ctx, query, conflictID, pq.Array(reportIDs))
+	return err
+}
+
+func (r *conflictRepo) FindOpenByReports(ctx context.Context, reportIDs []string) (*entity.Conflict, error) {
+	query := `SELECT ` + conflictColumns + ` FROM conflicts c
+	          WHERE c.status = 'open'
+	          AND EXISTS (SELECT 1 FROM conflict_reports cr WHERE cr.conflict_id = c.id AND cr.report_id = ANY($1::uuid[]))
+	          ORDER BY c.created_at
+	          LIMIT 1`
+	c, err := scanConflict(r.db.QueryRowContext(ctx, query, pq.Array(reportIDs)))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return c, err
+}
+
+func (r *conflictRepo) FindResolvedCovering(ctx context.Context, reportIDs []string, incidentTypes []string) (*entity.Conflict, error) {
+	query := `SELECT ` + conflictColumns + ` FROM conflicts c
+	          WHERE c.status = 'resolved'
+	          AND c.incident_types @> $2::text[]
+	          AND NOT EXISTS (
+	              SELECT 1 FROM unnest($1::uuid[]) AS wanted(id)
+	              WHERE NOT EXISTS (SELECT 1 FROM conflict_reports cr WHERE cr.conflict_id = c.id AND cr.report_id = wanted.id))
+	          ORDER BY c.resolved_at DESC
+	          LIMIT 1`
+	c, err := scanConflict(r.db.QueryRowContext(ctx, query, pq.Array(reportIDs), pq.Array(incidentTypes)))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return c, err
+}
+
+func (r *conflictRepo) AddReports(ctx context.Context, conflictID string, reportIDs []string, incidentTypes []string) error {
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	// Union des types déjà connus et des nouveaux
+	query := `UPDATE conflicts SET incident_types = ARRAY(SELECT DISTINCT unnest(incident_types || $1::text[]) ORDER BY 1) WHERE id = $2`
+	if _, err := tx.ExecContext(ctx, query, pq.Array(incidentTypes), conflictID); err != nil {
+		return err
+	}
+	if err := linkConflictReports(ctx, tx, conflictID, reportIDs); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *conflictRepo) GetByID(ctx context.Context, id string) (*entity.Conflict, error) {
+	query := `SELECT ` + conflictColumns + ` FROM conflicts c WHERE c.id = $1`
+	c, err := scanConflict(r.db.QueryRowContext(ctx, query, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return c, err
+}
+
+func (r *conflictRepo) GetAll(ctx context.Context, status string) ([]entity.Conflict, error) {
+	query := `SELECT ` + conflictColumns + ` FROM conflicts c`
+	var args []interface{}
+	if status != "" {
+		query += " WHERE c.status = $1"
+		args = append(args, status)
+	}
+	query += " ORDER BY c.created_at DESC"
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	results := []entity.Conflict{}
+	for rows.Next() {
+		c, err := scanConflict(rows)
+		if err != nil {
+			return nil, err
+		}
+		results = append(results, *c)
+	}
+	return results, rows.Err()
+}
+
+func (r *conflictRepo) HasOpenConflict(ctx context.Context, reportID string) (bool, error) {
+	query := `SELECT EXISTS (
+	            SELECT 1 FROM conflict_reports cr JOIN conflicts c ON c.id = cr.conflict_id
+	            WHERE cr.report_id = $1 AND c.status = 'open')`
+	var exists bool
+	err := r.db.QueryRowContext(ctx, query, reportID).Scan(&exists)
+	return exists, err
+}
+
+func (r *conflictRepo) Assign(ctx context.Context, id, assigneeID string) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE conflicts SET assignee_id = $1 WHERE id = $2`, assigneeID, id)
+	if err != nil {
+		return err
+	}
+	n, _ := result.RowsAffected()
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *conflictRepo) Resolve(ctx context.Context, id, resolverID, note string) error {
+	query := `UPDATE conflicts SET status = 'resolved', resolved_by = $1, resolution_note = $2, resolved_at = NOW() WHERE id = $3 AND status = 'open'`
+	result, err := r.db.ExecContext(ctx, query, resolverID, note, id)
+	if err != nil {
+		return err
+	}
+	n, _ := result.RowsAffected()
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -16,8 +16,8 @@ func NewUserRepository(db *sql.DB) repository.UserRepository {
 }
 
 func (r *userRepo) Create(ctx context.Context, user *entity.User) error {
-	query := `INSERT INTO users (id, username, role, password_hash, region_id, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
-	_, err := r.db.ExecContext(ctx, query, user.ID, user.Username, user.Role, user.PasswordHash, user.RegionID, user.CreatedAt, user.UpdatedAt)
+	query := `INSERT INTO users (id, username, role, password_hash, region_id, created_at, updated_at, activation_token_hash) VALUES ($1, $2, $3, $4, $5, $6, $7, NULLIF($8, ''))`
+	_, err := r.db.ExecContext(ctx, query, user.ID, user.Username, user.Role, user.PasswordHash, user.RegionID, user.CreatedAt, user.UpdatedAt, user.ActivationTokenHash)
 	return err
 }
 
@@ -83,6 +83,18 @@ func (r *userRepo) UpdateLastLogin(ctx context.Context, id string) error {
 	return err
 }
 
+func (r *userRepo) UpdatePasswordHash(ctx context.Context, id, passwordHash string) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE users SET password_hash = $1, updated_at = NOW() WHERE id = $2`, passwordHash, id)
+	if err != nil {
+		return err
+	}
+	n, _ := result.RowsAffected()
+	if n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
 func (r *userRepo) Delete(ctx context.Context, id string) error {
 	query := `DELETE FROM users WHERE id = $1`
 	_, err := r.db.ExecContext(ctx, query, id)
@@ -0,0 +1,216 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+)
+
+type webhookRepo struct{ db *sql.DB }
+
+func NewWebhookRepository(db *sql.DB) repository.WebhookRepository {
+	return &webhookRepo{db: db}
+}
+
+const webhookSubscriptionColumns = `id, name, url, secret, event_types, region_ids, min_severity, active, COALESCE(created_by,''), created_at, updated_at`
+
+func scanWebhookSubscription(row interface{ Scan(...interface{}) error }) (*entity.WebhookSubscription, error) {
+	var s entity.WebhookSubscription
+	err := row.Scan(&s.ID, &s.Name, &s.URL, &s.Secret, pq.Array(&s.EventTypes), pq.Array(&s.RegionIDs), &s.MinSeverity, &s.Active, &s.CreatedBy, &s.CreatedAt, &s.UpdatedAt)
+	if err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+func (r *webhookRepo) CreateSubscription(ctx context.Context, s *entity.WebhookSubscription) error {
+	query := `INSERT INTO webhook_subscriptions (name, url, secret, event_types, region_ids, min_severity, active, created_by)
+	          VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+	          RETURNING id, created_at, updated_at`
+	return r.db.QueryRowContext(ctx, query, s.Name, s.URL, s.Secret, pq.Array(s.EventTypes), pq.Array(nonNilStrings(s.RegionIDs)), s.MinSeverity, s.Active, s.CreatedBy).
+		Scan(&s.ID, &s.CreatedAt, &s.UpdatedAt)
+}
+
+func (r *webhookRepo) UpdateSubscription(ctx context.Context, s *entity.WebhookSubscription) error {
+	query := `UPDATE webhook_subscriptions SET name = $1, url = $2, event_types = $3, region_ids = $4, min_severity = $5, active = $6, updated_at = NOW()
+	          WHERE id = $7
+	          RETURNING updated_at`
+	return r.db.QueryRowContext(ctx, query, s.Name, s.URL, pq.Array(s.EventTypes), pq.Array(nonNilStrings(s.RegionIDs)), s.MinSeverity, s.Active, s.ID).Scan(&s.UpdatedAt)
+}
+
+func (r *webhookRepo) UpdateSecret(ctx context.Context, id, secret string) error {
+	result, err := r.db.ExecContext(ctx, `UPDATE webhook_subscriptions SET secret = $1, updated_at = NOW() WHERE id = $2`, secret, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := result.RowsAffected(); n == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+func (r *webhookRepo) DeleteSubscription(ctx context.Context, id string) error {
+	_, err := r.db.ExecContext(ctx, `DELETE FROM webhook_subscriptions WHERE id = $1`, id)
+	return err
+}
+
+func (r *webhookRepo) GetSubscription(ctx context.Context, id string) (*entity.WebhookSubscription, error) {
+	s, err := scanWebhookSubscription(r.db.QueryRowContext(ctx, `SELECT `+webhookSubscriptionColumns+` FROM webhook_subscriptions WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return s, err
+}
+
+func (r *webhookRepo) ListSubscriptions(ctx context.Context) ([]entity.WebhookSubscription, error) {
+	return r.querySubscriptions(ctx, `SELECT `+webhookSubscriptionColumns+` FROM webhook_subscriptions ORDER BY created_at DESC`)
+}
+
+func (r *webhookRepo) ActiveSubscriptions(ctx context.Context, eventType string) ([]entity.WebhookSubscription, error) {
+	return r.querySubscriptions(ctx, `SELECT `+webhookSubscriptionColumns+` FROM webhook_subscriptions WHERE active AND $1 = ANY(event_types)`, eventType)
+}
+
+func (r *webhookRepo) querySubscriptions(ctx context.Context, query string, args ...interface{}) ([]entity.WebhookSubscription, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	subs := []entity.WebhookSubscription{}
+	for rows.Next() {
+		s, err := scanWebhookSubscription(rows)
+		if err != nil {
+			return nil, err
+		}
+		subs = append(subs, *s)
+	}
+	return subs, rows.Err()
+}
+
+const webhookDeliveryColumns = `id, subscription_id, event_id, event_type, payload, status, attempts, response_status, COALESCE(last_error,''), next_attempt_at, created_at, delivered_at`
+
+func scanWebhookDelivery(row interface{ Scan(...interface{}) error }) (*entity.WebhookDelivery, error) {
+	var d entity.WebhookDelivery
+	var payload []byte
+	var deliveredAt sql.NullTime
+	err := row.Scan(&d.ID, &d.SubscriptionID, &d.EventID, &d.EventType, &payload, &d.Status, &d.Attempts, &d.ResponseStatus, &d.LastError, &d.NextAttemptAt, &d.CreatedAt, &deliveredAt)
+	if err != nil {
+		return nil, err
+	}
+	d.Payload = payload
+	if deliveredAt.Valid {
+		d.DeliveredAt = &deliveredAt.Time
+	}
+	return &d, nil
+}
+
+func (r *webhookRepo) CreateDelivery(ctx context.Context, d *entity.WebhookDelivery) (bool, error) {
+	query := `INSERT INTO webhook_deliveries (subscription_id, event_id, event_type, payload)
+	          VALUES ($1, $2, $3, $4)
+	          ON CONFLICT (subscription_id, event_id) DO NOTHING
+	          RETURNING id, status, next_attempt_at, created_at`
+	err := r.db.QueryRowContext(ctx, query, d.SubscriptionID, d.EventID, d.EventType, []byte(d.Payload)).
+		Scan(&d.ID, &d.Status, &d.NextAttemptAt, &d.CreatedAt)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+func (r *webhookRepo) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.WebhookDelivery, error) {
+	// Le bail repousse next_attempt_at : si le dispatcher meurt, la livraison sera reprise
+	query := `UPDATE webhook_deliveries SET next_attempt_at = NOW() + $2::interval, attempts = attempts + 1
+	          WHERE id IN (
+	            SELECT id FROM webhook_deliveries
+	            WHERE status = 'pending' AND next_attempt_at <= NOW()
+	            ORDER BY next_attempt_at
+	            LIMIT $1
+	            FOR UPDATE SKIP LOCKED)
+	          RETURNING ` + webhookDeliveryColumns
+	rows, err := r.db.QueryContext(ctx, query, limit, fmt.Sprintf("%d milliseconds", lease.Milliseconds()))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []entity.WebhookDelivery
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *d)
+	}
+	return deliveries, rows.Err()
+}
+
+func (r *webhookRepo) MarkDelivered(ctx context.Context, id string, responseStatus int) error {
+	query := `UPDATE webhook_deliveries SET status = 'delivered', response_status = $1, last_error = '', delivered_at = NOW() WHERE id = $2`
+	_, err := r.db.ExecContext(ctx, query, responseStatus, id)
+	return err
+}
+
+func (r *webhookRepo) MarkFailed(ctx context.Context, id string, responseStatus int, cause string, retryAt *time.Time) error {
+	if retryAt == nil {
+		query := `UPDATE webhook_deliveries SET status = 'failed', response_status = $1, last_error = $2 WHERE id = $3`
+		_, err := r.db.ExecContext(ctx, query, responseStatus, cause, id)
+		return err
+	}
+	query := `UPDATE webhook_deliveries SET response_status = $1, last_error = $2, next_attempt_at = $3 WHERE id = $4`
+	_, err := r.db.ExecContext(ctx, query, responseStatus, cause, *retryAt, id)
+	return err
+}
+
+func (r *webhookRepo) GetDelivery(ctx context.Context, id string) (*entity.WebhookDelivery, error) {
+	d, err := scanWebhookDelivery(r.db.QueryRowContext(ctx, `SELECT `+webhookDeliveryColumns+` FROM webhook_deliveries WHERE id = $1`, id))
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return d, err
+}
+
+func (r *webhookRepo) ListDeliveries(ctx context.Context, subscriptionID, status string, limit int) ([]entity.WebhookDelivery, error) {
+	query := `SELECT ` + webhookDeliveryColumns + ` FROM webhook_deliveries WHERE subscription_id = $1`
+	args := []interface{}{subscriptionID}
+	if status != "" {
+		query += ` AND status = $2`
+		args = append(args, status)
+	}
+	query += fmt.Sprintf(` ORDER BY created_at DESC LIMIT %d`, limit)
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []entity.WebhookDelivery{}
+	for rows.Next() {
+		d, err := scanWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *d)
+	}
+	return deliveries, rows.Err()
+}
+
+func (r *webhookRepo) ResetDelivery(ctx context.Context, id string) error {
+	query := `UPDATE webhook_deliveries SET status = 'pending', attempts = 0, last_error = '', next_attempt_at = NOW(), delivered_at = NULL WHERE id = $1`
+	_, err := r.db.ExecContext(ctx, query, id)
+	return err
+}
+
+// nonNilStrings évite d'insérer NULL dans une colonne tableau NOT NULL
+func nonNilStrings(values []string) []string {
+	if values == nil {
+		return []string{}
+	}
+	return values
+}
@@ -0,0 +1,200 @@
+package repositorytest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+)
+
+func testAlerts(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	rule := &entity.AlertRule{Name: "Violences au Littoral", IncidentTypes: []string{"VIOLE"}, MinSeverity: 4, RegionIDs: []string{seededRegionID},
+		GroupBy: entity.AlertGroupRegion, H3Resolution: 6, Threshold: 2, WindowMinutes: 30, Timezone: "Africa/Douala", Channels: []string{"dashboard", "email"}, Active: true}
+	if err := repos.Alerts.CreateRule(ctx, rule); err != nil || rule.ID == "" {
+		t.Fatalf("create rule: %+v, %v", rule, err)
+	}
+
+	t.Run("manages rules", func(t *testing.T) {
+		got, err := repos.Alerts.GetRule(ctx, rule.ID)
+		if err != nil || got == nil {
+			t.Fatalf("get rule: %+v, %v", got, err)
+		}
+		if !slices.Equal(got.IncidentTypes, []string{"VIOLE"}) || !slices.Equal(got.Channels, []string{"dashboard", "email"}) || got.Threshold != 2 || got.TimeOfDayStart != "" {
+			t.Errorf("unexpected rule: %+v", got)
+		}
+		if active, _ := repos.Alerts.ActiveRules(ctx); !slices.ContainsFunc(active, func(r entity.AlertRule) bool { return r.ID == rule.ID }) {
+			t.Error("active rule not listed")
+		}
+
+		rule.Threshold, rule.TimeOfDayStart, rule.TimeOfDayEnd = 3, "08:00", "18:00"
+		if err := repos.Alerts.UpdateRule(ctx, rule); err != nil {
+			t.Fatalf("update rule: %v", err)
+		}
+		if got, _ := repos.Alerts.GetRule(ctx, rule.ID); got.Threshold != 3 || got.TimeOfDayEnd != "18:00" {
+			t.Errorf("rule after update = %+v", got)
+		}
+		missing := *rule
+		missing.ID = uuid.New().String()
+		if err := repos.Alerts.UpdateRule(ctx, &missing); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("update unknown rule = %v, want sql.ErrNoRows", err)
+		}
+		if got, err := repos.Alerts.GetRule(ctx, missing.ID); got != nil || err != nil {
+			t.Errorf("unknown rule = %+v, %v; want nil, nil", got, err)
+		}
+	})
+
+	t.Run("selects reports in the window", func(t *testing.T) {
+		local := createUser(t, repos, entity.RoleObserver, seededRegionID)
+		elsewhere := createUser(t, repos, entity.RoleObserver, otherRegionID)
+		at := now().Add(-10 * time.Minute)
+		report := func(observerID, incidentType string, severity int) string {
+			return createReport(t, repos, observerID, func(r *entity.Report) {
+				r.IncidentType, r.Severity, r.CreatedAt = incidentType, severity, at
+			}).ID
+		}
+		byCode := report(local.ID, "VIOLE", 5)
+		byName := report(local.ID, "Violence", 4)
+		report(local.ID, "VIOLE", 2)
+		report(local.ID, "STUFF", 5)
+		report(elsewhere.ID, "VIOLE", 5)
+		rejected := report(local.ID, "VIOLE", 5)
+		if err := repos.Reports.UpdateStatus(ctx, rejected, entity.StatusRejected); err != nil {
+			t.Fatalf("update status: %v", err)
+		}
+
+		got, err := repos.Alerts.ReportsInWindow(ctx, repository.AlertReportFilter{
+			IncidentTypes: []string{"VIOLE"}, MinSeverity: 4, RegionIDs: []string{seededRegionID},
+			Since: at.Add(-time.Minute), Until: at.Add(time.Minute),
+		})
+		if err != nil {
+			t.Fatalf("reports in window: %v", err)
+		}
+		var ids []string
+		for _, r := range got {
+			ids = append(ids, r.ID)
+			if r.RegionID != seededRegionID {
+				t.Errorf("region not joined on %s: %q", r.ID, r.RegionID)
+			}
+		}
+		slices.Sort(ids)
+		want := []string{byCode, byName}
+		slices.Sort(want)
+		if !slices.Equal(ids, want) {
+			t.Errorf("reports = %v, want %v", ids, want)
+		}
+	})
+
+	t.Run("resolves the department of the nearest polling station", func(t *testing.T) {
+		observer := createUser(t, repos, entity.RoleObserver, seededRegionID)
+		station := func(code string, lat, lon float64) string {
+			dept := &entity.Department{Name: code, Code: code, RegionID: seededRegionID}
+			if err := repos.Regions.CreateDepartment(ctx, dept); err != nil {
+				t.Fatalf("create department: %v", err)
+			}
+			if _, err := repos.Stations.Upsert(ctx, &entity.PollingStation{Code: code + "-BV", Name: code, DepartmentID: dept.ID, Latitude: &lat, Longitude: &lon}); err != nil {
+				t.Fatalf("upsert station: %v", err)
+			}
+			return dept.ID
+		}
+		west, east := station("ALT-W", -30, 40), station("ALT-E", -30, 41)
+		at := now().Add(-5 * time.Minute)
+		near := createReport(t, repos, observer.ID, func(r *entity.Report) {
+			r.IncidentType, r.Severity, r.CreatedAt, r.GPSLocation = "DEPT", 3, at, "POINT(40.9 -30.1)"
+		}).ID
+
+		filter := repository.AlertReportFilter{IncidentTypes: []string{"DEPT"}, Since: at.Add(-time.Minute), Until: at.Add(time.Minute)}
+		if got, err := repos.Alerts.ReportsInWindow(ctx, filter); err != nil || len(got) != 1 || got[0].DepartmentID != "" {
+			t.Errorf("reports without department = %+v, %v", got, err)
+		}
+		filter.WithDepartment = true
+		got, err := repos.Alerts.ReportsInWindow(ctx, filter)
+		if err != nil || len(got) != 1 || got[0].ID != near || got[0].DepartmentID != east {
+			t.Errorf("reports = %+v, %v; want %s in %s (not %s)", got, err, near, east, west)
+		}
+	})
+
+	t.Run("feeds the open alert of a zone", func(t *testing.T) {
+		first := &entity.Alert{RuleID: rule.ID, GroupKey: seededRegionID, RegionID: seededRegionID, Severity: 4, ReportIDs: []string{"r1", "r2"}, ReportCount: 2}
+		created, err := repos.Alerts.UpsertOpenAlert(ctx, first)
+		if err != nil || !created || first.ID == "" || first.Status != entity.AlertOpen {
+			t.Fatalf("first upsert = %v, %v (%+v)", created, err, first)
+		}
+		again := &entity.Alert{RuleID: rule.ID, GroupKey: seededRegionID, RegionID: seededRegionID, Severity: 5, ReportIDs: []string{"r2", "r3"}, ReportCount: 2}
+		created, err = repos.Alerts.UpsertOpenAlert(ctx, again)
+		if err != nil || created || again.ID != first.ID {
+			t.Fatalf("second upsert = %v, %v (%+v); want the same alert", created, err, again)
+		}
+		ids := slices.Clone(again.ReportIDs)
+		slices.Sort(ids)
+		if !slices.Equal(ids, []string{"r1", "r2", "r3"}) || again.ReportCount != 3 || again.Severity != 5 {
+			t.Errorf("merged alert = %+v", again)
+		}
+
+		got, err := repos.Alerts.GetAlert(ctx, first.ID)
+		if err != nil || got == nil || got.RuleName != rule.Name || got.ReportCount != 3 {
+			t.Fatalf("get alert = %+v, %v", got, err)
+		}
+		if missing, err := repos.Alerts.GetAlert(ctx, uuid.New().String()); missing != nil || err != nil {
+			t.Errorf("unknown alert = %+v, %v; want nil, nil", missing, err)
+		}
+	})
+
+	t.Run("acknowledges, resolves and reopens", func(t *testing.T) {
+		open, err := repos.Alerts.ListAlerts(ctx, repository.AlertFilter{RuleID: rule.ID, Status: string(entity.AlertOpen)})
+		if err != nil || len(open) != 1 {
+			t.Fatalf("open alerts = %+v, %v", open, err)
+		}
+		id := open[0].ID
+		if err := repos.Alerts.UpdateAlertStatus(ctx, id, entity.AlertAcknowledged, "coord", ""); err != nil {
+			t.Fatalf("acknowledge: %v", err)
+		}
+		if got, _ := repos.Alerts.GetAlert(ctx, id); got.Status != entity.AlertAcknowledged || got.AcknowledgedBy != "coord" || got.AcknowledgedAt == nil {
+			t.Errorf("acknowledged alert = %+v", got)
+		}
+		if err := repos.Alerts.UpdateAlertStatus(ctx, id, entity.AlertOpen, "coord", ""); err == nil {
+			t.Error("reopening through UpdateAlertStatus accepted")
+		}
+
+		// Une fois acquittée, l'alerte n'absorbe plus les déclenchements : une nouvelle est ouverte
+		next := &entity.Alert{RuleID: rule.ID, GroupKey: seededRegionID, RegionID: seededRegionID, Severity: 4, ReportIDs: []string{"r4"}, ReportCount: 1}
+		if created, err := repos.Alerts.UpsertOpenAlert(ctx, next); err != nil || !created || next.ID == id {
+			t.Errorf("upsert after acknowledge = %v, %v (%+v); want a new alert", created, err, next)
+		}
+		if got, _ := repos.Alerts.GetAlert(ctx, id); got.ReportCount != 3 || slices.Contains(got.ReportIDs, "r4") {
+			t.Errorf("acknowledged alert grew: %+v", got)
+		}
+
+		if err := repos.Alerts.UpdateAlertStatus(ctx, id, entity.AlertResolved, "coord", "calme revenu"); err != nil {
+			t.Fatalf("resolve: %v", err)
+		}
+		if got, _ := repos.Alerts.GetAlert(ctx, id); got.Status != entity.AlertResolved || got.ResolutionNote != "calme revenu" || got.ResolvedAt == nil {
+			t.Errorf("resolved alert = %+v", got)
+		}
+		later := &entity.Alert{RuleID: rule.ID, GroupKey: seededRegionID, RegionID: seededRegionID, Severity: 4, ReportIDs: []string{"r5"}, ReportCount: 1}
+		if created, err := repos.Alerts.UpsertOpenAlert(ctx, later); err != nil || created || later.ID != next.ID {
+			t.Errorf("upsert after resolve = %v, %v (%+v); want %s fed", created, err, later, next.ID)
+		}
+		if all, _ := repos.Alerts.ListAlerts(ctx, repository.AlertFilter{RegionID: seededRegionID}); len(all) != 2 || all[0].ID != next.ID {
+			t.Errorf("alerts = %+v, want the newest first", all)
+		}
+	})
+
+	t.Run("deleting a rule cascades to alerts", func(t *testing.T) {
+		if err := repos.Alerts.DeleteRule(ctx, rule.ID); err != nil {
+			t.Fatalf("delete rule: %v", err)
+		}
+		if left, _ := repos.Alerts.ListAlerts(ctx, repository.AlertFilter{RuleID: rule.ID}); len(left) != 0 {
+			t.Errorf("%d alerts left", len(left))
+		}
+		if rules, _ := repos.Alerts.ListRules(ctx); slices.ContainsFunc(rules, func(r entity.AlertRule) bool { return r.ID == rule.ID }) {
+			t.Error("deleted rule still listed")
+		}
+	})
+}
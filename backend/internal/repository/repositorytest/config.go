@@ -0,0 +1,134 @@
+package repositorytest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+)
+
+func testConfig(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	appendVersion := func(section, data string, base int64, rolledBackFrom *int64) (*entity.ConfigVersion, error) {
+		v := &entity.ConfigVersion{Section: section, Data: json.RawMessage(data), ChangedBy: "admin", Comment: "contract"}
+		v.RolledBackFrom = rolledBackFrom
+		return v, repos.Config.Append(ctx, v, base)
+	}
+
+	if current, err := repos.Config.CurrentVersion(ctx); err != nil || current != 0 {
+		t.Fatalf("current version of an empty store = %d, %v", current, err)
+	}
+	v1, err := appendVersion("triangulation", `{"radius_m": 500}`, 0, nil)
+	if err != nil || v1.Version == 0 || v1.CreatedAt.IsZero() {
+		t.Fatalf("first append: %+v, %v", v1, err)
+	}
+	v2, err := appendVersion("rate_limiting", `{"rps": 10}`, 0, nil)
+	if err != nil {
+		t.Fatalf("second section: %v", err)
+	}
+	v3, err := appendVersion("triangulation", `{"radius_m": 800}`, v1.Version, nil)
+	if err != nil || v3.Version <= v2.Version {
+		t.Fatalf("next version: %+v, %v", v3, err)
+	}
+
+	t.Run("rejects a stale base version", func(t *testing.T) {
+		if _, err := appendVersion("triangulation", `{"radius_m": 1}`, v1.Version, nil); !errors.Is(err, repository.ErrConfigVersionConflict) {
+			t.Errorf("stale append = %v, want ErrConfigVersionConflict", err)
+		}
+		if _, err := appendVersion("rate_limiting", `{"rps": 1}`, 0, nil); !errors.Is(err, repository.ErrConfigVersionConflict) {
+			t.Errorf("append over an existing section with base 0 = %v, want ErrConfigVersionConflict", err)
+		}
+	})
+
+	t.Run("returns the latest version of each section", func(t *testing.T) {
+		latest, err := repos.Config.Latest(ctx)
+		if err != nil || len(latest) != 2 {
+			t.Fatalf("latest = %+v, %v", latest, err)
+		}
+		bySection := map[string]entity.ConfigVersion{}
+		for _, v := range latest {
+			bySection[v.Section] = v
+		}
+		if bySection["triangulation"].Version != v3.Version || bySection["rate_limiting"].Version != v2.Version {
+			t.Errorf("latest versions = %+v", bySection)
+		}
+		assertJSON(t, bySection["triangulation"].Data, `{"radius_m": 800}`)
+		if current, _ := repos.Config.CurrentVersion(ctx); current != v3.Version {
+			t.Errorf("current version = %d, want %d", current, v3.Version)
+		}
+	})
+
+	t.Run("keeps the history", func(t *testing.T) {
+		history, err := repos.Config.History(ctx, "triangulation", 10)
+		if err != nil || len(history) != 2 || history[0].Version != v3.Version || history[1].Version != v1.Version {
+			t.Fatalf("history = %+v, %v", history, err)
+		}
+		if limited, _ := repos.Config.History(ctx, "triangulation", 1); len(limited) != 1 {
+			t.Errorf("limited history = %d entries", len(limited))
+		}
+
+		rollback, err := appendVersion("triangulation", string(v1.Data), v3.Version, &v1.Version)
+		if err != nil {
+			t.Fatalf("rollback: %v", err)
+		}
+		got, err := repos.Config.GetVersion(ctx, rollback.Version)
+		if err != nil || got == nil || got.RolledBackFrom == nil || *got.RolledBackFrom != v1.Version || got.ChangedBy != "admin" || got.Comment != "contract" {
+			t.Fatalf("get version = %+v, %v", got, err)
+		}
+		assertJSON(t, got.Data, `{"radius_m": 500}`)
+		if missing, err := repos.Config.GetVersion(ctx, rollback.Version+100); missing != nil || err != nil {
+			t.Errorf("unknown version = %+v, %v; want nil, nil", missing, err)
+		}
+	})
+
+	t.Run("appends several sections all or nothing", func(t *testing.T) {
+		latest, _ := repos.Config.Latest(ctx)
+		bases := map[string]int64{}
+		for _, v := range latest {
+			bases[v.Section] = v.Version
+		}
+		batch := func(data string, bases map[string]int64) ([]*entity.ConfigVersion, error) {
+			versions := []*entity.ConfigVersion{
+				{Section: "triangulation", Data: json.RawMessage(data), ChangedBy: "admin"},
+				{Section: "storage", Data: json.RawMessage(data), ChangedBy: "admin"},
+			}
+			return versions, repos.Config.AppendAll(ctx, versions, bases)
+		}
+
+		// storage n'a jamais été enregistrée : une base non nulle est périmée
+		stale := map[string]int64{"triangulation": bases["triangulation"], "storage": 1}
+		if _, err := batch(`{"stale": true}`, stale); !errors.Is(err, repository.ErrConfigVersionConflict) {
+			t.Fatalf("stale batch = %v, want ErrConfigVersionConflict", err)
+		}
+		if current, _ := repos.Config.CurrentVersion(ctx); current != bases["triangulation"] {
+			t.Errorf("current version after a rejected batch = %d, want %d", current, bases["triangulation"])
+		}
+
+		versions, err := batch(`{"batch": true}`, bases)
+		if err != nil || versions[0].Version == 0 || versions[1].Version <= versions[0].Version {
+			t.Fatalf("batch = %+v, %v", versions, err)
+		}
+		if history, _ := repos.Config.History(ctx, "storage", 10); len(history) != 1 || history[0].Version != versions[1].Version {
+			t.Errorf("storage history = %+v", history)
+		}
+	})
+}
+
+// assertJSON compare deux documents JSON indépendamment de leur mise en forme (JSONB la normalise)
+func assertJSON(t *testing.T, got json.RawMessage, want string) {
+	t.Helper()
+	var g, w interface{}
+	if err := json.Unmarshal(got, &g); err != nil {
+		t.Fatalf("invalid JSON %s: %v", got, err)
+	}
+	if err := json.Unmarshal([]byte(want), &w); err != nil {
+		t.Fatalf("invalid JSON %s: %v", want, err)
+	}
+	if !reflect.DeepEqual(g, w) {
+		t.Errorf("JSON = %s, want %s", got, want)
+	}
+}
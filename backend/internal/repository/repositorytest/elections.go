@@ -0,0 +1,159 @@
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/openvote/backend/internal/domain/entity"
+)
+
+func testElections(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	date := time.Date(2026, 10, 12, 8, 0, 0, 0, time.UTC)
+
+	t.Run("creates, updates and deletes", func(t *testing.T) {
+		e := &entity.Election{Name: "Présidentielle", Type: "présidentielle", Status: entity.ElectionPlanned, Date: date,
+			Description: "Premier tour", RegionIDs: `["` + seededRegionID + `"]`}
+		if err := repos.Elections.Create(ctx, e); err != nil || e.ID == "" {
+			t.Fatalf("create: %+v, %v", e, err)
+		}
+		got, err := repos.Elections.GetByID(ctx, e.ID)
+		if err != nil || got == nil {
+			t.Fatalf("get by id: %+v, %v", got, err)
+		}
+		if got.Name != e.Name || got.RegionIDs != e.RegionIDs || got.Status != entity.ElectionPlanned || !got.Date.Equal(date) {
+			t.Errorf("unexpected election: %+v", got)
+		}
+
+		e.Name, e.Date = "Présidentielle (report)", date.Add(7*24*time.Hour)
+		if err := repos.Elections.Update(ctx, e); err != nil {
+			t.Fatalf("update: %v", err)
+		}
+		if err := repos.Elections.UpdateStatus(ctx, e.ID, entity.ElectionActive); err != nil {
+			t.Fatalf("update status: %v", err)
+		}
+		got, _ = repos.Elections.GetByID(ctx, e.ID)
+		if got.Name != e.Name || got.Status != entity.ElectionActive || !got.Date.Equal(e.Date) {
+			t.Errorf("election after update = %+v", got)
+		}
+
+		if err := repos.Elections.Delete(ctx, e.ID); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+		if got, err := repos.Elections.GetByID(ctx, e.ID); got != nil || err != nil {
+			t.Errorf("deleted election = %+v, %v; want nil, nil", got, err)
+		}
+	})
+
+	t.Run("lists by date, latest first", func(t *testing.T) {
+		for i, name := range []string{"Municipales", "Législatives"} {
+			e := &entity.Election{Name: name, Type: "générale", Status: entity.ElectionPlanned, Date: date.AddDate(i+1, 0, 0)}
+			if err := repos.Elections.Create(ctx, e); err != nil {
+				t.Fatalf("create: %v", err)
+			}
+		}
+		all, err := repos.Elections.GetAll(ctx)
+		if err != nil || len(all) < 2 {
+			t.Fatalf("get all: %d, %v", len(all), err)
+		}
+		if all[0].Name != "Législatives" || all[1].Name != "Municipales" {
+			t.Errorf("order = %s, %s", all[0].Name, all[1].Name)
+		}
+	})
+}
+
+func testAuditLogs(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	admin := uuid.New().String()
+
+	var created []*entity.AuditLog
+	for _, action := range []string{"LOGIN", "UPDATE_ROLE", "DELETE_USER"} {
+		l := &entity.AuditLog{AdminID: admin, AdminName: "admin", Action: action, TargetID: "target", Details: "{}"}
+		if err := repos.AuditLogs.Create(ctx, l); err != nil {
+			t.Fatalf("create: %v", err)
+		}
+		created = append(created, l)
+	}
+
+	t.Run("chains entries", func(t *testing.T) {
+		chain, err := repos.AuditLogs.ListChain(ctx, created[0].Seq-1, 10)
+		if err != nil || len(chain) != 3 {
+			t.Fatalf("list chain = %d entries, %v", len(chain), err)
+		}
+		prev := chain[0].PrevHash
+		for i, l := range chain {
+			if l.ID != created[i].ID || l.Action != created[i].Action || l.PrevHash != prev || l.Hash != l.ComputeHash(prev) {
+				t.Errorf("broken chain at %d: %+v", i, l)
+			}
+			if !l.CreatedAt.Equal(created[i].CreatedAt) {
+				t.Errorf("created_at at %d = %v, want %v", i, l.CreatedAt, created[i].CreatedAt)
+			}
+			prev = l.Hash
+		}
+	})
+
+	t.Run("pages after a sequence number", func(t *testing.T) {
+		page, err := repos.AuditLogs.ListChain(ctx, created[1].Seq, 10)
+		if err != nil || len(page) != 1 || page[0].Action != "DELETE_USER" {
+			t.Errorf("page = %+v, %v", page, err)
+		}
+		latest, err := repos.AuditLogs.GetAll(ctx, 1)
+		if err != nil || len(latest) != 1 || latest[0].ID != created[2].ID {
+			t.Errorf("latest = %+v, %v", latest, err)
+		}
+	})
+}
+
+func testIncidentTypes(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	t.Run("loads the reference types by severity", func(t *testing.T) {
+		types, err := repos.IncidentTypes.GetAll(ctx)
+		if err != nil || len(types) < 12 {
+			t.Fatalf("get all = %d, %v", len(types), err)
+		}
+		for i := 1; i < len(types); i++ {
+			if types[i-1].Severity < types[i].Severity {
+				t.Errorf("types not ordered by severity: %s (%d) before %s (%d)", types[i-1].Code, types[i-1].Severity, types[i].Code, types[i].Severity)
+			}
+		}
+	})
+
+	t.Run("finds a type by code or name", func(t *testing.T) {
+		byCode, err := repos.IncidentTypes.FindByCodeOrName(ctx, "STUFF")
+		if err != nil || byCode == nil || byCode.Severity != 5 {
+			t.Fatalf("by code = %+v, %v", byCode, err)
+		}
+		if byName, _ := repos.IncidentTypes.FindByCodeOrName(ctx, byCode.Name); byName == nil || byName.ID != byCode.ID {
+			t.Errorf("by name = %+v", byName)
+		}
+		if missing, err := repos.IncidentTypes.FindByCodeOrName(ctx, "UNKNOWN"); missing != nil || err != nil {
+			t.Errorf("unknown type = %+v, %v; want nil, nil", missing, err)
+		}
+	})
+
+	t.Run("creates, updates and deletes", func(t *testing.T) {
+		it := &entity.IncidentType{Name: "Bulletins manquants", Code: "NOBAL", Description: "Rupture de bulletins", Severity: 3, Color: "#d29922"}
+		if err := repos.IncidentTypes.Create(ctx, it); err != nil || it.ID == "" {
+			t.Fatalf("create: %+v, %v", it, err)
+		}
+		if err := repos.IncidentTypes.Create(ctx, &entity.IncidentType{Name: "Doublon", Code: "NOBAL", Severity: 1}); err == nil {
+			t.Error("duplicate code accepted")
+		}
+		it.Severity = 4
+		if err := repos.IncidentTypes.Update(ctx, it); err != nil {
+			t.Fatalf("update: %v", err)
+		}
+		if got, _ := repos.IncidentTypes.FindByCodeOrName(ctx, "NOBAL"); got == nil || got.Severity != 4 || got.Color != "#d29922" {
+			t.Errorf("type after update = %+v", got)
+		}
+		if err := repos.IncidentTypes.Delete(ctx, it.ID); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+		if got, _ := repos.IncidentTypes.FindByCodeOrName(ctx, "NOBAL"); got != nil {
+			t.Errorf("deleted type still found: %+v", got)
+		}
+	})
+}
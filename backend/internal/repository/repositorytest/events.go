@@ -0,0 +1,161 @@
+package repositorytest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+)
+
+func testIncidentEvents(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	observer := createUser(t, repos, entity.RoleObserver, seededRegionID)
+	base := now().Add(-2 * time.Hour)
+	place := func(wkt string, severity int, createdAt time.Time) string {
+		return createReport(t, repos, observer.ID, func(r *entity.Report) {
+			r.GPSLocation, r.Severity, r.CreatedAt = wkt, severity, createdAt
+		}).ID
+	}
+	r1 := place("POINT(10 4)", 2, base)
+	r2 := place("POINT(12 6)", 5, base.Add(10*time.Minute))
+	r3 := place("POINT(11 5)", 3, base.Add(20*time.Minute))
+
+	target := &entity.IncidentEvent{IncidentType: "STUFF", H3Index: "event-cell", Centroid: "POINT(10 4)", FirstReportedAt: base, LastReportedAt: base}
+	source := &entity.IncidentEvent{IncidentType: "STUFF", H3Index: "other-cell", Centroid: "POINT(13 7)", FirstReportedAt: base, LastReportedAt: base}
+	for _, e := range []*entity.IncidentEvent{target, source} {
+		if err := repos.Events.Create(ctx, e); err != nil || e.ID == "" {
+			t.Fatalf("create event: %+v, %v", e, err)
+		}
+	}
+
+	t.Run("refreshes aggregates when reports are attached", func(t *testing.T) {
+		if err := repos.Events.AttachReports(ctx, target.ID, []string{r1, r2}); err != nil {
+			t.Fatalf("attach: %v", err)
+		}
+		got, err := repos.Events.GetByID(ctx, target.ID)
+		if err != nil || got == nil {
+			t.Fatalf("get by id: %+v, %v", got, err)
+		}
+		if got.ReportCount != 2 || got.MaxSeverity != 5 || got.Centroid != "POINT(11 5)" || got.Status != entity.EventActive {
+			t.Errorf("unexpected aggregates: %+v", got)
+		}
+		if !got.FirstReportedAt.Equal(base) || !got.LastReportedAt.Equal(base.Add(10*time.Minute)) {
+			t.Errorf("window = %v..%v", got.FirstReportedAt, got.LastReportedAt)
+		}
+		if !slices.Equal(got.ReportIDs, []string{r1, r2}) {
+			t.Errorf("report ids = %v, want creation order", got.ReportIDs)
+		}
+		if missing, err := repos.Events.GetByID(ctx, uuid.New().String()); missing != nil || err != nil {
+			t.Errorf("unknown event = %+v, %v; want nil, nil", missing, err)
+		}
+	})
+
+	t.Run("finds a candidate by cell or distance", func(t *testing.T) {
+		start, end := base.Add(-time.Hour), base.Add(time.Hour)
+		if got, err := repos.Events.FindCandidate(ctx, "STUFF", "event-cell", 0, 0, 100, start, end); err != nil || got == nil || got.ID != target.ID {
+			t.Errorf("candidate by cell = %+v, %v", got, err)
+		}
+		// Le centroïde de la cible est POINT(11 5) : 0,001° de longitude ≈ 110 m
+		if got, err := repos.Events.FindCandidate(ctx, "STUFF", "none", 5, 11.001, 500, start, end); err != nil || got == nil || got.ID != target.ID {
+			t.Errorf("candidate by distance = %+v, %v", got, err)
+		}
+		if got, _ := repos.Events.FindCandidate(ctx, "VIOLE", "event-cell", 5, 11, 500, start, end); got != nil {
+			t.Errorf("candidate of another type = %+v", got)
+		}
+		if got, _ := repos.Events.FindCandidate(ctx, "STUFF", "event-cell", 5, 11, 500, base.Add(time.Hour), base.Add(2*time.Hour)); got != nil {
+			t.Errorf("candidate outside the window = %+v", got)
+		}
+	})
+
+	t.Run("merges sources into the target", func(t *testing.T) {
+		if err := repos.Events.AttachReports(ctx, source.ID, []string{r3}); err != nil {
+			t.Fatalf("attach: %v", err)
+		}
+		if err := repos.Events.Merge(ctx, target.ID, []string{source.ID}); err != nil {
+			t.Fatalf("merge: %v", err)
+		}
+		merged, _ := repos.Events.GetByID(ctx, target.ID)
+		if merged.ReportCount != 3 || merged.Centroid != "POINT(11 5)" || !merged.LastReportedAt.Equal(base.Add(20*time.Minute)) {
+			t.Errorf("aggregates after merge: %+v", merged)
+		}
+		old, _ := repos.Events.GetByID(ctx, source.ID)
+		if old.Status != entity.EventMerged || old.MergedInto != target.ID || len(old.ReportIDs) != 0 {
+			t.Errorf("source after merge: %+v", old)
+		}
+		if err := repos.Events.Merge(ctx, target.ID, []string{source.ID}); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("merging a merged event = %v, want sql.ErrNoRows", err)
+		}
+
+		mergedEvents, _ := repos.Events.GetAll(ctx, string(entity.EventMerged))
+		if len(mergedEvents) != 1 || mergedEvents[0].ID != source.ID {
+			t.Errorf("merged events = %+v", mergedEvents)
+		}
+	})
+
+	t.Run("splits reports into a new event", func(t *testing.T) {
+		split, err := repos.Events.Split(ctx, target.ID, []string{r2})
+		if err != nil || split == nil {
+			t.Fatalf("split: %+v, %v", split, err)
+		}
+		if split.ReportCount != 1 || split.MaxSeverity != 5 || split.Centroid != "POINT(12 6)" || !slices.Equal(split.ReportIDs, []string{r2}) {
+			t.Errorf("split event: %+v", split)
+		}
+		rest, _ := repos.Events.GetByID(ctx, target.ID)
+		if rest.ReportCount != 2 || rest.MaxSeverity != 3 || !slices.Equal(rest.ReportIDs, []string{r1, r3}) {
+			t.Errorf("remaining event: %+v", rest)
+		}
+		if _, err := repos.Events.Split(ctx, target.ID, []string{r2}); err == nil {
+			t.Error("splitting a foreign report succeeded")
+		}
+
+		active, _ := repos.Events.GetAll(ctx, string(entity.EventActive))
+		var ids []string
+		for _, e := range active {
+			ids = append(ids, e.ID)
+		}
+		if !slices.Equal(ids, []string{split.ID, target.ID}) {
+			t.Errorf("active events = %v, want highest severity first", ids)
+		}
+	})
+
+	t.Run("assigns reports to a found or created event once", func(t *testing.T) {
+		at := now()
+		assign := func(id string) (*entity.IncidentEvent, bool) {
+			t.Helper()
+			candidate := repository.EventCandidate{IncidentType: "ASSIGN", H3Index: "assign-cell", Lat: 3.8, Lon: 11.5, Radius: 500,
+				Start: at.Add(-time.Hour), End: at.Add(time.Hour)}
+			fallback := &entity.IncidentEvent{IncidentType: "ASSIGN", H3Index: "assign-cell", Centroid: "POINT(11.5 3.8)", FirstReportedAt: at, LastReportedAt: at}
+			e, created, err := repos.Events.AssignReport(ctx, id, "ASSIGN:assign-cell", candidate, fallback)
+			if err != nil || e == nil {
+				t.Fatalf("assign %s: %+v, %v", id, e, err)
+			}
+			return e, created
+		}
+		first := place("POINT(11.5 3.8)", 2, at)
+		second := place("POINT(11.5 3.8)", 4, at)
+
+		event, created := assign(first)
+		if !created || event.IncidentType != "ASSIGN" || !slices.Equal(event.ReportIDs, []string{first}) {
+			t.Errorf("first assignment = %+v, created %v", event, created)
+		}
+		joined, created := assign(second)
+		if created || joined.ID != event.ID || joined.ReportCount != 2 || joined.MaxSeverity != 4 {
+			t.Errorf("second assignment = %+v, created %v", joined, created)
+		}
+
+		// Un signalement déjà rattaché ne change pas d'événement
+		split, err := repos.Events.Split(ctx, event.ID, []string{second})
+		if err != nil {
+			t.Fatalf("split: %v", err)
+		}
+		if again, created := assign(second); created || again.ID != split.ID {
+			t.Errorf("reassignment = %+v, created %v; want %s kept", again, created, split.ID)
+		}
+	})
+}
@@ -0,0 +1,182 @@
+package repositorytest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/openvote/backend/internal/domain/entity"
+)
+
+func testLegal(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	doc := &entity.LegalDocument{Title: "Loi de contrat", Description: "Document de test", Type: "law", Version: "2026"}
+	if err := repos.Legal.CreateDocument(ctx, doc); err != nil || doc.ID == "" {
+		t.Fatalf("create document: %+v, %v", doc, err)
+	}
+
+	t.Run("lists documents including the seeded ones", func(t *testing.T) {
+		if err := repos.Legal.UpdateDocumentFullText(ctx, doc.ID, "Texte intégral"); err != nil {
+			t.Fatalf("update full text: %v", err)
+		}
+		docs, err := repos.Legal.GetAllDocuments(ctx)
+		if err != nil {
+			t.Fatalf("get all documents: %v", err)
+		}
+		found := false
+		for _, d := range docs {
+			if d.ID == doc.ID {
+				found = true
+				if d.FullText != "Texte intégral" || d.Type != "law" {
+					t.Errorf("unexpected document: %+v", d)
+				}
+			}
+		}
+		if !found {
+			t.Error("document missing from list")
+		}
+	})
+
+	t.Run("upserts batch articles on document and number", func(t *testing.T) {
+		batch := []entity.LegalArticle{
+			{DocumentID: doc.ID, ArticleNumber: "Art. 2", Title: "Scrutin", Content: "Le scrutin est secret.", Category: "Scrutin"},
+			{DocumentID: doc.ID, ArticleNumber: "Art. 1", Title: "Objet", Content: "La présente loi...", Category: "Principes"},
+		}
+		if err := repos.Legal.BatchCreateArticles(ctx, batch); err != nil {
+			t.Fatalf("batch create: %v", err)
+		}
+		batch[0].Title = "Secret du vote"
+		if err := repos.Legal.BatchCreateArticles(ctx, batch[:1]); err != nil {
+			t.Fatalf("batch upsert: %v", err)
+		}
+
+		articles, err := repos.Legal.GetArticlesByDocument(ctx, doc.ID)
+		if err != nil || len(articles) != 2 {
+			t.Fatalf("articles = %+v, %v", articles, err)
+		}
+		if articles[0].ArticleNumber != "Art. 1" || articles[1].Title != "Secret du vote" {
+			t.Errorf("unexpected articles: %+v", articles)
+		}
+		byCategory, _ := repos.Legal.GetArticlesByCategory(ctx, "Principes")
+		found := false
+		for _, a := range byCategory {
+			found = found || a.ID == articles[0].ID
+		}
+		if !found {
+			t.Error("article missing from its category")
+		}
+	})
+
+	t.Run("searches articles by cosine similarity", func(t *testing.T) {
+		near := &entity.LegalArticle{DocumentID: doc.ID, ArticleNumber: "Art. 10", Title: "Bourrage", Content: "Introduction de bulletins", Category: "Fraude"}
+		far := &entity.LegalArticle{DocumentID: doc.ID, ArticleNumber: "Art. 11", Title: "Campagne", Content: "Durée de campagne", Category: "Campagne"}
+		for _, a := range []*entity.LegalArticle{near, far} {
+			if err := repos.Legal.CreateArticle(ctx, a); err != nil || a.ID == "" {
+				t.Fatalf("create article: %+v, %v", a, err)
+			}
+		}
+		if err := repos.Legal.UpdateArticleEmbedding(ctx, near.ID, []float32{1, 0, 0}); err != nil {
+			t.Fatalf("update embedding: %v", err)
+		}
+		if err := repos.Legal.UpdateArticleEmbedding(ctx, far.ID, []float32{0, 1, 0.5}); err != nil {
+			t.Fatalf("update embedding: %v", err)
+		}
+
+		articles, scores, err := repos.Legal.SemanticSearch(ctx, []float32{1, 0.1, 0}, 2)
+		if err != nil || len(articles) != 2 || len(scores) != 2 {
+			t.Fatalf("search = %d articles, %d scores, %v", len(articles), len(scores), err)
+		}
+		if articles[0].ID != near.ID || articles[1].ID != far.ID {
+			t.Errorf("order = %s, %s; want nearest first", articles[0].ArticleNumber, articles[1].ArticleNumber)
+		}
+		if want := 1 / math.Sqrt(1.01); math.Abs(scores[0]-want) > 1e-4 {
+			t.Errorf("similarity = %f, want %f", scores[0], want)
+		}
+
+		pending, _ := repos.Legal.GetArticlesWithoutEmbedding(ctx)
+		for _, a := range pending {
+			if a.ID == near.ID || a.ID == far.ID {
+				t.Errorf("article %s listed without embedding", a.ArticleNumber)
+			}
+		}
+	})
+
+	t.Run("upserts report matches", func(t *testing.T) {
+		observer := createUser(t, repos, entity.RoleObserver, seededRegionID)
+		report := createReport(t, repos, observer.ID, nil)
+		article := &entity.LegalArticle{DocumentID: doc.ID, ArticleNumber: "Art. 20", Title: "Dépouillement", Content: "Public", Category: "Scrutin"}
+		if err := repos.Legal.CreateArticle(ctx, article); err != nil {
+			t.Fatalf("create article: %v", err)
+		}
+
+		first := &entity.ReportLegalMatch{ReportID: report.ID, ArticleID: article.ID, SimilarityScore: 0.7, MatchType: "auto", Notes: "première analyse"}
+		if err := repos.Legal.CreateReportMatch(ctx, first); err != nil {
+			t.Fatalf("create match: %v", err)
+		}
+		second := &entity.ReportLegalMatch{ReportID: report.ID, ArticleID: article.ID, SimilarityScore: 0.9, MatchType: "auto", Notes: "relance"}
+		if err := repos.Legal.CreateReportMatch(ctx, second); err != nil {
+			t.Fatalf("upsert match: %v", err)
+		}
+		if second.ID != first.ID {
+			t.Errorf("upsert created a new match: %s, want %s", second.ID, first.ID)
+		}
+
+		matches, err := repos.Legal.GetMatchesByReport(ctx, report.ID)
+		if err != nil || len(matches) != 1 {
+			t.Fatalf("matches = %+v, %v", matches, err)
+		}
+		if m := matches[0]; m.SimilarityScore != 0.9 || m.Notes != "relance" || m.ArticleNumber != "Art. 20" || m.ArticleTitle != "Dépouillement" {
+			t.Errorf("unexpected match: %+v", m)
+		}
+		if byArticle, _ := repos.Legal.GetMatchesByArticle(ctx, article.ID); len(byArticle) != 1 || byArticle[0].ReportID != report.ID {
+			t.Errorf("matches by article = %+v", byArticle)
+		}
+
+		if err := repos.Legal.DeleteArticle(ctx, article.ID); err != nil {
+			t.Fatalf("delete article: %v", err)
+		}
+		if left, _ := repos.Legal.GetMatchesByReport(ctx, report.ID); len(left) != 0 {
+			t.Errorf("%d matches left after deleting the article", len(left))
+		}
+	})
+
+	t.Run("keeps one analysis per report and per event", func(t *testing.T) {
+		observer := createUser(t, repos, entity.RoleObserver, seededRegionID)
+		report := createReport(t, repos, observer.ID, nil)
+
+		if _, err := repos.Legal.GetAnalysisByReport(ctx, report.ID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("missing analysis = %v, want sql.ErrNoRows", err)
+		}
+		first := &entity.LegalAnalysis{ReportID: report.ID, Summary: "v1", SeverityLevel: 2, LLMModel: "model-a"}
+		if err := repos.Legal.SaveAnalysis(ctx, first); err != nil {
+			t.Fatalf("save analysis: %v", err)
+		}
+		second := &entity.LegalAnalysis{ReportID: report.ID, Summary: "v2", Recommendation: "Saisir la commission", SeverityLevel: 4, LLMModel: "model-b"}
+		if err := repos.Legal.SaveAnalysis(ctx, second); err != nil {
+			t.Fatalf("save analysis again: %v", err)
+		}
+		got, err := repos.Legal.GetAnalysisByReport(ctx, report.ID)
+		if err != nil || got.ID != first.ID || got.Summary != "v2" || got.SeverityLevel != 4 || got.LLMModel != "model-b" {
+			t.Errorf("analysis = %+v, %v", got, err)
+		}
+
+		event := &entity.IncidentEvent{IncidentType: "STUFF", FirstReportedAt: now(), LastReportedAt: now()}
+		if err := repos.Events.Create(ctx, event); err != nil {
+			t.Fatalf("create event: %v", err)
+		}
+		for _, summary := range []string{"consolidée v1", "consolidée v2"} {
+			if err := repos.Legal.SaveEventAnalysis(ctx, &entity.LegalAnalysis{EventID: event.ID, Summary: summary, SeverityLevel: 5}); err != nil {
+				t.Fatalf("save event analysis: %v", err)
+			}
+		}
+		if got, err := repos.Legal.GetAnalysisByEvent(ctx, event.ID); err != nil || got.Summary != "consolidée v2" || got.EventID != event.ID {
+			t.Errorf("event analysis = %+v, %v", got, err)
+		}
+		if _, err := repos.Legal.GetAnalysisByEvent(ctx, uuid.New().String()); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("missing event analysis = %v, want sql.ErrNoRows", err)
+		}
+	})
+}
@@ -0,0 +1,137 @@
+package repositorytest
+
+import (
+	"context"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/openvote/backend/internal/domain/entity"
+)
+
+func testNotifications(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	user := createUser(t, repos, entity.RoleLocalCoord, seededRegionID)
+
+	t.Run("upserts preferences", func(t *testing.T) {
+		if got, err := repos.Notifications.GetPreferences(ctx, user.ID); got != nil || err != nil {
+			t.Fatalf("preferences before save = %+v, %v; want nil, nil", got, err)
+		}
+		prefs := &entity.NotificationPreferences{UserID: user.ID, Locale: "fr", Email: "coord@example.cm", Channels: []string{"inbox", "email"}, Timezone: "Africa/Douala"}
+		if err := repos.Notifications.SavePreferences(ctx, prefs); err != nil || prefs.UpdatedAt.IsZero() {
+			t.Fatalf("save: %+v, %v", prefs, err)
+		}
+		prefs.Locale, prefs.Phone, prefs.MutedKinds, prefs.QuietHoursStart, prefs.QuietHoursEnd = "en", "+237600000000", []string{"alert.opened"}, "22:00", "06:00"
+		if err := repos.Notifications.SavePreferences(ctx, prefs); err != nil {
+			t.Fatalf("second save: %v", err)
+		}
+		got, err := repos.Notifications.GetPreferences(ctx, user.ID)
+		if err != nil || got == nil {
+			t.Fatalf("get preferences: %+v, %v", got, err)
+		}
+		if got.Locale != "en" || got.Email != "coord@example.cm" || got.Phone != "+237600000000" || got.QuietHoursEnd != "06:00" ||
+			!slices.Equal(got.Channels, []string{"inbox", "email"}) || !slices.Equal(got.MutedKinds, []string{"alert.opened"}) {
+			t.Errorf("unexpected preferences: %+v", got)
+		}
+	})
+
+	t.Run("tracks unread inbox items", func(t *testing.T) {
+		var items []*entity.Notification
+		for _, title := range []string{"Première", "Seconde"} {
+			n := &entity.Notification{UserID: user.ID, Kind: "alert.opened", Title: title, Body: "..."}
+			if err := repos.Notifications.CreateInboxItem(ctx, n); err != nil || n.ID == "" {
+				t.Fatalf("create inbox item: %+v, %v", n, err)
+			}
+			items = append(items, n)
+			time.Sleep(2 * time.Millisecond) // Ordre de création distinct
+		}
+		if n, err := repos.Notifications.CountUnread(ctx, user.ID); err != nil || n != 2 {
+			t.Fatalf("unread = %d, %v; want 2", n, err)
+		}
+		inbox, _ := repos.Notifications.ListInbox(ctx, user.ID, false, 10)
+		if len(inbox) != 2 || inbox[0].ID != items[1].ID {
+			t.Errorf("inbox = %+v, want the newest first", inbox)
+		}
+
+		if n, err := repos.Notifications.MarkRead(ctx, user.ID, items[0].ID); err != nil || n != 1 {
+			t.Errorf("mark one read = %d, %v", n, err)
+		}
+		if n, _ := repos.Notifications.MarkRead(ctx, uuid.New().String(), items[1].ID); n != 0 {
+			t.Errorf("another user marked %d items read", n)
+		}
+		if unread, _ := repos.Notifications.ListInbox(ctx, user.ID, true, 10); len(unread) != 1 || unread[0].ID != items[1].ID || unread[0].ReadAt != nil {
+			t.Errorf("unread inbox = %+v", unread)
+		}
+		if n, _ := repos.Notifications.MarkRead(ctx, user.ID, ""); n != 1 {
+			t.Errorf("mark all read = %d, want 1", n)
+		}
+		if n, _ := repos.Notifications.CountUnread(ctx, user.ID); n != 0 {
+			t.Errorf("unread after mark all = %d", n)
+		}
+	})
+
+	t.Run("deduplicates and delivers", func(t *testing.T) {
+		d := &entity.NotificationDelivery{UserID: user.ID, Channel: "email", Kind: "alert.opened", DedupKey: "alert-1", Recipient: "coord@example.cm", Subject: "Alerte", Body: "..."}
+		created, err := repos.Notifications.CreateDelivery(ctx, d)
+		if err != nil || !created || d.ID == "" || d.Status != entity.NotificationPending {
+			t.Fatalf("create delivery = %v, %v (%+v)", created, err, d)
+		}
+		again := &entity.NotificationDelivery{UserID: user.ID, Channel: "email", Kind: "alert.opened", DedupKey: "alert-1", Body: "..."}
+		if created, err := repos.Notifications.CreateDelivery(ctx, again); err != nil || created {
+			t.Errorf("duplicate delivery = %v, %v; want ignored", created, err)
+		}
+		sms := &entity.NotificationDelivery{UserID: user.ID, Channel: "sms", Kind: "alert.opened", DedupKey: "alert-1", Body: "...",
+			NextAttemptAt: now().Add(time.Hour)} // Reporté après les heures calmes
+		if created, err := repos.Notifications.CreateDelivery(ctx, sms); err != nil || !created {
+			t.Fatalf("same key on another channel = %v, %v; want created", created, err)
+		}
+
+		claimed, err := repos.Notifications.ClaimDueDeliveries(ctx, 10, time.Minute)
+		if err != nil || len(claimed) != 1 || claimed[0].ID != d.ID || claimed[0].Attempts != 1 {
+			t.Fatalf("claim = %+v, %v; want only the due e-mail", claimed, err)
+		}
+		if none, _ := repos.Notifications.ClaimDueDeliveries(ctx, 10, time.Minute); len(none) != 0 {
+			t.Errorf("leased deliveries claimed again: %d", len(none))
+		}
+
+		retryAt := now().Add(-time.Second)
+		if err := repos.Notifications.MarkFailed(ctx, d.ID, "smtp timeout", &retryAt); err != nil {
+			t.Fatalf("mark failed: %v", err)
+		}
+		retried, _ := repos.Notifications.ClaimDueDeliveries(ctx, 10, time.Minute)
+		if len(retried) != 1 || retried[0].Attempts != 2 || retried[0].LastError != "smtp timeout" {
+			t.Errorf("retried = %+v", retried)
+		}
+		if err := repos.Notifications.MarkSent(ctx, d.ID); err != nil {
+			t.Fatalf("mark sent: %v", err)
+		}
+		sent, _ := repos.Notifications.ListDeliveries(ctx, string(entity.NotificationSent), 10)
+		if len(sent) != 1 || sent[0].ID != d.ID || sent[0].SentAt == nil || sent[0].LastError != "" {
+			t.Errorf("sent deliveries = %+v", sent)
+		}
+	})
+
+	t.Run("abandons and replays", func(t *testing.T) {
+		d := &entity.NotificationDelivery{UserID: user.ID, Channel: "sms", Kind: "report.rejected", DedupKey: "report-1", Body: "..."}
+		if _, err := repos.Notifications.CreateDelivery(ctx, d); err != nil {
+			t.Fatalf("create delivery: %v", err)
+		}
+		if err := repos.Notifications.MarkFailed(ctx, d.ID, "invalid number", nil); err != nil {
+			t.Fatalf("mark failed: %v", err)
+		}
+		if failed, _ := repos.Notifications.ListDeliveries(ctx, string(entity.NotificationFailed), 10); len(failed) != 1 || failed[0].ID != d.ID {
+			t.Errorf("failed deliveries = %+v", failed)
+		}
+		if ok, err := repos.Notifications.ResetDelivery(ctx, d.ID); err != nil || !ok {
+			t.Fatalf("reset = %v, %v", ok, err)
+		}
+		claimed, _ := repos.Notifications.ClaimDueDeliveries(ctx, 10, time.Minute)
+		if len(claimed) != 1 || claimed[0].ID != d.ID || claimed[0].Attempts != 1 || claimed[0].LastError != "" {
+			t.Errorf("replayed delivery = %+v", claimed)
+		}
+		if ok, err := repos.Notifications.ResetDelivery(ctx, uuid.New().String()); err != nil || ok {
+			t.Errorf("reset unknown = %v, %v; want false", ok, err)
+		}
+	})
+}
@@ -0,0 +1,70 @@
+package repositorytest
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+)
+
+func testOutbox(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	traceparent := "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+	first := &entity.OutboxMessage{Queue: "reports", Payload: json.RawMessage(`{"n": 1}`), Headers: map[string]string{"traceparent": traceparent}}
+	if err := repos.Outbox.Enqueue(ctx, first); err != nil || first.ID == "" {
+		t.Fatalf("enqueue: %+v, %v", first, err)
+	}
+	time.Sleep(2 * time.Millisecond) // Ordre de création distinct
+	second := &entity.OutboxMessage{Topic: "report.created", Payload: json.RawMessage(`{"n": 2}`)}
+	if err := repos.Outbox.Enqueue(ctx, second); err != nil {
+		t.Fatalf("enqueue: %v", err)
+	}
+
+	t.Run("claims the oldest messages under a lease", func(t *testing.T) {
+		claimed, err := repos.Outbox.Claim(ctx, 1, time.Minute)
+		if err != nil || len(claimed) != 1 || claimed[0].ID != first.ID || claimed[0].Attempts != 1 || claimed[0].Queue != "reports" {
+			t.Fatalf("first claim = %+v, %v", claimed, err)
+		}
+		var body struct{ N int }
+		if err := json.Unmarshal(claimed[0].Payload, &body); err != nil || body.N != 1 {
+			t.Errorf("payload = %s", claimed[0].Payload)
+		}
+		if claimed[0].Headers["traceparent"] != traceparent {
+			t.Errorf("headers = %v", claimed[0].Headers)
+		}
+		if next, _ := repos.Outbox.Claim(ctx, 10, time.Minute); len(next) != 1 || next[0].ID != second.ID || next[0].Topic != "report.created" {
+			t.Errorf("second claim = %+v", next)
+		}
+		if none, _ := repos.Outbox.Claim(ctx, 10, time.Minute); len(none) != 0 {
+			t.Errorf("leased messages claimed again: %+v", none)
+		}
+	})
+
+	t.Run("makes failed messages available again", func(t *testing.T) {
+		if err := repos.Outbox.MarkFailed(ctx, first.ID, "broker unavailable", 0); err != nil {
+			t.Fatalf("mark failed: %v", err)
+		}
+		retried, err := repos.Outbox.Claim(ctx, 10, time.Minute)
+		if err != nil || len(retried) != 1 || retried[0].ID != first.ID {
+			t.Fatalf("retry claim = %+v, %v", retried, err)
+		}
+		if retried[0].Attempts != 2 || retried[0].LastError != "broker unavailable" {
+			t.Errorf("retried message = %+v", retried[0])
+		}
+	})
+
+	t.Run("purges sent messages after the retention", func(t *testing.T) {
+		if err := repos.Outbox.MarkSent(ctx, first.ID); err != nil {
+			t.Fatalf("mark sent: %v", err)
+		}
+		if n, err := repos.Outbox.PurgeSent(ctx, time.Hour); err != nil || n != 0 {
+			t.Errorf("purge within retention = %d, %v; want 0", n, err)
+		}
+		time.Sleep(5 * time.Millisecond)
+		if n, err := repos.Outbox.PurgeSent(ctx, time.Millisecond); err != nil || n != 1 {
+			t.Errorf("purge after retention = %d, %v; want 1", n, err)
+		}
+	})
+}
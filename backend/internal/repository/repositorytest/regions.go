@@ -0,0 +1,154 @@
+package repositorytest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/openvote/backend/internal/domain/entity"
+)
+
+func testRegions(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	t.Run("loads the reference regions", func(t *testing.T) {
+		region, err := repos.Regions.GetRegionByID(ctx, seededRegionID)
+		if err != nil || region == nil || region.Code != "LT" {
+			t.Fatalf("seeded region = %+v, %v", region, err)
+		}
+		regions, _ := repos.Regions.GetAllRegions(ctx)
+		if len(regions) < 10 {
+			t.Errorf("%d regions, want the 10 reference regions", len(regions))
+		}
+		for i := 1; i < len(regions); i++ {
+			if regions[i-1].Name > regions[i].Name {
+				t.Errorf("regions not ordered by name: %q before %q", regions[i-1].Name, regions[i].Name)
+			}
+		}
+		if missing, err := repos.Regions.GetRegionByID(ctx, uuid.New().String()); missing != nil || err != nil {
+			t.Errorf("unknown region = %+v, %v; want nil, nil", missing, err)
+		}
+	})
+
+	t.Run("enforces unique codes", func(t *testing.T) {
+		region := &entity.Region{Name: "Région test", Code: "RT"}
+		if err := repos.Regions.CreateRegion(ctx, region); err != nil || region.ID == "" {
+			t.Fatalf("create region: %+v, %v", region, err)
+		}
+		if err := repos.Regions.CreateRegion(ctx, &entity.Region{Name: "Autre région", Code: "RT"}); err == nil {
+			t.Error("duplicate region code accepted")
+		}
+		if err := repos.Regions.UpdateRegion(ctx, region.ID, "Région renommée", "RR"); err != nil {
+			t.Fatalf("update region: %v", err)
+		}
+		if got, _ := repos.Regions.GetRegionByID(ctx, region.ID); got == nil || got.Name != "Région renommée" || got.Code != "RR" {
+			t.Errorf("region after update = %+v", got)
+		}
+		if err := repos.Regions.UpdateRegion(ctx, uuid.New().String(), "x", "XX"); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("update unknown region = %v, want sql.ErrNoRows", err)
+		}
+	})
+
+	t.Run("deleting a region cascades to departments and stations", func(t *testing.T) {
+		region := &entity.Region{Name: "Région éphémère", Code: "EPH"}
+		if err := repos.Regions.CreateRegion(ctx, region); err != nil {
+			t.Fatalf("create region: %v", err)
+		}
+		dept := &entity.Department{Name: "Département éphémère", Code: "EPH-1", RegionID: region.ID, Population: 1000, RegisteredVoters: 400}
+		if err := repos.Regions.CreateDepartment(ctx, dept); err != nil || dept.ID == "" {
+			t.Fatalf("create department: %+v, %v", dept, err)
+		}
+		if _, err := repos.Stations.Upsert(ctx, &entity.PollingStation{Code: "EPH-BV-1", Name: "École", DepartmentID: dept.ID}); err != nil {
+			t.Fatalf("upsert station: %v", err)
+		}
+
+		depts, _ := repos.Regions.GetDepartmentsByRegion(ctx, region.ID)
+		if len(depts) != 1 || depts[0].ID != dept.ID || depts[0].RegisteredVoters != 400 {
+			t.Fatalf("departments = %+v", depts)
+		}
+		if err := repos.Regions.UpdateDepartment(ctx, dept.ID, "Département renommé", "EPH-1", region.ID, 2000, 800); err != nil {
+			t.Fatalf("update department: %v", err)
+		}
+		if depts, _ := repos.Regions.GetDepartmentsByRegion(ctx, region.ID); len(depts) != 1 || depts[0].Population != 2000 || depts[0].Name != "Département renommé" {
+			t.Errorf("department after update = %+v", depts)
+		}
+		if err := repos.Regions.UpdateDepartment(ctx, uuid.New().String(), "x", "X-1", region.ID, 0, 0); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("update unknown department = %v, want sql.ErrNoRows", err)
+		}
+
+		if err := repos.Regions.DeleteRegion(ctx, region.ID); err != nil {
+			t.Fatalf("delete region: %v", err)
+		}
+		if depts, _ := repos.Regions.GetDepartmentsByRegion(ctx, region.ID); len(depts) != 0 {
+			t.Errorf("%d departments left", len(depts))
+		}
+		if stations, _ := repos.Stations.GetByDepartment(ctx, dept.ID); len(stations) != 0 {
+			t.Errorf("%d stations left", len(stations))
+		}
+	})
+}
+
+func testPollingStations(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	dept := &entity.Department{Name: "Wouri test", Code: "WOU-T", RegionID: seededRegionID}
+	if err := repos.Regions.CreateDepartment(ctx, dept); err != nil {
+		t.Fatalf("create department: %v", err)
+	}
+
+	t.Run("upserts on the station code", func(t *testing.T) {
+		lat, lon := 4.0511, 9.7679
+		station := &entity.PollingStation{Code: "WOU-T-001", Name: "École publique", DepartmentID: dept.ID, Address: "Akwa", Latitude: &lat, Longitude: &lon, RegisteredVoters: 350}
+		created, err := repos.Stations.Upsert(ctx, station)
+		if err != nil || !created || station.ID == "" {
+			t.Fatalf("first upsert = %v, %v (%+v)", created, err, station)
+		}
+		id, createdAt := station.ID, station.CreatedAt
+
+		updated := &entity.PollingStation{Code: "WOU-T-001", Name: "École publique d'Akwa", DepartmentID: dept.ID, RegisteredVoters: 420}
+		created, err = repos.Stations.Upsert(ctx, updated)
+		if err != nil || created {
+			t.Fatalf("second upsert = %v, %v; want an update", created, err)
+		}
+		if updated.ID != id || !updated.CreatedAt.Equal(createdAt) {
+			t.Errorf("upsert changed identity: %s/%v, want %s/%v", updated.ID, updated.CreatedAt, id, createdAt)
+		}
+
+		stations, err := repos.Stations.GetByDepartment(ctx, dept.ID)
+		if err != nil || len(stations) != 1 {
+			t.Fatalf("stations = %+v, %v", stations, err)
+		}
+		got := stations[0]
+		if got.Name != "École publique d'Akwa" || got.RegisteredVoters != 420 || got.Address != "" {
+			t.Errorf("station after upsert = %+v", got)
+		}
+		if got.Latitude != nil || got.Longitude != nil {
+			t.Errorf("coordinates should be cleared, got %v/%v", got.Latitude, got.Longitude)
+		}
+	})
+
+	t.Run("lists stations ordered by code", func(t *testing.T) {
+		lat, lon := 4.06, 9.70
+		for _, code := range []string{"WOU-T-003", "WOU-T-002"} {
+			if _, err := repos.Stations.Upsert(ctx, &entity.PollingStation{Code: code, Name: code, DepartmentID: dept.ID, Latitude: &lat, Longitude: &lon}); err != nil {
+				t.Fatalf("upsert %s: %v", code, err)
+			}
+		}
+		stations, _ := repos.Stations.GetByDepartment(ctx, dept.ID)
+		var codes []string
+		for _, s := range stations {
+			codes = append(codes, s.Code)
+		}
+		if len(codes) != 3 || codes[0] != "WOU-T-001" || codes[1] != "WOU-T-002" || codes[2] != "WOU-T-003" {
+			t.Errorf("codes = %v", codes)
+		}
+		if s := stations[1]; s.Latitude == nil || *s.Latitude != lat || *s.Longitude != lon {
+			t.Errorf("coordinates not round-tripped: %+v", s)
+		}
+		all, _ := repos.Stations.GetAll(ctx)
+		if len(all) < 3 {
+			t.Errorf("get all = %d stations", len(all))
+		}
+	})
+}
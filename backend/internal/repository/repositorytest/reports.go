@@ -0,0 +1,200 @@
+package repositorytest
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/openvote/backend/internal/domain/entity"
+)
+
+func testReports(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	observer := createUser(t, repos, entity.RoleObserver, seededRegionID)
+
+	t.Run("round-trips the WKT location and joins the observer region", func(t *testing.T) {
+		r := createReport(t, repos, observer.ID, func(r *entity.Report) {
+			r.GPSLocation = "POINT(11.5021 3.8480)"
+			r.Description = "Urne ouverte avant le dépouillement"
+			r.Severity = 5
+		})
+		got, err := repos.Reports.GetByID(ctx, r.ID)
+		if err != nil || got == nil {
+			t.Fatalf("get by id: %+v, %v", got, err)
+		}
+		if got.GPSLocation != "POINT(11.5021 3.848)" {
+			t.Errorf("gps_location = %q, want POINT(11.5021 3.848)", got.GPSLocation)
+		}
+		if got.RegionID != seededRegionID || got.Severity != 5 || got.Description != r.Description || got.Status != entity.StatusPending {
+			t.Errorf("unexpected report: %+v", got)
+		}
+		if !got.CreatedAt.Equal(r.CreatedAt) {
+			t.Errorf("created_at = %v, want %v", got.CreatedAt, r.CreatedAt)
+		}
+		if missing, err := repos.Reports.GetByID(ctx, uuid.New().String()); missing != nil || err != nil {
+			t.Errorf("unknown report = %+v, %v; want nil, nil", missing, err)
+		}
+	})
+
+	t.Run("writes outbox messages with the report or not at all", func(t *testing.T) {
+		r := &entity.Report{ID: uuid.New().String(), ObserverID: observer.ID, IncidentType: "INTIM", GPSLocation: "POINT(9.7 4.05)", H3Index: "outbox-cell",
+			Status: entity.StatusPending, Severity: 4, CreatedAt: now()}
+		msg := &entity.OutboxMessage{Topic: "report.created", Payload: json.RawMessage(`{"report_id": "` + r.ID + `"}`)}
+		if err := repos.Reports.CreateWithOutbox(ctx, r, msg); err != nil {
+			t.Fatalf("create with outbox: %v", err)
+		}
+		claimed, err := repos.Outbox.Claim(ctx, 10, time.Minute)
+		if err != nil || len(claimed) != 1 {
+			t.Fatalf("claim = %d messages, %v; want 1", len(claimed), err)
+		}
+		var payload map[string]string
+		if err := json.Unmarshal(claimed[0].Payload, &payload); err != nil || payload["report_id"] != r.ID || claimed[0].Topic != "report.created" {
+			t.Errorf("unexpected message: %+v", claimed[0])
+		}
+
+		// Identifiant déjà pris : ni le signalement ni le message ne sont écrits
+		dup := *r
+		if err := repos.Reports.CreateWithOutbox(ctx, &dup, &entity.OutboxMessage{Topic: "report.created", Payload: json.RawMessage(`{}`)}); err == nil {
+			t.Fatal("duplicate report accepted")
+		}
+		if again, _ := repos.Outbox.Claim(ctx, 10, time.Minute); len(again) != 0 {
+			t.Errorf("outbox message written for a rejected report: %+v", again)
+		}
+	})
+
+	t.Run("writes outbox messages with the status change", func(t *testing.T) {
+		r := createReport(t, repos, observer.ID, nil)
+		msg := &entity.OutboxMessage{Topic: "report.status_changed", Payload: json.RawMessage(`{"report_id": "` + r.ID + `"}`)}
+		if err := repos.Reports.UpdateStatusWithOutbox(ctx, r.ID, entity.StatusRejected, msg); err != nil {
+			t.Fatalf("update status with outbox: %v", err)
+		}
+		if got, _ := repos.Reports.GetByID(ctx, r.ID); got == nil || got.Status != entity.StatusRejected {
+			t.Errorf("status = %+v, want rejected", got)
+		}
+		claimed, err := repos.Outbox.Claim(ctx, 10, time.Minute)
+		if err != nil || len(claimed) != 1 || claimed[0].Topic != "report.status_changed" {
+			t.Fatalf("claim = %+v, %v; want the status message", claimed, err)
+		}
+
+		// Signalement inconnu : aucun message n'est écrit
+		if err := repos.Reports.UpdateStatusWithOutbox(ctx, uuid.New().String(), entity.StatusRejected, msg); err != nil {
+			t.Fatalf("update unknown report: %v", err)
+		}
+		if again, _ := repos.Outbox.Claim(ctx, 10, time.Minute); len(again) != 0 {
+			t.Errorf("outbox message written for an unknown report: %+v", again)
+		}
+	})
+
+	t.Run("lists by status, newest first", func(t *testing.T) {
+		older := createReport(t, repos, observer.ID, func(r *entity.Report) { r.CreatedAt = now().Add(-time.Hour) })
+		newer := createReport(t, repos, observer.ID, nil)
+		if err := repos.Reports.UpdateStatus(ctx, older.ID, entity.StatusVerified); err != nil {
+			t.Fatalf("update status: %v", err)
+		}
+
+		verified, err := repos.Reports.GetAll(ctx, string(entity.StatusVerified))
+		if err != nil {
+			t.Fatalf("get all: %v", err)
+		}
+		if len(verified) != 1 || verified[0].ID != older.ID {
+			t.Errorf("verified reports = %+v", verified)
+		}
+
+		all, _ := repos.Reports.GetAll(ctx, "")
+		var ids []string
+		for _, r := range all {
+			if r.ID == older.ID || r.ID == newer.ID {
+				ids = append(ids, r.ID)
+			}
+		}
+		if !slices.Equal(ids, []string{newer.ID, older.ID}) {
+			t.Errorf("order = %v, want newest first", ids)
+		}
+	})
+
+	t.Run("finds nearby reports with the author role", func(t *testing.T) {
+		author := &entity.User{ID: uuid.New().String(), Username: "nearby-" + uuid.New().String()[:8], Role: entity.RoleVerifiedCitizen, PasswordHash: "hash",
+			RegionID: seededRegionID, ActivationTokenHash: "token-hash", CreatedAt: now(), UpdatedAt: now()}
+		if err := repos.Users.Create(ctx, author); err != nil {
+			t.Fatalf("create author: %v", err)
+		}
+		at := now()
+		place := func(h3, wkt string, createdAt time.Time) string {
+			return createReport(t, repos, author.ID, func(r *entity.Report) {
+				r.H3Index, r.GPSLocation, r.CreatedAt = h3, wkt, createdAt
+			}).ID
+		}
+		sameCell := place("nearby-cell", "POINT(13.39 9.30)", at)
+		inRadius := place("elsewhere", "POINT(10.4175 5.4781)", at) // ~120 m
+		place("elsewhere", "POINT(13.39 9.30)", at)
+		place("nearby-cell", "POINT(10.4164 5.4781)", at.Add(-3*time.Hour))
+
+		got, err := repos.Reports.FindNearbyWithRole(ctx, "nearby-cell", 5.4781, 10.4164, 500, at.Add(-time.Hour), at.Add(time.Hour))
+		if err != nil {
+			t.Fatalf("find nearby: %v", err)
+		}
+		var ids []string
+		for _, r := range got {
+			if r.ObserverID != author.ID {
+				continue
+			}
+			ids = append(ids, r.ID)
+			if r.AuthorRole != entity.RoleVerifiedCitizen || r.AuthorTokenHash != "token-hash" {
+				t.Errorf("author not joined on %s: role %q token %q", r.ID, r.AuthorRole, r.AuthorTokenHash)
+			}
+		}
+		slices.Sort(ids)
+		want := []string{sameCell, inRadius}
+		slices.Sort(want)
+		if !slices.Equal(ids, want) {
+			t.Errorf("nearby = %v, want %v", ids, want)
+		}
+	})
+
+	t.Run("orders the review queue by severity, corroboration and age", func(t *testing.T) {
+		at := now().Add(-90 * time.Minute)
+		minor := createReport(t, repos, observer.ID, func(r *entity.Report) { r.Severity, r.CreatedAt = 1, at })
+		alone := createReport(t, repos, observer.ID, func(r *entity.Report) { r.Severity, r.CreatedAt = 5, at })
+		corroborated := createReport(t, repos, observer.ID, func(r *entity.Report) { r.Severity, r.CreatedAt = 5, at.Add(30*time.Minute) })
+		witness := createReport(t, repos, observer.ID, func(r *entity.Report) { r.Severity, r.CreatedAt = 1, at })
+		if err := repos.Reports.UpdateStatus(ctx, witness.ID, entity.StatusVerified); err != nil {
+			t.Fatalf("update status: %v", err)
+		}
+		event := &entity.IncidentEvent{IncidentType: "STUFF", FirstReportedAt: at, LastReportedAt: at}
+		if err := repos.Events.Create(ctx, event); err != nil {
+			t.Fatalf("create event: %v", err)
+		}
+		if err := repos.Events.AttachReports(ctx, event.ID, []string{corroborated.ID, witness.ID}); err != nil {
+			t.Fatalf("attach: %v", err)
+		}
+
+		queue, err := repos.Reports.GetReviewQueue(ctx, 100)
+		if err != nil {
+			t.Fatalf("review queue: %v", err)
+		}
+		mine := map[string]bool{minor.ID: true, alone.ID: true, corroborated.ID: true, witness.ID: true}
+		var ids []string
+		for _, item := range queue {
+			if !mine[item.ID] {
+				continue
+			}
+			ids = append(ids, item.ID)
+			switch item.ID {
+			case corroborated.ID:
+				if item.Corroboration != 1 || item.EventID != event.ID {
+					t.Errorf("corroborated item = %+v", item)
+				}
+			case alone.ID:
+				if item.Corroboration != 0 || item.EventID != "" || item.AgeMinutes < 89 {
+					t.Errorf("alone item = %+v", item)
+				}
+			}
+		}
+		if !slices.Equal(ids, []string{corroborated.ID, alone.ID, minor.ID}) {
+			t.Errorf("queue order = %v, want corroborated, alone, minor", ids)
+		}
+	})
+}
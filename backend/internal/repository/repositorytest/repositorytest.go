@@ -0,0 +1,117 @@
+// Package repositorytest contient la suite de contrat commune aux implémentations de domain/repository.
+//
+// Chaque backend (memory, postgres) l'exécute depuis ses propres tests avec une Factory qui fournit
+// des repositories vierges partageant le même stockage. Le stockage doit contenir les données de
+// référence des migrations (régions, types d'incidents) ; les autres données insérées par les
+// migrations (documents juridiques, règles d'alerte) sont tolérées : les vérifications ne portent
+// que sur les lignes créées par le test.
+package repositorytest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+)
+
+// Repositories regroupe les implémentations testées ; toutes partagent le même stockage
+// (les jointures entre signalements, utilisateurs et événements sont vérifiées)
+type Repositories struct {
+	Users         repository.UserRepository
+	Reports       repository.ReportRepository
+	Regions       repository.RegionRepository
+	Stations      repository.PollingStationRepository
+	Elections     repository.ElectionRepository
+	AuditLogs     repository.AuditLogRepository
+	IncidentTypes repository.IncidentTypeRepository
+	Legal         repository.LegalRepository
+	Clusters      repository.SuspiciousClusterRepository
+	Conflicts     repository.ConflictRepository
+	Events        repository.IncidentEventRepository
+	Outbox        repository.OutboxRepository
+	Webhooks      repository.WebhookRepository
+	Alerts        repository.AlertRepository
+	Notifications repository.NotificationRepository
+	Config        repository.ConfigRepository
+}
+
+// Factory retourne des repositories sur un stockage isolé, libéré par t.Cleanup
+type Factory func(t *testing.T) Repositories
+
+// Identifiants des données de référence insérées par les migrations
+const (
+	seededRegionID = "a1000001-0000-0000-0000-000000000005" // Littoral
+	otherRegionID  = "a1000001-0000-0000-0000-000000000002" // Centre
+)
+
+// Run exécute la suite de contrat ; chaque sous-test reçoit un stockage neuf
+func Run(t *testing.T, newRepos Factory) {
+	suites := []struct {
+		name string
+		run  func(t *testing.T, repos Repositories)
+	}{
+		{"users", testUsers},
+		{"reports", testReports},
+		{"regions", testRegions},
+		{"polling_stations", testPollingStations},
+		{"elections", testElections},
+		{"audit_logs", testAuditLogs},
+		{"incident_types", testIncidentTypes},
+		{"legal", testLegal},
+		{"suspicious_clusters", testSuspiciousClusters},
+		{"conflicts", testConflicts},
+		{"incident_events", testIncidentEvents},
+		{"outbox", testOutbox},
+		{"webhooks", testWebhooks},
+		{"alerts", testAlerts},
+		{"notifications", testNotifications},
+		{"config", testConfig},
+	}
+	for _, s := range suites {
+		t.Run(s.name, func(t *testing.T) {
+			s.run(t, newRepos(t))
+		})
+	}
+}
+
+// now retourne l'heure courante à la précision des colonnes TIMESTAMPTZ
+func now() time.Time {
+	return time.Now().UTC().Truncate(time.Microsecond)
+}
+
+// createUser insère un utilisateur au nom unique ; postgres exige un identifiant UUID fourni
+func createUser(t *testing.T, repos Repositories, role entity.UserRole, regionID string) *entity.User {
+	t.Helper()
+	id := uuid.New().String()
+	at := now()
+	u := &entity.User{ID: id, Username: "contract-" + id[:8], Role: role, PasswordHash: "hash", RegionID: regionID, CreatedAt: at, UpdatedAt: at}
+	if err := repos.Users.Create(context.Background(), u); err != nil {
+		t.Fatalf("create user: %v", err)
+	}
+	return u
+}
+
+// createReport insère un signalement en attente de l'observateur ; fill complète les champs du test
+func createReport(t *testing.T, repos Repositories, observerID string, fill func(r *entity.Report)) *entity.Report {
+	t.Helper()
+	r := &entity.Report{
+		ID:           uuid.New().String(),
+		ObserverID:   observerID,
+		IncidentType: "STUFF",
+		GPSLocation:  "POINT(9.7679 4.0511)",
+		H3Index:      "8a6a1a1a1a1ffff",
+		Status:       entity.StatusPending,
+		Severity:     3,
+		CreatedAt:    now(),
+	}
+	if fill != nil {
+		fill(r)
+	}
+	if err := repos.Reports.Create(context.Background(), r); err != nil {
+		t.Fatalf("create report: %v", err)
+	}
+	return r
+}
@@ -0,0 +1,194 @@
+package repositorytest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/openvote/backend/internal/domain/entity"
+)
+
+func testSuspiciousClusters(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	observer := createUser(t, repos, entity.RoleObserver, seededRegionID)
+	report := createReport(t, repos, observer.ID, nil)
+	related := createReport(t, repos, observer.ID, nil)
+	reviewer := uuid.New().String()
+
+	t.Run("upserts on the report and keeps the review", func(t *testing.T) {
+		c := &entity.SuspiciousCluster{ReportID: report.ID, RelatedReportIDs: []string{related.ID}, Signals: []string{"device"}, Score: 0.6}
+		if err := repos.Clusters.Upsert(ctx, c); err != nil || c.ID == "" || c.Status != entity.ClusterOpen {
+			t.Fatalf("upsert: %+v, %v", c, err)
+		}
+		if err := repos.Clusters.UpdateStatus(ctx, c.ID, entity.ClusterConfirmed, reviewer, "collusion avérée"); err != nil {
+			t.Fatalf("update status: %v", err)
+		}
+
+		refreshed := &entity.SuspiciousCluster{ReportID: report.ID, RelatedReportIDs: []string{related.ID}, Signals: []string{"device", "source"}, Score: 0.9}
+		if err := repos.Clusters.Upsert(ctx, refreshed); err != nil {
+			t.Fatalf("second upsert: %v", err)
+		}
+		if refreshed.ID != c.ID || refreshed.Status != entity.ClusterConfirmed {
+			t.Errorf("upsert reset the cluster: %+v", refreshed)
+		}
+
+		got, err := repos.Clusters.GetByID(ctx, c.ID)
+		if err != nil || got == nil {
+			t.Fatalf("get by id: %+v, %v", got, err)
+		}
+		if got.Score != 0.9 || !slices.Equal(got.Signals, []string{"device", "source"}) || !slices.Equal(got.RelatedReportIDs, []string{related.ID}) {
+			t.Errorf("evidence not refreshed: %+v", got)
+		}
+		if got.ReviewedBy != reviewer || got.ReviewNote != "collusion avérée" || got.ReviewedAt == nil {
+			t.Errorf("review lost: %+v", got)
+		}
+	})
+
+	t.Run("finds clusters by target or member report", func(t *testing.T) {
+		for _, id := range []string{report.ID, related.ID} {
+			found, err := repos.Clusters.FindByReport(ctx, id)
+			if err != nil || len(found) != 1 || found[0].ReportID != report.ID {
+				t.Errorf("find by report %s = %+v, %v", id, found, err)
+			}
+		}
+		if found, err := repos.Clusters.FindByReport(ctx, uuid.New().String()); err != nil || len(found) != 0 {
+			t.Errorf("find by unknown report = %+v, %v", found, err)
+		}
+	})
+
+	t.Run("filters by status", func(t *testing.T) {
+		open, err := repos.Clusters.GetAll(ctx, string(entity.ClusterOpen))
+		if err != nil {
+			t.Fatalf("get all: %v", err)
+		}
+		for _, c := range open {
+			if c.ReportID == report.ID {
+				t.Error("confirmed cluster listed as open")
+			}
+		}
+		if confirmed, _ := repos.Clusters.GetAll(ctx, string(entity.ClusterConfirmed)); len(confirmed) != 1 {
+			t.Errorf("%d confirmed clusters, want 1", len(confirmed))
+		}
+	})
+
+	t.Run("reopens a reviewed cluster joined by new members", func(t *testing.T) {
+		target := createReport(t, repos, observer.ID, nil)
+		member := createReport(t, repos, observer.ID, nil)
+		newcomer := createReport(t, repos, observer.ID, nil)
+		c := &entity.SuspiciousCluster{ReportID: target.ID, RelatedReportIDs: []string{target.ID, member.ID}, Signals: []string{"device"}, Score: 0.6}
+		if err := repos.Clusters.Upsert(ctx, c); err != nil {
+			t.Fatalf("upsert: %v", err)
+		}
+		if err := repos.Clusters.UpdateStatus(ctx, c.ID, entity.ClusterDismissed, reviewer, "faux positif"); err != nil {
+			t.Fatalf("update status: %v", err)
+		}
+
+		grown := &entity.SuspiciousCluster{ReportID: target.ID, RelatedReportIDs: []string{target.ID, member.ID, newcomer.ID}, Signals: []string{"device"}, Score: 0.8}
+		if err := repos.Clusters.Upsert(ctx, grown); err != nil {
+			t.Fatalf("second upsert: %v", err)
+		}
+		got, _ := repos.Clusters.GetByID(ctx, c.ID)
+		if grown.ID != c.ID || grown.Status != entity.ClusterOpen || got.Status != entity.ClusterOpen || got.ReviewedBy != "" || got.ReviewedAt != nil {
+			t.Errorf("grown cluster not reopened: %+v", got)
+		}
+	})
+
+	t.Run("reports missing clusters", func(t *testing.T) {
+		if got, err := repos.Clusters.GetByID(ctx, uuid.New().String()); got != nil || err != nil {
+			t.Errorf("unknown cluster = %+v, %v; want nil, nil", got, err)
+		}
+		if err := repos.Clusters.UpdateStatus(ctx, uuid.New().String(), entity.ClusterDismissed, reviewer, ""); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("update unknown cluster = %v, want sql.ErrNoRows", err)
+		}
+	})
+}
+
+func testConflicts(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	observer := createUser(t, repos, entity.RoleObserver, seededRegionID)
+	coordinator := createUser(t, repos, entity.RoleLocalCoord, seededRegionID)
+	r1 := createReport(t, repos, observer.ID, nil)
+	r2 := createReport(t, repos, observer.ID, func(r *entity.Report) { r.IncidentType = "VIOLE" })
+	r3 := createReport(t, repos, observer.ID, func(r *entity.Report) { r.IncidentType = "INTIM" })
+
+	c := &entity.Conflict{H3Index: "conflict-cell", IncidentTypes: []string{"VIOLE", "STUFF"}, ReportIDs: []string{r1.ID, r2.ID}}
+	if err := repos.Conflicts.Create(ctx, c); err != nil || c.ID == "" || c.Status != entity.ConflictOpen {
+		t.Fatalf("create: %+v, %v", c, err)
+	}
+
+	t.Run("finds the open conflict of a report", func(t *testing.T) {
+		found, err := repos.Conflicts.FindOpenByReports(ctx, []string{r2.ID, uuid.New().String()})
+		if err != nil || found == nil || found.ID != c.ID {
+			t.Fatalf("find open = %+v, %v", found, err)
+		}
+		want := []string{r1.ID, r2.ID}
+		slices.Sort(want)
+		if !slices.Equal(found.ReportIDs, want) {
+			t.Errorf("report ids = %v, want %v", found.ReportIDs, want)
+		}
+		if open, _ := repos.Conflicts.HasOpenConflict(ctx, r1.ID); !open {
+			t.Error("r1 should be in an open conflict")
+		}
+		if open, _ := repos.Conflicts.HasOpenConflict(ctx, r3.ID); open {
+			t.Error("r3 should not be in a conflict")
+		}
+		if none, err := repos.Conflicts.FindOpenByReports(ctx, []string{r3.ID}); none != nil || err != nil {
+			t.Errorf("find open for r3 = %+v, %v; want nil, nil", none, err)
+		}
+	})
+
+	t.Run("adds reports and merges incident types", func(t *testing.T) {
+		if err := repos.Conflicts.AddReports(ctx, c.ID, []string{r2.ID, r3.ID}, []string{"INTIM", "VIOLE"}); err != nil {
+			t.Fatalf("add reports: %v", err)
+		}
+		got, _ := repos.Conflicts.GetByID(ctx, c.ID)
+		if !slices.Equal(got.IncidentTypes, []string{"INTIM", "STUFF", "VIOLE"}) {
+			t.Errorf("incident types = %v", got.IncidentTypes)
+		}
+		if len(got.ReportIDs) != 3 || !slices.IsSorted(got.ReportIDs) {
+			t.Errorf("report ids = %v, want 3 sorted ids", got.ReportIDs)
+		}
+	})
+
+	t.Run("assigns and resolves once", func(t *testing.T) {
+		if err := repos.Conflicts.Assign(ctx, c.ID, coordinator.ID); err != nil {
+			t.Fatalf("assign: %v", err)
+		}
+		if err := repos.Conflicts.Resolve(ctx, c.ID, coordinator.ID, "doublon"); err != nil {
+			t.Fatalf("resolve: %v", err)
+		}
+		got, _ := repos.Conflicts.GetByID(ctx, c.ID)
+		if got.Status != entity.ConflictResolved || got.AssigneeID != coordinator.ID || got.ResolvedBy != coordinator.ID || got.ResolutionNote != "doublon" || got.ResolvedAt == nil {
+			t.Errorf("resolved conflict = %+v", got)
+		}
+		if err := repos.Conflicts.Resolve(ctx, c.ID, coordinator.ID, "again"); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("second resolve = %v, want sql.ErrNoRows", err)
+		}
+		if err := repos.Conflicts.Assign(ctx, uuid.New().String(), coordinator.ID); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("assign unknown = %v, want sql.ErrNoRows", err)
+		}
+		if open, _ := repos.Conflicts.GetAll(ctx, string(entity.ConflictOpen)); len(open) != 0 {
+			t.Errorf("%d open conflicts left", len(open))
+		}
+		if missing, err := repos.Conflicts.GetByID(ctx, uuid.New().String()); missing != nil || err != nil {
+			t.Errorf("unknown conflict = %+v, %v; want nil, nil", missing, err)
+		}
+	})
+
+	t.Run("finds the resolved conflict covering reports and types", func(t *testing.T) {
+		found, err := repos.Conflicts.FindResolvedCovering(ctx, []string{r1.ID, r3.ID}, []string{"INTIM", "STUFF"})
+		if err != nil || found == nil || found.ID != c.ID {
+			t.Fatalf("find resolved = %+v, %v", found, err)
+		}
+		// Un signalement ou un type que le conflit n'a pas couvert n'est pas arbitré
+		if none, err := repos.Conflicts.FindResolvedCovering(ctx, []string{r1.ID, uuid.New().String()}, []string{"STUFF"}); none != nil || err != nil {
+			t.Errorf("find resolved with an unknown report = %+v, %v; want nil, nil", none, err)
+		}
+		if none, err := repos.Conflicts.FindResolvedCovering(ctx, []string{r1.ID}, []string{"STUFF", "FRAUD"}); none != nil || err != nil {
+			t.Errorf("find resolved with a new type = %+v, %v; want nil, nil", none, err)
+		}
+	})
+}
@@ -0,0 +1,110 @@
+package repositorytest
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/openvote/backend/internal/domain/entity"
+)
+
+func testUsers(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+
+	t.Run("creates and reads back", func(t *testing.T) {
+		u := createUser(t, repos, entity.RoleObserver, seededRegionID)
+		got, err := repos.Users.GetByID(ctx, u.ID)
+		if err != nil || got == nil {
+			t.Fatalf("get by id: %v, %v", got, err)
+		}
+		if got.Username != u.Username || got.Role != entity.RoleObserver || got.RegionID != seededRegionID || got.PasswordHash != "hash" {
+			t.Errorf("unexpected user: %+v", got)
+		}
+		if !got.CreatedAt.Equal(u.CreatedAt) {
+			t.Errorf("created_at = %v, want %v", got.CreatedAt, u.CreatedAt)
+		}
+		if byName, err := repos.Users.GetByUsername(ctx, u.Username); err != nil || byName == nil || byName.ID != u.ID {
+			t.Errorf("get by username: %+v, %v", byName, err)
+		}
+	})
+
+	t.Run("returns nil for unknown users", func(t *testing.T) {
+		if got, err := repos.Users.GetByID(ctx, uuid.New().String()); got != nil || err != nil {
+			t.Errorf("get by id = %+v, %v; want nil, nil", got, err)
+		}
+		if got, err := repos.Users.GetByUsername(ctx, "nobody-"+uuid.New().String()); got != nil || err != nil {
+			t.Errorf("get by username = %+v, %v; want nil, nil", got, err)
+		}
+	})
+
+	t.Run("rejects duplicate usernames", func(t *testing.T) {
+		u := createUser(t, repos, entity.RoleCitizen, "")
+		dup := &entity.User{ID: uuid.New().String(), Username: u.Username, Role: entity.RoleCitizen, PasswordHash: "hash", CreatedAt: now(), UpdatedAt: now()}
+		if err := repos.Users.Create(ctx, dup); err == nil {
+			t.Error("duplicate username accepted")
+		}
+	})
+
+	t.Run("updates role, password and last login", func(t *testing.T) {
+		u := createUser(t, repos, entity.RoleCitizen, "")
+		if err := repos.Users.UpdateRole(ctx, u.ID, entity.RoleRegionAdmin, otherRegionID); err != nil {
+			t.Fatalf("update role: %v", err)
+		}
+		if err := repos.Users.UpdatePasswordHash(ctx, u.ID, "rotated"); err != nil {
+			t.Fatalf("update password: %v", err)
+		}
+		if err := repos.Users.UpdateLastLogin(ctx, u.ID); err != nil {
+			t.Fatalf("update last login: %v", err)
+		}
+		got, _ := repos.Users.GetByID(ctx, u.ID)
+		if got.Role != entity.RoleRegionAdmin || got.RegionID != otherRegionID || got.PasswordHash != "rotated" {
+			t.Errorf("unexpected user after updates: %+v", got)
+		}
+
+		all, err := repos.Users.GetAll(ctx)
+		if err != nil {
+			t.Fatalf("get all: %v", err)
+		}
+		found := false
+		for _, other := range all {
+			if other.ID == u.ID {
+				found = true
+				if other.LastLoginAt == nil {
+					t.Error("last login not listed")
+				}
+				if other.PasswordHash != "" {
+					t.Error("password hash listed")
+				}
+			}
+		}
+		if !found {
+			t.Error("user missing from list")
+		}
+	})
+
+	t.Run("reports missing users on update", func(t *testing.T) {
+		missing := uuid.New().String()
+		if err := repos.Users.UpdateRole(ctx, missing, entity.RoleObserver, ""); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("update role = %v, want sql.ErrNoRows", err)
+		}
+		if err := repos.Users.UpdatePasswordHash(ctx, missing, "x"); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("update password = %v, want sql.ErrNoRows", err)
+		}
+	})
+
+	t.Run("deleting a user cascades to reports", func(t *testing.T) {
+		u := createUser(t, repos, entity.RoleObserver, seededRegionID)
+		r := createReport(t, repos, u.ID, nil)
+		if err := repos.Users.Delete(ctx, u.ID); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+		if got, _ := repos.Users.GetByID(ctx, u.ID); got != nil {
+			t.Error("user still present")
+		}
+		if got, err := repos.Reports.GetByID(ctx, r.ID); got != nil || err != nil {
+			t.Errorf("report after delete = %+v, %v; want nil, nil", got, err)
+		}
+	})
+}
@@ -0,0 +1,148 @@
+package repositorytest
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/openvote/backend/internal/domain/entity"
+)
+
+func testWebhooks(t *testing.T, repos Repositories) {
+	ctx := context.Background()
+	sub := &entity.WebhookSubscription{Name: "Partenaire", URL: "https://partner.example/hooks", Secret: "s3cret",
+		EventTypes: []string{"report.created", "alert.opened"}, MinSeverity: 3, Active: true, CreatedBy: "admin"}
+	if err := repos.Webhooks.CreateSubscription(ctx, sub); err != nil || sub.ID == "" {
+		t.Fatalf("create subscription: %+v, %v", sub, err)
+	}
+
+	t.Run("manages subscriptions", func(t *testing.T) {
+		got, err := repos.Webhooks.GetSubscription(ctx, sub.ID)
+		if err != nil || got == nil {
+			t.Fatalf("get subscription: %+v, %v", got, err)
+		}
+		if got.Secret != "s3cret" || !slices.Equal(got.EventTypes, sub.EventTypes) || len(got.RegionIDs) != 0 || got.MinSeverity != 3 {
+			t.Errorf("unexpected subscription: %+v", got)
+		}
+		if active, _ := repos.Webhooks.ActiveSubscriptions(ctx, "alert.opened"); len(active) != 1 || active[0].ID != sub.ID {
+			t.Errorf("active subscriptions = %+v", active)
+		}
+		if active, _ := repos.Webhooks.ActiveSubscriptions(ctx, "user.role_changed"); len(active) != 0 {
+			t.Errorf("subscriptions for an unrelated event = %+v", active)
+		}
+
+		sub.Active, sub.RegionIDs = false, []string{seededRegionID}
+		if err := repos.Webhooks.UpdateSubscription(ctx, sub); err != nil {
+			t.Fatalf("update subscription: %v", err)
+		}
+		if active, _ := repos.Webhooks.ActiveSubscriptions(ctx, "alert.opened"); len(active) != 0 {
+			t.Errorf("inactive subscription still active: %+v", active)
+		}
+		if err := repos.Webhooks.UpdateSecret(ctx, sub.ID, "rotated"); err != nil {
+			t.Fatalf("update secret: %v", err)
+		}
+		if got, _ := repos.Webhooks.GetSubscription(ctx, sub.ID); got.Secret != "rotated" || !slices.Equal(got.RegionIDs, []string{seededRegionID}) {
+			t.Errorf("subscription after update = %+v", got)
+		}
+
+		missing := &entity.WebhookSubscription{ID: uuid.New().String(), Name: "x", URL: "https://x.example", EventTypes: []string{"report.created"}}
+		if err := repos.Webhooks.UpdateSubscription(ctx, missing); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("update unknown subscription = %v, want sql.ErrNoRows", err)
+		}
+		if err := repos.Webhooks.UpdateSecret(ctx, missing.ID, "x"); !errors.Is(err, sql.ErrNoRows) {
+			t.Errorf("update unknown secret = %v, want sql.ErrNoRows", err)
+		}
+		if got, err := repos.Webhooks.GetSubscription(ctx, missing.ID); got != nil || err != nil {
+			t.Errorf("unknown subscription = %+v, %v; want nil, nil", got, err)
+		}
+	})
+
+	t.Run("deduplicates deliveries per event", func(t *testing.T) {
+		d := &entity.WebhookDelivery{SubscriptionID: sub.ID, EventID: "evt-1", EventType: "report.created", Payload: json.RawMessage(`{"id": "evt-1"}`)}
+		created, err := repos.Webhooks.CreateDelivery(ctx, d)
+		if err != nil || !created || d.ID == "" || d.Status != entity.DeliveryPending {
+			t.Fatalf("create delivery = %v, %v (%+v)", created, err, d)
+		}
+		again := &entity.WebhookDelivery{SubscriptionID: sub.ID, EventID: "evt-1", EventType: "report.created", Payload: json.RawMessage(`{}`)}
+		if created, err := repos.Webhooks.CreateDelivery(ctx, again); err != nil || created {
+			t.Errorf("redelivered event = %v, %v; want ignored", created, err)
+		}
+		if list, _ := repos.Webhooks.ListDeliveries(ctx, sub.ID, "", 10); len(list) != 1 {
+			t.Errorf("%d deliveries, want 1", len(list))
+		}
+	})
+
+	t.Run("claims, retries and delivers", func(t *testing.T) {
+		d := &entity.WebhookDelivery{SubscriptionID: sub.ID, EventID: "evt-2", EventType: "alert.opened", Payload: json.RawMessage(`{}`)}
+		if _, err := repos.Webhooks.CreateDelivery(ctx, d); err != nil {
+			t.Fatalf("create delivery: %v", err)
+		}
+		claimed, err := repos.Webhooks.ClaimDueDeliveries(ctx, 10, time.Minute)
+		if err != nil || len(claimed) != 2 {
+			t.Fatalf("claim = %d, %v; want 2", len(claimed), err)
+		}
+		if none, _ := repos.Webhooks.ClaimDueDeliveries(ctx, 10, time.Minute); len(none) != 0 {
+			t.Errorf("leased deliveries claimed again: %d", len(none))
+		}
+
+		retryAt := now().Add(-time.Second)
+		if err := repos.Webhooks.MarkFailed(ctx, d.ID, 503, "unavailable", &retryAt); err != nil {
+			t.Fatalf("mark failed: %v", err)
+		}
+		retried, _ := repos.Webhooks.ClaimDueDeliveries(ctx, 10, time.Minute)
+		if len(retried) != 1 || retried[0].ID != d.ID || retried[0].Attempts != 2 || retried[0].ResponseStatus != 503 || retried[0].LastError != "unavailable" {
+			t.Errorf("retried = %+v", retried)
+		}
+
+		if err := repos.Webhooks.MarkDelivered(ctx, d.ID, 200); err != nil {
+			t.Fatalf("mark delivered: %v", err)
+		}
+		got, _ := repos.Webhooks.GetDelivery(ctx, d.ID)
+		if got.Status != entity.DeliveryDelivered || got.ResponseStatus != 200 || got.LastError != "" || got.DeliveredAt == nil {
+			t.Errorf("delivered = %+v", got)
+		}
+		if delivered, _ := repos.Webhooks.ListDeliveries(ctx, sub.ID, string(entity.DeliveryDelivered), 10); len(delivered) != 1 || delivered[0].ID != d.ID {
+			t.Errorf("delivered list = %+v", delivered)
+		}
+	})
+
+	t.Run("fails permanently and replays", func(t *testing.T) {
+		d := &entity.WebhookDelivery{SubscriptionID: sub.ID, EventID: "evt-3", EventType: "alert.opened", Payload: json.RawMessage(`{}`)}
+		if _, err := repos.Webhooks.CreateDelivery(ctx, d); err != nil {
+			t.Fatalf("create delivery: %v", err)
+		}
+		if err := repos.Webhooks.MarkFailed(ctx, d.ID, 410, "gone", nil); err != nil {
+			t.Fatalf("mark failed: %v", err)
+		}
+		if got, _ := repos.Webhooks.GetDelivery(ctx, d.ID); got.Status != entity.DeliveryFailed {
+			t.Errorf("status = %s, want failed", got.Status)
+		}
+		if err := repos.Webhooks.ResetDelivery(ctx, d.ID); err != nil {
+			t.Fatalf("reset: %v", err)
+		}
+		got, _ := repos.Webhooks.GetDelivery(ctx, d.ID)
+		if got.Status != entity.DeliveryPending || got.Attempts != 0 || got.LastError != "" {
+			t.Errorf("reset delivery = %+v", got)
+		}
+		if missing, err := repos.Webhooks.GetDelivery(ctx, uuid.New().String()); missing != nil || err != nil {
+			t.Errorf("unknown delivery = %+v, %v; want nil, nil", missing, err)
+		}
+	})
+
+	t.Run("deleting a subscription cascades to deliveries", func(t *testing.T) {
+		if err := repos.Webhooks.DeleteSubscription(ctx, sub.ID); err != nil {
+			t.Fatalf("delete: %v", err)
+		}
+		if list, _ := repos.Webhooks.ListDeliveries(ctx, sub.ID, "", 10); len(list) != 0 {
+			t.Errorf("%d deliveries left", len(list))
+		}
+		if subs, _ := repos.Webhooks.ListSubscriptions(ctx); len(subs) != 0 {
+			t.Errorf("%d subscriptions left", len(subs))
+		}
+	})
+}
@@ -0,0 +1,399 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/event"
+	"github.com/openvote/backend/internal/domain/repository"
+	"github.com/openvote/backend/internal/platform/notify"
+	"github.com/uber/h3-go/v4"
+)
+
+// Canaux de notification d'une règle d'alerte
+const (
+	AlertChannelDashboard = "dashboard" // Flux temps réel du tableau de bord
+	AlertChannelWebhook   = "webhook"   // Webhooks partenaires abonnés aux événements alert.*
+	// Notifications des coordinateurs concernés, selon leurs préférences
+	AlertChannelEmail = notify.ChannelEmail
+	AlertChannelSMS   = notify.ChannelSMS
+	AlertChannelInbox = notify.ChannelInbox
+)
+
+// AlertChannels liste les canaux acceptés dans une règle
+var AlertChannels = []string{AlertChannelDashboard, AlertChannelWebhook, AlertChannelEmail, AlertChannelSMS, AlertChannelInbox}
+
+const (
+	defaultAlertTimezone     = "Africa/Douala"
+	defaultAlertH3Resolution = 6 // ~36 km² : échelle d'un arrondissement
+	maxAlertWindowMinutes    = 24 * 60
+)
+
+var (
+	ErrAlertRuleNotFound      = errors.New("alert rule not found")
+	ErrAlertNotFound          = errors.New("alert not found")
+	ErrInvalidAlertRule       = errors.New("invalid alert rule")
+	ErrInvalidAlertTransition = errors.New("invalid alert status transition")
+)
+
+// AlertService gère les règles d'alerte et les alertes qu'elles déclenchent
+type AlertService interface {
+	CreateRule(ctx context.Context, rule *entity.AlertRule) error
+	UpdateRule(ctx context.Context, rule *entity.AlertRule) error
+	DeleteRule(ctx context.Context, id string) error
+	GetRule(ctx context.Context, id string) (*entity.AlertRule, error)
+	ListRules(ctx context.Context) ([]entity.AlertRule, error)
+
+	// EvaluateReport confronte un nouveau signalement aux règles actives et retourne les
+	// alertes déclenchées ou alimentées. Idempotent : un signalement réévalué ne duplique rien.
+	EvaluateReport(ctx context.Context, reportID string) ([]entity.Alert, error)
+
+	ListAlerts(ctx context.Context, filter repository.AlertFilter) ([]entity.Alert, error)
+	GetAlert(ctx context.Context, id string) (*entity.Alert, error)
+	Acknowledge(ctx context.Context, id, by string) (*entity.Alert, error)
+	Resolve(ctx context.Context, id, by, note string) (*entity.Alert, error)
+}
+
+type alertService struct {
+	repo       repository.AlertRepository
+	reportRepo repository.ReportRepository
+	events     event.Publisher
+}
+
+func NewAlertService(repo repository.AlertRepository, reportRepo repository.ReportRepository, events event.Publisher) AlertService {
+	return &alertService{repo: repo, reportRepo: reportRepo, events: events}
+}
+
+func (s *alertService) CreateRule(ctx context.Context, rule *entity.AlertRule) error {
+	if err := validateAlertRule(rule); err != nil {
+		return err
+	}
+	return s.repo.CreateRule(ctx, rule)
+}
+
+func (s *alertService) UpdateRule(ctx context.Context, rule *entity.AlertRule) error {
+	existing, err := s.repo.GetRule(ctx, rule.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return ErrAlertRuleNotFound
+	}
+	if err := validateAlertRule(rule); err != nil {
+		return err
+	}
+	return s.repo.UpdateRule(ctx, rule)
+}
+
+func (s *alertService) DeleteRule(ctx context.Context, id string) error {
+	existing, err := s.repo.GetRule(ctx, id)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return ErrAlertRuleNotFound
+	}
+	return s.repo.DeleteRule(ctx, id)
+}
+
+func (s *alertService) GetRule(ctx context.Context, id string) (*entity.AlertRule, error) {
+	rule, err := s.repo.GetRule(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if rule == nil {
+		return nil, ErrAlertRuleNotFound
+	}
+	return rule, nil
+}
+
+func (s *alertService) ListRules(ctx context.Context) ([]entity.AlertRule, error) {
+	return s.repo.ListRules(ctx)
+}
+
+// validateAlertRule vérifie la règle et complète les valeurs par défaut
+func validateAlertRule(rule *entity.AlertRule) error {
+	if strings.TrimSpace(rule.Name) == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidAlertRule)
+	}
+	if rule.MinSeverity == 0 {
+		rule.MinSeverity = 1
+	}
+	if rule.MinSeverity < 1 || rule.MinSeverity > 5 {
+		return fmt.Errorf("%w: min_severity must be between 1 and 5", ErrInvalidAlertRule)
+	}
+	if rule.GroupBy == "" {
+		rule.GroupBy = entity.AlertGroupRegion
+	}
+	if !slices.Contains([]string{entity.AlertGroupNone, entity.AlertGroupRegion, entity.AlertGroupDepartment, entity.AlertGroupH3}, rule.GroupBy) {
+		return fmt.Errorf("%w: group_by must be none, region, department or h3", ErrInvalidAlertRule)
+	}
+	if rule.GroupBy == entity.AlertGroupH3 && rule.H3Resolution == 0 {
+		rule.H3Resolution = defaultAlertH3Resolution
+	}
+	if rule.H3Resolution < 0 || rule.H3Resolution > 10 {
+		return fmt.Errorf("%w: h3_resolution must be between 0 and 10", ErrInvalidAlertRule)
+	}
+	if rule.Threshold == 0 {
+		rule.Threshold = 1
+	}
+	if rule.Threshold < 1 {
+		return fmt.Errorf("%w: threshold must be at least 1", ErrInvalidAlertRule)
+	}
+	if rule.WindowMinutes < 1 || rule.WindowMinutes > maxAlertWindowMinutes {
+		return fmt.Errorf("%w: window_minutes must be between 1 and %d", ErrInvalidAlertRule, maxAlertWindowMinutes)
+	}
+	for _, bound := range []string{rule.TimeOfDayStart, rule.TimeOfDayEnd} {
+		if _, err := parseTimeOfDay(bound); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidAlertRule, err)
+		}
+	}
+	if rule.Timezone == "" {
+		rule.Timezone = defaultAlertTimezone
+	}
+	if _, err := time.LoadLocation(rule.Timezone); err != nil {
+		return fmt.Errorf("%w: unknown timezone %q", ErrInvalidAlertRule, rule.Timezone)
+	}
+	if len(rule.Channels) == 0 {
+		rule.Channels = []string{AlertChannelDashboard}
+	}
+	for _, ch := range rule.Channels {
+		if !slices.Contains(AlertChannels, ch) {
+			return fmt.Errorf("%w: unknown channel %q", ErrInvalidAlertRule, ch)
+		}
+	}
+	return nil
+}
+
+// parseTimeOfDay convertit "HH:MM" en minutes depuis minuit ; -1 pour une borne vide
+func parseTimeOfDay(value string) (int, error) {
+	if value == "" {
+		return -1, nil
+	}
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("invalid time of day %q (expected HH:MM)", value)
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+// inTimeOfDay : la plage [start, end) peut passer minuit (22:00 → 06:00)
+func inTimeOfDay(t time.Time, rule entity.AlertRule, loc *time.Location) bool {
+	start, _ := parseTimeOfDay(rule.TimeOfDayStart)
+	end, _ := parseTimeOfDay(rule.TimeOfDayEnd)
+	local := t.In(loc)
+	m := local.Hour()*60 + local.Minute()
+	switch {
+	case start < 0 && end < 0:
+		return true
+	case start < 0:
+		return m < end
+	case end < 0:
+		return m >= start
+	case start <= end:
+		return m >= start && m < end
+	default:
+		return m >= start || m < end
+	}
+}
+
+// alertGroupKey identifie la zone d'un signalement selon le regroupement de la règle
+func alertGroupKey(rule entity.AlertRule, report entity.Report) string {
+	switch rule.GroupBy {
+	case entity.AlertGroupRegion:
+		return report.RegionID
+	case entity.AlertGroupDepartment:
+		return report.DepartmentID
+	case entity.AlertGroupH3:
+		cell := h3.Cell(h3.IndexFromString(report.H3Index))
+		if !cell.IsValid() {
+			return ""
+		}
+		if cell.Resolution() > rule.H3Resolution {
+			cell = cell.Parent(rule.H3Resolution)
+		}
+		return cell.String()
+	}
+	return ""
+}
+
+func (s *alertService) EvaluateReport(ctx context.Context, reportID string) ([]entity.Alert, error) {
+	target, err := s.reportRepo.GetByID(ctx, reportID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get report: %w", err)
+	}
+	if target == nil {
+		return nil, fmt.Errorf("report not found: %s", reportID)
+	}
+	rules, err := s.repo.ActiveRules(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load alert rules: %w", err)
+	}
+
+	var alerts []entity.Alert
+	for _, rule := range rules {
+		alert, created, err := s.evaluateRule(ctx, rule, *target)
+		if err != nil {
+			return alerts, fmt.Errorf("rule %s: %w", rule.Name, err)
+		}
+		if alert == nil {
+			continue
+		}
+		alerts = append(alerts, *alert)
+		if created {
+			publishEvents(ctx, s.events, event.AlertTriggered{
+				AlertID:     alert.ID,
+				RuleID:      rule.ID,
+				RuleName:    rule.Name,
+				GroupKey:    alert.GroupKey,
+				Severity:    alert.Severity,
+				RegionID:    alert.RegionID,
+				ReportIDs:   alert.ReportIDs,
+				ReportCount: alert.ReportCount,
+				Channels:    rule.Channels,
+			})
+		}
+	}
+	return alerts, nil
+}
+
+// evaluateRule retourne l'alerte créée ou alimentée par le signalement, nil si la règle ne s'applique pas
+func (s *alertService) evaluateRule(ctx context.Context, rule entity.AlertRule, target entity.Report) (*entity.Alert, bool, error) {
+	if target.Severity < rule.MinSeverity {
+		return nil, false, nil
+	}
+	if len(rule.RegionIDs) > 0 && !slices.Contains(rule.RegionIDs, target.RegionID) {
+		return nil, false, nil
+	}
+	loc, err := time.LoadLocation(rule.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	if !inTimeOfDay(target.CreatedAt, rule, loc) {
+		return nil, false, nil
+	}
+
+	// Le filtre sur le type d'incident (code ou nom) est appliqué par la requête :
+	// la règle ne concerne le signalement que s'il figure parmi les candidats
+	candidates, err := s.repo.ReportsInWindow(ctx, repository.AlertReportFilter{
+		IncidentTypes:  rule.IncidentTypes,
+		MinSeverity:    rule.MinSeverity,
+		RegionIDs:      rule.RegionIDs,
+		Since:          target.CreatedAt.Add(-time.Duration(rule.WindowMinutes) * time.Minute),
+		Until:          target.CreatedAt,
+		WithDepartment: rule.GroupBy == entity.AlertGroupDepartment,
+	})
+	if err != nil {
+		return nil, false, err
+	}
+	// La zone du signalement est lue sur sa ligne candidate (département renseigné par la requête)
+	i := slices.IndexFunc(candidates, func(r entity.Report) bool { return r.ID == target.ID })
+	if i < 0 {
+		return nil, false, nil
+	}
+
+	groupKey := alertGroupKey(rule, candidates[i])
+	var reportIDs []string
+	severity := 0
+	for _, r := range candidates {
+		if alertGroupKey(rule, r) != groupKey || !inTimeOfDay(r.CreatedAt, rule, loc) {
+			continue
+		}
+		reportIDs = append(reportIDs, r.ID)
+		severity = max(severity, r.Severity)
+	}
+	if len(reportIDs) < rule.Threshold {
+		return nil, false, nil
+	}
+
+	alert := &entity.Alert{
+		RuleID:      rule.ID,
+		RuleName:    rule.Name,
+		GroupKey:    groupKey,
+		RegionID:    target.RegionID,
+		Severity:    severity,
+		ReportIDs:   reportIDs,
+		ReportCount: len(reportIDs),
+	}
+	created, err := s.repo.UpsertOpenAlert(ctx, alert)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to save alert: %w", err)
+	}
+	return alert, created, nil
+}
+
+func (s *alertService) ListAlerts(ctx context.Context, filter repository.AlertFilter) ([]entity.Alert, error) {
+	return s.repo.ListAlerts(ctx, filter)
+}
+
+func (s *alertService) GetAlert(ctx context.Context, id string) (*entity.Alert, error) {
+	alert, err := s.repo.GetAlert(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if alert == nil {
+		return nil, ErrAlertNotFound
+	}
+	return alert, nil
+}
+
+func (s *alertService) Acknowledge(ctx context.Context, id, by string) (*entity.Alert, error) {
+	return s.transition(ctx, id, entity.AlertAcknowledged, by, "")
+}
+
+func (s *alertService) Resolve(ctx context.Context, id, by, note string) (*entity.Alert, error) {
+	return s.transition(ctx, id, entity.AlertResolved, by, note)
+}
+
+// transition : open → acknowledged → resolved (une alerte ouverte peut être résolue directement)
+func (s *alertService) transition(ctx context.Context, id string, status entity.AlertStatus, by, note string) (*entity.Alert, error) {
+	alert, err := s.GetAlert(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	allowed := alert.Status == entity.AlertOpen ||
+		(alert.Status == entity.AlertAcknowledged && status == entity.AlertResolved)
+	if !allowed {
+		return nil, fmt.Errorf("%w: %s → %s", ErrInvalidAlertTransition, alert.Status, status)
+	}
+	if err := s.repo.UpdateAlertStatus(ctx, id, status, by, note); err != nil {
+		return nil, err
+	}
+
+	var channels []string
+	if rule, err := s.repo.GetRule(ctx, alert.RuleID); err == nil && rule != nil {
+		channels = rule.Channels
+	}
+	publishEvents(ctx, s.events, event.AlertStatusChanged{
+		AlertID:   alert.ID,
+		RuleID:    alert.RuleID,
+		OldStatus: string(alert.Status),
+		NewStatus: string(status),
+		Severity:  alert.Severity,
+		RegionID:  alert.RegionID,
+		ChangedBy: by,
+		Channels:  channels,
+	})
+	return s.GetAlert(ctx, id)
+}
+
+// alertRoutedTo indique si un événement doit être transmis sur ce canal : les événements
+// d'alerte ne suivent que les canaux de leur règle, les autres événements passent toujours
+func alertRoutedTo(env event.Envelope, channel string) bool {
+	if env.Type != event.TypeAlertTriggered && env.Type != event.TypeAlertStatusChanged {
+		return true
+	}
+	var data struct {
+		Channels []string `json:"channels"`
+	}
+	if err := json.Unmarshal(env.Data, &data); err != nil {
+		return false
+	}
+	return slices.Contains(data.Channels, channel)
+}
@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/event"
+	"github.com/openvote/backend/internal/domain/repository"
+	"github.com/uber/h3-go/v4"
+)
+
+// Mock de event.Publisher : conserve les événements publiés
+type mockEventPublisher struct {
+	published []event.Payload
+}
+
+func (m *mockEventPublisher) Publish(ctx context.Context, events ...event.Payload) error {
+	m.published = append(m.published, events...)
+	return nil
+}
+
+// Mock de AlertRepository : les signalements candidats sont filtrés en mémoire
+type mockAlertRepo struct {
+	rules   []entity.AlertRule
+	reports []entity.Report
+	alerts  map[string]*entity.Alert
+}
+
+func (m *mockAlertRepo) CreateRule(ctx context.Context, rule *entity.AlertRule) error {
+	rule.ID = "rule-new"
+	m.rules = append(m.rules, *rule)
+	return nil
+}
+func (m *mockAlertRepo) UpdateRule(ctx context.Context, rule *entity.AlertRule) error { return nil }
+func (m *mockAlertRepo) DeleteRule(ctx context.Context, id string) error              { return nil }
+func (m *mockAlertRepo) GetRule(ctx context.Context, id string) (*entity.AlertRule, error) {
+	for i := range m.rules {
+		if m.rules[i].ID == id {
+			return &m.rules[i], nil
+		}
+	}
+	return nil, nil
+}
+func (m *mockAlertRepo) ListRules(ctx context.Context) ([]entity.AlertRule, error) {
+	return m.rules, nil
+}
+func (m *mockAlertRepo) ActiveRules(ctx context.Context) ([]entity.AlertRule, error) {
+	return m.rules, nil
+}
+func (m *mockAlertRepo) ReportsInWindow(ctx context.Context, f repository.AlertReportFilter) ([]entity.Report, error) {
+	var out []entity.Report
+	for _, r := range m.reports {
+		if r.CreatedAt.Before(f.Since) || r.CreatedAt.After(f.Until) || r.Severity < f.MinSeverity {
+			continue
+		}
+		if len(f.IncidentTypes) > 0 && !slices.Contains(f.IncidentTypes, r.IncidentType) {
+			continue
+		}
+		if len(f.RegionIDs) > 0 && !slices.Contains(f.RegionIDs, r.RegionID) {
+			continue
+		}
+		if !f.WithDepartment {
+			r.DepartmentID = ""
+		}
+		out = append(out, r)
+	}
+	return out, nil
+}
+func (m *mockAlertRepo) UpsertOpenAlert(ctx context.Context, a *entity.Alert) (bool, error) {
+	for _, existing := range m.alerts {
+		if existing.RuleID != a.RuleID || existing.GroupKey != a.GroupKey || existing.Status != entity.AlertOpen {
+			continue
+		}
+		for _, id := range a.ReportIDs {
+			if !slices.Contains(existing.ReportIDs, id) {
+				existing.ReportIDs = append(existing.ReportIDs, id)
+			}
+		}
+		existing.ReportCount = len(existing.ReportIDs)
+		*a = *existing
+		return false, nil
+	}
+	a.ID = fmt.Sprintf("alert-%d", len(m.alerts)+1)
+	a.Status = entity.AlertOpen
+	stored := *a
+	m.alerts[a.ID] = &stored
+	return true, nil
+}
+func (m *mockAlertRepo) GetAlert(ctx context.Context, id string) (*entity.Alert, error) {
+	for _, a := range m.alerts {
+		if a.ID == id {
+			copy := *a
+			return &copy, nil
+		}
+	}
+	return nil, nil
+}
+func (m *mockAlertRepo) ListAlerts(ctx context.Context, f repository.AlertFilter) ([]entity.Alert, error) {
+	return nil, nil
+}
+func (m *mockAlertRepo) UpdateAlertStatus(ctx context.Context, id string, status entity.AlertStatus, by, note string) error {
+	for _, a := range m.alerts {
+		if a.ID == id {
+			a.Status = status
+			a.ResolutionNote = note
+		}
+	}
+	return nil
+}
+
+// Signalements à Douala (même cellule H3 de résolution 6) et à Garoua
+var (
+	doualaCell = h3.LatLngToCell(h3.NewLatLng(4.0511, 9.7679), 10).String()
+	garouaCell = h3.LatLngToCell(h3.NewLatLng(9.3017, 13.3921), 10).String()
+)
+
+func alertTestReport(id, incidentType string, severity int, region, cell string, at time.Time) entity.Report {
+	return entity.Report{ID: id, IncidentType: incidentType, Severity: severity, RegionID: region, H3Index: cell, CreatedAt: at, Status: entity.StatusPending}
+}
+
+func newAlertTestService(t *testing.T, rules []entity.AlertRule, reports ...entity.Report) (AlertService, *mockAlertRepo, *mockEventPublisher) {
+	repo := &mockAlertRepo{rules: rules, reports: reports, alerts: map[string]*entity.Alert{}}
+	publisher := &mockEventPublisher{}
+	return NewAlertService(repo, newReportFixture(t, reports), publisher), repo, publisher
+}
+
+func TestEvaluateReport_HotspotThreshold(t *testing.T) {
+	base := time.Date(2026, 5, 10, 9, 0, 0, 0, time.UTC)
+	rule := entity.AlertRule{ID: "hotspot", Name: "Foyer critique", MinSeverity: 5, GroupBy: entity.AlertGroupH3, H3Resolution: 6,
+		Threshold: 3, WindowMinutes: 15, Timezone: "Africa/Douala", Channels: []string{AlertChannelDashboard}}
+
+	s, repo, publisher := newAlertTestService(t, []entity.AlertRule{rule},
+		alertTestReport("r1", "VIOLE", 5, "littoral", doualaCell, base),
+		alertTestReport("r2", "STUFF", 5, "littoral", doualaCell, base.Add(5*time.Minute)),
+		alertTestReport("r3", "INTIM", 5, "nord", garouaCell, base.Add(6*time.Minute)),    // autre zone
+		alertTestReport("r4", "BUYV", 4, "littoral", doualaCell, base.Add(7*time.Minute)), // sévérité insuffisante
+		alertTestReport("r5", "VIOLE", 5, "littoral", doualaCell, base.Add(10*time.Minute)),
+		alertTestReport("r6", "VIOLE", 5, "littoral", doualaCell, base.Add(40*time.Minute)), // hors fenêtre de r1
+	)
+	ctx := context.Background()
+
+	// Seulement 2 incidents critiques dans la zone : pas d'alerte
+	if alerts, err := s.EvaluateReport(ctx, "r2"); err != nil || len(alerts) != 0 {
+		t.Fatalf("Expected no alert below threshold, got %v, %v", alerts, err)
+	}
+
+	alerts, err := s.EvaluateReport(ctx, "r5")
+	if err != nil {
+		t.Fatalf("evaluate: %v", err)
+	}
+	if len(alerts) != 1 || alerts[0].ReportCount != 3 || !slices.Equal(alerts[0].ReportIDs, []string{"r1", "r2", "r5"}) {
+		t.Fatalf("Expected alert on r1, r2, r5, got %+v", alerts)
+	}
+	if len(publisher.published) != 1 {
+		t.Fatalf("Expected 1 alert.triggered event, got %d", len(publisher.published))
+	}
+	triggered := publisher.published[0].(event.AlertTriggered)
+	if triggered.RegionID != "littoral" || triggered.Severity != 5 || triggered.RuleName != "Foyer critique" {
+		t.Errorf("Unexpected event %+v", triggered)
+	}
+
+	// Réévaluation (message relivré) : l'alerte ouverte est alimentée, pas recréée
+	if _, err := s.EvaluateReport(ctx, "r5"); err != nil {
+		t.Fatalf("re-evaluate: %v", err)
+	}
+	if len(repo.alerts) != 1 || len(publisher.published) != 1 {
+		t.Errorf("Expected idempotent evaluation, got %d alerts and %d events", len(repo.alerts), len(publisher.published))
+	}
+}
+
+func TestEvaluateReport_TimeOfDayAndIncidentType(t *testing.T) {
+	douala, _ := time.LoadLocation("Africa/Douala")
+	rule := entity.AlertRule{ID: "early", Name: "Fermeture anticipée", IncidentTypes: []string{"EARLY"}, MinSeverity: 1,
+		GroupBy: entity.AlertGroupNone, Threshold: 1, WindowMinutes: 60, TimeOfDayEnd: "18:00", Timezone: "Africa/Douala"}
+
+	s, _, _ := newAlertTestService(t, []entity.AlertRule{rule},
+		alertTestReport("early", "EARLY", 4, "centre", doualaCell, time.Date(2026, 5, 10, 16, 30, 0, 0, douala)),
+		alertTestReport("late", "EARLY", 4, "centre", doualaCell, time.Date(2026, 5, 10, 18, 5, 0, 0, douala)),
+		alertTestReport("other", "NOMAT", 3, "centre", doualaCell, time.Date(2026, 5, 10, 16, 30, 0, 0, douala)),
+	)
+	ctx := context.Background()
+
+	if alerts, _ := s.EvaluateReport(ctx, "early"); len(alerts) != 1 {
+		t.Errorf("Expected alert for closure at 16:30, got %d", len(alerts))
+	}
+	if alerts, _ := s.EvaluateReport(ctx, "late"); len(alerts) != 0 {
+		t.Errorf("Expected no alert after 18:00, got %d", len(alerts))
+	}
+	if alerts, _ := s.EvaluateReport(ctx, "other"); len(alerts) != 0 {
+		t.Errorf("Expected no alert for other incident type, got %d", len(alerts))
+	}
+}
+
+func TestEvaluateReport_GroupsByDepartment(t *testing.T) {
+	base := time.Date(2026, 5, 10, 9, 0, 0, 0, time.UTC)
+	rule := entity.AlertRule{ID: "dept", Name: "Département agité", MinSeverity: 1, GroupBy: entity.AlertGroupDepartment, Threshold: 2,
+		WindowMinutes: 30, Timezone: "UTC", Channels: []string{AlertChannelDashboard}}
+	inDepartment := func(r entity.Report, departmentID string) entity.Report {
+		r.DepartmentID = departmentID
+		return r
+	}
+	s, _, _ := newAlertTestService(t, []entity.AlertRule{rule},
+		inDepartment(alertTestReport("r1", "VIOLE", 3, "littoral", doualaCell, base), "wouri"),
+		inDepartment(alertTestReport("r2", "VIOLE", 3, "littoral", doualaCell, base.Add(5*time.Minute)), "moungo"),
+		inDepartment(alertTestReport("r3", "VIOLE", 3, "littoral", doualaCell, base.Add(10*time.Minute)), "wouri"),
+	)
+	ctx := context.Background()
+
+	if alerts, _ := s.EvaluateReport(ctx, "r2"); len(alerts) != 0 {
+		t.Errorf("Expected no alert for a single report in moungo, got %+v", alerts)
+	}
+	alerts, err := s.EvaluateReport(ctx, "r3")
+	if err != nil || len(alerts) != 1 {
+		t.Fatalf("Expected 1 alert, got %+v, %v", alerts, err)
+	}
+	if alerts[0].GroupKey != "wouri" || !slices.Equal(alerts[0].ReportIDs, []string{"r1", "r3"}) {
+		t.Errorf("Expected r1 and r3 grouped in wouri, got %+v", alerts[0])
+	}
+}
+
+func TestAlertTransitions(t *testing.T) {
+	base := time.Date(2026, 5, 10, 9, 0, 0, 0, time.UTC)
+	rule := entity.AlertRule{ID: "any", Name: "Tout incident", MinSeverity: 1, GroupBy: entity.AlertGroupRegion, Threshold: 1,
+		WindowMinutes: 15, Timezone: "UTC", Channels: []string{AlertChannelDashboard, AlertChannelWebhook}}
+	s, _, publisher := newAlertTestService(t, []entity.AlertRule{rule}, alertTestReport("r1", "TECH", 2, "ouest", doualaCell, base))
+	ctx := context.Background()
+
+	alerts, _ := s.EvaluateReport(ctx, "r1")
+	if len(alerts) != 1 {
+		t.Fatalf("Expected 1 alert, got %d", len(alerts))
+	}
+	id := alerts[0].ID
+
+	acked, err := s.Acknowledge(ctx, id, "coord-1")
+	if err != nil || acked.Status != entity.AlertAcknowledged {
+		t.Fatalf("Expected acknowledged, got %+v, %v", acked, err)
+	}
+	if _, err := s.Acknowledge(ctx, id, "coord-1"); !errors.Is(err, ErrInvalidAlertTransition) {
+		t.Errorf("Expected ErrInvalidAlertTransition on second ack, got %v", err)
+	}
+	resolved, err := s.Resolve(ctx, id, "coord-1", "Bureau rouvert")
+	if err != nil || resolved.Status != entity.AlertResolved || resolved.ResolutionNote != "Bureau rouvert" {
+		t.Fatalf("Expected resolved, got %+v, %v", resolved, err)
+	}
+	if _, err := s.Resolve(ctx, "missing", "coord-1", ""); !errors.Is(err, ErrAlertNotFound) {
+		t.Errorf("Expected ErrAlertNotFound, got %v", err)
+	}
+
+	last := publisher.published[len(publisher.published)-1].(event.AlertStatusChanged)
+	if last.NewStatus != "resolved" || !slices.Equal(last.Channels, rule.Channels) {
+		t.Errorf("Unexpected status event %+v", last)
+	}
+}
+
+func TestEvaluateReport_AcknowledgedAlertOpensNewOne(t *testing.T) {
+	base := time.Date(2026, 5, 10, 9, 0, 0, 0, time.UTC)
+	rule := entity.AlertRule{ID: "any", Name: "Tout incident", MinSeverity: 1, GroupBy: entity.AlertGroupRegion, Threshold: 1,
+		WindowMinutes: 15, Timezone: "UTC", Channels: []string{AlertChannelDashboard}}
+	s, _, publisher := newAlertTestService(t, []entity.AlertRule{rule},
+		alertTestReport("r1", "TECH", 2, "ouest", doualaCell, base),
+		alertTestReport("r2", "TECH", 2, "ouest", doualaCell, base.Add(5*time.Minute)))
+	ctx := context.Background()
+
+	first, _ := s.EvaluateReport(ctx, "r1")
+	if len(first) != 1 {
+		t.Fatalf("Expected 1 alert, got %d", len(first))
+	}
+	if _, err := s.Acknowledge(ctx, first[0].ID, "coord-1"); err != nil {
+		t.Fatal(err)
+	}
+
+	// La reprise de l'activité après acquittement doit à nouveau notifier
+	second, _ := s.EvaluateReport(ctx, "r2")
+	if len(second) != 1 || second[0].ID == first[0].ID || second[0].Status != entity.AlertOpen {
+		t.Fatalf("Expected a new open alert, got %+v", second)
+	}
+	var triggered []string
+	for _, p := range publisher.published {
+		if e, ok := p.(event.AlertTriggered); ok {
+			triggered = append(triggered, e.AlertID)
+		}
+	}
+	if !slices.Equal(triggered, []string{first[0].ID, second[0].ID}) {
+		t.Errorf("Expected both alerts to be triggered, got %v", triggered)
+	}
+}
+
+func TestValidateAlertRule(t *testing.T) {
+	invalid := []entity.AlertRule{
+		{Name: "", WindowMinutes: 15},
+		{Name: "window", WindowMinutes: 0},
+		{Name: "group", WindowMinutes: 15, GroupBy: "commune"},
+		{Name: "time", WindowMinutes: 15, TimeOfDayEnd: "6pm"},
+		{Name: "tz", WindowMinutes: 15, Timezone: "Mars/Olympus"},
+		{Name: "channel", WindowMinutes: 15, Channels: []string{"pigeon"}},
+	}
+	for _, rule := range invalid {
+		rule := rule
+		if err := validateAlertRule(&rule); !errors.Is(err, ErrInvalidAlertRule) {
+			t.Errorf("%q: expected ErrInvalidAlertRule, got %v", rule.Name, err)
+		}
+	}
+
+	rule := entity.AlertRule{Name: "defaults", WindowMinutes: 15, GroupBy: entity.AlertGroupH3}
+	if err := validateAlertRule(&rule); err != nil {
+		t.Fatalf("validate: %v", err)
+	}
+	if rule.MinSeverity != 1 || rule.Threshold != 1 || rule.H3Resolution != defaultAlertH3Resolution ||
+		rule.Timezone != defaultAlertTimezone || !slices.Equal(rule.Channels, []string{AlertChannelDashboard}) {
+		t.Errorf("Unexpected defaults %+v", rule)
+	}
+}
+
+func TestAlertRoutedTo(t *testing.T) {
+	dashboardOnly, _ := event.New(event.AlertTriggered{AlertID: "a1", Channels: []string{AlertChannelDashboard}})
+	report, _ := event.New(event.ReportCreated{ReportID: "r1"})
+
+	if !alertRoutedTo(dashboardOnly, AlertChannelDashboard) || alertRoutedTo(dashboardOnly, AlertChannelWebhook) {
+		t.Error("Expected alert routed to its rule channels only")
+	}
+	if !alertRoutedTo(report, AlertChannelWebhook) {
+		t.Error("Expected non-alert events routed to every channel")
+	}
+}
@@ -4,8 +4,6 @@ import (
 	"context"
 	"errors"
 	"fmt"
-	"log"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -15,18 +13,6 @@ import (
 	"golang.org/x/crypto/bcrypt"
 )
 
-// jwtSecret est initialisé depuis la variable d'environnement JWT_SECRET
-var jwtSecret []byte
-
-func init() {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		log.Println("WARNING: JWT_SECRET non défini. Utilisation d'une clé par défaut (DEV UNIQUEMENT).")
-		secret = "super-secret-key-change-in-prod"
-	}
-	jwtSecret = []byte(secret)
-}
-
 type AuthService interface {
 	Register(ctx context.Context, username, password string) (*entity.User, error)
 	Login(ctx context.Context, username, password string) (string, error)
@@ -34,11 +20,13 @@ type AuthService interface {
 }
 
 type authService struct {
-	userRepo repository.UserRepository
+	userRepo  repository.UserRepository
+	jwtSecret []byte
 }
 
-func NewAuthService(userRepo repository.UserRepository) AuthService {
-	return &authService{userRepo: userRepo}
+// NewAuthService signe les jetons d'accès avec jwtSecret (config.Auth.JWTSecret)
+func NewAuthService(userRepo repository.UserRepository, jwtSecret string) AuthService {
+	return &authService{userRepo: userRepo, jwtSecret: []byte(jwtSecret)}
 }
 
 func (s *authService) Register(ctx context.Context, username, password string) (*entity.User, error) {
@@ -83,7 +71,7 @@ func (s *authService) Login(ctx context.Context, username, password string) (str
 		"exp":  time.Now().Add(time.Hour * 24).Unix(),
 	})
 
-	tokenString, err := token.SignedString(jwtSecret)
+	tokenString, err := token.SignedString(s.jwtSecret)
 	if err != nil {
 		return "", err
 	}
@@ -99,7 +87,7 @@ func (s *authService) ValidateToken(tokenString string) (*jwt.MapClaims, error)
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return jwtSecret, nil
+		return s.jwtSecret, nil
 	})
 
 	if err != nil {
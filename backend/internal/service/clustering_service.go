@@ -0,0 +1,186 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+	"github.com/openvote/backend/internal/platform/logging"
+	"github.com/openvote/backend/internal/platform/telemetry"
+	"github.com/uber/h3-go/v4"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	// eventRadiusMeters : distance maximale entre un signalement et le centroïde de l'événement
+	eventRadiusMeters = 500.0
+	// eventGapWindow : écart maximal entre un signalement et la période couverte par l'événement
+	eventGapWindow = 60 * time.Minute
+	// eventLockResolution : résolution H3 des zones de regroupement (~5 km², plus large que le rayon)
+	eventLockResolution = 7
+)
+
+var clusteringLogger = logging.For("clustering")
+
+var (
+	ErrEventNotFound         = errors.New("incident event not found")
+	ErrInvalidEventOperation = errors.New("invalid incident event operation")
+)
+
+// ClusteringService regroupe les signalements décrivant un même incident en événements consolidés
+type ClusteringService interface {
+	// AssignReport rattache un signalement à l'événement correspondant, ou en crée un nouveau
+	AssignReport(ctx context.Context, reportID string) (*entity.IncidentEvent, error)
+	MergeEvents(ctx context.Context, targetID string, sourceIDs []string) (*entity.IncidentEvent, error)
+	SplitEvent(ctx context.Context, eventID string, reportIDs []string) (*entity.IncidentEvent, error)
+}
+
+type clusteringService struct {
+	reportRepo repository.ReportRepository
+	eventRepo  repository.IncidentEventRepository
+}
+
+func NewClusteringService(reportRepo repository.ReportRepository, eventRepo repository.IncidentEventRepository) ClusteringService {
+	return &clusteringService{reportRepo: reportRepo, eventRepo: eventRepo}
+}
+
+func (s *clusteringService) AssignReport(ctx context.Context, reportID string) (*entity.IncidentEvent, error) {
+	ctx, span := telemetry.StartSpan(ctx, "ClusteringService.AssignReport", trace.WithAttributes(attribute.String("report.id", reportID)))
+	ev, err := s.assignReport(ctx, reportID)
+	telemetry.EndSpan(span, err)
+	return ev, err
+}
+
+func (s *clusteringService) assignReport(ctx context.Context, reportID string) (*entity.IncidentEvent, error) {
+	report, err := s.reportRepo.GetByID(ctx, reportID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get report: %w", err)
+	}
+	if report == nil {
+		return nil, fmt.Errorf("report not found: %s", reportID)
+	}
+
+	lat, lon, err := ParseWKTPoint(report.GPSLocation)
+	if err != nil {
+		clusteringLogger.WarnContext(ctx, "Position GPS illisible", "report_id", reportID)
+	}
+
+	// Même type, même zone, période chevauchante → même événement ; recherche, création et
+	// rattachement se font sous un même verrou pour ne pas dédoubler un événement
+	candidate := repository.EventCandidate{
+		IncidentType: report.IncidentType,
+		H3Index:      report.H3Index,
+		Lat:          lat,
+		Lon:          lon,
+		Radius:       eventRadiusMeters,
+		Start:        report.CreatedAt.Add(-eventGapWindow),
+		End:          report.CreatedAt.Add(eventGapWindow),
+	}
+	fallback := &entity.IncidentEvent{
+		IncidentType:    report.IncidentType,
+		H3Index:         report.H3Index,
+		Centroid:        report.GPSLocation,
+		FirstReportedAt: report.CreatedAt,
+		LastReportedAt:  report.CreatedAt,
+		MaxSeverity:     report.Severity,
+		Status:          entity.EventActive,
+	}
+	event, created, err := s.eventRepo.AssignReport(ctx, reportID, eventLockKey(report), candidate, fallback)
+	if err != nil {
+		return nil, fmt.Errorf("failed to assign report to an incident event: %w", err)
+	}
+	if created {
+		clusteringLogger.InfoContext(ctx, "Nouvel événement", "event_id", event.ID, "incident_type", report.IncidentType, "report_id", reportID)
+	} else {
+		clusteringLogger.InfoContext(ctx, "Signalement rattaché à l'événement", "report_id", reportID, "event_id", event.ID, "report_count", event.ReportCount)
+	}
+	return event, nil
+}
+
+// eventLockKey sérialise le regroupement par type d'incident et cellule H3 parente : deux
+// signalements voisins ne créent qu'un événement, les zones éloignées ne s'attendent pas
+func eventLockKey(report *entity.Report) string {
+	cell := h3.Cell(h3.IndexFromString(report.H3Index))
+	if !cell.IsValid() {
+		return report.IncidentType + ":" + report.H3Index
+	}
+	if cell.Resolution() > eventLockResolution {
+		cell = cell.Parent(eventLockResolution)
+	}
+	return report.IncidentType + ":" + cell.String()
+}
+
+func (s *clusteringService) MergeEvents(ctx context.Context, targetID string, sourceIDs []string) (*entity.IncidentEvent, error) {
+	if len(sourceIDs) == 0 {
+		return nil, fmt.Errorf("%w: no source event", ErrInvalidEventOperation)
+	}
+	seen := make(map[string]bool, len(sourceIDs))
+	unique := sourceIDs[:0:0]
+	for _, id := range sourceIDs {
+		if id == targetID {
+			return nil, fmt.Errorf("%w: cannot merge an event into itself", ErrInvalidEventOperation)
+		}
+		if !seen[id] {
+			seen[id] = true
+			unique = append(unique, id)
+		}
+	}
+	sourceIDs = unique
+	for _, id := range append([]string{targetID}, sourceIDs...) {
+		event, err := s.eventRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		if event == nil {
+			return nil, fmt.Errorf("%w: %s", ErrEventNotFound, id)
+		}
+		if event.Status != entity.EventActive {
+			return nil, fmt.Errorf("%w: event %s is already merged", ErrInvalidEventOperation, id)
+		}
+	}
+
+	if err := s.eventRepo.Merge(ctx, targetID, sourceIDs); err != nil {
+		return nil, fmt.Errorf("failed to merge events: %w", err)
+	}
+	return s.eventRepo.GetByID(ctx, targetID)
+}
+
+func (s *clusteringService) SplitEvent(ctx context.Context, eventID string, reportIDs []string) (*entity.IncidentEvent, error) {
+	event, err := s.eventRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return nil, err
+	}
+	if event == nil {
+		return nil, fmt.Errorf("%w: %s", ErrEventNotFound, eventID)
+	}
+
+	members := make(map[string]bool, len(event.ReportIDs))
+	for _, id := range event.ReportIDs {
+		members[id] = true
+	}
+	selected := make(map[string]bool, len(reportIDs))
+	for _, id := range reportIDs {
+		if !members[id] {
+			return nil, fmt.Errorf("%w: report %s does not belong to event %s", ErrInvalidEventOperation, id, eventID)
+		}
+		selected[id] = true
+	}
+	// Un découpage doit laisser au moins un signalement de chaque côté
+	if len(selected) == 0 || len(selected) >= len(members) {
+		return nil, fmt.Errorf("%w: split must keep at least one report in each event", ErrInvalidEventOperation)
+	}
+
+	ids := make([]string, 0, len(selected))
+	for id := range selected {
+		ids = append(ids, id)
+	}
+	created, err := s.eventRepo.Split(ctx, eventID, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to split event: %w", err)
+	}
+	return created, nil
+}
@@ -0,0 +1,197 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+	"github.com/openvote/backend/internal/repository/memory"
+)
+
+// newClusteringFixture charge les signalements et retourne le service avec le repository
+// d'événements du même store
+func newClusteringFixture(t *testing.T, reports []entity.Report) (ClusteringService, repository.IncidentEventRepository) {
+	t.Helper()
+	store, reportRepo := newReportStore(t, reports)
+	events := memory.NewIncidentEventRepository(store)
+	return NewClusteringService(reportRepo, events), events
+}
+
+func clusteringReports(ids ...string) []entity.Report {
+	now := time.Now()
+	reports := make([]entity.Report, 0, len(ids))
+	for _, id := range ids {
+		reports = append(reports, entity.Report{ID: id, IncidentType: "STUFF", GPSLocation: "POINT(11.5 3.8)", H3Index: "8a2a1072b59ffff",
+			Severity: 2, Status: entity.StatusPending, CreatedAt: now})
+	}
+	return reports
+}
+
+func activeEvents(t *testing.T, events repository.IncidentEventRepository) []entity.IncidentEvent {
+	t.Helper()
+	active, err := events.GetAll(context.Background(), string(entity.EventActive))
+	if err != nil {
+		t.Fatalf("list events: %v", err)
+	}
+	return active
+}
+
+func TestAssignReport_CreatesEventWhenNoCandidate(t *testing.T) {
+	svc, events := newClusteringFixture(t, clusteringReports("r1"))
+
+	event, err := svc.AssignReport(context.Background(), "r1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if event == nil || event.IncidentType != "STUFF" || event.ReportCount != 1 {
+		t.Fatalf("Expected a new STUFF event, got %+v", event)
+	}
+	if len(event.ReportIDs) != 1 || event.ReportIDs[0] != "r1" {
+		t.Errorf("Expected r1 attached to the new event, got %v", event.ReportIDs)
+	}
+	if active := activeEvents(t, events); len(active) != 1 {
+		t.Errorf("Expected one event, got %d", len(active))
+	}
+}
+
+func TestAssignReport_JoinsExistingEvent(t *testing.T) {
+	svc, events := newClusteringFixture(t, clusteringReports("r1", "r2"))
+
+	first, err := svc.AssignReport(context.Background(), "r1")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	second, err := svc.AssignReport(context.Background(), "r2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if second.ID != first.ID || second.ReportCount != 2 {
+		t.Errorf("Expected r2 to join %s, got %+v", first.ID, second)
+	}
+	if active := activeEvents(t, events); len(active) != 1 {
+		t.Errorf("Expected no new event to be created, got %d events", len(active))
+	}
+}
+
+func TestAssignReport_KeepsAlreadyClusteredReport(t *testing.T) {
+	ctx := context.Background()
+	svc, events := newClusteringFixture(t, clusteringReports("r1", "r2"))
+	if _, err := svc.AssignReport(ctx, "r1"); err != nil {
+		t.Fatal(err)
+	}
+	// r2 est séparé dans son propre événement par un modérateur
+	joined, err := svc.AssignReport(ctx, "r2")
+	if err != nil {
+		t.Fatal(err)
+	}
+	split, err := svc.SplitEvent(ctx, joined.ID, []string{"r2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Rejoué (re-triangulation, redélivrance), r2 ne doit pas revenir dans le premier événement
+	again, err := svc.AssignReport(ctx, "r2")
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if again.ID != split.ID {
+		t.Errorf("Expected r2 to stay in %s, got %s", split.ID, again.ID)
+	}
+	if first, _ := events.GetByID(ctx, joined.ID); first.ReportCount != 1 {
+		t.Errorf("Expected the first event to keep only r1, got %+v", first)
+	}
+}
+
+func TestAssignReport_ConcurrentReportsShareOneEvent(t *testing.T) {
+	ids := make([]string, 20)
+	for i := range ids {
+		ids[i] = fmt.Sprintf("r%d", i)
+	}
+	svc, events := newClusteringFixture(t, clusteringReports(ids...))
+
+	var wg sync.WaitGroup
+	errs := make(chan error, len(ids))
+	for _, id := range ids {
+		wg.Add(1)
+		go func(id string) {
+			defer wg.Done()
+			if _, err := svc.AssignReport(context.Background(), id); err != nil {
+				errs <- err
+			}
+		}(id)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+
+	active := activeEvents(t, events)
+	if len(active) != 1 || active[0].ReportCount != len(ids) {
+		t.Errorf("Expected a single event with %d reports, got %+v", len(ids), active)
+	}
+}
+
+func TestMergeEvents_RejectsMergedOrSelf(t *testing.T) {
+	ctx := context.Background()
+	svc, events := newClusteringFixture(t, nil)
+	now := time.Now()
+	a := &entity.IncidentEvent{IncidentType: "STUFF", FirstReportedAt: now, LastReportedAt: now}
+	b := &entity.IncidentEvent{IncidentType: "STUFF", FirstReportedAt: now, LastReportedAt: now}
+	c := &entity.IncidentEvent{IncidentType: "STUFF", FirstReportedAt: now, LastReportedAt: now}
+	for _, e := range []*entity.IncidentEvent{a, b, c} {
+		if err := events.Create(ctx, e); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := events.Merge(ctx, c.ID, []string{b.ID}); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := svc.MergeEvents(ctx, a.ID, []string{a.ID}); !errors.Is(err, ErrInvalidEventOperation) {
+		t.Errorf("Expected invalid operation for self-merge, got %v", err)
+	}
+	if _, err := svc.MergeEvents(ctx, a.ID, []string{b.ID}); !errors.Is(err, ErrInvalidEventOperation) {
+		t.Errorf("Expected invalid operation for already merged source, got %v", err)
+	}
+	if _, err := svc.MergeEvents(ctx, a.ID, []string{"missing"}); !errors.Is(err, ErrEventNotFound) {
+		t.Errorf("Expected not found, got %v", err)
+	}
+	if got, _ := events.GetByID(ctx, a.ID); got.Status != entity.EventActive {
+		t.Errorf("Expected no merge to reach the repository, got %+v", got)
+	}
+}
+
+func TestSplitEvent_RequiresProperSubset(t *testing.T) {
+	ctx := context.Background()
+	svc, events := newClusteringFixture(t, clusteringReports("r1", "r2", "r3"))
+	var event *entity.IncidentEvent
+	for _, id := range []string{"r1", "r2", "r3"} {
+		var err error
+		if event, err = svc.AssignReport(ctx, id); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if _, err := svc.SplitEvent(ctx, event.ID, []string{"r1", "r2", "r3"}); !errors.Is(err, ErrInvalidEventOperation) {
+		t.Errorf("Expected invalid operation when splitting every report, got %v", err)
+	}
+	if _, err := svc.SplitEvent(ctx, event.ID, []string{"r9"}); !errors.Is(err, ErrInvalidEventOperation) {
+		t.Errorf("Expected invalid operation for foreign report, got %v", err)
+	}
+	created, err := svc.SplitEvent(ctx, event.ID, []string{"r2"})
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if len(created.ReportIDs) != 1 || created.ReportIDs[0] != "r2" {
+		t.Errorf("Expected new event with r2, got %v", created.ReportIDs)
+	}
+	if rest, _ := events.GetByID(ctx, event.ID); rest.ReportCount != 2 {
+		t.Errorf("Expected r1 and r3 left in the original event, got %+v", rest)
+	}
+}
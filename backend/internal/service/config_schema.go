@@ -0,0 +1,241 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Sections de la configuration système (une version par modification de section)
+const (
+	ConfigSectionTriangulation = "triangulation"
+	ConfigSectionRateLimiting  = "rate_limiting"
+	ConfigSectionStorage       = "storage"
+)
+
+// ConfigSections liste les sections modifiables, dans l'ordre d'affichage
+var ConfigSections = []string{ConfigSectionTriangulation, ConfigSectionRateLimiting, ConfigSectionStorage}
+
+// RateLimitConfig : quotas de requêtes par IP et par minute
+type RateLimitConfig struct {
+	GlobalPerMinute int `json:"global_per_minute"`
+	AuthPerMinute   int `json:"auth_per_minute"` // Routes d'authentification (anti brute-force)
+}
+
+// StorageConfig : stockage des preuves (MinIO)
+type StorageConfig struct {
+	BucketName          string `json:"bucket_name"`
+	UploadExpiryMinutes int    `json:"upload_expiry_min"` // Validité des URL d'upload présignées
+}
+
+// UploadExpiry retourne la validité des URL d'upload
+func (c StorageConfig) UploadExpiry() time.Duration {
+	return time.Duration(c.UploadExpiryMinutes) * time.Minute
+}
+
+// SystemConfig est la configuration système typée, section par section
+type SystemConfig struct {
+	Triangulation TriangulationConfig `json:"triangulation"`
+	RateLimiting  RateLimitConfig     `json:"rate_limiting"`
+	Storage       StorageConfig       `json:"storage"`
+}
+
+// DefaultSystemConfig reprend les valeurs historiques, utilisées tant qu'aucune version n'est enregistrée
+func DefaultSystemConfig() SystemConfig {
+	triangulation := DefaultTriangulationConfig()
+	triangulation.Name = "" // Réservé aux configurations candidates de l'outil de rejeu
+	return SystemConfig{
+		Triangulation: triangulation,
+		RateLimiting:  RateLimitConfig{GlobalPerMinute: 100, AuthPerMinute: 10},
+		Storage:       StorageConfig{BucketName: "evidence", UploadExpiryMinutes: 15},
+	}
+}
+
+var ErrInvalidConfig = errors.New("invalid configuration")
+
+// ConfigValidationError détaille les champs refusés (clé : chemin du champ, ex. "triangulation.threshold")
+type ConfigValidationError struct {
+	Section string
+	Fields  map[string]string
+}
+
+func (e *ConfigValidationError) Error() string {
+	keys := make([]string, 0, len(e.Fields))
+	for k := range e.Fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, k := range keys {
+		parts = append(parts, k+": "+e.Fields[k])
+	}
+	return fmt.Sprintf("%s: %s", ErrInvalidConfig, strings.Join(parts, "; "))
+}
+
+func (e *ConfigValidationError) Unwrap() error { return ErrInvalidConfig }
+
+// configField retourne un pointeur vers la section dans cfg (nil pour une section inconnue)
+func configField(cfg *SystemConfig, section string) interface{} {
+	switch section {
+	case ConfigSectionTriangulation:
+		return &cfg.Triangulation
+	case ConfigSectionRateLimiting:
+		return &cfg.RateLimiting
+	case ConfigSectionStorage:
+		return &cfg.Storage
+	}
+	return nil
+}
+
+// s3BucketName : règles de nommage des buckets S3/MinIO
+var s3BucketName = regexp.MustCompile(`^[a-z0-9][a-z0-9.-]{1,61}[a-z0-9]$`)
+
+// validateConfigSection vérifie les bornes d'une section ; nil si elle est valide
+func validateConfigSection(cfg SystemConfig, section string) *ConfigValidationError {
+	fields := map[string]string{}
+	check := func(ok bool, field, msg string) {
+		if !ok {
+			fields[section+"."+field] = msg
+		}
+	}
+
+	switch section {
+	case ConfigSectionTriangulation:
+		t := cfg.Triangulation
+		check(t.Threshold > 0 && t.Threshold <= 10, "threshold", "must be in (0, 10]")
+		check(t.RadiusMeters >= 50 && t.RadiusMeters <= 5000, "radius_meters", "must be between 50 and 5000")
+		check(t.TimeWindowMinutes >= 1 && t.TimeWindowMinutes <= 240, "time_window_minutes", "must be between 1 and 240")
+		for name, w := range map[string]float64{
+			"observer": t.Weights.Observer, "verified_citizen": t.Weights.VerifiedCitizen,
+			"citizen": t.Weights.Citizen, "other": t.Weights.Other,
+		} {
+			check(w >= 0 && w <= 10, "weights."+name, "must be between 0 and 10")
+		}
+	case ConfigSectionRateLimiting:
+		r := cfg.RateLimiting
+		check(r.GlobalPerMinute >= 1 && r.GlobalPerMinute <= 100000, "global_per_minute", "must be between 1 and 100000")
+		check(r.AuthPerMinute >= 1 && r.AuthPerMinute <= 1000, "auth_per_minute", "must be between 1 and 1000")
+		check(r.AuthPerMinute <= r.GlobalPerMinute, "auth_per_minute", "must not exceed global_per_minute")
+	case ConfigSectionStorage:
+		s := cfg.Storage
+		check(s3BucketName.MatchString(s.BucketName), "bucket_name", "must be 3-63 lowercase letters, digits, dots or hyphens")
+		// Limite S3 des URL présignées : 7 jours
+		check(s.UploadExpiryMinutes >= 1 && s.UploadExpiryMinutes <= 7*24*60, "upload_expiry_min", "must be between 1 and 10080")
+	default:
+		return &ConfigValidationError{Section: section, Fields: map[string]string{"section": fmt.Sprintf("unknown section %q", section)}}
+	}
+
+	if len(fields) == 0 {
+		return nil
+	}
+	return &ConfigValidationError{Section: section, Fields: fields}
+}
+
+// applyConfigPatch fusionne patch (objet JSON partiel) dans la section de base et valide le résultat.
+// Les champs inconnus sont refusés pour ne pas perdre silencieusement une faute de frappe.
+func applyConfigPatch(base SystemConfig, section string, patch json.RawMessage) (SystemConfig, error) {
+	field := configField(&base, section)
+	if field == nil {
+		return base, validateConfigSection(base, section)
+	}
+
+	current, err := json.Marshal(field)
+	if err != nil {
+		return base, err
+	}
+	var merged map[string]interface{}
+	if err := json.Unmarshal(current, &merged); err != nil {
+		return base, err
+	}
+	var changes map[string]interface{}
+	if err := json.Unmarshal(patch, &changes); err != nil {
+		return base, &ConfigValidationError{Section: section, Fields: map[string]string{section: "must be a JSON object"}}
+	}
+	mergeJSONObjects(merged, changes)
+
+	data, err := json.Marshal(merged)
+	if err != nil {
+		return base, err
+	}
+	next := base
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(configField(&next, section)); err != nil {
+		return base, &ConfigValidationError{Section: section, Fields: map[string]string{section: err.Error()}}
+	}
+	if verr := validateConfigSection(next, section); verr != nil {
+		return base, verr
+	}
+	return next, nil
+}
+
+// mergeJSONObjects fusionne récursivement src dans dst (les sous-objets sont fusionnés, le reste remplacé)
+func mergeJSONObjects(dst, src map[string]interface{}) {
+	for k, v := range src {
+		if sub, ok := v.(map[string]interface{}); ok {
+			if existing, ok := dst[k].(map[string]interface{}); ok {
+				mergeJSONObjects(existing, sub)
+				continue
+			}
+		}
+		dst[k] = v
+	}
+}
+
+// ConfigDiffEntry est une différence entre deux versions d'une section
+type ConfigDiffEntry struct {
+	Path string      `json:"path"`
+	Old  interface{} `json:"old"`
+	New  interface{} `json:"new"`
+}
+
+// diffConfigJSON compare deux objets JSON champ par champ (chemins pointés, triés)
+func diffConfigJSON(oldData, newData json.RawMessage) ([]ConfigDiffEntry, error) {
+	oldFlat, newFlat := map[string]interface{}{}, map[string]interface{}{}
+	for _, pair := range []struct {
+		data json.RawMessage
+		flat map[string]interface{}
+	}{{oldData, oldFlat}, {newData, newFlat}} {
+		var obj map[string]interface{}
+		if err := json.Unmarshal(pair.data, &obj); err != nil {
+			return nil, err
+		}
+		flattenJSON("", obj, pair.flat)
+	}
+
+	paths := map[string]bool{}
+	for p := range oldFlat {
+		paths[p] = true
+	}
+	for p := range newFlat {
+		paths[p] = true
+	}
+	diff := []ConfigDiffEntry{}
+	for p := range paths {
+		o, n := oldFlat[p], newFlat[p]
+		if fmt.Sprint(o) != fmt.Sprint(n) {
+			diff = append(diff, ConfigDiffEntry{Path: p, Old: o, New: n})
+		}
+	}
+	sort.Slice(diff, func(i, j int) bool { return diff[i].Path < diff[j].Path })
+	return diff, nil
+}
+
+func flattenJSON(prefix string, obj map[string]interface{}, out map[string]interface{}) {
+	for k, v := range obj {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		if sub, ok := v.(map[string]interface{}); ok {
+			flattenJSON(path, sub, out)
+			continue
+		}
+		out[path] = v
+	}
+}
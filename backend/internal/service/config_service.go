@@ -0,0 +1,446 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/event"
+	"github.com/openvote/backend/internal/domain/repository"
+	"github.com/openvote/backend/internal/platform/logging"
+)
+
+var configLogger = logging.For("config")
+
+// configPollInterval : délai de prise en compte d'une modification faite par une autre instance
+const configPollInterval = 10 * time.Second
+
+var (
+	ErrConfigVersionNotFound = errors.New("config version not found")
+	// ErrConfigConflict : la section a changé depuis la version sur laquelle portait la modification
+	ErrConfigConflict = errors.New("config section was modified concurrently, reload and retry")
+)
+
+// ConfigUpdate décrit une modification d'une section
+type ConfigUpdate struct {
+	Section string
+	// Patch est un objet JSON partiel fusionné dans la valeur courante
+	Patch     json.RawMessage
+	ChangedBy string
+	Comment   string
+	// BaseVersion est la version lue par l'appelant (0 : version courante de cette instance)
+	BaseVersion int64
+}
+
+// ConfigChangeListener est appelé après chaque changement de section (local ou venant d'une autre instance)
+type ConfigChangeListener func(section string, cfg SystemConfig)
+
+// ConfigCheck vérifie une section modifiée avant son enregistrement (ressource externe disponible,
+// etc.) ; une erreur annule toute la modification
+type ConfigCheck func(ctx context.Context, section string, cfg SystemConfig) error
+
+// ConfigService gère la configuration système persistée, validée et versionnée
+type ConfigService interface {
+	// Load lit la dernière version de chaque section (valeurs par défaut pour les sections jamais modifiées)
+	Load(ctx context.Context) error
+	// Start recharge périodiquement les sections modifiées par d'autres instances (API, worker)
+	Start(ctx context.Context)
+
+	Current() SystemConfig
+	// Versions retourne la version courante de chaque section (0 : valeurs par défaut)
+	Versions() map[string]int64
+
+	// Update enregistre une nouvelle version ; retourne nil sans rien écrire si la section est inchangée
+	Update(ctx context.Context, u ConfigUpdate) (*entity.ConfigVersion, error)
+	// UpdateSections valide et vérifie toutes les sections, puis enregistre en une seule transaction
+	// celles qui changent (formulaire d'administration qui renvoie toute la configuration)
+	UpdateSections(ctx context.Context, patches map[string]json.RawMessage, changedBy, comment string) ([]entity.ConfigVersion, error)
+	History(ctx context.Context, section string, limit int) ([]entity.ConfigVersion, error)
+	// Diff compare deux versions d'une section (0 : valeurs par défaut)
+	Diff(ctx context.Context, section string, from, to int64) ([]ConfigDiffEntry, error)
+	// Rollback enregistre une nouvelle version reprenant le contenu d'une version antérieure
+	Rollback(ctx context.Context, section string, version int64, changedBy, comment string) (*entity.ConfigVersion, error)
+
+	// OnChange abonne un sous-système aux changements, pour qu'il se reconfigure à chaud
+	OnChange(listener ConfigChangeListener)
+	// BeforeChange ajoute une vérification exécutée avant chaque enregistrement local
+	BeforeChange(check ConfigCheck)
+}
+
+type configService struct {
+	repo   repository.ConfigRepository
+	events event.Publisher
+
+	mu        sync.RWMutex
+	current   SystemConfig
+	versions  map[string]int64
+	lastSeen  int64
+	listeners []ConfigChangeListener
+	checks    []ConfigCheck
+}
+
+func NewConfigService(repo repository.ConfigRepository, events event.Publisher) ConfigService {
+	return &configService{
+		repo:     repo,
+		events:   events,
+		current:  DefaultSystemConfig(),
+		versions: make(map[string]int64),
+	}
+}
+
+func (s *configService) Current() SystemConfig {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.current
+}
+
+func (s *configService) Versions() map[string]int64 {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	versions := make(map[string]int64, len(ConfigSections))
+	for _, section := range ConfigSections {
+		versions[section] = s.versions[section]
+	}
+	return versions
+}
+
+func (s *configService) OnChange(listener ConfigChangeListener) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.listeners = append(s.listeners, listener)
+}
+
+func (s *configService) BeforeChange(check ConfigCheck) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checks = append(s.checks, check)
+}
+
+// check exécute les vérifications sur la configuration candidate, pour chaque section modifiée
+func (s *configService) check(ctx context.Context, cfg SystemConfig, sections ...string) error {
+	s.mu.RLock()
+	checks := slices.Clone(s.checks)
+	s.mu.RUnlock()
+
+	invalid := &ConfigValidationError{Fields: map[string]string{}}
+	for _, section := range sections {
+		for _, check := range checks {
+			if err := check(ctx, section, cfg); err != nil {
+				invalid.Fields[section] = err.Error()
+				break
+			}
+		}
+	}
+	if len(invalid.Fields) > 0 {
+		return invalid
+	}
+	return nil
+}
+
+// notify prévient les abonnés, hors verrou (ils relisent souvent la configuration)
+func (s *configService) notify(sections []string) {
+	s.mu.RLock()
+	cfg, listeners := s.current, slices.Clone(s.listeners)
+	s.mu.RUnlock()
+	for _, section := range sections {
+		for _, listener := range listeners {
+			listener(section, cfg)
+		}
+	}
+}
+
+func (s *configService) Load(ctx context.Context) error {
+	_, err := s.reload(ctx)
+	return err
+}
+
+// reload applique les sections dont la version a changé et retourne leurs noms
+func (s *configService) reload(ctx context.Context) ([]string, error) {
+	latest, err := s.repo.Latest(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load system config: %w", err)
+	}
+
+	s.mu.Lock()
+	var changed []string
+	for _, v := range latest {
+		if v.Version > s.lastSeen {
+			s.lastSeen = v.Version
+		}
+		if s.versions[v.Section] == v.Version {
+			continue
+		}
+		next, err := decodeConfigSection(s.current, v.Section, v.Data)
+		if err != nil {
+			// Version écrite par une instance plus récente ou invalide : on garde la valeur actuelle
+			configLogger.WarnContext(ctx, "Version de configuration ignorée", "section", v.Section, "version", v.Version, "error", err)
+			continue
+		}
+		s.current = next
+		s.versions[v.Section] = v.Version
+		changed = append(changed, v.Section)
+	}
+	s.mu.Unlock()
+
+	if len(changed) > 0 {
+		configLogger.InfoContext(ctx, "Sections de configuration chargées", "sections", changed)
+		s.notify(changed)
+	}
+	return changed, nil
+}
+
+// decodeConfigSection lit une version enregistrée (tolère les champs inconnus) et la valide
+func decodeConfigSection(base SystemConfig, section string, data json.RawMessage) (SystemConfig, error) {
+	next := base
+	field := configField(&next, section)
+	if field == nil {
+		return base, fmt.Errorf("unknown section %q", section)
+	}
+	if err := json.Unmarshal(data, field); err != nil {
+		return base, err
+	}
+	if verr := validateConfigSection(next, section); verr != nil {
+		return base, verr
+	}
+	return next, nil
+}
+
+func (s *configService) Start(ctx context.Context) {
+	ticker := time.NewTicker(configPollInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		version, err := s.repo.CurrentVersion(ctx)
+		if err != nil {
+			if ctx.Err() == nil {
+				configLogger.ErrorContext(ctx, "Vérification des versions impossible", "error", err)
+			}
+			continue
+		}
+		s.mu.RLock()
+		stale := version > s.lastSeen
+		s.mu.RUnlock()
+		if stale {
+			if _, err := s.reload(ctx); err != nil && ctx.Err() == nil {
+				configLogger.ErrorContext(ctx, "Rechargement impossible", "error", err)
+			}
+		}
+	}
+}
+
+func (s *configService) Update(ctx context.Context, u ConfigUpdate) (*entity.ConfigVersion, error) {
+	s.mu.RLock()
+	base, current := s.current, s.versions[u.Section]
+	s.mu.RUnlock()
+
+	if u.BaseVersion != 0 && u.BaseVersion != current {
+		return nil, ErrConfigConflict
+	}
+	next, err := applyConfigPatch(base, u.Section, u.Patch)
+	if err != nil {
+		return nil, err
+	}
+
+	oldData, _ := json.Marshal(configField(&base, u.Section))
+	newData, _ := json.Marshal(configField(&next, u.Section))
+	if string(oldData) == string(newData) {
+		return nil, nil
+	}
+	if err := s.check(ctx, next, u.Section); err != nil {
+		return nil, err
+	}
+
+	version := &entity.ConfigVersion{Section: u.Section, Data: newData, ChangedBy: u.ChangedBy, Comment: u.Comment}
+	if err := s.append(ctx, []*entity.ConfigVersion{version}, map[string]int64{u.Section: current}); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+func (s *configService) UpdateSections(ctx context.Context, patches map[string]json.RawMessage, changedBy, comment string) ([]entity.ConfigVersion, error) {
+	s.mu.RLock()
+	base, current := s.current, maps.Clone(s.versions)
+	s.mu.RUnlock()
+
+	invalid := &ConfigValidationError{Fields: map[string]string{}}
+	for section, patch := range patches {
+		if _, err := applyConfigPatch(base, section, patch); err != nil {
+			var verr *ConfigValidationError
+			if !errors.As(err, &verr) {
+				return nil, err
+			}
+			for field, msg := range verr.Fields {
+				invalid.Fields[field] = msg
+			}
+		}
+	}
+	if len(invalid.Fields) > 0 {
+		return nil, invalid
+	}
+
+	// Configuration candidate : toutes les sections modifiées, vérifiées ensemble avant l'écriture
+	next := base
+	var changed []*entity.ConfigVersion
+	bases := map[string]int64{}
+	for _, section := range ConfigSections {
+		patch, ok := patches[section]
+		if !ok {
+			continue
+		}
+		applied, _ := applyConfigPatch(base, section, patch)
+		oldData, _ := json.Marshal(configField(&base, section))
+		newData, _ := json.Marshal(configField(&applied, section))
+		if string(oldData) == string(newData) {
+			continue
+		}
+		_ = json.Unmarshal(newData, configField(&next, section))
+		changed = append(changed, &entity.ConfigVersion{Section: section, Data: newData, ChangedBy: changedBy, Comment: comment})
+		bases[section] = current[section]
+	}
+	versions := []entity.ConfigVersion{}
+	if len(changed) == 0 {
+		return versions, nil
+	}
+	sections := make([]string, 0, len(changed))
+	for _, v := range changed {
+		sections = append(sections, v.Section)
+	}
+	if err := s.check(ctx, next, sections...); err != nil {
+		return nil, err
+	}
+
+	if err := s.append(ctx, changed, bases); err != nil {
+		return nil, err
+	}
+	for _, v := range changed {
+		versions = append(versions, *v)
+	}
+	return versions, nil
+}
+
+// append écrit les versions (une transaction) puis les applique localement, prévient les abonnés
+// et publie config.changed
+func (s *configService) append(ctx context.Context, versions []*entity.ConfigVersion, baseVersions map[string]int64) error {
+	if err := s.repo.AppendAll(ctx, versions, baseVersions); err != nil {
+		if errors.Is(err, repository.ErrConfigVersionConflict) {
+			// Modification concurrente (autre instance) : on se resynchronise pour la prochaine tentative
+			if _, rerr := s.reload(ctx); rerr != nil {
+				configLogger.ErrorContext(ctx, "Rechargement impossible", "error", rerr)
+			}
+			return ErrConfigConflict
+		}
+		return fmt.Errorf("failed to save config version: %w", err)
+	}
+
+	sections := make([]string, 0, len(versions))
+	s.mu.Lock()
+	for _, v := range versions {
+		// Seule la section modifiée est reprise : les autres ont pu changer entre-temps
+		field := configField(&s.current, v.Section)
+		_ = json.Unmarshal(v.Data, field)
+		s.versions[v.Section] = v.Version
+		if v.Version > s.lastSeen {
+			s.lastSeen = v.Version
+		}
+		sections = append(sections, v.Section)
+	}
+	s.mu.Unlock()
+
+	events := make([]event.Payload, 0, len(versions))
+	for _, v := range versions {
+		// L'auteur (nom d'utilisateur) reste dans l'historique des versions, pas dans le journal
+		configLogger.InfoContext(ctx, "Version de configuration enregistrée", "section", v.Section, "version", v.Version)
+		changed := event.ConfigChanged{Section: v.Section, Version: v.Version, ChangedBy: v.ChangedBy}
+		if v.RolledBackFrom != nil {
+			changed.RolledBackFrom = *v.RolledBackFrom
+		}
+		events = append(events, changed)
+	}
+	s.notify(sections)
+	publishEvents(ctx, s.events, events...)
+	return nil
+}
+
+func (s *configService) History(ctx context.Context, section string, limit int) ([]entity.ConfigVersion, error) {
+	if !slices.Contains(ConfigSections, section) {
+		return nil, validateConfigSection(SystemConfig{}, section)
+	}
+	return s.repo.History(ctx, section, limit)
+}
+
+// sectionData retourne le contenu d'une version de la section (0 : valeurs par défaut)
+func (s *configService) sectionData(ctx context.Context, section string, version int64) (json.RawMessage, error) {
+	if version == 0 {
+		defaults := DefaultSystemConfig()
+		return json.Marshal(configField(&defaults, section))
+	}
+	v, err := s.repo.GetVersion(ctx, version)
+	if err != nil {
+		return nil, err
+	}
+	if v == nil || v.Section != section {
+		return nil, ErrConfigVersionNotFound
+	}
+	return v.Data, nil
+}
+
+func (s *configService) Diff(ctx context.Context, section string, from, to int64) ([]ConfigDiffEntry, error) {
+	if !slices.Contains(ConfigSections, section) {
+		return nil, validateConfigSection(SystemConfig{}, section)
+	}
+	oldData, err := s.sectionData(ctx, section, from)
+	if err != nil {
+		return nil, err
+	}
+	newData, err := s.sectionData(ctx, section, to)
+	if err != nil {
+		return nil, err
+	}
+	return diffConfigJSON(oldData, newData)
+}
+
+func (s *configService) Rollback(ctx context.Context, section string, version int64, changedBy, comment string) (*entity.ConfigVersion, error) {
+	if !slices.Contains(ConfigSections, section) {
+		return nil, validateConfigSection(SystemConfig{}, section)
+	}
+	data, err := s.sectionData(ctx, section, version)
+	if err != nil {
+		return nil, err
+	}
+
+	s.mu.RLock()
+	base, current := s.current, s.versions[section]
+	s.mu.RUnlock()
+
+	// Le contenu cible est revalidé : les règles ont pu se durcir depuis
+	next, err := applyConfigPatch(base, section, data)
+	if err != nil {
+		return nil, err
+	}
+	if comment == "" {
+		comment = fmt.Sprintf("Retour à la version %d", version)
+	}
+	if err := s.check(ctx, next, section); err != nil {
+		return nil, err
+	}
+	newData, _ := json.Marshal(configField(&next, section))
+	v := &entity.ConfigVersion{Section: section, Data: newData, ChangedBy: changedBy, Comment: comment}
+	if version != 0 {
+		v.RolledBackFrom = &version
+	}
+	if err := s.append(ctx, []*entity.ConfigVersion{v}, map[string]int64{section: current}); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
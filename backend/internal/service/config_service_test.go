@@ -0,0 +1,329 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/event"
+	"github.com/openvote/backend/internal/domain/repository"
+)
+
+// Mock de ConfigRepository en mémoire, partageable entre deux instances du service
+type mockConfigRepo struct {
+	mu       sync.Mutex
+	versions []entity.ConfigVersion
+}
+
+func (m *mockConfigRepo) Latest(ctx context.Context) ([]entity.ConfigVersion, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	latest := map[string]entity.ConfigVersion{}
+	for _, v := range m.versions {
+		latest[v.Section] = v
+	}
+	var list []entity.ConfigVersion
+	for _, v := range latest {
+		list = append(list, v)
+	}
+	return list, nil
+}
+func (m *mockConfigRepo) CurrentVersion(ctx context.Context) (int64, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return int64(len(m.versions)), nil
+}
+func (m *mockConfigRepo) GetVersion(ctx context.Context, version int64) (*entity.ConfigVersion, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if version < 1 || int(version) > len(m.versions) {
+		return nil, nil
+	}
+	v := m.versions[version-1]
+	return &v, nil
+}
+func (m *mockConfigRepo) History(ctx context.Context, section string, limit int) ([]entity.ConfigVersion, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var list []entity.ConfigVersion
+	for i := len(m.versions) - 1; i >= 0 && len(list) < limit; i-- {
+		if m.versions[i].Section == section {
+			list = append(list, m.versions[i])
+		}
+	}
+	return list, nil
+}
+func (m *mockConfigRepo) Append(ctx context.Context, v *entity.ConfigVersion, baseVersion int64) error {
+	return m.AppendAll(ctx, []*entity.ConfigVersion{v}, map[string]int64{v.Section: baseVersion})
+}
+func (m *mockConfigRepo) AppendAll(ctx context.Context, versions []*entity.ConfigVersion, baseVersions map[string]int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	latest := map[string]int64{}
+	for _, existing := range m.versions {
+		latest[existing.Section] = existing.Version
+	}
+	for _, v := range versions {
+		if latest[v.Section] != baseVersions[v.Section] {
+			return repository.ErrConfigVersionConflict
+		}
+	}
+	for _, v := range versions {
+		v.Version = int64(len(m.versions) + 1)
+		m.versions = append(m.versions, *v)
+	}
+	return nil
+}
+
+func TestConfigUpdate_VersionsNotifiesAndPublishes(t *testing.T) {
+	repo := &mockConfigRepo{}
+	events := &mockEventPublisher{}
+	svc := NewConfigService(repo, events)
+
+	var notified []string
+	var threshold float64
+	svc.OnChange(func(section string, cfg SystemConfig) {
+		notified = append(notified, section)
+		threshold = cfg.Triangulation.Threshold
+	})
+
+	ctx := context.Background()
+	v, err := svc.Update(ctx, ConfigUpdate{Section: ConfigSectionTriangulation, Patch: json.RawMessage(`{"threshold": 1.5, "weights": {"citizen": 0.25}}`), ChangedBy: "admin"})
+	if err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+	if v == nil || v.Version != 1 {
+		t.Fatalf("expected version 1, got %+v", v)
+	}
+
+	cfg := svc.Current()
+	if cfg.Triangulation.Threshold != 1.5 || cfg.Triangulation.Weights.Citizen != 0.25 {
+		t.Errorf("patch not applied: %+v", cfg.Triangulation)
+	}
+	// Les champs absents du patch conservent leur valeur
+	if cfg.Triangulation.Weights.Observer != 1.0 || cfg.Triangulation.RadiusMeters != 500 {
+		t.Errorf("unpatched fields changed: %+v", cfg.Triangulation)
+	}
+	if len(notified) != 1 || notified[0] != ConfigSectionTriangulation || threshold != 1.5 {
+		t.Errorf("listener not notified with the new config: %v %v", notified, threshold)
+	}
+	if len(events.published) != 1 {
+		t.Fatalf("expected a config.changed event, got %d", len(events.published))
+	}
+	if changed, ok := events.published[0].(event.ConfigChanged); !ok || changed.Version != 1 || changed.Section != ConfigSectionTriangulation {
+		t.Errorf("unexpected event: %+v", events.published[0])
+	}
+
+	// Même valeur : aucune nouvelle version
+	if v, err := svc.Update(ctx, ConfigUpdate{Section: ConfigSectionTriangulation, Patch: json.RawMessage(`{"threshold": 1.5}`)}); err != nil || v != nil {
+		t.Errorf("no-op update should not create a version: %+v, %v", v, err)
+	}
+	if svc.Versions()[ConfigSectionTriangulation] != 1 || svc.Versions()[ConfigSectionStorage] != 0 {
+		t.Errorf("unexpected versions: %v", svc.Versions())
+	}
+}
+
+func TestConfigUpdate_ValidationErrors(t *testing.T) {
+	svc := NewConfigService(&mockConfigRepo{}, nil)
+	ctx := context.Background()
+
+	cases := []struct {
+		section, patch, field string
+	}{
+		{ConfigSectionTriangulation, `{"threshold": -1}`, "triangulation.threshold"},
+		{ConfigSectionTriangulation, `{"weights": {"observer": 20}}`, "triangulation.weights.observer"},
+		{ConfigSectionTriangulation, `{"treshold": 2}`, "triangulation"},
+		{ConfigSectionRateLimiting, `{"global_per_minute": 5, "auth_per_minute": 10}`, "rate_limiting.auth_per_minute"},
+		{ConfigSectionStorage, `{"bucket_name": "Evidence_Bucket"}`, "storage.bucket_name"},
+		{ConfigSectionStorage, `{"upload_expiry_min": "15"}`, "storage"},
+		{"logging", `{}`, "section"},
+	}
+	for _, tc := range cases {
+		_, err := svc.Update(ctx, ConfigUpdate{Section: tc.section, Patch: json.RawMessage(tc.patch)})
+		var verr *ConfigValidationError
+		if !errors.As(err, &verr) || !errors.Is(err, ErrInvalidConfig) {
+			t.Errorf("%s %s: expected a validation error, got %v", tc.section, tc.patch, err)
+			continue
+		}
+		if _, ok := verr.Fields[tc.field]; !ok {
+			t.Errorf("%s %s: expected field %s in %v", tc.section, tc.patch, tc.field, verr.Fields)
+		}
+	}
+	if svc.Current() != DefaultSystemConfig() {
+		t.Errorf("invalid updates must not change the configuration")
+	}
+}
+
+func TestConfigUpdateSections_AllOrNothing(t *testing.T) {
+	repo := &mockConfigRepo{}
+	svc := NewConfigService(repo, nil)
+	ctx := context.Background()
+
+	_, err := svc.UpdateSections(ctx, map[string]json.RawMessage{
+		ConfigSectionRateLimiting: json.RawMessage(`{"global_per_minute": 200}`),
+		ConfigSectionStorage:      json.RawMessage(`{"upload_expiry_min": 0}`),
+	}, "admin", "")
+	if !errors.Is(err, ErrInvalidConfig) {
+		t.Fatalf("expected a validation error, got %v", err)
+	}
+	if len(repo.versions) != 0 {
+		t.Fatalf("no section should be saved when one is invalid")
+	}
+
+	// Le formulaire renvoie toute la configuration : seules les sections modifiées sont versionnées
+	full, _ := json.Marshal(DefaultSystemConfig())
+	var sections map[string]json.RawMessage
+	json.Unmarshal(full, &sections)
+	sections[ConfigSectionRateLimiting] = json.RawMessage(`{"global_per_minute": 200, "auth_per_minute": 10}`)
+
+	versions, err := svc.UpdateSections(ctx, sections, "admin", "pic de trafic")
+	if err != nil {
+		t.Fatalf("UpdateSections: %v", err)
+	}
+	if len(versions) != 1 || versions[0].Section != ConfigSectionRateLimiting || versions[0].Comment != "pic de trafic" {
+		t.Errorf("expected only rate_limiting to be versioned, got %+v", versions)
+	}
+}
+
+func TestConfigUpdateSections_CheckedBeforeSaving(t *testing.T) {
+	repo := &mockConfigRepo{}
+	svc := NewConfigService(repo, nil)
+	var reconfigured []string
+	svc.OnChange(func(section string, cfg SystemConfig) { reconfigured = append(reconfigured, section) })
+	// Le bucket est préparé avant l'enregistrement : un stockage indisponible annule tout
+	var prepared []string
+	svc.BeforeChange(func(ctx context.Context, section string, cfg SystemConfig) error {
+		if section != ConfigSectionStorage {
+			return nil
+		}
+		prepared = append(prepared, cfg.Storage.BucketName)
+		if cfg.Storage.BucketName == "unreachable" {
+			return errors.New("storage unavailable")
+		}
+		return nil
+	})
+	ctx := context.Background()
+	patches := func(bucket string) map[string]json.RawMessage {
+		return map[string]json.RawMessage{
+			ConfigSectionRateLimiting: json.RawMessage(`{"global_per_minute": 200}`),
+			ConfigSectionStorage:      json.RawMessage(`{"bucket_name": "` + bucket + `"}`),
+		}
+	}
+
+	_, err := svc.UpdateSections(ctx, patches("unreachable"), "admin", "")
+	var verr *ConfigValidationError
+	if !errors.As(err, &verr) || verr.Fields[ConfigSectionStorage] == "" {
+		t.Fatalf("expected a storage validation error, got %v", err)
+	}
+	if len(repo.versions) != 0 || len(reconfigured) != 0 {
+		t.Fatalf("nothing should be saved or applied, got %d versions and %v", len(repo.versions), reconfigured)
+	}
+
+	// Une section modifiée entre-temps par une autre instance : aucune section n'est écrite
+	other := NewConfigService(repo, nil)
+	if _, err := other.Update(ctx, ConfigUpdate{Section: ConfigSectionStorage, Patch: json.RawMessage(`{"upload_expiry_min": 30}`)}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := svc.UpdateSections(ctx, patches("evidence-2026"), "admin", ""); !errors.Is(err, ErrConfigConflict) {
+		t.Fatalf("expected a conflict, got %v", err)
+	}
+	if len(repo.versions) != 1 || svc.Current().RateLimiting.GlobalPerMinute == 200 {
+		t.Fatalf("rate_limiting saved despite the conflict: %+v", repo.versions)
+	}
+
+	versions, err := svc.UpdateSections(ctx, patches("evidence-2026"), "admin", "")
+	if err != nil || len(versions) != 2 {
+		t.Fatalf("UpdateSections = %+v, %v", versions, err)
+	}
+	if cfg := svc.Current(); cfg.Storage.BucketName != "evidence-2026" || cfg.Storage.UploadExpiryMinutes != 30 || cfg.RateLimiting.GlobalPerMinute != 200 {
+		t.Errorf("unexpected config: %+v", cfg)
+	}
+	if len(prepared) != 3 || prepared[2] != "evidence-2026" {
+		t.Errorf("storage checks = %v", prepared)
+	}
+}
+
+func TestConfigHistoryDiffRollback(t *testing.T) {
+	svc := NewConfigService(&mockConfigRepo{}, nil)
+	ctx := context.Background()
+
+	svc.Update(ctx, ConfigUpdate{Section: ConfigSectionStorage, Patch: json.RawMessage(`{"upload_expiry_min": 30}`)})
+	svc.Update(ctx, ConfigUpdate{Section: ConfigSectionRateLimiting, Patch: json.RawMessage(`{"global_per_minute": 300}`)})
+	svc.Update(ctx, ConfigUpdate{Section: ConfigSectionStorage, Patch: json.RawMessage(`{"upload_expiry_min": 60, "bucket_name": "evidence-2025"}`)})
+
+	history, err := svc.History(ctx, ConfigSectionStorage, 10)
+	if err != nil || len(history) != 2 || history[0].Version != 3 || history[1].Version != 1 {
+		t.Fatalf("unexpected history: %+v, %v", history, err)
+	}
+
+	diff, err := svc.Diff(ctx, ConfigSectionStorage, 1, 3)
+	if err != nil {
+		t.Fatalf("Diff: %v", err)
+	}
+	if len(diff) != 2 || diff[0].Path != "bucket_name" || diff[1].Path != "upload_expiry_min" || diff[1].Old != 30.0 || diff[1].New != 60.0 {
+		t.Errorf("unexpected diff: %+v", diff)
+	}
+	if _, err := svc.Diff(ctx, ConfigSectionStorage, 2, 3); !errors.Is(err, ErrConfigVersionNotFound) {
+		t.Errorf("version 2 belongs to another section, got %v", err)
+	}
+
+	v, err := svc.Rollback(ctx, ConfigSectionStorage, 1, "admin", "")
+	if err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if v.Version != 4 || v.RolledBackFrom == nil || *v.RolledBackFrom != 1 {
+		t.Errorf("unexpected rollback version: %+v", v)
+	}
+	if cfg := svc.Current().Storage; cfg.UploadExpiryMinutes != 30 || cfg.BucketName != "evidence" {
+		t.Errorf("rollback not applied: %+v", cfg)
+	}
+
+	// Retour aux valeurs par défaut
+	if _, err := svc.Rollback(ctx, ConfigSectionRateLimiting, 0, "admin", ""); err != nil {
+		t.Fatalf("Rollback to defaults: %v", err)
+	}
+	if svc.Current().RateLimiting != DefaultSystemConfig().RateLimiting {
+		t.Errorf("rate limiting should be back to defaults: %+v", svc.Current().RateLimiting)
+	}
+}
+
+func TestConfig_ConflictAndReloadAcrossInstances(t *testing.T) {
+	repo := &mockConfigRepo{}
+	api := NewConfigService(repo, nil).(*configService)
+	worker := NewConfigService(repo, nil).(*configService)
+	ctx := context.Background()
+
+	var reloaded SystemConfig
+	worker.OnChange(func(section string, cfg SystemConfig) { reloaded = cfg })
+
+	if _, err := api.Update(ctx, ConfigUpdate{Section: ConfigSectionTriangulation, Patch: json.RawMessage(`{"radius_meters": 800}`)}); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	// Le worker n'a pas encore relu : sa modification porte sur une version périmée
+	_, err := worker.Update(ctx, ConfigUpdate{Section: ConfigSectionTriangulation, Patch: json.RawMessage(`{"threshold": 2}`)})
+	if !errors.Is(err, ErrConfigConflict) {
+		t.Fatalf("expected ErrConfigConflict, got %v", err)
+	}
+	// Le conflit a déclenché une relecture : la version de l'API est appliquée et notifiée
+	if reloaded.Triangulation.RadiusMeters != 800 || worker.Current().Triangulation.RadiusMeters != 800 {
+		t.Errorf("worker should have reloaded the API version: %+v", worker.Current().Triangulation)
+	}
+	if _, err := worker.Update(ctx, ConfigUpdate{Section: ConfigSectionTriangulation, Patch: json.RawMessage(`{"threshold": 2}`)}); err != nil {
+		t.Fatalf("retry after reload: %v", err)
+	}
+
+	// Formulaire ouvert avant la modification du worker : refusé, et l'API se resynchronise
+	if _, err := api.Update(ctx, ConfigUpdate{Section: ConfigSectionTriangulation, Patch: json.RawMessage(`{"threshold": 3}`), BaseVersion: 1}); !errors.Is(err, ErrConfigConflict) {
+		t.Errorf("expected ErrConfigConflict for a stale base version, got %v", err)
+	}
+	if api.Current().Triangulation.Threshold != 2 || api.Versions()[ConfigSectionTriangulation] != 2 {
+		t.Errorf("api should have picked up the worker version: %+v", api.Current().Triangulation)
+	}
+	if changed, err := api.reload(ctx); err != nil || len(changed) != 0 {
+		t.Errorf("nothing left to reload: %v %v", changed, err)
+	}
+}
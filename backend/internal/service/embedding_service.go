@@ -6,10 +6,13 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
-	"os"
 	"time"
+
+	"github.com/openvote/backend/internal/platform/logging"
+	"github.com/openvote/backend/internal/platform/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // EmbeddingService gère la communication avec Ollama pour générer des embeddings vectoriels
@@ -18,33 +21,31 @@ type EmbeddingService interface {
 	GetModel() string
 }
 
+var embeddingLogger = logging.For("embedding")
+
 type embeddingService struct {
 	ollamaURL string
 	model     string
 	client    *http.Client
 }
 
-func NewEmbeddingService() EmbeddingService {
-	url := os.Getenv("OLLAMA_URL")
-	if url == "" {
-		url = "http://localhost:11434"
-	}
-
-	// Ordre de préférence : variable d'env > snowflake multilingue > nomic fallback
-	model := os.Getenv("EMBEDDING_MODEL")
+// NewEmbeddingService interroge Ollama à ollamaURL ; sans model (config.Ollama.EmbeddingModel),
+// le modèle est détecté au démarrage
+func NewEmbeddingService(ollamaURL, model string) EmbeddingService {
+	// Ordre de préférence : configuration > snowflake multilingue > nomic fallback
 	if model == "" {
 		// Tester si snowflake est disponible
-		if isModelAvailable(url, "snowflake-arctic-embed2") {
+		if isModelAvailable(ollamaURL, "snowflake-arctic-embed2") {
 			model = "snowflake-arctic-embed2"
-			log.Printf("[EMBEDDING] Modèle multilingue snowflake-arctic-embed2 détecté ✓")
+			embeddingLogger.Info("Modèle multilingue détecté", "model", model)
 		} else {
 			model = "nomic-embed-text"
-			log.Printf("[EMBEDDING] Fallback sur nomic-embed-text (snowflake pas encore disponible)")
+			embeddingLogger.Info("Modèle multilingue indisponible, repli", "model", model)
 		}
 	}
 
 	return &embeddingService{
-		ollamaURL: url,
+		ollamaURL: ollamaURL,
 		model:     model,
 		client: &http.Client{
 			Timeout: 180 * time.Second, // Premier appel peut être lent (chargement modèle en RAM)
@@ -58,12 +59,29 @@ func (s *embeddingService) GetModel() string {
 
 // isModelAvailable vérifie si un modèle est installé dans Ollama
 func isModelAvailable(ollamaURL, modelName string) bool {
-	client := &http.Client{Timeout: 5 * time.Second}
-	resp, err := client.Get(ollamaURL + "/api/tags")
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	models, err := listOllamaModels(ctx, ollamaURL)
 	if err != nil {
 		return false
 	}
+	return hasModel(models, modelName)
+}
+
+// listOllamaModels retourne les modèles installés dans Ollama (/api/tags)
+func listOllamaModels(ctx context.Context, ollamaURL string) ([]string, error) {
+	req, err := http.NewRequestWithContext(ctx, "GET", ollamaURL+"/api/tags", nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
 	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("ollama erreur %d", resp.StatusCode)
+	}
 
 	var result struct {
 		Models []struct {
@@ -71,10 +89,19 @@ func isModelAvailable(ollamaURL, modelName string) bool {
 		} `json:"models"`
 	}
 	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
-		return false
+		return nil, err
 	}
+	models := make([]string, 0, len(result.Models))
 	for _, m := range result.Models {
-		if m.Name == modelName || m.Name == modelName+":latest" {
+		models = append(models, m.Name)
+	}
+	return models, nil
+}
+
+// hasModel accepte le nom nu ou étiqueté ":latest"
+func hasModel(models []string, modelName string) bool {
+	for _, m := range models {
+		if m == modelName || m == modelName+":latest" {
 			return true
 		}
 	}
@@ -93,6 +120,16 @@ type ollamaEmbedResponse struct {
 }
 
 func (s *embeddingService) GenerateEmbedding(ctx context.Context, text string) ([]float32, error) {
+	ctx, span := telemetry.StartSpan(ctx, "ollama embeddings", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("ollama.model", s.model)))
+	start := time.Now()
+	embedding, err := s.generateEmbedding(ctx, text)
+	telemetry.ObserveModelCall("embedding", s.model, start, err)
+	telemetry.EndSpan(span, err)
+	return embedding, err
+}
+
+func (s *embeddingService) generateEmbedding(ctx context.Context, text string) ([]float32, error) {
 	reqBody := ollamaEmbedRequest{
 		Model:  s.model,
 		Prompt: text,
@@ -129,6 +166,6 @@ func (s *embeddingService) GenerateEmbedding(ctx context.Context, text string) (
 		return nil, fmt.Errorf("embedding vide retourné par Ollama")
 	}
 
-	log.Printf("[EMBEDDING] Modèle %s → %d chars → vecteur %dD", s.model, len(text), len(result.Embedding))
+	embeddingLogger.DebugContext(ctx, "Embedding généré", "model", s.model, "text_chars", len(text), "dimensions", len(result.Embedding))
 	return result.Embedding, nil
 }
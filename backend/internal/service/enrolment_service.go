@@ -4,7 +4,6 @@ import (
 	"context"
 	"errors"
 	"time"
-	"os"
 
 	"github.com/golang-jwt/jwt/v5"
 	"github.com/openvote/backend/internal/domain/entity"
@@ -30,14 +29,12 @@ type ActivationClaims struct {
 	jwt.RegisteredClaims
 }
 
-func NewEnrolmentService(userRepo repository.UserRepository) EnrolmentService {
-    secret := os.Getenv("JWT_SECRET")
-    if secret == "" {
-        secret = "default-secret-change-me"
-    }
+// NewEnrolmentService signe les tokens d'activation et les jetons émis à l'enrôlement avec
+// jwtSecret, le secret de AuthService : ces jetons doivent y être acceptés
+func NewEnrolmentService(userRepo repository.UserRepository, jwtSecret string) EnrolmentService {
 	return &enrolmentService{
 		userRepo:  userRepo,
-		jwtSecret: []byte(secret),
+		jwtSecret: []byte(jwtSecret),
 	}
 }
 
@@ -46,6 +43,8 @@ func (s *enrolmentService) GenerateActivationToken(ctx context.Context, role ent
 		Role:     role,
 		RegionID: regionID,
 		RegisteredClaims: jwt.RegisteredClaims{
+			// Identifiant unique : deux tokens émis dans la même seconde (lot imprimé) restent distincts
+			ID:        uuid.New().String(),
 			ExpiresAt: jwt.NewNumericDate(time.Now().Add(24 * 365 * time.Hour)), // Valide 1 an pour les QR codes imprimés
 			Issuer:    "openvote-admin",
 		},
@@ -80,11 +79,17 @@ func (s *enrolmentService) Enroll(ctx context.Context, activationToken, pin stri
 		return nil, "", "", err
 	}
 
+	now := time.Now()
 	user := &entity.User{
+		ID:           uuid.New().String(),
 		Username:     username,
 		Role:         claims.Role,
 		RegionID:     claims.RegionID,
 		PasswordHash: string(hashedPin),
+		CreatedAt:    now,
+		UpdatedAt:    now,
+		// Empreinte du token : permet de repérer les comptes issus d'un même lot (détection Sybil)
+		ActivationTokenHash: fingerprint(activationToken),
 	}
 
 	if err := s.userRepo.Create(ctx, user); err != nil {
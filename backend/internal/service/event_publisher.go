@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/event"
+	"github.com/openvote/backend/internal/domain/repository"
+	"github.com/openvote/backend/internal/platform/logging"
+	"github.com/openvote/backend/internal/platform/telemetry"
+)
+
+var eventsLogger = logging.For("events")
+
+// eventPublisher écrit les événements dans l'outbox : le relais les publie ensuite sur
+// l'échange des événements, avec leur type comme clé de routage
+type eventPublisher struct {
+	outboxRepo repository.OutboxRepository
+}
+
+func NewEventPublisher(outboxRepo repository.OutboxRepository) event.Publisher {
+	return &eventPublisher{outboxRepo: outboxRepo}
+}
+
+func (p *eventPublisher) Publish(ctx context.Context, events ...event.Payload) error {
+	messages, err := eventOutboxMessages(ctx, events...)
+	if err != nil {
+		return err
+	}
+	return p.outboxRepo.Enqueue(ctx, messages...)
+}
+
+// eventOutboxMessages prépare les messages d'outbox des événements, à écrire dans la même
+// transaction que le changement d'état qu'ils décrivent
+func eventOutboxMessages(ctx context.Context, events ...event.Payload) ([]*entity.OutboxMessage, error) {
+	messages := make([]*entity.OutboxMessage, 0, len(events))
+	for _, e := range events {
+		msg, err := eventOutboxMessage(e)
+		if err != nil {
+			return nil, err
+		}
+		messages = append(messages, msg)
+	}
+	withRequestContext(ctx, messages)
+	return messages, nil
+}
+
+// withRequestContext joint aux messages le contexte de trace et l'identifiant de la requête :
+// le relais les transmet au broker et le worker poursuit la même trace et la même corrélation
+func withRequestContext(ctx context.Context, messages []*entity.OutboxMessage) {
+	headers := logging.InjectRequestID(ctx, telemetry.InjectHeaders(ctx))
+	for _, msg := range messages {
+		msg.Headers = headers
+	}
+}
+
+// eventOutboxMessage emballe un événement dans un message d'outbox routé par son type
+func eventOutboxMessage(p event.Payload) (*entity.OutboxMessage, error) {
+	env, err := event.New(p)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(env)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal event envelope: %w", err)
+	}
+	return &entity.OutboxMessage{Topic: string(env.Type), Payload: payload}, nil
+}
+
+// publishEvents publie sans faire échouer l'opération métier déjà effectuée : l'erreur est journalisée
+func publishEvents(ctx context.Context, publisher event.Publisher, events ...event.Payload) {
+	if publisher == nil {
+		return
+	}
+	if err := publisher.Publish(ctx, events...); err != nil {
+		for _, e := range events {
+			eventsLogger.ErrorContext(ctx, "Publication impossible", "event_type", e.EventType(), "error", err)
+		}
+	}
+}
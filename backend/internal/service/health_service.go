@@ -0,0 +1,268 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/openvote/backend/internal/platform/queue"
+)
+
+// Statut d'un composant
+const (
+	HealthUp   = "up"
+	HealthDown = "down"
+)
+
+// Statut global de l'instance (/readyz)
+const (
+	ReadinessReady    = "ready"
+	ReadinessDegraded = "degraded"  // Un composant non critique est en panne : l'API répond, en mode dégradé
+	ReadinessNotReady = "not_ready" // Un composant critique est en panne : l'instance ne doit plus recevoir de trafic
+)
+
+// defaultHealthTimeout borne chaque sonde : un composant qui ne répond pas à temps est en panne
+const defaultHealthTimeout = 2 * time.Second
+
+// readinessCacheTTL : les sondes ne sont rejouées qu'une fois par intervalle, quel que soit le
+// nombre d'appels (/readyz est public)
+const readinessCacheTTL = 5 * time.Second
+
+// HealthCheck sonde un composant ; les détails (modèles, pool...) sont repris tels quels dans la réponse
+type HealthCheck struct {
+	Name string
+	// Critical : une panne rend l'instance non prête (503), sinon elle n'est que dégradée
+	Critical bool
+	Probe    func(ctx context.Context) (map[string]interface{}, error)
+}
+
+// ComponentHealth est le résultat d'une sonde
+type ComponentHealth struct {
+	Name      string                 `json:"name"`
+	Status    string                 `json:"status"`
+	Critical  bool                   `json:"critical"`
+	LatencyMS float64                `json:"latency_ms"`
+	Error     string                 `json:"error,omitempty"`
+	Details   map[string]interface{} `json:"details,omitempty"`
+}
+
+// Readiness agrège les sondes de tous les composants
+type Readiness struct {
+	Status     string            `json:"status"`
+	Components []ComponentHealth `json:"components"`
+	CheckedAt  time.Time         `json:"checked_at"`
+}
+
+// Ready indique si l'instance peut recevoir du trafic (mode dégradé compris)
+func (r Readiness) Ready() bool {
+	return r.Status != ReadinessNotReady
+}
+
+// Summary ne garde que le statut et la latence des composants (réponse publique) : les erreurs
+// et détails (pool de connexions, modèles) restent sur l'écoute interne
+func (r Readiness) Summary() Readiness {
+	components := make([]ComponentHealth, len(r.Components))
+	for i, c := range r.Components {
+		components[i] = ComponentHealth{Name: c.Name, Status: c.Status, Critical: c.Critical, LatencyMS: c.LatencyMS}
+	}
+	r.Components = components
+	return r
+}
+
+// HealthService sonde les dépendances de l'API (base, file, stockage, Ollama)
+type HealthService interface {
+	// Readiness retourne le résultat des sondes, rejouées au plus une fois par readinessCacheTTL
+	Readiness(ctx context.Context) Readiness
+	// Uptime : durée écoulée depuis le démarrage du service (/livez)
+	Uptime() time.Duration
+}
+
+type healthService struct {
+	checks  []HealthCheck
+	timeout time.Duration
+	ttl     time.Duration
+	started time.Time
+
+	// Dernier résultat ; le verrou fait aussi attendre les appels simultanés pendant les sondes
+	mu      sync.Mutex
+	last    Readiness
+	checked time.Time
+}
+
+// NewHealthService : timeout <= 0 applique le délai par défaut à chaque sonde
+func NewHealthService(timeout time.Duration, checks ...HealthCheck) HealthService {
+	if timeout <= 0 {
+		timeout = defaultHealthTimeout
+	}
+	return &healthService{checks: checks, timeout: timeout, ttl: readinessCacheTTL, started: time.Now()}
+}
+
+func (s *healthService) Uptime() time.Duration {
+	return time.Since(s.started)
+}
+
+func (s *healthService) Readiness(ctx context.Context) Readiness {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.checked.IsZero() && time.Since(s.checked) < s.ttl {
+		return s.last
+	}
+	// Le résultat est partagé : l'annulation de la requête qui déclenche les sondes ne le fausse pas
+	s.last = s.probeAll(context.WithoutCancel(ctx))
+	s.checked = time.Now()
+	return s.last
+}
+
+// probeAll exécute les sondes en parallèle, chacune bornée par le délai du service
+func (s *healthService) probeAll(ctx context.Context) Readiness {
+	components := make([]ComponentHealth, len(s.checks))
+	var wg sync.WaitGroup
+	for i, check := range s.checks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			components[i] = s.probe(ctx, check)
+		}()
+	}
+	wg.Wait()
+
+	status := ReadinessReady
+	for _, c := range components {
+		if c.Status == HealthUp {
+			continue
+		}
+		if c.Critical {
+			status = ReadinessNotReady
+			break
+		}
+		status = ReadinessDegraded
+	}
+	return Readiness{Status: status, Components: components, CheckedAt: time.Now().UTC()}
+}
+
+type probeResult struct {
+	details map[string]interface{}
+	err     error
+}
+
+func (s *healthService) probe(ctx context.Context, check HealthCheck) ComponentHealth {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+
+	start := time.Now()
+	// Une sonde qui ignore le contexte ne bloque pas la réponse
+	done := make(chan probeResult, 1)
+	go func() {
+		details, err := check.Probe(ctx)
+		done <- probeResult{details, err}
+	}()
+	var res probeResult
+	select {
+	case res = <-done:
+	case <-ctx.Done():
+		res.err = fmt.Errorf("timeout after %s", s.timeout)
+	}
+
+	c := ComponentHealth{
+		Name:      check.Name,
+		Status:    HealthUp,
+		Critical:  check.Critical,
+		LatencyMS: float64(time.Since(start).Microseconds()) / 1000,
+		Details:   res.details,
+	}
+	if res.err != nil {
+		c.Status = HealthDown
+		c.Error = res.err.Error()
+	}
+	return c
+}
+
+// DatabaseHealthCheck sonde PostgreSQL. db nil : dépôts en mémoire, choisis (DB_BACKEND=memory)
+// ou subis (base injoignable au démarrage hors production : composant en panne, les données ne
+// sont pas persistées).
+func DatabaseHealthCheck(db *sql.DB, fallback bool) HealthCheck {
+	return HealthCheck{
+		Name:     "database",
+		Critical: true,
+		Probe: func(ctx context.Context) (map[string]interface{}, error) {
+			if db == nil {
+				details := map[string]interface{}{"backend": "memory"}
+				if fallback {
+					return details, fmt.Errorf("postgres unreachable at startup: in-memory fallback, data is not persisted")
+				}
+				return details, nil
+			}
+			stats := db.Stats()
+			details := map[string]interface{}{
+				"backend":          "postgres",
+				"open_connections": stats.OpenConnections,
+				"in_use":           stats.InUse,
+				"idle":             stats.Idle,
+				"wait_count":       stats.WaitCount,
+			}
+			return details, db.PingContext(ctx)
+		},
+	}
+}
+
+// QueueHealthCheck sonde le backend de file ; une panne est tolérée (les messages restent en outbox)
+func QueueHealthCheck(backend string, publisher queue.Publisher) HealthCheck {
+	if backend == "" {
+		backend = queue.BackendRabbitMQ
+	}
+	return HealthCheck{
+		Name: "queue",
+		Probe: func(ctx context.Context) (map[string]interface{}, error) {
+			details := map[string]interface{}{"backend": backend}
+			if publisher == nil {
+				return details, fmt.Errorf("queue backend unavailable: messages kept in the outbox")
+			}
+			if p, ok := publisher.(queue.Pinger); ok {
+				return details, p.Ping(ctx)
+			}
+			return details, nil
+		},
+	}
+}
+
+// StorageHealthCheck sonde le stockage des preuves (MinIO)
+func StorageHealthCheck(storage StorageService) HealthCheck {
+	return HealthCheck{
+		Name: "storage",
+		Probe: func(ctx context.Context) (map[string]interface{}, error) {
+			return nil, storage.Ping(ctx)
+		},
+	}
+}
+
+// OllamaHealthCheck sonde Ollama et la présence des modèles d'embedding et d'analyse juridique
+func OllamaHealthCheck(ollamaURL, embeddingModel, llmModel string) HealthCheck {
+	return HealthCheck{
+		Name: "ollama",
+		Probe: func(ctx context.Context) (map[string]interface{}, error) {
+			// Ollama injoignable : les deux modèles sont indisponibles
+			installed, err := listOllamaModels(ctx, ollamaURL)
+			models := map[string]interface{}{}
+			var missing []string
+			for role, name := range map[string]string{"embedding": embeddingModel, "llm": llmModel} {
+				available := err == nil && hasModel(installed, name)
+				models[role] = map[string]interface{}{"name": name, "available": available}
+				if !available {
+					missing = append(missing, name)
+				}
+			}
+			details := map[string]interface{}{"models": models}
+			if err != nil {
+				return details, err
+			}
+			if len(missing) > 0 {
+				slices.Sort(missing)
+				return details, fmt.Errorf("models not installed: %v", missing)
+			}
+			return details, nil
+		},
+	}
+}
@@ -0,0 +1,157 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func staticCheck(name string, critical bool, err error) HealthCheck {
+	return HealthCheck{Name: name, Critical: critical, Probe: func(ctx context.Context) (map[string]interface{}, error) {
+		return map[string]interface{}{"probe": name}, err
+	}}
+}
+
+func TestReadinessAggregatesComponents(t *testing.T) {
+	down := errors.New("connection refused")
+	cases := []struct {
+		name   string
+		checks []HealthCheck
+		want   string
+	}{
+		{"all up", []HealthCheck{staticCheck("database", true, nil), staticCheck("queue", false, nil)}, ReadinessReady},
+		{"optional component down", []HealthCheck{staticCheck("database", true, nil), staticCheck("queue", false, down)}, ReadinessDegraded},
+		{"critical component down", []HealthCheck{staticCheck("database", true, down), staticCheck("queue", false, down)}, ReadinessNotReady},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			r := NewHealthService(time.Second, tc.checks...).Readiness(context.Background())
+			if r.Status != tc.want {
+				t.Errorf("status = %s, want %s", r.Status, tc.want)
+			}
+			if r.Ready() != (tc.want != ReadinessNotReady) {
+				t.Errorf("ready = %v for status %s", r.Ready(), r.Status)
+			}
+			if len(r.Components) != len(tc.checks) {
+				t.Fatalf("components = %+v", r.Components)
+			}
+			for i, c := range r.Components {
+				if c.Name != tc.checks[i].Name || c.Details["probe"] != c.Name {
+					t.Errorf("component %d = %+v", i, c)
+				}
+				if (c.Status == HealthDown) != (c.Error != "") {
+					t.Errorf("component %s: status %s with error %q", c.Name, c.Status, c.Error)
+				}
+			}
+		})
+	}
+}
+
+func TestReadinessTimesOutSlowProbes(t *testing.T) {
+	hang := HealthCheck{Name: "ollama", Probe: func(ctx context.Context) (map[string]interface{}, error) {
+		time.Sleep(time.Second) // Ignore le contexte
+		return nil, nil
+	}}
+	start := time.Now()
+	r := NewHealthService(50*time.Millisecond, hang, staticCheck("database", true, nil)).Readiness(context.Background())
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("readiness took %s", elapsed)
+	}
+	if r.Status != ReadinessDegraded || r.Components[0].Status != HealthDown || r.Components[0].LatencyMS < 50 {
+		t.Errorf("readiness = %+v", r)
+	}
+}
+
+func TestReadinessIsCachedBetweenCalls(t *testing.T) {
+	var probes atomic.Int32
+	counted := HealthCheck{Name: "database", Critical: true, Probe: func(ctx context.Context) (map[string]interface{}, error) {
+		probes.Add(1)
+		return map[string]interface{}{"in_use": 3}, errors.New("dial tcp 10.0.0.5:5432: connection refused")
+	}}
+	svc := NewHealthService(time.Second, counted).(*healthService)
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			svc.Readiness(context.Background())
+		}()
+	}
+	wg.Wait()
+	if n := probes.Load(); n != 1 {
+		t.Errorf("probes = %d, want 1 within the cache interval", n)
+	}
+
+	svc.ttl = 0
+	r := svc.Readiness(context.Background())
+	if n := probes.Load(); n != 2 {
+		t.Errorf("probes = %d, want 2 once the cache expired", n)
+	}
+
+	// La vue publique ne garde que le statut et la latence
+	summary := r.Summary()
+	if c := summary.Components[0]; c.Status != HealthDown || c.Error != "" || c.Details != nil {
+		t.Errorf("public component = %+v", c)
+	}
+	if r.Components[0].Error == "" || r.Components[0].Details == nil {
+		t.Errorf("summary altered the detailed view: %+v", r.Components[0])
+	}
+}
+
+func TestDatabaseHealthCheckWithoutPostgres(t *testing.T) {
+	if _, err := DatabaseHealthCheck(nil, false).Probe(context.Background()); err != nil {
+		t.Errorf("memory backend by choice = %v, want up", err)
+	}
+	details, err := DatabaseHealthCheck(nil, true).Probe(context.Background())
+	if err == nil || details["backend"] != "memory" {
+		t.Errorf("in-memory fallback = %v, %v; want down", details, err)
+	}
+}
+
+func TestQueueHealthCheckWithoutBroker(t *testing.T) {
+	details, err := QueueHealthCheck("", nil).Probe(context.Background())
+	if err == nil || details["backend"] != "rabbitmq" {
+		t.Errorf("missing broker = %v, %v", details, err)
+	}
+}
+
+func TestOllamaHealthCheckReportsModels(t *testing.T) {
+	ollama := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/tags" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"models":[{"name":"nomic-embed-text:latest"},{"name":"mistral:7b"}]}`))
+	}))
+	defer ollama.Close()
+
+	model := func(details map[string]interface{}, role string) map[string]interface{} {
+		models, _ := details["models"].(map[string]interface{})
+		m, _ := models[role].(map[string]interface{})
+		return m
+	}
+
+	details, err := OllamaHealthCheck(ollama.URL, "nomic-embed-text", "mistral").Probe(context.Background())
+	if err == nil {
+		t.Error("missing LLM model not reported")
+	}
+	if model(details, "embedding")["available"] != true || model(details, "llm")["available"] != false {
+		t.Errorf("models = %v", details)
+	}
+
+	if _, err := OllamaHealthCheck(ollama.URL, "nomic-embed-text", "mistral:7b").Probe(context.Background()); err != nil {
+		t.Errorf("all models installed = %v", err)
+	}
+
+	ollama.Close()
+	details, err = OllamaHealthCheck(ollama.URL, "nomic-embed-text", "mistral:7b").Probe(context.Background())
+	if err == nil || model(details, "embedding")["available"] != false {
+		t.Errorf("unreachable ollama = %v, %v", details, err)
+	}
+}
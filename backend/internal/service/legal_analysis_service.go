@@ -6,22 +6,27 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
-	"log"
 	"net/http"
-	"os"
 	"strings"
 	"time"
+
+	"github.com/openvote/backend/internal/platform/logging"
+	"github.com/openvote/backend/internal/platform/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // LegalAnalysisService analyse les rapports terrain à la lumière du droit via un LLM local
 type LegalAnalysisService interface {
 	AnalyzeIncident(ctx context.Context, incident IncidentContext) (*LegalAnalysis, error)
+	GetModel() string
 }
 
 // IncidentContext contient toutes les informations nécessaires pour l'analyse juridique
 type IncidentContext struct {
 	IncidentType string
 	Description  string
+	ReportCount  int // Nombre de signalements concordants (analyse au niveau de l'événement)
 	Articles     []ArticleMatch
 }
 
@@ -49,25 +54,23 @@ type ViolationItem struct {
 	Severity      string `json:"severity"` // mineur, modéré, grave, critique
 }
 
+var llmLogger = logging.For("llm")
+
 type legalAnalysisService struct {
 	ollamaURL string
 	model     string
 	client    *http.Client
 }
 
-func NewLegalAnalysisService() LegalAnalysisService {
-	url := os.Getenv("OLLAMA_URL")
-	if url == "" {
-		url = "http://localhost:11434"
-	}
-
-	model := os.Getenv("LLM_MODEL")
+// NewLegalAnalysisService interroge le modèle model (config.Ollama.LLMModel, "mistral" par
+// défaut) de l'instance Ollama ollamaURL
+func NewLegalAnalysisService(ollamaURL, model string) LegalAnalysisService {
 	if model == "" {
 		model = "mistral"
 	}
 
 	return &legalAnalysisService{
-		ollamaURL: url,
+		ollamaURL: ollamaURL,
 		model:     model,
 		client: &http.Client{
 			Timeout: 120 * time.Second, // LLM peut prendre du temps
@@ -75,6 +78,10 @@ func NewLegalAnalysisService() LegalAnalysisService {
 	}
 }
 
+func (s *legalAnalysisService) GetModel() string {
+	return s.model
+}
+
 // ollamaGenerateRequest est la requête envoyée à l'API Ollama /api/generate
 type ollamaGenerateRequest struct {
 	Model  string `json:"model"`
@@ -88,6 +95,16 @@ type ollamaGenerateResponse struct {
 }
 
 func (s *legalAnalysisService) AnalyzeIncident(ctx context.Context, incident IncidentContext) (*LegalAnalysis, error) {
+	ctx, span := telemetry.StartSpan(ctx, "ollama generate", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("ollama.model", s.model)))
+	start := time.Now()
+	analysis, err := s.analyzeIncident(ctx, incident)
+	telemetry.ObserveModelCall("generate", s.model, start, err)
+	telemetry.EndSpan(span, err)
+	return analysis, err
+}
+
+func (s *legalAnalysisService) analyzeIncident(ctx context.Context, incident IncidentContext) (*LegalAnalysis, error) {
 	prompt := buildLegalPrompt(incident)
 
 	reqBody := ollamaGenerateRequest{
@@ -107,7 +124,8 @@ func (s *legalAnalysisService) AnalyzeIncident(ctx context.Context, incident Inc
 	}
 	req.Header.Set("Content-Type", "application/json")
 
-	log.Printf("[LLM] Analyse juridique en cours avec %s...", s.model)
+	// Le prompt reprend la description de l'incident : il n'est jamais journalisé
+	llmLogger.DebugContext(ctx, "Analyse juridique en cours", "model", s.model)
 	resp, err := s.client.Do(req)
 	if err != nil {
 		return nil, fmt.Errorf("erreur appel Ollama LLM: %w", err)
@@ -124,7 +142,7 @@ func (s *legalAnalysisService) AnalyzeIncident(ctx context.Context, incident Inc
 		return nil, fmt.Errorf("erreur décodage réponse LLM: %w", err)
 	}
 
-	log.Printf("[LLM] Analyse terminée (%d caractères)", len(result.Response))
+	llmLogger.InfoContext(ctx, "Analyse juridique terminée", "model", s.model, "response_chars", len(result.Response))
 
 	// Parser la réponse du LLM
 	analysis := parseLLMResponse(result.Response, incident)
@@ -142,6 +160,9 @@ INCIDENT SIGNALÉ:
 	sb.WriteString(incident.IncidentType)
 	sb.WriteString("\n- Description: ")
 	sb.WriteString(incident.Description)
+	if incident.ReportCount > 1 {
+		sb.WriteString(fmt.Sprintf("\n- Signalements concordants: %d", incident.ReportCount))
+	}
 	sb.WriteString("\n\nARTICLES DE LOI PERTINENTS:\n")
 
 	for i, art := range incident.Articles {
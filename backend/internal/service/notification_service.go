@@ -0,0 +1,340 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+	"github.com/openvote/backend/internal/platform/logging"
+	"github.com/openvote/backend/internal/platform/notify"
+)
+
+var notifyLogger = logging.For("notify")
+
+const (
+	notificationMaxAttempts   = 5
+	notificationBaseBackoff   = time.Minute
+	notificationMaxBackoff    = time.Hour
+	notificationBatchSize     = 50
+	notificationDeliveryLease = time.Minute
+)
+
+var (
+	ErrInvalidPreferences           = errors.New("invalid notification preferences")
+	ErrNotificationDeliveryNotFound = errors.New("notification delivery not found")
+)
+
+// NotificationRequest décrit une notification à envoyer à un ensemble d'utilisateurs
+type NotificationRequest struct {
+	Kind    string
+	UserIDs []string
+	// Data alimente les modèles FR/EN du type
+	Data interface{}
+	// DedupKey (identifiant de l'événement source) : un même événement n'est notifié qu'une fois
+	DedupKey string
+	// Channels restreint les canaux utilisés (nil : tous ceux choisis par l'utilisateur)
+	Channels []string
+	// Urgent ignore les heures calmes
+	Urgent bool
+}
+
+// NotificationService envoie les notifications selon les préférences de chaque utilisateur
+type NotificationService interface {
+	// Notify planifie les envois et retourne leur nombre ; l'envoi effectif est fait par DeliverDue
+	Notify(ctx context.Context, req NotificationRequest) (int, error)
+	// DeliverDue envoie les notifications échues (premières tentatives, reprises, fin des heures calmes)
+	DeliverDue(ctx context.Context) (int, error)
+
+	GetPreferences(ctx context.Context, userID string) (*entity.NotificationPreferences, error)
+	UpdatePreferences(ctx context.Context, prefs *entity.NotificationPreferences) error
+
+	ListInbox(ctx context.Context, userID string, unreadOnly bool, limit int) ([]entity.Notification, int, error)
+	MarkRead(ctx context.Context, userID, id string) (int, error)
+
+	ListDeliveries(ctx context.Context, status string, limit int) ([]entity.NotificationDelivery, error)
+	RetryDelivery(ctx context.Context, id string) error
+	// Channels liste les canaux configurés sur cette instance
+	Channels() []string
+}
+
+type notificationService struct {
+	repo     repository.NotificationRepository
+	channels map[string]notify.Channel
+	now      func() time.Time
+}
+
+// NewNotificationService : la boîte de réception in-app est toujours disponible ; les canaux
+// e-mail et SMS ne sont passés que s'ils sont configurés
+func NewNotificationService(repo repository.NotificationRepository, channels ...notify.Channel) NotificationService {
+	s := &notificationService{repo: repo, channels: make(map[string]notify.Channel), now: time.Now}
+	s.channels[notify.ChannelInbox] = &inboxChannel{repo: repo}
+	for _, ch := range channels {
+		s.channels[ch.Name()] = ch
+	}
+	return s
+}
+
+func (s *notificationService) Channels() []string {
+	names := make([]string, 0, len(s.channels))
+	for name := range s.channels {
+		names = append(names, name)
+	}
+	slices.Sort(names)
+	return names
+}
+
+// defaultNotificationPreferences : boîte de réception uniquement, en français
+func defaultNotificationPreferences(userID string) *entity.NotificationPreferences {
+	return &entity.NotificationPreferences{
+		UserID:     userID,
+		Locale:     defaultNotificationLocale,
+		Channels:   []string{notify.ChannelInbox},
+		MutedKinds: []string{},
+		Timezone:   defaultAlertTimezone,
+	}
+}
+
+func (s *notificationService) GetPreferences(ctx context.Context, userID string) (*entity.NotificationPreferences, error) {
+	prefs, err := s.repo.GetPreferences(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if prefs == nil {
+		return defaultNotificationPreferences(userID), nil
+	}
+	return prefs, nil
+}
+
+func (s *notificationService) UpdatePreferences(ctx context.Context, prefs *entity.NotificationPreferences) error {
+	if err := validateNotificationPreferences(prefs); err != nil {
+		return err
+	}
+	return s.repo.SavePreferences(ctx, prefs)
+}
+
+func validateNotificationPreferences(p *entity.NotificationPreferences) error {
+	if p.Locale == "" {
+		p.Locale = defaultNotificationLocale
+	}
+	if !slices.Contains(NotificationLocales, p.Locale) {
+		return fmt.Errorf("%w: locale must be fr or en", ErrInvalidPreferences)
+	}
+	for _, ch := range p.Channels {
+		switch ch {
+		case notify.ChannelInbox:
+		case notify.ChannelEmail:
+			if !strings.Contains(p.Email, "@") {
+				return fmt.Errorf("%w: email is required for the email channel", ErrInvalidPreferences)
+			}
+		case notify.ChannelSMS:
+			if !strings.HasPrefix(p.Phone, "+") {
+				return fmt.Errorf("%w: phone in international format (+237...) is required for the sms channel", ErrInvalidPreferences)
+			}
+		default:
+			return fmt.Errorf("%w: unknown channel %q", ErrInvalidPreferences, ch)
+		}
+	}
+	if (p.QuietHoursStart == "") != (p.QuietHoursEnd == "") {
+		return fmt.Errorf("%w: quiet hours need both a start and an end", ErrInvalidPreferences)
+	}
+	for _, bound := range []string{p.QuietHoursStart, p.QuietHoursEnd} {
+		if _, err := parseTimeOfDay(bound); err != nil {
+			return fmt.Errorf("%w: %v", ErrInvalidPreferences, err)
+		}
+	}
+	if p.Timezone == "" {
+		p.Timezone = defaultAlertTimezone
+	}
+	if _, err := time.LoadLocation(p.Timezone); err != nil {
+		return fmt.Errorf("%w: unknown timezone %q", ErrInvalidPreferences, p.Timezone)
+	}
+	if p.MutedKinds == nil {
+		p.MutedKinds = []string{}
+	}
+	return nil
+}
+
+// quietHoursEnd retourne la fin des heures calmes en cours, ou false hors heures calmes
+func quietHoursEnd(now time.Time, p *entity.NotificationPreferences) (time.Time, bool) {
+	if p.QuietHoursStart == "" || p.QuietHoursEnd == "" {
+		return time.Time{}, false
+	}
+	loc, err := time.LoadLocation(p.Timezone)
+	if err != nil {
+		loc = time.UTC
+	}
+	if !inTimeOfDay(now, entity.AlertRule{TimeOfDayStart: p.QuietHoursStart, TimeOfDayEnd: p.QuietHoursEnd}, loc) {
+		return time.Time{}, false
+	}
+	end, _ := parseTimeOfDay(p.QuietHoursEnd)
+	local := now.In(loc)
+	until := time.Date(local.Year(), local.Month(), local.Day(), end/60, end%60, 0, 0, loc)
+	if !until.After(local) {
+		// Plage passant minuit, avant minuit : fin le lendemain
+		until = until.AddDate(0, 0, 1)
+	}
+	return until, true
+}
+
+func (s *notificationService) Notify(ctx context.Context, req NotificationRequest) (int, error) {
+	if _, ok := notificationTemplates[req.Kind]; !ok {
+		return 0, fmt.Errorf("unknown notification kind %q", req.Kind)
+	}
+
+	created := 0
+	for _, userID := range req.UserIDs {
+		prefs, err := s.GetPreferences(ctx, userID)
+		if err != nil {
+			return created, fmt.Errorf("failed to load preferences of %s: %w", userID, err)
+		}
+		if slices.Contains(prefs.MutedKinds, req.Kind) {
+			continue
+		}
+		subject, body, err := renderNotification(req.Kind, prefs.Locale, req.Data)
+		if err != nil {
+			return created, err
+		}
+
+		for _, name := range prefs.Channels {
+			if _, ok := s.channels[name]; !ok {
+				continue
+			}
+			if req.Channels != nil && !slices.Contains(req.Channels, name) {
+				continue
+			}
+			delivery := &entity.NotificationDelivery{
+				UserID:        userID,
+				Channel:       name,
+				Kind:          req.Kind,
+				DedupKey:      req.DedupKey,
+				Subject:       subject,
+				Body:          body,
+				NextAttemptAt: s.now(),
+			}
+			switch name {
+			case notify.ChannelEmail:
+				delivery.Recipient = prefs.Email
+			case notify.ChannelSMS:
+				delivery.Recipient = prefs.Phone
+			default:
+				delivery.Recipient = userID
+			}
+			// La boîte de réception est silencieuse : seuls l'e-mail et le SMS attendent la fin des heures calmes
+			if name != notify.ChannelInbox && !req.Urgent {
+				if until, quiet := quietHoursEnd(s.now(), prefs); quiet {
+					delivery.NextAttemptAt = until
+				}
+			}
+
+			isNew, err := s.repo.CreateDelivery(ctx, delivery)
+			if err != nil {
+				return created, fmt.Errorf("failed to schedule %s notification for %s: %w", name, userID, err)
+			}
+			if isNew {
+				created++
+			}
+		}
+	}
+	return created, nil
+}
+
+func (s *notificationService) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := s.repo.ClaimDueDeliveries(ctx, notificationBatchSize, notificationDeliveryLease)
+	if err != nil {
+		return 0, err
+	}
+	for _, d := range deliveries {
+		s.deliver(ctx, d)
+	}
+	return len(deliveries), nil
+}
+
+// deliver envoie une notification et consigne le résultat (succès, nouvelle tentative ou abandon)
+func (s *notificationService) deliver(ctx context.Context, d entity.NotificationDelivery) {
+	ch, ok := s.channels[d.Channel]
+	if !ok {
+		if err := s.repo.MarkFailed(ctx, d.ID, "canal non configuré", nil); err != nil {
+			notifyLogger.ErrorContext(ctx, "Impossible de consigner l'échec", "delivery_id", d.ID, "error", err)
+		}
+		return
+	}
+
+	sendErr := ch.Send(ctx, notify.Message{UserID: d.UserID, Recipient: d.Recipient, Kind: d.Kind, Subject: d.Subject, Body: d.Body})
+	if sendErr == nil {
+		if err := s.repo.MarkSent(ctx, d.ID); err != nil {
+			notifyLogger.ErrorContext(ctx, "Impossible de marquer la notification comme envoyée", "delivery_id", d.ID, "error", err)
+		}
+		return
+	}
+
+	var retryAt *time.Time
+	if d.Attempts < notificationMaxAttempts && !errors.Is(sendErr, notify.ErrPermanent) {
+		next := s.now().Add(notificationBackoff(d.Attempts))
+		retryAt = &next
+		notifyLogger.WarnContext(ctx, "Envoi échoué, nouvelle tentative planifiée", "delivery_id", d.ID, "channel", d.Channel, "attempt", d.Attempts, "retry_at", next, "error", sendErr)
+	} else {
+		notifyLogger.ErrorContext(ctx, "Envoi abandonné", "delivery_id", d.ID, "channel", d.Channel, "attempts", d.Attempts, "error", sendErr)
+	}
+	if err := s.repo.MarkFailed(ctx, d.ID, sendErr.Error(), retryAt); err != nil {
+		notifyLogger.ErrorContext(ctx, "Impossible de consigner l'échec", "delivery_id", d.ID, "error", err)
+	}
+}
+
+// notificationBackoff : 1 min, 2 min, 4 min... plafonné à 1 h
+func notificationBackoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	delay := notificationBaseBackoff << (attempts - 1)
+	if delay <= 0 || delay > notificationMaxBackoff {
+		return notificationMaxBackoff
+	}
+	return delay
+}
+
+func (s *notificationService) ListInbox(ctx context.Context, userID string, unreadOnly bool, limit int) ([]entity.Notification, int, error) {
+	items, err := s.repo.ListInbox(ctx, userID, unreadOnly, limit)
+	if err != nil {
+		return nil, 0, err
+	}
+	unread, err := s.repo.CountUnread(ctx, userID)
+	if err != nil {
+		return nil, 0, err
+	}
+	return items, unread, nil
+}
+
+func (s *notificationService) MarkRead(ctx context.Context, userID, id string) (int, error) {
+	return s.repo.MarkRead(ctx, userID, id)
+}
+
+func (s *notificationService) ListDeliveries(ctx context.Context, status string, limit int) ([]entity.NotificationDelivery, error) {
+	return s.repo.ListDeliveries(ctx, status, limit)
+}
+
+func (s *notificationService) RetryDelivery(ctx context.Context, id string) error {
+	found, err := s.repo.ResetDelivery(ctx, id)
+	if err != nil {
+		return err
+	}
+	if !found {
+		return ErrNotificationDeliveryNotFound
+	}
+	return nil
+}
+
+// inboxChannel dépose la notification dans la boîte de réception in-app
+type inboxChannel struct {
+	repo repository.NotificationRepository
+}
+
+func (c *inboxChannel) Name() string { return notify.ChannelInbox }
+
+func (c *inboxChannel) Send(ctx context.Context, msg notify.Message) error {
+	return c.repo.CreateInboxItem(ctx, &entity.Notification{UserID: msg.UserID, Kind: msg.Kind, Title: msg.Subject, Body: msg.Body})
+}
@@ -0,0 +1,335 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/event"
+	"github.com/openvote/backend/internal/platform/notify"
+)
+
+// Mock de NotificationRepository en mémoire ; now fixe l'horloge des envois dus
+type mockNotificationRepo struct {
+	prefs      map[string]*entity.NotificationPreferences
+	inbox      []entity.Notification
+	deliveries []*entity.NotificationDelivery
+	now        func() time.Time
+}
+
+func newMockNotificationRepo(now func() time.Time, prefs ...entity.NotificationPreferences) *mockNotificationRepo {
+	m := &mockNotificationRepo{prefs: map[string]*entity.NotificationPreferences{}, now: now}
+	for i := range prefs {
+		m.prefs[prefs[i].UserID] = &prefs[i]
+	}
+	return m
+}
+
+func (m *mockNotificationRepo) GetPreferences(ctx context.Context, userID string) (*entity.NotificationPreferences, error) {
+	return m.prefs[userID], nil
+}
+func (m *mockNotificationRepo) SavePreferences(ctx context.Context, p *entity.NotificationPreferences) error {
+	m.prefs[p.UserID] = p
+	return nil
+}
+func (m *mockNotificationRepo) CreateInboxItem(ctx context.Context, n *entity.Notification) error {
+	n.ID = fmt.Sprintf("inbox-%d", len(m.inbox)+1)
+	m.inbox = append(m.inbox, *n)
+	return nil
+}
+func (m *mockNotificationRepo) ListInbox(ctx context.Context, userID string, unreadOnly bool, limit int) ([]entity.Notification, error) {
+	var items []entity.Notification
+	for _, n := range m.inbox {
+		if n.UserID == userID && (!unreadOnly || n.ReadAt == nil) {
+			items = append(items, n)
+		}
+	}
+	return items, nil
+}
+func (m *mockNotificationRepo) CountUnread(ctx context.Context, userID string) (int, error) {
+	items, _ := m.ListInbox(ctx, userID, true, 0)
+	return len(items), nil
+}
+func (m *mockNotificationRepo) MarkRead(ctx context.Context, userID, id string) (int, error) {
+	count := 0
+	now := m.now()
+	for i := range m.inbox {
+		n := &m.inbox[i]
+		if n.UserID == userID && n.ReadAt == nil && (id == "" || n.ID == id) {
+			n.ReadAt = &now
+			count++
+		}
+	}
+	return count, nil
+}
+func (m *mockNotificationRepo) CreateDelivery(ctx context.Context, d *entity.NotificationDelivery) (bool, error) {
+	for _, existing := range m.deliveries {
+		if existing.UserID == d.UserID && existing.Channel == d.Channel && existing.DedupKey == d.DedupKey {
+			return false, nil
+		}
+	}
+	d.ID = fmt.Sprintf("del-%d", len(m.deliveries)+1)
+	d.Status = entity.NotificationPending
+	m.deliveries = append(m.deliveries, d)
+	return true, nil
+}
+func (m *mockNotificationRepo) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.NotificationDelivery, error) {
+	var due []entity.NotificationDelivery
+	for _, d := range m.deliveries {
+		if d.Status == entity.NotificationPending && !d.NextAttemptAt.After(m.now()) {
+			d.Attempts++
+			d.NextAttemptAt = m.now().Add(lease)
+			due = append(due, *d)
+		}
+	}
+	return due, nil
+}
+func (m *mockNotificationRepo) find(id string) *entity.NotificationDelivery {
+	for _, d := range m.deliveries {
+		if d.ID == id {
+			return d
+		}
+	}
+	return nil
+}
+func (m *mockNotificationRepo) MarkSent(ctx context.Context, id string) error {
+	m.find(id).Status = entity.NotificationSent
+	return nil
+}
+func (m *mockNotificationRepo) MarkFailed(ctx context.Context, id, cause string, retryAt *time.Time) error {
+	d := m.find(id)
+	d.LastError = cause
+	if retryAt == nil {
+		d.Status = entity.NotificationFailed
+	} else {
+		d.NextAttemptAt = *retryAt
+	}
+	return nil
+}
+func (m *mockNotificationRepo) ListDeliveries(ctx context.Context, status string, limit int) ([]entity.NotificationDelivery, error) {
+	var list []entity.NotificationDelivery
+	for _, d := range m.deliveries {
+		if status == "" || string(d.Status) == status {
+			list = append(list, *d)
+		}
+	}
+	return list, nil
+}
+func (m *mockNotificationRepo) ResetDelivery(ctx context.Context, id string) (bool, error) {
+	d := m.find(id)
+	if d == nil {
+		return false, nil
+	}
+	d.Status, d.Attempts, d.NextAttemptAt = entity.NotificationPending, 0, m.now()
+	return true, nil
+}
+
+// recordingChannel enregistre les messages ; err simule une panne du fournisseur
+type recordingChannel struct {
+	name string
+	sent []notify.Message
+	err  error
+}
+
+func (c *recordingChannel) Name() string { return c.name }
+func (c *recordingChannel) Send(ctx context.Context, msg notify.Message) error {
+	if c.err != nil {
+		return c.err
+	}
+	c.sent = append(c.sent, msg)
+	return nil
+}
+
+var notificationTestAlert = event.AlertTriggered{
+	AlertID: "alert-1", RuleName: "Fraude Douala", GroupKey: "CE", Severity: 4, RegionID: "CE", ReportCount: 3,
+}
+
+// newNotificationTestService : horloge fixée à 14h00 heure de Douala
+func newNotificationTestService(prefs ...entity.NotificationPreferences) (*notificationService, *mockNotificationRepo, *recordingChannel, *recordingChannel, *time.Time) {
+	loc, _ := time.LoadLocation("Africa/Douala")
+	now := time.Date(2025, 10, 12, 14, 0, 0, 0, loc)
+	clock := func() time.Time { return now }
+
+	repo := newMockNotificationRepo(clock, prefs...)
+	email := &recordingChannel{name: notify.ChannelEmail}
+	sms := &recordingChannel{name: notify.ChannelSMS}
+	svc := NewNotificationService(repo, email, sms).(*notificationService)
+	svc.now = clock
+	return svc, repo, email, sms, &now
+}
+
+func TestNotify_RoutesByPreferencesAndLocale(t *testing.T) {
+	svc, repo, email, sms, _ := newNotificationTestService(
+		entity.NotificationPreferences{UserID: "fr-user", Locale: "fr", Email: "coord@example.cm", Channels: []string{"email", "inbox"}, Timezone: "Africa/Douala"},
+		entity.NotificationPreferences{UserID: "en-user", Locale: "en", Phone: "+237690000000", Channels: []string{"sms"}, Timezone: "Africa/Douala"},
+	)
+	ctx := context.Background()
+
+	n, err := svc.Notify(ctx, NotificationRequest{Kind: NotificationAlertTriggered, UserIDs: []string{"fr-user", "en-user", "no-prefs"}, Data: notificationTestAlert, DedupKey: "evt-1"})
+	if err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	// fr-user : email + inbox ; en-user : sms ; no-prefs : inbox par défaut
+	if n != 4 {
+		t.Fatalf("expected 4 deliveries, got %d", n)
+	}
+	if sent, _ := svc.DeliverDue(ctx); sent != 4 {
+		t.Fatalf("expected 4 due deliveries, got %d", sent)
+	}
+
+	if len(email.sent) != 1 || email.sent[0].Recipient != "coord@example.cm" || !strings.HasPrefix(email.sent[0].Subject, "[OpenVote] Alerte") {
+		t.Errorf("unexpected email: %+v", email.sent)
+	}
+	if len(sms.sent) != 1 || sms.sent[0].Recipient != "+237690000000" || !strings.Contains(sms.sent[0].Body, "Rule \"Fraude Douala\" fired") {
+		t.Errorf("unexpected sms: %+v", sms.sent)
+	}
+	if len(repo.inbox) != 2 {
+		t.Errorf("expected 2 inbox items, got %d", len(repo.inbox))
+	}
+
+	// Même événement rejoué : aucun nouvel envoi
+	if n, _ := svc.Notify(ctx, NotificationRequest{Kind: NotificationAlertTriggered, UserIDs: []string{"fr-user"}, Data: notificationTestAlert, DedupKey: "evt-1"}); n != 0 {
+		t.Errorf("duplicate event should not be notified again, got %d", n)
+	}
+}
+
+func TestNotify_MutedKindsAndChannelRestriction(t *testing.T) {
+	svc, _, _, _, _ := newNotificationTestService(
+		entity.NotificationPreferences{UserID: "muted", Locale: "fr", Channels: []string{"inbox"}, MutedKinds: []string{NotificationAlertTriggered}, Timezone: "Africa/Douala"},
+		entity.NotificationPreferences{UserID: "both", Locale: "fr", Email: "a@example.cm", Channels: []string{"email", "inbox"}, Timezone: "Africa/Douala"},
+	)
+	n, err := svc.Notify(context.Background(), NotificationRequest{
+		Kind: NotificationAlertTriggered, UserIDs: []string{"muted", "both"}, Data: notificationTestAlert, DedupKey: "evt-2",
+		Channels: []string{notify.ChannelInbox},
+	})
+	if err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if n != 1 {
+		t.Errorf("expected only the inbox of 'both', got %d deliveries", n)
+	}
+}
+
+func TestNotify_QuietHoursDeferNonUrgent(t *testing.T) {
+	// Heures calmes 13h-07h (plage passant minuit) : il est 14h
+	prefs := entity.NotificationPreferences{UserID: "u1", Locale: "fr", Email: "a@example.cm", Channels: []string{"email", "inbox"},
+		QuietHoursStart: "13:00", QuietHoursEnd: "07:00", Timezone: "Africa/Douala"}
+	svc, repo, email, _, now := newNotificationTestService(prefs)
+	ctx := context.Background()
+
+	if _, err := svc.Notify(ctx, NotificationRequest{Kind: NotificationAlertTriggered, UserIDs: []string{"u1"}, Data: notificationTestAlert, DedupKey: "evt-3"}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	svc.DeliverDue(ctx)
+	if len(email.sent) != 0 {
+		t.Fatalf("email should wait for the end of quiet hours")
+	}
+	if len(repo.inbox) != 1 {
+		t.Errorf("inbox is not affected by quiet hours")
+	}
+	wantAt := time.Date(2025, 10, 13, 7, 0, 0, 0, now.Location())
+	for _, d := range repo.deliveries {
+		if d.Channel == notify.ChannelEmail && !d.NextAttemptAt.Equal(wantAt) {
+			t.Errorf("email deferred to %s, want %s", d.NextAttemptAt, wantAt)
+		}
+	}
+
+	// Une alerte urgente passe outre
+	if _, err := svc.Notify(ctx, NotificationRequest{Kind: NotificationAlertTriggered, UserIDs: []string{"u1"}, Data: notificationTestAlert, DedupKey: "evt-4", Urgent: true}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	svc.DeliverDue(ctx)
+	if len(email.sent) != 1 {
+		t.Errorf("urgent email should be sent immediately, got %d", len(email.sent))
+	}
+
+	// Le lendemain à 7h, l'envoi différé part
+	*now = wantAt
+	svc.DeliverDue(ctx)
+	if len(email.sent) != 2 {
+		t.Errorf("deferred email should be sent at the end of quiet hours, got %d", len(email.sent))
+	}
+}
+
+func TestDeliverDue_RetryThenGiveUp(t *testing.T) {
+	prefs := entity.NotificationPreferences{UserID: "u1", Locale: "fr", Phone: "+237690000000", Channels: []string{"sms"}, Timezone: "Africa/Douala"}
+	svc, repo, _, sms, now := newNotificationTestService(prefs)
+	sms.err = errors.New("gateway timeout")
+	ctx := context.Background()
+
+	svc.Notify(ctx, NotificationRequest{Kind: NotificationAlertTriggered, UserIDs: []string{"u1"}, Data: notificationTestAlert, DedupKey: "evt-5"})
+	for i := 0; i < notificationMaxAttempts; i++ {
+		if n, _ := svc.DeliverDue(ctx); n != 1 {
+			t.Fatalf("attempt %d: expected the delivery to be due", i+1)
+		}
+		*now = now.Add(notificationMaxBackoff)
+	}
+	d := repo.deliveries[0]
+	if d.Status != entity.NotificationFailed || d.Attempts != notificationMaxAttempts || d.LastError != "gateway timeout" {
+		t.Errorf("unexpected delivery after exhaustion: %+v", d)
+	}
+
+	// Relance manuelle après réparation de la passerelle
+	sms.err = nil
+	if err := svc.RetryDelivery(ctx, d.ID); err != nil {
+		t.Fatalf("RetryDelivery: %v", err)
+	}
+	svc.DeliverDue(ctx)
+	if d.Status != entity.NotificationSent || len(sms.sent) != 1 {
+		t.Errorf("retried delivery should be sent: %+v", d)
+	}
+	if err := svc.RetryDelivery(ctx, "missing"); !errors.Is(err, ErrNotificationDeliveryNotFound) {
+		t.Errorf("expected ErrNotificationDeliveryNotFound, got %v", err)
+	}
+}
+
+func TestDeliverDue_PermanentFailure(t *testing.T) {
+	prefs := entity.NotificationPreferences{UserID: "u1", Locale: "fr", Email: "a@example.cm", Channels: []string{"email"}, Timezone: "Africa/Douala"}
+	svc, repo, email, _, _ := newNotificationTestService(prefs)
+	email.err = fmt.Errorf("%w: mailbox unavailable", notify.ErrPermanent)
+	ctx := context.Background()
+
+	svc.Notify(ctx, NotificationRequest{Kind: NotificationAlertTriggered, UserIDs: []string{"u1"}, Data: notificationTestAlert, DedupKey: "evt-6"})
+	svc.DeliverDue(ctx)
+	if d := repo.deliveries[0]; d.Status != entity.NotificationFailed || d.Attempts != 1 {
+		t.Errorf("permanent error should fail at once: %+v", d)
+	}
+}
+
+func TestNotificationBackoff(t *testing.T) {
+	cases := map[int]time.Duration{1: time.Minute, 2: 2 * time.Minute, 4: 8 * time.Minute, 10: time.Hour}
+	for attempts, want := range cases {
+		if got := notificationBackoff(attempts); got != want {
+			t.Errorf("backoff(%d) = %s, want %s", attempts, got, want)
+		}
+	}
+}
+
+func TestValidateNotificationPreferences(t *testing.T) {
+	cases := []struct {
+		name  string
+		prefs entity.NotificationPreferences
+		valid bool
+	}{
+		{"defaults", entity.NotificationPreferences{Channels: []string{"inbox"}}, true},
+		{"email without address", entity.NotificationPreferences{Channels: []string{"email"}}, false},
+		{"sms with local number", entity.NotificationPreferences{Channels: []string{"sms"}, Phone: "690000000"}, false},
+		{"unknown channel", entity.NotificationPreferences{Channels: []string{"pigeon"}}, false},
+		{"unknown locale", entity.NotificationPreferences{Locale: "de"}, false},
+		{"half quiet hours", entity.NotificationPreferences{QuietHoursStart: "22:00"}, false},
+		{"bad timezone", entity.NotificationPreferences{Timezone: "Mars/Olympus"}, false},
+	}
+	for _, tc := range cases {
+		err := validateNotificationPreferences(&tc.prefs)
+		if tc.valid && err != nil {
+			t.Errorf("%s: unexpected error %v", tc.name, err)
+		}
+		if !tc.valid && !errors.Is(err, ErrInvalidPreferences) {
+			t.Errorf("%s: expected ErrInvalidPreferences, got %v", tc.name, err)
+		}
+	}
+}
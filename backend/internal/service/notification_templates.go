@@ -0,0 +1,75 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"text/template"
+)
+
+// Types de notification (un modèle FR/EN par type)
+const (
+	NotificationAlertTriggered     = "alert.triggered"
+	NotificationAlertStatusChanged = "alert.status_changed"
+)
+
+// NotificationLocales : langues disponibles ; le français est la langue par défaut
+var NotificationLocales = []string{"fr", "en"}
+
+const defaultNotificationLocale = "fr"
+
+type notificationTemplate struct {
+	subject *template.Template
+	body    *template.Template
+}
+
+func mustNotificationTemplate(subject, body string) notificationTemplate {
+	return notificationTemplate{
+		subject: template.Must(template.New("subject").Parse(subject)),
+		body:    template.Must(template.New("body").Parse(body)),
+	}
+}
+
+// notificationTemplates[type][langue]
+var notificationTemplates = map[string]map[string]notificationTemplate{
+	NotificationAlertTriggered: {
+		"fr": mustNotificationTemplate(
+			`[OpenVote] Alerte : {{.RuleName}}`,
+			`La règle « {{.RuleName}} » s'est déclenchée : {{.ReportCount}} signalement(s), sévérité {{.Severity}}/5.
+Zone : {{if .GroupKey}}{{.GroupKey}}{{else}}tout le périmètre{{end}}{{if .RegionID}} (région {{.RegionID}}){{end}}.
+Acquittez l'alerte depuis le tableau de bord (réf. {{.AlertID}}).`),
+		"en": mustNotificationTemplate(
+			`[OpenVote] Alert: {{.RuleName}}`,
+			`Rule "{{.RuleName}}" fired: {{.ReportCount}} report(s), severity {{.Severity}}/5.
+Area: {{if .GroupKey}}{{.GroupKey}}{{else}}whole scope{{end}}{{if .RegionID}} (region {{.RegionID}}){{end}}.
+Acknowledge the alert from the dashboard (ref. {{.AlertID}}).`),
+	},
+	NotificationAlertStatusChanged: {
+		"fr": mustNotificationTemplate(
+			`[OpenVote] Alerte {{.AlertID}} : {{.NewStatus}}`,
+			`L'alerte {{.AlertID}} est passée au statut « {{.NewStatus}} »{{if .ChangedBy}} (par {{.ChangedBy}}){{end}}.`),
+		"en": mustNotificationTemplate(
+			`[OpenVote] Alert {{.AlertID}}: {{.NewStatus}}`,
+			`Alert {{.AlertID}} is now "{{.NewStatus}}"{{if .ChangedBy}} (by {{.ChangedBy}}){{end}}.`),
+	},
+}
+
+// renderNotification produit le sujet et le corps dans la langue demandée (français à défaut)
+func renderNotification(kind, locale string, data interface{}) (string, string, error) {
+	byLocale, ok := notificationTemplates[kind]
+	if !ok {
+		return "", "", fmt.Errorf("no template for notification kind %q", kind)
+	}
+	tmpl, ok := byLocale[locale]
+	if !ok {
+		tmpl = byLocale[defaultNotificationLocale]
+	}
+
+	var subject, body bytes.Buffer
+	if err := tmpl.subject.Execute(&subject, data); err != nil {
+		return "", "", fmt.Errorf("failed to render %s subject: %w", kind, err)
+	}
+	if err := tmpl.body.Execute(&body, data); err != nil {
+		return "", "", fmt.Errorf("failed to render %s body: %w", kind, err)
+	}
+	return subject.String(), body.String(), nil
+}
@@ -5,8 +5,10 @@ import (
 	"fmt"
 
 	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/event"
 	"github.com/openvote/backend/internal/domain/repository"
 	"github.com/openvote/backend/internal/platform/queue"
+	"github.com/openvote/backend/internal/platform/telemetry"
 	"github.com/uber/h3-go/v4"
 )
 
@@ -14,22 +16,36 @@ type ReportService interface {
 	CreateReport(ctx context.Context, report *entity.Report) error
 	GetAllReports(ctx context.Context, status string) ([]entity.Report, error)
 	GetReportByID(ctx context.Context, id string) (*entity.Report, error)
-	UpdateReportStatus(ctx context.Context, id string, status entity.ReportStatus) error
+	// UpdateReportStatus applique une décision d'administrateur (changedBy) et publie ReportStatusChanged
+	UpdateReportStatus(ctx context.Context, id string, status entity.ReportStatus, changedBy string) error
+	GetReviewQueue(ctx context.Context, limit int) ([]entity.ReviewQueueItem, error)
 }
 
 type reportService struct {
-	repo      repository.ReportRepository
-	publisher queue.Publisher
+	repo             repository.ReportRepository
+	incidentTypeRepo repository.IncidentTypeRepository
+	userRepo         repository.UserRepository
 }
 
-func NewReportService(repo repository.ReportRepository, publisher queue.Publisher) ReportService {
+// defaultSeverity s'applique aux types d'incidents absents du référentiel
+const defaultSeverity = 3
+
+func NewReportService(repo repository.ReportRepository, incidentTypeRepo repository.IncidentTypeRepository, userRepo repository.UserRepository) ReportService {
 	return &reportService{
-		repo:      repo,
-		publisher: publisher,
+		repo:             repo,
+		incidentTypeRepo: incidentTypeRepo,
+		userRepo:         userRepo,
 	}
 }
 
 func (s *reportService) CreateReport(ctx context.Context, report *entity.Report) error {
+	ctx, span := telemetry.StartSpan(ctx, "ReportService.CreateReport")
+	err := s.createReport(ctx, report)
+	telemetry.EndSpan(span, err)
+	return err
+}
+
+func (s *reportService) createReport(ctx context.Context, report *entity.Report) error {
 	// 1. Validation basique (Business Logic)
 	if report.IncidentType == "" {
 		return fmt.Errorf("incident_type is required")
@@ -86,22 +102,99 @@ func (s *reportService) CreateReport(ctx context.Context, report *entity.Report)
 	cell := h3.LatLngToCell(latLng, 10)
 	report.H3Index = cell.String()
 
-	// 3. Sauvegarde PostgreSQL
-	if err := s.repo.Create(ctx, report); err != nil {
+	// Les identifiants réseau/appareil ne sont conservés que sous forme d'empreinte (détection Sybil)
+	report.SourceFingerprint = fingerprint(report.SourceFingerprint)
+	report.DeviceFingerprint = fingerprint(report.DeviceFingerprint)
+
+	// Sévérité copiée du référentiel : elle détermine la file de traitement et l'ordre de revue
+	report.Severity = defaultSeverity
+	incidentType, err := s.incidentTypeRepo.FindByCodeOrName(ctx, report.IncidentType)
+	if err != nil {
+		return fmt.Errorf("failed to resolve incident type: %w", err)
+	}
+	if incidentType != nil {
+		report.Severity = incidentType.Severity
+	}
+
+	// Région de l'observateur : filtre des abonnés (webhooks, flux temps réel)
+	observer, err := s.userRepo.GetByID(ctx, report.ObserverID)
+	if err != nil {
+		return fmt.Errorf("failed to resolve observer: %w", err)
+	}
+	if observer != nil {
+		report.RegionID = observer.RegionID
+	}
+
+	// 3. Sauvegarde PostgreSQL + messages de publication (outbox) dans la même transaction.
+	// Le relais publie ensuite vers le broker : un broker indisponible ne perd aucun signalement.
+	messages, err := reportCreatedMessages(report)
+	if err != nil {
+		return err
+	}
+	withRequestContext(ctx, messages)
+	if err := s.repo.CreateWithOutbox(ctx, report, messages...); err != nil {
 		return fmt.Errorf("failed to save report to db: %w", err)
 	}
 
-	// 4. Envoi RabbitMQ (Async)
-	// On envoie l'ID ou l'objet complet
-	go func() {
-		// Contexte background pour ne pas être annulé par la requête HTTP
-		if err := s.publisher.Publish(context.Background(), "new_reports", report); err != nil {
-			// Log error (pas de logger configuré dans l'exo, fmt.Print)
-			fmt.Printf("ERROR: failed to publish to rabbitmq: %v\n", err)
+	return nil
+}
+
+// reportCreatedMessages prépare l'événement ReportCreated pour la file de triangulation adaptée
+// à la sévérité et pour l'échange des événements, plus EvidenceUploaded si une preuve est jointe
+func reportCreatedMessages(report *entity.Report) ([]*entity.OutboxMessage, error) {
+	created := event.ReportCreated{
+		ReportID:     report.ID,
+		ObserverID:   report.ObserverID,
+		IncidentType: report.IncidentType,
+		Severity:     report.Severity,
+		RegionID:     report.RegionID,
+		H3Index:      report.H3Index,
+		GPSLocation:  report.GPSLocation,
+		CreatedAt:    report.CreatedAt,
+	}
+	broadcast, err := eventOutboxMessage(created)
+	if err != nil {
+		return nil, err
+	}
+	// Même enveloppe (même identifiant) dans la file de travail que sur l'échange
+	messages := []*entity.OutboxMessage{triangulationMessage(report, broadcast.Payload), broadcast}
+
+	if report.ProofURL != "" {
+		evidence, err := eventOutboxMessage(event.EvidenceUploaded{
+			ReportID: report.ID,
+			ProofURL: report.ProofURL,
+			RegionID: report.RegionID,
+		})
+		if err != nil {
+			return nil, err
 		}
-	}()
+		messages = append(messages, evidence)
+	}
+	return messages, nil
+}
 
-	return nil
+// triangulationMessage place le signalement dans la file de triangulation adaptée à sa sévérité
+func triangulationMessage(report *entity.Report, payload []byte) *entity.OutboxMessage {
+	return &entity.OutboxMessage{Queue: queue.ReportQueue(report.Severity), Payload: payload}
+}
+
+// retriangulationMessage remet un signalement existant en file de triangulation (décision de
+// revue) : le consommateur ne lit que l'identifiant de l'événement ReportCreated
+func retriangulationMessage(report *entity.Report) (*entity.OutboxMessage, error) {
+	msg, err := eventOutboxMessage(event.ReportCreated{
+		ReportID:     report.ID,
+		ObserverID:   report.ObserverID,
+		IncidentType: report.IncidentType,
+		Severity:     report.Severity,
+		RegionID:     report.RegionID,
+		H3Index:      report.H3Index,
+		GPSLocation:  report.GPSLocation,
+		CreatedAt:    report.CreatedAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return triangulationMessage(report, msg.Payload), nil
 }
 
 func (s *reportService) GetAllReports(ctx context.Context, status string) ([]entity.Report, error) {
@@ -112,6 +205,34 @@ func (s *reportService) GetReportByID(ctx context.Context, id string) (*entity.R
 	return s.repo.GetByID(ctx, id)
 }
 
-func (s *reportService) UpdateReportStatus(ctx context.Context, id string, status entity.ReportStatus) error {
-	return s.repo.UpdateStatus(ctx, id, status)
+func (s *reportService) UpdateReportStatus(ctx context.Context, id string, status entity.ReportStatus, changedBy string) error {
+	report, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+	if report == nil {
+		return fmt.Errorf("report %s not found", id)
+	}
+	if report.Status == status {
+		return s.repo.UpdateStatus(ctx, id, status)
+	}
+	// L'événement est écrit avec le nouveau statut : l'un ne peut exister sans l'autre
+	messages, err := eventOutboxMessages(ctx, event.ReportStatusChanged{
+		ReportID:     id,
+		OldStatus:    string(report.Status),
+		NewStatus:    string(status),
+		IncidentType: report.IncidentType,
+		Severity:     report.Severity,
+		RegionID:     report.RegionID,
+		Source:       event.SourceAdmin,
+		ChangedBy:    changedBy,
+	})
+	if err != nil {
+		return err
+	}
+	return s.repo.UpdateStatusWithOutbox(ctx, id, status, messages...)
+}
+
+func (s *reportService) GetReviewQueue(ctx context.Context, limit int) ([]entity.ReviewQueueItem, error) {
+	return s.repo.GetReviewQueue(ctx, limit)
 }
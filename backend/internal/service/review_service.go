@@ -0,0 +1,225 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+
+	"github.com/google/uuid"
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+)
+
+var (
+	ErrClusterNotFound   = errors.New("suspicious cluster not found")
+	ErrConflictNotFound  = errors.New("conflict not found or already resolved")
+	ErrAssigneeNotFound  = errors.New("assignee not found")
+	ErrInvalidAssignee   = errors.New("assignee_id must be a user UUID")
+	ErrInvalidResolution = errors.New("rejected reports must belong to the conflict")
+)
+
+// ReviewService applique les décisions des modérateurs sur les files de revue de la triangulation
+type ReviewService interface {
+	ListClusters(ctx context.Context, status string) ([]entity.SuspiciousCluster, error)
+	// ReviewCluster enregistre la décision puis l'applique aux signalements du cluster : collusion
+	// confirmée, ils sont rejetés ; faux positif, ils repassent par la triangulation. Retourne les
+	// signalements rejetés ou remis en file. Rejouer la même décision est sans effet de bord.
+	ReviewCluster(ctx context.Context, id string, status entity.ClusterReviewStatus, reviewerID, note string) ([]string, error)
+
+	ListConflicts(ctx context.Context, status string) ([]entity.Conflict, error)
+	GetConflict(ctx context.Context, id string) (*entity.Conflict, error)
+	// AssignConflict attribue le conflit à un utilisateur existant
+	AssignConflict(ctx context.Context, id, assigneeID string) error
+	// ResolveConflict clôt le conflit, rejette les signalements erronés désignés par le modérateur
+	// et remet les autres en file de triangulation. Retourne les signalements rejetés puis rejoués.
+	ResolveConflict(ctx context.Context, id, resolverID, note string, rejectIDs []string) (rejected, queued []string, err error)
+}
+
+type reviewService struct {
+	clusterRepo   repository.SuspiciousClusterRepository
+	conflictRepo  repository.ConflictRepository
+	userRepo      repository.UserRepository
+	reportRepo    repository.ReportRepository
+	outboxRepo    repository.OutboxRepository
+	reportService ReportService
+}
+
+func NewReviewService(clusterRepo repository.SuspiciousClusterRepository, conflictRepo repository.ConflictRepository, userRepo repository.UserRepository, reportRepo repository.ReportRepository, outboxRepo repository.OutboxRepository, reportService ReportService) ReviewService {
+	return &reviewService{
+		clusterRepo:   clusterRepo,
+		conflictRepo:  conflictRepo,
+		userRepo:      userRepo,
+		reportRepo:    reportRepo,
+		outboxRepo:    outboxRepo,
+		reportService: reportService,
+	}
+}
+
+func (s *reviewService) ListClusters(ctx context.Context, status string) ([]entity.SuspiciousCluster, error) {
+	return s.clusterRepo.GetAll(ctx, status)
+}
+
+func (s *reviewService) ReviewCluster(ctx context.Context, id string, status entity.ClusterReviewStatus, reviewerID, note string) ([]string, error) {
+	cluster, err := s.clusterRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if cluster == nil {
+		return nil, ErrClusterNotFound
+	}
+	err = s.clusterRepo.UpdateStatus(ctx, id, status, reviewerID, note)
+	if err == sql.ErrNoRows {
+		return nil, ErrClusterNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	ids := append([]string{cluster.ReportID}, cluster.RelatedReportIDs...)
+	reports, err := s.loadReports(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+	switch status {
+	case entity.ClusterConfirmed:
+		return s.reject(ctx, reports, reviewerID)
+	case entity.ClusterDismissed:
+		return s.retriangulate(ctx, reports)
+	}
+	return nil, nil
+}
+
+func (s *reviewService) ListConflicts(ctx context.Context, status string) ([]entity.Conflict, error) {
+	return s.conflictRepo.GetAll(ctx, status)
+}
+
+func (s *reviewService) GetConflict(ctx context.Context, id string) (*entity.Conflict, error) {
+	return s.conflictRepo.GetByID(ctx, id)
+}
+
+func (s *reviewService) AssignConflict(ctx context.Context, id, assigneeID string) error {
+	// Colonne UUID avec clé étrangère : une valeur invalide ne doit pas atteindre la base
+	if _, err := uuid.Parse(assigneeID); err != nil {
+		return ErrInvalidAssignee
+	}
+	assignee, err := s.userRepo.GetByID(ctx, assigneeID)
+	if err != nil {
+		return err
+	}
+	if assignee == nil {
+		return ErrAssigneeNotFound
+	}
+	err = s.conflictRepo.Assign(ctx, id, assigneeID)
+	if err == sql.ErrNoRows {
+		return ErrConflictNotFound
+	}
+	return err
+}
+
+func (s *reviewService) ResolveConflict(ctx context.Context, id, resolverID, note string, rejectIDs []string) ([]string, []string, error) {
+	conflict, err := s.conflictRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if conflict == nil || conflict.Status != entity.ConflictOpen {
+		return nil, nil, ErrConflictNotFound
+	}
+	toReject := make(map[string]bool, len(rejectIDs))
+	for _, reportID := range rejectIDs {
+		if !slices.Contains(conflict.ReportIDs, reportID) {
+			return nil, nil, fmt.Errorf("%w: %s", ErrInvalidResolution, reportID)
+		}
+		toReject[reportID] = true
+	}
+
+	err = s.conflictRepo.Resolve(ctx, id, resolverID, note)
+	if err == sql.ErrNoRows {
+		return nil, nil, ErrConflictNotFound
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Les signalements retenus par le conflit sont rejoués : ceux qui ne sont plus contredits
+	// suivent le calcul normal, un désaccord persistant ouvre un nouveau conflit
+	reports, err := s.loadReports(ctx, conflict.ReportIDs)
+	if err != nil {
+		return nil, nil, err
+	}
+	var wrong, remaining []*entity.Report
+	for _, r := range reports {
+		if toReject[r.ID] {
+			wrong = append(wrong, r)
+		} else {
+			remaining = append(remaining, r)
+		}
+	}
+	rejected, err := s.reject(ctx, wrong, resolverID)
+	if err != nil {
+		return rejected, nil, err
+	}
+	queued, err := s.retriangulate(ctx, remaining)
+	return rejected, queued, err
+}
+
+// loadReports charge les signalements désignés, sans doublon (supprimés : ignorés)
+func (s *reviewService) loadReports(ctx context.Context, ids []string) ([]*entity.Report, error) {
+	seen := make(map[string]bool, len(ids))
+	var reports []*entity.Report
+	for _, id := range ids {
+		if seen[id] {
+			continue
+		}
+		seen[id] = true
+		report, err := s.reportRepo.GetByID(ctx, id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load report %s: %w", id, err)
+		}
+		if report != nil {
+			reports = append(reports, report)
+		}
+	}
+	return reports, nil
+}
+
+// reject rejette les signalements d'une collusion confirmée (ReportStatusChanged publié)
+func (s *reviewService) reject(ctx context.Context, reports []*entity.Report, reviewerID string) ([]string, error) {
+	var rejected []string
+	for _, r := range reports {
+		if r.Status == entity.StatusRejected {
+			continue
+		}
+		if err := s.reportService.UpdateReportStatus(ctx, r.ID, entity.StatusRejected, reviewerID); err != nil {
+			return rejected, fmt.Errorf("failed to reject report %s: %w", r.ID, err)
+		}
+		rejected = append(rejected, r.ID)
+	}
+	return rejected, nil
+}
+
+// retriangulate remet en file de triangulation les signalements encore en attente
+func (s *reviewService) retriangulate(ctx context.Context, reports []*entity.Report) ([]string, error) {
+	var ids []string
+	var messages []*entity.OutboxMessage
+	for _, r := range reports {
+		if r.Status != entity.StatusPending {
+			continue
+		}
+		msg, err := retriangulationMessage(r)
+		if err != nil {
+			return nil, err
+		}
+		ids = append(ids, r.ID)
+		messages = append(messages, msg)
+	}
+	if len(messages) == 0 {
+		return nil, nil
+	}
+	withRequestContext(ctx, messages)
+	if err := s.outboxRepo.Enqueue(ctx, messages...); err != nil {
+		return nil, fmt.Errorf("failed to enqueue triangulation: %w", err)
+	}
+	return ids, nil
+}
@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/event"
+	"github.com/openvote/backend/internal/domain/repository"
+	"github.com/openvote/backend/internal/platform/queue"
+	"github.com/openvote/backend/internal/repository/memory"
+)
+
+func TestReviewClusterAppliesDecisionToReports(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	sybilReports := []entity.Report{
+		{ID: "r1", ObserverID: "u1", AuthorRole: entity.RoleCitizen, AuthorTokenHash: "batch", IncidentType: "A", Severity: 5, CreatedAt: now},
+		{ID: "r2", ObserverID: "u2", AuthorRole: entity.RoleCitizen, AuthorTokenHash: "batch", IncidentType: "A", Severity: 5, CreatedAt: now},
+		{ID: "r3", ObserverID: "u3", AuthorRole: entity.RoleCitizen, AuthorTokenHash: "batch", IncidentType: "A", Severity: 5, CreatedAt: now},
+		{ID: "r4", ObserverID: "u4", AuthorRole: entity.RoleCitizen, AuthorTokenHash: "batch", IncidentType: "A", Severity: 5, CreatedAt: now},
+		{ID: "target", ObserverID: "u5", AuthorRole: entity.RoleCitizen, AuthorTokenHash: "batch", IncidentType: "A", Severity: 5, CreatedAt: now},
+	}
+
+	// flagged rejoue la triangulation : le cluster suspect est enregistré pour revue
+	type fixture struct {
+		review        ReviewService
+		store         *memory.Store
+		reports       repository.ReportRepository
+		triangulation TriangulationService
+		clusterID     string
+	}
+	flagged := func(t *testing.T) fixture {
+		t.Helper()
+		store, reports := newReportStore(t, sybilReports)
+		clusters := memory.NewSuspiciousClusterRepository(store)
+		triangulation := NewTriangulationService(reports, clusters, memory.NewConflictRepository(store), NewEventPublisher(memory.NewOutboxRepository(store)))
+		if err := triangulation.CalculateTrustScore(ctx, "target"); err != nil {
+			t.Fatalf("triangulation: %v", err)
+		}
+		open, _ := clusters.GetAll(ctx, string(entity.ClusterOpen))
+		if len(open) != 1 {
+			t.Fatalf("expected 1 open cluster, got %d", len(open))
+		}
+		// L'événement de triangulation suspecte est consommé : seuls les effets de la revue restent
+		if events := outboxEvents(t, store); len(events) != 1 || events[0].Type != event.TypeReportTriangulated {
+			t.Fatalf("expected the suspicious triangulation event, got %+v", events)
+		}
+		reportService := NewReportService(reports, nil, nil)
+		review := NewReviewService(clusters, memory.NewConflictRepository(store), memory.NewUserRepository(store), reports, memory.NewOutboxRepository(store), reportService)
+		return fixture{review, store, reports, triangulation, open[0].ID}
+	}
+
+	t.Run("confirmed collusion rejects every report of the cluster", func(t *testing.T) {
+		f := flagged(t)
+		review, clusterID := f.review, f.clusterID
+
+		rejected, err := review.ReviewCluster(ctx, clusterID, entity.ClusterConfirmed, "mod-1", "même lot de jetons")
+		if err != nil {
+			t.Fatalf("review: %v", err)
+		}
+		if len(rejected) != len(sybilReports) || !slices.Contains(rejected, "target") {
+			t.Errorf("rejected = %v", rejected)
+		}
+		events := outboxEvents(t, f.store)
+		for _, e := range events {
+			var changed event.ReportStatusChanged
+			if err := e.Decode(&changed); err != nil || changed.NewStatus != string(entity.StatusRejected) || changed.ChangedBy != "mod-1" {
+				t.Errorf("unexpected event %+v (%v)", e, err)
+			}
+		}
+		if len(events) != len(rejected) {
+			t.Errorf("%d status events for %d rejected reports", len(events), len(rejected))
+		}
+
+		// Décision rejouée : rien de plus à rejeter
+		again, err := review.ReviewCluster(ctx, clusterID, entity.ClusterConfirmed, "mod-1", "")
+		if err != nil || len(again) != 0 {
+			t.Errorf("second review = %v, %v", again, err)
+		}
+	})
+
+	t.Run("dismissed cluster sends pending reports back to triangulation", func(t *testing.T) {
+		f := flagged(t)
+		review, store, clusterID := f.review, f.store, f.clusterID
+
+		queued, err := review.ReviewCluster(ctx, clusterID, entity.ClusterDismissed, "mod-1", "bureau de vote très fréquenté")
+		if err != nil {
+			t.Fatalf("review: %v", err)
+		}
+		if len(queued) != len(sybilReports) {
+			t.Errorf("queued = %v", queued)
+		}
+		messages, err := memory.NewOutboxRepository(store).Claim(ctx, 10, time.Minute)
+		if err != nil || len(messages) != len(sybilReports) {
+			t.Fatalf("outbox = %d messages, %v", len(messages), err)
+		}
+		for _, msg := range messages {
+			var env event.Envelope
+			if err := json.Unmarshal(msg.Payload, &env); err != nil || env.Type != event.TypeReportCreated {
+				t.Errorf("unexpected payload %s (%v)", msg.Payload, err)
+			}
+			if msg.Queue != queue.ReportQueue(5) {
+				t.Errorf("queue = %s, want the urgent triangulation queue", msg.Queue)
+			}
+		}
+
+		// Rejouée, la triangulation ne signale plus le cluster écarté
+		if err := f.triangulation.CalculateTrustScore(ctx, "target"); err != nil {
+			t.Fatalf("triangulation: %v", err)
+		}
+		if status := reportStatus(t, f.reports, "target"); status != entity.StatusVerified {
+			t.Errorf("status after dismissal = %s, want verified", status)
+		}
+	})
+
+	t.Run("unknown cluster", func(t *testing.T) {
+		if _, err := flagged(t).review.ReviewCluster(ctx, "missing", entity.ClusterDismissed, "mod-1", ""); !errors.Is(err, ErrClusterNotFound) {
+			t.Errorf("err = %v, want ErrClusterNotFound", err)
+		}
+	})
+}
+
+func TestResolveConflictRequeuesHeldReports(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	reports := newReportFixture(t, []entity.Report{
+		{ID: "stuff", AuthorRole: entity.RoleObserver, IncidentType: "STUFF", Severity: 3, CreatedAt: now},
+		{ID: "viole", AuthorRole: entity.RoleObserver, IncidentType: "VIOLE", Severity: 3, CreatedAt: now},
+	})
+	store := memory.NewStore()
+	conflicts := memory.NewConflictRepository(store)
+	users := memory.NewUserRepository(store)
+	moderator := &entity.User{ID: "6f1c8a52-6a8e-4c43-a3a4-3a4f3f0c9d11", Username: "moderateur", Role: entity.RoleRegionAdmin}
+	if err := users.Create(ctx, moderator); err != nil {
+		t.Fatal(err)
+	}
+	conflict := &entity.Conflict{IncidentTypes: []string{"STUFF", "VIOLE"}, ReportIDs: []string{"stuff", "viole"}, Status: entity.ConflictOpen}
+	if err := conflicts.Create(ctx, conflict); err != nil {
+		t.Fatal(err)
+	}
+	outbox := memory.NewOutboxRepository(store)
+	review := NewReviewService(memory.NewSuspiciousClusterRepository(store), conflicts, users, reports, outbox, NewReportService(reports, nil, nil))
+
+	for assignee, want := range map[string]error{"moderateur": ErrInvalidAssignee, "1b4e28ba-2fa1-11d2-883f-0016d3cca427": ErrAssigneeNotFound} {
+		if err := review.AssignConflict(ctx, conflict.ID, assignee); !errors.Is(err, want) {
+			t.Errorf("assign %s: err = %v, want %v", assignee, err, want)
+		}
+	}
+	if err := review.AssignConflict(ctx, conflict.ID, moderator.ID); err != nil {
+		t.Fatalf("assign: %v", err)
+	}
+
+	if _, _, err := review.ResolveConflict(ctx, conflict.ID, moderator.ID, "x", []string{"other"}); !errors.Is(err, ErrInvalidResolution) {
+		t.Errorf("foreign report accepted: %v", err)
+	}
+	rejected, queued, err := review.ResolveConflict(ctx, conflict.ID, moderator.ID, "pas de violence constatée", []string{"viole"})
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if !slices.Equal(rejected, []string{"viole"}) || !slices.Equal(queued, []string{"stuff"}) {
+		t.Errorf("rejected = %v, queued = %v", rejected, queued)
+	}
+	if status := reportStatus(t, reports, "viole"); status != entity.StatusRejected {
+		t.Errorf("viole status = %s", status)
+	}
+	messages, _ := outbox.Claim(ctx, 10, time.Minute)
+	if len(messages) != 1 || messages[0].Queue != queue.ReportQueue(3) {
+		t.Errorf("expected the held report back in the triangulation queue, got %+v", messages)
+	}
+
+	// Rejouée, la triangulation vérifie le signalement qui n'est plus contredit
+	triangulation := NewTriangulationService(reports, memory.NewSuspiciousClusterRepository(store), conflicts, NewEventPublisher(outbox))
+	if err := triangulation.CalculateTrustScore(ctx, "stuff"); err != nil {
+		t.Fatalf("triangulation: %v", err)
+	}
+	if status := reportStatus(t, reports, "stuff"); status != entity.StatusVerified {
+		t.Errorf("stuff status = %s, want verified", status)
+	}
+
+	if _, _, err := review.ResolveConflict(ctx, conflict.ID, moderator.ID, "encore", nil); !errors.Is(err, ErrConflictNotFound) {
+		t.Errorf("second resolution: %v", err)
+	}
+}
+
+func TestResolveConflictWithoutRejectionDoesNotReopen(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now()
+	store, reports := newReportStore(t, []entity.Report{
+		{ID: "stuff", AuthorRole: entity.RoleObserver, IncidentType: "STUFF", Severity: 3, CreatedAt: now},
+		{ID: "viole", AuthorRole: entity.RoleObserver, IncidentType: "VIOLE", Severity: 3, CreatedAt: now},
+	})
+	conflicts := memory.NewConflictRepository(store)
+	users := memory.NewUserRepository(store)
+	review := NewReviewService(memory.NewSuspiciousClusterRepository(store), conflicts, users, reports, memory.NewOutboxRepository(store), NewReportService(reports, nil, nil))
+	triangulation := NewTriangulationService(reports, memory.NewSuspiciousClusterRepository(store), conflicts, NewEventPublisher(memory.NewOutboxRepository(store)))
+
+	if err := triangulation.CalculateTrustScore(ctx, "stuff"); err != nil {
+		t.Fatalf("triangulation: %v", err)
+	}
+	open, _ := conflicts.GetAll(ctx, string(entity.ConflictOpen))
+	if len(open) != 1 {
+		t.Fatalf("open conflicts = %+v, want 1", open)
+	}
+
+	// Les deux signalements sont jugés fondés : aucun rejet, tous repartent en triangulation
+	rejected, queued, err := review.ResolveConflict(ctx, open[0].ID, "", "les deux incidents ont eu lieu", []string{})
+	if err != nil {
+		t.Fatalf("resolve: %v", err)
+	}
+	if len(rejected) != 0 || len(queued) != 2 {
+		t.Errorf("rejected = %v, queued = %v", rejected, queued)
+	}
+	for _, id := range queued {
+		if err := triangulation.CalculateTrustScore(ctx, id); err != nil {
+			t.Fatalf("triangulation %s: %v", id, err)
+		}
+	}
+	if open, _ := conflicts.GetAll(ctx, string(entity.ConflictOpen)); len(open) != 0 {
+		t.Errorf("arbitrated conflict reopened: %+v", open)
+	}
+	for _, id := range queued {
+		if status := reportStatus(t, reports, id); status != entity.StatusPending {
+			t.Errorf("%s status = %s, want pending", id, status)
+		}
+	}
+
+	// Un nouveau type dans la zone n'a pas été arbitré : un nouveau conflit s'ouvre
+	author := &entity.User{ID: "obs-intim", Username: "obs-intim", Role: entity.RoleObserver}
+	if err := users.Create(ctx, author); err != nil {
+		t.Fatal(err)
+	}
+	intim := &entity.Report{ID: "intim", ObserverID: author.ID, IncidentType: "INTIM", GPSLocation: "POINT(2.35 48.85)", H3Index: "h3_index",
+		Status: entity.StatusPending, Severity: 3, CreatedAt: now}
+	if err := reports.Create(ctx, intim); err != nil {
+		t.Fatal(err)
+	}
+	if err := triangulation.CalculateTrustScore(ctx, "intim"); err != nil {
+		t.Fatalf("triangulation: %v", err)
+	}
+	if open, _ := conflicts.GetAll(ctx, string(entity.ConflictOpen)); len(open) != 1 || !slices.Contains(open[0].IncidentTypes, "INTIM") {
+		t.Errorf("open conflicts after a new incident type = %+v", open)
+	}
+}
@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"fmt"
+	"sync"
 	"time"
 
 	"github.com/openvote/backend/internal/platform/storage"
@@ -11,35 +12,99 @@ import (
 type StorageService interface {
 	GenerateUploadURL(ctx context.Context, fileName string) (string, error)
 	Initialize(ctx context.Context) error
+	// Prepare crée au besoin le bucket de cfg sans l'utiliser (vérification avant enregistrement)
+	Prepare(ctx context.Context, cfg StorageConfig) error
+	// Reconfigure change de bucket (créé au besoin) et de durée de validité des URL
+	Reconfigure(ctx context.Context, cfg StorageConfig) error
+	// Ping vérifie que le stockage répond et que le bucket courant existe (sonde /readyz)
+	Ping(ctx context.Context) error
 }
 
 type storageService struct {
-	storage    storage.Storage
-	bucketName string
+	storage storage.Storage
+
+	mu           sync.RWMutex
+	bucketName   string
+	uploadExpiry time.Duration
 }
 
 func NewStorageService(s storage.Storage, bucketName string) StorageService {
 	return &storageService{
-		storage:    s,
-		bucketName: bucketName,
+		storage:      s,
+		bucketName:   bucketName,
+		uploadExpiry: 15 * time.Minute,
 	}
 }
 
+func (s *storageService) settings() (string, time.Duration) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.bucketName, s.uploadExpiry
+}
+
 func (s *storageService) Initialize(ctx context.Context) error {
-	exists, err := s.storage.BucketExists(ctx, s.bucketName)
+	bucketName, _ := s.settings()
+	return s.ensureBucket(ctx, bucketName)
+}
+
+func (s *storageService) ensureBucket(ctx context.Context, bucketName string) error {
+	exists, err := s.storage.BucketExists(ctx, bucketName)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return s.storage.MakeBucket(ctx, bucketName)
+	}
+	return nil
+}
+
+func (s *storageService) Prepare(ctx context.Context, cfg StorageConfig) error {
+	current, _ := s.settings()
+	if cfg.BucketName == current {
+		return nil
+	}
+	if s.storage == nil {
+		return fmt.Errorf("storage not configured")
+	}
+	if err := s.ensureBucket(ctx, cfg.BucketName); err != nil {
+		return fmt.Errorf("failed to prepare bucket %s: %w", cfg.BucketName, err)
+	}
+	return nil
+}
+
+func (s *storageService) Reconfigure(ctx context.Context, cfg StorageConfig) error {
+	current, _ := s.settings()
+	// Le nouveau bucket doit exister avant que des URL ne pointent dessus
+	if cfg.BucketName != current && s.storage != nil {
+		if err := s.ensureBucket(ctx, cfg.BucketName); err != nil {
+			return fmt.Errorf("failed to prepare bucket %s: %w", cfg.BucketName, err)
+		}
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.bucketName = cfg.BucketName
+	s.uploadExpiry = cfg.UploadExpiry()
+	return nil
+}
+
+func (s *storageService) Ping(ctx context.Context) error {
+	if s.storage == nil {
+		return fmt.Errorf("storage not configured")
+	}
+	bucketName, _ := s.settings()
+	exists, err := s.storage.BucketExists(ctx, bucketName)
 	if err != nil {
 		return err
 	}
 	if !exists {
-		return s.storage.MakeBucket(ctx, s.bucketName)
+		return fmt.Errorf("bucket %s not found", bucketName)
 	}
 	return nil
 }
 
 func (s *storageService) GenerateUploadURL(ctx context.Context, fileName string) (string, error) {
-	// URL valable 15 minutes
-	expiry := 15 * time.Minute
-	url, err := s.storage.GetPresignedUploadURL(ctx, s.bucketName, fileName, expiry)
+	bucketName, expiry := s.settings()
+	url, err := s.storage.GetPresignedUploadURL(ctx, bucketName, fileName, expiry)
 	if err != nil {
 		return "", fmt.Errorf("failed to generate upload URL: %w", err)
 	}
@@ -0,0 +1,176 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sync"
+
+	"github.com/openvote/backend/internal/domain/event"
+)
+
+const (
+	// streamHistorySize : événements conservés pour la reprise après reconnexion (Last-Event-ID)
+	streamHistorySize = 1000
+	// streamBufferSize : retard toléré d'un client avant déconnexion ; il reprend alors
+	// depuis l'historique grâce à Last-Event-ID
+	streamBufferSize = 64
+)
+
+// StreamEventTypes liste les événements diffusés au tableau de bord en temps réel
+var StreamEventTypes = []event.Type{
+	event.TypeReportCreated,
+	event.TypeReportStatusChanged,
+	event.TypeReportTriangulated,
+	event.TypeEvidenceUploaded,
+	event.TypeElectionStatusChanged,
+	event.TypeAlertTriggered,
+	event.TypeAlertStatusChanged,
+}
+
+// streamGlobalTypes sont nationaux par nature (scrutins, configuration) : visibles de tous
+var streamGlobalTypes = []event.Type{
+	event.TypeElectionStatusChanged,
+	event.TypeConfigChanged,
+}
+
+// StreamScope restreint les événements reçus par un client : tout le territoire,
+// ou une seule région. Un événement sans région n'est diffusé hors du périmètre
+// national que s'il est global par nature.
+type StreamScope struct {
+	All      bool
+	RegionID string
+}
+
+func (s StreamScope) allows(env event.Envelope) bool {
+	if s.All || slices.Contains(streamGlobalTypes, env.Type) {
+		return true
+	}
+	var attrs webhookAttributes
+	if err := json.Unmarshal(env.Data, &attrs); err != nil {
+		return false
+	}
+	return attrs.RegionID != "" && attrs.RegionID == s.RegionID
+}
+
+// StreamSubscription est l'abonnement d'un client connecté
+type StreamSubscription struct {
+	// Backlog : événements manqués depuis Last-Event-ID, à envoyer avant Events
+	Backlog []event.Envelope
+	// Resumed est faux si Last-Event-ID n'est plus dans l'historique : le client doit recharger
+	Resumed bool
+	// Events est fermé à la déconnexion forcée (client trop lent, arrêt du serveur)
+	Events <-chan event.Envelope
+
+	events chan event.Envelope
+	scope  StreamScope
+}
+
+// StreamService diffuse les événements du bus aux clients SSE/WebSocket de l'instance
+type StreamService interface {
+	// HandleEvent est l'abonné du registre d'événements
+	HandleEvent(ctx context.Context, env event.Envelope) error
+	// Subscribe enregistre un client ; lastEventID vide pour une première connexion
+	Subscribe(scope StreamScope, lastEventID string) *StreamSubscription
+	Unsubscribe(sub *StreamSubscription)
+	// Close déconnecte tous les clients (arrêt du serveur)
+	Close()
+}
+
+type streamService struct {
+	mu          sync.Mutex
+	history     []event.Envelope
+	subscribers map[*StreamSubscription]struct{}
+	closed      bool
+}
+
+func NewStreamService() StreamService {
+	return &streamService{subscribers: make(map[*StreamSubscription]struct{})}
+}
+
+func (s *streamService) HandleEvent(ctx context.Context, env event.Envelope) error {
+	if !slices.Contains(StreamEventTypes, env.Type) || !alertRoutedTo(env, AlertChannelDashboard) {
+		return nil
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	// Événement relivré par le bus : déjà diffusé
+	for i := len(s.history) - 1; i >= 0; i-- {
+		if s.history[i].ID == env.ID {
+			return nil
+		}
+	}
+	s.history = append(s.history, env)
+	if len(s.history) > streamHistorySize {
+		s.history = slices.Delete(s.history, 0, len(s.history)-streamHistorySize)
+	}
+
+	for sub := range s.subscribers {
+		if !sub.scope.allows(env) {
+			continue
+		}
+		select {
+		case sub.events <- env:
+		default:
+			// Client trop lent : déconnecté plutôt que de bloquer la diffusion
+			s.drop(sub)
+		}
+	}
+	return nil
+}
+
+func (s *streamService) Subscribe(scope StreamScope, lastEventID string) *StreamSubscription {
+	events := make(chan event.Envelope, streamBufferSize)
+	sub := &StreamSubscription{Events: events, events: events, scope: scope, Resumed: true}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.closed {
+		close(events)
+		return sub
+	}
+	// Historique et enregistrement sous le même verrou : aucun événement perdu entre les deux
+	if lastEventID != "" {
+		sub.Resumed = false
+		for i := len(s.history) - 1; i >= 0; i-- {
+			if s.history[i].ID == lastEventID {
+				sub.Resumed = true
+				for _, env := range s.history[i+1:] {
+					if scope.allows(env) {
+						sub.Backlog = append(sub.Backlog, env)
+					}
+				}
+				break
+			}
+		}
+	}
+	s.subscribers[sub] = struct{}{}
+	return sub
+}
+
+func (s *streamService) Unsubscribe(sub *StreamSubscription) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.drop(sub)
+}
+
+func (s *streamService) Close() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.closed = true
+	for sub := range s.subscribers {
+		s.drop(sub)
+	}
+}
+
+// drop retire un abonné et ferme son canal ; appelé verrou tenu
+func (s *streamService) drop(sub *StreamSubscription) {
+	if _, ok := s.subscribers[sub]; !ok {
+		return
+	}
+	delete(s.subscribers, sub)
+	close(sub.events)
+}
@@ -0,0 +1,82 @@
+package service
+
+import (
+	"context"
+	"testing"
+
+	"github.com/openvote/backend/internal/domain/event"
+)
+
+func TestStreamService_FiltersByScope(t *testing.T) {
+	s := NewStreamService()
+	ctx := context.Background()
+
+	national := s.Subscribe(StreamScope{All: true}, "")
+	north := s.Subscribe(StreamScope{RegionID: "north"}, "")
+
+	s.HandleEvent(ctx, mustEnvelope(t, event.ReportCreated{ReportID: "r1", RegionID: "north"}))
+	s.HandleEvent(ctx, mustEnvelope(t, event.ReportCreated{ReportID: "r2", RegionID: "south"}))
+	s.HandleEvent(ctx, mustEnvelope(t, event.ElectionStatusChanged{ElectionID: "e1", NewStatus: "active"}))
+	// Observateur sans région : seul le périmètre national le reçoit
+	s.HandleEvent(ctx, mustEnvelope(t, event.ReportCreated{ReportID: "r3"}))
+	// Hors du flux temps réel
+	s.HandleEvent(ctx, mustEnvelope(t, event.UserRoleChanged{UserID: "u1", RegionID: "north"}))
+
+	if got := len(national.Events); got != 4 {
+		t.Errorf("Expected 4 events for national scope, got %d", got)
+	}
+	if got := len(north.Events); got != 2 {
+		t.Errorf("Expected report r1 and election for north scope, got %d", got)
+	}
+	if env := <-north.Events; env.Type != event.TypeReportCreated {
+		t.Errorf("Unexpected first event %s", env.Type)
+	}
+}
+
+func TestStreamService_ResumesFromLastEventID(t *testing.T) {
+	s := NewStreamService()
+	ctx := context.Background()
+
+	first := mustEnvelope(t, event.ReportCreated{ReportID: "r1", RegionID: "north"})
+	second := mustEnvelope(t, event.ReportCreated{ReportID: "r2", RegionID: "south"})
+	third := mustEnvelope(t, event.ReportStatusChanged{ReportID: "r1", NewStatus: "verified", RegionID: "north"})
+	for _, env := range []event.Envelope{first, second, third, first} {
+		s.HandleEvent(ctx, env)
+	}
+
+	sub := s.Subscribe(StreamScope{RegionID: "north"}, first.ID)
+	if !sub.Resumed || len(sub.Backlog) != 1 || sub.Backlog[0].ID != third.ID {
+		t.Errorf("Expected backlog [%s], got resumed=%v %v", third.ID, sub.Resumed, sub.Backlog)
+	}
+
+	lost := s.Subscribe(StreamScope{All: true}, "unknown-id")
+	if lost.Resumed || len(lost.Backlog) != 0 {
+		t.Errorf("Expected reset for unknown Last-Event-ID, got resumed=%v backlog=%d", lost.Resumed, len(lost.Backlog))
+	}
+}
+
+func TestStreamService_DropsSlowSubscriber(t *testing.T) {
+	s := NewStreamService()
+	ctx := context.Background()
+
+	slow := s.Subscribe(StreamScope{All: true}, "")
+	for i := 0; i <= streamBufferSize; i++ {
+		s.HandleEvent(ctx, mustEnvelope(t, event.ReportCreated{ReportID: "r"}))
+	}
+
+	received := 0
+	for range slow.Events {
+		received++
+	}
+	if received != streamBufferSize {
+		t.Errorf("Expected %d buffered events before disconnect, got %d", streamBufferSize, received)
+	}
+
+	// Arrêt du serveur : tous les clients sont déconnectés
+	active := s.Subscribe(StreamScope{All: true}, "")
+	s.Close()
+	if _, ok := <-active.Events; ok {
+		t.Error("Expected channel closed on shutdown")
+	}
+	s.Unsubscribe(active)
+}
@@ -0,0 +1,187 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/openvote/backend/internal/domain/entity"
+)
+
+// Signaux de collusion entre deux signalements citoyens
+const (
+	SignalSharedActivationToken = "shared_activation_token"
+	SignalSharedDevice          = "shared_device"
+	SignalSharedSourceIP        = "shared_source_ip"
+	SignalNearDuplicateText     = "near_duplicate_text"
+	SignalBurstSubmission       = "burst_submission"
+)
+
+// Poids de chaque signal. Une IP partagée seule ne suffit pas (NAT opérateur mobile),
+// pas plus qu'une simple rafale (plusieurs témoins réagissent au même incident).
+var sybilSignalWeights = map[string]float64{
+	SignalSharedActivationToken: 1.0,
+	SignalSharedDevice:          1.0,
+	SignalSharedSourceIP:        0.6,
+	SignalNearDuplicateText:     0.5,
+	SignalBurstSubmission:       0.3,
+}
+
+const (
+	// sybilPairThreshold est le score à partir duquel une paire de signalements est jugée collusive
+	sybilPairThreshold = 0.8
+	// burstWindowSeconds : deux soumissions à moins de N secondes d'intervalle
+	burstWindowSeconds = 10
+	// nearDuplicateSimilarity : similarité de Jaccard minimale entre deux descriptions
+	nearDuplicateSimilarity = 0.85
+	// minComparableWords : en dessous, une description est trop courte pour être comparée
+	minComparableWords = 4
+)
+
+// SybilAssessment est le résultat de l'analyse d'un groupe de signalements voisins
+type SybilAssessment struct {
+	Suspicious bool
+	Score      float64  // Score de la paire la plus suspecte
+	Signals    []string // Signaux observés dans les paires suspectes
+	ReportIDs  []string // Signalements impliqués dans au moins une paire suspecte
+}
+
+// SybilDetector identifie les clusters de signalements citoyens potentiellement coordonnés
+type SybilDetector interface {
+	Assess(reports []entity.Report) SybilAssessment
+}
+
+type sybilDetector struct{}
+
+func NewSybilDetector() SybilDetector {
+	return &sybilDetector{}
+}
+
+// isCitizenRole indique si le rôle relève de la pondération citoyenne (les observateurs sont accrédités)
+func isCitizenRole(role entity.UserRole) bool {
+	return role == entity.RoleCitizen || role == entity.RoleVerifiedCitizen
+}
+
+func (d *sybilDetector) Assess(reports []entity.Report) SybilAssessment {
+	var candidates []entity.Report
+	for _, r := range reports {
+		if r.Status == entity.StatusRejected || !isCitizenRole(r.AuthorRole) {
+			continue
+		}
+		candidates = append(candidates, r)
+	}
+
+	result := SybilAssessment{}
+	involved := make(map[string]bool)
+	signals := make(map[string]bool)
+
+	for i := 0; i < len(candidates); i++ {
+		for j := i + 1; j < len(candidates); j++ {
+			a, b := candidates[i], candidates[j]
+			// Un même compte ne se corrobore pas lui-même : hors périmètre du détecteur
+			if a.ObserverID != "" && a.ObserverID == b.ObserverID {
+				continue
+			}
+
+			pairSignals := pairSybilSignals(a, b)
+			score := 0.0
+			for _, s := range pairSignals {
+				score += sybilSignalWeights[s]
+			}
+			if score < sybilPairThreshold {
+				continue
+			}
+
+			result.Suspicious = true
+			result.Score = math.Max(result.Score, score)
+			involved[a.ID] = true
+			involved[b.ID] = true
+			for _, s := range pairSignals {
+				signals[s] = true
+			}
+		}
+	}
+
+	for id := range involved {
+		result.ReportIDs = append(result.ReportIDs, id)
+	}
+	for s := range signals {
+		result.Signals = append(result.Signals, s)
+	}
+	sort.Strings(result.ReportIDs)
+	sort.Strings(result.Signals)
+	return result
+}
+
+// pairSybilSignals liste les signaux partagés par deux signalements
+func pairSybilSignals(a, b entity.Report) []string {
+	var signals []string
+	if a.AuthorTokenHash != "" && a.AuthorTokenHash == b.AuthorTokenHash {
+		signals = append(signals, SignalSharedActivationToken)
+	}
+	if a.DeviceFingerprint != "" && a.DeviceFingerprint == b.DeviceFingerprint {
+		signals = append(signals, SignalSharedDevice)
+	}
+	if a.SourceFingerprint != "" && a.SourceFingerprint == b.SourceFingerprint {
+		signals = append(signals, SignalSharedSourceIP)
+	}
+	if textSimilarity(a.Description, b.Description) >= nearDuplicateSimilarity {
+		signals = append(signals, SignalNearDuplicateText)
+	}
+	if math.Abs(a.CreatedAt.Sub(b.CreatedAt).Seconds()) <= burstWindowSeconds {
+		signals = append(signals, SignalBurstSubmission)
+	}
+	return signals
+}
+
+// textSimilarity calcule la similarité de Jaccard entre les ensembles de mots de deux textes
+func textSimilarity(a, b string) float64 {
+	wa, wb := wordSet(a), wordSet(b)
+	if len(wa) < minComparableWords || len(wb) < minComparableWords {
+		return 0
+	}
+	inter := 0
+	for w := range wa {
+		if wb[w] {
+			inter++
+		}
+	}
+	union := len(wa) + len(wb) - inter
+	return float64(inter) / float64(union)
+}
+
+func wordSet(text string) map[string]bool {
+	words := strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	set := make(map[string]bool, len(words))
+	for _, w := range words {
+		set[w] = true
+	}
+	return set
+}
+
+// fingerprintKey est la clé HMAC des empreintes, fixée au démarrage par SetFingerprintKey
+var fingerprintKey []byte
+
+// SetFingerprintKey fixe la clé des empreintes (config.Auth.FingerprintKey()). Une nouvelle
+// clé rend les nouvelles empreintes incomparables aux anciennes.
+func SetFingerprintKey(key string) {
+	fingerprintKey = []byte(key)
+}
+
+// fingerprint dérive une empreinte HMAC non réversible d'un identifiant sensible
+// (IP, identifiant d'appareil, token d'activation) pour permettre les comparaisons
+// sans jamais persister la valeur en clair.
+func fingerprint(value string) string {
+	if value == "" {
+		return ""
+	}
+	mac := hmac.New(sha256.New, fingerprintKey)
+	mac.Write([]byte(value))
+	return hex.EncodeToString(mac.Sum(nil))[:32]
+}
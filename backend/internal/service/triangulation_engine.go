@@ -0,0 +1,193 @@
+package service
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+)
+
+// RoleWeights définit le poids de confiance de chaque rôle d'auteur
+type RoleWeights struct {
+	Observer        float64 `json:"observer"`
+	VerifiedCitizen float64 `json:"verified_citizen"`
+	Citizen         float64 `json:"citizen"`
+	Other           float64 `json:"other"`
+}
+
+// TriangulationConfig regroupe les paramètres ajustables de la triangulation.
+// Les clés JSON reprennent celles exposées par GET /admin/config.
+type TriangulationConfig struct {
+	Name              string      `json:"name,omitempty"`
+	Threshold         float64     `json:"threshold"`
+	RadiusMeters      float64     `json:"radius_meters"`
+	TimeWindowMinutes int         `json:"time_window_minutes"`
+	Weights           RoleWeights `json:"weights"`
+}
+
+// DefaultTriangulationConfig retourne la configuration de production historique
+func DefaultTriangulationConfig() TriangulationConfig {
+	return TriangulationConfig{
+		Name:              "default",
+		Threshold:         1.0,
+		RadiusMeters:      500,
+		TimeWindowMinutes: 30,
+		Weights: RoleWeights{
+			Observer:        1.0,
+			VerifiedCitizen: 0.35,
+			Citizen:         0.2,
+			Other:           0.1,
+		},
+	}
+}
+
+// Window retourne la demi-fenêtre temporelle de recherche des voisins
+func (c TriangulationConfig) Window() time.Duration {
+	return time.Duration(c.TimeWindowMinutes) * time.Minute
+}
+
+// Weight retourne le poids de confiance associé au rôle de l'auteur
+func (c TriangulationConfig) Weight(role entity.UserRole) float64 {
+	switch role {
+	case entity.RoleObserver:
+		return c.Weights.Observer
+	case entity.RoleVerifiedCitizen:
+		return c.Weights.VerifiedCitizen
+	case entity.RoleCitizen:
+		return c.Weights.Citizen
+	default:
+		return c.Weights.Other // Hors rôle spécifié
+	}
+}
+
+// TriangulationOutcome est la décision prise pour un signalement
+type TriangulationOutcome string
+
+const (
+	OutcomeVerified     TriangulationOutcome = "verified"     // Auto-vérifié
+	OutcomeInsufficient TriangulationOutcome = "insufficient" // Score sous le seuil, reste en attente
+	OutcomeConflict     TriangulationOutcome = "conflict"     // Types d'incidents contradictoires
+	OutcomeSuspicious   TriangulationOutcome = "suspicious"   // Cluster Sybil, revue humaine
+)
+
+// TriangulationDecision détaille l'évaluation d'un signalement par rapport à ses voisins
+type TriangulationDecision struct {
+	Outcome          TriangulationOutcome
+	Score            float64
+	IndependentScore float64 // Score après réduction des clusters suspects à une seule source
+	NeighborCount    int
+	IncidentTypes    map[string]int
+	Sybil            SybilAssessment
+}
+
+// EvaluateTriangulation applique la logique de score sur des données en mémoire,
+// sans accès au repository : utilisée par le worker comme par l'outil de rejeu.
+// Les voisins incluent le signalement cible lui-même.
+func EvaluateTriangulation(neighbors []entity.Report, cfg TriangulationConfig, detector SybilDetector) TriangulationDecision {
+	decision := TriangulationDecision{
+		NeighborCount: len(neighbors),
+		IncidentTypes: make(map[string]int),
+	}
+
+	for _, r := range neighbors {
+		// On ne compte que les signalements qui ne sont PAS rejetés
+		if r.Status == entity.StatusRejected {
+			continue
+		}
+		decision.Score += cfg.Weight(r.AuthorRole)
+		decision.IncidentTypes[r.IncidentType]++
+	}
+	decision.IndependentScore = decision.Score
+
+	// Plusieurs types d'incidents dans la même zone/temps → arbitrage humain
+	if len(decision.IncidentTypes) > 1 {
+		decision.Outcome = OutcomeConflict
+		return decision
+	}
+
+	if decision.Score < cfg.Threshold {
+		decision.Outcome = OutcomeInsufficient
+		return decision
+	}
+
+	// Un cluster collusif ne compte que pour une seule source
+	decision.Sybil = detector.Assess(neighbors)
+	if decision.Sybil.Suspicious {
+		decision.IndependentScore = independentTrustScore(neighbors, decision.Sybil.ReportIDs, cfg)
+		if decision.IndependentScore < cfg.Threshold {
+			decision.Outcome = OutcomeSuspicious
+			return decision
+		}
+	}
+
+	decision.Outcome = OutcomeVerified
+	return decision
+}
+
+// independentTrustScore recalcule le score en réduisant les signalements suspects
+// à une seule source (le poids le plus élevé du cluster)
+func independentTrustScore(reports []entity.Report, suspiciousIDs []string, cfg TriangulationConfig) float64 {
+	suspicious := make(map[string]bool, len(suspiciousIDs))
+	for _, id := range suspiciousIDs {
+		suspicious[id] = true
+	}
+
+	score, clusterWeight := 0.0, 0.0
+	for _, r := range reports {
+		if r.Status == entity.StatusRejected {
+			continue
+		}
+		w := cfg.Weight(r.AuthorRole)
+		if suspicious[r.ID] {
+			clusterWeight = math.Max(clusterWeight, w)
+			continue
+		}
+		score += w
+	}
+	return score + clusterWeight
+}
+
+// FindNeighbors reproduit en mémoire la requête spatiale et temporelle du repository :
+// même tuile H3 ou distance inférieure au rayon, dans la fenêtre temporelle.
+func FindNeighbors(target entity.Report, candidates []entity.Report, cfg TriangulationConfig) []entity.Report {
+	lat, lon, posErr := ParseWKTPoint(target.GPSLocation)
+	start := target.CreatedAt.Add(-cfg.Window())
+	end := target.CreatedAt.Add(cfg.Window())
+
+	var neighbors []entity.Report
+	for _, r := range candidates {
+		if r.CreatedAt.Before(start) || r.CreatedAt.After(end) {
+			continue
+		}
+		sameCell := target.H3Index != "" && r.H3Index == target.H3Index
+		within := false
+		if posErr == nil {
+			if rLat, rLon, err := ParseWKTPoint(r.GPSLocation); err == nil {
+				within = haversineMeters(lat, lon, rLat, rLon) <= cfg.RadiusMeters
+			}
+		}
+		if sameCell || within {
+			neighbors = append(neighbors, r)
+		}
+	}
+	return neighbors
+}
+
+// ParseWKTPoint extrait latitude et longitude d'un point WKT "POINT(lon lat)"
+func ParseWKTPoint(wkt string) (lat, lon float64, err error) {
+	if _, err = fmt.Sscanf(wkt, "POINT(%f %f)", &lon, &lat); err != nil {
+		return 0, 0, fmt.Errorf("invalid WKT point %q: %w", wkt, err)
+	}
+	return lat, lon, nil
+}
+
+// haversineMeters calcule la distance orthodromique entre deux coordonnées WGS84
+func haversineMeters(lat1, lon1, lat2, lon2 float64) float64 {
+	const earthRadius = 6371000.0
+	toRad := func(d float64) float64 { return d * math.Pi / 180 }
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	a := math.Sin(dLat/2)*math.Sin(dLat/2) + math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+	return 2 * earthRadius * math.Atan2(math.Sqrt(a), math.Sqrt(1-a))
+}
@@ -3,28 +3,71 @@ package service
 import (
 	"context"
 	"fmt"
-	"log"
-	"time"
+	"slices"
+	"sort"
+	"sync"
 
 	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/event"
 	"github.com/openvote/backend/internal/domain/repository"
+	"github.com/openvote/backend/internal/platform/logging"
+	"github.com/openvote/backend/internal/platform/telemetry"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+// outcomeHeld : vérification suspendue par un conflit ouvert (métrique des décisions)
+const outcomeHeld = "held"
+
+var triangulationLogger = logging.For("triangulation")
+
 type TriangulationService interface {
 	CalculateTrustScore(ctx context.Context, reportID string) error
+	// Reconfigure remplace les paramètres à chaud (configuration système)
+	Reconfigure(cfg TriangulationConfig)
 }
 
 type triangulationService struct {
-	reportRepo repository.ReportRepository
+	reportRepo   repository.ReportRepository
+	clusterRepo  repository.SuspiciousClusterRepository
+	conflictRepo repository.ConflictRepository
+	events       event.Publisher
+	detector     SybilDetector
+
+	mu     sync.RWMutex
+	config TriangulationConfig
 }
 
-func NewTriangulationService(reportRepo repository.ReportRepository) TriangulationService {
+func NewTriangulationService(reportRepo repository.ReportRepository, clusterRepo repository.SuspiciousClusterRepository, conflictRepo repository.ConflictRepository, events event.Publisher) TriangulationService {
 	return &triangulationService{
-		reportRepo: reportRepo,
+		reportRepo:   reportRepo,
+		clusterRepo:  clusterRepo,
+		conflictRepo: conflictRepo,
+		events:       events,
+		detector:     NewSybilDetector(),
+		config:       DefaultTriangulationConfig(),
 	}
 }
 
+func (s *triangulationService) Reconfigure(cfg TriangulationConfig) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.config = cfg
+}
+
 func (s *triangulationService) CalculateTrustScore(ctx context.Context, reportID string) error {
+	ctx, span := telemetry.StartSpan(ctx, "TriangulationService.CalculateTrustScore", trace.WithAttributes(attribute.String("report.id", reportID)))
+	err := s.calculateTrustScore(ctx, reportID)
+	telemetry.EndSpan(span, err)
+	return err
+}
+
+func (s *triangulationService) calculateTrustScore(ctx context.Context, reportID string) error {
+	// Paramètres figés pour toute l'évaluation, même si un administrateur les modifie entre-temps
+	s.mu.RLock()
+	cfg := s.config
+	s.mu.RUnlock()
+
 	// 1. Récupère le signalement cible
 	target, err := s.reportRepo.GetByID(ctx, reportID)
 	if err != nil {
@@ -40,64 +83,205 @@ func (s *triangulationService) CalculateTrustScore(ctx context.Context, reportID
 	}
 
 	// Parsing de la position GPS (Format WKT: POINT(lon lat))
-	var lat, lon float64
-	_, err = fmt.Sscanf(target.GPSLocation, "POINT(%f %f)", &lon, &lat)
+	lat, lon, err := ParseWKTPoint(target.GPSLocation)
 	if err != nil {
-		log.Printf("[TRIANGULATION] Warning: Could not parse GPS location for report %s: %v", reportID, err)
+		// L'erreur cite la position : elle n'est pas journalisée
+		triangulationLogger.WarnContext(ctx, "Position GPS illisible", "report_id", reportID)
 	}
 
-	// Fenêtre temporelle +/- 30 minutes
-	start := target.CreatedAt.Add(-30 * time.Minute)
-	end := target.CreatedAt.Add(30 * time.Minute)
+	// Fenêtre temporelle +/- N minutes
+	start := target.CreatedAt.Add(-cfg.Window())
+	end := target.CreatedAt.Add(cfg.Window())
 
 	// 2. Requête Spatiale & Temporelle
-	// Rayon de 500m
-	nearbyReports, err := s.reportRepo.FindNearbyWithRole(ctx, target.H3Index, lat, lon, 500.0, start, end)
+	nearbyReports, err := s.reportRepo.FindNearbyWithRole(ctx, target.H3Index, lat, lon, cfg.RadiusMeters, start, end)
 	if err != nil {
 		return fmt.Errorf("failed to fetch nearby reports: %w", err)
 	}
 
-	// 3. Calcul du Score & Détection de Conflits
-	totalScore := 0.0
-	incidentTypes := make(map[string]int)
+	// 3. Calcul du Score & Détection de Conflits (logique pure, partagée avec l'outil de rejeu)
+	decision := EvaluateTriangulation(nearbyReports, cfg, s.detector)
+	triangulationLogger.InfoContext(ctx, "Signalement évalué", "report_id", reportID, "neighbors", decision.NeighborCount, "score", decision.Score)
+	trace.SpanFromContext(ctx).SetAttributes(
+		attribute.String("triangulation.outcome", string(decision.Outcome)),
+		attribute.Float64("triangulation.score", decision.Score),
+		attribute.Int("triangulation.neighbors", decision.NeighborCount),
+	)
 
-	for _, r := range nearbyReports {
-		// On ne compte que les signalements qui ne sont PAS rejetés
-		if r.Status == entity.StatusRejected {
-			continue
+	switch decision.Outcome {
+	case OutcomeConflict:
+		telemetry.CountTriangulation(string(decision.Outcome))
+		triangulationLogger.InfoContext(ctx, "Conflit détecté", "report_id", reportID, "incident_types", decision.IncidentTypes)
+		if err := s.recordConflict(ctx, target, nearbyReports, decision.IncidentTypes); err != nil {
+			return err
+		}
+		publishEvents(ctx, s.events, triangulatedEvent(target, decision))
+		return nil
+	case OutcomeInsufficient:
+		telemetry.CountTriangulation(string(decision.Outcome))
+		return nil
+	}
+
+	// 4. Un signalement impliqué dans un conflit ouvert attend la résolution humaine
+	if s.conflictRepo != nil {
+		inConflict, err := s.conflictRepo.HasOpenConflict(ctx, reportID)
+		if err != nil {
+			return fmt.Errorf("failed to check open conflicts: %w", err)
+		}
+		if inConflict {
+			telemetry.CountTriangulation(outcomeHeld)
+			triangulationLogger.InfoContext(ctx, "Conflit ouvert, pas d'auto-vérification", "report_id", reportID, "score", decision.Score)
+			return nil
 		}
+	}
+
+	// 5. Détection Sybil : un cluster collusif ne compte que pour une seule source
+	if decision.Outcome == OutcomeSuspicious {
+		// Cluster déjà écarté par un modérateur (faux positif) : la collusion n'est plus retenue,
+		// tant qu'aucun nouveau membre ne s'y est joint
+		dismissed, err := s.sybilDismissed(ctx, reportID, decision.Sybil.ReportIDs)
+		if err != nil {
+			return err
+		}
+		if dismissed {
+			triangulationLogger.InfoContext(ctx, "Cluster suspect écarté en revue, score brut retenu", "report_id", reportID, "score", decision.Score)
+			decision.Outcome = OutcomeVerified
+		}
+	}
+	telemetry.CountTriangulation(string(decision.Outcome))
+	if decision.Outcome == OutcomeSuspicious {
+		triangulationLogger.InfoContext(ctx, "Signalement suspect, revue humaine", "report_id", reportID,
+			"score", decision.Score, "independent_score", decision.IndependentScore, "signals", decision.Sybil.Signals)
+		if err := s.flagSuspiciousCluster(ctx, reportID, decision.Sybil); err != nil {
+			return err
+		}
+		publishEvents(ctx, s.events, triangulatedEvent(target, decision))
+		return nil
+	}
+	if decision.Sybil.Suspicious {
+		triangulationLogger.InfoContext(ctx, "Cluster suspect ignoré, sources indépendantes suffisantes", "report_id", reportID, "independent_score", decision.IndependentScore)
+	}
+
+	triangulationLogger.InfoContext(ctx, "Signalement vérifié", "report_id", reportID, "score", decision.Score)
+	// Vérification et événements dans la même transaction : un échec est rejoué par la file
+	messages, err := eventOutboxMessages(ctx, triangulatedEvent(target, decision), event.ReportStatusChanged{
+		ReportID:     reportID,
+		OldStatus:    string(target.Status),
+		NewStatus:    string(entity.StatusVerified),
+		IncidentType: target.IncidentType,
+		Severity:     target.Severity,
+		RegionID:     target.RegionID,
+		Source:       event.SourceTriangulation,
+	})
+	if err != nil {
+		return err
+	}
+	return s.reportRepo.UpdateStatusWithOutbox(ctx, reportID, entity.StatusVerified, messages...)
+}
+
+// triangulatedEvent décrit la décision de triangulation pour les abonnés
+func triangulatedEvent(target *entity.Report, decision TriangulationDecision) event.ReportTriangulated {
+	return event.ReportTriangulated{
+		ReportID:         target.ID,
+		Outcome:          string(decision.Outcome),
+		Score:            decision.Score,
+		IndependentScore: decision.IndependentScore,
+		NeighborCount:    decision.NeighborCount,
+		IncidentType:     target.IncidentType,
+		Severity:         target.Severity,
+		RegionID:         target.RegionID,
+	}
+}
 
-		// Ajout du poids selon le rôle
-		score := 0.0
-		switch r.AuthorRole {
-		case entity.RoleObserver:
-			score = 1.0
-		case entity.RoleVerifiedCitizen:
-			score = 0.35
-		case entity.RoleCitizen:
-			score = 0.2
-		default:
-			score = 0.1 // Hors rôle spécifié
+// sybilDismissed indique si un modérateur a écarté un cluster suspect contenant le signalement
+// et tous les membres du cluster détecté ; un membre jamais revu impose une nouvelle revue
+func (s *triangulationService) sybilDismissed(ctx context.Context, reportID string, members []string) (bool, error) {
+	if s.clusterRepo == nil {
+		return false, nil
+	}
+	clusters, err := s.clusterRepo.FindByReport(ctx, reportID)
+	if err != nil {
+		return false, fmt.Errorf("failed to look up reviewed clusters: %w", err)
+	}
+	for _, c := range clusters {
+		if c.Status != entity.ClusterDismissed {
+			continue
 		}
-		totalScore += score
+		reviewed := append([]string{c.ReportID}, c.RelatedReportIDs...)
+		if !slices.ContainsFunc(members, func(id string) bool { return !slices.Contains(reviewed, id) }) {
+			return true, nil
+		}
+	}
+	return false, nil
+}
 
-		// Détection de conflit (simple: compte les types d'incidents)
-		incidentTypes[r.IncidentType]++
+// flagSuspiciousCluster enregistre le cluster pour revue au lieu de vérifier le signalement
+func (s *triangulationService) flagSuspiciousCluster(ctx context.Context, reportID string, assessment SybilAssessment) error {
+	if s.clusterRepo == nil {
+		return nil
+	}
+	cluster := &entity.SuspiciousCluster{
+		ReportID:         reportID,
+		RelatedReportIDs: assessment.ReportIDs,
+		Signals:          assessment.Signals,
+		Score:            assessment.Score,
+		Status:           entity.ClusterOpen,
+	}
+	if err := s.clusterRepo.Upsert(ctx, cluster); err != nil {
+		return fmt.Errorf("failed to persist suspicious cluster: %w", err)
 	}
+	return nil
+}
 
-	log.Printf("[TRIANGULATION] Report %s: Neighbors: %d, Total Score: %.2f", reportID, len(nearbyReports), totalScore)
+// recordConflict crée un conflit pour les signalements voisins, ou enrichit le conflit ouvert
+// qui en implique déjà une partie
+func (s *triangulationService) recordConflict(ctx context.Context, target *entity.Report, reports []entity.Report, incidentTypes map[string]int) error {
+	if s.conflictRepo == nil {
+		return nil
+	}
 
-	// Gestion des conflits
-	if len(incidentTypes) > 1 {
-		// Logique simpliste: si plus d'un type d'incident est reporté dans la même zone/temps
-		log.Printf("[TRIANGULATION] CONFLIT détecté pour le signalement %s (Types variés: %v)", reportID, incidentTypes)
+	var reportIDs []string
+	for _, r := range reports {
+		if r.Status != entity.StatusRejected {
+			reportIDs = append(reportIDs, r.ID)
+		}
+	}
+	types := make([]string, 0, len(incidentTypes))
+	for t := range incidentTypes {
+		types = append(types, t)
+	}
+	sort.Strings(types)
+
+	// Désaccord déjà arbitré sur ces signalements et ces types : on ne rouvre pas le conflit,
+	// le signalement reste en attente d'une décision humaine
+	resolved, err := s.conflictRepo.FindResolvedCovering(ctx, reportIDs, types)
+	if err != nil {
+		return fmt.Errorf("failed to look up resolved conflicts: %w", err)
+	}
+	if resolved != nil {
+		triangulationLogger.InfoContext(ctx, "Conflit déjà arbitré, pas de nouveau conflit", "report_id", target.ID, "conflict_id", resolved.ID)
+		return nil
 	}
 
-	// 4. Prise de Décision
-	if totalScore >= 1.0 {
-		log.Printf("[TRIANGULATION] Report %s VERIFIED (Score: %.2f)", reportID, totalScore)
-		return s.reportRepo.UpdateStatus(ctx, reportID, entity.StatusVerified)
+	existing, err := s.conflictRepo.FindOpenByReports(ctx, reportIDs)
+	if err != nil {
+		return fmt.Errorf("failed to look up open conflicts: %w", err)
+	}
+	if existing != nil {
+		if err := s.conflictRepo.AddReports(ctx, existing.ID, reportIDs, types); err != nil {
+			return fmt.Errorf("failed to extend conflict %s: %w", existing.ID, err)
+		}
+		return nil
 	}
 
+	conflict := &entity.Conflict{
+		H3Index:       target.H3Index,
+		IncidentTypes: types,
+		ReportIDs:     reportIDs,
+		Status:        entity.ConflictOpen,
+	}
+	if err := s.conflictRepo.Create(ctx, conflict); err != nil {
+		return fmt.Errorf("failed to persist conflict: %w", err)
+	}
 	return nil
 }
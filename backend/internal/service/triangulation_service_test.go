@@ -2,34 +2,116 @@ package service
 
 import (
 	"context"
+	"encoding/json"
+	"slices"
 	"testing"
 	"time"
 
 	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/event"
+	"github.com/openvote/backend/internal/domain/repository"
+	"github.com/openvote/backend/internal/repository/memory"
 )
 
-// Mock de ReportRepository pour les tests
-type mockReportRepo struct {
-	reports       map[string]*entity.Report
-	nearbyResult  []entity.Report
-	updatedID     string
-	updatedStatus entity.ReportStatus
+// newReportFixture charge les signalements dans un store en mémoire. L'auteur de chaque signalement
+// (ObserverID, "obs-<id>" par défaut) est créé avec le rôle, la région et le jeton d'activation
+// portés par le signalement, que FindNearbyWithRole et GetByID relisent par jointure.
+func newReportFixture(t *testing.T, reports []entity.Report) repository.ReportRepository {
+	t.Helper()
+	_, repo := newReportStore(t, reports)
+	return repo
 }
 
-func (m *mockReportRepo) Create(ctx context.Context, report *entity.Report) error { return nil }
-func (m *mockReportRepo) GetAll(ctx context.Context, status string) ([]entity.Report, error) {
-	return nil, nil
+// newReportStore est newReportFixture avec le store, pour relire l'outbox écrite avec les statuts
+func newReportStore(t *testing.T, reports []entity.Report) (*memory.Store, repository.ReportRepository) {
+	t.Helper()
+	ctx := context.Background()
+	store := memory.NewStore()
+	users := memory.NewUserRepository(store)
+	repo := memory.NewReportRepository(store)
+	for _, r := range reports {
+		if r.ObserverID == "" {
+			r.ObserverID = "obs-" + r.ID
+		}
+		if r.AuthorRole == "" {
+			r.AuthorRole = entity.RoleCitizen
+		}
+		if r.GPSLocation == "" {
+			r.GPSLocation = "POINT(2.35 48.85)"
+		}
+		if r.H3Index == "" {
+			r.H3Index = "h3_index"
+		}
+		if existing, _ := users.GetByID(ctx, r.ObserverID); existing == nil {
+			author := &entity.User{ID: r.ObserverID, Username: r.ObserverID, Role: r.AuthorRole, RegionID: r.RegionID, ActivationTokenHash: r.AuthorTokenHash}
+			if err := users.Create(ctx, author); err != nil {
+				t.Fatalf("seed user %s: %v", r.ObserverID, err)
+			}
+		}
+		if err := repo.Create(ctx, &r); err != nil {
+			t.Fatalf("seed report %s: %v", r.ID, err)
+		}
+	}
+	return store, repo
+}
+
+// outboxEvents relit les événements écrits dans l'outbox du store
+func outboxEvents(t *testing.T, store *memory.Store) []event.Envelope {
+	t.Helper()
+	messages, err := memory.NewOutboxRepository(store).Claim(context.Background(), 100, time.Minute)
+	if err != nil {
+		t.Fatalf("claim outbox: %v", err)
+	}
+	envelopes := make([]event.Envelope, 0, len(messages))
+	for _, msg := range messages {
+		var env event.Envelope
+		if err := json.Unmarshal(msg.Payload, &env); err != nil {
+			t.Fatalf("decode outbox message %s: %v", msg.Payload, err)
+		}
+		envelopes = append(envelopes, env)
+	}
+	return envelopes
+}
+
+func reportStatus(t *testing.T, repo repository.ReportRepository, id string) entity.ReportStatus {
+	t.Helper()
+	report, err := repo.GetByID(context.Background(), id)
+	if err != nil || report == nil {
+		t.Fatalf("report %s: %v", id, err)
+	}
+	return report.Status
 }
-func (m *mockReportRepo) GetByID(ctx context.Context, id string) (*entity.Report, error) {
-	return m.reports[id], nil
+
+// triangulationFixture : service de triangulation branché sur les repositories mémoire d'un
+// même store ; les événements publiés sont relus dans l'outbox (outboxEvents)
+type triangulationFixture struct {
+	store     *memory.Store
+	reports   repository.ReportRepository
+	clusters  repository.SuspiciousClusterRepository
+	conflicts repository.ConflictRepository
+	service   TriangulationService
 }
-func (m *mockReportRepo) FindNearbyWithRole(ctx context.Context, h3Index string, lat, lon, radius float64, start, end time.Time) ([]entity.Report, error) {
-	return m.nearbyResult, nil
+
+func newTriangulationFixture(t *testing.T, reports []entity.Report) triangulationFixture {
+	t.Helper()
+	store, repo := newReportStore(t, reports)
+	f := triangulationFixture{
+		store:     store,
+		reports:   repo,
+		clusters:  memory.NewSuspiciousClusterRepository(store),
+		conflicts: memory.NewConflictRepository(store),
+	}
+	f.service = NewTriangulationService(repo, f.clusters, f.conflicts, NewEventPublisher(memory.NewOutboxRepository(store)))
+	return f
 }
-func (m *mockReportRepo) UpdateStatus(ctx context.Context, id string, status entity.ReportStatus) error {
-	m.updatedID = id
-	m.updatedStatus = status
-	return nil
+
+func (f triangulationFixture) openClusters(t *testing.T) []entity.SuspiciousCluster {
+	t.Helper()
+	open, err := f.clusters.GetAll(context.Background(), string(entity.ClusterOpen))
+	if err != nil {
+		t.Fatalf("list clusters: %v", err)
+	}
+	return open
 }
 
 func TestTriangulationScenarios(t *testing.T) {
@@ -37,71 +119,237 @@ func TestTriangulationScenarios(t *testing.T) {
 	now := time.Now()
 
 	t.Run("Validation Citoyenne: 5 reports (0.2 each) at same location", func(t *testing.T) {
-		repo := &mockReportRepo{
-			reports: map[string]*entity.Report{
-				"target": {ID: "target", Status: entity.StatusPending, CreatedAt: now, GPSLocation: "POINT(2.35 48.85)", H3Index: "h3_index"},
-			},
-			nearbyResult: []entity.Report{
-				{ID: "r1", AuthorRole: entity.RoleCitizen, IncidentType: "A", CreatedAt: now},
-				{ID: "r2", AuthorRole: entity.RoleCitizen, IncidentType: "A", CreatedAt: now},
-				{ID: "r3", AuthorRole: entity.RoleCitizen, IncidentType: "A", CreatedAt: now},
-				{ID: "r4", AuthorRole: entity.RoleCitizen, IncidentType: "A", CreatedAt: now},
-				{ID: "target", AuthorRole: entity.RoleCitizen, IncidentType: "A", CreatedAt: now}, // 5ème rapport (le cible lui-même)
-			},
-		}
-		s := NewTriangulationService(repo)
-
-		err := s.CalculateTrustScore(ctx, "target")
+		f := newTriangulationFixture(t, []entity.Report{
+			{ID: "r1", AuthorRole: entity.RoleCitizen, IncidentType: "A", CreatedAt: now},
+			{ID: "r2", AuthorRole: entity.RoleCitizen, IncidentType: "A", CreatedAt: now},
+			{ID: "r3", AuthorRole: entity.RoleCitizen, IncidentType: "A", CreatedAt: now},
+			{ID: "r4", AuthorRole: entity.RoleCitizen, IncidentType: "A", CreatedAt: now},
+			{ID: "target", AuthorRole: entity.RoleCitizen, IncidentType: "A", CreatedAt: now}, // 5ème rapport (le cible lui-même)
+		})
+
+		err := f.service.CalculateTrustScore(ctx, "target")
 		if err != nil {
 			t.Fatalf("Calculation failed: %v", err)
 		}
 
-		if repo.updatedStatus != entity.StatusVerified {
-			t.Errorf("Expected status VERIFIED, got %s", repo.updatedStatus)
+		if status := reportStatus(t, f.reports, "target"); status != entity.StatusVerified {
+			t.Errorf("Expected status VERIFIED, got %s", status)
 		}
 	})
 
 	t.Run("Observateur: 1 report (1.0) passes immediately", func(t *testing.T) {
-		repo := &mockReportRepo{
-			reports: map[string]*entity.Report{
-				"obs": {ID: "obs", Status: entity.StatusPending, CreatedAt: now, GPSLocation: "POINT(2.35 48.85)", H3Index: "h3_index"},
-			},
-			nearbyResult: []entity.Report{
-				{ID: "obs", AuthorRole: entity.RoleObserver, IncidentType: "B", CreatedAt: now},
-			},
-		}
-		s := NewTriangulationService(repo)
+		f := newTriangulationFixture(t, []entity.Report{
+			{ID: "obs", AuthorRole: entity.RoleObserver, IncidentType: "B", Severity: 4, RegionID: "reg-1", CreatedAt: now},
+		})
 
-		err := s.CalculateTrustScore(ctx, "obs")
+		err := f.service.CalculateTrustScore(ctx, "obs")
 		if err != nil {
 			t.Fatalf("Calculation failed: %v", err)
 		}
 
-		if repo.updatedStatus != entity.StatusVerified {
-			t.Errorf("Expected status VERIFIED for observer, got %s", repo.updatedStatus)
+		if status := reportStatus(t, f.reports, "obs"); status != entity.StatusVerified {
+			t.Errorf("Expected status VERIFIED for observer, got %s", status)
+		}
+
+		// L'auto-vérification est écrite dans l'outbox avec le statut, pour les abonnés (webhooks, flux temps réel)
+		// Écrits dans la même transaction, les deux messages n'ont pas d'ordre garanti
+		events := make(map[event.Type]event.Envelope)
+		for _, e := range outboxEvents(t, f.store) {
+			events[e.Type] = e
+		}
+		if len(events) != 2 {
+			t.Fatalf("Expected 2 events, got %v", events)
+		}
+		var outcome event.ReportTriangulated
+		if err := events[event.TypeReportTriangulated].Decode(&outcome); err != nil || outcome.Outcome != "verified" || outcome.Score != 1.0 {
+			t.Errorf("Unexpected triangulation event: %+v (%v)", events[event.TypeReportTriangulated], err)
+		}
+		var changed event.ReportStatusChanged
+		if err := events[event.TypeReportStatusChanged].Decode(&changed); err != nil || changed.NewStatus != "verified" || changed.OldStatus != "pending" || changed.Source != event.SourceTriangulation || changed.RegionID != "reg-1" || changed.Severity != 4 {
+			t.Errorf("Unexpected status event: %+v (%v)", events[event.TypeReportStatusChanged], err)
 		}
 	})
 
 	t.Run("Insufficient: 3 regular citizens (3 * 0.2 = 0.6) stays pending", func(t *testing.T) {
-		repo := &mockReportRepo{
-			reports: map[string]*entity.Report{
-				"target": {ID: "target", Status: entity.StatusPending, CreatedAt: now, GPSLocation: "POINT(2.35 48.85)", H3Index: "h3_index"},
-			},
-			nearbyResult: []entity.Report{
-				{ID: "r1", AuthorRole: entity.RoleCitizen, IncidentType: "A", CreatedAt: now},
-				{ID: "r2", AuthorRole: entity.RoleCitizen, IncidentType: "A", CreatedAt: now},
-				{ID: "target", AuthorRole: entity.RoleCitizen, IncidentType: "A", CreatedAt: now},
-			},
-		}
-		s := NewTriangulationService(repo)
-
-		err := s.CalculateTrustScore(ctx, "target")
+		f := newTriangulationFixture(t, []entity.Report{
+			{ID: "r1", AuthorRole: entity.RoleCitizen, IncidentType: "A", CreatedAt: now},
+			{ID: "r2", AuthorRole: entity.RoleCitizen, IncidentType: "A", CreatedAt: now},
+			{ID: "target", AuthorRole: entity.RoleCitizen, IncidentType: "A", CreatedAt: now},
+		})
+
+		err := f.service.CalculateTrustScore(ctx, "target")
 		if err != nil {
 			t.Fatalf("Calculation failed: %v", err)
 		}
 
-		if repo.updatedStatus == entity.StatusVerified {
+		if reportStatus(t, f.reports, "target") == entity.StatusVerified {
 			t.Errorf("Expected status to remain PENDING, but was updated to VERIFIED")
 		}
+		if events := outboxEvents(t, f.store); len(events) != 0 {
+			t.Errorf("Expected no event for an insufficient score, got %+v", events)
+		}
+	})
+	t.Run("Sybil: 5 citizens enrolled from the same token are flagged, not verified", func(t *testing.T) {
+		f := newTriangulationFixture(t, []entity.Report{
+			{ID: "r1", ObserverID: "u1", AuthorRole: entity.RoleCitizen, AuthorTokenHash: "batch", IncidentType: "A", CreatedAt: now},
+			{ID: "r2", ObserverID: "u2", AuthorRole: entity.RoleCitizen, AuthorTokenHash: "batch", IncidentType: "A", CreatedAt: now.Add(-5 * time.Minute)},
+			{ID: "r3", ObserverID: "u3", AuthorRole: entity.RoleCitizen, AuthorTokenHash: "batch", IncidentType: "A", CreatedAt: now.Add(-10 * time.Minute)},
+			{ID: "r4", ObserverID: "u4", AuthorRole: entity.RoleCitizen, AuthorTokenHash: "batch", IncidentType: "A", CreatedAt: now.Add(-15 * time.Minute)},
+			{ID: "target", ObserverID: "u5", AuthorRole: entity.RoleCitizen, AuthorTokenHash: "batch", IncidentType: "A", CreatedAt: now},
+		})
+
+		if err := f.service.CalculateTrustScore(ctx, "target"); err != nil {
+			t.Fatalf("Calculation failed: %v", err)
+		}
+
+		if reportStatus(t, f.reports, "target") == entity.StatusVerified {
+			t.Errorf("Expected suspicious cluster to remain PENDING, but was VERIFIED")
+		}
+		open := f.openClusters(t)
+		if len(open) != 1 {
+			t.Fatalf("Expected 1 suspicious cluster, got %d", len(open))
+		}
+		if got := open[0].Signals; !slices.Contains(got, SignalSharedActivationToken) {
+			t.Errorf("Expected shared activation token signal, got %v", got)
+		}
+		events := outboxEvents(t, f.store)
+		var outcome event.ReportTriangulated
+		if len(events) != 1 || events[0].Decode(&outcome) != nil || outcome.Outcome != string(OutcomeSuspicious) {
+			t.Errorf("Expected a suspicious triangulation event, got %+v", events)
+		}
+	})
+
+	t.Run("Sybil: a cluster dismissed in review no longer blocks verification", func(t *testing.T) {
+		f := newTriangulationFixture(t, []entity.Report{
+			{ID: "r1", ObserverID: "u1", AuthorRole: entity.RoleCitizen, AuthorTokenHash: "batch", IncidentType: "A", CreatedAt: now},
+			{ID: "r2", ObserverID: "u2", AuthorRole: entity.RoleCitizen, AuthorTokenHash: "batch", IncidentType: "A", CreatedAt: now},
+			{ID: "r3", ObserverID: "u3", AuthorRole: entity.RoleCitizen, AuthorTokenHash: "batch", IncidentType: "A", CreatedAt: now},
+			{ID: "r4", ObserverID: "u4", AuthorRole: entity.RoleCitizen, AuthorTokenHash: "batch", IncidentType: "A", CreatedAt: now},
+			{ID: "target", ObserverID: "u5", AuthorRole: entity.RoleCitizen, AuthorTokenHash: "batch", IncidentType: "A", CreatedAt: now},
+		})
+		dismissed := &entity.SuspiciousCluster{ReportID: "target", RelatedReportIDs: []string{"r1", "r2", "r3", "r4", "target"}, Signals: []string{SignalSharedActivationToken}}
+		if err := f.clusters.Upsert(ctx, dismissed); err != nil {
+			t.Fatal(err)
+		}
+		if err := f.clusters.UpdateStatus(ctx, dismissed.ID, entity.ClusterDismissed, "mod-1", "faux positif"); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := f.service.CalculateTrustScore(ctx, "target"); err != nil {
+			t.Fatalf("Calculation failed: %v", err)
+		}
+		if status := reportStatus(t, f.reports, "target"); status != entity.StatusVerified {
+			t.Errorf("Expected dismissed cluster member to be VERIFIED, got %s", status)
+		}
+		if open := f.openClusters(t); len(open) != 0 {
+			t.Errorf("Expected no new flag after dismissal, got %+v", open)
+		}
+
+		// Un nouveau signalement du même lot n'a jamais été revu : la collusion est signalée à nouveau
+		users := memory.NewUserRepository(f.store)
+		if err := users.Create(ctx, &entity.User{ID: "u6", Username: "u6", Role: entity.RoleCitizen, ActivationTokenHash: "batch"}); err != nil {
+			t.Fatal(err)
+		}
+		late := &entity.Report{ID: "late", ObserverID: "u6", IncidentType: "A", GPSLocation: "POINT(2.35 48.85)", H3Index: "h3_index", Status: entity.StatusPending, CreatedAt: now}
+		if err := f.reports.Create(ctx, late); err != nil {
+			t.Fatal(err)
+		}
+		if err := f.service.CalculateTrustScore(ctx, "late"); err != nil {
+			t.Fatalf("Calculation failed: %v", err)
+		}
+		if status := reportStatus(t, f.reports, "late"); status != entity.StatusPending {
+			t.Errorf("Expected the new colluding report to stay PENDING, got %s", status)
+		}
+		open := f.openClusters(t)
+		if len(open) != 1 || open[0].ReportID != "late" || !slices.Contains(open[0].RelatedReportIDs, "late") {
+			t.Errorf("Expected a new open cluster for the late report, got %+v", open)
+		}
+	})
+
+	t.Run("Sybil: near-identical texts submitted seconds apart are flagged", func(t *testing.T) {
+		text := "Des hommes armés bloquent l'entrée du bureau de vote de l'école publique"
+		f := newTriangulationFixture(t, []entity.Report{
+			{ID: "r1", ObserverID: "u1", AuthorRole: entity.RoleVerifiedCitizen, Description: text, IncidentType: "A", CreatedAt: now.Add(-2 * time.Second)},
+			{ID: "r2", ObserverID: "u2", AuthorRole: entity.RoleVerifiedCitizen, Description: text + " !", IncidentType: "A", CreatedAt: now.Add(-4 * time.Second)},
+			{ID: "target", ObserverID: "u3", AuthorRole: entity.RoleVerifiedCitizen, Description: text, IncidentType: "A", CreatedAt: now},
+		})
+
+		if err := f.service.CalculateTrustScore(ctx, "target"); err != nil {
+			t.Fatalf("Calculation failed: %v", err)
+		}
+
+		if reportStatus(t, f.reports, "target") == entity.StatusVerified {
+			t.Errorf("Expected copy-pasted burst to remain PENDING, but was VERIFIED")
+		}
+		if open := f.openClusters(t); len(open) != 1 {
+			t.Errorf("Expected 1 suspicious cluster, got %d", len(open))
+		}
+	})
+
+	t.Run("Sybil: an independent observer still verifies despite a suspicious cluster", func(t *testing.T) {
+		f := newTriangulationFixture(t, []entity.Report{
+			{ID: "r1", ObserverID: "u1", AuthorRole: entity.RoleCitizen, DeviceFingerprint: "dev", IncidentType: "A", CreatedAt: now},
+			{ID: "r2", ObserverID: "u2", AuthorRole: entity.RoleCitizen, DeviceFingerprint: "dev", IncidentType: "A", CreatedAt: now},
+			{ID: "obs", ObserverID: "u3", AuthorRole: entity.RoleObserver, IncidentType: "A", CreatedAt: now},
+			{ID: "target", ObserverID: "u1", AuthorRole: entity.RoleCitizen, IncidentType: "A", CreatedAt: now},
+		})
+
+		if err := f.service.CalculateTrustScore(ctx, "target"); err != nil {
+			t.Fatalf("Calculation failed: %v", err)
+		}
+
+		if status := reportStatus(t, f.reports, "target"); status != entity.StatusVerified {
+			t.Errorf("Expected status VERIFIED thanks to the observer, got %s", status)
+		}
+		if open := f.openClusters(t); len(open) != 0 {
+			t.Errorf("Expected no flag when independent sources suffice, got %d", len(open))
+		}
+	})
+	t.Run("Conflit: different incident types in the same area open a conflict", func(t *testing.T) {
+		f := newTriangulationFixture(t, []entity.Report{
+			{ID: "r1", AuthorRole: entity.RoleObserver, IncidentType: "VIOLE", CreatedAt: now},
+			{ID: "target", AuthorRole: entity.RoleObserver, IncidentType: "STUFF", CreatedAt: now},
+		})
+
+		if err := f.service.CalculateTrustScore(ctx, "target"); err != nil {
+			t.Fatalf("Calculation failed: %v", err)
+		}
+
+		if reportStatus(t, f.reports, "target") == entity.StatusVerified {
+			t.Errorf("Expected conflicting report to remain PENDING, but was VERIFIED")
+		}
+		open, _ := f.conflicts.GetAll(ctx, string(entity.ConflictOpen))
+		if len(open) != 1 {
+			t.Fatalf("Expected 1 conflict, got %d", len(open))
+		}
+		if got := open[0]; len(got.ReportIDs) != 2 || len(got.IncidentTypes) != 2 {
+			t.Errorf("Expected conflict linking 2 reports and 2 types, got %+v", got)
+		}
+
+		// Rejoué, le même désaccord enrichit le conflit ouvert au lieu d'en créer un autre
+		if err := f.service.CalculateTrustScore(ctx, "r1"); err != nil {
+			t.Fatalf("Calculation failed: %v", err)
+		}
+		if all, _ := f.conflicts.GetAll(ctx, ""); len(all) != 1 {
+			t.Errorf("Expected the open conflict to be reused, got %d conflicts", len(all))
+		}
+	})
+
+	t.Run("Conflit: report in an open conflict is not auto-verified", func(t *testing.T) {
+		f := newTriangulationFixture(t, []entity.Report{
+			{ID: "obs", AuthorRole: entity.RoleObserver, IncidentType: "B", CreatedAt: now},
+		})
+		held := &entity.Conflict{IncidentTypes: []string{"A", "B"}, ReportIDs: []string{"obs"}}
+		if err := f.conflicts.Create(ctx, held); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := f.service.CalculateTrustScore(ctx, "obs"); err != nil {
+			t.Fatalf("Calculation failed: %v", err)
+		}
+
+		if reportStatus(t, f.reports, "obs") == entity.StatusVerified {
+			t.Errorf("Expected report in open conflict to remain PENDING, but was VERIFIED")
+		}
 	})
 }
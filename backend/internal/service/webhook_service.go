@@ -0,0 +1,438 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/event"
+	"github.com/openvote/backend/internal/domain/repository"
+	"github.com/openvote/backend/internal/platform/logging"
+)
+
+var webhookLogger = logging.For("webhook")
+
+// En-têtes envoyés avec chaque livraison. La signature porte sur "<timestamp>.<corps>" :
+// le partenaire recalcule HMAC-SHA256 avec son secret et rejette les horodatages trop anciens.
+const (
+	WebhookSignatureHeader = "X-OpenVote-Signature"
+	WebhookTimestampHeader = "X-OpenVote-Timestamp"
+	WebhookEventHeader     = "X-OpenVote-Event"
+	WebhookDeliveryHeader  = "X-OpenVote-Delivery"
+)
+
+const (
+	// webhookMaxAttempts : au-delà, la livraison passe en échec (rejouable manuellement)
+	webhookMaxAttempts   = 8
+	webhookBaseBackoff   = 30 * time.Second
+	webhookMaxBackoff    = 6 * time.Hour
+	webhookHTTPTimeout   = 10 * time.Second
+	webhookDialTimeout   = 5 * time.Second
+	webhookBatchSize     = 50
+	webhookDeliveryLease = time.Minute
+)
+
+var (
+	ErrWebhookNotFound  = errors.New("webhook subscription not found")
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+	ErrInvalidWebhook   = errors.New("invalid webhook subscription")
+
+	// errWebhookTargetBlocked : adresse interne refusée à la connexion (DNS modifié, redirection)
+	errWebhookTargetBlocked = errors.New("webhook target resolves to a loopback, link-local or private address")
+)
+
+type WebhookService interface {
+	// CreateSubscription valide l'abonnement et génère son secret HMAC (sub.Secret)
+	CreateSubscription(ctx context.Context, sub *entity.WebhookSubscription) error
+	UpdateSubscription(ctx context.Context, sub *entity.WebhookSubscription) error
+	DeleteSubscription(ctx context.Context, id string) error
+	GetSubscription(ctx context.Context, id string) (*entity.WebhookSubscription, error)
+	ListSubscriptions(ctx context.Context) ([]entity.WebhookSubscription, error)
+
+	// Dispatch crée une livraison pour chaque abonnement correspondant à l'événement
+	Dispatch(ctx context.Context, env event.Envelope) (int, error)
+	// DeliverDue envoie les livraisons échues et retourne le nombre traité
+	DeliverDue(ctx context.Context) (int, error)
+	ListDeliveries(ctx context.Context, subscriptionID, status string, limit int) ([]entity.WebhookDelivery, error)
+	// ReplayDelivery remet une livraison (échouée ou non) en file d'envoi immédiat
+	ReplayDelivery(ctx context.Context, id string) (*entity.WebhookDelivery, error)
+}
+
+type webhookService struct {
+	repo      repository.WebhookRepository
+	client    *http.Client
+	allowHTTP bool
+	lookupIP  func(ctx context.Context, host string) ([]net.IPAddr, error)
+	now       func() time.Time
+}
+
+// NewWebhookService accepte un client HTTP nil : le client par défaut (délai d'expiration) refuse
+// de se connecter aux adresses internes. allowHTTP accepte les URL http:// (développement).
+func NewWebhookService(repo repository.WebhookRepository, client *http.Client, allowHTTP bool) WebhookService {
+	if client == nil {
+		client = newWebhookClient()
+	}
+	return &webhookService{repo: repo, client: client, allowHTTP: allowHTTP, lookupIP: net.DefaultResolver.LookupIPAddr, now: time.Now}
+}
+
+// newWebhookClient vérifie chaque adresse au moment de la connexion : la validation de l'URL ne
+// suffit pas (DNS modifié depuis l'enregistrement, redirection vers une adresse interne)
+func newWebhookClient() *http.Client {
+	dialer := &net.Dialer{Timeout: webhookDialTimeout, KeepAlive: 30 * time.Second}
+	transport := &http.Transport{
+		// Pas de proxy : la connexion partirait vers le proxy, hors de la vérification
+		Proxy: nil,
+		DialContext: func(ctx context.Context, network, addr string) (net.Conn, error) {
+			host, port, err := net.SplitHostPort(addr)
+			if err != nil {
+				return nil, err
+			}
+			ips, err := net.DefaultResolver.LookupIPAddr(ctx, host)
+			if err != nil {
+				return nil, err
+			}
+			for _, ip := range ips {
+				if blockedWebhookIP(ip.IP) {
+					return nil, fmt.Errorf("%w: %s (%s)", errWebhookTargetBlocked, host, ip.IP)
+				}
+			}
+			var dialErr error
+			for _, ip := range ips {
+				conn, err := dialer.DialContext(ctx, network, net.JoinHostPort(ip.IP.String(), port))
+				if err == nil {
+					return conn, nil
+				}
+				dialErr = err
+			}
+			if dialErr == nil {
+				dialErr = fmt.Errorf("no address for %s", host)
+			}
+			return nil, dialErr
+		},
+		ForceAttemptHTTP2:   true,
+		MaxIdleConns:        100,
+		IdleConnTimeout:     90 * time.Second,
+		TLSHandshakeTimeout: webhookDialTimeout,
+	}
+	return &http.Client{Timeout: webhookHTTPTimeout, Transport: transport}
+}
+
+// blockedWebhookIP : boucle locale, lien local (dont les métadonnées cloud 169.254.169.254),
+// réseaux privés (RFC 1918, ULA IPv6), adresse non spécifiée et multicast
+func blockedWebhookIP(ip net.IP) bool {
+	return ip.IsLoopback() || ip.IsLinkLocalUnicast() || ip.IsLinkLocalMulticast() || ip.IsPrivate() ||
+		ip.IsUnspecified() || ip.IsMulticast()
+}
+
+func (s *webhookService) CreateSubscription(ctx context.Context, sub *entity.WebhookSubscription) error {
+	if err := s.validateWebhook(ctx, sub); err != nil {
+		return err
+	}
+	secret, err := NewWebhookSecret()
+	if err != nil {
+		return err
+	}
+	sub.Secret = secret
+	return s.repo.CreateSubscription(ctx, sub)
+}
+
+func (s *webhookService) UpdateSubscription(ctx context.Context, sub *entity.WebhookSubscription) error {
+	existing, err := s.repo.GetSubscription(ctx, sub.ID)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return ErrWebhookNotFound
+	}
+	if err := s.validateWebhook(ctx, sub); err != nil {
+		return err
+	}
+	return s.repo.UpdateSubscription(ctx, sub)
+}
+
+func (s *webhookService) DeleteSubscription(ctx context.Context, id string) error {
+	existing, err := s.repo.GetSubscription(ctx, id)
+	if err != nil {
+		return err
+	}
+	if existing == nil {
+		return ErrWebhookNotFound
+	}
+	return s.repo.DeleteSubscription(ctx, id)
+}
+
+func (s *webhookService) GetSubscription(ctx context.Context, id string) (*entity.WebhookSubscription, error) {
+	sub, err := s.repo.GetSubscription(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if sub == nil {
+		return nil, ErrWebhookNotFound
+	}
+	return sub, nil
+}
+
+func (s *webhookService) ListSubscriptions(ctx context.Context) ([]entity.WebhookSubscription, error) {
+	return s.repo.ListSubscriptions(ctx)
+}
+
+// validateWebhook vérifie l'URL, les types d'événements et la sévérité minimale
+func (s *webhookService) validateWebhook(ctx context.Context, sub *entity.WebhookSubscription) error {
+	if sub.Name == "" {
+		return fmt.Errorf("%w: name is required", ErrInvalidWebhook)
+	}
+	u, err := url.Parse(sub.URL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("%w: url must be an absolute http(s) URL", ErrInvalidWebhook)
+	}
+	if u.Scheme == "http" && !s.allowHTTP {
+		return fmt.Errorf("%w: url must use https", ErrInvalidWebhook)
+	}
+	if err := s.validateWebhookHost(ctx, u.Hostname()); err != nil {
+		return err
+	}
+	if len(sub.EventTypes) == 0 {
+		return fmt.Errorf("%w: at least one event type is required", ErrInvalidWebhook)
+	}
+	for _, t := range sub.EventTypes {
+		if !slices.Contains(event.Types, event.Type(t)) {
+			return fmt.Errorf("%w: unknown event type %q", ErrInvalidWebhook, t)
+		}
+	}
+	if sub.MinSeverity < 0 || sub.MinSeverity > 5 {
+		return fmt.Errorf("%w: min_severity must be between 0 and 5", ErrInvalidWebhook)
+	}
+	return nil
+}
+
+// validateWebhookHost refuse les adresses internes. Un nom qui ne se résout pas encore est
+// accepté : la connexion, vérifiée à chaque livraison, reste le contrôle déterminant.
+func (s *webhookService) validateWebhookHost(ctx context.Context, host string) error {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return fmt.Errorf("%w: url must not target a local address", ErrInvalidWebhook)
+	}
+	var ips []net.IP
+	if ip := net.ParseIP(host); ip != nil {
+		ips = append(ips, ip)
+	} else if addrs, err := s.lookupIP(ctx, host); err == nil {
+		for _, addr := range addrs {
+			ips = append(ips, addr.IP)
+		}
+	}
+	for _, ip := range ips {
+		if blockedWebhookIP(ip) {
+			return fmt.Errorf("%w: url must not target a loopback, link-local or private address (%s)", ErrInvalidWebhook, ip)
+		}
+	}
+	return nil
+}
+
+// NewWebhookSecret génère un secret de signature (création ou rotation)
+func NewWebhookSecret() (string, error) {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate webhook secret: %w", err)
+	}
+	return "whsec_" + hex.EncodeToString(b), nil
+}
+
+// webhookAttributes : champs communs aux événements de signalement, utilisés par les filtres
+type webhookAttributes struct {
+	RegionID string `json:"region_id"`
+	Severity int    `json:"severity"`
+}
+
+// webhookMatches applique les filtres de l'abonnement ; un événement sans région ou sans
+// sévérité n'est transmis qu'aux abonnements qui ne filtrent pas sur ce critère
+func webhookMatches(sub entity.WebhookSubscription, eventType event.Type, attrs webhookAttributes) bool {
+	if !sub.Active || !slices.Contains(sub.EventTypes, string(eventType)) {
+		return false
+	}
+	if len(sub.RegionIDs) > 0 && !slices.Contains(sub.RegionIDs, attrs.RegionID) {
+		return false
+	}
+	return attrs.Severity >= sub.MinSeverity
+}
+
+func (s *webhookService) Dispatch(ctx context.Context, env event.Envelope) (int, error) {
+	if !alertRoutedTo(env, AlertChannelWebhook) {
+		return 0, nil
+	}
+	subs, err := s.repo.ActiveSubscriptions(ctx, string(env.Type))
+	if err != nil {
+		return 0, fmt.Errorf("failed to load webhook subscriptions: %w", err)
+	}
+	if len(subs) == 0 {
+		return 0, nil
+	}
+
+	var attrs webhookAttributes
+	if err := json.Unmarshal(env.Data, &attrs); err != nil {
+		return 0, fmt.Errorf("failed to read event attributes: %w", err)
+	}
+	payload, err := json.Marshal(env)
+	if err != nil {
+		return 0, fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	created := 0
+	for _, sub := range subs {
+		if !webhookMatches(sub, env.Type, attrs) {
+			continue
+		}
+		delivery := &entity.WebhookDelivery{
+			SubscriptionID: sub.ID,
+			EventID:        env.ID,
+			EventType:      string(env.Type),
+			Payload:        payload,
+		}
+		// Événement relivré par le bus : la livraison existe déjà, rien à faire
+		isNew, err := s.repo.CreateDelivery(ctx, delivery)
+		if err != nil {
+			return created, fmt.Errorf("failed to create delivery for %s: %w", sub.ID, err)
+		}
+		if isNew {
+			created++
+		}
+	}
+	return created, nil
+}
+
+func (s *webhookService) DeliverDue(ctx context.Context) (int, error) {
+	deliveries, err := s.repo.ClaimDueDeliveries(ctx, webhookBatchSize, webhookDeliveryLease)
+	if err != nil {
+		return 0, err
+	}
+
+	subs := make(map[string]*entity.WebhookSubscription)
+	for _, d := range deliveries {
+		sub, ok := subs[d.SubscriptionID]
+		if !ok {
+			if sub, err = s.repo.GetSubscription(ctx, d.SubscriptionID); err != nil {
+				return 0, err
+			}
+			subs[d.SubscriptionID] = sub
+		}
+		if sub == nil || !sub.Active {
+			if err := s.repo.MarkFailed(ctx, d.ID, 0, "abonnement désactivé", nil); err != nil {
+				webhookLogger.ErrorContext(ctx, "Impossible de consigner l'échec", "delivery_id", d.ID, "error", err)
+			}
+			continue
+		}
+		s.deliver(ctx, sub, d)
+	}
+	return len(deliveries), nil
+}
+
+// deliver envoie une livraison et consigne le résultat (succès, nouvelle tentative ou échec)
+func (s *webhookService) deliver(ctx context.Context, sub *entity.WebhookSubscription, d entity.WebhookDelivery) {
+	status, sendErr := s.send(ctx, sub, d)
+	if sendErr == nil {
+		if err := s.repo.MarkDelivered(ctx, d.ID, status); err != nil {
+			webhookLogger.ErrorContext(ctx, "Impossible de marquer la livraison comme livrée", "delivery_id", d.ID, "error", err)
+		}
+		return
+	}
+
+	var retryAt *time.Time
+	if d.Attempts < webhookMaxAttempts {
+		next := s.now().Add(webhookBackoff(d.Attempts))
+		retryAt = &next
+		webhookLogger.WarnContext(ctx, "Livraison échouée, nouvelle tentative planifiée", "delivery_id", d.ID, "subscription", sub.Name, "attempt", d.Attempts, "retry_at", next, "error", sendErr)
+	} else {
+		webhookLogger.ErrorContext(ctx, "Livraison abandonnée", "delivery_id", d.ID, "subscription", sub.Name, "attempts", d.Attempts, "error", sendErr)
+	}
+	if err := s.repo.MarkFailed(ctx, d.ID, status, sendErr.Error(), retryAt); err != nil {
+		webhookLogger.ErrorContext(ctx, "Impossible de consigner l'échec", "delivery_id", d.ID, "error", err)
+	}
+}
+
+// send poste la charge signée ; toute réponse hors 2xx est une erreur
+func (s *webhookService) send(ctx context.Context, sub *entity.WebhookSubscription, d entity.WebhookDelivery) (int, error) {
+	timestamp := s.now().Unix()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, sub.URL, bytes.NewReader(d.Payload))
+	if err != nil {
+		return 0, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "OpenVote-Webhooks/1.0")
+	req.Header.Set(WebhookEventHeader, d.EventType)
+	req.Header.Set(WebhookDeliveryHeader, d.ID)
+	req.Header.Set(WebhookTimestampHeader, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(WebhookSignatureHeader, SignWebhookPayload(sub.Secret, timestamp, d.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected response status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// SignWebhookPayload calcule la signature "sha256=<hex>" de "<timestamp>.<corps>"
+func SignWebhookPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// webhookBackoff : 30s, 1m, 2m, 4m... plafonné à 6h
+func webhookBackoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	if attempts > 20 {
+		return webhookMaxBackoff
+	}
+	delay := webhookBaseBackoff * time.Duration(1<<(attempts-1))
+	if delay > webhookMaxBackoff {
+		return webhookMaxBackoff
+	}
+	return delay
+}
+
+func (s *webhookService) ListDeliveries(ctx context.Context, subscriptionID, status string, limit int) ([]entity.WebhookDelivery, error) {
+	if _, err := s.GetSubscription(ctx, subscriptionID); err != nil {
+		return nil, err
+	}
+	return s.repo.ListDeliveries(ctx, subscriptionID, status, limit)
+}
+
+func (s *webhookService) ReplayDelivery(ctx context.Context, id string) (*entity.WebhookDelivery, error) {
+	d, err := s.repo.GetDelivery(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if d == nil {
+		return nil, ErrDeliveryNotFound
+	}
+	if err := s.repo.ResetDelivery(ctx, id); err != nil {
+		return nil, err
+	}
+	d.Status = entity.DeliveryPending
+	d.Attempts = 0
+	return d, nil
+}
@@ -0,0 +1,317 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/event"
+)
+
+// Mock de WebhookRepository en mémoire
+type mockWebhookRepo struct {
+	subs       map[string]*entity.WebhookSubscription
+	deliveries map[string]*entity.WebhookDelivery
+	retryAt    map[string]*time.Time
+}
+
+func newMockWebhookRepo(subs ...entity.WebhookSubscription) *mockWebhookRepo {
+	m := &mockWebhookRepo{
+		subs:       map[string]*entity.WebhookSubscription{},
+		deliveries: map[string]*entity.WebhookDelivery{},
+		retryAt:    map[string]*time.Time{},
+	}
+	for i := range subs {
+		m.subs[subs[i].ID] = &subs[i]
+	}
+	return m
+}
+
+func (m *mockWebhookRepo) CreateSubscription(ctx context.Context, s *entity.WebhookSubscription) error {
+	s.ID = "sub-new"
+	m.subs[s.ID] = s
+	return nil
+}
+func (m *mockWebhookRepo) UpdateSubscription(ctx context.Context, s *entity.WebhookSubscription) error {
+	m.subs[s.ID] = s
+	return nil
+}
+func (m *mockWebhookRepo) DeleteSubscription(ctx context.Context, id string) error {
+	delete(m.subs, id)
+	return nil
+}
+func (m *mockWebhookRepo) UpdateSecret(ctx context.Context, id, secret string) error {
+	m.subs[id].Secret = secret
+	return nil
+}
+func (m *mockWebhookRepo) GetSubscription(ctx context.Context, id string) (*entity.WebhookSubscription, error) {
+	return m.subs[id], nil
+}
+func (m *mockWebhookRepo) ListSubscriptions(ctx context.Context) ([]entity.WebhookSubscription, error) {
+	var subs []entity.WebhookSubscription
+	for _, s := range m.subs {
+		subs = append(subs, *s)
+	}
+	return subs, nil
+}
+func (m *mockWebhookRepo) ActiveSubscriptions(ctx context.Context, eventType string) ([]entity.WebhookSubscription, error) {
+	return m.ListSubscriptions(ctx)
+}
+func (m *mockWebhookRepo) CreateDelivery(ctx context.Context, d *entity.WebhookDelivery) (bool, error) {
+	for _, existing := range m.deliveries {
+		if existing.SubscriptionID == d.SubscriptionID && existing.EventID == d.EventID {
+			return false, nil
+		}
+	}
+	d.ID = "del-" + d.SubscriptionID + "-" + d.EventID
+	d.Status = entity.DeliveryPending
+	m.deliveries[d.ID] = d
+	return true, nil
+}
+func (m *mockWebhookRepo) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]entity.WebhookDelivery, error) {
+	var due []entity.WebhookDelivery
+	for _, d := range m.deliveries {
+		if d.Status == entity.DeliveryPending {
+			d.Attempts++
+			due = append(due, *d)
+		}
+	}
+	return due, nil
+}
+func (m *mockWebhookRepo) MarkDelivered(ctx context.Context, id string, responseStatus int) error {
+	m.deliveries[id].Status = entity.DeliveryDelivered
+	m.deliveries[id].ResponseStatus = responseStatus
+	return nil
+}
+func (m *mockWebhookRepo) MarkFailed(ctx context.Context, id string, responseStatus int, cause string, retryAt *time.Time) error {
+	d := m.deliveries[id]
+	d.ResponseStatus = responseStatus
+	d.LastError = cause
+	m.retryAt[id] = retryAt
+	if retryAt == nil {
+		d.Status = entity.DeliveryFailed
+	}
+	return nil
+}
+func (m *mockWebhookRepo) GetDelivery(ctx context.Context, id string) (*entity.WebhookDelivery, error) {
+	return m.deliveries[id], nil
+}
+func (m *mockWebhookRepo) ListDeliveries(ctx context.Context, subscriptionID, status string, limit int) ([]entity.WebhookDelivery, error) {
+	return nil, nil
+}
+func (m *mockWebhookRepo) ResetDelivery(ctx context.Context, id string) error {
+	m.deliveries[id].Status = entity.DeliveryPending
+	m.deliveries[id].Attempts = 0
+	return nil
+}
+
+func mustEnvelope(t *testing.T, p event.Payload) event.Envelope {
+	t.Helper()
+	env, err := event.New(p)
+	if err != nil {
+		t.Fatalf("event: %v", err)
+	}
+	return env
+}
+
+func TestWebhookDispatch_AppliesFilters(t *testing.T) {
+	repo := newMockWebhookRepo(
+		entity.WebhookSubscription{ID: "all", Active: true, EventTypes: []string{"report.created"}},
+		entity.WebhookSubscription{ID: "north-critical", Active: true, EventTypes: []string{"report.created"}, RegionIDs: []string{"north"}, MinSeverity: 4},
+		entity.WebhookSubscription{ID: "south", Active: true, EventTypes: []string{"report.created"}, RegionIDs: []string{"south"}},
+		entity.WebhookSubscription{ID: "verified-only", Active: true, EventTypes: []string{"report.status_changed"}},
+		entity.WebhookSubscription{ID: "paused", Active: false, EventTypes: []string{"report.created"}},
+	)
+	s := NewWebhookService(repo, nil, false)
+	ctx := context.Background()
+
+	env := mustEnvelope(t, event.ReportCreated{ReportID: "r1", Severity: 5, RegionID: "north"})
+	n, err := s.Dispatch(ctx, env)
+	if err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Expected deliveries for 'all' and 'north-critical', got %d: %v", n, repo.deliveries)
+	}
+
+	// Sévérité insuffisante pour l'abonnement critique
+	n, _ = s.Dispatch(ctx, mustEnvelope(t, event.ReportCreated{ReportID: "r2", Severity: 2, RegionID: "north"}))
+	if n != 1 {
+		t.Errorf("Expected only 'all' for minor incident, got %d", n)
+	}
+
+	// Un événement relivré par le bus ne crée pas de doublon
+	if n, _ := s.Dispatch(ctx, env); n != 0 {
+		t.Errorf("Expected redelivered event to be ignored, got %d new deliveries", n)
+	}
+}
+
+func TestWebhookDeliverDue_SignsAndRetries(t *testing.T) {
+	var received *http.Request
+	var body []byte
+	fail := true
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r
+		body, _ = io.ReadAll(r.Body)
+		if fail {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	repo := newMockWebhookRepo(entity.WebhookSubscription{ID: "partner", Name: "Partner", URL: server.URL, Secret: "whsec_test", Active: true, EventTypes: []string{"report.status_changed"}})
+	s := NewWebhookService(repo, server.Client(), true).(*webhookService)
+	now := time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+	s.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	if _, err := s.Dispatch(ctx, mustEnvelope(t, event.ReportStatusChanged{ReportID: "r1", NewStatus: "verified"})); err != nil {
+		t.Fatalf("dispatch: %v", err)
+	}
+
+	// 1ère tentative : 502 → nouvelle tentative dans 30s
+	if _, err := s.DeliverDue(ctx); err != nil {
+		t.Fatalf("deliver: %v", err)
+	}
+	var delivery *entity.WebhookDelivery
+	for _, d := range repo.deliveries {
+		delivery = d
+	}
+	if delivery.Status != entity.DeliveryPending || delivery.ResponseStatus != http.StatusBadGateway {
+		t.Errorf("Expected pending delivery after 502, got %+v", delivery)
+	}
+	if retry := repo.retryAt[delivery.ID]; retry == nil || !retry.Equal(now.Add(30*time.Second)) {
+		t.Errorf("Expected retry in 30s, got %v", retry)
+	}
+
+	// Signature vérifiable par le partenaire
+	ts, _ := strconv.ParseInt(received.Header.Get(WebhookTimestampHeader), 10, 64)
+	if got, want := received.Header.Get(WebhookSignatureHeader), SignWebhookPayload("whsec_test", ts, body); got != want {
+		t.Errorf("Signature mismatch: got %s, want %s", got, want)
+	}
+	if received.Header.Get(WebhookEventHeader) != "report.status_changed" {
+		t.Errorf("Unexpected event header %q", received.Header.Get(WebhookEventHeader))
+	}
+
+	// 2ème tentative : succès
+	fail = false
+	s.DeliverDue(ctx)
+	if delivery.Status != entity.DeliveryDelivered || delivery.ResponseStatus != http.StatusNoContent {
+		t.Errorf("Expected delivered, got %+v", delivery)
+	}
+}
+
+func TestWebhookDeliverDue_FailsAfterMaxAttempts(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	repo := newMockWebhookRepo(entity.WebhookSubscription{ID: "partner", URL: server.URL, Secret: "s", Active: true, EventTypes: []string{"report.created"}})
+	s := NewWebhookService(repo, server.Client(), true)
+	ctx := context.Background()
+	s.Dispatch(ctx, mustEnvelope(t, event.ReportCreated{ReportID: "r1"}))
+
+	for i := 0; i < webhookMaxAttempts; i++ {
+		s.DeliverDue(ctx)
+	}
+	var delivery *entity.WebhookDelivery
+	for _, d := range repo.deliveries {
+		delivery = d
+	}
+	if delivery.Status != entity.DeliveryFailed || delivery.Attempts != webhookMaxAttempts {
+		t.Fatalf("Expected failed after %d attempts, got %+v", webhookMaxAttempts, delivery)
+	}
+
+	// Rejeu manuel : la livraison repart de zéro
+	replayed, err := s.ReplayDelivery(ctx, delivery.ID)
+	if err != nil || replayed.Status != entity.DeliveryPending || delivery.Attempts != 0 {
+		t.Errorf("Expected replayed delivery pending, got %+v, %v", replayed, err)
+	}
+	if _, err := s.ReplayDelivery(ctx, "missing"); !errors.Is(err, ErrDeliveryNotFound) {
+		t.Errorf("Expected ErrDeliveryNotFound, got %v", err)
+	}
+}
+
+func TestWebhookCreateSubscription_Validates(t *testing.T) {
+	s := NewWebhookService(newMockWebhookRepo(), nil, false).(*webhookService)
+	s.lookupIP = func(ctx context.Context, host string) ([]net.IPAddr, error) {
+		if host == "intranet.partner.example" {
+			return []net.IPAddr{{IP: net.ParseIP("10.1.2.3")}}, nil
+		}
+		return []net.IPAddr{{IP: net.ParseIP("203.0.113.10")}}, nil
+	}
+	ctx := context.Background()
+
+	invalid := []entity.WebhookSubscription{
+		{Name: "no url", EventTypes: []string{"report.created"}},
+		{Name: "ftp", URL: "ftp://partner.example", EventTypes: []string{"report.created"}},
+		{Name: "plain http", URL: "http://partner.example/hook", EventTypes: []string{"report.created"}},
+		{Name: "localhost", URL: "https://localhost:8443/hook", EventTypes: []string{"report.created"}},
+		{Name: "loopback", URL: "https://127.0.0.1/hook", EventTypes: []string{"report.created"}},
+		{Name: "loopback v6", URL: "https://[::1]/hook", EventTypes: []string{"report.created"}},
+		{Name: "metadata", URL: "https://169.254.169.254/latest", EventTypes: []string{"report.created"}},
+		{Name: "rfc1918", URL: "https://192.168.1.20/hook", EventTypes: []string{"report.created"}},
+		{Name: "resolves private", URL: "https://intranet.partner.example/hook", EventTypes: []string{"report.created"}},
+		{Name: "no events", URL: "https://partner.example/hook"},
+		{Name: "unknown event", URL: "https://partner.example/hook", EventTypes: []string{"report.deleted"}},
+		{Name: "severity", URL: "https://partner.example/hook", EventTypes: []string{"report.created"}, MinSeverity: 9},
+	}
+	for _, sub := range invalid {
+		sub := sub
+		if err := s.CreateSubscription(ctx, &sub); !errors.Is(err, ErrInvalidWebhook) {
+			t.Errorf("%s: expected ErrInvalidWebhook, got %v", sub.Name, err)
+		}
+	}
+
+	sub := entity.WebhookSubscription{Name: "ok", URL: "https://partner.example/hook", EventTypes: []string{"report.triangulated"}, Active: true}
+	if err := s.CreateSubscription(ctx, &sub); err != nil {
+		t.Fatalf("create: %v", err)
+	}
+	if len(sub.Secret) != len("whsec_")+64 {
+		t.Errorf("Expected generated secret, got %q", sub.Secret)
+	}
+
+	// http:// reste possible en développement, jamais vers une adresse interne
+	s.allowHTTP = true
+	local := entity.WebhookSubscription{Name: "dev", URL: "http://partner.example/hook", EventTypes: []string{"report.created"}}
+	if err := s.CreateSubscription(ctx, &local); err != nil {
+		t.Errorf("http in development: %v", err)
+	}
+	local.URL = "http://10.0.0.8/hook"
+	if err := s.CreateSubscription(ctx, &local); !errors.Is(err, ErrInvalidWebhook) {
+		t.Errorf("private address in development: %v", err)
+	}
+}
+
+func TestWebhookClientRefusesInternalAddresses(t *testing.T) {
+	// Abonnement enregistré avant un changement de DNS : la connexion est refusée quand même
+	var called bool
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { called = true }))
+	defer server.Close()
+
+	sub := &entity.WebhookSubscription{ID: "partner", URL: server.URL, Secret: "s", Active: true}
+	s := NewWebhookService(newMockWebhookRepo(*sub), nil, true).(*webhookService)
+	_, err := s.send(context.Background(), sub, entity.WebhookDelivery{ID: "d1", EventType: "report.created", Payload: []byte(`{}`)})
+	if !errors.Is(err, errWebhookTargetBlocked) || called {
+		t.Errorf("send to %s: err = %v, called = %v; want errWebhookTargetBlocked", server.URL, err, called)
+	}
+}
+
+func TestWebhookBackoff(t *testing.T) {
+	cases := map[int]time.Duration{1: 30 * time.Second, 2: time.Minute, 4: 4 * time.Minute, 15: webhookMaxBackoff}
+	for attempts, want := range cases {
+		if got := webhookBackoff(attempts); got != want {
+			t.Errorf("webhookBackoff(%d) = %s, want %s", attempts, got, want)
+		}
+	}
+}
@@ -0,0 +1,36 @@
+package worker
+
+import (
+	"context"
+
+	"github.com/openvote/backend/internal/domain/event"
+	"github.com/openvote/backend/internal/platform/logging"
+	"github.com/openvote/backend/internal/service"
+)
+
+var alertLogger = logging.For("alert")
+
+// AlertEvaluator confronte chaque nouveau signalement aux règles d'alerte actives
+type AlertEvaluator struct {
+	alertService service.AlertService
+}
+
+func NewAlertEvaluator(alertService service.AlertService) *AlertEvaluator {
+	return &AlertEvaluator{alertService: alertService}
+}
+
+// HandleEvent est l'abonné du registre d'événements (report.created)
+func (e *AlertEvaluator) HandleEvent(ctx context.Context, env event.Envelope) error {
+	var created event.ReportCreated
+	if err := env.Decode(&created); err != nil {
+		return err
+	}
+	alerts, err := e.alertService.EvaluateReport(ctx, created.ReportID)
+	if err != nil {
+		return err
+	}
+	for _, alert := range alerts {
+		alertLogger.InfoContext(ctx, "Alerte déclenchée", "rule", alert.RuleName, "report_count", alert.ReportCount, "group_key", alert.GroupKey, "alert_id", alert.ID)
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package worker
+
+import (
+	"context"
+	"slices"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/event"
+	"github.com/openvote/backend/internal/domain/repository"
+	"github.com/openvote/backend/internal/platform/notify"
+	"github.com/openvote/backend/internal/service"
+)
+
+// urgentAlertSeverity : à partir de cette sévérité, les heures calmes sont ignorées
+const urgentAlertSeverity = 5
+
+// AlertNotifier prévient les coordinateurs concernés quand une alerte se déclenche ou change de statut
+type AlertNotifier struct {
+	notificationService service.NotificationService
+	userRepo            repository.UserRepository
+}
+
+func NewAlertNotifier(notificationService service.NotificationService, userRepo repository.UserRepository) *AlertNotifier {
+	return &AlertNotifier{notificationService: notificationService, userRepo: userRepo}
+}
+
+// HandleEvent est l'abonné du registre d'événements (alert.triggered, alert.status_changed)
+func (n *AlertNotifier) HandleEvent(ctx context.Context, env event.Envelope) error {
+	var (
+		kind     string
+		data     interface{}
+		regionID string
+		severity int
+		channels []string
+	)
+	switch env.Type {
+	case event.TypeAlertTriggered:
+		var triggered event.AlertTriggered
+		if err := env.Decode(&triggered); err != nil {
+			return err
+		}
+		kind, data, regionID, severity, channels = service.NotificationAlertTriggered, triggered, triggered.RegionID, triggered.Severity, triggered.Channels
+	case event.TypeAlertStatusChanged:
+		var changed event.AlertStatusChanged
+		if err := env.Decode(&changed); err != nil {
+			return err
+		}
+		kind, data, regionID, severity, channels = service.NotificationAlertStatusChanged, changed, changed.RegionID, changed.Severity, changed.Channels
+	default:
+		return nil
+	}
+
+	// Seuls les canaux de notification de la règle sont retenus (dashboard et webhook ont leurs propres relais)
+	var selected []string
+	for _, ch := range channels {
+		if ch == notify.ChannelEmail || ch == notify.ChannelSMS || ch == notify.ChannelInbox {
+			selected = append(selected, ch)
+		}
+	}
+	if len(selected) == 0 {
+		return nil
+	}
+
+	recipients, err := n.recipients(ctx, regionID)
+	if err != nil {
+		return err
+	}
+	if len(recipients) == 0 {
+		return nil
+	}
+
+	count, err := n.notificationService.Notify(ctx, service.NotificationRequest{
+		Kind:     kind,
+		UserIDs:  recipients,
+		Data:     data,
+		DedupKey: env.ID,
+		Channels: selected,
+		Urgent:   severity >= urgentAlertSeverity,
+	})
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		notifyLogger.InfoContext(ctx, "Notifications planifiées", "event_type", env.Type, "event_id", env.ID, "count", count)
+	}
+	return nil
+}
+
+// recipients : les super_admin, plus les administrateurs et coordinateurs de la région de l'alerte
+func (n *AlertNotifier) recipients(ctx context.Context, regionID string) ([]string, error) {
+	users, err := n.userRepo.GetAll(ctx)
+	if err != nil {
+		return nil, err
+	}
+	regional := []entity.UserRole{entity.RoleRegionAdmin, entity.RoleLocalCoord}
+
+	var ids []string
+	for _, u := range users {
+		if u.Role == entity.RoleSuperAdmin || (regionID != "" && u.RegionID == regionID && slices.Contains(regional, u.Role)) {
+			ids = append(ids, u.ID)
+		}
+	}
+	return ids, nil
+}
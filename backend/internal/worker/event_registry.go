@@ -0,0 +1,93 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/openvote/backend/internal/domain/event"
+	"github.com/openvote/backend/internal/platform/logging"
+	"github.com/openvote/backend/internal/platform/queue"
+)
+
+var eventsLogger = logging.For("events")
+
+// EventHandler traite un événement reçu du bus
+type EventHandler func(ctx context.Context, env event.Envelope) error
+
+type eventSubscription struct {
+	name     string
+	patterns []string
+	handler  EventHandler
+}
+
+// EventRegistry abonne des consommateurs (notifications, webhooks, statistiques...) aux
+// événements métier sans toucher aux services qui les publient. Chaque abonné dispose de sa
+// propre file durable ("events.<nom>"), liée à l'échange des événements : il reçoit sa copie
+// de chaque événement, avec nouvelles tentatives et file morte indépendantes.
+type EventRegistry struct {
+	consumer      queue.Consumer
+	subscriptions []eventSubscription
+}
+
+func NewEventRegistry(consumer queue.Consumer) *EventRegistry {
+	return &EventRegistry{consumer: consumer}
+}
+
+// Abonnés communs à l'API et à cmd/worker ; le flux temps réel a en plus une file par instance de l'API
+const (
+	SubscriberWebhooks      = "webhooks"
+	SubscriberAlerts        = "alerts"
+	SubscriberNotifications = "notifications"
+)
+
+// SharedSubscribers liste les abonnés communs, pour l'administration de leurs files mortes
+var SharedSubscribers = []string{SubscriberWebhooks, SubscriberAlerts, SubscriberNotifications}
+
+// EventQueueName nomme la file d'un abonné
+func EventQueueName(subscriber string) string {
+	return "events." + subscriber
+}
+
+// Subscribe enregistre un abonné pour des types d'événements ; les motifs topic
+// sont acceptés ("report.*", "#"). À appeler avant Start.
+func (r *EventRegistry) Subscribe(name string, handler EventHandler, types ...event.Type) {
+	patterns := make([]string, len(types))
+	for i, t := range types {
+		patterns[i] = string(t)
+	}
+	r.subscriptions = append(r.subscriptions, eventSubscription{name: name, patterns: patterns, handler: handler})
+}
+
+// Subscribers liste les files des abonnés enregistrés
+func (r *EventRegistry) Subscribers() []string {
+	names := make([]string, len(r.subscriptions))
+	for i, sub := range r.subscriptions {
+		names[i] = EventQueueName(sub.name)
+	}
+	return names
+}
+
+// Start lie les files des abonnés à l'échange puis lance leur consommation
+func (r *EventRegistry) Start(ctx context.Context) error {
+	for _, sub := range r.subscriptions {
+		queueName := EventQueueName(sub.name)
+		if err := r.consumer.Bind(ctx, queueName, sub.patterns...); err != nil {
+			return fmt.Errorf("failed to bind subscriber %s: %w", sub.name, err)
+		}
+
+		handler := sub.handler
+		err := r.consumer.Consume(ctx, queueName, func(ctx context.Context, body []byte) error {
+			var env event.Envelope
+			if err := json.Unmarshal(body, &env); err != nil {
+				return fmt.Errorf("failed to unmarshal event: %w", err)
+			}
+			return handler(ctx, env)
+		})
+		if err != nil {
+			return fmt.Errorf("failed to start subscriber %s: %w", sub.name, err)
+		}
+		eventsLogger.Info("Abonné démarré", "subscriber", sub.name, "patterns", sub.patterns)
+	}
+	return nil
+}
@@ -0,0 +1,98 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/event"
+	"github.com/openvote/backend/internal/platform/queue"
+)
+
+func TestEventRegistry_RoutesEventsToSubscribers(t *testing.T) {
+	broker := queue.NewMemoryBroker(queue.DefaultConsumerOptions())
+	defer broker.Close()
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	reports := make(chan event.ReportStatusChanged, 1)
+	all := make(chan event.Type, 2)
+
+	registry := NewEventRegistry(broker)
+	registry.Subscribe("webhooks", func(ctx context.Context, env event.Envelope) error {
+		var changed event.ReportStatusChanged
+		if err := env.Decode(&changed); err != nil {
+			return err
+		}
+		reports <- changed
+		return nil
+	}, event.TypeReportStatusChanged)
+	registry.Subscribe("audit", func(ctx context.Context, env event.Envelope) error {
+		all <- env.Type
+		return nil
+	}, "#")
+	if err := registry.Start(ctx); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+
+	for _, p := range []event.Payload{
+		event.ReportStatusChanged{ReportID: "r1", OldStatus: "pending", NewStatus: "verified", Source: event.SourceAdmin},
+		event.UserRoleChanged{UserID: "u1", OldRole: "citizen", NewRole: "observer"},
+	} {
+		env, err := event.New(p)
+		if err != nil {
+			t.Fatalf("new event: %v", err)
+		}
+		if err := broker.PublishTopic(ctx, string(env.Type), env); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+
+	select {
+	case changed := <-reports:
+		if changed.ReportID != "r1" || changed.NewStatus != "verified" {
+			t.Errorf("unexpected payload: %+v", changed)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("status change not delivered to webhooks subscriber")
+	}
+
+	seen := map[event.Type]bool{}
+	for len(seen) < 2 {
+		select {
+		case typ := <-all:
+			seen[typ] = true
+		case <-time.After(2 * time.Second):
+			t.Fatalf("audit subscriber received only %v", seen)
+		}
+	}
+	select {
+	case extra := <-reports:
+		t.Errorf("webhooks subscriber received unsubscribed event: %+v", extra)
+	default:
+	}
+}
+
+func TestReportIDFromMessage(t *testing.T) {
+	env, err := event.New(event.ReportCreated{ReportID: "r1", Severity: 4})
+	if err != nil {
+		t.Fatalf("new event: %v", err)
+	}
+	body, _ := json.Marshal(env)
+	if id, err := reportIDFromMessage(body); err != nil || id != "r1" {
+		t.Errorf("envelope: got %q, %v", id, err)
+	}
+
+	// Messages publiés avant le bus d'événements : entity.Report brut
+	if id, err := reportIDFromMessage([]byte(`{"id":"legacy","incident_type":"fraude"}`)); err != nil || id != "legacy" {
+		t.Errorf("legacy: got %q, %v", id, err)
+	}
+
+	// Une version de schéma inconnue part en nouvelle tentative / file morte plutôt que d'être mal lue
+	env.Version = 99
+	body, _ = json.Marshal(env)
+	if _, err := reportIDFromMessage(body); err == nil {
+		t.Error("expected unsupported schema version to be rejected")
+	}
+}
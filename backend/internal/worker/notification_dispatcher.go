@@ -0,0 +1,42 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/openvote/backend/internal/platform/logging"
+	"github.com/openvote/backend/internal/service"
+)
+
+var notifyLogger = logging.For("notify")
+
+const notificationPollInterval = 5 * time.Second
+
+// NotificationDispatcher envoie périodiquement les notifications dues
+// (premières tentatives, reprises et envois différés après les heures calmes)
+type NotificationDispatcher struct {
+	notificationService service.NotificationService
+}
+
+func NewNotificationDispatcher(notificationService service.NotificationService) *NotificationDispatcher {
+	return &NotificationDispatcher{notificationService: notificationService}
+}
+
+// Start envoie les notifications dues jusqu'à l'annulation du contexte
+func (d *NotificationDispatcher) Start(ctx context.Context) {
+	notifyLogger.Info("Démarrage de l'expéditeur", "channels", d.notificationService.Channels())
+	ticker := time.NewTicker(notificationPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.notificationService.DeliverDue(ctx); err != nil && ctx.Err() == nil {
+			notifyLogger.Error("Erreur d'envoi", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			notifyLogger.Info("Arrêt de l'expéditeur")
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,126 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/repository"
+	"github.com/openvote/backend/internal/platform/logging"
+	"github.com/openvote/backend/internal/platform/queue"
+	"github.com/openvote/backend/internal/platform/telemetry"
+)
+
+var outboxLogger = logging.For("outbox")
+
+const (
+	outboxPollInterval = time.Second
+	outboxBatchSize    = 100
+	// outboxLease : durée de réservation d'un message ; au-delà, un autre relais peut le reprendre
+	outboxLease = 30 * time.Second
+	// outboxMaxBackoff plafonne le délai entre deux tentatives de publication
+	outboxMaxBackoff = 5 * time.Minute
+	// outboxRetention : durée de conservation des messages publiés
+	outboxRetention     = 7 * 24 * time.Hour
+	outboxPurgeInterval = time.Hour
+)
+
+// OutboxRelay publie vers le broker les messages écrits dans l'outbox, puis les marque envoyés.
+// Un message peut être publié plus d'une fois (crash entre publication et marquage) :
+// les consommateurs doivent rester idempotents.
+type OutboxRelay struct {
+	outboxRepo repository.OutboxRepository
+	publisher  queue.Publisher
+}
+
+func NewOutboxRelay(outboxRepo repository.OutboxRepository, publisher queue.Publisher) *OutboxRelay {
+	return &OutboxRelay{outboxRepo: outboxRepo, publisher: publisher}
+}
+
+// Start relaie l'outbox jusqu'à l'annulation du contexte
+func (r *OutboxRelay) Start(ctx context.Context) {
+	outboxLogger.Info("Démarrage du relais")
+	ticker := time.NewTicker(outboxPollInterval)
+	defer ticker.Stop()
+	lastPurge := time.Now()
+
+	for {
+		// Vide le backlog par lots avant d'attendre le prochain tick
+		for {
+			n, err := r.RelayBatch(ctx)
+			if err != nil {
+				outboxLogger.Error("Erreur de relais", "error", err)
+				break
+			}
+			if n < outboxBatchSize {
+				break
+			}
+		}
+
+		if time.Since(lastPurge) >= outboxPurgeInterval {
+			if purged, err := r.outboxRepo.PurgeSent(ctx, outboxRetention); err != nil {
+				outboxLogger.Error("Erreur de purge", "error", err)
+			} else if purged > 0 {
+				outboxLogger.Info("Messages publiés purgés", "count", purged)
+			}
+			lastPurge = time.Now()
+		}
+
+		select {
+		case <-ctx.Done():
+			outboxLogger.Info("Arrêt du relais")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RelayBatch publie un lot de messages et retourne le nombre de messages réservés
+func (r *OutboxRelay) RelayBatch(ctx context.Context) (int, error) {
+	messages, err := r.outboxRepo.Claim(ctx, outboxBatchSize, outboxLease)
+	if err != nil {
+		return 0, err
+	}
+
+	for _, msg := range messages {
+		if err := r.publish(ctx, msg); err != nil {
+			backoff := outboxBackoff(msg.Attempts)
+			outboxLogger.WarnContext(ctx, "Publication échouée, nouvelle tentative planifiée", "message_id", msg.ID, "attempt", msg.Attempts, "retry_in", backoff.String(), "error", err)
+			if markErr := r.outboxRepo.MarkFailed(ctx, msg.ID, err.Error(), backoff); markErr != nil {
+				outboxLogger.ErrorContext(ctx, "Impossible de consigner l'échec", "message_id", msg.ID, "error", markErr)
+			}
+			continue
+		}
+		if err := r.outboxRepo.MarkSent(ctx, msg.ID); err != nil {
+			// Le bail expirera et le message sera republié : livraison au moins une fois
+			outboxLogger.ErrorContext(ctx, "Impossible de marquer le message comme envoyé", "message_id", msg.ID, "error", err)
+		}
+	}
+	return len(messages), nil
+}
+
+// publish route le message vers sa file, ou vers l'échange des événements s'il porte un sujet.
+// La publication poursuit la trace et la corrélation de la requête qui a écrit le message.
+func (r *OutboxRelay) publish(ctx context.Context, msg entity.OutboxMessage) error {
+	ctx = logging.ExtractRequestID(telemetry.ExtractHeaders(ctx, msg.Headers), msg.Headers)
+	if msg.Topic != "" {
+		return r.publisher.PublishTopic(ctx, msg.Topic, json.RawMessage(msg.Payload))
+	}
+	return r.publisher.Publish(ctx, msg.Queue, json.RawMessage(msg.Payload))
+}
+
+// outboxBackoff calcule un délai exponentiel (2s, 4s, 8s...) plafonné
+func outboxBackoff(attempts int) time.Duration {
+	if attempts < 1 {
+		attempts = 1
+	}
+	if attempts > 16 {
+		return outboxMaxBackoff
+	}
+	delay := time.Duration(1<<attempts) * time.Second
+	if delay > outboxMaxBackoff {
+		return outboxMaxBackoff
+	}
+	return delay
+}
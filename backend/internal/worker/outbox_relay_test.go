@@ -0,0 +1,118 @@
+package worker
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+)
+
+// Mock de OutboxRepository pour les tests
+type mockOutboxRepo struct {
+	pending []entity.OutboxMessage
+	sent    []string
+	failed  map[string]time.Duration
+}
+
+func (m *mockOutboxRepo) Enqueue(ctx context.Context, messages ...*entity.OutboxMessage) error {
+	for _, msg := range messages {
+		m.pending = append(m.pending, *msg)
+	}
+	return nil
+}
+func (m *mockOutboxRepo) Claim(ctx context.Context, limit int, lease time.Duration) ([]entity.OutboxMessage, error) {
+	claimed := m.pending
+	m.pending = nil
+	for i := range claimed {
+		claimed[i].Attempts++
+	}
+	return claimed, nil
+}
+func (m *mockOutboxRepo) MarkSent(ctx context.Context, id string) error {
+	m.sent = append(m.sent, id)
+	return nil
+}
+func (m *mockOutboxRepo) MarkFailed(ctx context.Context, id string, cause string, retryAfter time.Duration) error {
+	m.failed[id] = retryAfter
+	return nil
+}
+func (m *mockOutboxRepo) PurgeSent(ctx context.Context, olderThan time.Duration) (int64, error) {
+	return 0, nil
+}
+
+// Mock de Publisher : échoue pour les files listées dans failQueues
+type mockPublisher struct {
+	published  map[string][]string
+	failQueues map[string]bool
+}
+
+func (p *mockPublisher) Publish(ctx context.Context, queueName string, message interface{}) error {
+	if p.failQueues[queueName] {
+		return errors.New("broker unavailable")
+	}
+	body, _ := json.Marshal(message)
+	p.published[queueName] = append(p.published[queueName], string(body))
+	return nil
+}
+func (p *mockPublisher) PublishTopic(ctx context.Context, routingKey string, message interface{}) error {
+	return p.Publish(ctx, "topic:"+routingKey, message)
+}
+func (p *mockPublisher) Close() {}
+
+func TestRelayBatch_PublishesAndMarksSent(t *testing.T) {
+	repo := &mockOutboxRepo{
+		pending: []entity.OutboxMessage{
+			{ID: "m1", Queue: "new_reports", Payload: json.RawMessage(`{"id":"r1"}`)},
+			{ID: "m2", Queue: "new_reports.urgent", Payload: json.RawMessage(`{"id":"r2"}`)},
+		},
+		failed: map[string]time.Duration{},
+	}
+	pub := &mockPublisher{published: map[string][]string{}, failQueues: map[string]bool{"new_reports.urgent": true}}
+
+	n, err := NewOutboxRelay(repo, pub).RelayBatch(context.Background())
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if n != 2 {
+		t.Errorf("Expected 2 claimed messages, got %d", n)
+	}
+
+	// Le payload est publié tel quel (pas de double encodage JSON)
+	if got := pub.published["new_reports"]; len(got) != 1 || got[0] != `{"id":"r1"}` {
+		t.Errorf("Expected raw payload published, got %v", got)
+	}
+	if len(repo.sent) != 1 || repo.sent[0] != "m1" {
+		t.Errorf("Expected only m1 marked sent, got %v", repo.sent)
+	}
+	if backoff, ok := repo.failed["m2"]; !ok || backoff != 2*time.Second {
+		t.Errorf("Expected m2 rescheduled after 2s, got %v (found=%v)", backoff, ok)
+	}
+}
+
+func TestOutboxBackoff_IsCapped(t *testing.T) {
+	if got := outboxBackoff(3); got != 8*time.Second {
+		t.Errorf("Expected 8s after 3 attempts, got %s", got)
+	}
+	if got := outboxBackoff(40); got != outboxMaxBackoff {
+		t.Errorf("Expected backoff capped at %s, got %s", outboxMaxBackoff, got)
+	}
+}
+
+func TestRelayBatch_RoutesEventsToExchange(t *testing.T) {
+	repo := &mockOutboxRepo{failed: map[string]time.Duration{}}
+	repo.Enqueue(context.Background(), &entity.OutboxMessage{ID: "e1", Topic: "report.status_changed", Payload: json.RawMessage(`{"type":"report.status_changed"}`)})
+	pub := &mockPublisher{published: map[string][]string{}}
+
+	if _, err := NewOutboxRelay(repo, pub).RelayBatch(context.Background()); err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got := pub.published["topic:report.status_changed"]; len(got) != 1 {
+		t.Errorf("Expected event published on exchange, got %v", pub.published)
+	}
+	if len(pub.published[""]) != 0 {
+		t.Error("Event must not be published to the default exchange")
+	}
+}
@@ -4,43 +4,83 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
-	"log"
 
 	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/domain/event"
+	"github.com/openvote/backend/internal/platform/logging"
 	"github.com/openvote/backend/internal/platform/queue"
 	"github.com/openvote/backend/internal/service"
 )
 
+// workerLogger : les entrées de traitement reprennent l'identifiant de la requête qui a
+// créé le signalement (transmis par l'outbox puis la file)
+var workerLogger = logging.For("worker")
+
 type ReportConsumer struct {
 	consumer             queue.Consumer
 	triangulationService service.TriangulationService
+	clusteringService    service.ClusteringService
 }
 
-func NewReportConsumer(consumer queue.Consumer, triangulationService service.TriangulationService) *ReportConsumer {
+func NewReportConsumer(consumer queue.Consumer, triangulationService service.TriangulationService, clusteringService service.ClusteringService) *ReportConsumer {
 	return &ReportConsumer{
 		consumer:             consumer,
 		triangulationService: triangulationService,
+		clusteringService:    clusteringService,
 	}
 }
 
 func (c *ReportConsumer) Start(ctx context.Context) error {
-	log.Printf("[WORKER] Starting ReportConsumer on queue 'new_reports'...")
+	workerLogger.Info("Starting ReportConsumer", "queues", queue.ReportQueues)
 
 	handler := func(ctx context.Context, body []byte) error {
-		var report entity.Report
-		if err := json.Unmarshal(body, &report); err != nil {
-			return fmt.Errorf("failed to unmarshal report: %w", err)
+		reportID, err := reportIDFromMessage(body)
+		if err != nil {
+			return err
 		}
 
-		log.Printf("[WORKER] Processing report: %s", report.ID)
+		workerLogger.InfoContext(ctx, "Processing report", "report_id", reportID)
 
 		// Appel au service de triangulation
-		if err := c.triangulationService.CalculateTrustScore(ctx, report.ID); err != nil {
-			return fmt.Errorf("triangulation failed for report %s: %w", report.ID, err)
+		if err := c.triangulationService.CalculateTrustScore(ctx, reportID); err != nil {
+			return fmt.Errorf("triangulation failed for report %s: %w", reportID, err)
+		}
+
+		// Regroupement en événement consolidé
+		if _, err := c.clusteringService.AssignReport(ctx, reportID); err != nil {
+			return fmt.Errorf("clustering failed for report %s: %w", reportID, err)
 		}
 
 		return nil
 	}
 
-	return c.consumer.Consume(ctx, "new_reports", handler)
+	// Une consommation par file : la file urgente n'attend jamais la file ordinaire
+	for _, name := range queue.ReportQueues {
+		if err := c.consumer.Consume(ctx, name, handler); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reportIDFromMessage lit un événement ReportCreated, ou l'ancien format (entity.Report brut)
+// des messages publiés avant le bus d'événements et encore présents dans les files
+func reportIDFromMessage(body []byte) (string, error) {
+	var env event.Envelope
+	if err := json.Unmarshal(body, &env); err != nil {
+		return "", fmt.Errorf("failed to unmarshal message: %w", err)
+	}
+	if env.Type == "" {
+		var report entity.Report
+		if err := json.Unmarshal(body, &report); err != nil {
+			return "", fmt.Errorf("failed to unmarshal report: %w", err)
+		}
+		return report.ID, nil
+	}
+
+	var created event.ReportCreated
+	if err := env.Decode(&created); err != nil {
+		return "", err
+	}
+	return created.ReportID, nil
 }
@@ -0,0 +1,87 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/entity"
+	"github.com/openvote/backend/internal/platform/queue"
+	"github.com/openvote/backend/internal/service"
+)
+
+// Mock de TriangulationService : bloque jusqu'à release et note si un délai était posé
+type blockingTriangulation struct {
+	mu          sync.Mutex
+	processed   []string
+	hadDeadline bool
+	started     chan struct{}
+	release     chan struct{}
+}
+
+func (m *blockingTriangulation) CalculateTrustScore(ctx context.Context, reportID string) error {
+	m.started <- struct{}{}
+	<-m.release
+	_, hasDeadline := ctx.Deadline()
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.processed = append(m.processed, reportID)
+	m.hadDeadline = hasDeadline
+	return ctx.Err()
+}
+
+func (m *blockingTriangulation) Reconfigure(cfg service.TriangulationConfig) {}
+
+type noopClustering struct{}
+
+func (noopClustering) AssignReport(ctx context.Context, reportID string) (*entity.IncidentEvent, error) {
+	return &entity.IncidentEvent{ID: "evt-" + reportID}, nil
+}
+func (noopClustering) MergeEvents(ctx context.Context, targetID string, sourceIDs []string) (*entity.IncidentEvent, error) {
+	return nil, nil
+}
+func (noopClustering) SplitEvent(ctx context.Context, eventID string, reportIDs []string) (*entity.IncidentEvent, error) {
+	return nil, nil
+}
+
+func TestReportConsumer_DrainsInFlightReportsOnShutdown(t *testing.T) {
+	broker := queue.NewMemoryBroker(queue.ConsumerOptions{Workers: 2, MessageTimeout: 5 * time.Second})
+	defer broker.Close()
+	tri := &blockingTriangulation{started: make(chan struct{}, 2), release: make(chan struct{})}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := NewReportConsumer(broker, tri, noopClustering{}).Start(ctx); err != nil {
+		t.Fatalf("start: %v", err)
+	}
+	for _, id := range []string{"r1", "r2"} {
+		if err := broker.Publish(ctx, queue.QueueNewReports, entity.Report{ID: id}); err != nil {
+			t.Fatalf("publish: %v", err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		select {
+		case <-tri.started:
+		case <-time.After(2 * time.Second):
+			t.Fatalf("only %d/2 reports processed concurrently", i)
+		}
+	}
+
+	// SIGTERM pendant le traitement : les deux signalements doivent aller au bout
+	cancel()
+	close(tri.release)
+	drainCtx, cancelDrain := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancelDrain()
+	if err := broker.Drain(drainCtx); err != nil {
+		t.Fatalf("drain: %v", err)
+	}
+
+	tri.mu.Lock()
+	defer tri.mu.Unlock()
+	if len(tri.processed) != 2 {
+		t.Errorf("expected 2 reports completed, got %v", tri.processed)
+	}
+	if !tri.hadDeadline {
+		t.Error("expected per-message timeout on handler context")
+	}
+}
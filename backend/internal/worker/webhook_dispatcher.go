@@ -0,0 +1,56 @@
+package worker
+
+import (
+	"context"
+	"time"
+
+	"github.com/openvote/backend/internal/domain/event"
+	"github.com/openvote/backend/internal/platform/logging"
+	"github.com/openvote/backend/internal/service"
+)
+
+var webhookLogger = logging.For("webhook")
+
+const webhookPollInterval = 2 * time.Second
+
+// WebhookDispatcher transforme les événements du bus en livraisons webhook
+// puis envoie périodiquement les livraisons dues (premières tentatives et reprises).
+type WebhookDispatcher struct {
+	webhookService service.WebhookService
+}
+
+func NewWebhookDispatcher(webhookService service.WebhookService) *WebhookDispatcher {
+	return &WebhookDispatcher{webhookService: webhookService}
+}
+
+// HandleEvent est l'abonné du registre d'événements : il enregistre une livraison
+// par abonnement correspondant ; l'envoi HTTP est fait par Start.
+func (d *WebhookDispatcher) HandleEvent(ctx context.Context, env event.Envelope) error {
+	n, err := d.webhookService.Dispatch(ctx, env)
+	if err != nil {
+		return err
+	}
+	if n > 0 {
+		webhookLogger.InfoContext(ctx, "Livraisons planifiées", "event_type", env.Type, "event_id", env.ID, "count", n)
+	}
+	return nil
+}
+
+// Start envoie les livraisons dues jusqu'à l'annulation du contexte
+func (d *WebhookDispatcher) Start(ctx context.Context) {
+	webhookLogger.Info("Démarrage de l'expéditeur")
+	ticker := time.NewTicker(webhookPollInterval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.webhookService.DeliverDue(ctx); err != nil && ctx.Err() == nil {
+			webhookLogger.Error("Erreur d'envoi", "error", err)
+		}
+		select {
+		case <-ctx.Done():
+			webhookLogger.Info("Arrêt de l'expéditeur")
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -0,0 +1,6 @@
+-- Annule 010
+DROP TABLE IF EXISTS suspicious_clusters;
+ALTER TABLE reports DROP COLUMN IF EXISTS device_fingerprint;
+ALTER TABLE reports DROP COLUMN IF EXISTS source_fingerprint;
+DROP INDEX IF EXISTS idx_users_activation_token_hash;
+ALTER TABLE users DROP COLUMN IF EXISTS activation_token_hash;
@@ -0,0 +1,26 @@
+-- Migration 010: Détection Sybil / collusion des signalements citoyens
+-- Empreintes d'enrôlement et de source, clusters suspects soumis à revue humaine
+
+-- 1. Empreinte du token d'activation utilisé lors de l'enrôlement
+ALTER TABLE users ADD COLUMN IF NOT EXISTS activation_token_hash VARCHAR(64);
+CREATE INDEX IF NOT EXISTS idx_users_activation_token_hash ON users (activation_token_hash);
+
+-- 2. Empreintes (HMAC) de l'IP source et de l'appareil — jamais en clair
+ALTER TABLE reports ADD COLUMN IF NOT EXISTS source_fingerprint VARCHAR(64);
+ALTER TABLE reports ADD COLUMN IF NOT EXISTS device_fingerprint VARCHAR(64);
+
+-- 3. Clusters suspects (un par signalement cible)
+CREATE TABLE IF NOT EXISTS suspicious_clusters (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    report_id UUID NOT NULL UNIQUE REFERENCES reports(id) ON DELETE CASCADE,
+    related_report_ids UUID[] NOT NULL DEFAULT '{}',
+    signals TEXT[] NOT NULL DEFAULT '{}',
+    score FLOAT NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'confirmed', 'dismissed')),
+    reviewed_by VARCHAR(100) DEFAULT '',
+    review_note TEXT DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    reviewed_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_suspicious_clusters_status ON suspicious_clusters (status, created_at DESC);
@@ -0,0 +1,3 @@
+-- Annule 011
+DROP TABLE IF EXISTS conflict_reports;
+DROP TABLE IF EXISTS conflicts;
@@ -0,0 +1,24 @@
+-- Migration 011: Conflits entre signalements
+-- Remplace la simple ligne de log de la triangulation par un objet de revue persistant
+
+CREATE TABLE IF NOT EXISTS conflicts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    h3_index VARCHAR(15),
+    incident_types TEXT[] NOT NULL DEFAULT '{}',
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'resolved')),
+    assignee_id UUID REFERENCES users(id) ON DELETE SET NULL,
+    resolution_note TEXT DEFAULT '',
+    resolved_by VARCHAR(100) DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    resolved_at TIMESTAMP WITH TIME ZONE
+);
+
+-- Liaison conflit <-> signalements impliqués
+CREATE TABLE IF NOT EXISTS conflict_reports (
+    conflict_id UUID NOT NULL REFERENCES conflicts(id) ON DELETE CASCADE,
+    report_id UUID NOT NULL REFERENCES reports(id) ON DELETE CASCADE,
+    PRIMARY KEY (conflict_id, report_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_conflicts_status ON conflicts (status, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_conflict_reports_report ON conflict_reports (report_id);
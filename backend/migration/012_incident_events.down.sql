@@ -0,0 +1,10 @@
+-- Annule 012
+-- Les analyses au niveau de l'événement n'ont pas de signalement : supprimées avec la colonne
+DROP INDEX IF EXISTS idx_legal_analyses_event;
+DELETE FROM legal_analyses WHERE report_id IS NULL;
+ALTER TABLE legal_analyses DROP COLUMN IF EXISTS event_id;
+ALTER TABLE legal_analyses ALTER COLUMN report_id SET NOT NULL;
+
+DROP INDEX IF EXISTS idx_reports_event;
+ALTER TABLE reports DROP COLUMN IF EXISTS event_id;
+DROP TABLE IF EXISTS incident_events;
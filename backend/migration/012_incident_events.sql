@@ -0,0 +1,29 @@
+-- Migration 012: Regroupement des signalements en événements consolidés
+-- Plusieurs observateurs signalant le même incident au même endroit produisent un seul événement
+
+CREATE TABLE IF NOT EXISTS incident_events (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    incident_type VARCHAR(100) NOT NULL,
+    h3_index VARCHAR(15),
+    centroid GEOMETRY(Point, 4326),
+    first_reported_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    last_reported_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    report_count INT NOT NULL DEFAULT 0,
+    max_severity INT NOT NULL DEFAULT 1 CHECK (max_severity BETWEEN 1 AND 5),
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'merged')),
+    merged_into UUID REFERENCES incident_events(id) ON DELETE SET NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_incident_events_centroid ON incident_events USING GIST (centroid);
+CREATE INDEX IF NOT EXISTS idx_incident_events_lookup ON incident_events (incident_type, status, last_reported_at DESC);
+
+-- Un signalement appartient à au plus un événement
+ALTER TABLE reports ADD COLUMN IF NOT EXISTS event_id UUID REFERENCES incident_events(id) ON DELETE SET NULL;
+CREATE INDEX IF NOT EXISTS idx_reports_event ON reports (event_id);
+
+-- Analyses juridiques au niveau de l'événement
+ALTER TABLE legal_analyses ALTER COLUMN report_id DROP NOT NULL;
+ALTER TABLE legal_analyses ADD COLUMN IF NOT EXISTS event_id UUID REFERENCES incident_events(id) ON DELETE CASCADE;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_legal_analyses_event ON legal_analyses (event_id);
@@ -0,0 +1,3 @@
+-- Annule 013
+DROP INDEX IF EXISTS idx_reports_review_queue;
+ALTER TABLE reports DROP COLUMN IF EXISTS severity;
@@ -0,0 +1,12 @@
+-- Migration 013: Sévérité portée par le signalement
+-- Copie de incident_types.severity au moment de la soumission, pour prioriser le traitement et la revue
+
+ALTER TABLE reports ADD COLUMN IF NOT EXISTS severity INT NOT NULL DEFAULT 3 CHECK (severity BETWEEN 1 AND 5);
+
+-- Rattrapage des signalements existants (type saisi par code ou par libellé)
+UPDATE reports r SET severity = it.severity
+FROM incident_types it
+WHERE (it.code = r.incident_type OR it.name = r.incident_type) AND r.severity <> it.severity;
+
+-- File de revue : sévérité décroissante puis ancienneté
+CREATE INDEX IF NOT EXISTS idx_reports_review_queue ON reports (status, severity DESC, created_at);
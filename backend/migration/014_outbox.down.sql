@@ -0,0 +1,2 @@
+-- Annule 014 : les messages non relayés sont perdus
+DROP TABLE IF EXISTS outbox;
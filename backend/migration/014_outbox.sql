@@ -0,0 +1,18 @@
+-- Migration 014: Outbox transactionnelle
+-- Les messages à publier sont écrits dans la même transaction que la donnée métier,
+-- puis relayés vers le broker par un worker : livraison au moins une fois.
+
+CREATE TABLE IF NOT EXISTS outbox (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    queue VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent')),
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT DEFAULT '',
+    available_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(), -- Bail du relais ou prochaine tentative
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    sent_at TIMESTAMP WITH TIME ZONE
+);
+
+CREATE INDEX IF NOT EXISTS idx_outbox_pending ON outbox (available_at, created_at) WHERE status = 'pending';
+CREATE INDEX IF NOT EXISTS idx_outbox_sent ON outbox (sent_at) WHERE status = 'sent';
@@ -0,0 +1,2 @@
+-- Annule 015
+DROP TABLE IF EXISTS queue_jobs;
@@ -0,0 +1,17 @@
+-- Migration 015: File de messages adossée à PostgreSQL
+-- Alternative à RabbitMQ pour les petits déploiements (QUEUE_BACKEND=postgres)
+
+CREATE TABLE IF NOT EXISTS queue_jobs (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    queue VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'ready' CHECK (status IN ('ready', 'dead')),
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT DEFAULT '',
+    available_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    failed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_queue_jobs_ready ON queue_jobs (queue, available_at, created_at) WHERE status = 'ready';
+CREATE INDEX IF NOT EXISTS idx_queue_jobs_dead ON queue_jobs (queue, failed_at DESC) WHERE status = 'dead';
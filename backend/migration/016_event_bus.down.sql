@@ -0,0 +1,4 @@
+-- Annule 016
+DROP TABLE IF EXISTS queue_bindings;
+ALTER TABLE outbox ALTER COLUMN queue DROP DEFAULT;
+ALTER TABLE outbox DROP COLUMN IF EXISTS topic;
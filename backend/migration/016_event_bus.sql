@@ -0,0 +1,14 @@
+-- Migration 016: Bus d'événements métier
+-- Les événements passent par l'outbox avec un sujet (clé de routage de l'échange topic)
+-- au lieu d'une file nommée.
+
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS topic VARCHAR(100) NOT NULL DEFAULT '';
+ALTER TABLE outbox ALTER COLUMN queue SET DEFAULT '';
+
+-- Abonnements des files aux clés de routage pour le backend PostgreSQL (QUEUE_BACKEND=postgres)
+CREATE TABLE IF NOT EXISTS queue_bindings (
+    queue VARCHAR(100) NOT NULL,
+    pattern VARCHAR(100) NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    PRIMARY KEY (queue, pattern)
+);
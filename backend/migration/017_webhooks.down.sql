@@ -0,0 +1,3 @@
+-- Annule 017
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_subscriptions;
@@ -0,0 +1,3 @@
+-- Annule 018
+DROP TABLE IF EXISTS alerts;
+DROP TABLE IF EXISTS alert_rules;
@@ -0,0 +1,55 @@
+-- Migration 018: Règles d'alerte et alertes
+-- Une règle se déclenche quand au moins `threshold` signalements correspondant à ses conditions
+-- (type, sévérité, région, plage horaire) surviennent dans une même zone pendant `window_minutes`.
+
+CREATE TABLE IF NOT EXISTS alert_rules (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    name VARCHAR(150) NOT NULL,
+    description TEXT DEFAULT '',
+    incident_types TEXT[] NOT NULL DEFAULT '{}', -- Codes ou noms ; vide : tous les types
+    min_severity INT NOT NULL DEFAULT 1 CHECK (min_severity BETWEEN 1 AND 5),
+    region_ids TEXT[] NOT NULL DEFAULT '{}',     -- Vide : toutes les régions
+    group_by VARCHAR(20) NOT NULL DEFAULT 'region' CHECK (group_by IN ('none', 'region', 'h3')),
+    h3_resolution INT NOT NULL DEFAULT 6 CHECK (h3_resolution BETWEEN 0 AND 10),
+    threshold INT NOT NULL DEFAULT 1 CHECK (threshold >= 1),
+    window_minutes INT NOT NULL DEFAULT 15 CHECK (window_minutes >= 1),
+    time_of_day_start VARCHAR(5) DEFAULT '', -- "HH:MM" ; vide : pas de borne
+    time_of_day_end VARCHAR(5) DEFAULT '',
+    timezone VARCHAR(64) NOT NULL DEFAULT 'Africa/Douala',
+    channels TEXT[] NOT NULL DEFAULT '{dashboard}',
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_by VARCHAR(100) DEFAULT '',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS alerts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    rule_id UUID NOT NULL REFERENCES alert_rules(id) ON DELETE CASCADE,
+    group_key VARCHAR(100) NOT NULL DEFAULT '', -- Zone concernée (région, cellule H3)
+    region_id VARCHAR(100) DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'acknowledged', 'resolved')),
+    severity INT NOT NULL DEFAULT 1,
+    report_ids TEXT[] NOT NULL DEFAULT '{}',
+    report_count INT NOT NULL DEFAULT 0,
+    first_triggered_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    last_triggered_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    acknowledged_by VARCHAR(100) DEFAULT '',
+    acknowledged_at TIMESTAMP WITH TIME ZONE,
+    resolved_by VARCHAR(100) DEFAULT '',
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    resolution_note TEXT DEFAULT ''
+);
+
+-- Une seule alerte non résolue par règle et par zone : les nouveaux signalements l'alimentent
+CREATE UNIQUE INDEX IF NOT EXISTS idx_alerts_open ON alerts (rule_id, group_key) WHERE status <> 'resolved';
+CREATE INDEX IF NOT EXISTS idx_alerts_status ON alerts (status, last_triggered_at DESC);
+
+-- Règles d'exemple (inactives) : à ajuster par les coordinateurs
+INSERT INTO alert_rules (name, description, min_severity, group_by, threshold, window_minutes, active)
+SELECT 'Foyer critique', 'Au moins 3 incidents de sévérité 5 dans la même zone en 15 minutes', 5, 'h3', 3, 15, FALSE
+WHERE NOT EXISTS (SELECT 1 FROM alert_rules WHERE name = 'Foyer critique');
+
+INSERT INTO alert_rules (name, description, incident_types, group_by, threshold, window_minutes, time_of_day_end, active)
+SELECT 'Fermeture anticipée', 'Bureau de vote fermé avant 18:00', '{EARLY}', 'none', 1, 60, '18:00', FALSE
+WHERE NOT EXISTS (SELECT 1 FROM alert_rules WHERE name = 'Fermeture anticipée');
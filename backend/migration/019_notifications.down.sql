@@ -0,0 +1,4 @@
+-- Annule 019
+DROP TABLE IF EXISTS notification_deliveries;
+DROP TABLE IF EXISTS notifications;
+DROP TABLE IF EXISTS notification_preferences;
@@ -0,0 +1,48 @@
+-- Migration 019: Notifications (e-mail, SMS, boîte de réception in-app)
+-- Préférences par utilisateur, boîte de réception, journal des envois avec nouvelles tentatives.
+
+CREATE TABLE IF NOT EXISTS notification_preferences (
+    user_id UUID PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    locale VARCHAR(5) NOT NULL DEFAULT 'fr' CHECK (locale IN ('fr', 'en')),
+    email VARCHAR(255) DEFAULT '',
+    phone VARCHAR(32) DEFAULT '',
+    channels TEXT[] NOT NULL DEFAULT '{inbox}',
+    muted_kinds TEXT[] NOT NULL DEFAULT '{}',
+    quiet_hours_start VARCHAR(5) DEFAULT '', -- "HH:MM" ; vide : pas d'heures calmes
+    quiet_hours_end VARCHAR(5) DEFAULT '',
+    timezone VARCHAR(64) NOT NULL DEFAULT 'Africa/Douala',
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE TABLE IF NOT EXISTS notifications (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    kind VARCHAR(100) NOT NULL,
+    title TEXT NOT NULL,
+    body TEXT NOT NULL,
+    read_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_notifications_user ON notifications (user_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_notifications_unread ON notifications (user_id) WHERE read_at IS NULL;
+
+CREATE TABLE IF NOT EXISTS notification_deliveries (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    channel VARCHAR(20) NOT NULL,
+    kind VARCHAR(100) NOT NULL,
+    dedup_key VARCHAR(200) NOT NULL,
+    recipient VARCHAR(255) NOT NULL DEFAULT '',
+    subject TEXT NOT NULL DEFAULT '',
+    body TEXT NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'sent', 'failed')),
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT DEFAULT '',
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(), -- Reportée à la fin des heures calmes
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    sent_at TIMESTAMP WITH TIME ZONE,
+    UNIQUE (user_id, channel, dedup_key) -- Un événement relivré n'est notifié qu'une fois
+);
+
+CREATE INDEX IF NOT EXISTS idx_notification_deliveries_due ON notification_deliveries (next_attempt_at) WHERE status = 'pending';
@@ -0,0 +1,2 @@
+-- Annule 020 : la configuration revient aux valeurs par défaut
+DROP TABLE IF EXISTS system_config_versions;
@@ -0,0 +1,16 @@
+-- Migration 020: Configuration système versionnée
+-- Chaque modification d'une section (triangulation, rate_limiting, storage) ajoute une version :
+-- la valeur courante d'une section est sa dernière version, les précédentes servent d'historique
+-- et de point de retour (rollback). Sans version enregistrée, les valeurs par défaut du code s'appliquent.
+
+CREATE TABLE IF NOT EXISTS system_config_versions (
+    version BIGSERIAL PRIMARY KEY,
+    section VARCHAR(50) NOT NULL,
+    data JSONB NOT NULL,
+    changed_by VARCHAR(100) DEFAULT '',
+    comment TEXT DEFAULT '',
+    rolled_back_from BIGINT REFERENCES system_config_versions(version),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_system_config_section ON system_config_versions(section, version DESC);
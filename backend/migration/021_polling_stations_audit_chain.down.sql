@@ -0,0 +1,6 @@
+-- Annule 021 : les empreintes du journal d'audit sont perdues
+DROP INDEX IF EXISTS idx_audit_logs_seq;
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS hash;
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS prev_hash;
+ALTER TABLE audit_logs DROP COLUMN IF EXISTS seq;
+DROP TABLE IF EXISTS polling_stations;
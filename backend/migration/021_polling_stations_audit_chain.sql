@@ -0,0 +1,26 @@
+-- Migration 021: Bureaux de vote et chaînage du journal d'audit
+-- Les bureaux de vote sont importés depuis un CSV (openvote-admin import polling-stations).
+-- Chaque entrée d'audit porte l'empreinte de la précédente : une suppression ou une modification
+-- a posteriori rompt la chaîne (openvote-admin verify-audit).
+
+CREATE TABLE IF NOT EXISTS polling_stations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    code VARCHAR(50) NOT NULL UNIQUE,
+    name VARCHAR(255) NOT NULL,
+    department_id UUID NOT NULL REFERENCES departments(id) ON DELETE CASCADE,
+    address TEXT DEFAULT '',
+    latitude DOUBLE PRECISION,
+    longitude DOUBLE PRECISION,
+    registered_voters INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_polling_stations_department ON polling_stations (department_id);
+
+-- Ordre d'insertion explicite (created_at n'est pas unique) ; les entrées antérieures restent hors chaîne
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS seq BIGSERIAL;
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS prev_hash VARCHAR(64);
+ALTER TABLE audit_logs ADD COLUMN IF NOT EXISTS hash VARCHAR(64);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_audit_logs_seq ON audit_logs (seq);
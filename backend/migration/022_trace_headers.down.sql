@@ -0,0 +1,3 @@
+-- Annule 022
+ALTER TABLE queue_jobs DROP COLUMN IF EXISTS headers;
+ALTER TABLE outbox DROP COLUMN IF EXISTS headers;
@@ -0,0 +1,6 @@
+-- Migration 022: Propagation du contexte de trace
+-- Les messages de l'outbox et de queue_jobs transportent les en-têtes W3C (traceparent,
+-- tracestate) de la requête d'origine : la trace se poursuit jusque dans le worker.
+
+ALTER TABLE outbox ADD COLUMN IF NOT EXISTS headers JSONB NOT NULL DEFAULT '{}';
+ALTER TABLE queue_jobs ADD COLUMN IF NOT EXISTS headers JSONB NOT NULL DEFAULT '{}';
@@ -0,0 +1,48 @@
+-- migrate:replaces 004
+-- Migration 023: Version rejouable de 004_veille_electorale
+-- 004 insère ses articles avec ON CONFLICT (article_number), contrainte qui n'existe pas sur une
+-- base neuve (et que 005 supprime) : le script échoue. Ce correctif est joué à la place de 004
+-- sur les bases où elle est en attente ; ailleurs, il n'a rien à faire.
+
+-- Contenu de 004 : données démographiques et cadre réglementaire
+
+-- 1. Ajout des colonnes démographiques aux départements
+ALTER TABLE departments ADD COLUMN IF NOT EXISTS population INTEGER DEFAULT 0;
+ALTER TABLE departments ADD COLUMN IF NOT EXISTS registered_voters INTEGER DEFAULT 0;
+
+-- 2. Table pour le Cadre Réglementaire (Code Électoral)
+CREATE TABLE IF NOT EXISTS legal_framework (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    article_number VARCHAR(100) NOT NULL,
+    title VARCHAR(1000) NOT NULL,
+    content TEXT NOT NULL,
+    category VARCHAR(100), -- ex: 'Inscription', 'Scrutin', 'Contentieux'
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- 3. Insertion de quelques données de référence réglementaires (Cameroun)
+-- article_number n'est pas unique (voir 005) : on insère seulement les articles absents
+INSERT INTO legal_framework (article_number, title, content, category)
+SELECT v.article_number, v.title, v.content, v.category FROM (VALUES
+('Art. 2', 'Suffrage Universel', 'Le suffrage est universel, égal et secret. Il peut être direct ou indirect...', 'Principes Généraux'),
+('Art. 45', 'Commission Mixte de Révision', 'Une commission mixte de révision des listes électorales est créée dans chaque commune...', 'Inscription'),
+('Art. 74', 'Carte Électorale', 'La carte électorale est permanente. Elle est établie par ELECAM...', 'Inscription'),
+('Art. 102', 'Campagne Électorale', 'La campagne électorale est ouverte à partir du quinzième jour précédant le scrutin...', 'Campagne'),
+('Art. 115', 'Opérations de Vote', 'Le scrutin ne dure qu''un seul jour. Il est ouvert à 8 heures et clos à 18 heures...', 'Scrutin'),
+('Art. 120', 'Dépouillement', 'Le dépouillement suit immédiatement la clôture du scrutin. Il est public...', 'Scrutin')
+) AS v(article_number, title, content, category)
+WHERE NOT EXISTS (SELECT 1 FROM legal_framework lf WHERE lf.article_number = v.article_number);
+
+-- 4. Mise à jour de quelques données démographiques (estimations) pour l'analyse
+-- Mfoundi (Yaoundé)
+UPDATE departments SET population = 4200000, registered_voters = 1200000 WHERE code = 'CE-MF';
+-- Wouri (Douala)
+UPDATE departments SET population = 3900000, registered_voters = 1100000 WHERE code = 'LT-WO';
+-- Bénoué (Garoua)
+UPDATE departments SET population = 1500000, registered_voters = 450000 WHERE code = 'NO-BE';
+-- Mifi (Bafoussam)
+UPDATE departments SET population = 800000, registered_voters = 250000 WHERE code = 'OU-MI';
+-- Fako (Limbe/Buea)
+UPDATE departments SET population = 900000, registered_voters = 280000 WHERE code = 'SW-FA';
+-- Mezam (Bamenda)
+UPDATE departments SET population = 1100000, registered_voters = 320000 WHERE code = 'NW-MZ';
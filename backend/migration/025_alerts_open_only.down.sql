@@ -0,0 +1,4 @@
+-- Annule 025
+-- Échoue si une zone a à la fois une alerte acquittée et une alerte ouverte : en résoudre une avant.
+DROP INDEX IF EXISTS idx_alerts_open;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_alerts_open ON alerts (rule_id, group_key) WHERE status <> 'resolved';
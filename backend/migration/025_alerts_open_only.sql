@@ -0,0 +1,6 @@
+-- Migration 025: Seules les alertes ouvertes absorbent les nouveaux signalements
+-- Une alerte acquittée n'est plus alimentée en silence : la reprise de l'activité dans la zone
+-- ouvre une nouvelle alerte (et notifie à nouveau les canaux de la règle).
+
+DROP INDEX IF EXISTS idx_alerts_open;
+CREATE UNIQUE INDEX IF NOT EXISTS idx_alerts_open ON alerts (rule_id, group_key) WHERE status = 'open';
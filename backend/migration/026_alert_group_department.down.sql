@@ -0,0 +1,4 @@
+-- Annule 026
+UPDATE alert_rules SET group_by = 'region' WHERE group_by = 'department';
+ALTER TABLE alert_rules DROP CONSTRAINT IF EXISTS alert_rules_group_by_check;
+ALTER TABLE alert_rules ADD CONSTRAINT alert_rules_group_by_check CHECK (group_by IN ('none', 'region', 'h3'));
@@ -0,0 +1,5 @@
+-- Migration 026: Regroupement des alertes par département
+-- Le département d'un signalement est celui du bureau de vote géolocalisé le plus proche.
+
+ALTER TABLE alert_rules DROP CONSTRAINT IF EXISTS alert_rules_group_by_check;
+ALTER TABLE alert_rules ADD CONSTRAINT alert_rules_group_by_check CHECK (group_by IN ('none', 'region', 'department', 'h3'));